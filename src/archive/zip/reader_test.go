@@ -289,6 +289,25 @@ var tests = []ZipTest{
 			},
 		},
 	},
+	{
+		// Zstandard compressed (method 93), created by a program
+		// using the zstd command.
+		Name: "zstd.zip",
+		File: []ZipTestFile{
+			{
+				Name:     "test.txt",
+				Content:  []byte("This is a test text file.\n"),
+				Modified: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+				Mode:     0644,
+			},
+			{
+				Name:     "gophers.txt",
+				Content:  bytes.Repeat([]byte("Gophers are small burrowing rodents. "), 50),
+				Modified: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+				Mode:     0644,
+			},
+		},
+	},
 	{
 		Name:   "Bad-CRC32-in-data-descriptor",
 		Source: returnCorruptCRC32Zip,
@@ -6,6 +6,7 @@ package zip
 
 import (
 	"compress/flate"
+	"compress/zstd"
 	"errors"
 	"io"
 	"sync"
@@ -101,6 +102,84 @@ func (r *pooledFlateReader) Close() error {
 	return err
 }
 
+var zstdWriterPool sync.Pool
+
+func newZstdWriter(w io.Writer) io.WriteCloser {
+	zw, ok := zstdWriterPool.Get().(*zstd.Writer)
+	if ok {
+		zw.Reset(w)
+	} else {
+		zw = zstd.NewWriter(w)
+	}
+	return &pooledZstdWriter{zw: zw}
+}
+
+type pooledZstdWriter struct {
+	mu sync.Mutex // guards Close and Write
+	zw *zstd.Writer
+}
+
+func (w *pooledZstdWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.zw == nil {
+		return 0, errors.New("Write after Close")
+	}
+	return w.zw.Write(p)
+}
+
+func (w *pooledZstdWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var err error
+	if w.zw != nil {
+		err = w.zw.Close()
+		w.zw.Reset(nil)
+		zstdWriterPool.Put(w.zw)
+		w.zw = nil
+	}
+	return err
+}
+
+var zstdReaderPool sync.Pool
+
+func newZstdReader(r io.Reader) io.ReadCloser {
+	zr, ok := zstdReaderPool.Get().(*zstd.Reader)
+	if ok {
+		zr.Reset(r)
+	} else {
+		zr = zstd.NewReader(r)
+	}
+	return &pooledZstdReader{zr: zr}
+}
+
+type pooledZstdReader struct {
+	mu sync.Mutex // guards Close and Read
+	zr *zstd.Reader
+}
+
+func (r *pooledZstdReader) Read(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.zr == nil {
+		return 0, errors.New("Read after Close")
+	}
+	return r.zr.Read(p)
+}
+
+func (r *pooledZstdReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	if r.zr != nil {
+		err = r.zr.Close()
+		r.zr.Reset(nil)
+		zstdReaderPool.Put(r.zr)
+		r.zr = nil
+	}
+	return err
+}
+
 var (
 	compressors   sync.Map // map[uint16]Compressor
 	decompressors sync.Map // map[uint16]Decompressor
@@ -115,7 +194,8 @@ func init() {
 }
 
 // RegisterDecompressor allows custom decompressors for a specified method ID.
-// The common methods [Store] and [Deflate] are built in.
+// The common methods [Store], [Deflate] and [Zstd] are built in.
+// Registering a decompressor for [Zstd] replaces the built-in one.
 func RegisterDecompressor(method uint16, dcomp Decompressor) {
 	if _, dup := decompressors.LoadOrStore(method, dcomp); dup {
 		panic("decompressor already registered")
@@ -123,7 +203,8 @@ func RegisterDecompressor(method uint16, dcomp Decompressor) {
 }
 
 // RegisterCompressor registers custom compressors for a specified method ID.
-// The common methods [Store] and [Deflate] are built in.
+// The common methods [Store], [Deflate] and [Zstd] are built in.
+// Registering a compressor for [Zstd] replaces the built-in one.
 func RegisterCompressor(method uint16, comp Compressor) {
 	if _, dup := compressors.LoadOrStore(method, comp); dup {
 		panic("compressor already registered")
@@ -133,6 +214,12 @@ func RegisterCompressor(method uint16, comp Compressor) {
 func compressor(method uint16) Compressor {
 	ci, ok := compressors.Load(method)
 	if !ok {
+		if method == Zstd {
+			// The built-in zstd support is not in the registry, so that
+			// programs that registered their own zstd implementation
+			// before it was built in continue to work.
+			return func(w io.Writer) (io.WriteCloser, error) { return newZstdWriter(w), nil }
+		}
 		return nil
 	}
 	return ci.(Compressor)
@@ -141,6 +228,10 @@ func compressor(method uint16) Compressor {
 func decompressor(method uint16) Decompressor {
 	di, ok := decompressors.Load(method)
 	if !ok {
+		if method == Zstd {
+			// Not in the registry, like the zstd compressor.
+			return newZstdReader
+		}
 		return nil
 	}
 	return di.(Decompressor)
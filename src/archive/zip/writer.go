@@ -321,6 +321,9 @@ func (w *Writer) CreateHeader(fh *FileHeader) (io.Writer, error) {
 
 	fh.CreatorVersion = fh.CreatorVersion&0xff00 | zipVersion20 // preserve compatibility byte
 	fh.ReaderVersion = zipVersion20
+	if fh.Method == Zstd {
+		fh.ReaderVersion = zipVersion63
+	}
 
 	// If Modified is set, this takes precedence over MS-DOS timestamp fields.
 	if !fh.Modified.IsZero() {
@@ -660,7 +663,7 @@ func (w *fileWriter) close() error {
 	if w.CompressedSize64 > uint32max || w.UncompressedSize64 > uint32max {
 		fh.CompressedSize = uint32max
 		fh.UncompressedSize = uint32max
-		fh.ReaderVersion = zipVersion45 // requires 4.5 - File uses ZIP64 format extensions
+		fh.ReaderVersion = max(fh.ReaderVersion, zipVersion45) // requires 4.5 - File uses ZIP64 format extensions
 	} else {
 		fh.CompressedSize = uint32(fh.CompressedSize64)
 		fh.UncompressedSize = uint32(fh.UncompressedSize64)
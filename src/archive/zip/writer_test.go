@@ -72,6 +72,12 @@ var writeTests = []WriteTest{
 		Method: Deflate,
 		Mode:   0755 | fs.ModeDevice | fs.ModeCharDevice,
 	},
+	{
+		Name:   "zstd",
+		Data:   bytes.Repeat([]byte("Rabbits, guinea pigs, gophers, marsupial rats, and quolls. "), 100),
+		Method: Zstd,
+		Mode:   0644,
+	},
 }
 
 func TestWriter(t *testing.T) {
@@ -106,6 +112,73 @@ func TestWriter(t *testing.T) {
 	}
 }
 
+func TestWriterZstd(t *testing.T) {
+	data := bytes.Repeat([]byte("Rabbits, guinea pigs, gophers, marsupial rats, and quolls. "), 1000)
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	fw, err := w.CreateHeader(&FileHeader{Name: "zstd", Method: Zstd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := r.File[0]
+	if f.Method != Zstd {
+		t.Errorf("Method = %d, want %d", f.Method, Zstd)
+	}
+	if f.ReaderVersion != zipVersion63 {
+		t.Errorf("ReaderVersion = %d, want %d", f.ReaderVersion, zipVersion63)
+	}
+	if f.CompressedSize64 >= f.UncompressedSize64/10 {
+		t.Errorf("compressed %d bytes to %d", f.UncompressedSize64, f.CompressedSize64)
+	}
+	testReadFile(t, f, &WriteTest{Name: "zstd", Data: data, Mode: 0666})
+}
+
+// Programs that registered their own zstd implementation,
+// before it was built in, must continue to work.
+func TestRegisterZstd(t *testing.T) {
+	var calls int
+	RegisterCompressor(Zstd, func(w io.Writer) (io.WriteCloser, error) {
+		calls++
+		return newZstdWriter(w), nil
+	})
+	RegisterDecompressor(Zstd, func(r io.Reader) io.ReadCloser {
+		calls++
+		return newZstdReader(r)
+	})
+	// Restore the built-in zstd support for other tests.
+	t.Cleanup(func() {
+		compressors.Delete(Zstd)
+		decompressors.Delete(Zstd)
+	})
+
+	data := []byte("registered zstd")
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	testCreate(t, w, &WriteTest{Name: "zstd", Data: data, Method: Zstd, Mode: 0644})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testReadFile(t, r.File[0], &WriteTest{Name: "zstd", Data: data, Mode: 0644})
+	if calls != 2 {
+		t.Errorf("registered zstd compressor and decompressor called %d times, want 2", calls)
+	}
+}
+
 // TestWriterComment is test for EOCD comment read/write.
 func TestWriterComment(t *testing.T) {
 	tests := []struct {
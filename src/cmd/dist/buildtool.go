@@ -62,6 +62,7 @@ var bootstrapDirs = []string{
 	"cmd/preprofile",
 	"compress/flate",
 	"compress/zlib",
+	"compress/zstd",
 	"container/heap",
 	"debug/dwarf",
 	"debug/elf",
@@ -98,7 +99,6 @@ var bootstrapDirs = []string{
 	"internal/types/errors",
 	"internal/unsafeheader",
 	"internal/xcoff",
-	"internal/zstd",
 	"math/bits",
 	"sort",
 }
@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// dictMagic is the magic number at the start of a zstd dictionary.
+const dictMagic = 0xec30a437
+
+// dictionary is a parsed dictionary. RFC 5.
+type dictionary struct {
+	// id is the Dictionary_ID, or 0 for a raw content dictionary.
+	id uint32
+
+	// content is the data that precedes each frame.
+	content []byte
+
+	// Initial repeated offsets.
+	repeats [3]uint32
+
+	// Initial Huffman table, if huffmanTableBits is not 0.
+	huffmanTable     []uint16
+	huffmanTableBits int
+
+	// Initial sequence FSE tables; nil for a raw content dictionary.
+	seqTables    [3][]fseBaselineEntry
+	seqTableBits [3]uint8
+}
+
+// parseDictionary parses a dictionary.
+// Data that does not start with the dictionary magic number
+// is treated as raw content.
+func parseDictionary(data []byte) (*dictionary, error) {
+	d := &dictionary{
+		repeats: [3]uint32{1, 4, 8},
+	}
+	if len(data) < 8 || binary.LittleEndian.Uint32(data) != dictMagic {
+		d.content = bytes.Clone(data)
+		return d, nil
+	}
+
+	d.id = binary.LittleEndian.Uint32(data[4:])
+	if d.id == 0 {
+		return nil, errors.New("zstd: invalid dictionary: zero Dictionary_ID")
+	}
+
+	// Use a Reader to parse the entropy tables,
+	// which are in the same format as in a compressed block.
+	var r Reader
+	off := 8
+	d.huffmanTable = make([]uint16, 1<<maxHuffmanBits)
+	bits, off, err := r.readHuff(data, off, d.huffmanTable)
+	if err != nil {
+		return nil, fmt.Errorf("zstd: invalid dictionary: %v", err)
+	}
+	d.huffmanTableBits = bits
+
+	for _, kind := range [...]seqCode{seqOffset, seqMatch, seqLiteral} {
+		off, err = r.setSeqTable(data, off, kind, 2)
+		if err != nil {
+			return nil, fmt.Errorf("zstd: invalid dictionary: %v", err)
+		}
+		d.seqTables[kind] = r.seqTables[kind]
+		d.seqTableBits[kind] = r.seqTableBits[kind]
+	}
+
+	if len(data)-off < 12 {
+		return nil, errors.New("zstd: invalid dictionary: missing repeat offsets")
+	}
+	d.content = bytes.Clone(data[off+12:])
+	for i := range d.repeats {
+		rep := binary.LittleEndian.Uint32(data[off+4*i:])
+		if rep == 0 || rep > uint32(len(d.content)) {
+			return nil, errors.New("zstd: invalid dictionary: bad repeat offset")
+		}
+		d.repeats[i] = rep
+	}
+	return d, nil
+}
@@ -0,0 +1,374 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// Literals_Block_Type values. RFC 3.1.1.3.1.1.
+const (
+	literalsRaw        = 0
+	literalsRLE        = 1
+	literalsCompressed = 2
+)
+
+// minHuffLiterals is the smallest number of literals that we try
+// to compress with a Huffman code.
+const minHuffLiterals = 64
+
+// appendLiterals appends a literals section holding lits to out.
+// RFC 3.1.1.3.1.
+func (e *encoder) appendLiterals(out, lits []byte) []byte {
+	if isRLE(lits) {
+		return append(appendRawRLEHeader(out, literalsRLE, len(lits)), lits[0])
+	}
+	if len(lits) >= minHuffLiterals {
+		start := len(out)
+		var ok bool
+		out, ok = e.huff.appendLiterals(out, lits)
+		if ok && len(out)-start < len(lits) {
+			return out
+		}
+		out = out[:start]
+	}
+	return append(appendRawRLEHeader(out, literalsRaw, len(lits)), lits...)
+}
+
+// appendRawRLEHeader appends the literals section header for a
+// Raw_Literals_Block or RLE_Literals_Block of size bytes.
+// RFC 3.1.1.3.1.1.
+func appendRawRLEHeader(out []byte, typ byte, size int) []byte {
+	switch {
+	case size < 1<<5:
+		return append(out, typ|byte(size)<<3)
+	case size < 1<<12:
+		return append(out, typ|1<<2|byte(size)<<4, byte(size>>4))
+	default:
+		return append(out, typ|3<<2|byte(size)<<4, byte(size>>4), byte(size>>12))
+	}
+}
+
+// huffEncoder builds Huffman codes for literals.
+type huffEncoder struct {
+	count  [256]uint32
+	length [256]uint8  // code length of each symbol, 0 if unused
+	code   [256]uint16 // code of each symbol
+	nodes  []huffNode
+}
+
+// huffNode is a node in a Huffman tree while computing code lengths.
+type huffNode struct {
+	count  uint32
+	sym    int // symbol for leaves, -1 for internal nodes
+	parent int
+}
+
+// appendLiterals appends a Compressed_Literals_Block holding lits to out.
+// It reports false if lits can't be usefully compressed.
+// RFC 3.1.1.3.1.4.
+func (h *huffEncoder) appendLiterals(out, lits []byte) ([]byte, bool) {
+	clear(h.count[:])
+	for _, c := range lits {
+		h.count[c]++
+	}
+	maxBits := h.buildLengths()
+	if maxBits == 0 {
+		return out, false
+	}
+	h.assignCodes(maxBits)
+
+	// Leave room for the largest header; we move the data if the
+	// header turns out to be smaller.
+	const maxHeaderSize = 5
+	hdrPos := len(out)
+	out = append(out, 0, 0, 0, 0, 0)
+	dataPos := len(out)
+	var ok bool
+	out, ok = h.appendDescription(out, maxBits)
+	if !ok {
+		return out[:hdrPos], false
+	}
+
+	var streams int
+	if len(lits) <= 1023 {
+		streams = 1
+		out = h.appendStream(out, lits)
+	} else {
+		streams = 4
+		// Jump table. RFC 3.1.1.3.1.6.
+		jump := len(out)
+		out = append(out, 0, 0, 0, 0, 0, 0)
+		segment := (len(lits) + 3) / 4
+		for i := 0; i < 4; i++ {
+			streamStart := len(out)
+			out = h.appendStream(out, lits[min(i*segment, len(lits)):min((i+1)*segment, len(lits))])
+			if i < 3 {
+				size := len(out) - streamStart
+				if size > 0xffff {
+					return out[:hdrPos], false
+				}
+				binary.LittleEndian.PutUint16(out[jump+2*i:], uint16(size))
+			}
+		}
+	}
+
+	regen := len(lits)
+	comp := len(out) - dataPos
+	var hdr []byte
+	switch {
+	case streams == 1 && comp < 1<<10:
+		v := uint32(literalsCompressed) | uint32(regen)<<4 | uint32(comp)<<14
+		hdr = []byte{byte(v), byte(v >> 8), byte(v >> 16)}
+	case streams == 4 && regen < 1<<10 && comp < 1<<10:
+		v := uint32(literalsCompressed) | 1<<2 | uint32(regen)<<4 | uint32(comp)<<14
+		hdr = []byte{byte(v), byte(v >> 8), byte(v >> 16)}
+	case streams == 4 && regen < 1<<14 && comp < 1<<14:
+		v := uint32(literalsCompressed) | 2<<2 | uint32(regen)<<4 | uint32(comp)<<18
+		hdr = binary.LittleEndian.AppendUint32(nil, v)
+	case streams == 4 && regen < 1<<18 && comp < 1<<18:
+		v := uint64(literalsCompressed) | 3<<2 | uint64(regen)<<4 | uint64(comp)<<22
+		hdr = binary.LittleEndian.AppendUint64(nil, v)[:5]
+	default:
+		return out[:hdrPos], false
+	}
+	copy(out[hdrPos:], hdr)
+	if len(hdr) < maxHeaderSize {
+		out = append(out[:hdrPos+len(hdr)], out[dataPos:]...)
+	}
+	return out, true
+}
+
+// buildLengths computes the length of the Huffman code of each symbol
+// from the counts. It returns the maximum code length, or 0 if there
+// are fewer than two different symbols.
+func (h *huffEncoder) buildLengths() uint8 {
+	clear(h.length[:])
+	nodes := h.nodes[:0]
+	for s, c := range h.count {
+		if c > 0 {
+			nodes = append(nodes, huffNode{count: c, sym: s})
+		}
+	}
+	leaves := len(nodes)
+	if leaves < 2 {
+		return 0
+	}
+	slices.SortFunc(nodes, func(a, b huffNode) int {
+		if a.count != b.count {
+			if a.count < b.count {
+				return -1
+			}
+			return 1
+		}
+		return a.sym - b.sym
+	})
+
+	// Build the tree with two queues: the sorted leaves, and the
+	// internal nodes, which are created in increasing count order.
+	leaf, internal := 0, leaves
+	pick := func() int {
+		if leaf < leaves && (internal >= len(nodes) || nodes[leaf].count <= nodes[internal].count) {
+			leaf++
+			return leaf - 1
+		}
+		internal++
+		return internal - 1
+	}
+	for i := 0; i < leaves-1; i++ {
+		a := pick()
+		b := pick()
+		nodes = append(nodes, huffNode{count: nodes[a].count + nodes[b].count, sym: -1})
+		nodes[a].parent = len(nodes) - 1
+		nodes[b].parent = len(nodes) - 1
+	}
+	h.nodes = nodes
+
+	// Internal nodes follow their children, so walk backward from
+	// the root computing depths, stored in the parent field.
+	root := len(nodes) - 1
+	nodes[root].parent = 0
+	for i := root - 1; i >= 0; i-- {
+		nodes[i].parent = nodes[nodes[i].parent].parent + 1
+	}
+
+	maxBits := uint8(0)
+	for _, n := range nodes[:leaves] {
+		l := uint8(min(n.parent, 255))
+		h.length[n.sym] = l
+		maxBits = max(maxBits, l)
+	}
+	if maxBits > maxHuffmanBits {
+		h.limitLengths(nodes[:leaves])
+		maxBits = maxHuffmanBits
+	}
+	return maxBits
+}
+
+// limitLengths adjusts the code lengths so that none is longer
+// than maxHuffmanBits, while keeping the code complete.
+// leaves is the list of symbols sorted by increasing count.
+func (h *huffEncoder) limitLengths(leaves []huffNode) {
+	// Measure the code space used in units of 1<<-maxHuffmanBits.
+	const full = 1 << maxHuffmanBits
+	used := 0
+	for _, n := range leaves {
+		h.length[n.sym] = min(h.length[n.sym], maxHuffmanBits)
+		used += full >> h.length[n.sym]
+	}
+
+	// Make codes longer, starting with the rarest symbols whose
+	// codes are not already at the limit, until the code fits.
+	for used > full {
+		best := -1
+		for i, n := range leaves {
+			l := h.length[n.sym]
+			if l < maxHuffmanBits && (best < 0 || l > h.length[leaves[best].sym]) {
+				best = i
+			}
+		}
+		s := leaves[best].sym
+		used -= full >> (h.length[s] + 1)
+		h.length[s]++
+	}
+
+	// Fill any unused code space by making the longest codes shorter,
+	// starting with the most common symbols.
+	for used < full {
+		best := -1
+		for i := len(leaves) - 1; i >= 0; i-- {
+			l := h.length[leaves[i].sym]
+			if best < 0 || l > h.length[leaves[best].sym] {
+				best = i
+			}
+		}
+		s := leaves[best].sym
+		used += full >> h.length[s]
+		h.length[s]--
+	}
+}
+
+// assignCodes assigns codes in the order used by readHuff:
+// symbols with longer codes come first, and symbols with the same
+// code length are in increasing order. RFC 4.2.1.
+func (h *huffEncoder) assignCodes(maxBits uint8) {
+	next := uint32(0)
+	for l := maxBits; l > 0; l-- {
+		for s, sl := range h.length {
+			if sl == l {
+				h.code[s] = uint16(next >> (maxBits - l))
+				next += 1 << (maxBits - l)
+			}
+		}
+	}
+}
+
+// appendDescription appends the Huffman tree description,
+// which gives the weight of each symbol. RFC 4.2.1.
+// It reports false if the description can't be written.
+func (h *huffEncoder) appendDescription(out []byte, maxBits uint8) ([]byte, bool) {
+	// The weight of the last symbol is not written.
+	last := 255
+	for h.length[last] == 0 {
+		last--
+	}
+	var weights [256]uint8
+	var count [13]uint32
+	for s, l := range h.length[:last] {
+		if l > 0 {
+			weights[s] = maxBits + 1 - l
+		}
+		count[weights[s]]++
+	}
+
+	if out, ok := appendFSEWeights(out, weights[:last], count[:maxBits+1]); ok {
+		return out, true
+	}
+	if last > 128 {
+		return out, false
+	}
+
+	// Write the weights directly, as 4-bit values.
+	out = append(out, byte(127+last))
+	for i := 0; i < last; i += 2 {
+		out = append(out, weights[i]<<4|weights[i+1])
+	}
+	return out, true
+}
+
+// appendFSEWeights appends a Huffman tree description with the weights
+// compressed using FSE. It reports false if that is not possible or
+// would not be smaller than writing the weights directly. RFC 4.2.1.2.
+func appendFSEWeights(out []byte, weights []uint8, count []uint32) ([]byte, bool) {
+	n := len(weights)
+	if n < 2 {
+		return out, false
+	}
+	maxSym := len(count) - 1
+	for count[maxSym] == 0 {
+		maxSym--
+	}
+
+	var norm [13]int16
+	tableLog := fseTableLog(6, n, maxSym)
+	normalizeCounts(norm[:maxSym+1], count[:maxSym+1], n, tableLog)
+
+	// The decoder detects the end of the stream when it runs out of
+	// bits to update the state of the next to last weight, so the
+	// state for that weight must need at least one bit.
+	if int(norm[weights[n-2]]) > 1<<(tableLog-1) {
+		return out, false
+	}
+
+	hdrPos := len(out)
+	out = append(out, 0)
+	out = appendFSETable(out, norm[:maxSym+1], tableLog)
+
+	var e fseEncoder
+	e.build(norm[:maxSym+1], tableLog)
+
+	// Encode two interleaved streams, with even weights using
+	// the first state and odd weights using the second.
+	bw := bitWriter{out: out}
+	var state1, state2 uint32
+	i := n
+	if n%2 == 1 {
+		state1 = e.initState(weights[n-1])
+		state2 = e.initState(weights[n-2])
+		state1 = e.encode(&bw, state1, weights[n-3])
+		i -= 3
+	} else {
+		state2 = e.initState(weights[n-1])
+		state1 = e.initState(weights[n-2])
+		i -= 2
+	}
+	for i > 0 {
+		state2 = e.encode(&bw, state2, weights[i-1])
+		state1 = e.encode(&bw, state1, weights[i-2])
+		i -= 2
+	}
+	e.flushState(&bw, state2)
+	e.flushState(&bw, state1)
+	out = bw.close()
+
+	size := len(out) - hdrPos - 1
+	if size >= 128 || (n <= 128 && size >= (n+1)/2) {
+		return out[:hdrPos], false
+	}
+	out[hdrPos] = byte(size)
+	return out, true
+}
+
+// appendStream appends a Huffman-coded stream of lits to out.
+func (h *huffEncoder) appendStream(out, lits []byte) []byte {
+	// The decoder reads the stream backward.
+	bw := bitWriter{out: out}
+	for i := len(lits) - 1; i >= 0; i-- {
+		c := lits[i]
+		bw.addBits(uint32(h.code[c]), h.length[c])
+	}
+	return bw.close()
+}
@@ -0,0 +1,404 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// blockType is the type of a block. RFC 3.1.1.2.2.
+type blockType uint8
+
+const (
+	blockRaw blockType = iota
+	blockRLE
+	blockCompressed
+)
+
+// defaultLevel is the level used for DefaultCompression.
+const defaultLevel = 3
+
+// minMatch is the shortest match that the encoder looks for.
+// The format permits matches of 3 bytes, but they rarely pay off.
+const minMatch = 4
+
+// levelParams describes how the encoder searches for matches
+// at a given compression level.
+type levelParams struct {
+	windowLog uint8 // log2 of the window size
+	hashLog   uint8 // log2 of the hash table size
+	chainLog  uint8 // log2 of the hash chain size; 0 for no chain
+	depth     int   // maximum number of chain entries to check
+	lazy      int   // number of positions to look ahead for a better match
+	skipLog   uint8 // controls skipping ahead over incompressible data
+}
+
+// levels holds the parameters for each compression level.
+// Entry 0 is used for NoCompression, which does not search.
+var levels = [...]levelParams{
+	{windowLog: 17},
+	{windowLog: 19, hashLog: 15, depth: 1, skipLog: 5},
+	{windowLog: 20, hashLog: 16, depth: 1, skipLog: 6},
+	{windowLog: 21, hashLog: 16, chainLog: 16, depth: 4, skipLog: 8},
+	{windowLog: 21, hashLog: 17, chainLog: 17, depth: 8, lazy: 1, skipLog: 8},
+	{windowLog: 22, hashLog: 17, chainLog: 18, depth: 16, lazy: 1, skipLog: 9},
+	{windowLog: 22, hashLog: 18, chainLog: 19, depth: 32, lazy: 2, skipLog: 9},
+	{windowLog: 23, hashLog: 19, chainLog: 20, depth: 64, lazy: 2, skipLog: 10},
+	{windowLog: 23, hashLog: 20, chainLog: 21, depth: 128, lazy: 2, skipLog: 10},
+	{windowLog: 23, hashLog: 20, chainLog: 22, depth: 256, lazy: 2, skipLog: 11},
+}
+
+// sequence is a single zstd sequence: some literals followed by a match.
+type sequence struct {
+	litLen   uint32 // number of literal bytes
+	matchLen uint32 // length of the match
+	offBase  uint32 // Offset_Value, which is a repeat code or offset+3
+}
+
+// encoder compresses blocks of data.
+// It holds the match finder state, which persists across the
+// blocks of a frame.
+type encoder struct {
+	p levelParams
+
+	// table maps a hash of four bytes to the most recent position
+	// in the history with that hash, plus one. Zero means no position.
+	table []int32
+
+	// chain maps a position, masked by the chain size, to the
+	// previous position with the same hash, plus one.
+	chain []int32
+
+	// nextInsert is the next position to add to table and chain.
+	nextInsert int
+
+	// reps holds the repeated offsets. RFC 3.1.2.5.
+	reps [3]uint32
+
+	seqs []sequence
+	lits []byte
+
+	// Scratch space for entropy coding.
+	llCodes []uint8
+	ofCodes []uint8
+	mlCodes []uint8
+	fse     [3]fseEncoder
+	huff    huffEncoder
+}
+
+// init prepares e to compress at level.
+func (e *encoder) init(level int) {
+	e.p = levels[level]
+}
+
+// reset prepares e for a new frame.
+func (e *encoder) reset() {
+	clear(e.table)
+	clear(e.chain)
+	e.nextInsert = 0
+	e.reps = [3]uint32{1, 4, 8}
+}
+
+// windowLog returns log2 of the window size.
+func (e *encoder) windowLog() uint8 {
+	return e.p.windowLog
+}
+
+// windowSize returns the window size.
+func (e *encoder) windowSize() int {
+	return 1 << e.p.windowLog
+}
+
+// shiftAlign returns the value that the amount of discarded history
+// must be a multiple of.
+func (e *encoder) shiftAlign() int {
+	if e.p.chainLog == 0 {
+		return 1
+	}
+	return 1 << e.p.chainLog
+}
+
+// shift adjusts the match finder for the discarding of n bytes of history.
+// n must be a multiple of e.shiftAlign().
+func (e *encoder) shift(n int) {
+	shiftPositions(e.table, n)
+	shiftPositions(e.chain, n)
+	e.nextInsert = max(e.nextInsert-n, 0)
+}
+
+// shiftPositions subtracts n from each position in s,
+// dropping positions that are no longer in the history.
+func shiftPositions(s []int32, n int) {
+	for i, v := range s {
+		if int(v) <= n {
+			s[i] = 0
+		} else {
+			s[i] = v - int32(n)
+		}
+	}
+}
+
+// compressBlock appends the compressed form of hist[start:] to out,
+// returning the extended buffer and the block type.
+// The data in hist before start is history that matches may refer to.
+func (e *encoder) compressBlock(out, hist []byte, start int) ([]byte, blockType) {
+	src := hist[start:]
+	if len(src) == 0 {
+		return out, blockRaw
+	}
+	if isRLE(src) {
+		return append(out, src[0]), blockRLE
+	}
+
+	e.alloc()
+
+	// If we fall back to a raw block, the decoder will not see
+	// the sequences, so it will not update the repeated offsets.
+	savedReps := e.reps
+
+	e.findSequences(hist, start)
+
+	blockStart := len(out)
+	out = e.appendLiterals(out, e.lits)
+	out = e.appendSequences(out)
+	if len(out)-blockStart >= len(src) {
+		e.reps = savedReps
+		return append(out[:blockStart], src...), blockRaw
+	}
+	return out, blockCompressed
+}
+
+// alloc allocates the match finder tables, if not already done.
+func (e *encoder) alloc() {
+	if e.table == nil {
+		e.table = make([]int32, 1<<e.p.hashLog)
+		if e.p.chainLog > 0 {
+			e.chain = make([]int32, 1<<e.p.chainLog)
+		}
+	}
+}
+
+// loadHistory adds every position in hist to the match finder,
+// so that the first block of a frame can refer to a dictionary.
+func (e *encoder) loadHistory(hist []byte) {
+	e.alloc()
+	e.index(hist, len(hist)-minMatch+1)
+}
+
+// isRLE reports whether all the bytes in b are the same.
+func isRLE(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	c := b[0]
+	for _, x := range b[1:] {
+		if x != c {
+			return false
+		}
+	}
+	return true
+}
+
+// hash4 returns a hash of the four bytes at the start of b.
+func (e *encoder) hash4(b []byte) uint32 {
+	return (binary.LittleEndian.Uint32(b) * 2654435761) >> (32 - e.p.hashLog)
+}
+
+// insert adds positions from e.nextInsert up to, but not including,
+// end to the hash table and chain. Positions must have at least
+// four bytes of data available in src.
+func (e *encoder) insert(src []byte, end int) {
+	if e.chain == nil {
+		// Without a chain only recent positions matter.
+		e.nextInsert = max(e.nextInsert, end-2)
+	}
+	e.index(src, end)
+}
+
+// index adds positions from e.nextInsert up to, but not including,
+// end to the hash table and chain.
+func (e *encoder) index(src []byte, end int) {
+	chainMask := len(e.chain) - 1
+	for p := e.nextInsert; p < end; p++ {
+		h := e.hash4(src[p:])
+		if e.chain != nil {
+			e.chain[p&chainMask] = e.table[h]
+		}
+		e.table[h] = int32(p + 1)
+	}
+	e.nextInsert = max(e.nextInsert, end)
+}
+
+// matchLen returns the length of the common prefix of a and b.
+func matchLen(a, b []byte) int {
+	n := 0
+	for len(a) >= 8 && len(b) >= 8 {
+		x := binary.LittleEndian.Uint64(a) ^ binary.LittleEndian.Uint64(b)
+		if x != 0 {
+			return n + bits.TrailingZeros64(x)>>3
+		}
+		n += 8
+		a, b = a[8:], b[8:]
+	}
+	for i := 0; i < len(a) && i < len(b) && a[i] == b[i]; i++ {
+		n++
+	}
+	return n
+}
+
+// bestMatch finds the longest match for the data at src[pos:],
+// considering at most offsets that fit in the window.
+// It returns the match offset and length; the length is zero if there
+// is no match of at least minMatch bytes.
+// It adds pos, and any earlier positions not yet added,
+// to the hash table.
+func (e *encoder) bestMatch(src []byte, pos int) (offset, length int) {
+	if e.chain != nil {
+		e.insert(src, pos)
+	}
+
+	minPos := max(0, pos-e.windowSize())
+
+	// Try the most recent offset first: it is cheap to encode.
+	if rep := int(e.reps[0]); pos-rep >= minPos {
+		if l := matchLen(src[pos-rep:], src[pos:]); l >= minMatch {
+			offset, length = rep, l
+		}
+	}
+
+	h := e.hash4(src[pos:])
+	cand := int(e.table[h]) - 1
+	if pos >= e.nextInsert {
+		if e.chain != nil {
+			e.chain[pos&(len(e.chain)-1)] = e.table[h]
+		}
+		e.table[h] = int32(pos + 1)
+		e.nextInsert = pos + 1
+	} else if cand == pos && e.chain != nil {
+		cand = int(e.chain[pos&(len(e.chain)-1)]) - 1
+	}
+
+	for depth := e.p.depth; depth > 0 && cand >= minPos && cand < pos; depth-- {
+		// Check the byte that would extend the current best match
+		// before comparing the whole candidate.
+		if pos+length < len(src) && src[cand+length] == src[pos+length] {
+			if l := matchLen(src[cand:], src[pos:]); l > length {
+				offset, length = pos-cand, l
+				if pos+l == len(src) {
+					break
+				}
+			}
+		}
+		// Chain entries for positions more than the chain size
+		// back have been overwritten.
+		if e.chain == nil || pos-cand >= len(e.chain) {
+			break
+		}
+		next := int(e.chain[cand&(len(e.chain)-1)]) - 1
+		if next >= cand {
+			break
+		}
+		cand = next
+	}
+
+	if length < minMatch {
+		return 0, 0
+	}
+	return offset, length
+}
+
+// matchGain estimates the benefit of a match, for lazy matching.
+func matchGain(offset, length int) int {
+	return length*4 - bits.Len32(uint32(offset))
+}
+
+// findSequences finds the sequences that describe hist[start:],
+// storing them in e.seqs and the corresponding literals in e.lits.
+func (e *encoder) findSequences(hist []byte, start int) {
+	e.seqs = e.seqs[:0]
+	e.lits = e.lits[:0]
+
+	src := hist
+	end := len(src)
+	// Leave room for the lookahead reads.
+	limit := end - 8 - e.p.lazy
+
+	anchor := start
+	pos := start
+	for pos < limit {
+		offset, length := e.bestMatch(src, pos)
+		if length == 0 {
+			pos += 1 + (pos-anchor)>>e.p.skipLog
+			continue
+		}
+
+		// Lazy matching: see if a match starting a bit later is better.
+		for i := 0; i < e.p.lazy && pos+1 < limit; i++ {
+			offset2, length2 := e.bestMatch(src, pos+1)
+			if length2 == 0 || matchGain(offset2, length2) <= matchGain(offset, length)+4 {
+				break
+			}
+			pos++
+			offset, length = offset2, length2
+		}
+
+		// Extend the match backward.
+		for pos > anchor && pos-offset > 0 && src[pos-1] == src[pos-offset-1] {
+			pos--
+			length++
+		}
+
+		e.addSequence(src[anchor:pos], offset, length)
+		pos += length
+		anchor = pos
+		e.insert(src, min(pos, limit))
+	}
+	e.lits = append(e.lits, src[anchor:end]...)
+}
+
+// addSequence records a sequence with literals lits followed by a match
+// of length at offset.
+func (e *encoder) addSequence(lits []byte, offset, length int) {
+	e.lits = append(e.lits, lits...)
+	e.seqs = append(e.seqs, sequence{
+		litLen:   uint32(len(lits)),
+		matchLen: uint32(length),
+		offBase:  e.offBase(uint32(offset), len(lits) == 0),
+	})
+}
+
+// offBase converts offset to an Offset_Value, using the repeated
+// offsets where possible, and updates the repeated offsets the way
+// the decoder will. RFC 3.1.2.5.
+func (e *encoder) offBase(offset uint32, noLits bool) uint32 {
+	r := &e.reps
+	if !noLits {
+		switch offset {
+		case r[0]:
+			return 1
+		case r[1]:
+			r[0], r[1] = r[1], r[0]
+			return 2
+		case r[2]:
+			r[0], r[1], r[2] = r[2], r[0], r[1]
+			return 3
+		}
+	} else {
+		switch offset {
+		case r[1]:
+			r[0], r[1] = r[1], r[0]
+			return 1
+		case r[2]:
+			r[0], r[1], r[2] = r[2], r[0], r[1]
+			return 2
+		case r[0] - 1:
+			r[0], r[1], r[2] = offset, r[0], r[1]
+			return 3
+		}
+	}
+	r[0], r[1], r[2] = offset, r[0], r[1]
+	return offset + 3
+}
@@ -0,0 +1,557 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// bitWriter writes a bit stream. The bits of each value are stored
+// starting with the low bits of each byte, so that a stream closed
+// with close can be read by a reverseBitReader.
+type bitWriter struct {
+	out   []byte
+	bits  uint64
+	nbits uint
+}
+
+// addBits adds the low n bits of v to the stream. n must be at most 32.
+func (bw *bitWriter) addBits(v uint32, n uint8) {
+	bw.bits |= (uint64(v) & (1<<n - 1)) << bw.nbits
+	bw.nbits += uint(n)
+	for bw.nbits >= 8 {
+		bw.out = append(bw.out, byte(bw.bits))
+		bw.bits >>= 8
+		bw.nbits -= 8
+	}
+}
+
+// flush writes any partial byte, padding with zero bits.
+func (bw *bitWriter) flush() []byte {
+	if bw.nbits > 0 {
+		bw.out = append(bw.out, byte(bw.bits))
+		bw.bits = 0
+		bw.nbits = 0
+	}
+	return bw.out
+}
+
+// close writes the 1 bit that marks the end of a stream that is read
+// in reverse, and then flushes the stream.
+func (bw *bitWriter) close() []byte {
+	bw.addBits(1, 1)
+	return bw.flush()
+}
+
+// fseEncoder is an FSE encoding table. It produces bit streams
+// that are decoded using a table built by buildFSE from the
+// same distribution.
+type fseEncoder struct {
+	tableLog   uint8
+	stateTable []uint16
+	symbols    [256]fseSymbolTransform
+}
+
+// fseSymbolTransform describes how to encode one symbol.
+type fseSymbolTransform struct {
+	deltaNbBits    uint32 // used to compute the number of bits to write
+	deltaFindState int32  // offset into stateTable for this symbol
+}
+
+// build sets up e to encode symbols with the normalized distribution
+// norm, which must add up to 1<<tableLog counting -1 values as 1.
+// The symbols are spread as in buildFSE. RFC 4.1.1.
+func (e *fseEncoder) build(norm []int16, tableLog uint8) {
+	tableSize := 1 << tableLog
+	e.tableLog = tableLog
+	if cap(e.stateTable) < tableSize {
+		e.stateTable = make([]uint16, tableSize)
+	}
+	e.stateTable = e.stateTable[:tableSize]
+
+	var spread [1 << 9]uint8
+	highThreshold := tableSize - 1
+	for s, n := range norm {
+		if n < 0 {
+			spread[highThreshold] = uint8(s)
+			highThreshold--
+		}
+	}
+	pos := 0
+	step := (tableSize >> 1) + (tableSize >> 3) + 3
+	mask := tableSize - 1
+	for s, n := range norm {
+		for j := 0; j < int(n); j++ {
+			spread[pos] = uint8(s)
+			pos = (pos + step) & mask
+			for pos > highThreshold {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+
+	// Order the states by symbol; the states for each symbol are
+	// in increasing order.
+	var cumul [257]int
+	for s, n := range norm {
+		cumul[s+1] = cumul[s] + max(int(n), 0)
+		if n < 0 {
+			cumul[s+1]++
+		}
+	}
+	for u := 0; u < tableSize; u++ {
+		s := spread[u]
+		e.stateTable[cumul[s]] = uint16(tableSize + u)
+		cumul[s]++
+	}
+
+	total := 0
+	for s, n := range norm {
+		switch {
+		case n == 0:
+			e.symbols[s] = fseSymbolTransform{}
+		case n == 1 || n == -1:
+			e.symbols[s] = fseSymbolTransform{
+				deltaNbBits:    uint32(tableLog)<<16 - uint32(tableSize),
+				deltaFindState: int32(total - 1),
+			}
+			total++
+		default:
+			maxBitsOut := uint32(tableLog) - uint32(bits.Len16(uint16(n-1))-1)
+			minStatePlus := uint32(n) << maxBitsOut
+			e.symbols[s] = fseSymbolTransform{
+				deltaNbBits:    maxBitsOut<<16 - minStatePlus,
+				deltaFindState: int32(total - int(n)),
+			}
+			total += int(n)
+		}
+	}
+}
+
+// initState returns the initial encoder state for the last symbol
+// in a stream, which is the first symbol decoded.
+func (e *fseEncoder) initState(sym uint8) uint32 {
+	tt := e.symbols[sym]
+	nbBits := (tt.deltaNbBits + 1<<15) >> 16
+	v := nbBits<<16 - tt.deltaNbBits
+	return uint32(e.stateTable[int32(v>>nbBits)+tt.deltaFindState])
+}
+
+// encode writes the bits that lead from sym to the symbol
+// represented by state, and returns the state representing sym.
+func (e *fseEncoder) encode(bw *bitWriter, state uint32, sym uint8) uint32 {
+	tt := e.symbols[sym]
+	nbBits := (state + tt.deltaNbBits) >> 16
+	bw.addBits(state, uint8(nbBits))
+	return uint32(e.stateTable[int32(state>>nbBits)+tt.deltaFindState])
+}
+
+// flushState writes the final state, which is the initial state for
+// the decoder.
+func (e *fseEncoder) flushState(bw *bitWriter, state uint32) {
+	bw.addBits(state, e.tableLog)
+}
+
+// fseTableLog picks a table size for an FSE distribution of total
+// symbols whose largest value is maxSym, limited to maxLog.
+func fseTableLog(maxLog uint8, total, maxSym int) uint8 {
+	tableLog := int(maxLog)
+	if l := bits.Len(uint(total-1)) - 3; l < tableLog {
+		tableLog = l
+	}
+	minLog := min(bits.Len(uint(total)), bits.Len(uint(maxSym))+1)
+	tableLog = max(tableLog, minLog, 5)
+	return uint8(min(tableLog, int(maxLog)))
+}
+
+// normalizeCounts sets norm to a distribution proportional to count
+// that adds up to 1<<tableLog. total is the sum of count.
+// Symbols with a very low count are given the probability -1,
+// which means "less than 1". RFC 4.1.1.
+func normalizeCounts(norm []int16, count []uint32, total int, tableLog uint8) {
+	tableSize := 1 << tableLog
+	sum := 0
+	largest := 0
+	for s, c := range count {
+		switch {
+		case c == 0:
+			norm[s] = 0
+			continue
+		case int(c)*tableSize < total:
+			norm[s] = -1
+			sum++
+			continue
+		}
+		n := (int(c)*tableSize + total/2) / total
+		norm[s] = int16(n)
+		sum += n
+		if n > int(norm[largest]) {
+			largest = s
+		}
+	}
+
+	// Fix up rounding errors, preferring to adjust the most
+	// probable symbols, which affects them the least.
+	for sum != tableSize {
+		if sum < tableSize {
+			norm[largest] += int16(tableSize - sum)
+			break
+		}
+		if int(norm[largest])-(sum-tableSize) >= int(norm[largest]+1)/2 {
+			norm[largest] -= int16(sum - tableSize)
+			break
+		}
+		// Take one from each of the largest symbols in turn.
+		big := 0
+		for s, n := range norm {
+			if n > norm[big] {
+				big = s
+			}
+		}
+		norm[big]--
+		sum--
+		largest = big
+	}
+}
+
+// fseCost returns an estimate of the number of bits needed to encode
+// symbols with count using the distribution norm, or -1 if norm
+// can not encode them.
+func fseCost(count []uint32, norm []int16, tableLog uint8) int {
+	var cost float64
+	for s, c := range count {
+		if c == 0 {
+			continue
+		}
+		if s >= len(norm) || norm[s] == 0 {
+			return -1
+		}
+		n := max(norm[s], 1)
+		cost += float64(c) * (float64(tableLog) - math.Log2(float64(n)))
+	}
+	return int(cost)
+}
+
+// appendFSETable appends a description of the distribution norm,
+// in the format read by readFSE. RFC 4.1.1.
+func appendFSETable(out []byte, norm []int16, tableLog uint8) []byte {
+	bw := bitWriter{out: out}
+	bw.addBits(uint32(tableLog-5), 4)
+
+	tableSize := 1 << tableLog
+	remaining := tableSize + 1
+	threshold := tableSize
+	nbBits := tableLog + 1
+	prev0 := false
+	sym := 0
+	for remaining > 1 {
+		if prev0 {
+			start := sym
+			for norm[sym] == 0 {
+				sym++
+			}
+			for sym >= start+24 {
+				start += 24
+				bw.addBits(0xffff, 16)
+			}
+			for sym >= start+3 {
+				start += 3
+				bw.addBits(3, 2)
+			}
+			bw.addBits(uint32(sym-start), 2)
+		}
+
+		count := int(norm[sym])
+		sym++
+		max := 2*threshold - 1 - remaining
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		count++
+		if count >= threshold {
+			count += max
+		}
+		if count < max {
+			bw.addBits(uint32(count), nbBits-1)
+		} else {
+			bw.addBits(uint32(count), nbBits)
+		}
+		prev0 = count == 1
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+	}
+	return bw.flush()
+}
+
+// seqEncInfo is the information needed to encode a kind of
+// sequence code.
+type seqEncInfo struct {
+	predef    []int16 // predefined distribution
+	predefLog uint8   // table log of predefined distribution
+	maxSym    int     // max symbol value
+	maxLog    uint8   // max table log
+}
+
+// seqEncInfos is the seqEncInfo for each kind of sequence code,
+// matching seqCodeInfo.
+var seqEncInfos = [3]seqEncInfo{
+	seqLiteral: {
+		predef:    literalPredefinedDistribution,
+		predefLog: 6,
+		maxSym:    35,
+		maxLog:    9,
+	},
+	seqOffset: {
+		predef:    offsetPredefinedDistribution,
+		predefLog: 5,
+		maxSym:    31,
+		maxLog:    8,
+	},
+	seqMatch: {
+		predef:    matchPredefinedDistribution,
+		predefLog: 6,
+		maxSym:    52,
+		maxLog:    9,
+	},
+}
+
+// literalPredefinedDistribution is the predefined distribution table
+// for literal lengths. RFC 3.1.1.3.2.2.1.
+var literalPredefinedDistribution = []int16{
+	4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+	2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+	-1, -1, -1, -1,
+}
+
+// offsetPredefinedDistribution is the predefined distribution table
+// for offsets. RFC 3.1.1.3.2.2.3.
+var offsetPredefinedDistribution = []int16{
+	1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+}
+
+// matchPredefinedDistribution is the predefined distribution table
+// for match lengths. RFC 3.1.1.3.2.2.2.
+var matchPredefinedDistribution = []int16{
+	1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+	-1, -1, -1, -1, -1,
+}
+
+// predefinedEncoders returns the FSE encoders for the predefined
+// distributions.
+var predefinedEncoders = sync.OnceValue(func() *[3]fseEncoder {
+	var encs [3]fseEncoder
+	for kind, info := range seqEncInfos {
+		encs[kind].build(info.predef, info.predefLog)
+	}
+	return &encs
+})
+
+// literalLengthCodes maps small literal lengths to their codes.
+var literalLengthCodes = [64]uint8{
+	0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+	16, 16, 17, 17, 18, 18, 19, 19, 20, 20, 20, 20, 21, 21, 21, 21,
+	22, 22, 22, 22, 22, 22, 22, 22, 23, 23, 23, 23, 23, 23, 23, 23,
+	24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24,
+}
+
+// literalLengthCode returns the code for a literal length.
+// RFC 3.1.1.3.2.1.1.
+func literalLengthCode(litLen uint32) uint8 {
+	if litLen < 64 {
+		return literalLengthCodes[litLen]
+	}
+	return uint8(bits.Len32(litLen)) - 1 + 19
+}
+
+// matchLengthCodes maps small match lengths, minus 3, to their codes.
+var matchLengthCodes = [128]uint8{
+	0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+	16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
+	32, 32, 33, 33, 34, 34, 35, 35, 36, 36, 36, 36, 37, 37, 37, 37,
+	38, 38, 38, 38, 38, 38, 38, 38, 39, 39, 39, 39, 39, 39, 39, 39,
+	40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40,
+	41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41,
+	42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42,
+	42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42,
+}
+
+// matchLengthCode returns the code for a match length.
+// RFC 3.1.1.3.2.1.1.
+func matchLengthCode(matchLen uint32) uint8 {
+	mlBase := matchLen - 3
+	if mlBase < 128 {
+		return matchLengthCodes[mlBase]
+	}
+	return uint8(bits.Len32(mlBase)) - 1 + 36
+}
+
+// literalLengthExtra returns the baseline and number of extra bits
+// for a literal length code.
+func literalLengthExtra(code uint8) (uint32, uint8) {
+	if code < literalLengthOffset {
+		return uint32(code), 0
+	}
+	v := literalLengthBase[code-literalLengthOffset]
+	return v & 0xffffff, uint8(v >> 24)
+}
+
+// matchLengthExtra returns the baseline and number of extra bits
+// for a match length code.
+func matchLengthExtra(code uint8) (uint32, uint8) {
+	if code < matchLengthOffset {
+		return uint32(code) + 3, 0
+	}
+	v := matchLengthBase[code-matchLengthOffset]
+	return v & 0xffffff, uint8(v >> 24)
+}
+
+// Symbol compression modes. RFC 3.1.1.3.2.1.
+const (
+	modePredefined = 0
+	modeRLE        = 1
+	modeCompressed = 2
+)
+
+// appendSequences appends the sequences section for e.seqs to out.
+// RFC 3.1.1.3.2.
+func (e *encoder) appendSequences(out []byte) []byte {
+	nb := len(e.seqs)
+	switch {
+	case nb < 128:
+		out = append(out, byte(nb))
+	case nb < 0x7f00:
+		out = append(out, byte(nb>>8)+128, byte(nb))
+	default:
+		out = append(out, 0xff, byte(nb-0x7f00), byte((nb-0x7f00)>>8))
+	}
+	if nb == 0 {
+		return out
+	}
+
+	e.llCodes = e.llCodes[:0]
+	e.ofCodes = e.ofCodes[:0]
+	e.mlCodes = e.mlCodes[:0]
+	var llCount [36]uint32
+	var ofCount [32]uint32
+	var mlCount [53]uint32
+	for _, s := range e.seqs {
+		llc := literalLengthCode(s.litLen)
+		ofc := uint8(bits.Len32(s.offBase) - 1)
+		mlc := matchLengthCode(s.matchLen)
+		e.llCodes = append(e.llCodes, llc)
+		e.ofCodes = append(e.ofCodes, ofc)
+		e.mlCodes = append(e.mlCodes, mlc)
+		llCount[llc]++
+		ofCount[ofc]++
+		mlCount[mlc]++
+	}
+
+	modePos := len(out)
+	out = append(out, 0)
+	var encs [3]*fseEncoder
+	var modes [3]uint8
+	for kind, count := range [3][]uint32{llCount[:], ofCount[:], mlCount[:]} {
+		out, modes[kind], encs[kind] = e.chooseTable(out, seqCode(kind), count, nb)
+	}
+	out[modePos] = modes[seqLiteral]<<6 | modes[seqOffset]<<4 | modes[seqMatch]<<2
+
+	// The decoder reads the bit stream backward, so we encode
+	// the sequences from last to first. RFC 3.1.1.3.2.1.2.
+	bw := bitWriter{out: out}
+	last := nb - 1
+	llState := encs[seqLiteral].initRLE(e.llCodes[last])
+	ofState := encs[seqOffset].initRLE(e.ofCodes[last])
+	mlState := encs[seqMatch].initRLE(e.mlCodes[last])
+	e.addExtraBits(&bw, last)
+	for i := last - 1; i >= 0; i-- {
+		ofState = encs[seqOffset].encodeRLE(&bw, ofState, e.ofCodes[i])
+		mlState = encs[seqMatch].encodeRLE(&bw, mlState, e.mlCodes[i])
+		llState = encs[seqLiteral].encodeRLE(&bw, llState, e.llCodes[i])
+		e.addExtraBits(&bw, i)
+	}
+	encs[seqMatch].flushRLE(&bw, mlState)
+	encs[seqOffset].flushRLE(&bw, ofState)
+	encs[seqLiteral].flushRLE(&bw, llState)
+	return bw.close()
+}
+
+// addExtraBits writes the extra bits of sequence i that are added to
+// the baselines of the sequence codes.
+func (e *encoder) addExtraBits(bw *bitWriter, i int) {
+	s := &e.seqs[i]
+	base, n := literalLengthExtra(e.llCodes[i])
+	bw.addBits(s.litLen-base, n)
+	base, n = matchLengthExtra(e.mlCodes[i])
+	bw.addBits(s.matchLen-base, n)
+	ofc := e.ofCodes[i]
+	bw.addBits(s.offBase-1<<ofc, ofc)
+}
+
+// The RLE variants of the fseEncoder methods treat a nil encoder as
+// RLE_Mode, in which there are no state bits.
+
+func (e *fseEncoder) initRLE(sym uint8) uint32 {
+	if e == nil {
+		return 0
+	}
+	return e.initState(sym)
+}
+
+func (e *fseEncoder) encodeRLE(bw *bitWriter, state uint32, sym uint8) uint32 {
+	if e == nil {
+		return 0
+	}
+	return e.encode(bw, state, sym)
+}
+
+func (e *fseEncoder) flushRLE(bw *bitWriter, state uint32) {
+	if e != nil {
+		e.flushState(bw, state)
+	}
+}
+
+// chooseTable picks the compression mode for one kind of sequence code,
+// given the count of each code, and appends any table description to out.
+// It returns the extended buffer, the mode, and the encoder to use,
+// which is nil for RLE_Mode.
+func (e *encoder) chooseTable(out []byte, kind seqCode, count []uint32, nb int) ([]byte, uint8, *fseEncoder) {
+	info := &seqEncInfos[kind]
+
+	maxSym := len(count) - 1
+	for count[maxSym] == 0 {
+		maxSym--
+	}
+	if int(count[maxSym]) == nb {
+		// All the codes are the same.
+		if nb > 2 || fseCost(count, info.predef, info.predefLog) < 0 {
+			return append(out, byte(maxSym)), modeRLE, nil
+		}
+	}
+
+	predefCost := fseCost(count, info.predef, info.predefLog)
+	if nb < 64 && predefCost >= 0 {
+		return out, modePredefined, &predefinedEncoders()[kind]
+	}
+
+	var norm [53]int16
+	tableLog := fseTableLog(info.maxLog, nb, maxSym)
+	normalizeCounts(norm[:maxSym+1], count[:maxSym+1], nb, tableLog)
+	desc := appendFSETable(out, norm[:maxSym+1], tableLog)
+	cost := fseCost(count, norm[:maxSym+1], tableLog) + 8*(len(desc)-len(out))
+	if predefCost >= 0 && predefCost <= cost {
+		return out, modePredefined, &predefinedEncoders()[kind]
+	}
+	e.fse[kind].build(norm[:maxSym+1], tableLog)
+	return desc, modeCompressed, &e.fse[kind]
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd_test
+
+import (
+	"bytes"
+	"compress/zstd"
+	"io"
+	"log"
+	"os"
+)
+
+func Example_writerReader() {
+	var buf bytes.Buffer
+	zw := zstd.NewWriter(&buf)
+
+	if _, err := zw.Write([]byte("A long time ago in a galaxy far, far away...\n")); err != nil {
+		log.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	zr := zstd.NewReader(&buf)
+	if _, err := io.Copy(os.Stdout, zr); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// A long time ago in a galaxy far, far away...
+}
+
+func ExampleNewWriterDict() {
+	// Small messages that share a lot of content with the dictionary
+	// compress much better than they would on their own.
+	dict := []byte(`{"type": "greeting", "language": "en", "text": "hello, world"}`)
+	msg := []byte(`{"type": "greeting", "language": "en", "text": "hello, gopher"}`)
+
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriterDict(&buf, zstd.BestCompression, dict)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := zw.Write(msg); err != nil {
+		log.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	zr, err := zstd.NewReaderDict(&buf, dict)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := io.Copy(os.Stdout, zr); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// {"type": "greeting", "language": "en", "text": "hello, gopher"}
+}
@@ -138,3 +138,34 @@ func FuzzReverse(f *testing.F) {
 		}
 	})
 }
+
+// Fuzz test to verify that the Writer's output decompresses to its input.
+func FuzzWriter(f *testing.F) {
+	for _, test := range tests {
+		f.Add([]byte(test.uncompressed), uint8(defaultLevel))
+	}
+	f.Add(bytes.Repeat([]byte("abcdefghijklmnop"), 256), uint8(BestSpeed))
+	f.Add(bytes.Repeat([]byte("abcdefghijklmnop"), 256), uint8(BestCompression))
+
+	f.Fuzz(func(t *testing.T, b []byte, level uint8) {
+		var compressed bytes.Buffer
+		w, err := NewWriterLevel(&compressed, int(level%(BestCompression+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := io.ReadAll(NewReader(&compressed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, b) {
+			showDiffs(t, got, b)
+		}
+	})
+}
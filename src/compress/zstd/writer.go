@@ -0,0 +1,332 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// These constants select the compression level used by a [Writer].
+// They follow the conventions of the [compress/flate] package.
+// Higher levels trade speed for a smaller output.
+// The levels do not correspond to the levels of the reference zstd
+// implementation.
+const (
+	NoCompression      = 0
+	BestSpeed          = 1
+	BestCompression    = 9
+	DefaultCompression = -1
+)
+
+// maxBlockSize is the maximum size of a block. RFC 3.1.1.2.3.
+const maxBlockSize = 128 << 10
+
+// frameMagic is the magic number at the start of a zstd frame.
+const frameMagic = 0xfd2fb528
+
+var errWriterClosed = errors.New("zstd: write to closed Writer")
+
+// A Writer is an [io.WriteCloser].
+// Writes to a Writer are compressed and written to w.
+//
+// Each Writer produces a single zstd frame, which is completed by
+// calling Close. The frame includes a content checksum.
+type Writer struct {
+	w     io.Writer
+	level int
+	dict  *dictionary
+	enc   encoder
+
+	// hist holds the history that may be referenced by matches,
+	// followed by the data that has not yet been compressed,
+	// which starts at hist[pending].
+	hist    []byte
+	pending int
+
+	wroteHeader bool
+	closed      bool
+	err         error
+
+	checksum xxhash64
+	out      []byte
+}
+
+// NewWriter returns a new [Writer] compressing data at the
+// [DefaultCompression] level.
+// Writes to the returned writer are compressed and written to w.
+//
+// It is the caller's responsibility to call Close on the [Writer] when done.
+// Writes may be buffered and not flushed until Close.
+//
+// Note that the exact bytes written to w are not covered by the Go 1
+// compatibility promise. Callers, including tests, should not depend on the
+// exact written bytes.
+func NewWriter(w io.Writer) *Writer {
+	z, _ := NewWriterLevel(w, DefaultCompression)
+	return z
+}
+
+// NewWriterLevel is like [NewWriter] but specifies the compression level
+// instead of assuming [DefaultCompression].
+//
+// The compression level can be [DefaultCompression], [NoCompression],
+// or any integer value between [BestSpeed] and [BestCompression] inclusive.
+// The error returned will be nil if the level is valid.
+func NewWriterLevel(w io.Writer, level int) (*Writer, error) {
+	return NewWriterDict(w, level, nil)
+}
+
+// NewWriterDict is like [NewWriterLevel] but compresses using a dictionary.
+// The dictionary may be either a zstd dictionary, as described in RFC 8878
+// section 5, or raw content that is likely to appear in the data.
+// The compressed data can only be decompressed by a [Reader] using
+// the same dictionary, as returned by [NewReaderDict].
+//
+// The dictionary's entropy tables are not used when compressing;
+// only its content and repeat offsets are.
+// A nil or empty dictionary is the same as no dictionary.
+func NewWriterDict(w io.Writer, level int, dict []byte) (*Writer, error) {
+	if level < DefaultCompression || level > BestCompression {
+		return nil, fmt.Errorf("zstd: invalid compression level: %d", level)
+	}
+	if level == DefaultCompression {
+		level = defaultLevel
+	}
+	z := &Writer{level: level}
+	if len(dict) > 0 {
+		d, err := parseDictionary(dict)
+		if err != nil {
+			return nil, err
+		}
+		z.dict = d
+	}
+	z.enc.init(level)
+	z.Reset(w)
+	return z, nil
+}
+
+// Reset discards the Writer z's state and makes it equivalent to the
+// result of its original state from [NewWriter], [NewWriterLevel]
+// or [NewWriterDict], but writing to w instead.
+// This permits reusing a Writer rather than allocating a new one.
+// The compression level and dictionary are preserved.
+func (z *Writer) Reset(w io.Writer) {
+	z.w = w
+	z.hist = z.hist[:0]
+	z.pending = 0
+	z.wroteHeader = false
+	z.closed = false
+	z.err = nil
+	z.checksum.reset()
+	z.enc.reset()
+	if z.dict != nil {
+		z.hist = append(z.hist, z.dict.content...)
+		z.pending = len(z.hist)
+		z.enc.reps = z.dict.repeats
+		if z.level != NoCompression {
+			z.enc.loadHistory(z.hist)
+		}
+	}
+}
+
+// Write writes a compressed form of p to the underlying [io.Writer].
+// The compressed bytes are not necessarily flushed until
+// the [Writer] is closed or explicitly flushed.
+func (z *Writer) Write(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	if z.closed {
+		return 0, errWriterClosed
+	}
+	n := len(p)
+	for len(p) > 0 {
+		if len(z.hist)-z.pending == maxBlockSize {
+			if err := z.writeBlock(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		chunk := min(len(p), maxBlockSize-(len(z.hist)-z.pending))
+		z.makeRoom(chunk)
+		z.hist = append(z.hist, p[:chunk]...)
+		p = p[chunk:]
+	}
+	return n, nil
+}
+
+// makeRoom discards old history, if necessary, so that n more bytes
+// can be added to z.hist without exceeding the history limit.
+func (z *Writer) makeRoom(n int) {
+	window := z.enc.windowSize()
+	if len(z.hist)+n <= 2*window+maxBlockSize {
+		return
+	}
+	// Keep window bytes of history before the pending data,
+	// rounding so that positions in the match finder's chain table
+	// stay at the same index.
+	shift := (z.pending - window) &^ (z.enc.shiftAlign() - 1)
+	if shift <= 0 {
+		return
+	}
+	copy(z.hist, z.hist[shift:])
+	z.hist = z.hist[:len(z.hist)-shift]
+	z.pending -= shift
+	z.enc.shift(shift)
+}
+
+// Flush compresses any pending data and writes it to the underlying
+// writer. It is useful mainly in compressed network protocols,
+// to ensure that a remote reader has enough data to reconstruct
+// everything written so far.
+// Flush does not return until the data has been written.
+// If the underlying writer returns an error, Flush returns that error.
+func (z *Writer) Flush() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.closed {
+		return nil
+	}
+	if z.pending == len(z.hist) && z.wroteHeader {
+		return nil
+	}
+	return z.writeBlock(false)
+}
+
+// Close closes the [Writer] by compressing and writing any pending
+// data and the end of the frame to the underlying writer.
+// It does not close the underlying writer.
+func (z *Writer) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.closed {
+		return nil
+	}
+	z.closed = true
+	if err := z.writeBlock(true); err != nil {
+		return err
+	}
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(z.checksum.digest()))
+	_, z.err = z.w.Write(buf[:])
+	return z.err
+}
+
+// writeBlock compresses the pending data as a single block and
+// writes it out, preceded by the frame header if that has not been
+// written yet.
+func (z *Writer) writeBlock(last bool) error {
+	src := z.hist[z.pending:]
+	out := z.out[:0]
+	if !z.wroteHeader {
+		// If this is the only block in the frame, we know the
+		// content size and can use a single segment.
+		out = z.appendFrameHeader(out, last, uint64(len(src)))
+		z.wroteHeader = true
+	}
+
+	z.checksum.update(src)
+
+	hdr := len(out)
+	out = append(out, 0, 0, 0)
+	var typ blockType
+	if z.level == NoCompression {
+		out, typ = append(out, src...), blockRaw
+	} else {
+		out, typ = z.enc.compressBlock(out, z.hist, z.pending)
+	}
+	size := len(out) - hdr - 3
+	if typ == blockRLE {
+		size = len(src)
+	}
+	v := uint32(size)<<3 | uint32(typ)<<1
+	if last {
+		v |= 1
+	}
+	out[hdr] = byte(v)
+	out[hdr+1] = byte(v >> 8)
+	out[hdr+2] = byte(v >> 16)
+
+	z.pending = len(z.hist)
+	z.out = out
+	_, z.err = z.w.Write(out)
+	return z.err
+}
+
+// appendFrameHeader appends the frame header to out. RFC 3.1.1.1.
+// If single is true, size is the size of the complete frame content.
+func (z *Writer) appendFrameHeader(out []byte, single bool, size uint64) []byte {
+	out = binary.LittleEndian.AppendUint32(out, frameMagic)
+
+	// Content_Checksum_Flag is always set.
+	descriptor := byte(1 << 2)
+
+	var dictID uint32
+	if z.dict != nil {
+		dictID = z.dict.id
+	}
+	var dictIDSize int
+	switch {
+	case dictID == 0:
+	case dictID < 1<<8:
+		descriptor |= 1
+		dictIDSize = 1
+	case dictID < 1<<16:
+		descriptor |= 2
+		dictIDSize = 2
+	default:
+		descriptor |= 3
+		dictIDSize = 4
+	}
+
+	var fcsSize int
+	if single {
+		descriptor |= 1 << 5
+		switch {
+		case size < 256:
+			fcsSize = 1
+		case size < 256+1<<16:
+			descriptor |= 1 << 6
+			fcsSize = 2
+		case size < 1<<32:
+			descriptor |= 2 << 6
+			fcsSize = 4
+		default:
+			descriptor |= 3 << 6
+			fcsSize = 8
+		}
+	}
+	out = append(out, descriptor)
+
+	if !single {
+		// Window_Descriptor with a zero mantissa.
+		out = append(out, byte(z.enc.windowLog()-10)<<3)
+	}
+
+	switch dictIDSize {
+	case 1:
+		out = append(out, byte(dictID))
+	case 2:
+		out = binary.LittleEndian.AppendUint16(out, uint16(dictID))
+	case 4:
+		out = binary.LittleEndian.AppendUint32(out, dictID)
+	}
+
+	switch fcsSize {
+	case 1:
+		out = append(out, byte(size))
+	case 2:
+		out = binary.LittleEndian.AppendUint16(out, uint16(size-256))
+	case 4:
+		out = binary.LittleEndian.AppendUint32(out, uint32(size))
+	case 8:
+		out = binary.LittleEndian.AppendUint64(out, size)
+	}
+	return out
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// writerInputs returns a set of inputs to compress.
+func writerInputs(t testing.TB) map[string][]byte {
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := make([]byte, 300<<10)
+	for i := range random {
+		random[i] = byte(rng.Uint32())
+	}
+	text := make([]byte, 0, 300<<10)
+	words := strings.Fields("the quick brown fox jumps over the lazy dog zstd compression is fun and useful")
+	for len(text) < cap(text)-16 {
+		text = append(text, words[rng.IntN(len(words))]...)
+		text = append(text, ' ')
+	}
+	skewed := make([]byte, 200<<10)
+	for i := range skewed {
+		// Mostly one byte, with rare other values, so that the
+		// Huffman code lengths have to be limited.
+		if rng.IntN(5000) == 0 {
+			skewed[i] = byte(rng.IntN(256))
+		} else if rng.IntN(2) == 0 {
+			skewed[i] = 'a'
+		} else {
+			skewed[i] = byte(rng.IntN(30))
+		}
+	}
+	gettysburg, err := os.ReadFile("../testdata/gettysburg.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string][]byte{
+		"empty":      nil,
+		"byte":       {'x'},
+		"short":      []byte("hello, world\n"),
+		"rle":        bytes.Repeat([]byte{'z'}, 200<<10),
+		"random":     random,
+		"text":       text,
+		"skewed":     skewed,
+		"gettysburg": bytes.Repeat(gettysburg, 100),
+		"bigdata":    bigData(t),
+	}
+}
+
+func compress(t testing.TB, data []byte, level int, dict []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriterDict(&buf, level, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for name, data := range writerInputs(t) {
+		for level := NoCompression; level <= BestCompression; level++ {
+			if testing.Short() && level > BestSpeed && level != defaultLevel && level != BestCompression {
+				continue
+			}
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed := compress(t, data, level, nil)
+				got, err := io.ReadAll(NewReader(bytes.NewReader(compressed)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, data) {
+					showDiffs(t, got, data)
+				}
+				if level > NoCompression && len(data) > 1000 && name != "random" && name != "skewed" && len(compressed) > len(data)/2 {
+					t.Errorf("compressed %d bytes to %d bytes", len(data), len(compressed))
+				}
+			})
+		}
+	}
+}
+
+func TestWriterExternal(t *testing.T) {
+	zstd := findZstd(t)
+	for name, data := range writerInputs(t) {
+		for _, level := range []int{NoCompression, BestSpeed, DefaultCompression, BestCompression} {
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed := compress(t, data, level, nil)
+				cmd := exec.Command(zstd, "-d")
+				cmd.Stdin = bytes.NewReader(compressed)
+				var stderr bytes.Buffer
+				cmd.Stderr = &stderr
+				got, err := cmd.Output()
+				if err != nil {
+					t.Fatalf("%v: %s", err, stderr.Bytes())
+				}
+				if !bytes.Equal(got, data) {
+					showDiffs(t, got, data)
+				}
+			})
+		}
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	data := bigData(t)
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	r := NewReader(&buf)
+	var got []byte
+	for len(data) > 0 {
+		n := min(len(data), 10000)
+		if _, err := w.Write(data[:n]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		// Everything written so far should now be readable.
+		chunk := make([]byte, n)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(chunk, data[:n]) {
+			t.Fatalf("flushed data mismatch")
+		}
+		got = append(got, chunk...)
+		data = data[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Read at end = %d, %v; want 0, EOF", n, err)
+	}
+}
+
+func TestWriterReset(t *testing.T) {
+	data := bigData(t)
+	var buf1, buf2 bytes.Buffer
+	w := NewWriter(&buf1)
+	w.Write(data[:1000])
+	w.Reset(&buf2)
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := compress(t, data, DefaultCompression, nil)
+	if !bytes.Equal(buf2.Bytes(), want) {
+		t.Errorf("output after Reset differs from new Writer")
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w := NewWriter(io.Discard)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func TestWriterLevel(t *testing.T) {
+	for _, level := range []int{-2, 10} {
+		if _, err := NewWriterLevel(io.Discard, level); err == nil {
+			t.Errorf("NewWriterLevel(%d) succeeded", level)
+		}
+	}
+}
+
+func TestWriterConcatenated(t *testing.T) {
+	a := compress(t, []byte("hello, "), DefaultCompression, nil)
+	b := compress(t, []byte("world"), BestSpeed, nil)
+	got, err := io.ReadAll(NewReader(bytes.NewReader(append(a, b...))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello, world" {
+		t.Errorf("got %q, want %q", got, "hello, world")
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	data := bigData(b)
+	for _, level := range []int{BestSpeed, DefaultCompression, BestCompression} {
+		b.Run(fmt.Sprint(level), func(b *testing.B) {
+			w, _ := NewWriterLevel(io.Discard, level)
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				w.Reset(io.Discard)
+				w.Write(data)
+				w.Close()
+			}
+		})
+	}
+}
+
+// dictSample returns data that compresses well with testdata/json.dict.
+func dictSample() []byte {
+	var b bytes.Buffer
+	names := []string{"alpha", "gamma", "kappa", "lambda"}
+	for i := range 20 {
+		fmt.Fprintf(&b, `{"id": %d, "name": %q, "tags": [%q, %q], "active": %t}`+"\n",
+			i, names[i%4], names[(i+1)%4], names[(i+3)%4], i%3 == 0)
+	}
+	return b.Bytes()
+}
+
+func TestWriterDict(t *testing.T) {
+	formatted, err := os.ReadFile("testdata/json.dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := dictSample()
+	tests := []struct {
+		name string
+		dict []byte
+	}{
+		{"formatted", formatted},
+		{"raw", data[:len(data)/2]},
+	}
+	for _, test := range tests {
+		for _, level := range []int{NoCompression, BestSpeed, DefaultCompression, BestCompression} {
+			t.Run(fmt.Sprintf("%s/%d", test.name, level), func(t *testing.T) {
+				compressed := compress(t, data, level, test.dict)
+				plain := len(compress(t, data, level, nil))
+				if level != NoCompression && len(compressed) >= plain {
+					t.Errorf("compressed to %d bytes with dictionary, %d without", len(compressed), plain)
+				}
+				r, err := NewReaderDict(bytes.NewReader(compressed), test.dict)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, data) {
+					showDiffs(t, got, data)
+				}
+			})
+		}
+	}
+
+	// A frame that names a dictionary can't be read without it.
+	compressed := compress(t, data, DefaultCompression, formatted)
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(compressed))); err == nil {
+		t.Error("reading without dictionary succeeded")
+	}
+}
+
+func TestWriterDictExternal(t *testing.T) {
+	zstd := findZstd(t)
+	data := dictSample()
+	dictFile := "testdata/json.dict"
+	dict, err := os.ReadFile(dictFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Our output, decompressed by zstd.
+	cmd := exec.Command(zstd, "-d", "-D", dictFile)
+	cmd.Stdin = bytes.NewReader(compress(t, data, DefaultCompression, dict))
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("zstd -d failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		showDiffs(t, got, data)
+	}
+
+	// zstd output, decompressed by us.
+	cmd = exec.Command(zstd, "-z", "-D", dictFile)
+	cmd.Stdin = bytes.NewReader(data)
+	compressed, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("zstd -z failed: %v", err)
+	}
+	r, err := NewReaderDict(bytes.NewReader(compressed), dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		showDiffs(t, got, data)
+	}
+}
@@ -2,8 +2,13 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package zstd provides a decompressor for zstd streams,
-// described in RFC 8878. It does not support dictionaries.
+// Package zstd implements reading and writing of zstd compressed data,
+// as described in RFC 8878.
+//
+// A [Reader] decompresses a stream of one or more zstd frames.
+// A [Writer] compresses data into a single zstd frame.
+// Both support dictionaries, which can improve the compression
+// of small inputs that resemble the dictionary.
 package zstd
 
 import (
@@ -82,6 +87,9 @@ type Reader struct {
 
 	// For checksum computation.
 	checksum xxhash64
+
+	// The dictionary to use, if any.
+	dict *dictionary
 }
 
 // NewReader creates a new Reader that decompresses data from the given reader.
@@ -91,8 +99,25 @@ func NewReader(input io.Reader) *Reader {
 	return r
 }
 
+// NewReaderDict is like [NewReader] but uses a dictionary.
+// The dictionary may be either a zstd dictionary, as described in RFC 8878
+// section 5, or raw content. It is used for frames that have the same
+// dictionary ID, or that have no dictionary ID.
+// Frames that require a different dictionary are reported as errors.
+func NewReaderDict(input io.Reader, dict []byte) (*Reader, error) {
+	d, err := parseDictionary(dict)
+	if err != nil {
+		return nil, err
+	}
+	r := new(Reader)
+	r.dict = d
+	r.Reset(input)
+	return r, nil
+}
+
 // Reset discards the current state and starts reading a new stream from r.
 // This permits reusing a Reader rather than allocating a new one.
+// Any dictionary passed to [NewReaderDict] is retained.
 func (r *Reader) Reset(input io.Reader) {
 	r.r = input
 
@@ -131,6 +156,12 @@ func (r *Reader) Read(p []byte) (int, error) {
 	return n, nil
 }
 
+// Close closes the Reader. It does not close the underlying reader.
+// It returns nil; it exists so that a Reader implements [io.ReadCloser].
+func (r *Reader) Close() error {
+	return nil
+}
+
 // ReadByte implements [io.ByteReader].
 func (r *Reader) ReadByte() (byte, error) {
 	if err := r.refillIfNeeded(); err != nil {
@@ -255,14 +286,12 @@ retry:
 	}
 
 	// Dictionary_ID. RFC 3.1.1.1.3.
-	if dictionaryIdSize != 0 {
-		dictionaryId := r.scratch[windowDescriptorSize : windowDescriptorSize+dictionaryIdSize]
-		// Allow only zero Dictionary ID.
-		for _, b := range dictionaryId {
-			if b != 0 {
-				return r.makeError(relativeOffset, "dictionaries are not supported")
-			}
-		}
+	var dictionaryId uint32
+	for i, b := range r.scratch[windowDescriptorSize : windowDescriptorSize+dictionaryIdSize] {
+		dictionaryId |= uint32(b) << (8 * i)
+	}
+	if dictionaryId != 0 && (r.dict == nil || r.dict.id != dictionaryId) {
+		return r.makeError(relativeOffset, fmt.Sprintf("unknown dictionary ID %d", dictionaryId))
 	}
 
 	// Frame_Content_Size. RFC 3.1.1.1.4.
@@ -308,14 +337,46 @@ retry:
 	r.repeatedOffset2 = 4
 	r.repeatedOffset3 = 8
 	r.huffmanTableBits = 0
-	r.window.reset(int(windowSize))
 	r.seqTables[0] = nil
 	r.seqTables[1] = nil
 	r.seqTables[2] = nil
+	if r.dict != nil {
+		r.useDictionary(int(windowSize))
+	} else {
+		r.window.reset(int(windowSize))
+	}
 
 	return nil
 }
 
+// useDictionary sets up the initial state for a frame
+// using the dictionary. RFC 5.
+func (r *Reader) useDictionary(windowSize int) {
+	d := r.dict
+
+	r.repeatedOffset1 = d.repeats[0]
+	r.repeatedOffset2 = d.repeats[1]
+	r.repeatedOffset3 = d.repeats[2]
+
+	if d.huffmanTableBits != 0 {
+		if len(r.huffmanTable) < 1<<maxHuffmanBits {
+			r.huffmanTable = make([]uint16, 1<<maxHuffmanBits)
+		}
+		copy(r.huffmanTable, d.huffmanTable)
+		r.huffmanTableBits = d.huffmanTableBits
+	}
+
+	// The sequence tables are never modified in place,
+	// so we can share them.
+	r.seqTables = d.seqTables
+	r.seqTableBits = d.seqTableBits
+
+	// The dictionary content precedes the frame content,
+	// and may be referred to by matches.
+	r.window.reset(windowSize + len(d.content))
+	r.window.save(d.content)
+}
+
 // skipFrame skips a skippable frame. RFC 3.1.2.
 func (r *Reader) skipFrame() error {
 	relativeOffset := 0
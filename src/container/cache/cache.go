@@ -0,0 +1,407 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cache implements a bounded in-memory cache with least
+// recently used (LRU) eviction.
+//
+// A [Cache] holds entries up to a maximum number or a maximum total
+// cost. When adding an entry exceeds a bound, the least recently used
+// entries are evicted. Entries may also expire after a time to live.
+// [Cache.GetOrLoad] loads missing entries, with a single load in
+// progress for each key at a time.
+package cache
+
+import (
+	"errors"
+	"iter"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Options configures a [Cache].
+type Options[K comparable, V any] struct {
+	// MaxEntries is the maximum number of entries in the cache.
+	// If zero or negative, the number of entries is not bounded.
+	MaxEntries int
+
+	// MaxCost is the maximum total cost of the entries in the cache.
+	// If zero or negative, the total cost is not bounded.
+	// An entry whose cost alone exceeds MaxCost is not added: it is
+	// passed to OnEvict at once, and any previous entry for its key is
+	// removed.
+	MaxCost int64
+
+	// Cost returns the cost of an entry, such as its size in bytes.
+	// If Cost is nil, each entry has a cost of 1.
+	Cost func(key K, value V) int64
+
+	// TTL is the time to live of entries added by Set and GetOrLoad.
+	// An entry expires when its time to live has elapsed after it was
+	// added. If TTL is zero or negative, entries don't expire.
+	TTL time.Duration
+
+	// OnEvict, if non-nil, is called with each entry removed from the
+	// cache, after the change that removed it is complete. It must not
+	// block for long, since it is called by the goroutine that changed
+	// the cache.
+	OnEvict func(key K, value V, reason EvictReason)
+}
+
+// An EvictReason tells why an entry was removed from a [Cache].
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota // the cache was over its maximum size
+	EvictExpired                     // the entry's time to live elapsed
+	EvictDeleted                     // the entry was deleted or the cache cleared
+	EvictReplaced                    // Set replaced the entry with a new value
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "EvictCapacity"
+	case EvictExpired:
+		return "EvictExpired"
+	case EvictDeleted:
+		return "EvictDeleted"
+	case EvictReplaced:
+		return "EvictReplaced"
+	}
+	return "EvictReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// Stats are statistics about the use of a [Cache].
+type Stats struct {
+	Hits        uint64 // lookups that found an entry
+	Misses      uint64 // lookups that found no entry, or an expired one
+	Evictions   uint64 // entries removed to stay within the size bounds
+	Expirations uint64 // entries removed because they expired
+}
+
+// A Cache is a map from keys to values with a bounded size and least
+// recently used eviction. It is safe for concurrent use by multiple
+// goroutines.
+type Cache[K comparable, V any] struct {
+	opts Options[K, V]
+	now  func() time.Time // for testing
+
+	mu      sync.Mutex
+	entries map[K]*entry[K, V]
+	root    entry[K, V] // sentinel of the LRU list; root.next is the most recent
+	cost    int64
+	stats   Stats
+	loading map[K]*call[V]
+	evicted []evicted[K, V] // removed entries not yet passed to OnEvict
+}
+
+type entry[K comparable, V any] struct {
+	prev, next *entry[K, V]
+	key        K
+	value      V
+	cost       int64
+	expires    time.Time // zero if the entry doesn't expire
+}
+
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// A call is a GetOrLoad call that is loading a value.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	stale bool // the key was changed while loading; don't store value
+}
+
+// New returns an empty cache configured by opts.
+// A nil opts is equivalent to a zero Options.
+func New[K comparable, V any](opts *Options[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		now:     time.Now,
+		entries: make(map[K]*entry[K, V]),
+		loading: make(map[K]*call[V]),
+	}
+	if opts != nil {
+		c.opts = *opts
+	}
+	c.root.next = &c.root
+	c.root.prev = &c.root
+	return c
+}
+
+// Get returns the value for key, and whether it was found.
+// Finding an entry makes it the most recently used.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	e := c.lookup(key)
+	if e != nil {
+		value, ok = e.value, true
+	}
+	c.unlock()
+	return value, ok
+}
+
+// Peek returns the value for key, and whether it was found, without
+// making the entry the most recently used or updating statistics.
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.entries[key]
+	if e == nil || c.expired(e) {
+		return value, false
+	}
+	return e.value, true
+}
+
+// Set sets the value for key, with the time to live in the cache's
+// options, and makes the entry the most recently used.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL is like [Cache.Set], but the entry expires after ttl
+// instead of the time to live in the cache's options.
+// If ttl is zero or negative, the entry doesn't expire.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	if cl := c.loading[key]; cl != nil {
+		cl.stale = true
+	}
+	c.set(key, value, ttl)
+	c.unlock()
+}
+
+// Delete removes the entry for key, if any,
+// and reports whether there was one.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	if cl := c.loading[key]; cl != nil {
+		cl.stale = true
+	}
+	e := c.entries[key]
+	if e != nil {
+		c.remove(e, EvictDeleted)
+	}
+	c.unlock()
+	return e != nil
+}
+
+// Clear removes all entries from the cache.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	for _, cl := range c.loading {
+		cl.stale = true
+	}
+	for c.root.prev != &c.root {
+		c.remove(c.root.prev, EvictDeleted)
+	}
+	c.unlock()
+}
+
+// DeleteExpired removes all expired entries from the cache.
+// Expired entries are otherwise removed only when they are looked up
+// or evicted, and count against the size of the cache until then.
+func (c *Cache[K, V]) DeleteExpired() {
+	c.mu.Lock()
+	for _, e := range c.entries {
+		if c.expired(e) {
+			c.stats.Expirations++
+			c.remove(e, EvictExpired)
+		}
+	}
+	c.unlock()
+}
+
+// Len returns the number of entries in the cache,
+// including expired entries that haven't been removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Cost returns the total cost of the entries in the cache.
+func (c *Cache[K, V]) Cost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cost
+}
+
+// Stats returns statistics about the use of the cache.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// All returns an iterator over the unexpired entries in the cache,
+// from the most to the least recently used. The entries are those in
+// the cache when All is called; the iteration doesn't observe later
+// changes, nor does it change the order of the entries.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	c.mu.Lock()
+	var entries []evicted[K, V]
+	for e := c.root.next; e != &c.root; e = e.next {
+		if !c.expired(e) {
+			entries = append(entries, evicted[K, V]{key: e.key, value: e.value})
+		}
+	}
+	c.mu.Unlock()
+	return func(yield func(K, V) bool) {
+		for _, e := range entries {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// ErrLoadPanicked is returned by [Cache.GetOrLoad] to callers waiting
+// for a load function that panicked.
+var ErrLoadPanicked = errors.New("cache: load function panicked")
+
+// GetOrLoad returns the value for key. If the cache has no entry for
+// key, GetOrLoad calls load to get the value and adds it to the cache,
+// unless load returns an error.
+//
+// Only one load for a key is in progress at a time. Callers asking
+// for a key while it is being loaded wait for the load to complete,
+// and receive its result. If the key is set or deleted while it is
+// being loaded, the loaded value is returned but not added.
+func (c *Cache[K, V]) GetOrLoad(key K, load func(key K) (V, error)) (V, error) {
+	c.mu.Lock()
+	if e := c.lookup(key); e != nil {
+		v := e.value
+		c.unlock()
+		return v, nil
+	}
+	if cl := c.loading[key]; cl != nil {
+		c.unlock()
+		<-cl.done
+		return cl.value, cl.err
+	}
+	cl := &call[V]{done: make(chan struct{})}
+	c.loading[key] = cl
+	c.unlock()
+
+	returned := false
+	defer func() {
+		if !returned {
+			cl.err = ErrLoadPanicked
+		}
+		c.mu.Lock()
+		delete(c.loading, key)
+		if cl.err == nil && !cl.stale {
+			c.set(key, cl.value, c.opts.TTL)
+		}
+		c.unlock()
+		close(cl.done)
+	}()
+	cl.value, cl.err = load(key)
+	returned = true
+	return cl.value, cl.err
+}
+
+// unlock unlocks c.mu and calls OnEvict for the removed entries.
+func (c *Cache[K, V]) unlock() {
+	ev := c.evicted
+	c.evicted = nil
+	c.mu.Unlock()
+	if c.opts.OnEvict != nil {
+		for _, e := range ev {
+			c.opts.OnEvict(e.key, e.value, e.reason)
+		}
+	}
+}
+
+// lookup returns the unexpired entry for key, or nil, and counts a hit
+// or miss. It makes the entry the most recently used.
+func (c *Cache[K, V]) lookup(key K) *entry[K, V] {
+	e := c.entries[key]
+	if e != nil && c.expired(e) {
+		c.stats.Expirations++
+		c.remove(e, EvictExpired)
+		e = nil
+	}
+	if e == nil {
+		c.stats.Misses++
+		return nil
+	}
+	c.stats.Hits++
+	c.unlink(e)
+	c.pushFront(e)
+	return e
+}
+
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration) {
+	if e := c.entries[key]; e != nil {
+		c.remove(e, EvictReplaced)
+	}
+	e := &entry[K, V]{key: key, value: value, cost: 1}
+	if c.opts.Cost != nil {
+		e.cost = c.opts.Cost(key, value)
+	}
+	if c.opts.MaxCost > 0 && e.cost > c.opts.MaxCost {
+		// Adding the entry would evict every other entry,
+		// and then the entry itself.
+		c.stats.Evictions++
+		if c.opts.OnEvict != nil {
+			c.evicted = append(c.evicted, evicted[K, V]{key: key, value: value, reason: EvictCapacity})
+		}
+		return
+	}
+	if ttl > 0 {
+		e.expires = c.now().Add(ttl)
+	}
+	c.entries[key] = e
+	c.cost += e.cost
+	c.pushFront(e)
+	for c.overCapacity() {
+		oldest := c.root.prev
+		if c.expired(oldest) {
+			c.stats.Expirations++
+			c.remove(oldest, EvictExpired)
+		} else {
+			c.stats.Evictions++
+			c.remove(oldest, EvictCapacity)
+		}
+	}
+}
+
+func (c *Cache[K, V]) overCapacity() bool {
+	return c.opts.MaxEntries > 0 && len(c.entries) > c.opts.MaxEntries ||
+		c.opts.MaxCost > 0 && c.cost > c.opts.MaxCost
+}
+
+func (c *Cache[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expires.IsZero() && !c.now().Before(e.expires)
+}
+
+// remove removes e from the cache, recording it for OnEvict.
+func (c *Cache[K, V]) remove(e *entry[K, V], reason EvictReason) {
+	c.unlink(e)
+	delete(c.entries, e.key)
+	c.cost -= e.cost
+	if c.opts.OnEvict != nil {
+		c.evicted = append(c.evicted, evicted[K, V]{key: e.key, value: e.value, reason: reason})
+	}
+}
+
+func (c *Cache[K, V]) pushFront(e *entry[K, V]) {
+	e.prev = &c.root
+	e.next = c.root.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+func (c *Cache[K, V]) unlink(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+}
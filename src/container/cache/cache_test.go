@@ -0,0 +1,342 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type eviction struct {
+	key    string
+	value  int
+	reason EvictReason
+}
+
+func newTestCache(opts *Options[string, int]) (*Cache[string, int], *[]eviction) {
+	var evs []eviction
+	opts.OnEvict = func(key string, value int, reason EvictReason) {
+		evs = append(evs, eviction{key, value, reason})
+	}
+	return New(opts), &evs
+}
+
+func keys(c *Cache[string, int]) []string {
+	var ks []string
+	for k := range c.All() {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+func TestMaxEntries(t *testing.T) {
+	c, evs := newTestCache(&Options[string, int]{MaxEntries: 3})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal(`Get("a") not found`)
+	}
+	c.Set("d", 4)
+	if got, want := keys(c), []string{"d", "a", "c"}; !slices.Equal(got, want) {
+		t.Errorf("keys = %q, want %q", got, want)
+	}
+	if want := []eviction{{"b", 2, EvictCapacity}}; !slices.Equal(*evs, want) {
+		t.Errorf("evictions = %v, want %v", *evs, want)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error(`Get("b") found evicted entry`)
+	}
+	want := Stats{Hits: 1, Misses: 1, Evictions: 1}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMaxCost(t *testing.T) {
+	c, evs := newTestCache(&Options[string, int]{
+		MaxCost: 10,
+		Cost:    func(_ string, v int) int64 { return int64(v) },
+	})
+	c.Set("a", 4)
+	c.Set("b", 4)
+	c.Set("c", 4)
+	if got, want := c.Cost(), int64(8); got != want {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+	if got, want := keys(c), []string{"c", "b"}; !slices.Equal(got, want) {
+		t.Errorf("keys = %q, want %q", got, want)
+	}
+	// An entry larger than MaxCost is evicted at once, without evicting
+	// other entries. The entry it replaces is removed.
+	c.Set("d", 11)
+	c.Set("c", 11)
+	if got, want := keys(c), []string{"b"}; !slices.Equal(got, want) {
+		t.Errorf("keys = %q, want %q", got, want)
+	}
+	if got, want := c.Cost(), int64(4); got != want {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+	want := []eviction{{"a", 4, EvictCapacity}, {"d", 11, EvictCapacity}, {"c", 4, EvictReplaced}, {"c", 11, EvictCapacity}}
+	if !slices.Equal(*evs, want) {
+		t.Errorf("evictions = %v, want %v", *evs, want)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Unix(1e9, 0)
+	c, evs := newTestCache(&Options[string, int]{TTL: time.Minute})
+	c.now = func() time.Time { return now }
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Hour)
+	c.SetWithTTL("c", 3, 0)
+
+	now = now.Add(time.Minute - 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Error(`Get("a") not found before TTL`)
+	}
+	now = now.Add(1)
+	if _, ok := c.Peek("a"); ok {
+		t.Error(`Peek("a") found expired entry`)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error(`Get("a") found expired entry`)
+	}
+	now = now.Add(time.Hour)
+	if got, want := keys(c), []string{"c"}; !slices.Equal(got, want) {
+		t.Errorf("keys = %q, want %q", got, want)
+	}
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len() = %v, want 2 before DeleteExpired", got)
+	}
+	c.DeleteExpired()
+	if got := c.Len(); got != 1 {
+		t.Errorf("Len() = %v, want 1 after DeleteExpired", got)
+	}
+	want := []eviction{{"a", 1, EvictExpired}, {"b", 2, EvictExpired}}
+	if !slices.Equal(*evs, want) {
+		t.Errorf("evictions = %v, want %v", *evs, want)
+	}
+	if got, want := c.Stats(), (Stats{Hits: 1, Misses: 1, Expirations: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEvictExpiredFirst(t *testing.T) {
+	now := time.Unix(1e9, 0)
+	c, evs := newTestCache(&Options[string, int]{MaxEntries: 2})
+	c.now = func() time.Time { return now }
+	c.SetWithTTL("a", 1, time.Second)
+	c.Set("b", 2)
+	now = now.Add(time.Second)
+	c.Set("c", 3)
+	if want := []eviction{{"a", 1, EvictExpired}}; !slices.Equal(*evs, want) {
+		t.Errorf("evictions = %v, want %v", *evs, want)
+	}
+}
+
+func TestDeleteReplaceClear(t *testing.T) {
+	c, evs := newTestCache(&Options[string, int]{})
+	c.Set("a", 1)
+	c.Set("a", 2)
+	c.Set("b", 3)
+	if !c.Delete("b") {
+		t.Error(`Delete("b") = false, want true`)
+	}
+	if c.Delete("b") {
+		t.Error(`second Delete("b") = true, want false`)
+	}
+	c.Set("c", 4)
+	c.Clear()
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() = %v after Clear, want 0", got)
+	}
+	want := []eviction{{"a", 1, EvictReplaced}, {"b", 3, EvictDeleted}, {"a", 2, EvictDeleted}, {"c", 4, EvictDeleted}}
+	if !slices.Equal(*evs, want) {
+		t.Errorf("evictions = %v, want %v", *evs, want)
+	}
+}
+
+func TestEvictReasonString(t *testing.T) {
+	for r, want := range map[EvictReason]string{
+		EvictCapacity: "EvictCapacity",
+		EvictReplaced: "EvictReplaced",
+		42:            "EvictReason(42)",
+	} {
+		if got := r.String(); got != want {
+			t.Errorf("EvictReason(%d).String() = %q, want %q", int(r), got, want)
+		}
+	}
+}
+
+func TestOnEvictUnlocked(t *testing.T) {
+	var c *Cache[string, int]
+	var lens []int
+	c = New(&Options[string, int]{
+		MaxEntries: 1,
+		OnEvict: func(key string, value int, reason EvictReason) {
+			// Must not deadlock.
+			lens = append(lens, c.Len())
+		},
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Delete("b")
+	if want := []int{1, 0}; !slices.Equal(lens, want) {
+		t.Errorf("Len() in OnEvict = %v, want %v", lens, want)
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	c := New[string, int](nil)
+	var loads int
+	load := func(key string) (int, error) {
+		loads++
+		if key == "bad" {
+			return 0, errors.New("bad key")
+		}
+		return len(key), nil
+	}
+	for range 2 {
+		if v, err := c.GetOrLoad("abc", load); v != 3 || err != nil {
+			t.Errorf(`GetOrLoad("abc") = %v, %v; want 3, nil`, v, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loads = %v, want 1", loads)
+	}
+	// Errors aren't cached.
+	for range 2 {
+		if _, err := c.GetOrLoad("bad", load); err == nil {
+			t.Error(`GetOrLoad("bad") succeeded`)
+		}
+	}
+	if loads != 3 {
+		t.Errorf("loads = %v, want 3", loads)
+	}
+	if got, want := c.Stats(), (Stats{Hits: 1, Misses: 3}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetOrLoadConcurrent(t *testing.T) {
+	c := New[string, int](nil)
+	release := make(chan struct{})
+	var (
+		mu    sync.Mutex
+		loads int
+	)
+	load := func(key string) (int, error) {
+		mu.Lock()
+		loads++
+		mu.Unlock()
+		<-release
+		return 42, nil
+	}
+	const n = 10
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() {
+			if v, err := c.GetOrLoad("k", load); v != 42 || err != nil {
+				t.Errorf(`GetOrLoad("k") = %v, %v; want 42, nil`, v, err)
+			}
+		})
+	}
+	// Wait for all callers to miss before releasing the load.
+	for c.Stats().Misses < n {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("loads = %v, want 1", loads)
+	}
+}
+
+func TestGetOrLoadStale(t *testing.T) {
+	c := New[string, int](nil)
+	v, err := c.GetOrLoad("k", func(key string) (int, error) {
+		c.Delete(key)
+		return 1, nil
+	})
+	if v != 1 || err != nil {
+		t.Errorf(`GetOrLoad("k") = %v, %v; want 1, nil`, v, err)
+	}
+	if _, ok := c.Get("k"); ok {
+		t.Error("value loaded while key was deleted was added to cache")
+	}
+
+	c.GetOrLoad("k", func(key string) (int, error) {
+		c.Set(key, 2)
+		return 1, nil
+	})
+	if v, _ := c.Get("k"); v != 2 {
+		t.Errorf(`Get("k") = %v, want 2 as set while loading`, v)
+	}
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	c := New[string, int](nil)
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		<-started
+		_, err := c.GetOrLoad("k", func(string) (int, error) {
+			return 0, fmt.Errorf("second load")
+		})
+		done <- err
+	}()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("GetOrLoad didn't panic")
+			}
+		}()
+		c.GetOrLoad("k", func(string) (int, error) {
+			close(started)
+			for c.Stats().Misses < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			panic("load")
+		})
+	}()
+	if err := <-done; err != ErrLoadPanicked {
+		t.Errorf("waiting GetOrLoad error = %v, want ErrLoadPanicked", err)
+	}
+	// The key can be loaded again.
+	if v, err := c.GetOrLoad("k", func(string) (int, error) { return 1, nil }); v != 1 || err != nil {
+		t.Errorf(`GetOrLoad("k") = %v, %v; want 1, nil`, v, err)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	c := New(&Options[int, int]{MaxEntries: 50, TTL: time.Hour})
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				k := (g*31 + i) % 100
+				switch i % 4 {
+				case 0:
+					c.Set(k, i)
+				case 1:
+					c.Get(k)
+				case 2:
+					c.GetOrLoad(k, func(k int) (int, error) { return k, nil })
+				case 3:
+					c.Delete(k)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if n := c.Len(); n > 50 {
+		t.Errorf("Len() = %v, want at most 50", n)
+	}
+}
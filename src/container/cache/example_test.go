@@ -0,0 +1,37 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache_test
+
+import (
+	"container/cache"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func Example() {
+	c := cache.New(&cache.Options[string, string]{
+		MaxEntries: 2,
+		TTL:        time.Hour,
+		OnEvict: func(key, value string, reason cache.EvictReason) {
+			fmt.Printf("evicted %s (%v)\n", key, reason)
+		},
+	})
+	c.Set("a", "apple")
+	c.Set("b", "banana")
+	c.Get("a")
+	c.Set("c", "cherry") // evicts b, the least recently used
+
+	v, err := c.GetOrLoad("d", func(key string) (string, error) {
+		return strings.Repeat(key, 3), nil
+	})
+	fmt.Println(v, err)
+	fmt.Printf("%+v\n", c.Stats())
+	// Output:
+	// evicted b (EvictCapacity)
+	// evicted a (EvictCapacity)
+	// ddd <nil>
+	// {Hits:1 Misses:1 Evictions:2 Expirations:0}
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher
+
+import (
+	"crypto/internal/fips140/aes"
+	"crypto/internal/fips140/aes/gcm"
+	"crypto/internal/fips140/alias"
+	"crypto/internal/fips140only"
+	"crypto/subtle"
+	"errors"
+	"internal/byteorder"
+)
+
+const (
+	gcmSIVNonceSize = 12
+	gcmSIVTagSize   = 16
+	gcmSIVMaxSize   = 1 << 36 // maximum plaintext and additional data size
+)
+
+// NewGCMSIV returns an AES-GCM-SIV [AEAD], as specified in RFC 8452, using the
+// given 16 or 32 byte key.
+//
+// AES-GCM-SIV is resistant to nonce misuse: if a nonce is reused, it only
+// reveals whether the same plaintext and additional data were encrypted
+// twice. Nonces should still be unique whenever possible.
+//
+// Unlike the other modes in this package, AES-GCM-SIV derives new AES keys
+// for each message, so it takes a key rather than a [Block].
+func NewGCMSIV(key []byte) (AEAD, error) {
+	if fips140only.Enforced() {
+		return nil, errors.New("crypto/cipher: use of AES-GCM-SIV is not allowed in FIPS 140-only mode")
+	}
+	if len(key) != 16 && len(key) != 32 {
+		return nil, errors.New("cipher: invalid key size for AES-GCM-SIV")
+	}
+	b, err := aes.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return &gcmSIV{block: b, keySize: len(key)}, nil
+}
+
+type gcmSIV struct {
+	block   *aes.Block // key-generating key
+	keySize int
+}
+
+func (g *gcmSIV) NonceSize() int {
+	return gcmSIVNonceSize
+}
+
+func (g *gcmSIV) Overhead() int {
+	return gcmSIVTagSize
+}
+
+// deriveKeys derives the per-nonce message-authentication and
+// message-encryption keys. See RFC 8452, Section 4.
+func (g *gcmSIV) deriveKeys(nonce []byte) (authKey [16]byte, encBlock *aes.Block) {
+	var in, out [aes.BlockSize]byte
+	var keys [16 + 32]byte
+	copy(in[4:], nonce)
+	for i := range 2 + g.keySize/8 {
+		byteorder.LEPutUint32(in[:4], uint32(i))
+		g.block.Encrypt(out[:], in[:])
+		copy(keys[8*i:], out[:8])
+	}
+	copy(authKey[:], keys[:16])
+	encBlock, err := aes.New(keys[16 : 16+g.keySize])
+	if err != nil {
+		panic("crypto/cipher: internal error: " + err.Error())
+	}
+	return authKey, encBlock
+}
+
+func (g *gcmSIV) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("crypto/cipher: incorrect nonce length given to GCM-SIV")
+	}
+	if uint64(len(plaintext)) > gcmSIVMaxSize || uint64(len(additionalData)) > gcmSIVMaxSize {
+		panic("crypto/cipher: message too large for GCM-SIV")
+	}
+
+	ret, out := sliceForAppend(dst, len(plaintext)+gcmSIVTagSize)
+	if alias.InexactOverlap(out, plaintext) {
+		panic("crypto/cipher: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("crypto/cipher: invalid buffer overlap of output and additional data")
+	}
+
+	authKey, encBlock := g.deriveKeys(nonce)
+	// The tag is computed over the plaintext, so it must be computed before
+	// encrypting in place.
+	tag := gcmSIVTag(&authKey, encBlock, nonce, plaintext, additionalData)
+	gcmSIVCounterCrypt(encBlock, out, plaintext, &tag)
+	copy(out[len(plaintext):], tag[:])
+
+	return ret
+}
+
+func (g *gcmSIV) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("crypto/cipher: incorrect nonce length given to GCM-SIV")
+	}
+	if len(ciphertext) < gcmSIVTagSize {
+		return nil, errOpen
+	}
+	if uint64(len(ciphertext)) > gcmSIVMaxSize+gcmSIVTagSize || uint64(len(additionalData)) > gcmSIVMaxSize {
+		return nil, errOpen
+	}
+
+	ret, out := sliceForAppend(dst, len(ciphertext)-gcmSIVTagSize)
+	if alias.InexactOverlap(out, ciphertext) {
+		panic("crypto/cipher: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("crypto/cipher: invalid buffer overlap of output and additional data")
+	}
+
+	var tag [gcmSIVTagSize]byte
+	copy(tag[:], ciphertext[len(ciphertext)-gcmSIVTagSize:])
+	ciphertext = ciphertext[:len(ciphertext)-gcmSIVTagSize]
+
+	authKey, encBlock := g.deriveKeys(nonce)
+	gcmSIVCounterCrypt(encBlock, out, ciphertext, &tag)
+	expectedTag := gcmSIVTag(&authKey, encBlock, nonce, out, additionalData)
+	if subtle.ConstantTimeCompare(expectedTag[:], tag[:]) != 1 {
+		// Don't release unauthenticated plaintext.
+		clear(out)
+		return nil, errOpen
+	}
+
+	return ret, nil
+}
+
+// gcmSIVTag computes the tag over the plaintext and additional data.
+// See RFC 8452, Section 4.
+func gcmSIVTag(authKey *[16]byte, encBlock *aes.Block, nonce, plaintext, additionalData []byte) [gcmSIVTagSize]byte {
+	var lenBlock [16]byte
+	byteorder.LEPutUint64(lenBlock[:8], uint64(len(additionalData))*8)
+	byteorder.LEPutUint64(lenBlock[8:], uint64(len(plaintext))*8)
+	p := newPolyval(authKey)
+	p.update(additionalData)
+	p.update(plaintext)
+	p.update(lenBlock[:])
+	s := p.sum()
+	subtle.XORBytes(s[:gcmSIVNonceSize], s[:gcmSIVNonceSize], nonce)
+	s[15] &= 0x7f
+	encBlock.Encrypt(s[:], s[:])
+	return s
+}
+
+// gcmSIVCounterCrypt encrypts src into out with the AES-GCM-SIV counter mode,
+// which starts from the tag with the most significant bit set.
+func gcmSIVCounterCrypt(b *aes.Block, out, src []byte, tag *[gcmSIVTagSize]byte) {
+	counter := *tag
+	counter[15] |= 0x80
+	aes.XORKeyStreamLE32(b, out, src, &counter)
+}
+
+// polyval computes POLYVAL, as specified in RFC 8452, Section 3, with the
+// GHASH implementation of GCM, following Appendix A:
+//
+//	POLYVAL(H, X_1, ..., X_n) = ByteReverse(GHASH(mulX_GHASH(ByteReverse(H)),
+//	    ByteReverse(X_1), ..., ByteReverse(X_n)))
+//
+// Blocks are byte-reversed into buf, and hashed when it's full or by sum.
+type polyval struct {
+	key [16]byte // mulX_GHASH(ByteReverse(H))
+	acc [16]byte // GHASH of the blocks hashed so far
+	buf [32 * 16]byte
+	n   int // bytes in buf
+}
+
+func newPolyval(h *[16]byte) *polyval {
+	p := &polyval{}
+	byteReverse(&p.key, h[:])
+	// mulX_GHASH shifts the key right by one bit, as a big-endian integer,
+	// and reduces it if the bit shifted out was set.
+	hi := byteorder.BEUint64(p.key[:8])
+	lo := byteorder.BEUint64(p.key[8:])
+	mask := -(lo & 1)
+	lo = lo>>1 | hi<<63
+	hi = hi>>1 ^ 0xe1<<56&mask
+	byteorder.BEPutUint64(p.key[:8], hi)
+	byteorder.BEPutUint64(p.key[8:], lo)
+	return p
+}
+
+// update adds the blocks of in to the POLYVAL input. If len(in) is not a
+// multiple of 16, the last block is padded with zeroes.
+func (p *polyval) update(in []byte) {
+	for len(in) > 0 {
+		if p.n == len(p.buf) {
+			p.flush()
+		}
+		n := min(len(in), 16)
+		block := (*[16]byte)(p.buf[p.n : p.n+16])
+		byteReverse(block, in[:n])
+		p.n += 16
+		in = in[n:]
+	}
+}
+
+// flush hashes the blocks in buf into acc. GHASH starts from a zero
+// accumulator, so the previous one is added to the first block.
+func (p *polyval) flush() {
+	if p.n == 0 {
+		return
+	}
+	subtle.XORBytes(p.buf[:16], p.buf[:16], p.acc[:])
+	copy(p.acc[:], gcm.GHASH(&p.key, p.buf[:p.n]))
+	p.n = 0
+}
+
+func (p *polyval) sum() [16]byte {
+	p.flush()
+	var s [16]byte
+	byteReverse(&s, p.acc[:])
+	return s
+}
+
+// byteReverse sets dst to the reverse of src, padded with zeroes to 16 bytes.
+func byteReverse(dst *[16]byte, src []byte) {
+	clear(dst[:16-len(src)])
+	for i, b := range src {
+		dst[15-i] = b
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher_test
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/internal/cryptotest"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+var gcmSIVTests = []struct {
+	key, nonce, plaintext, ad, result string
+}{
+	// RFC 8452, Appendix C.1.
+	{
+		"01000000000000000000000000000000",
+		"030000000000000000000000",
+		"",
+		"",
+		"dc20e2d83f25705bb49e439eca56de25",
+	},
+	{
+		"01000000000000000000000000000000",
+		"030000000000000000000000",
+		"0100000000000000",
+		"",
+		"b5d839330ac7b786578782fff6013b815b287c22493a364c",
+	},
+	{
+		"01000000000000000000000000000000",
+		"030000000000000000000000",
+		"010000000000000000000000",
+		"",
+		"7323ea61d05932260047d942a4978db357391a0bc4fdec8b0d106639",
+	},
+	{
+		"01000000000000000000000000000000",
+		"030000000000000000000000",
+		"0200000000000000",
+		"01",
+		"1e6daba35669f4273b0a1a2560969cdf790d99759abd1508",
+	},
+	// RFC 8452, Appendix C.2.
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"030000000000000000000000",
+		"",
+		"",
+		"07f5f4169bbf55a8400cd47ea6fd400f",
+	},
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"030000000000000000000000",
+		"0100000000000000",
+		"",
+		"c2ef328e5c71c83b843122130f7364b761e0b97427e3df28",
+	},
+	// Multiple blocks of plaintext and additional data.
+	{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"030000000000000000000000",
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60616263",
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f2021222324252627",
+		"1e3891ddd67407e4469165235840ae48b0784a3f0902343e78f9f2532ad698b22991b8e4e0ca9a23f4d3da2cb48731101ae717d6b6f76a22c0c34f48bebda596f1c04b59300fae0ff7e922e474bf6ab282934f001da0a9c34bcc9895de2520e3356d3f1b785e31eafeef27d98193f72c850a2593",
+	},
+}
+
+func TestGCMSIV(t *testing.T) {
+	for i, tt := range gcmSIVTests {
+		key, _ := hex.DecodeString(tt.key)
+		nonce, _ := hex.DecodeString(tt.nonce)
+		plaintext, _ := hex.DecodeString(tt.plaintext)
+		ad, _ := hex.DecodeString(tt.ad)
+		result, _ := hex.DecodeString(tt.result)
+
+		aead, err := cipher.NewGCMSIV(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := aead.Seal(nil, nonce, plaintext, ad); !bytes.Equal(got, result) {
+			t.Errorf("#%d: Seal = %x, want %x", i, got, result)
+		}
+		got, err := aead.Open(nil, nonce, result, ad)
+		if err != nil {
+			t.Errorf("#%d: Open: %v", i, err)
+		} else if !bytes.Equal(got, plaintext) {
+			t.Errorf("#%d: Open = %x, want %x", i, got, plaintext)
+		}
+
+		result[len(result)-1] ^= 1
+		if _, err := aead.Open(nil, nonce, result, ad); err == nil {
+			t.Errorf("#%d: Open succeeded with a modified tag", i)
+		}
+		result[len(result)-1] ^= 1
+		if len(plaintext) > 0 {
+			result[0] ^= 1
+			if _, err := aead.Open(nil, nonce, result, ad); err == nil {
+				t.Errorf("#%d: Open succeeded with a modified ciphertext", i)
+			}
+		}
+	}
+}
+
+func TestGCMSIVLong(t *testing.T) {
+	// Inputs longer than the POLYVAL buffer, checked against an independent
+	// POLYVAL implementation.
+	for _, tt := range []struct {
+		keySize int
+		sum     string
+	}{
+		{16, "2fd3065750d29d32cb048d8c8e3c8a1cc3fc897ce2206c9392de362443357199"},
+		{32, "97d17e04fa88be76d976f00d824f4c2ccca2f0c6c504a7bd0275ed634f80751b"},
+	} {
+		key := make([]byte, tt.keySize)
+		for i := range key {
+			key[i] = byte(i)
+		}
+		plaintext := make([]byte, 1031)
+		for i := range plaintext {
+			plaintext[i] = byte(i * 7)
+		}
+		ad := make([]byte, 557)
+		for i := range ad {
+			ad[i] = byte(i * 3)
+		}
+		aead, err := cipher.NewGCMSIV(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sealed := aead.Seal(nil, make([]byte, 12), plaintext, ad)
+		if sum := sha256.Sum256(sealed); hex.EncodeToString(sum[:]) != tt.sum {
+			t.Errorf("%d-byte key: SHA-256 of Seal output = %x, want %s", tt.keySize, sum, tt.sum)
+		}
+	}
+}
+
+func TestGCMSIVKeySize(t *testing.T) {
+	for _, size := range []int{0, 15, 24, 33} {
+		if _, err := cipher.NewGCMSIV(make([]byte, size)); err == nil {
+			t.Errorf("NewGCMSIV accepted a %d-byte key", size)
+		}
+	}
+}
+
+func TestGCMSIVAEAD(t *testing.T) {
+	for _, size := range []int{16, 32} {
+		key := make([]byte, size)
+		cryptotest.TestAEAD(t, func() (cipher.AEAD, error) { return cipher.NewGCMSIV(key) })
+	}
+}
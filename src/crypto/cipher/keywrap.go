@@ -0,0 +1,190 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Key wrap (KW) and key wrap with padding (KWP) modes.
+
+// See RFC 3394, RFC 5649, and NIST SP 800-38F.
+
+package cipher
+
+import (
+	"crypto/internal/fips140only"
+	"crypto/subtle"
+	"errors"
+	"internal/byteorder"
+)
+
+const (
+	kwBlockSize = 16
+	kwSemiblock = 8
+)
+
+var (
+	kwDefaultIV  = [kwSemiblock]byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+	kwpIVPrefix  = [4]byte{0xa6, 0x59, 0x59, 0xa6}
+	errKeyUnwrap = errors.New("cipher: key unwrap failed")
+)
+
+// WrapKey wraps key with the AES key wrap algorithm (KW), as specified in
+// RFC 3394 and NIST SP 800-38F. The block must have a 128-bit block size, and
+// is usually created by [crypto/aes.NewCipher] with a key-encryption key.
+//
+// The length of key must be a multiple of 8 bytes, and at least 16 bytes.
+// The wrapped key is 8 bytes longer than key. Use [WrapKeyWithPadding] to wrap
+// keys of arbitrary length.
+func WrapKey(b Block, key []byte) ([]byte, error) {
+	if err := checkKeyWrapBlock(b); err != nil {
+		return nil, err
+	}
+	if len(key)%kwSemiblock != 0 || len(key) < 2*kwSemiblock {
+		return nil, errors.New("cipher: invalid key length for key wrap")
+	}
+	out := make([]byte, kwSemiblock+len(key))
+	copy(out[kwSemiblock:], key)
+	a := kwWrap(b, kwDefaultIV, out[kwSemiblock:])
+	copy(out, a[:])
+	return out, nil
+}
+
+// UnwrapKey unwraps a key wrapped with [WrapKey], and checks its integrity.
+func UnwrapKey(b Block, wrapped []byte) ([]byte, error) {
+	if err := checkKeyWrapBlock(b); err != nil {
+		return nil, err
+	}
+	if len(wrapped)%kwSemiblock != 0 || len(wrapped) < 3*kwSemiblock {
+		return nil, errKeyUnwrap
+	}
+	key := make([]byte, len(wrapped)-kwSemiblock)
+	copy(key, wrapped[kwSemiblock:])
+	a := kwUnwrap(b, [kwSemiblock]byte(wrapped), key)
+	if subtle.ConstantTimeCompare(a[:], kwDefaultIV[:]) != 1 {
+		clear(key)
+		return nil, errKeyUnwrap
+	}
+	return key, nil
+}
+
+// WrapKeyWithPadding wraps key with the AES key wrap with padding algorithm
+// (KWP), as specified in RFC 5649 and NIST SP 800-38F. The block must have a
+// 128-bit block size, and is usually created by [crypto/aes.NewCipher] with a
+// key-encryption key.
+//
+// The key must not be empty. The wrapped key is between 9 and 16 bytes longer
+// than key.
+func WrapKeyWithPadding(b Block, key []byte) ([]byte, error) {
+	if err := checkKeyWrapBlock(b); err != nil {
+		return nil, err
+	}
+	if len(key) == 0 || uint64(len(key)) > 1<<32-1 {
+		return nil, errors.New("cipher: invalid key length for key wrap with padding")
+	}
+	var iv [kwSemiblock]byte
+	copy(iv[:], kwpIVPrefix[:])
+	byteorder.BEPutUint32(iv[4:], uint32(len(key)))
+
+	padded := (len(key) + kwSemiblock - 1) / kwSemiblock * kwSemiblock
+	out := make([]byte, kwSemiblock+padded)
+	if padded == kwSemiblock {
+		// A single semiblock is encrypted directly with the IV.
+		copy(out, iv[:])
+		copy(out[kwSemiblock:], key)
+		b.Encrypt(out, out)
+		return out, nil
+	}
+	copy(out[kwSemiblock:], key)
+	a := kwWrap(b, iv, out[kwSemiblock:])
+	copy(out, a[:])
+	return out, nil
+}
+
+// UnwrapKeyWithPadding unwraps a key wrapped with [WrapKeyWithPadding], and
+// checks its integrity.
+func UnwrapKeyWithPadding(b Block, wrapped []byte) ([]byte, error) {
+	if err := checkKeyWrapBlock(b); err != nil {
+		return nil, err
+	}
+	if len(wrapped)%kwSemiblock != 0 || len(wrapped) < 2*kwSemiblock {
+		return nil, errKeyUnwrap
+	}
+	var a [kwSemiblock]byte
+	key := make([]byte, len(wrapped)-kwSemiblock)
+	if len(wrapped) == 2*kwSemiblock {
+		var block [kwBlockSize]byte
+		b.Decrypt(block[:], wrapped)
+		copy(a[:], block[:kwSemiblock])
+		copy(key, block[kwSemiblock:])
+		clear(block[:])
+	} else {
+		copy(key, wrapped[kwSemiblock:])
+		a = kwUnwrap(b, [kwSemiblock]byte(wrapped), key)
+	}
+
+	// Check the IV prefix, that the length is within the last semiblock, and
+	// that the padding is all zeroes, without leaking which check failed. All
+	// values are well below 2⁶³, so the sign bit of a difference is the result
+	// of a comparison.
+	n := uint64(byteorder.BEUint32(a[4:]))
+	size := uint64(len(key))
+	ok := subtle.ConstantTimeCompare(a[:4], kwpIVPrefix[:])
+	ok &= int((size - kwSemiblock - n) >> 63) // n > size - 8
+	ok &= int((n - size - 1) >> 63)           // n <= size
+	var padding byte
+	for i := size - kwSemiblock; i < size; i++ {
+		isPadding := byte((n - 1 - i) >> 63) // i >= n
+		padding |= key[i] & -isPadding
+	}
+	ok &= subtle.ConstantTimeByteEq(padding, 0)
+	if ok != 1 {
+		clear(key)
+		return nil, errKeyUnwrap
+	}
+	return key[:n], nil
+}
+
+func checkKeyWrapBlock(b Block) error {
+	if fips140only.Enforced() {
+		return errors.New("crypto/cipher: use of key wrap is not allowed in FIPS 140-only mode")
+	}
+	if b.BlockSize() != kwBlockSize {
+		return errors.New("cipher: key wrap requires 128-bit block cipher")
+	}
+	return nil
+}
+
+// kwWrap is the wrapping function W of NIST SP 800-38F, Algorithm 1. It
+// encrypts r in place, and returns the new value of the first semiblock.
+func kwWrap(b Block, a [kwSemiblock]byte, r []byte) [kwSemiblock]byte {
+	n := len(r) / kwSemiblock
+	var block [kwBlockSize]byte
+	for j := range 6 {
+		for i := range n {
+			copy(block[:kwSemiblock], a[:])
+			copy(block[kwSemiblock:], r[i*kwSemiblock:])
+			b.Encrypt(block[:], block[:])
+			t := uint64(n*j + i + 1)
+			byteorder.BEPutUint64(a[:], byteorder.BEUint64(block[:kwSemiblock])^t)
+			copy(r[i*kwSemiblock:], block[kwSemiblock:])
+		}
+	}
+	return a
+}
+
+// kwUnwrap is the unwrapping function W⁻¹ of NIST SP 800-38F, Algorithm 2. It
+// decrypts r in place, and returns the recovered value of the first semiblock.
+func kwUnwrap(b Block, a [kwSemiblock]byte, r []byte) [kwSemiblock]byte {
+	n := len(r) / kwSemiblock
+	var block [kwBlockSize]byte
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			byteorder.BEPutUint64(block[:kwSemiblock], byteorder.BEUint64(a[:])^t)
+			copy(block[kwSemiblock:], r[i*kwSemiblock:])
+			b.Decrypt(block[:], block[:])
+			copy(a[:], block[:kwSemiblock])
+			copy(r[i*kwSemiblock:], block[kwSemiblock:])
+		}
+	}
+	clear(block[:])
+	return a
+}
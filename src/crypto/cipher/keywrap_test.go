@@ -0,0 +1,146 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"encoding/hex"
+	"testing"
+)
+
+var keyWrapTests = []struct {
+	kek, key, wrapped string
+	padding           bool
+}{
+	// RFC 3394, Section 4.
+	{
+		"000102030405060708090a0b0c0d0e0f",
+		"00112233445566778899aabbccddeeff",
+		"1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5",
+		false,
+	},
+	{
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+		"00112233445566778899aabbccddeeff000102030405060708090a0b0c0d0e0f",
+		"28c9f404c4b810f4cbccb35cfb87f8263f5786e2d80ed326cbc7f0e71a99f43bfb988b9b7a02dd21",
+		false,
+	},
+	// RFC 5649, Section 6.
+	{
+		"5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8",
+		"c37b7e6492584340bed12207808941155068f738",
+		"138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6a",
+		true,
+	},
+	{
+		"5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8",
+		"466f7250617369",
+		"afbeb0f07dfbf5419200f2ccb50bb24f",
+		true,
+	},
+}
+
+func TestKeyWrap(t *testing.T) {
+	for i, tt := range keyWrapTests {
+		kek, _ := hex.DecodeString(tt.kek)
+		key, _ := hex.DecodeString(tt.key)
+		wrapped, _ := hex.DecodeString(tt.wrapped)
+		b, err := aes.NewCipher(kek)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wrap, unwrap := cipher.WrapKey, cipher.UnwrapKey
+		if tt.padding {
+			wrap, unwrap = cipher.WrapKeyWithPadding, cipher.UnwrapKeyWithPadding
+		}
+
+		got, err := wrap(b, key)
+		if err != nil {
+			t.Errorf("#%d: wrap: %v", i, err)
+		} else if !bytes.Equal(got, wrapped) {
+			t.Errorf("#%d: wrap = %x, want %x", i, got, wrapped)
+		}
+		got, err = unwrap(b, wrapped)
+		if err != nil {
+			t.Errorf("#%d: unwrap: %v", i, err)
+		} else if !bytes.Equal(got, key) {
+			t.Errorf("#%d: unwrap = %x, want %x", i, got, key)
+		}
+
+		for j := range wrapped {
+			wrapped[j] ^= 1
+			if _, err := unwrap(b, wrapped); err == nil {
+				t.Errorf("#%d: unwrap succeeded with byte %d modified", i, j)
+			}
+			wrapped[j] ^= 1
+		}
+	}
+}
+
+func TestKeyWrapWithPaddingLengths(t *testing.T) {
+	b, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 1; n <= 40; n++ {
+		key := bytes.Repeat([]byte{0xff}, n)
+		wrapped, err := cipher.WrapKeyWithPadding(b, key)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", n, err)
+		}
+		if want := (n+7)/8*8 + 8; len(wrapped) != want {
+			t.Errorf("%d bytes: wrapped length %d, want %d", n, len(wrapped), want)
+		}
+		got, err := cipher.UnwrapKeyWithPadding(b, wrapped)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", n, err)
+		}
+		if !bytes.Equal(got, key) {
+			t.Errorf("%d bytes: unwrapped %x", n, got)
+		}
+		// A padded key must not unwrap as an unpadded one.
+		if len(wrapped) >= 24 {
+			if _, err := cipher.UnwrapKey(b, wrapped); err == nil {
+				t.Errorf("%d bytes: UnwrapKey succeeded with a KWP ciphertext", n)
+			}
+		}
+	}
+}
+
+func TestKeyWrapInvalidInputs(t *testing.T) {
+	b, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{0, 8, 17, 31} {
+		if _, err := cipher.WrapKey(b, make([]byte, n)); err == nil {
+			t.Errorf("WrapKey accepted a %d-byte key", n)
+		}
+	}
+	if _, err := cipher.WrapKeyWithPadding(b, nil); err == nil {
+		t.Errorf("WrapKeyWithPadding accepted an empty key")
+	}
+	for _, n := range []int{0, 8, 16, 25} {
+		if _, err := cipher.UnwrapKey(b, make([]byte, n)); err == nil {
+			t.Errorf("UnwrapKey accepted a %d-byte input", n)
+		}
+	}
+	for _, n := range []int{0, 8, 17} {
+		if _, err := cipher.UnwrapKeyWithPadding(b, make([]byte, n)); err == nil {
+			t.Errorf("UnwrapKeyWithPadding accepted a %d-byte input", n)
+		}
+	}
+
+	d, err := des.NewCipher(make([]byte, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cipher.WrapKey(d, make([]byte, 16)); err == nil {
+		t.Errorf("WrapKey accepted a 64-bit block cipher")
+	}
+}
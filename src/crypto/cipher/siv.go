@@ -0,0 +1,194 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher
+
+import (
+	"crypto/internal/fips140/aes"
+	"crypto/internal/fips140/aes/gcm"
+	"crypto/internal/fips140/alias"
+	"crypto/internal/fips140only"
+	"crypto/subtle"
+	"errors"
+)
+
+const (
+	sivStandardNonceSize = 16
+	sivSize              = 16
+)
+
+// NewSIV returns an AES-SIV [AEAD], as specified in RFC 5297, using the given
+// 32, 48, or 64 byte key, with 16-byte nonces.
+//
+// The first half of the key is used for the S2V authentication with
+// AES-CMAC, and the second half for AES-CTR encryption, so the three key
+// sizes correspond to AES-128, AES-192, and AES-256 respectively.
+//
+// AES-SIV is resistant to nonce misuse: if a nonce is reused, it only reveals
+// whether the same plaintext and additional data were encrypted twice.
+//
+// The synthetic IV is prepended to the ciphertext, as specified in RFC 5297,
+// rather than appended like the tag of other AEADs. Unlike the other modes in
+// this package, AES-SIV uses two AES keys, so it takes a key rather than a
+// [Block].
+func NewSIV(key []byte) (AEAD, error) {
+	return NewSIVWithNonceSize(key, sivStandardNonceSize)
+}
+
+// NewSIVWithNonceSize is like [NewSIV], but accepts nonces of the given
+// length.
+//
+// If size is zero, the nonce is omitted from the S2V input, which makes
+// encryption deterministic, as in RFC 5297, Section 3. Deterministic
+// encryption reveals when the same plaintext and additional data are
+// encrypted twice, and should only be used for key wrapping or for values,
+// like identifiers, that must be searchable while encrypted.
+func NewSIVWithNonceSize(key []byte, size int) (AEAD, error) {
+	if fips140only.Enforced() {
+		return nil, errors.New("crypto/cipher: use of AES-SIV is not allowed in FIPS 140-only mode")
+	}
+	if len(key) != 32 && len(key) != 48 && len(key) != 64 {
+		return nil, errors.New("cipher: invalid key size for AES-SIV")
+	}
+	if size < 0 {
+		return nil, errors.New("cipher: invalid nonce size for AES-SIV")
+	}
+	macBlock, err := aes.New(key[:len(key)/2])
+	if err != nil {
+		return nil, err
+	}
+	ctrBlock, err := aes.New(key[len(key)/2:])
+	if err != nil {
+		return nil, err
+	}
+	return &siv{mac: gcm.NewCMAC(macBlock), block: ctrBlock, nonceSize: size}, nil
+}
+
+type siv struct {
+	mac       *gcm.CMAC
+	block     *aes.Block
+	nonceSize int
+}
+
+func (s *siv) NonceSize() int {
+	return s.nonceSize
+}
+
+func (s *siv) Overhead() int {
+	return sivSize
+}
+
+func (s *siv) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != s.nonceSize {
+		panic("crypto/cipher: incorrect nonce length given to SIV")
+	}
+
+	ret, out := sliceForAppend(dst, sivSize+len(plaintext))
+	if alias.InexactOverlap(out, plaintext) {
+		panic("crypto/cipher: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("crypto/cipher: invalid buffer overlap of output and additional data")
+	}
+	// The synthetic IV is prepended, so if dst is plaintext[:0] the
+	// ciphertext doesn't line up with the plaintext. Move the plaintext into
+	// place first, and then encrypt it in place.
+	ciphertext := out[sivSize:]
+	if alias.AnyOverlap(out, plaintext) {
+		copy(ciphertext, plaintext)
+		plaintext = ciphertext
+	}
+
+	v := s.s2v(additionalData, nonce, plaintext)
+	s.counterCrypt(ciphertext, plaintext, &v)
+	copy(out, v[:])
+
+	return ret
+}
+
+func (s *siv) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != s.nonceSize {
+		panic("crypto/cipher: incorrect nonce length given to SIV")
+	}
+	if len(ciphertext) < sivSize {
+		return nil, errOpen
+	}
+
+	ret, out := sliceForAppend(dst, len(ciphertext)-sivSize)
+	if alias.InexactOverlap(out, ciphertext) {
+		panic("crypto/cipher: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("crypto/cipher: invalid buffer overlap of output and additional data")
+	}
+
+	var v [sivSize]byte
+	copy(v[:], ciphertext)
+	ciphertext = ciphertext[sivSize:]
+	// See the discussion in Seal. Note that if there is any overlap at this
+	// point, it's because out starts at the synthetic IV.
+	if alias.AnyOverlap(out, ciphertext) {
+		copy(out, ciphertext)
+		ciphertext = out
+	}
+
+	s.counterCrypt(out, ciphertext, &v)
+	expected := s.s2v(additionalData, nonce, out)
+	if subtle.ConstantTimeCompare(expected[:], v[:]) != 1 {
+		// Don't release unauthenticated plaintext.
+		clear(out)
+		return nil, errOpen
+	}
+
+	return ret, nil
+}
+
+// counterCrypt encrypts src into out with AES-CTR, starting from the
+// synthetic IV with the 31st and 63rd bits cleared. See RFC 5297, Section 2.6.
+func (s *siv) counterCrypt(out, src []byte, v *[sivSize]byte) {
+	q := *v
+	q[8] &= 0x7f
+	q[12] &= 0x7f
+	aes.NewCTR(s.block, q[:]).XORKeyStream(out, src)
+}
+
+// s2v computes the S2V function of RFC 5297, Section 2.4, over the additional
+// data, the nonce (if nonces are used), and the plaintext.
+func (s *siv) s2v(additionalData, nonce, plaintext []byte) [sivSize]byte {
+	var zero [sivSize]byte
+	d := s.mac.MAC(zero[:])
+
+	d = dbl(d)
+	m := s.mac.MAC(additionalData)
+	subtle.XORBytes(d[:], d[:], m[:])
+	if s.nonceSize > 0 {
+		d = dbl(d)
+		m = s.mac.MAC(nonce)
+		subtle.XORBytes(d[:], d[:], m[:])
+	}
+
+	if len(plaintext) >= sivSize {
+		// T = Sn xorend D.
+		t := make([]byte, len(plaintext))
+		copy(t, plaintext)
+		end := t[len(t)-sivSize:]
+		subtle.XORBytes(end, end, d[:])
+		return s.mac.MAC(t)
+	}
+	// T = dbl(D) xor pad(Sn).
+	d = dbl(d)
+	subtle.XORBytes(d[:], d[:], plaintext)
+	d[len(plaintext)] ^= 0x80
+	return s.mac.MAC(d[:])
+}
+
+// dbl doubles x in GF(2¹²⁸), as specified in RFC 5297, Section 2.3.
+func dbl(x [16]byte) [16]byte {
+	var msb byte
+	for i := len(x) - 1; i >= 0; i-- {
+		msb, x[i] = x[i]>>7, x[i]<<1|msb
+	}
+	x[len(x)-1] ^= msb * 0b10000111
+	return x
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cipher_test
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/internal/cryptotest"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+var sivTests = []struct {
+	key, nonce, plaintext, ad, result string
+}{
+	// RFC 5297, Appendix A.1.
+	{
+		"fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+		"",
+		"112233445566778899aabbccddee",
+		"101112131415161718191a1b1c1d1e1f2021222324252627",
+		"85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c",
+	},
+	{
+		"000000000000000000000000000000000000000000000000111111111111111111111111111111111111111111111111",
+		"",
+		"",
+		"",
+		"0f6f4e61f1e460950b5bd37ce2621639",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+			"1111111111111111111111111111111122222222222222222222222222222222",
+		"abababababababababababababababab",
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f3031",
+		"6164",
+		"8b1405bfe02d1195b86a7fb06bcdde9649daf114806d1560db5e184ceda6ee79ba85fa8979597f7e4efff2311dc3261bc581b0d55bcf3a04b152fd0d120c9205474c",
+	},
+}
+
+func TestSIV(t *testing.T) {
+	for i, tt := range sivTests {
+		key, _ := hex.DecodeString(tt.key)
+		nonce, _ := hex.DecodeString(tt.nonce)
+		plaintext, _ := hex.DecodeString(tt.plaintext)
+		ad, _ := hex.DecodeString(tt.ad)
+		result, _ := hex.DecodeString(tt.result)
+
+		aead, err := cipher.NewSIVWithNonceSize(key, len(nonce))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := aead.Seal(nil, nonce, plaintext, ad); !bytes.Equal(got, result) {
+			t.Errorf("#%d: Seal = %x, want %x", i, got, result)
+		}
+		got, err := aead.Open(nil, nonce, result, ad)
+		if err != nil {
+			t.Errorf("#%d: Open: %v", i, err)
+		} else if !bytes.Equal(got, plaintext) {
+			t.Errorf("#%d: Open = %x, want %x", i, got, plaintext)
+		}
+
+		result[0] ^= 1
+		if _, err := aead.Open(nil, nonce, result, ad); err == nil {
+			t.Errorf("#%d: Open succeeded with a modified synthetic IV", i)
+		}
+		result[0] ^= 1
+		if len(plaintext) > 0 {
+			result[len(result)-1] ^= 1
+			if _, err := aead.Open(nil, nonce, result, ad); err == nil {
+				t.Errorf("#%d: Open succeeded with a modified ciphertext", i)
+			}
+		}
+	}
+}
+
+func TestSIVInPlace(t *testing.T) {
+	aead, err := cipher.NewSIV(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	plaintext := []byte("a plaintext longer than one block")
+	want := aead.Seal(nil, nonce, plaintext, nil)
+
+	buf := make([]byte, len(plaintext), len(plaintext)+aead.Overhead())
+	copy(buf, plaintext)
+	ciphertext := aead.Seal(buf[:0], nonce, buf, nil)
+	if !bytes.Equal(ciphertext, want) {
+		t.Fatalf("in-place Seal = %x, want %x", ciphertext, want)
+	}
+	got, err := aead.Open(ciphertext[:0], nonce, ciphertext, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("in-place Open = %q, want %q", got, plaintext)
+	}
+}
+
+func TestSIVKeySize(t *testing.T) {
+	for _, size := range []int{0, 16, 24, 40, 65} {
+		if _, err := cipher.NewSIV(make([]byte, size)); err == nil {
+			t.Errorf("NewSIV accepted a %d-byte key", size)
+		}
+	}
+}
+
+func TestSIVAEAD(t *testing.T) {
+	for _, keySize := range []int{32, 48, 64} {
+		key := make([]byte, keySize)
+		for _, nonceSize := range []int{0, 12, 16} {
+			t.Run(fmt.Sprintf("Key-%d/NonceSize-%d", keySize, nonceSize), func(t *testing.T) {
+				cryptotest.TestAEAD(t, func() (cipher.AEAD, error) { return cipher.NewSIVWithNonceSize(key, nonceSize) })
+			})
+		}
+	}
+}
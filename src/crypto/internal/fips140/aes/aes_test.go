@@ -4,7 +4,10 @@
 
 package aes
 
-import "testing"
+import (
+	"bytes"
+	"testing"
+)
 
 // See const.go for overview of math here.
 
@@ -118,3 +121,29 @@ func TestTd(t *testing.T) {
 		}
 	}
 }
+
+// Test that XORKeyStreamLE32 wraps the counter without carrying into the rest
+// of the counter block.
+func TestXORKeyStreamLE32(t *testing.T) {
+	b, err := New(make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr := [BlockSize]byte{0: 0xfe, 1: 0xff, 2: 0xff, 3: 0xff, 4: 0x42}
+	src := make([]byte, 3*BlockSize+5)
+	dst := make([]byte, len(src))
+	XORKeyStreamLE32(b, dst, src, &ctr)
+
+	want := make([]byte, 4*BlockSize)
+	for i, c := range []byte{0xfe, 0xff, 0x00, 0x01} {
+		in := ctr
+		in[0] = c
+		if c < 0xfe {
+			in[1], in[2], in[3] = 0, 0, 0
+		}
+		b.Encrypt(want[i*BlockSize:], in[:])
+	}
+	if !bytes.Equal(dst, want[:len(src)]) {
+		t.Errorf("XORKeyStreamLE32 = %x, want %x", dst, want[:len(src)])
+	}
+}
@@ -146,3 +146,36 @@ func add128(lo, hi uint64, x uint64) (uint64, uint64) {
 	hi, _ = bits.Add64(hi, 0, c)
 	return lo, hi
 }
+
+// XORKeyStreamLE32 XORs src with the keystream of a counter mode where the
+// counter is the first four bytes of the counter block, incremented as a
+// little-endian integer modulo 2^32, and stores the result in dst. This is
+// the counter mode of AES-GCM-SIV, specified in RFC 8452, Section 4, which
+// is not approved.
+//
+// The keystream starts from the counter block ctr.
+func XORKeyStreamLE32(b *Block, dst, src []byte, ctr *[BlockSize]byte) {
+	if len(dst) < len(src) {
+		panic("crypto/aes: len(dst) < len(src)")
+	}
+	dst = dst[:len(src)]
+	if alias.InexactOverlap(dst, src) {
+		panic("crypto/aes: invalid buffer overlap")
+	}
+	fips140.RecordNonApproved()
+
+	counter := *ctr
+	c := byteorder.LEUint32(counter[:4])
+	var buf [8 * BlockSize]byte
+	for len(src) > 0 {
+		n := min(len(src), len(buf))
+		for i := 0; i < n; i += BlockSize {
+			byteorder.LEPutUint32(counter[:4], c)
+			c++
+			encryptBlock(b, buf[i:], counter[:])
+		}
+		subtle.XORBytes(dst, src[:n], buf[:n])
+		src = src[n:]
+		dst = dst[n:]
+	}
+}
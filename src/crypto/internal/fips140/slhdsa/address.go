@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slhdsa
+
+import "crypto/internal/fips140deps/byteorder"
+
+// address is the 32-byte ADRS structure used to domain separate hash
+// function calls, as defined in FIPS 205, Section 4.2.
+//
+//	layer address  [0:4]
+//	tree address   [4:16]
+//	type           [16:20]
+//	key pair       [20:24]
+//	chain / height [24:28]
+//	hash / index   [28:32]
+type address [32]byte
+
+// Address types, FIPS 205, Section 4.2.
+const (
+	addrWOTSHash  = 0
+	addrWOTSPK    = 1
+	addrTree      = 2
+	addrFORSTree  = 3
+	addrFORSRoots = 4
+	addrWOTSPRF   = 5
+	addrFORSPRF   = 6
+)
+
+func (a *address) setLayerAddress(l uint32) {
+	byteorder.BEPutUint32(a[0:4], l)
+}
+
+// setTreeAddress sets the 12-byte tree address. Tree addresses are at most
+// h - h' ≤ 64 bits long, so the first four bytes are always zero.
+func (a *address) setTreeAddress(t uint64) {
+	byteorder.BEPutUint32(a[4:8], 0)
+	byteorder.BEPutUint64(a[8:16], t)
+}
+
+// setTypeAndClear sets the address type and zeroes the last 12 bytes.
+func (a *address) setTypeAndClear(y uint32) {
+	byteorder.BEPutUint32(a[16:20], y)
+	clear(a[20:32])
+}
+
+func (a *address) setKeyPairAddress(i uint32) {
+	byteorder.BEPutUint32(a[20:24], i)
+}
+
+func (a *address) keyPairAddress() uint32 {
+	return byteorder.BEUint32(a[20:24])
+}
+
+func (a *address) setChainAddress(i uint32) {
+	byteorder.BEPutUint32(a[24:28], i)
+}
+
+func (a *address) setTreeHeight(z uint32) {
+	byteorder.BEPutUint32(a[24:28], z)
+}
+
+func (a *address) setHashAddress(i uint32) {
+	byteorder.BEPutUint32(a[28:32], i)
+}
+
+func (a *address) setTreeIndex(i uint32) {
+	byteorder.BEPutUint32(a[28:32], i)
+}
+
+// compressed returns the 22-byte ADRSᶜ used by the SHA2 parameter sets,
+// as defined in FIPS 205, Section 11.2.
+func (a *address) compressed() [22]byte {
+	var c [22]byte
+	c[0] = a[3]
+	copy(c[1:9], a[8:16])
+	c[9] = a[19]
+	copy(c[10:22], a[20:32])
+	return c
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slhdsa
+
+// FORS few-time signatures, FIPS 205, Section 8.
+
+const maxK, maxA = 35, 14
+
+func (p *Parameters) forsSigSize() int {
+	return p.k * (p.a + 1) * p.n
+}
+
+// forsIndices splits md into k a-bit indices, implementing base_2b
+// (Algorithm 4) for b = a.
+func (p *Parameters) forsIndices(md []byte) (indices [maxK]uint32) {
+	var in, bits int
+	var total uint32
+	for i := range p.k {
+		for bits < p.a {
+			total = total<<8 | uint32(md[in])
+			in++
+			bits += 8
+		}
+		bits -= p.a
+		indices[i] = (total >> bits) & (1<<p.a - 1)
+	}
+	return indices
+}
+
+// forsSKGen computes the FORS private key value with index idx into out[:n].
+//
+// It implements Algorithm 14, fors_skGen.
+func (c *hashContext) forsSKGen(out []byte, adrs address, idx uint32) {
+	skADRS := adrs
+	skADRS.setTypeAndClear(addrFORSPRF)
+	skADRS.setKeyPairAddress(adrs.keyPairAddress())
+	skADRS.setTreeIndex(idx)
+	c.prf(out, &skADRS)
+}
+
+// forsNode computes into out[:n] the root of the subtree of height z whose
+// index among the subtrees of that height is i.
+//
+// It implements Algorithm 15, fors_node.
+func (c *hashContext) forsNode(out []byte, i, z uint32, adrs address) {
+	n := c.p.n
+	if z == 0 {
+		var sk [maxN]byte
+		c.forsSKGen(sk[:n], adrs, i)
+		adrs.setTreeHeight(0)
+		adrs.setTreeIndex(i)
+		c.f(out, &adrs, sk[:n])
+		return
+	}
+	var nodes [2 * maxN]byte
+	c.forsNode(nodes[:n], 2*i, z-1, adrs)
+	c.forsNode(nodes[n:2*n], 2*i+1, z-1, adrs)
+	adrs.setTreeHeight(z)
+	adrs.setTreeIndex(i)
+	c.t(out, &adrs, nodes[:2*n])
+}
+
+// forsSign computes a FORS signature of md into sig[:k×(a+1)×n].
+//
+// It implements Algorithm 16, fors_sign.
+func (c *hashContext) forsSign(sig, md []byte, adrs address) {
+	n, a := c.p.n, c.p.a
+	indices := c.p.forsIndices(md)
+	for i := range c.p.k {
+		s := sig[i*(a+1)*n : (i+1)*(a+1)*n]
+		c.forsSKGen(s[:n], adrs, uint32(i)<<a+indices[i])
+		auth := s[n:]
+		for j := range a {
+			k := (indices[i] >> j) ^ 1
+			c.forsNode(auth[j*n:(j+1)*n], uint32(i)<<(a-j)+k, uint32(j), adrs)
+		}
+	}
+}
+
+// forsPKFromSig computes a FORS public key from a signature of md into out[:n].
+//
+// It implements Algorithm 17, fors_pkFromSig.
+func (c *hashContext) forsPKFromSig(out, sig, md []byte, adrs address) {
+	n, a := c.p.n, c.p.a
+	indices := c.p.forsIndices(md)
+	var roots [maxK * maxN]byte
+	for i := range c.p.k {
+		s := sig[i*(a+1)*n : (i+1)*(a+1)*n]
+		idx := uint32(i)<<a + indices[i]
+		var node [2 * maxN]byte
+		adrs.setTreeHeight(0)
+		adrs.setTreeIndex(idx)
+		c.f(node[:n], &adrs, s[:n])
+		auth := s[n:]
+		for j := range a {
+			adrs.setTreeHeight(uint32(j + 1))
+			adrs.setTreeIndex(idx >> (j + 1))
+			if (indices[i]>>j)&1 == 0 {
+				copy(node[n:2*n], auth[j*n:(j+1)*n])
+			} else {
+				copy(node[n:2*n], node[:n])
+				copy(node[:n], auth[j*n:(j+1)*n])
+			}
+			c.t(node[:n], &adrs, node[:2*n])
+		}
+		copy(roots[i*n:(i+1)*n], node[:n])
+	}
+	pkADRS := adrs
+	pkADRS.setTypeAndClear(addrFORSRoots)
+	pkADRS.setKeyPairAddress(adrs.keyPairAddress())
+	c.t(out, &pkADRS, roots[:c.p.k*n])
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slhdsa
+
+import (
+	"crypto/internal/fips140/hmac"
+	"crypto/internal/fips140/sha256"
+	"crypto/internal/fips140/sha3"
+	"crypto/internal/fips140/sha512"
+	"crypto/internal/fips140deps/byteorder"
+)
+
+// hashContext implements the functions PRF, F, H, and Tₗ of FIPS 205,
+// Section 11, for a parameter set, a public seed, and optionally a secret seed.
+//
+// Every call to these functions starts by hashing PK.seed, and for the SHA2
+// parameter sets PK.seed is padded to a full block, so the hash state after
+// absorbing it is computed once and copied for each call.
+type hashContext struct {
+	p      *Parameters
+	skSeed []byte // nil if only verifying
+
+	shake sha3.SHAKE     // SHAKE256(PK.seed || ...)
+	s256  sha256.Digest  // SHA-256(PK.seed || toByte(0, 64 - n) || ...)
+	s512  sha512.Digest  // SHA-512(PK.seed || toByte(0, 128 - n) || ...)
+	buf   [64]byte       // scratch space for SHA2 outputs
+	zeros [128 - 16]byte // padding for PK.seed
+}
+
+func newHashContext(p *Parameters, pkSeed, skSeed []byte) *hashContext {
+	c := &hashContext{p: p, skSeed: skSeed}
+	if p.shake {
+		c.shake = *sha3.NewShake256()
+		c.shake.Write(pkSeed)
+		return c
+	}
+	c.s256 = *sha256.New()
+	c.s256.Write(pkSeed)
+	c.s256.Write(c.zeros[:64-p.n])
+	if p.n > 16 {
+		c.s512 = *sha512.New()
+		c.s512.Write(pkSeed)
+		c.s512.Write(c.zeros[:128-p.n])
+	}
+	return c
+}
+
+// f computes F(PK.seed, ADRS, in) into out[:n]. out and in may overlap.
+func (c *hashContext) f(out []byte, adrs *address, in []byte) {
+	if c.p.shake {
+		h := c.shake
+		h.Write(adrs[:])
+		h.Write(in)
+		h.Read(out[:c.p.n])
+		return
+	}
+	h := c.s256
+	ac := adrs.compressed()
+	h.Write(ac[:])
+	h.Write(in)
+	copy(out[:c.p.n], h.Sum(c.buf[:0]))
+}
+
+// t computes H(PK.seed, ADRS, in) or Tₗ(PK.seed, ADRS, in) into out[:n].
+// out and in may overlap.
+func (c *hashContext) t(out []byte, adrs *address, in []byte) {
+	if c.p.shake || c.p.n == 16 {
+		c.f(out, adrs, in)
+		return
+	}
+	h := c.s512
+	ac := adrs.compressed()
+	h.Write(ac[:])
+	h.Write(in)
+	copy(out[:c.p.n], h.Sum(c.buf[:0]))
+}
+
+// prf computes PRF(PK.seed, SK.seed, ADRS) into out[:n].
+func (c *hashContext) prf(out []byte, adrs *address) {
+	c.f(out, adrs, c.skSeed)
+}
+
+// prfMsg computes PRF_msg(SK.prf, opt_rand, M) into out[:n], where M is the
+// concatenation of prefix and msg.
+func (c *hashContext) prfMsg(out, skPRF, optRand, prefix, msg []byte) {
+	n := c.p.n
+	switch {
+	case c.p.shake:
+		h := sha3.NewShake256()
+		h.Write(skPRF)
+		h.Write(optRand)
+		h.Write(prefix)
+		h.Write(msg)
+		h.Read(out[:n])
+	case n == 16:
+		h := hmac.New(sha256.New, skPRF)
+		h.Write(optRand)
+		h.Write(prefix)
+		h.Write(msg)
+		copy(out[:n], h.Sum(c.buf[:0]))
+	default:
+		h := hmac.New(sha512.New, skPRF)
+		h.Write(optRand)
+		h.Write(prefix)
+		h.Write(msg)
+		copy(out[:n], h.Sum(c.buf[:0]))
+	}
+}
+
+// hMsg computes H_msg(R, PK.seed, PK.root, M) into out[:m], where M is the
+// concatenation of prefix and msg.
+func (c *hashContext) hMsg(out, r, pkSeed, pkRoot, prefix, msg []byte) {
+	out = out[:c.p.m]
+	switch {
+	case c.p.shake:
+		h := sha3.NewShake256()
+		h.Write(r)
+		h.Write(pkSeed)
+		h.Write(pkRoot)
+		h.Write(prefix)
+		h.Write(msg)
+		h.Read(out)
+	case c.p.n == 16:
+		h := sha256.New()
+		h.Write(r)
+		h.Write(pkSeed)
+		h.Write(pkRoot)
+		h.Write(prefix)
+		h.Write(msg)
+		seed := append(append(append([]byte{}, r...), pkSeed...), h.Sum(nil)...)
+		mgf1(out, seed, func(b []byte) []byte {
+			h := sha256.New()
+			h.Write(b)
+			return h.Sum(nil)
+		})
+	default:
+		h := sha512.New()
+		h.Write(r)
+		h.Write(pkSeed)
+		h.Write(pkRoot)
+		h.Write(prefix)
+		h.Write(msg)
+		seed := append(append(append([]byte{}, r...), pkSeed...), h.Sum(nil)...)
+		mgf1(out, seed, func(b []byte) []byte {
+			h := sha512.New()
+			h.Write(b)
+			return h.Sum(nil)
+		})
+	}
+}
+
+// mgf1 fills out with MGF1 applied to seed, as defined in RFC 8017,
+// Appendix B.2.1.
+func mgf1(out, seed []byte, hash func([]byte) []byte) {
+	in := append(seed, 0, 0, 0, 0)
+	var counter uint32
+	for len(out) > 0 {
+		byteorder.BEPutUint32(in[len(seed):], counter)
+		n := copy(out, hash(in))
+		out = out[n:]
+		counter++
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package slhdsa implements the SLH-DSA stateless hash-based signature scheme
+// specified in FIPS 205.
+package slhdsa
+
+import (
+	"crypto/internal/fips140"
+	"crypto/internal/fips140/drbg"
+	"crypto/internal/fips140/subtle"
+	"crypto/internal/fips140deps/byteorder"
+	"errors"
+)
+
+// Parameters is one of the SLH-DSA parameter sets of FIPS 205, Section 11.
+type Parameters struct {
+	name  string
+	n     int  // security parameter, in bytes
+	h     int  // total height of the hypertree
+	d     int  // number of hypertree layers
+	hp    int  // height of each XMSS tree, h'
+	a     int  // height of each FORS tree
+	k     int  // number of FORS trees
+	m     int  // length of the message digest, in bytes
+	shake bool // SHAKE or SHA2 instantiation
+}
+
+const maxN, maxM = 32, 49
+
+var (
+	SHA2_128s  = &Parameters{name: "SLH-DSA-SHA2-128s", n: 16, h: 63, d: 7, hp: 9, a: 12, k: 14, m: 30}
+	SHA2_128f  = &Parameters{name: "SLH-DSA-SHA2-128f", n: 16, h: 66, d: 22, hp: 3, a: 6, k: 33, m: 34}
+	SHA2_192s  = &Parameters{name: "SLH-DSA-SHA2-192s", n: 24, h: 63, d: 7, hp: 9, a: 14, k: 17, m: 39}
+	SHA2_192f  = &Parameters{name: "SLH-DSA-SHA2-192f", n: 24, h: 66, d: 22, hp: 3, a: 8, k: 33, m: 42}
+	SHA2_256s  = &Parameters{name: "SLH-DSA-SHA2-256s", n: 32, h: 64, d: 8, hp: 8, a: 14, k: 22, m: 47}
+	SHA2_256f  = &Parameters{name: "SLH-DSA-SHA2-256f", n: 32, h: 68, d: 17, hp: 4, a: 9, k: 35, m: 49}
+	SHAKE_128s = &Parameters{name: "SLH-DSA-SHAKE-128s", n: 16, h: 63, d: 7, hp: 9, a: 12, k: 14, m: 30, shake: true}
+	SHAKE_128f = &Parameters{name: "SLH-DSA-SHAKE-128f", n: 16, h: 66, d: 22, hp: 3, a: 6, k: 33, m: 34, shake: true}
+	SHAKE_192s = &Parameters{name: "SLH-DSA-SHAKE-192s", n: 24, h: 63, d: 7, hp: 9, a: 14, k: 17, m: 39, shake: true}
+	SHAKE_192f = &Parameters{name: "SLH-DSA-SHAKE-192f", n: 24, h: 66, d: 22, hp: 3, a: 8, k: 33, m: 42, shake: true}
+	SHAKE_256s = &Parameters{name: "SLH-DSA-SHAKE-256s", n: 32, h: 64, d: 8, hp: 8, a: 14, k: 22, m: 47, shake: true}
+	SHAKE_256f = &Parameters{name: "SLH-DSA-SHAKE-256f", n: 32, h: 68, d: 17, hp: 4, a: 9, k: 35, m: 49, shake: true}
+)
+
+// Name returns the name of the parameter set, e.g. "SLH-DSA-SHA2-128s".
+func (p *Parameters) Name() string {
+	return p.name
+}
+
+func (p *Parameters) PublicKeySize() int {
+	return 2 * p.n
+}
+
+func (p *Parameters) PrivateKeySize() int {
+	return 4 * p.n
+}
+
+func (p *Parameters) SignatureSize() int {
+	return (1+p.h+p.d*p.wotsLen())*p.n + p.forsSigSize()
+}
+
+type PrivateKey struct {
+	skSeed [maxN]byte
+	skPRF  [maxN]byte
+	pub    PublicKey
+}
+
+type PublicKey struct {
+	p    *Parameters
+	seed [maxN]byte
+	root [maxN]byte
+}
+
+func (priv *PrivateKey) Equal(x *PrivateKey) bool {
+	return priv.pub.Equal(&x.pub) &&
+		subtle.ConstantTimeCompare(priv.skSeed[:], x.skSeed[:]) == 1 &&
+		subtle.ConstantTimeCompare(priv.skPRF[:], x.skPRF[:]) == 1
+}
+
+// Bytes returns the private key encoding SK.seed || SK.prf || PK.seed || PK.root.
+func (priv *PrivateKey) Bytes() []byte {
+	n := priv.pub.p.n
+	b := make([]byte, 0, 4*n)
+	b = append(b, priv.skSeed[:n]...)
+	b = append(b, priv.skPRF[:n]...)
+	b = append(b, priv.pub.seed[:n]...)
+	return append(b, priv.pub.root[:n]...)
+}
+
+func (priv *PrivateKey) PublicKey() *PublicKey {
+	return &priv.pub
+}
+
+func (pub *PublicKey) Equal(x *PublicKey) bool {
+	return pub.p == x.p && pub.seed == x.seed && pub.root == x.root
+}
+
+// Bytes returns the public key encoding PK.seed || PK.root.
+func (pub *PublicKey) Bytes() []byte {
+	n := pub.p.n
+	b := make([]byte, 0, 2*n)
+	b = append(b, pub.seed[:n]...)
+	return append(b, pub.root[:n]...)
+}
+
+func (pub *PublicKey) Parameters() *Parameters {
+	return pub.p
+}
+
+func GenerateKey(p *Parameters) *PrivateKey {
+	fipsSelfTest()
+	fips140.RecordApproved()
+	var seeds [3 * maxN]byte
+	drbg.Read(seeds[:3*p.n])
+	priv := newPrivateKey(p, seeds[:p.n], seeds[p.n:2*p.n], seeds[2*p.n:3*p.n])
+	fipsPCT(priv)
+	return priv
+}
+
+var (
+	errInvalidPrivateKeyLength = errors.New("slhdsa: invalid private key length")
+	errInvalidPublicKeyLength  = errors.New("slhdsa: invalid public key length")
+	errInconsistentPrivateKey  = errors.New("slhdsa: private key does not match its public key")
+)
+
+// NewPrivateKey decodes a private key encoded as
+// SK.seed || SK.prf || PK.seed || PK.root.
+//
+// PK.root is recomputed from the other values and checked.
+func NewPrivateKey(p *Parameters, b []byte) (*PrivateKey, error) {
+	fipsSelfTest()
+	fips140.RecordApproved()
+	n := p.n
+	if len(b) != 4*n {
+		return nil, errInvalidPrivateKeyLength
+	}
+	priv := newPrivateKey(p, b[:n], b[n:2*n], b[2*n:3*n])
+	if subtle.ConstantTimeCompare(priv.pub.root[:n], b[3*n:]) != 1 {
+		return nil, errInconsistentPrivateKey
+	}
+	return priv, nil
+}
+
+// NewPrivateKeyFromSeeds derives a private key from SK.seed, SK.prf, and
+// PK.seed, as in Algorithm 18, slh_keygen_internal.
+func NewPrivateKeyFromSeeds(p *Parameters, skSeed, skPRF, pkSeed []byte) (*PrivateKey, error) {
+	fipsSelfTest()
+	fips140.RecordApproved()
+	n := p.n
+	if len(skSeed) != n || len(skPRF) != n || len(pkSeed) != n {
+		return nil, errInvalidPrivateKeyLength
+	}
+	return newPrivateKey(p, skSeed, skPRF, pkSeed), nil
+}
+
+// newPrivateKey implements Algorithm 18, slh_keygen_internal.
+func newPrivateKey(p *Parameters, skSeed, skPRF, pkSeed []byte) *PrivateKey {
+	n := p.n
+	priv := &PrivateKey{pub: PublicKey{p: p}}
+	copy(priv.skSeed[:], skSeed)
+	copy(priv.skPRF[:], skPRF)
+	copy(priv.pub.seed[:], pkSeed)
+	c := newHashContext(p, priv.pub.seed[:n], priv.skSeed[:n])
+	var adrs address
+	adrs.setLayerAddress(uint32(p.d - 1))
+	c.xmssNode(priv.pub.root[:n], 0, uint32(p.hp), adrs)
+	return priv
+}
+
+func NewPublicKey(p *Parameters, b []byte) (*PublicKey, error) {
+	n := p.n
+	if len(b) != 2*n {
+		return nil, errInvalidPublicKeyLength
+	}
+	pub := &PublicKey{p: p}
+	copy(pub.seed[:], b[:n])
+	copy(pub.root[:], b[n:])
+	return pub, nil
+}
+
+var (
+	errContextTooLong   = errors.New("slhdsa: context too long")
+	errRandomLength     = errors.New("slhdsa: invalid random length")
+	errInvalidSignature = errors.New("slhdsa: invalid signature")
+	errSignatureSize    = errors.New("slhdsa: invalid signature length")
+)
+
+// messagePrefix returns the prefix prepended to messages by the pure
+// SLH-DSA signing and verification interfaces, Algorithms 22 and 24.
+func messagePrefix(context string) ([]byte, error) {
+	if len(context) > 255 {
+		return nil, errContextTooLong
+	}
+	prefix := make([]byte, 0, 2+len(context))
+	prefix = append(prefix, 0, byte(len(context)))
+	return append(prefix, context...), nil
+}
+
+func Sign(priv *PrivateKey, msg []byte, context string) ([]byte, error) {
+	fipsSelfTest()
+	fips140.RecordApproved()
+	prefix, err := messagePrefix(context)
+	if err != nil {
+		return nil, err
+	}
+	var optRand [maxN]byte
+	drbg.Read(optRand[:priv.pub.p.n])
+	return signInternal(priv, prefix, msg, optRand[:priv.pub.p.n]), nil
+}
+
+// SignDeterministic signs msg using PK.seed in place of the random value
+// opt_rand, as allowed by Algorithm 19.
+func SignDeterministic(priv *PrivateKey, msg []byte, context string) ([]byte, error) {
+	fipsSelfTest()
+	fips140.RecordApproved()
+	prefix, err := messagePrefix(context)
+	if err != nil {
+		return nil, err
+	}
+	return signInternal(priv, prefix, msg, priv.pub.seed[:priv.pub.p.n]), nil
+}
+
+func TestingOnlySignWithRandom(priv *PrivateKey, msg []byte, context string, random []byte) ([]byte, error) {
+	fipsSelfTest()
+	fips140.RecordApproved()
+	prefix, err := messagePrefix(context)
+	if err != nil {
+		return nil, err
+	}
+	if len(random) != priv.pub.p.n {
+		return nil, errRandomLength
+	}
+	return signInternal(priv, prefix, msg, random), nil
+}
+
+func Verify(pub *PublicKey, msg, sig []byte, context string) error {
+	fipsSelfTest()
+	fips140.RecordApproved()
+	prefix, err := messagePrefix(context)
+	if err != nil {
+		return err
+	}
+	return verifyInternal(pub, prefix, msg, sig)
+}
+
+// splitDigest splits the output of H_msg into the FORS message digest and
+// the indices of the XMSS tree and leaf, as in Algorithms 19 and 20.
+func (p *Parameters) splitDigest(digest []byte) (md []byte, idxTree uint64, idxLeaf uint32) {
+	mdLen := (p.k*p.a + 7) / 8
+	treeBits := p.h - p.hp
+	treeLen := (treeBits + 7) / 8
+	leafLen := (p.hp + 7) / 8
+	md = digest[:mdLen]
+	idxTree = toInt(digest[mdLen : mdLen+treeLen])
+	if treeBits < 64 {
+		idxTree &= 1<<treeBits - 1
+	}
+	idxLeaf = uint32(toInt(digest[mdLen+treeLen:mdLen+treeLen+leafLen])) & (1<<p.hp - 1)
+	return md, idxTree, idxLeaf
+}
+
+// toInt implements Algorithm 2, toInt, for inputs of up to eight bytes.
+func toInt(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[8-len(b):], b)
+	return byteorder.BEUint64(buf[:])
+}
+
+// signInternal implements Algorithm 19, slh_sign_internal, for the message
+// prefix || msg.
+func signInternal(priv *PrivateKey, prefix, msg, optRand []byte) []byte {
+	p := priv.pub.p
+	n := p.n
+	pkSeed, pkRoot := priv.pub.seed[:n], priv.pub.root[:n]
+	c := newHashContext(p, pkSeed, priv.skSeed[:n])
+
+	sig := make([]byte, p.SignatureSize())
+	r := sig[:n]
+	c.prfMsg(r, priv.skPRF[:n], optRand, prefix, msg)
+	var digest [maxM]byte
+	c.hMsg(digest[:], r, pkSeed, pkRoot, prefix, msg)
+	md, idxTree, idxLeaf := p.splitDigest(digest[:p.m])
+
+	var adrs address
+	adrs.setTreeAddress(idxTree)
+	adrs.setTypeAndClear(addrFORSTree)
+	adrs.setKeyPairAddress(idxLeaf)
+	forsSig := sig[n : n+p.forsSigSize()]
+	c.forsSign(forsSig, md, adrs)
+	var pkFORS [maxN]byte
+	c.forsPKFromSig(pkFORS[:n], forsSig, md, adrs)
+	c.htSign(sig[n+p.forsSigSize():], pkFORS[:n], idxTree, idxLeaf)
+	return sig
+}
+
+// verifyInternal implements Algorithm 20, slh_verify_internal, for the
+// message prefix || msg.
+func verifyInternal(pub *PublicKey, prefix, msg, sig []byte) error {
+	p := pub.p
+	n := p.n
+	if len(sig) != p.SignatureSize() {
+		return errSignatureSize
+	}
+	pkSeed, pkRoot := pub.seed[:n], pub.root[:n]
+	c := newHashContext(p, pkSeed, nil)
+
+	r := sig[:n]
+	var digest [maxM]byte
+	c.hMsg(digest[:], r, pkSeed, pkRoot, prefix, msg)
+	md, idxTree, idxLeaf := p.splitDigest(digest[:p.m])
+
+	var adrs address
+	adrs.setTreeAddress(idxTree)
+	adrs.setTypeAndClear(addrFORSTree)
+	adrs.setKeyPairAddress(idxLeaf)
+	forsSig := sig[n : n+p.forsSigSize()]
+	var pkFORS [maxN]byte
+	c.forsPKFromSig(pkFORS[:n], forsSig, md, adrs)
+	if !c.htVerify(pkFORS[:n], sig[n+p.forsSigSize():], idxTree, idxLeaf, pkRoot) {
+		return errInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slhdsa
+
+import (
+	"crypto/internal/fips140/sha3"
+	"encoding/hex"
+	"testing"
+)
+
+// TestAccumulated accumulates the public keys and signatures produced from
+// a deterministic stream of seeds, randomness, and messages, and checks the
+// hash of the result, to avoid checking in megabytes of test vectors.
+//
+// The expected values were generated with OpenSSL 3.5.
+func TestAccumulated(t *testing.T) {
+	for _, tt := range []struct {
+		p          *Parameters
+		iterations int
+		expected   string
+	}{
+		{SHA2_128s, 1, "1f363cf9a7d9dde473d35bf9ae041c6384f737e103846fb6289983ce355e2a06"},
+		{SHA2_128f, 3, "40b7cb5825c2237953455114ff17c6fe9568fa56fc103b8536b3ead66f7fb210"},
+		{SHA2_192s, 1, "ce6735383650228c508b6996cba29ebc8f7d00bf52fa41e34c84f77c910c92fb"},
+		{SHA2_192f, 3, "e957878ddb52778c1fa367cbc7972c544f23d15916bbaa723befb0eb25fe6891"},
+		{SHA2_256s, 1, "eec08637e0d7be5f8539534db96e7bf60faf09d97a87679e40261d7a03683822"},
+		{SHA2_256f, 3, "d56b7ba6835e659aafa7f6df443bb996cfb86c0ee0e60a61693437de6ca4a4cb"},
+		{SHAKE_128s, 1, "e41fc97936da9536fa97ef434cc70c2ec70963df74b0e7544f02ae93aa987af6"},
+		{SHAKE_128f, 3, "9f516912bee59be1a961718aaa716cfee4966001ec526310d3e24c9863cc3253"},
+		{SHAKE_192s, 1, "c8df9c6d5f39b223451948fabe8ea0a7d4e9baa0f070278f5c007b28cd5e8da0"},
+		{SHAKE_192f, 3, "d960ce3b2439883027b9703fcd6c123ad2ad88eebbbc2955513ea277beff8d56"},
+		{SHAKE_256s, 1, "ef05677e26364711aad1e43afab403e8816247022cae57db5516c4444cd4485c"},
+		{SHAKE_256f, 3, "f488397ba6e7e7d045d1c978254fb75e9e2914a70ce73f7a1693b0434333209a"},
+	} {
+		t.Run(tt.p.Name(), func(t *testing.T) {
+			if tt.p.hp > 4 && testing.Short() {
+				t.Skip("slow parameter set in short mode")
+			}
+			t.Parallel()
+			testAccumulated(t, tt.p, tt.iterations, tt.expected)
+		})
+	}
+}
+
+func testAccumulated(t *testing.T, p *Parameters, iterations int, expected string) {
+	s := sha3.NewShake128()
+	o := sha3.NewShake128()
+	n := p.n
+	seeds := make([]byte, 3*n)
+	random := make([]byte, n)
+
+	for i := range iterations {
+		s.Read(seeds)
+		s.Read(random)
+		msg := make([]byte, i)
+		s.Read(msg)
+		priv, err := NewPrivateKeyFromSeeds(p, seeds[:n], seeds[n:2*n], seeds[2*n:])
+		if err != nil {
+			t.Fatalf("NewPrivateKeyFromSeeds: %v", err)
+		}
+		o.Write(priv.PublicKey().Bytes())
+		sig, err := TestingOnlySignWithRandom(priv, msg, "", random)
+		if err != nil {
+			t.Fatalf("TestingOnlySignWithRandom: %v", err)
+		}
+		o.Write(sig)
+
+		if len(sig) != p.SignatureSize() {
+			t.Fatalf("signature length = %d, want %d", len(sig), p.SignatureSize())
+		}
+		if err := Verify(priv.PublicKey(), msg, sig, ""); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		priv2, err := NewPrivateKey(p, priv.Bytes())
+		if err != nil {
+			t.Fatalf("NewPrivateKey: %v", err)
+		}
+		if !priv.Equal(priv2) {
+			t.Fatalf("re-parsed private key is not equal")
+		}
+	}
+
+	sum := make([]byte, 32)
+	o.Read(sum)
+	if got := hex.EncodeToString(sum); got != expected {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+}
+
+func TestCAST(t *testing.T) {
+	if err := fips140CAST(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignatureSize(t *testing.T) {
+	for _, tt := range []struct {
+		p    *Parameters
+		size int
+	}{
+		{SHA2_128s, 7856}, {SHAKE_128s, 7856},
+		{SHA2_128f, 17088}, {SHAKE_128f, 17088},
+		{SHA2_192s, 16224}, {SHAKE_192s, 16224},
+		{SHA2_192f, 35664}, {SHAKE_192f, 35664},
+		{SHA2_256s, 29792}, {SHAKE_256s, 29792},
+		{SHA2_256f, 49856}, {SHAKE_256f, 49856},
+	} {
+		if got := tt.p.SignatureSize(); got != tt.size {
+			t.Errorf("%s: SignatureSize() = %d, want %d", tt.p.Name(), got, tt.size)
+		}
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	for _, p := range []*Parameters{SHA2_128s, SHA2_128f, SHAKE_128s, SHAKE_128f} {
+		b.Run(p.Name(), func(b *testing.B) {
+			priv := GenerateKey(p)
+			msg := []byte("Hello, world!")
+			for b.Loop() {
+				if _, err := Sign(priv, msg, ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	for _, p := range []*Parameters{SHA2_128s, SHA2_128f, SHAKE_128s, SHAKE_128f} {
+		b.Run(p.Name(), func(b *testing.B) {
+			priv := GenerateKey(p)
+			msg := []byte("Hello, world!")
+			sig, err := Sign(priv, msg, "")
+			if err != nil {
+				b.Fatal(err)
+			}
+			for b.Loop() {
+				if err := Verify(priv.PublicKey(), msg, sig, ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
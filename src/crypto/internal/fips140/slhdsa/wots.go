@@ -0,0 +1,108 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slhdsa
+
+// WOTS+ one-time signatures, FIPS 205, Section 5.
+//
+// All parameter sets use lg_w = 4, so a message of n bytes is encoded
+// as len₁ = 2n base-16 digits, followed by a len₂ = 3 digit checksum.
+
+const (
+	w    = 16 // Winternitz parameter
+	len2 = 3
+
+	maxWOTSLen = 2*maxN + len2
+)
+
+func (p *Parameters) wotsLen() int {
+	return 2*p.n + len2
+}
+
+// wotsDigits computes the base-w digits of msg and of its checksum.
+func (p *Parameters) wotsDigits(msg []byte) (digits [maxWOTSLen]byte) {
+	var csum int
+	for i, b := range msg[:p.n] {
+		digits[2*i] = b >> 4
+		digits[2*i+1] = b & 0xf
+		csum += w - 1 - int(b>>4)
+		csum += w - 1 - int(b&0xf)
+	}
+	// The checksum is at most len₁ × (w - 1) < 2¹², so it is exactly the
+	// three base-16 digits of csum left-shifted by four bits and encoded
+	// as two bytes, as in Algorithms 7 and 8.
+	digits[2*p.n] = byte(csum >> 8 & 0xf)
+	digits[2*p.n+1] = byte(csum >> 4 & 0xf)
+	digits[2*p.n+2] = byte(csum & 0xf)
+	return digits
+}
+
+// chain applies F to x[:n] in place steps times, starting at index start.
+//
+// It implements Algorithm 5, chain.
+func (c *hashContext) chain(x []byte, start, steps int, adrs *address) {
+	for j := start; j < start+steps; j++ {
+		adrs.setHashAddress(uint32(j))
+		c.f(x, adrs, x[:c.p.n])
+	}
+}
+
+// wotsPKGen computes a WOTS+ public key into out[:n].
+//
+// It implements Algorithm 6, wots_pkGen.
+func (c *hashContext) wotsPKGen(out []byte, adrs address) {
+	n, wotsLen := c.p.n, c.p.wotsLen()
+	skADRS := adrs
+	skADRS.setTypeAndClear(addrWOTSPRF)
+	skADRS.setKeyPairAddress(adrs.keyPairAddress())
+	var tmp [maxWOTSLen * maxN]byte
+	for i := range wotsLen {
+		skADRS.setChainAddress(uint32(i))
+		x := tmp[i*n : (i+1)*n]
+		c.prf(x, &skADRS)
+		adrs.setChainAddress(uint32(i))
+		c.chain(x, 0, w-1, &adrs)
+	}
+	pkADRS := adrs
+	pkADRS.setTypeAndClear(addrWOTSPK)
+	pkADRS.setKeyPairAddress(adrs.keyPairAddress())
+	c.t(out, &pkADRS, tmp[:wotsLen*n])
+}
+
+// wotsSign computes a WOTS+ signature of msg[:n] into sig[:len×n].
+//
+// It implements Algorithm 7, wots_sign.
+func (c *hashContext) wotsSign(sig, msg []byte, adrs address) {
+	n := c.p.n
+	digits := c.p.wotsDigits(msg)
+	skADRS := adrs
+	skADRS.setTypeAndClear(addrWOTSPRF)
+	skADRS.setKeyPairAddress(adrs.keyPairAddress())
+	for i := range c.p.wotsLen() {
+		skADRS.setChainAddress(uint32(i))
+		x := sig[i*n : (i+1)*n]
+		c.prf(x, &skADRS)
+		adrs.setChainAddress(uint32(i))
+		c.chain(x, 0, int(digits[i]), &adrs)
+	}
+}
+
+// wotsPKFromSig computes a WOTS+ public key from a signature of msg[:n]
+// into out[:n]. out may overlap msg.
+//
+// It implements Algorithm 8, wots_pkFromSig.
+func (c *hashContext) wotsPKFromSig(out, sig, msg []byte, adrs address) {
+	n, wotsLen := c.p.n, c.p.wotsLen()
+	digits := c.p.wotsDigits(msg)
+	var tmp [maxWOTSLen * maxN]byte
+	copy(tmp[:], sig[:wotsLen*n])
+	for i := range wotsLen {
+		adrs.setChainAddress(uint32(i))
+		c.chain(tmp[i*n:(i+1)*n], int(digits[i]), w-1-int(digits[i]), &adrs)
+	}
+	pkADRS := adrs
+	pkADRS.setTypeAndClear(addrWOTSPK)
+	pkADRS.setKeyPairAddress(adrs.keyPairAddress())
+	c.t(out, &pkADRS, tmp[:wotsLen*n])
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slhdsa
+
+// XMSS and the SLH-DSA hypertree, FIPS 205, Sections 6 and 7.
+
+func (p *Parameters) xmssSigSize() int {
+	return (p.wotsLen() + p.hp) * p.n
+}
+
+// xmssNode computes into out[:n] the root of the subtree of height z whose
+// index among the subtrees of that height is i.
+//
+// It implements Algorithm 9, xmss_node.
+func (c *hashContext) xmssNode(out []byte, i, z uint32, adrs address) {
+	if z == 0 {
+		adrs.setTypeAndClear(addrWOTSHash)
+		adrs.setKeyPairAddress(i)
+		c.wotsPKGen(out, adrs)
+		return
+	}
+	n := c.p.n
+	var nodes [2 * maxN]byte
+	c.xmssNode(nodes[:n], 2*i, z-1, adrs)
+	c.xmssNode(nodes[n:2*n], 2*i+1, z-1, adrs)
+	adrs.setTypeAndClear(addrTree)
+	adrs.setTreeHeight(z)
+	adrs.setTreeIndex(i)
+	c.t(out, &adrs, nodes[:2*n])
+}
+
+// xmssSign computes an XMSS signature of msg[:n] with leaf idx into
+// sig[:(len+h')×n].
+//
+// It implements Algorithm 10, xmss_sign.
+func (c *hashContext) xmssSign(sig, msg []byte, idx uint32, adrs address) {
+	n := c.p.n
+	auth := sig[c.p.wotsLen()*n:]
+	for j := range c.p.hp {
+		k := (idx >> j) ^ 1
+		c.xmssNode(auth[j*n:(j+1)*n], k, uint32(j), adrs)
+	}
+	adrs.setTypeAndClear(addrWOTSHash)
+	adrs.setKeyPairAddress(idx)
+	c.wotsSign(sig, msg, adrs)
+}
+
+// xmssPKFromSig computes an XMSS public key from a signature of msg[:n]
+// with leaf idx into out[:n]. out may overlap msg.
+//
+// It implements Algorithm 11, xmss_pkFromSig.
+func (c *hashContext) xmssPKFromSig(out []byte, idx uint32, sig, msg []byte, adrs address) {
+	n := c.p.n
+	var node [2 * maxN]byte
+	adrs.setTypeAndClear(addrWOTSHash)
+	adrs.setKeyPairAddress(idx)
+	c.wotsPKFromSig(node[:n], sig, msg, adrs)
+	adrs.setTypeAndClear(addrTree)
+	auth := sig[c.p.wotsLen()*n:]
+	for k := range c.p.hp {
+		adrs.setTreeHeight(uint32(k + 1))
+		adrs.setTreeIndex(idx >> (k + 1))
+		if (idx>>k)&1 == 0 {
+			copy(node[n:2*n], auth[k*n:(k+1)*n])
+		} else {
+			copy(node[n:2*n], node[:n])
+			copy(node[:n], auth[k*n:(k+1)*n])
+		}
+		c.t(node[:n], &adrs, node[:2*n])
+	}
+	copy(out[:n], node[:n])
+}
+
+// htSign computes a hypertree signature of msg[:n] into sig[:d×(len+h')×n].
+//
+// It implements Algorithm 12, ht_sign.
+func (c *hashContext) htSign(sig, msg []byte, idxTree uint64, idxLeaf uint32) {
+	n, size := c.p.n, c.p.xmssSigSize()
+	var adrs address
+	adrs.setTreeAddress(idxTree)
+	var root [maxN]byte
+	copy(root[:n], msg)
+	for j := range c.p.d {
+		if j > 0 {
+			idxLeaf = uint32(idxTree & (1<<c.p.hp - 1))
+			idxTree >>= c.p.hp
+			adrs.setLayerAddress(uint32(j))
+			adrs.setTreeAddress(idxTree)
+		}
+		s := sig[j*size : (j+1)*size]
+		c.xmssSign(s, root[:n], idxLeaf, adrs)
+		if j < c.p.d-1 {
+			c.xmssPKFromSig(root[:n], idxLeaf, s, root[:n], adrs)
+		}
+	}
+}
+
+// htVerify reports whether sig is a valid hypertree signature of msg[:n].
+//
+// It implements Algorithm 13, ht_verify.
+func (c *hashContext) htVerify(msg, sig []byte, idxTree uint64, idxLeaf uint32, pkRoot []byte) bool {
+	n, size := c.p.n, c.p.xmssSigSize()
+	var adrs address
+	adrs.setTreeAddress(idxTree)
+	var node [maxN]byte
+	copy(node[:n], msg)
+	for j := range c.p.d {
+		if j > 0 {
+			idxLeaf = uint32(idxTree & (1<<c.p.hp - 1))
+			idxTree >>= c.p.hp
+			adrs.setLayerAddress(uint32(j))
+			adrs.setTreeAddress(idxTree)
+		}
+		c.xmssPKFromSig(node[:n], idxLeaf, sig[j*size:(j+1)*size], node[:n], adrs)
+	}
+	return string(node[:n]) == string(pkRoot[:n])
+}
@@ -10,6 +10,10 @@ func LEUint16(b []byte) uint16 {
 	return byteorder.LEUint16(b)
 }
 
+func LEUint32(b []byte) uint32 {
+	return byteorder.LEUint32(b)
+}
+
 func BEUint32(b []byte) uint32 {
 	return byteorder.BEUint32(b)
 }
@@ -38,6 +42,10 @@ func LEPutUint16(b []byte, v uint16) {
 	byteorder.LEPutUint16(b, v)
 }
 
+func LEPutUint32(b []byte, v uint32) {
+	byteorder.LEPutUint32(b, v)
+}
+
 func LEPutUint64(b []byte, v uint64) {
 	byteorder.LEPutUint64(b, v)
 }
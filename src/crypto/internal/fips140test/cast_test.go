@@ -59,6 +59,8 @@ var allCASTs = []string{
 	"RSASSA-PKCS-v1.5 2048-bit sign and verify",
 	"SHA2-256",
 	"SHA2-512",
+	"SLH-DSA sign and verify PCT",
+	"SLH-DSA-SHA2-128f",
 	"TLSv1.2-SHA2-256",
 	"TLSv1.3-SHA2-256",
 	"cSHAKE128",
@@ -70,6 +72,11 @@ func init() {
 			return strings.HasPrefix(s, "ML-DSA")
 		})
 	}
+	if fips140.Version() == "v1.0.0" || fips140.Version() == "v1.26.0" {
+		allCASTs = slices.DeleteFunc(allCASTs, func(s string) bool {
+			return strings.HasPrefix(s, "SLH-DSA")
+		})
+	}
 }
 
 func TestAllCASTs(t *testing.T) {
@@ -118,6 +125,7 @@ func TestConditionals(t *testing.T) {
 	moduleStatus(t)
 
 	fips140v126Conditionals()
+	slhdsaConditionals()
 	// ML-KEM PCT
 	kMLKEM, err := mlkem.GenerateKey768()
 	if err != nil {
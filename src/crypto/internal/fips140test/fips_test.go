@@ -104,6 +104,7 @@ func TestFIPS140(t *testing.T) {
 	fatalIfErr(t, err)
 
 	testFIPS140v126(t, plaintext)
+	testSLHDSA(t, plaintext)
 
 	t.Run("AES-CTR", func(t *testing.T) {
 		ensureServiceIndicator(t)
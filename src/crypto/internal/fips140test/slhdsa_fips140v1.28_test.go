@@ -0,0 +1,33 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(fips140v1.0 || fips140v1.26)
+
+package fipstest
+
+import (
+	"crypto/internal/fips140/slhdsa"
+	"testing"
+)
+
+func slhdsaConditionals() {
+	// SLH-DSA sign and verify PCT
+	kSLHDSA := slhdsa.GenerateKey(slhdsa.SHA2_128f)
+	// SLH-DSA-SHA2-128f
+	slhdsa.SignDeterministic(kSLHDSA, make([]byte, 32), "")
+}
+
+func testSLHDSA(t *testing.T, plaintext []byte) {
+	t.Run("SLH-DSA KeyGen, SigGen, SigVer", func(t *testing.T) {
+		ensureServiceIndicator(t)
+		k := slhdsa.GenerateKey(slhdsa.SHA2_128f)
+
+		sig, err := slhdsa.SignDeterministic(k, plaintext, "")
+		fatalIfErr(t, err)
+		t.Logf("SLH-DSA signature: %x", sig)
+
+		err = slhdsa.Verify(k.PublicKey(), plaintext, sig, "")
+		fatalIfErr(t, err)
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose_test
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/jose"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+func ExampleVerify() {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	key := &jose.Key{Key: priv, KeyID: "2026-01", Algorithm: string(jose.Ed25519)}
+
+	// The verifier usually fetches the public keys as a JWK Set.
+	jwks, err := json.Marshal(&jose.KeySet{Keys: []*jose.Key{key.Public()}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	jws, err := jose.Sign([]byte(`{"sub":"gopher"}`), jose.SigningKey{
+		Algorithm: jose.Ed25519,
+		Key:       key,
+		Header:    &jose.Header{Type: "JWT"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var keys jose.KeySet
+	if err := json.Unmarshal(jwks, &keys); err != nil {
+		log.Fatal(err)
+	}
+	payload, header, err := jose.Verify([]byte(jws), []jose.SignatureAlgorithm{jose.Ed25519}, &keys)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(header.KeyID, string(payload))
+	// Output: 2026-01 {"sub":"gopher"}
+}
+
+func ExampleDecrypt() {
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	jwe, err := jose.Encrypt([]byte("secret message"), jose.ECDHES, jose.A256GCM, priv.PublicKey(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	plaintext, _, err := jose.Decrypt([]byte(jwe), []jose.KeyAlgorithm{jose.ECDHES}, []jose.ContentEncryption{jose.A256GCM}, priv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(plaintext))
+	// Output: secret message
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package jose implements the JSON Object Signing and Encryption (JOSE)
+// formats: JSON Web Keys (JWK, RFC 7517), JSON Web Signatures (JWS, RFC 7515),
+// and JSON Web Encryption (JWE, RFC 7516), with the algorithms of RFC 7518,
+// RFC 8037, and draft-ietf-cose-dilithium.
+//
+// Keys are the types of the crypto packages, like [*ecdsa.PrivateKey] or
+// [ed25519.PublicKey], and are converted to and from JWKs with [Key].
+//
+// [Verify] and [Decrypt] take an explicit list of accepted algorithms, and
+// check that the key matches the algorithm in the header before using it.
+// This prevents algorithm confusion attacks, where an attacker picks an
+// algorithm the application didn't expect, like "none", or HMAC with an RSA
+// public key as the secret. The "none" algorithm is not supported at all.
+//
+// This package doesn't implement JSON Web Tokens (JWT, RFC 7519). A JWT is
+// a JWS or JWE whose payload is a JSON object of claims, which applications
+// can unmarshal and validate with [encoding/json].
+package jose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// Header is a JOSE Header, the set of parameters describing a JWS signature
+// or a JWE encryption. See RFC 7515, Section 4 and RFC 7516, Section 4.
+type Header struct {
+	// Algorithm is the "alg" parameter. It is set by the signing and
+	// encryption functions, and is ignored if set by the caller.
+	Algorithm string
+
+	// Encryption is the "enc" parameter of a JWE. It is set by [Encrypt],
+	// and is ignored if set by the caller.
+	Encryption string
+
+	// KeyID is the "kid" parameter, which identifies the key used to sign or
+	// encrypt. When verifying or decrypting with a [KeySet], it is used to
+	// select the key.
+	KeyID string
+
+	// Type is the "typ" parameter, the media type of the JWS or JWE, for
+	// example "JWT".
+	Type string
+
+	// ContentType is the "cty" parameter, the media type of the payload.
+	ContentType string
+
+	// Extra holds any other header parameters. When marshaling, values are
+	// encoded with [encoding/json]. When parsing, values are [json.RawMessage].
+	//
+	// The "crit" parameter is not supported, since this package doesn't
+	// implement any extension that would need it. Headers that include it are
+	// rejected when parsing.
+	Extra map[string]any
+}
+
+// registeredParams are the header parameters that are set by this package,
+// and can't be set in Header.Extra.
+var registeredParams = []string{"alg", "enc", "kid", "typ", "cty", "crit",
+	"epk", "apu", "apv", "zip", "b64"}
+
+func (h *Header) marshal(alg, enc string, internal map[string]any) ([]byte, error) {
+	m := make(map[string]any)
+	if h != nil {
+		for k, v := range h.Extra {
+			if slices.Contains(registeredParams, k) {
+				return nil, errors.New("jose: header parameter " + k + " can't be set in Header.Extra")
+			}
+			m[k] = v
+		}
+		if h.KeyID != "" {
+			m["kid"] = h.KeyID
+		}
+		if h.Type != "" {
+			m["typ"] = h.Type
+		}
+		if h.ContentType != "" {
+			m["cty"] = h.ContentType
+		}
+	}
+	m["alg"] = alg
+	if enc != "" {
+		m["enc"] = enc
+	}
+	for k, v := range internal {
+		m[k] = v
+	}
+	return json.Marshal(m)
+}
+
+// parseHeader parses one or more JSON objects, for example the protected and
+// unprotected headers of a JWS, which must not have parameters in common.
+// It also returns the raw parameters, for algorithm-specific processing.
+func parseHeader(objects ...[]byte) (*Header, map[string]json.RawMessage, error) {
+	raw := make(map[string]json.RawMessage)
+	for _, obj := range objects {
+		if obj == nil {
+			continue
+		}
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(obj, &m); err != nil {
+			return nil, nil, errors.New("jose: malformed header")
+		}
+		for k, v := range m {
+			if _, ok := raw[k]; ok {
+				return nil, nil, errors.New("jose: duplicate header parameter " + k)
+			}
+			raw[k] = v
+		}
+	}
+
+	h := &Header{}
+	for k, v := range raw {
+		var dst *string
+		switch k {
+		case "alg":
+			dst = &h.Algorithm
+		case "enc":
+			dst = &h.Encryption
+		case "kid":
+			dst = &h.KeyID
+		case "typ":
+			dst = &h.Type
+		case "cty":
+			dst = &h.ContentType
+		case "crit":
+			return nil, nil, errors.New("jose: unsupported critical header parameters")
+		case "zip":
+			return nil, nil, errors.New("jose: compressed payloads are not supported")
+		default:
+			if h.Extra == nil {
+				h.Extra = make(map[string]any)
+			}
+			h.Extra[k] = v
+			continue
+		}
+		if err := json.Unmarshal(v, dst); err != nil {
+			return nil, nil, errors.New("jose: malformed header parameter " + k)
+		}
+	}
+	if h.Algorithm == "" {
+		return nil, nil, errors.New("jose: missing alg header parameter")
+	}
+	return h, raw, nil
+}
+
+var b64 = base64.RawURLEncoding.Strict()
+
+func decodeB64(s string) ([]byte, error) {
+	return b64.DecodeString(s)
+}
+
+// base64Bytes is a []byte that is encoded in JSON as base64url without
+// padding, as required by JOSE.
+type base64Bytes []byte
+
+func (b base64Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b64.EncodeToString(b))
+}
+
+func (b *base64Bytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := decodeB64(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// containsAlg reports whether algs contains alg.
+func containsAlg[S ~string](algs []S, alg string) bool {
+	return slices.Contains(algs, S(alg))
+}
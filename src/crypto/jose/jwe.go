@@ -0,0 +1,329 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// KeyAlgorithm is a JWE "alg" value, which determines how the content
+// encryption key is derived or encrypted.
+type KeyAlgorithm string
+
+// Key management algorithms, from RFC 7518, Section 4.6.
+const (
+	// ECDHES is ECDH-ES in Direct Key Agreement mode: the content
+	// encryption key is derived from the shared secret.
+	ECDHES KeyAlgorithm = "ECDH-ES"
+
+	// ECDHESA128KW, ECDHESA192KW, and ECDHESA256KW are ECDH-ES with a
+	// random content encryption key, wrapped with AES Key Wrap using a key
+	// derived from the shared secret.
+	ECDHESA128KW KeyAlgorithm = "ECDH-ES+A128KW"
+	ECDHESA192KW KeyAlgorithm = "ECDH-ES+A192KW"
+	ECDHESA256KW KeyAlgorithm = "ECDH-ES+A256KW"
+)
+
+// keyWrapSize returns the size of the AES Key Wrap key, or zero for
+// Direct Key Agreement.
+func (alg KeyAlgorithm) keyWrapSize() int {
+	switch alg {
+	case ECDHESA128KW:
+		return 16
+	case ECDHESA192KW:
+		return 24
+	case ECDHESA256KW:
+		return 32
+	}
+	return 0
+}
+
+// ContentEncryption is a JWE "enc" value, the algorithm that encrypts the
+// plaintext.
+type ContentEncryption string
+
+// Content encryption algorithms, from RFC 7518, Section 5.3.
+const (
+	A128GCM ContentEncryption = "A128GCM"
+	A192GCM ContentEncryption = "A192GCM"
+	A256GCM ContentEncryption = "A256GCM"
+)
+
+func (enc ContentEncryption) keySize() int {
+	switch enc {
+	case A128GCM:
+		return 16
+	case A192GCM:
+		return 24
+	case A256GCM:
+		return 32
+	}
+	return 0
+}
+
+const (
+	gcmNonceSize = 12
+	gcmTagSize   = 16
+)
+
+// Encrypt encrypts plaintext to the recipient public key, and returns the JWE
+// Compact Serialization.
+//
+// key is an [*ecdh.PublicKey] on the X25519, P-256, P-384, or P-521 curves,
+// an [*ecdsa.PublicKey] on the NIST curves, or a [*Key] containing one of
+// those. If key is a *Key, its Algorithm must be empty or match alg, and its
+// KeyID is used as the default "kid" header parameter.
+//
+// h holds optional parameters for the protected header.
+func Encrypt(plaintext []byte, alg KeyAlgorithm, enc ContentEncryption, key any, h *Header) (string, error) {
+	if alg != ECDHES && alg.keyWrapSize() == 0 {
+		return "", errors.New("jose: unsupported key management algorithm " + string(alg))
+	}
+	if enc.keySize() == 0 {
+		return "", errors.New("jose: unsupported content encryption algorithm " + string(enc))
+	}
+	if jk, ok := key.(*Key); ok {
+		if jk.Algorithm != "" && jk.Algorithm != string(alg) {
+			return "", errors.New("jose: key can't be used with algorithm " + string(alg))
+		}
+		if jk.KeyID != "" && (h == nil || h.KeyID == "") {
+			hh := Header{KeyID: jk.KeyID}
+			if h != nil {
+				hh = *h
+				hh.KeyID = jk.KeyID
+			}
+			h = &hh
+		}
+		key = jk.Key
+	}
+	pub, err := ecdhPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	ephemeral, err := pub.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	z, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return "", err
+	}
+	epk, err := (&Key{Key: ephemeral.PublicKey()}).toJWK()
+	if err != nil {
+		return "", err
+	}
+	header, err := h.marshal(string(alg), string(enc), map[string]any{"epk": epk})
+	if err != nil {
+		return "", err
+	}
+	protected := b64.EncodeToString(header)
+
+	var cek, encryptedKey []byte
+	if alg == ECDHES {
+		cek = concatKDF(z, string(enc), nil, nil, enc.keySize())
+	} else {
+		kek := concatKDF(z, string(alg), nil, nil, alg.keyWrapSize())
+		cek = make([]byte, enc.keySize())
+		rand.Read(cek)
+		block, err := aes.NewCipher(kek)
+		if err != nil {
+			return "", err
+		}
+		encryptedKey, err = cipher.WrapKey(block, cek)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, gcmNonceSize)
+	rand.Read(iv)
+	sealed := aead.Seal(nil, iv, plaintext, []byte(protected))
+	ciphertext, tag := sealed[:len(plaintext)], sealed[len(plaintext):]
+
+	return strings.Join([]string{
+		protected,
+		b64.EncodeToString(encryptedKey),
+		b64.EncodeToString(iv),
+		b64.EncodeToString(ciphertext),
+		b64.EncodeToString(tag),
+	}, "."), nil
+}
+
+// Decrypt parses a JWE in the Compact Serialization, decrypts it, and returns
+// the plaintext and the header.
+//
+// Only JWEs using one of the key management algorithms in algs and one of the
+// content encryption algorithms in encs are accepted, and neither can be
+// empty.
+//
+// key is an [*ecdh.PrivateKey], an [*ecdsa.PrivateKey], a [*Key], or a
+// [*KeySet]. If key is a *KeySet, the keys matching the "kid" header parameter
+// are tried, or all the keys if the header has no "kid". Keys are only used
+// with the algorithm in their "alg" parameter, if any.
+func Decrypt(jwe []byte, algs []KeyAlgorithm, encs []ContentEncryption, key any) (plaintext []byte, header *Header, err error) {
+	if len(algs) == 0 || len(encs) == 0 {
+		return nil, nil, errors.New("jose: no allowed encryption algorithms")
+	}
+	parts := strings.Split(strings.TrimSpace(string(jwe)), ".")
+	if len(parts) != 5 {
+		return nil, nil, errors.New("jose: malformed JWE")
+	}
+	protected, err1 := decodeB64(parts[0])
+	encryptedKey, err2 := decodeB64(parts[1])
+	iv, err3 := decodeB64(parts[2])
+	ciphertext, err4 := decodeB64(parts[3])
+	tag, err5 := decodeB64(parts[4])
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		return nil, nil, errors.New("jose: malformed JWE")
+	}
+	h, raw, err := parseHeader(protected)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !containsAlg(algs, h.Algorithm) {
+		return nil, nil, errors.New("jose: key management algorithm " + h.Algorithm + " is not allowed")
+	}
+	if !containsAlg(encs, h.Encryption) {
+		return nil, nil, errors.New("jose: content encryption algorithm " + h.Encryption + " is not allowed")
+	}
+	alg, enc := KeyAlgorithm(h.Algorithm), ContentEncryption(h.Encryption)
+	if alg != ECDHES && alg.keyWrapSize() == 0 {
+		return nil, nil, errors.New("jose: unsupported key management algorithm " + h.Algorithm)
+	}
+	if enc.keySize() == 0 {
+		return nil, nil, errors.New("jose: unsupported content encryption algorithm " + h.Encryption)
+	}
+	if len(iv) != gcmNonceSize || len(tag) != gcmTagSize {
+		return nil, nil, errors.New("jose: malformed JWE")
+	}
+	if alg == ECDHES && len(encryptedKey) != 0 {
+		return nil, nil, errors.New("jose: unexpected encrypted key with ECDH-ES")
+	}
+
+	var epkKey Key
+	if raw["epk"] == nil {
+		return nil, nil, errors.New("jose: missing epk header parameter")
+	}
+	if err := json.Unmarshal(raw["epk"], &epkKey); err != nil {
+		return nil, nil, errors.New("jose: invalid epk header parameter")
+	}
+	epk, err := ecdhPublicKey(epkKey.Key)
+	if err != nil {
+		return nil, nil, errors.New("jose: invalid epk header parameter")
+	}
+	var apu, apv base64Bytes
+	for name, dst := range map[string]*base64Bytes{"apu": &apu, "apv": &apv} {
+		if v, ok := raw[name]; ok {
+			if err := json.Unmarshal(v, dst); err != nil {
+				return nil, nil, errors.New("jose: malformed " + name + " header parameter")
+			}
+		}
+	}
+
+	sealed := append(ciphertext, tag...)
+	for _, k := range candidateKeys(key, h.KeyID, h.Algorithm) {
+		priv, err := ecdhPrivateKey(k)
+		if err != nil || priv.Curve() != epk.Curve() {
+			continue
+		}
+		z, err := priv.ECDH(epk)
+		if err != nil {
+			continue
+		}
+		var cek []byte
+		if alg == ECDHES {
+			cek = concatKDF(z, string(enc), apu, apv, enc.keySize())
+		} else {
+			kek := concatKDF(z, string(alg), apu, apv, alg.keyWrapSize())
+			block, err := aes.NewCipher(kek)
+			if err != nil {
+				continue
+			}
+			cek, err = cipher.UnwrapKey(block, encryptedKey)
+			if err != nil || len(cek) != enc.keySize() {
+				continue
+			}
+		}
+		block, err := aes.NewCipher(cek)
+		if err != nil {
+			continue
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			continue
+		}
+		plaintext, err := aead.Open(nil, iv, sealed, []byte(parts[0]))
+		if err != nil {
+			continue
+		}
+		return plaintext, h, nil
+	}
+	return nil, nil, errors.New("jose: decryption failed")
+}
+
+func ecdhPublicKey(key any) (*ecdh.PublicKey, error) {
+	if k, ok := key.(interface{ Public() crypto.PublicKey }); ok {
+		key = k.Public()
+	}
+	switch k := key.(type) {
+	case *ecdh.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		return k.ECDH()
+	}
+	return nil, errors.New("jose: unsupported key type for ECDH-ES")
+}
+
+func ecdhPrivateKey(key any) (*ecdh.PrivateKey, error) {
+	switch k := key.(type) {
+	case *ecdh.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k.ECDH()
+	}
+	return nil, errors.New("jose: unsupported key type for ECDH-ES")
+}
+
+// concatKDF is the Concat KDF of NIST SP 800-56A, Section 5.8.1, with
+// SHA-256, as used by ECDH-ES. See RFC 7518, Section 4.6.2.
+func concatKDF(z []byte, algID string, apu, apv []byte, size int) []byte {
+	var otherInfo []byte
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(algID)))
+	otherInfo = append(otherInfo, algID...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(apu)))
+	otherInfo = append(otherInfo, apu...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(apv)))
+	otherInfo = append(otherInfo, apv...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(size*8))
+
+	var out []byte
+	for counter := uint32(1); len(out) < size; counter++ {
+		h := sha256.New()
+		h.Write(binary.BigEndian.AppendUint32(nil, counter))
+		h.Write(z)
+		h.Write(otherInfo)
+		out = h.Sum(out)
+	}
+	return out[:size]
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/jose"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// RFC 7518, Appendix C.
+func TestConcatKDFVector(t *testing.T) {
+	alice := mustUnmarshalKey(t, `{"kty":"EC",
+      "crv":"P-256",
+      "x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+      "y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+      "d":"0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo"
+     }`)
+	bob := mustUnmarshalKey(t, `{"kty":"EC",
+      "crv":"P-256",
+      "x":"weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ",
+      "y":"e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck",
+      "d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"
+     }`)
+	a, err := alice.Key.(*ecdsa.PrivateKey).ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := bob.Key.(*ecdsa.PrivateKey).ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	z, err := a.ECDH(b.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := jose.ConcatKDF(z, "A128GCM", []byte("Alice"), []byte("Bob"), 16)
+	if want := "VqqN6vgjbSBcIijNcacQGg"; base64.RawURLEncoding.EncodeToString(got) != want {
+		t.Errorf("ConcatKDF = %x, want %s", got, want)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	x25519, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	p256, _ := ecdh.P256().GenerateKey(rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdh.P521().GenerateKey(rand.Reader)
+	algs := []jose.KeyAlgorithm{jose.ECDHES, jose.ECDHESA128KW, jose.ECDHESA192KW, jose.ECDHESA256KW}
+	encs := []jose.ContentEncryption{jose.A128GCM, jose.A192GCM, jose.A256GCM}
+	plaintext := []byte("The true sign of intelligence is not knowledge but imagination.")
+
+	for _, key := range []interface {
+		Public() crypto.PublicKey
+	}{x25519, p256, p384, p521} {
+		for _, alg := range algs {
+			for _, enc := range encs {
+				h := &jose.Header{KeyID: "k", ContentType: "text/plain"}
+				jwe, err := jose.Encrypt(plaintext, alg, enc, key.Public(), h)
+				if err != nil {
+					t.Fatalf("%T %s %s: %v", key, alg, enc, err)
+				}
+				got, hh, err := jose.Decrypt([]byte(jwe), algs, encs, key)
+				if err != nil {
+					t.Fatalf("%T %s %s: %v", key, alg, enc, err)
+				}
+				if !bytes.Equal(got, plaintext) {
+					t.Errorf("%T %s %s: plaintext = %q", key, alg, enc, got)
+				}
+				if hh.Algorithm != string(alg) || hh.Encryption != string(enc) ||
+					hh.KeyID != "k" || hh.ContentType != "text/plain" {
+					t.Errorf("%T %s %s: unexpected header %+v", key, alg, enc, hh)
+				}
+
+				if _, _, err := jose.Decrypt([]byte(jwe), []jose.KeyAlgorithm{otherAlg(alg)}, encs, key); err == nil {
+					t.Errorf("%T %s %s: Decrypt accepted an algorithm not in the allowlist", key, alg, enc)
+				}
+				if _, _, err := jose.Decrypt([]byte(jwe), algs, []jose.ContentEncryption{otherEnc(enc)}, key); err == nil {
+					t.Errorf("%T %s %s: Decrypt accepted an encryption not in the allowlist", key, alg, enc)
+				}
+			}
+		}
+	}
+}
+
+func otherAlg(alg jose.KeyAlgorithm) jose.KeyAlgorithm {
+	if alg == jose.ECDHES {
+		return jose.ECDHESA256KW
+	}
+	return jose.ECDHES
+}
+
+func otherEnc(enc jose.ContentEncryption) jose.ContentEncryption {
+	if enc == jose.A128GCM {
+		return jose.A256GCM
+	}
+	return jose.A128GCM
+}
+
+func TestDecryptKeySet(t *testing.T) {
+	k1, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	k2, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	k3, _ := ecdh.P256().GenerateKey(rand.Reader)
+	set := &jose.KeySet{Keys: []*jose.Key{
+		{Key: k1, KeyID: "1"},
+		{Key: k2, KeyID: "2", Algorithm: string(jose.ECDHES)},
+		{Key: k3, KeyID: "3"},
+	}}
+	algs := []jose.KeyAlgorithm{jose.ECDHES, jose.ECDHESA128KW}
+	encs := []jose.ContentEncryption{jose.A128GCM}
+	for _, tt := range []struct {
+		key *jose.Key
+		alg jose.KeyAlgorithm
+		ok  bool
+	}{
+		{&jose.Key{Key: k1.PublicKey(), KeyID: "1"}, jose.ECDHES, true},
+		{&jose.Key{Key: k1.PublicKey()}, jose.ECDHESA128KW, true},
+		{&jose.Key{Key: k2.PublicKey(), KeyID: "2"}, jose.ECDHES, true},
+		// Key 2 is restricted to ECDH-ES.
+		{&jose.Key{Key: k2.PublicKey(), KeyID: "2"}, jose.ECDHESA128KW, false},
+		{&jose.Key{Key: k3.PublicKey(), KeyID: "1"}, jose.ECDHES, false},
+		{&jose.Key{Key: k3.PublicKey()}, jose.ECDHES, true},
+	} {
+		jwe, err := jose.Encrypt([]byte("x"), tt.alg, jose.A128GCM, tt.key, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, h, err := jose.Decrypt([]byte(jwe), algs, encs, set)
+		if (err == nil) != tt.ok {
+			t.Errorf("kid %q, %s: err = %v, want success %v", tt.key.KeyID, tt.alg, err, tt.ok)
+		}
+		if err == nil && h.KeyID != tt.key.KeyID {
+			t.Errorf("kid %q, %s: header kid = %q", tt.key.KeyID, tt.alg, h.KeyID)
+		}
+	}
+}
+
+func TestDecryptTampered(t *testing.T) {
+	key, _ := ecdh.P256().GenerateKey(rand.Reader)
+	algs := []jose.KeyAlgorithm{jose.ECDHES, jose.ECDHESA128KW}
+	encs := []jose.ContentEncryption{jose.A128GCM}
+	for _, alg := range algs {
+		jwe, err := jose.Encrypt([]byte("attack at dawn"), alg, jose.A128GCM, key.PublicKey(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts := strings.Split(jwe, ".")
+		for i := range parts {
+			if parts[i] == "" {
+				continue
+			}
+			tampered := make([]string, len(parts))
+			copy(tampered, parts)
+			b, _ := base64.RawURLEncoding.DecodeString(parts[i])
+			b[len(b)-1] ^= 1
+			tampered[i] = base64.RawURLEncoding.EncodeToString(b)
+			if _, _, err := jose.Decrypt([]byte(strings.Join(tampered, ".")), algs, encs, key); err == nil {
+				t.Errorf("%s: Decrypt accepted a modified part %d", alg, i)
+			}
+		}
+		if _, _, err := jose.Decrypt([]byte(jwe+"."), algs, encs, key); err == nil {
+			t.Errorf("%s: Decrypt accepted a malformed JWE", alg)
+		}
+
+		other, _ := ecdh.P256().GenerateKey(rand.Reader)
+		if _, _, err := jose.Decrypt([]byte(jwe), algs, encs, other); err == nil {
+			t.Errorf("%s: Decrypt succeeded with the wrong key", alg)
+		}
+	}
+
+	if _, err := jose.Encrypt([]byte("x"), "RSA-OAEP", jose.A128GCM, key.PublicKey(), nil); err == nil {
+		t.Errorf("Encrypt accepted an unsupported algorithm")
+	}
+	if _, err := jose.Encrypt([]byte("x"), jose.ECDHES, "A128CBC-HS256", key.PublicKey(), nil); err == nil {
+		t.Errorf("Encrypt accepted an unsupported encryption")
+	}
+}
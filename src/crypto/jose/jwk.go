@@ -0,0 +1,537 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/mldsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// Key is a JSON Web Key, as specified in RFC 7517.
+//
+// The following key types are supported:
+//
+//   - "EC" keys, as [*ecdsa.PublicKey] and [*ecdsa.PrivateKey], or
+//     [*ecdh.PublicKey] and [*ecdh.PrivateKey] on the NIST curves, for the
+//     P-256, P-384, and P-521 curves (RFC 7518, Section 6.2)
+//   - "RSA" keys, as [*rsa.PublicKey] and [*rsa.PrivateKey], with two primes
+//     (RFC 7518, Section 6.3)
+//   - "oct" keys, as []byte (RFC 7518, Section 6.4)
+//   - "OKP" keys, as [ed25519.PublicKey] and [ed25519.PrivateKey] for the
+//     Ed25519 curve, and [*ecdh.PublicKey] and [*ecdh.PrivateKey] for the
+//     X25519 curve (RFC 8037)
+//   - "AKP" keys, as [*mldsa.PublicKey] and [*mldsa.PrivateKey], where the
+//     Algorithm is the name of the parameter set (draft-ietf-cose-dilithium)
+//
+// EC keys are unmarshaled as ECDSA keys. Use [ecdsa.PublicKey.ECDH] and
+// [ecdsa.PrivateKey.ECDH] to convert them, although [Encrypt] and [Decrypt]
+// accept ECDSA keys directly.
+type Key struct {
+	// Key is the public, private, or symmetric key.
+	Key any
+
+	// KeyID is the "kid" parameter.
+	KeyID string
+
+	// Algorithm is the "alg" parameter. If set, the key can only be used
+	// with that algorithm. It is required for AKP keys.
+	Algorithm string
+
+	// Use is the "use" parameter, usually "sig" or "enc". It is informative
+	// only, and is not checked by this package.
+	Use string
+}
+
+// ErrUnsupportedKeyType is returned when unmarshaling a JWK whose type or
+// curve is not supported.
+var ErrUnsupportedKeyType = errors.New("jose: unsupported key type")
+
+// jwk is the JSON representation of a JWK.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+
+	Crv string      `json:"crv,omitempty"`
+	X   base64Bytes `json:"x,omitempty"`
+	Y   base64Bytes `json:"y,omitempty"`
+
+	N  base64Bytes `json:"n,omitempty"`
+	E  base64Bytes `json:"e,omitempty"`
+	P  base64Bytes `json:"p,omitempty"`
+	Q  base64Bytes `json:"q,omitempty"`
+	DP base64Bytes `json:"dp,omitempty"`
+	DQ base64Bytes `json:"dq,omitempty"`
+	QI base64Bytes `json:"qi,omitempty"`
+	// Oth is only used to reject multi-prime RSA keys.
+	Oth json.RawMessage `json:"oth,omitempty"`
+
+	K base64Bytes `json:"k,omitempty"`
+
+	Pub  base64Bytes `json:"pub,omitempty"`
+	Priv base64Bytes `json:"priv,omitempty"`
+
+	// D is the private exponent of RSA keys, and the private key of EC
+	// and OKP keys.
+	D base64Bytes `json:"d,omitempty"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (k *Key) MarshalJSON() ([]byte, error) {
+	j, err := k.toJWK()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+func (k *Key) toJWK() (*jwk, error) {
+	j := &jwk{Kid: k.KeyID, Alg: k.Algorithm, Use: k.Use}
+	switch key := k.Key.(type) {
+	case *ecdsa.PublicKey:
+		if err := j.setEC(key); err != nil {
+			return nil, err
+		}
+	case *ecdsa.PrivateKey:
+		if err := j.setEC(&key.PublicKey); err != nil {
+			return nil, err
+		}
+		d, err := key.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		j.D = d
+	case *ecdh.PublicKey:
+		if err := j.setECDH(key); err != nil {
+			return nil, err
+		}
+	case *ecdh.PrivateKey:
+		if err := j.setECDH(key.PublicKey()); err != nil {
+			return nil, err
+		}
+		j.D = key.Bytes()
+	case ed25519.PublicKey:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, errors.New("jose: invalid Ed25519 public key")
+		}
+		j.Kty, j.Crv, j.X = "OKP", "Ed25519", base64Bytes(key)
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, errors.New("jose: invalid Ed25519 private key")
+		}
+		j.Kty, j.Crv, j.X, j.D = "OKP", "Ed25519", base64Bytes(key.Public().(ed25519.PublicKey)), key.Seed()
+	case *rsa.PublicKey:
+		j.setRSA(key)
+	case *rsa.PrivateKey:
+		if len(key.Primes) != 2 {
+			return nil, errors.New("jose: multi-prime RSA keys are not supported")
+		}
+		j.setRSA(&key.PublicKey)
+		key.Precompute()
+		j.D = key.D.Bytes()
+		j.P, j.Q = key.Primes[0].Bytes(), key.Primes[1].Bytes()
+		j.DP, j.DQ, j.QI = key.Precomputed.Dp.Bytes(), key.Precomputed.Dq.Bytes(), key.Precomputed.Qinv.Bytes()
+	case []byte:
+		if len(key) == 0 {
+			return nil, errors.New("jose: empty symmetric key")
+		}
+		j.Kty, j.K = "oct", key
+	case *mldsa.PublicKey:
+		if err := j.setAKP(key.Parameters()); err != nil {
+			return nil, err
+		}
+		j.Pub = key.Bytes()
+	case *mldsa.PrivateKey:
+		if err := j.setAKP(key.PublicKey().Parameters()); err != nil {
+			return nil, err
+		}
+		j.Pub, j.Priv = key.PublicKey().Bytes(), key.Bytes()
+	default:
+		return nil, errors.New("jose: unsupported key type")
+	}
+	return j, nil
+}
+
+func (j *jwk) setEC(key *ecdsa.PublicKey) error {
+	crv, ok := curveName(key.Curve)
+	if !ok {
+		return errors.New("jose: unsupported ECDSA curve")
+	}
+	b, err := key.Bytes()
+	if err != nil {
+		return err
+	}
+	j.setCoordinates(crv, b)
+	return nil
+}
+
+func (j *jwk) setECDH(key *ecdh.PublicKey) error {
+	switch key.Curve() {
+	case ecdh.X25519():
+		j.Kty, j.Crv, j.X = "OKP", "X25519", key.Bytes()
+	case ecdh.P256():
+		j.setCoordinates("P-256", key.Bytes())
+	case ecdh.P384():
+		j.setCoordinates("P-384", key.Bytes())
+	case ecdh.P521():
+		j.setCoordinates("P-521", key.Bytes())
+	default:
+		return errors.New("jose: unsupported ECDH curve")
+	}
+	return nil
+}
+
+// setCoordinates sets an EC key from its uncompressed point encoding.
+func (j *jwk) setCoordinates(crv string, point []byte) {
+	size := (len(point) - 1) / 2
+	j.Kty, j.Crv, j.X, j.Y = "EC", crv, point[1:1+size], point[1+size:]
+}
+
+func (j *jwk) setRSA(key *rsa.PublicKey) {
+	j.Kty, j.N, j.E = "RSA", key.N.Bytes(), big.NewInt(int64(key.E)).Bytes()
+}
+
+func (j *jwk) setAKP(params mldsa.Parameters) error {
+	if j.Alg != "" && j.Alg != params.String() {
+		return errors.New("jose: Algorithm doesn't match the ML-DSA parameters")
+	}
+	j.Kty, j.Alg = "AKP", params.String()
+	return nil
+}
+
+func curveName(c elliptic.Curve) (string, bool) {
+	switch c {
+	case elliptic.P256():
+		return "P-256", true
+	case elliptic.P384():
+		return "P-384", true
+	case elliptic.P521():
+		return "P-521", true
+	}
+	return "", false
+}
+
+func curveByName(name string) (elliptic.Curve, int) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), 32
+	case "P-384":
+		return elliptic.P384(), 48
+	case "P-521":
+		return elliptic.P521(), 66
+	}
+	return nil, 0
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It returns an error wrapping
+// [ErrUnsupportedKeyType] if the key type or curve is not supported.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var j jwk
+	if err := json.Unmarshal(data, &j); err != nil {
+		return errors.New("jose: malformed JWK: " + err.Error())
+	}
+	key, err := j.key()
+	if err != nil {
+		return err
+	}
+	*k = Key{Key: key, KeyID: j.Kid, Algorithm: j.Alg, Use: j.Use}
+	return nil
+}
+
+func (j *jwk) key() (any, error) {
+	switch j.Kty {
+	case "EC":
+		return j.ecKey()
+	case "OKP":
+		return j.okpKey()
+	case "RSA":
+		return j.rsaKey()
+	case "oct":
+		if len(j.K) == 0 {
+			return nil, errors.New("jose: malformed oct JWK")
+		}
+		return []byte(j.K), nil
+	case "AKP":
+		return j.akpKey()
+	case "":
+		return nil, errors.New("jose: missing kty in JWK")
+	default:
+		return nil, errors.Join(ErrUnsupportedKeyType, errors.New("jose: unsupported kty "+j.Kty))
+	}
+}
+
+func (j *jwk) ecKey() (any, error) {
+	curve, size := curveByName(j.Crv)
+	if curve == nil {
+		return nil, errors.Join(ErrUnsupportedKeyType, errors.New("jose: unsupported EC curve "+j.Crv))
+	}
+	// RFC 7518, Section 6.2.1.2 requires the full coordinate size.
+	if len(j.X) != size || len(j.Y) != size {
+		return nil, errors.New("jose: malformed EC JWK")
+	}
+	point := append([]byte{4}, j.X...)
+	point = append(point, j.Y...)
+	pub, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+	if err != nil {
+		return nil, errors.New("jose: invalid EC JWK: " + err.Error())
+	}
+	if j.D == nil {
+		return pub, nil
+	}
+	if len(j.D) != size {
+		return nil, errors.New("jose: malformed EC JWK")
+	}
+	priv, err := ecdsa.ParseRawPrivateKey(curve, j.D)
+	if err != nil {
+		return nil, errors.New("jose: invalid EC JWK: " + err.Error())
+	}
+	if !priv.PublicKey.Equal(pub) {
+		return nil, errors.New("jose: EC JWK private key doesn't match public key")
+	}
+	return priv, nil
+}
+
+func (j *jwk) okpKey() (any, error) {
+	switch j.Crv {
+	case "Ed25519":
+		if len(j.X) != ed25519.PublicKeySize {
+			return nil, errors.New("jose: malformed Ed25519 JWK")
+		}
+		pub := ed25519.PublicKey(j.X)
+		if j.D == nil {
+			return pub, nil
+		}
+		if len(j.D) != ed25519.SeedSize {
+			return nil, errors.New("jose: malformed Ed25519 JWK")
+		}
+		priv := ed25519.NewKeyFromSeed(j.D)
+		if !pub.Equal(priv.Public()) {
+			return nil, errors.New("jose: Ed25519 JWK private key doesn't match public key")
+		}
+		return priv, nil
+	case "X25519":
+		pub, err := ecdh.X25519().NewPublicKey(j.X)
+		if err != nil {
+			return nil, errors.New("jose: invalid X25519 JWK: " + err.Error())
+		}
+		if j.D == nil {
+			return pub, nil
+		}
+		priv, err := ecdh.X25519().NewPrivateKey(j.D)
+		if err != nil {
+			return nil, errors.New("jose: invalid X25519 JWK: " + err.Error())
+		}
+		if !pub.Equal(priv.PublicKey()) {
+			return nil, errors.New("jose: X25519 JWK private key doesn't match public key")
+		}
+		return priv, nil
+	default:
+		return nil, errors.Join(ErrUnsupportedKeyType, errors.New("jose: unsupported OKP curve "+j.Crv))
+	}
+}
+
+func (j *jwk) rsaKey() (any, error) {
+	if len(j.N) == 0 || len(j.E) == 0 || len(j.E) > 4 {
+		return nil, errors.New("jose: malformed RSA JWK")
+	}
+	e := new(big.Int).SetBytes(j.E).Int64()
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(j.N), E: int(e)}
+	if j.D == nil {
+		return pub, nil
+	}
+	if j.Oth != nil {
+		return nil, errors.Join(ErrUnsupportedKeyType, errors.New("jose: multi-prime RSA keys are not supported"))
+	}
+	if j.P == nil || j.Q == nil {
+		return nil, errors.New("jose: RSA JWK without primes is not supported")
+	}
+	priv := &rsa.PrivateKey{
+		PublicKey: *pub,
+		D:         new(big.Int).SetBytes(j.D),
+		Primes:    []*big.Int{new(big.Int).SetBytes(j.P), new(big.Int).SetBytes(j.Q)},
+	}
+	// Like crypto/x509.ParsePKCS1PrivateKey, recompute the CRT values rather
+	// than trusting the encoded ones, but reject keys where they don't match.
+	priv.Precompute()
+	if err := priv.Validate(); err != nil {
+		return nil, errors.New("jose: invalid RSA JWK: " + err.Error())
+	}
+	if j.DP != nil && new(big.Int).SetBytes(j.DP).Cmp(priv.Precomputed.Dp) != 0 ||
+		j.DQ != nil && new(big.Int).SetBytes(j.DQ).Cmp(priv.Precomputed.Dq) != 0 ||
+		j.QI != nil && new(big.Int).SetBytes(j.QI).Cmp(priv.Precomputed.Qinv) != 0 {
+		return nil, errors.New("jose: invalid RSA JWK: inconsistent CRT values")
+	}
+	return priv, nil
+}
+
+func (j *jwk) akpKey() (any, error) {
+	var params mldsa.Parameters
+	switch j.Alg {
+	case "ML-DSA-44":
+		params = mldsa.MLDSA44()
+	case "ML-DSA-65":
+		params = mldsa.MLDSA65()
+	case "ML-DSA-87":
+		params = mldsa.MLDSA87()
+	default:
+		return nil, errors.Join(ErrUnsupportedKeyType, errors.New("jose: unsupported AKP algorithm "+j.Alg))
+	}
+	pub, err := mldsa.NewPublicKey(params, j.Pub)
+	if err != nil {
+		return nil, errors.New("jose: invalid AKP JWK: " + err.Error())
+	}
+	if j.Priv == nil {
+		return pub, nil
+	}
+	priv, err := mldsa.NewPrivateKey(params, j.Priv)
+	if err != nil {
+		return nil, errors.New("jose: invalid AKP JWK: " + err.Error())
+	}
+	if !pub.Equal(priv.PublicKey()) {
+		return nil, errors.New("jose: AKP JWK private key doesn't match public key")
+	}
+	return priv, nil
+}
+
+// Public returns the public key corresponding to k, with the same KeyID,
+// Algorithm, and Use. It returns nil for symmetric keys.
+func (k *Key) Public() *Key {
+	var pub any
+	switch key := k.Key.(type) {
+	case []byte:
+		return nil
+	case interface{ Public() crypto.PublicKey }:
+		pub = key.Public()
+	default:
+		pub = key
+	}
+	return &Key{Key: pub, KeyID: k.KeyID, Algorithm: k.Algorithm, Use: k.Use}
+}
+
+// Thumbprint returns the JWK Thumbprint of k, as specified in RFC 7638, using
+// hash h, usually [crypto.SHA256]. The thumbprint of a private key is the
+// thumbprint of its public key.
+func (k *Key) Thumbprint(h crypto.Hash) ([]byte, error) {
+	if !h.Available() {
+		return nil, errors.New("jose: hash function is not available")
+	}
+	j, err := k.toJWK()
+	if err != nil {
+		return nil, err
+	}
+	// The required members, in lexicographic order, without whitespace. The
+	// AKP members are from draft-ietf-cose-dilithium.
+	var b []byte
+	member := func(name string, value any) {
+		if b == nil {
+			b = append(b, '{')
+		} else {
+			b = append(b, ',')
+		}
+		v, _ := json.Marshal(value)
+		b = append(b, '"')
+		b = append(b, name...)
+		b = append(b, '"', ':')
+		b = append(b, v...)
+	}
+	switch j.Kty {
+	case "EC":
+		member("crv", j.Crv)
+		member("kty", j.Kty)
+		member("x", j.X)
+		member("y", j.Y)
+	case "OKP":
+		member("crv", j.Crv)
+		member("kty", j.Kty)
+		member("x", j.X)
+	case "RSA":
+		member("e", j.E)
+		member("kty", j.Kty)
+		member("n", j.N)
+	case "oct":
+		member("k", j.K)
+		member("kty", j.Kty)
+	case "AKP":
+		member("alg", j.Alg)
+		member("kty", j.Kty)
+		member("pub", j.Pub)
+	}
+	b = append(b, '}')
+
+	hh := h.New()
+	hh.Write(b)
+	return hh.Sum(nil), nil
+}
+
+// KeySet is a JWK Set, as specified in RFC 7517, Section 5.
+type KeySet struct {
+	Keys []*Key
+}
+
+type jwkSet struct {
+	Keys []json.RawMessage `json:"keys"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (s *KeySet) MarshalJSON() ([]byte, error) {
+	var set jwkSet
+	set.Keys = make([]json.RawMessage, 0, len(s.Keys))
+	for _, k := range s.Keys {
+		b, err := k.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, b)
+	}
+	return json.Marshal(set)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. Keys with an unsupported type
+// or curve are skipped, as recommended by RFC 7517, Section 5. Other invalid
+// keys cause an error.
+func (s *KeySet) UnmarshalJSON(data []byte) error {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return errors.New("jose: malformed JWK Set: " + err.Error())
+	}
+	if set.Keys == nil {
+		return errors.New("jose: malformed JWK Set: missing keys member")
+	}
+	keys := make([]*Key, 0, len(set.Keys))
+	for _, raw := range set.Keys {
+		k := new(Key)
+		if err := k.UnmarshalJSON(raw); errors.Is(err, ErrUnsupportedKeyType) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	s.Keys = keys
+	return nil
+}
+
+// lookup returns the keys of s that match kid, or all keys if kid is empty.
+func (s *KeySet) lookup(kid string) []*Key {
+	if kid == "" {
+		return s.Keys
+	}
+	var keys []*Key
+	for _, k := range s.Keys {
+		if k.KeyID == kid {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
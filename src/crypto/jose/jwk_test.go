@@ -0,0 +1,266 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose_test
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/jose"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// RFC 7638, Section 3.1.
+func TestThumbprintRSAVector(t *testing.T) {
+	key := mustUnmarshalKey(t, `{
+      "kty": "RSA",
+      "n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAt`+
+		`VT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn6`+
+		`4tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FD`+
+		`W2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n9`+
+		`1CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINH`+
+		`aQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+      "e": "AQAB",
+      "alg": "RS256",
+      "kid": "2011-04-29"
+     }`)
+	if key.KeyID != "2011-04-29" || key.Algorithm != "RS256" {
+		t.Errorf("unexpected key parameters %+v", key)
+	}
+	tp, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := base64.RawURLEncoding.EncodeToString(tp), "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("Thumbprint = %s, want %s", got, want)
+	}
+}
+
+// RFC 8037, Appendix A.3.
+func TestThumbprintEd25519Vector(t *testing.T) {
+	key := mustUnmarshalKey(t, `{"kty":"OKP","crv":"Ed25519",
+   "d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+   "x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`)
+	if _, ok := key.Key.(ed25519.PrivateKey); !ok {
+		t.Fatalf("unexpected key type %T", key.Key)
+	}
+	tp, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := base64.RawURLEncoding.EncodeToString(tp), "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"; got != want {
+		t.Errorf("Thumbprint = %s, want %s", got, want)
+	}
+	tpPub, err := key.Public().Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(tpPub) != string(tp) {
+		t.Errorf("public key thumbprint doesn't match private key thumbprint")
+	}
+}
+
+func keyEqual(a, b any) bool {
+	switch a := a.(type) {
+	case interface{ Equal(crypto.PrivateKey) bool }:
+		return a.Equal(b)
+	case interface{ Equal(crypto.PublicKey) bool }:
+		return a.Equal(b)
+	}
+	return false
+}
+
+func TestKeyRoundTrip(t *testing.T) {
+	keys := testSigningKeys(t)
+	for _, alg := range []jose.SignatureAlgorithm{jose.EdDSA,
+		jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512} {
+		delete(keys, alg)
+	}
+	var all []any
+	for _, k := range keys {
+		all = append(all, k)
+		if _, ok := k.(crypto.Signer); ok {
+			all = append(all, publicKey(k))
+		}
+	}
+	x25519, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	p384, _ := ecdh.P384().GenerateKey(rand.Reader)
+	all = append(all, x25519, x25519.PublicKey(), p384, p384.PublicKey())
+
+	for _, k := range all {
+		key := &jose.Key{Key: k, KeyID: "kid", Use: "sig"}
+		data, err := json.Marshal(key)
+		if err != nil {
+			t.Fatalf("%T: %v", k, err)
+		}
+		got := new(jose.Key)
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatalf("%T: %v\n%s", k, err, data)
+		}
+		if got.KeyID != "kid" || got.Use != "sig" {
+			t.Errorf("%T: unexpected parameters %+v", k, got)
+		}
+		switch want := k.(type) {
+		case []byte:
+			if string(got.Key.([]byte)) != string(want) {
+				t.Errorf("%T: key mismatch", k)
+			}
+		case *ecdh.PrivateKey, *ecdh.PublicKey:
+			// NIST ECDH keys are unmarshaled as ECDSA keys.
+			if p, ok := got.Key.(*ecdsa.PrivateKey); ok {
+				got.Key, _ = p.ECDH()
+			} else if p, ok := got.Key.(*ecdsa.PublicKey); ok {
+				got.Key, _ = p.ECDH()
+			}
+			if !keyEqual(want, got.Key) {
+				t.Errorf("%T: key mismatch, got %T", k, got.Key)
+			}
+		default:
+			if !keyEqual(want, got.Key) {
+				t.Errorf("%T: key mismatch, got %T", k, got.Key)
+			}
+		}
+
+		// Marshaling is stable.
+		data2, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != string(data2) {
+			t.Errorf("%T: remarshaled JWK differs:\n%s\n%s", k, data, data2)
+		}
+
+		// The thumbprint doesn't depend on private or optional parameters.
+		tp, err := key.Thumbprint(crypto.SHA256)
+		if _, ok := k.([]byte); ok {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%T: %v", k, err)
+		}
+		tpPub, err := (&jose.Key{Key: publicKey(k)}).Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("%T: %v", k, err)
+		}
+		if string(tp) != string(tpPub) {
+			t.Errorf("%T: thumbprint differs from public key thumbprint", k)
+		}
+	}
+}
+
+func TestKeyPrivateParameters(t *testing.T) {
+	priv := testRSAKey()
+	data, err := json.Marshal(&jose.Key{Key: priv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"n", "e", "d", "p", "q", "dp", "dq", "qi"} {
+		if _, ok := m[p]; !ok {
+			t.Errorf("missing RSA parameter %q", p)
+		}
+	}
+	data, err = json.Marshal(&jose.Key{Key: &priv.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"d"`) {
+		t.Errorf("public JWK contains private parameters: %s", data)
+	}
+
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if !reflect.DeepEqual((&jose.Key{Key: ec, KeyID: "x"}).Public(), &jose.Key{Key: &ec.PublicKey, KeyID: "x"}) {
+		t.Errorf("unexpected Public result")
+	}
+	if (&jose.Key{Key: []byte("secret")}).Public() != nil {
+		t.Errorf("Public of a symmetric key is not nil")
+	}
+}
+
+func TestKeyUnmarshalInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name, jwk   string
+		unsupported bool
+	}{
+		{"unknown kty", `{"kty":"XYZ"}`, true},
+		{"unknown crv", `{"kty":"EC","crv":"secp256k1","x":"AA","y":"AA"}`, true},
+		{"unknown OKP crv", `{"kty":"OKP","crv":"Ed448","x":"AA"}`, true},
+		{"missing kty", `{"x":"AA"}`, false},
+		{"short coordinate", `{"kty":"EC","crv":"P-256",
+			"x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SQ",
+			"y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps"}`, false},
+		{"off curve", `{"kty":"EC","crv":"P-256",
+			"x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+			"y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFppt"}`, false},
+		{"mismatched EC private key", `{"kty":"EC","crv":"P-256",
+			"x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+			"y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+			"d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"}`, false},
+		{"mismatched Ed25519 private key", `{"kty":"OKP","crv":"Ed25519",
+			"d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+			"x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0"}`, false},
+		{"padded base64", `{"kty":"oct","k":"AAAA=="}`, false},
+		{"empty oct", `{"kty":"oct","k":""}`, false},
+		{"RSA other primes", `{"kty":"RSA","n":"AQAB","e":"AQAB","d":"AQ","oth":[]}`, true},
+	} {
+		err := json.Unmarshal([]byte(tt.jwk), new(jose.Key))
+		if err == nil {
+			t.Errorf("%s: Unmarshal succeeded", tt.name)
+			continue
+		}
+		if errors.Is(err, jose.ErrUnsupportedKeyType) != tt.unsupported {
+			t.Errorf("%s: err = %v, want ErrUnsupportedKeyType %v", tt.name, err, tt.unsupported)
+		}
+	}
+
+	if _, err := json.Marshal(&jose.Key{Key: "not a key"}); err == nil {
+		t.Errorf("Marshal accepted an invalid key")
+	}
+	if _, err := json.Marshal(&jose.Key{Key: &rsa.PrivateKey{}}); err == nil {
+		t.Errorf("Marshal accepted an empty RSA key")
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	set := new(jose.KeySet)
+	err := json.Unmarshal([]byte(`{"keys":[
+		{"kty":"OKP","crv":"Ed25519","kid":"a","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+		{"kty":"XYZ","kid":"b"},
+		{"kty":"EC","crv":"secp256k1","kid":"c","x":"AA","y":"AA"},
+		{"kty":"oct","kid":"d","k":"AAAA"}
+	]}`), set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 || set.Keys[0].KeyID != "a" || set.Keys[1].KeyID != "d" {
+		t.Fatalf("unexpected keys %+v", set.Keys)
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"keys":[{"kty":"OKP","kid":"a","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},{"kty":"oct","kid":"d","k":"AAAA"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	// Malformed supported keys are not skipped.
+	err = json.Unmarshal([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AA"}]}`), set)
+	if err == nil {
+		t.Errorf("Unmarshal accepted a malformed key")
+	}
+}
@@ -0,0 +1,501 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/mldsa"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// SignatureAlgorithm is a JWS "alg" value.
+type SignatureAlgorithm string
+
+// Signature algorithms, from RFC 7518, Section 3, RFC 8037, RFC 9864, and
+// draft-ietf-cose-dilithium.
+const (
+	HS256 SignatureAlgorithm = "HS256" // HMAC using SHA-256
+	HS384 SignatureAlgorithm = "HS384" // HMAC using SHA-384
+	HS512 SignatureAlgorithm = "HS512" // HMAC using SHA-512
+
+	RS256 SignatureAlgorithm = "RS256" // RSASSA-PKCS1-v1_5 using SHA-256
+	RS384 SignatureAlgorithm = "RS384" // RSASSA-PKCS1-v1_5 using SHA-384
+	RS512 SignatureAlgorithm = "RS512" // RSASSA-PKCS1-v1_5 using SHA-512
+
+	PS256 SignatureAlgorithm = "PS256" // RSASSA-PSS using SHA-256
+	PS384 SignatureAlgorithm = "PS384" // RSASSA-PSS using SHA-384
+	PS512 SignatureAlgorithm = "PS512" // RSASSA-PSS using SHA-512
+
+	ES256 SignatureAlgorithm = "ES256" // ECDSA using P-256 and SHA-256
+	ES384 SignatureAlgorithm = "ES384" // ECDSA using P-384 and SHA-384
+	ES512 SignatureAlgorithm = "ES512" // ECDSA using P-521 and SHA-512
+
+	// EdDSA is the polymorphic EdDSA algorithm of RFC 8037. Only Ed25519
+	// keys are supported. New applications should use [Ed25519].
+	EdDSA   SignatureAlgorithm = "EdDSA"
+	Ed25519 SignatureAlgorithm = "Ed25519" // Ed25519, from RFC 9864
+
+	MLDSA44 SignatureAlgorithm = "ML-DSA-44" // ML-DSA-44
+	MLDSA65 SignatureAlgorithm = "ML-DSA-65" // ML-DSA-65
+	MLDSA87 SignatureAlgorithm = "ML-DSA-87" // ML-DSA-87
+)
+
+// rsaMinBits is the minimum RSA key size, from RFC 7518, Sections 3.3 and 3.5.
+const rsaMinBits = 2048
+
+func (alg SignatureAlgorithm) hash() crypto.Hash {
+	switch alg {
+	case HS256, RS256, PS256, ES256:
+		return crypto.SHA256
+	case HS384, RS384, PS384, ES384:
+		return crypto.SHA384
+	case HS512, RS512, PS512, ES512:
+		return crypto.SHA512
+	}
+	return 0
+}
+
+func (alg SignatureAlgorithm) curve() elliptic.Curve {
+	switch alg {
+	case ES256:
+		return elliptic.P256()
+	case ES384:
+		return elliptic.P384()
+	case ES512:
+		return elliptic.P521()
+	}
+	return nil
+}
+
+func (alg SignatureAlgorithm) mldsaParameters() mldsa.Parameters {
+	switch alg {
+	case MLDSA44:
+		return mldsa.MLDSA44()
+	case MLDSA65:
+		return mldsa.MLDSA65()
+	case MLDSA87:
+		return mldsa.MLDSA87()
+	}
+	return mldsa.Parameters{}
+}
+
+// SigningKey is a key and algorithm to produce a JWS signature with.
+type SigningKey struct {
+	// Algorithm is the signature algorithm.
+	Algorithm SignatureAlgorithm
+
+	// Key is a []byte for the HMAC algorithms, a [crypto.Signer] with a
+	// public key matching Algorithm, or a [*Key] containing one of those.
+	//
+	// If Key is a *Key, its Algorithm must be empty or match Algorithm, and
+	// its KeyID is used as the default "kid" header parameter.
+	Key any
+
+	// Header holds optional parameters for the protected header.
+	Header *Header
+}
+
+// Sign signs payload and returns the JWS Compact Serialization.
+func Sign(payload []byte, key SigningKey) (string, error) {
+	protected, sig, err := key.sign(payload)
+	if err != nil {
+		return "", err
+	}
+	return protected + "." + b64.EncodeToString(payload) + "." + b64.EncodeToString(sig), nil
+}
+
+type jwsJSON struct {
+	Payload    *string          `json:"payload"`
+	Signatures []*jwsSignature  `json:"signatures,omitempty"`
+	Protected  *string          `json:"protected,omitempty"`
+	Header     *json.RawMessage `json:"header,omitempty"`
+	Signature  *string          `json:"signature,omitempty"`
+}
+
+type jwsSignature struct {
+	Protected *string          `json:"protected,omitempty"`
+	Header    *json.RawMessage `json:"header,omitempty"`
+	Signature *string          `json:"signature"`
+}
+
+// SignJSON signs payload with one or more keys, and returns the JWS General
+// JSON Serialization. All header parameters are in the protected headers.
+func SignJSON(payload []byte, keys ...SigningKey) ([]byte, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("jose: no signing keys")
+	}
+	encodedPayload := b64.EncodeToString(payload)
+	out := jwsJSON{Payload: &encodedPayload}
+	for _, key := range keys {
+		protected, sig, err := key.sign(payload)
+		if err != nil {
+			return nil, err
+		}
+		encodedSig := b64.EncodeToString(sig)
+		out.Signatures = append(out.Signatures, &jwsSignature{Protected: &protected, Signature: &encodedSig})
+	}
+	return json.Marshal(out)
+}
+
+// sign returns the encoded protected header and the signature.
+func (k SigningKey) sign(payload []byte) (string, []byte, error) {
+	key := k.Key
+	h := k.Header
+	if jk, ok := key.(*Key); ok {
+		if jk.Algorithm != "" && jk.Algorithm != string(k.Algorithm) {
+			return "", nil, errors.New("jose: key can't be used with algorithm " + string(k.Algorithm))
+		}
+		if jk.KeyID != "" && (h == nil || h.KeyID == "") {
+			hh := Header{KeyID: jk.KeyID}
+			if h != nil {
+				hh = *h
+				hh.KeyID = jk.KeyID
+			}
+			h = &hh
+		}
+		key = jk.Key
+	}
+	header, err := h.marshal(string(k.Algorithm), "", nil)
+	if err != nil {
+		return "", nil, err
+	}
+	protected := b64.EncodeToString(header)
+	input := signingInput(protected, b64.EncodeToString(payload))
+	sig, err := sign(k.Algorithm, key, input)
+	if err != nil {
+		return "", nil, err
+	}
+	return protected, sig, nil
+}
+
+func signingInput(protected, payload string) []byte {
+	return []byte(protected + "." + payload)
+}
+
+func sign(alg SignatureAlgorithm, key any, input []byte) ([]byte, error) {
+	errKey := errors.New("jose: key can't be used with algorithm " + string(alg))
+	switch alg {
+	case HS256, HS384, HS512:
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, errKey
+		}
+		if len(secret) < alg.hash().Size() {
+			return nil, errors.New("jose: HMAC key is too short for algorithm " + string(alg))
+		}
+		mac := hmac.New(alg.hash().New, secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errKey
+	}
+	switch alg {
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		pub, ok := signer.Public().(*rsa.PublicKey)
+		if !ok {
+			return nil, errKey
+		}
+		if pub.N.BitLen() < rsaMinBits {
+			return nil, errors.New("jose: RSA key is too small")
+		}
+		var opts crypto.SignerOpts = alg.hash()
+		if alg[0] == 'P' {
+			opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: alg.hash()}
+		}
+		return signer.Sign(rand.Reader, digest(alg.hash(), input), opts)
+	case ES256, ES384, ES512:
+		pub, ok := signer.Public().(*ecdsa.PublicKey)
+		if !ok || pub.Curve != alg.curve() {
+			return nil, errKey
+		}
+		sig, err := signer.Sign(rand.Reader, digest(alg.hash(), input), alg.hash())
+		if err != nil {
+			return nil, err
+		}
+		return ecdsaASN1ToRaw(sig, (pub.Curve.Params().BitSize+7)/8)
+	case EdDSA, Ed25519:
+		if _, ok := signer.Public().(ed25519.PublicKey); !ok {
+			return nil, errKey
+		}
+		return signer.Sign(rand.Reader, input, crypto.Hash(0))
+	case MLDSA44, MLDSA65, MLDSA87:
+		pub, ok := signer.Public().(*mldsa.PublicKey)
+		if !ok || pub.Parameters() != alg.mldsaParameters() {
+			return nil, errKey
+		}
+		return signer.Sign(rand.Reader, input, crypto.Hash(0))
+	default:
+		return nil, errors.New("jose: unsupported signature algorithm " + string(alg))
+	}
+}
+
+func digest(h crypto.Hash, input []byte) []byte {
+	hh := h.New()
+	hh.Write(input)
+	return hh.Sum(nil)
+}
+
+// ecdsaASN1ToRaw converts an ASN.1 ECDSA signature to the fixed-size
+// concatenation of r and s, as required by RFC 7518, Section 3.4.
+func ecdsaASN1ToRaw(sig []byte, size int) ([]byte, error) {
+	var r, s []byte
+	input := cryptobyte.String(sig)
+	var inner cryptobyte.String
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) || !input.Empty() ||
+		!inner.ReadASN1Integer(&r) || !inner.ReadASN1Integer(&s) || !inner.Empty() {
+		return nil, errors.New("jose: invalid ECDSA signature from signer")
+	}
+	r, s = bytes.TrimLeft(r, "\x00"), bytes.TrimLeft(s, "\x00")
+	if len(r) > size || len(s) > size {
+		return nil, errors.New("jose: invalid ECDSA signature from signer")
+	}
+	out := make([]byte, 2*size)
+	copy(out[size-len(r):size], r)
+	copy(out[2*size-len(s):], s)
+	return out, nil
+}
+
+// Verify parses a JWS in the Compact Serialization, or in the General or
+// Flattened JSON Serialization, verifies it, and returns the payload and the
+// header of the verified signature.
+//
+// Only signatures using one of the algorithms in algs are considered, and
+// algs must not be empty. The "alg" header parameter must be in the protected
+// header.
+//
+// key is a []byte for the HMAC algorithms, a public key type supported by
+// [Key], a [*Key], or a [*KeySet]. A private key can be used in place of its
+// public key. If key is a *KeySet, the keys matching the "kid" header
+// parameter are tried, or all the keys if the header has no "kid". Keys are
+// only used with algorithms they are compatible with, and with the
+// algorithm in their "alg" parameter, if any.
+//
+// If the JWS has multiple signatures, it is accepted if any of them is valid.
+func Verify(jws []byte, algs []SignatureAlgorithm, key any) (payload []byte, header *Header, err error) {
+	if len(algs) == 0 {
+		return nil, nil, errors.New("jose: no allowed signature algorithms")
+	}
+	sigs, payload, err := parseJWS(jws)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = errors.New("jose: signature verification failed")
+	for _, s := range sigs {
+		if !containsAlg(algs, s.header.Algorithm) {
+			err = errors.New("jose: signature algorithm " + s.header.Algorithm + " is not allowed")
+			continue
+		}
+		alg := SignatureAlgorithm(s.header.Algorithm)
+		for _, k := range candidateKeys(key, s.header.KeyID, string(alg)) {
+			if verify(alg, k, s.input, s.signature) == nil {
+				return payload, s.header, nil
+			}
+		}
+	}
+	return nil, nil, err
+}
+
+// candidateKeys returns the keys to try for a signature or encryption with
+// the given "kid" and "alg" header parameters.
+func candidateKeys(key any, kid, alg string) []any {
+	var keys []*Key
+	switch k := key.(type) {
+	case *KeySet:
+		keys = k.lookup(kid)
+	case *Key:
+		keys = []*Key{k}
+	default:
+		return []any{key}
+	}
+	var out []any
+	for _, k := range keys {
+		if k.Algorithm == "" || k.Algorithm == alg {
+			out = append(out, k.Key)
+		}
+	}
+	return out
+}
+
+type parsedSignature struct {
+	header    *Header
+	input     []byte
+	signature []byte
+}
+
+func parseJWS(jws []byte) ([]parsedSignature, []byte, error) {
+	jws = bytes.TrimSpace(jws)
+	if len(jws) > 0 && jws[0] == '{' {
+		return parseJWSJSON(jws)
+	}
+
+	parts := strings.Split(string(jws), ".")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("jose: malformed JWS")
+	}
+	payload, err1 := decodeB64(parts[1])
+	sig, err2 := decodeB64(parts[2])
+	if err1 != nil || err2 != nil {
+		return nil, nil, errors.New("jose: malformed JWS")
+	}
+	h, err := parseProtectedHeader(parts[0], nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []parsedSignature{{h, signingInput(parts[0], parts[1]), sig}}, payload, nil
+}
+
+func parseJWSJSON(data []byte) ([]parsedSignature, []byte, error) {
+	var j jwsJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, nil, errors.New("jose: malformed JWS JSON Serialization")
+	}
+	if j.Payload == nil {
+		return nil, nil, errors.New("jose: JWS with detached payload is not supported")
+	}
+	payload, err := decodeB64(*j.Payload)
+	if err != nil {
+		return nil, nil, errors.New("jose: malformed JWS payload")
+	}
+	flattened := j.Protected != nil || j.Header != nil || j.Signature != nil
+	if flattened == (j.Signatures != nil) {
+		return nil, nil, errors.New("jose: malformed JWS JSON Serialization")
+	}
+	if flattened {
+		j.Signatures = []*jwsSignature{{Protected: j.Protected, Header: j.Header, Signature: j.Signature}}
+	}
+
+	var sigs []parsedSignature
+	for _, s := range j.Signatures {
+		if s == nil || s.Protected == nil || s.Signature == nil {
+			return nil, nil, errors.New("jose: malformed JWS JSON Serialization")
+		}
+		sig, err := decodeB64(*s.Signature)
+		if err != nil {
+			return nil, nil, errors.New("jose: malformed JWS signature")
+		}
+		var unprotected []byte
+		if s.Header != nil {
+			unprotected = *s.Header
+		}
+		h, err := parseProtectedHeader(*s.Protected, unprotected)
+		if err != nil {
+			return nil, nil, err
+		}
+		sigs = append(sigs, parsedSignature{h, signingInput(*s.Protected, *j.Payload), sig})
+	}
+	return sigs, payload, nil
+}
+
+// parseProtectedHeader parses an encoded protected header and an optional
+// unprotected header, and checks that "alg" is protected.
+func parseProtectedHeader(encoded string, unprotected []byte) (*Header, error) {
+	protected, err := decodeB64(encoded)
+	if err != nil {
+		return nil, errors.New("jose: malformed protected header")
+	}
+	h, _, err := parseHeader(protected, unprotected)
+	if err != nil {
+		return nil, err
+	}
+	var p struct {
+		Alg *string `json:"alg"`
+	}
+	if err := json.Unmarshal(protected, &p); err != nil || p.Alg == nil {
+		return nil, errors.New("jose: alg header parameter is not protected")
+	}
+	return h, nil
+}
+
+func verify(alg SignatureAlgorithm, key any, input, sig []byte) error {
+	errKey := errors.New("jose: key can't be used with algorithm " + string(alg))
+	if k, ok := key.(interface{ Public() crypto.PublicKey }); ok {
+		key = k.Public()
+	}
+	switch alg {
+	case HS256, HS384, HS512:
+		secret, ok := key.([]byte)
+		if !ok {
+			return errKey
+		}
+		if len(secret) < alg.hash().Size() {
+			return errors.New("jose: HMAC key is too short for algorithm " + string(alg))
+		}
+		mac := hmac.New(alg.hash().New, secret)
+		mac.Write(input)
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return errors.New("jose: invalid signature")
+		}
+		return nil
+	case RS256, RS384, RS512:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errKey
+		}
+		if pub.N.BitLen() < rsaMinBits {
+			return errors.New("jose: RSA key is too small")
+		}
+		return rsa.VerifyPKCS1v15(pub, alg.hash(), digest(alg.hash(), input), sig)
+	case PS256, PS384, PS512:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errKey
+		}
+		if pub.N.BitLen() < rsaMinBits {
+			return errors.New("jose: RSA key is too small")
+		}
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+		return rsa.VerifyPSS(pub, alg.hash(), digest(alg.hash(), input), sig, opts)
+	case ES256, ES384, ES512:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve != alg.curve() {
+			return errKey
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("jose: invalid signature")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest(alg.hash(), input), r, s) {
+			return errors.New("jose: invalid signature")
+		}
+		return nil
+	case EdDSA, Ed25519:
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || len(pub) != ed25519.PublicKeySize {
+			return errKey
+		}
+		if !ed25519.Verify(pub, input, sig) {
+			return errors.New("jose: invalid signature")
+		}
+		return nil
+	case MLDSA44, MLDSA65, MLDSA87:
+		pub, ok := key.(*mldsa.PublicKey)
+		if !ok || pub.Parameters() != alg.mldsaParameters() {
+			return errKey
+		}
+		return mldsa.Verify(pub, input, sig, nil)
+	default:
+		return errors.New("jose: unsupported signature algorithm " + string(alg))
+	}
+}
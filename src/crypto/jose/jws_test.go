@@ -0,0 +1,367 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/jose"
+	"crypto/mldsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func mustUnmarshalKey(t *testing.T, s string) *jose.Key {
+	t.Helper()
+	k := new(jose.Key)
+	if err := json.Unmarshal([]byte(s), k); err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// RFC 7515, Appendix A.1.
+func TestVerifyHS256Vector(t *testing.T) {
+	key := mustUnmarshalKey(t, `{"kty":"oct",
+      "k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"
+     }`)
+	jws := "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	payload, h, err := jose.Verify([]byte(jws), []jose.SignatureAlgorithm{jose.HS256}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Type != "JWT" || h.Algorithm != "HS256" {
+		t.Errorf("unexpected header %+v", h)
+	}
+	if !strings.HasPrefix(string(payload), `{"iss":"joe",`) {
+		t.Errorf("unexpected payload %q", payload)
+	}
+
+	if _, _, err := jose.Verify([]byte(jws), []jose.SignatureAlgorithm{jose.HS384}, key); err == nil {
+		t.Errorf("Verify accepted an algorithm not in the allowlist")
+	}
+	if _, _, err := jose.Verify([]byte(jws), nil, key); err == nil {
+		t.Errorf("Verify accepted an empty allowlist")
+	}
+}
+
+// RFC 7515, Appendix A.3.
+func TestVerifyES256Vector(t *testing.T) {
+	key := mustUnmarshalKey(t, `{"kty":"EC",
+      "crv":"P-256",
+      "x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+      "y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"
+     }`)
+	jws := "eyJhbGciOiJFUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q"
+	if _, _, err := jose.Verify([]byte(jws), []jose.SignatureAlgorithm{jose.ES256}, key); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// RFC 8037, Appendix A.4.
+func TestSignEdDSAVector(t *testing.T) {
+	key := mustUnmarshalKey(t, `{"kty":"OKP","crv":"Ed25519",
+   "d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+   "x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`)
+	want := "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc" +
+		".hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+	got, err := jose.Sign([]byte("Example of Ed25519 signing"), jose.SigningKey{Algorithm: jose.EdDSA, Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+	if _, _, err := jose.Verify([]byte(want), []jose.SignatureAlgorithm{jose.EdDSA}, key.Public()); err != nil {
+		t.Error(err)
+	}
+}
+
+var testRSAKey = sync.OnceValue(func() *rsa.PrivateKey {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return k
+})
+
+func testSigningKeys(t *testing.T) map[jose.SignatureAlgorithm]any {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, ed, _ := ed25519.GenerateKey(rand.Reader)
+	keys := map[jose.SignatureAlgorithm]any{
+		jose.HS256:   bytes.Repeat([]byte{1}, 32),
+		jose.HS384:   bytes.Repeat([]byte{2}, 48),
+		jose.HS512:   bytes.Repeat([]byte{3}, 64),
+		jose.RS256:   testRSAKey(),
+		jose.RS384:   testRSAKey(),
+		jose.RS512:   testRSAKey(),
+		jose.PS256:   testRSAKey(),
+		jose.PS384:   testRSAKey(),
+		jose.PS512:   testRSAKey(),
+		jose.ES256:   p256,
+		jose.ES384:   p384,
+		jose.ES512:   p521,
+		jose.EdDSA:   ed,
+		jose.Ed25519: ed,
+	}
+	for alg, params := range map[jose.SignatureAlgorithm]mldsa.Parameters{
+		jose.MLDSA44: mldsa.MLDSA44(),
+		jose.MLDSA65: mldsa.MLDSA65(),
+		jose.MLDSA87: mldsa.MLDSA87(),
+	} {
+		k, err := mldsa.GenerateKey(params)
+		if err != nil {
+			t.Logf("skipping %s: %v", alg, err)
+			continue
+		}
+		keys[alg] = k
+	}
+	return keys
+}
+
+func publicKey(k any) any {
+	if s, ok := k.(crypto.Signer); ok {
+		return s.Public()
+	}
+	return k
+}
+
+func TestSignVerify(t *testing.T) {
+	keys := testSigningKeys(t)
+	payload := []byte(`{"sub":"gopher"}`)
+	for alg, key := range keys {
+		t.Run(string(alg), func(t *testing.T) {
+			h := &jose.Header{Type: "JWT", KeyID: "k1", Extra: map[string]any{"x-custom": 42}}
+			jws, err := jose.Sign(payload, jose.SigningKey{Algorithm: alg, Key: key, Header: h})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, hh, err := jose.Verify([]byte(jws), []jose.SignatureAlgorithm{alg}, publicKey(key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Errorf("payload = %q, want %q", got, payload)
+			}
+			if hh.Algorithm != string(alg) || hh.Type != "JWT" || hh.KeyID != "k1" ||
+				string(hh.Extra["x-custom"].(json.RawMessage)) != "42" {
+				t.Errorf("unexpected header %+v", hh)
+			}
+
+			// Modify the payload.
+			parts := strings.Split(jws, ".")
+			parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`))
+			if _, _, err := jose.Verify([]byte(strings.Join(parts, ".")), []jose.SignatureAlgorithm{alg}, publicKey(key)); err == nil {
+				t.Errorf("Verify accepted a modified payload")
+			}
+
+			// Keys of other algorithms must be rejected.
+			for other, otherKey := range keys {
+				if other == alg || sameKeyType(other, alg) {
+					continue
+				}
+				if _, _, err := jose.Verify([]byte(jws), []jose.SignatureAlgorithm{alg}, publicKey(otherKey)); err == nil {
+					t.Errorf("Verify accepted a %s key", other)
+				}
+				if _, err := jose.Sign(payload, jose.SigningKey{Algorithm: alg, Key: otherKey}); err == nil {
+					t.Errorf("Sign accepted a %s key", other)
+				}
+			}
+		})
+	}
+}
+
+func sameKeyType(a, b jose.SignatureAlgorithm) bool {
+	family := func(alg jose.SignatureAlgorithm) string {
+		switch alg {
+		case jose.HS256, jose.HS384, jose.HS512:
+			return "HMAC"
+		case jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512:
+			return "RSA"
+		case jose.EdDSA, jose.Ed25519:
+			return "Ed25519"
+		}
+		return string(alg)
+	}
+	return family(a) == family(b)
+}
+
+func TestSignJSON(t *testing.T) {
+	keys := testSigningKeys(t)
+	payload := []byte("hello")
+	jws, err := jose.SignJSON(payload,
+		jose.SigningKey{Algorithm: jose.ES256, Key: keys[jose.ES256], Header: &jose.Header{KeyID: "ec"}},
+		jose.SigningKey{Algorithm: jose.Ed25519, Key: &jose.Key{Key: keys[jose.Ed25519], KeyID: "ed"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for alg, kid := range map[jose.SignatureAlgorithm]string{jose.ES256: "ec", jose.Ed25519: "ed"} {
+		got, h, err := jose.Verify(jws, []jose.SignatureAlgorithm{alg}, publicKey(keys[alg]))
+		if err != nil {
+			t.Fatalf("%s: %v", alg, err)
+		}
+		if !bytes.Equal(got, payload) || h.KeyID != kid {
+			t.Errorf("%s: got %q, %+v", alg, got, h)
+		}
+	}
+
+	// Flattened JSON Serialization.
+	var general struct {
+		Payload    string `json:"payload"`
+		Signatures []struct {
+			Protected string `json:"protected"`
+			Signature string `json:"signature"`
+		} `json:"signatures"`
+	}
+	if err := json.Unmarshal(jws, &general); err != nil {
+		t.Fatal(err)
+	}
+	flattened, _ := json.Marshal(map[string]any{
+		"payload":   general.Payload,
+		"protected": general.Signatures[1].Protected,
+		"header":    map[string]string{"x-unprotected": "yes"},
+		"signature": general.Signatures[1].Signature,
+	})
+	_, h, err := jose.Verify(flattened, []jose.SignatureAlgorithm{jose.Ed25519}, publicKey(keys[jose.Ed25519]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(h.Extra["x-unprotected"].(json.RawMessage)) != `"yes"` {
+		t.Errorf("unprotected header not parsed: %+v", h)
+	}
+}
+
+func TestVerifyKeySet(t *testing.T) {
+	keys := testSigningKeys(t)
+	set := &jose.KeySet{Keys: []*jose.Key{
+		{Key: publicKey(keys[jose.ES256]), KeyID: "a", Algorithm: "ES256"},
+		{Key: publicKey(keys[jose.ES384]), KeyID: "b"},
+		{Key: publicKey(keys[jose.Ed25519]), KeyID: "c", Algorithm: "Ed25519"},
+	}}
+	algs := []jose.SignatureAlgorithm{jose.ES256, jose.ES384, jose.EdDSA, jose.Ed25519}
+	for _, tt := range []struct {
+		alg jose.SignatureAlgorithm
+		kid string
+		ok  bool
+	}{
+		{jose.ES256, "a", true},
+		{jose.ES256, "", true},
+		{jose.ES256, "b", false},
+		{jose.ES384, "b", true},
+		{jose.Ed25519, "c", true},
+		// The key is restricted to Ed25519 by its alg parameter.
+		{jose.EdDSA, "c", false},
+	} {
+		jws, err := jose.Sign([]byte("x"), jose.SigningKey{Algorithm: tt.alg, Key: keys[tt.alg], Header: &jose.Header{KeyID: tt.kid}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = jose.Verify([]byte(jws), algs, set)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s with kid %q: err = %v, want success %v", tt.alg, tt.kid, err, tt.ok)
+		}
+	}
+}
+
+func TestVerifyAlgorithmConfusion(t *testing.T) {
+	rsaKey := testRSAKey()
+	all := []jose.SignatureAlgorithm{jose.HS256, jose.RS256}
+	payload := b64(`{"admin":true}`)
+
+	// HS256 signed with the DER of the RSA public key as the secret must
+	// not verify with the RSA public key.
+	secret := rsaKey.PublicKey.N.Bytes()
+	header := b64(`{"alg":"HS256"}`)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(header + "." + payload))
+	jws := header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if _, _, err := jose.Verify([]byte(jws), all, &rsaKey.PublicKey); err == nil {
+		t.Errorf("Verify accepted HS256 with an RSA public key")
+	}
+
+	// The "none" algorithm is never accepted.
+	none := b64(`{"alg":"none"}`) + "." + payload + "."
+	if _, _, err := jose.Verify([]byte(none), []jose.SignatureAlgorithm{"none"}, &rsaKey.PublicKey); err == nil {
+		t.Errorf("Verify accepted alg none")
+	}
+
+	// alg must be protected.
+	sig, err := jose.Sign([]byte("x"), jose.SigningKey{Algorithm: jose.RS256, Key: rsaKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(sig, ".")
+	unprotected, _ := json.Marshal(map[string]any{
+		"payload": parts[1], "protected": b64(`{}`),
+		"header": map[string]string{"alg": "RS256"}, "signature": parts[2],
+	})
+	if _, _, err := jose.Verify(unprotected, all, &rsaKey.PublicKey); err == nil {
+		t.Errorf("Verify accepted an unprotected alg")
+	}
+
+	// Critical extensions are rejected.
+	crit := b64(`{"alg":"HS256","crit":["exp"],"exp":1}`)
+	mac = hmac.New(sha256.New, bytes.Repeat([]byte{1}, 32))
+	mac.Write([]byte(crit + "." + payload))
+	jws = crit + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if _, _, err := jose.Verify([]byte(jws), all, bytes.Repeat([]byte{1}, 32)); err == nil {
+		t.Errorf("Verify accepted a crit header")
+	}
+
+	// HMAC keys shorter than the hash are rejected.
+	if _, err := jose.Sign([]byte("x"), jose.SigningKey{Algorithm: jose.HS256, Key: []byte("short")}); err == nil {
+		t.Errorf("Sign accepted a short HMAC key")
+	}
+
+	// Keys restricted to another algorithm are rejected.
+	if _, err := jose.Sign([]byte("x"), jose.SigningKey{Algorithm: jose.PS256, Key: &jose.Key{Key: rsaKey, Algorithm: "RS256"}}); err == nil {
+		t.Errorf("Sign accepted a key restricted to another algorithm")
+	}
+}
+
+func b64(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	valid, err := jose.Sign([]byte("x"), jose.SigningKey{Algorithm: jose.HS256, Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, jws := range []string{
+		"",
+		"a.b",
+		valid + ".",
+		valid + "=",
+		"!" + valid,
+		b64(`{"alg":"HS256","alg":"HS256"}`) + valid[strings.Index(valid, "."):],
+		`{"payload":"eA","signatures":[]}`,
+		`{"signatures":[{"protected":"eyJhbGciOiJIUzI1NiJ9","signature":""}]}`,
+		`{"payload":"eA","protected":"eyJhbGciOiJIUzI1NiJ9","signatures":[]}`,
+		`{"payload":"eA","signatures":[{"protected":"eyJhbGciOiJIUzI1NiJ9"}]}`,
+	} {
+		if _, _, err := jose.Verify([]byte(jws), []jose.SignatureAlgorithm{jose.HS256}, key); err == nil {
+			t.Errorf("Verify(%q) succeeded", jws)
+		}
+	}
+}
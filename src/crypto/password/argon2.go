@@ -0,0 +1,354 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strconv"
+	"sync"
+)
+
+// Argon2id are the parameters of the Argon2id password hashing function,
+// specified in RFC 9106.
+//
+// Hashes are encoded as PHC strings, for example
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+//
+// with a 16-byte salt and a 32-byte hash.
+type Argon2id struct {
+	// Memory is the memory size in KiB. It must be at least 8*Threads.
+	Memory uint32
+
+	// Time is the number of passes over the memory. It must be at least 1.
+	Time uint32
+
+	// Threads is the degree of parallelism. It must be at least 1.
+	Threads uint8
+}
+
+const (
+	argon2Version    = 0x13
+	argon2idType     = 2
+	argon2SyncPoints = 4
+	argon2BlockWords = 128 // 1024-byte blocks
+
+	argon2SaltSize = 16
+	argon2KeySize  = 32
+)
+
+func (p Argon2id) check() error {
+	if p.Threads < 1 {
+		return errors.New("password: Argon2id Threads must be at least 1")
+	}
+	if p.Time < 1 {
+		return errors.New("password: Argon2id Time must be at least 1")
+	}
+	if p.Memory < 8*uint32(p.Threads) {
+		return errors.New("password: Argon2id Memory must be at least 8*Threads KiB")
+	}
+	return nil
+}
+
+func (p Argon2id) hash(password string) (string, error) {
+	if err := p.check(); err != nil {
+		return "", err
+	}
+	salt, err := randomSalt(argon2SaltSize)
+	if err != nil {
+		return "", err
+	}
+	key := argon2id([]byte(password), salt, nil, nil, p.Time, p.Memory, p.Threads, argon2KeySize)
+	return p.encode(salt, key), nil
+}
+
+func (p Argon2id) encode(salt, key []byte) string {
+	b := []byte("$argon2id$v=")
+	b = strconv.AppendInt(b, argon2Version, 10)
+	b = append(b, "$m="...)
+	b = strconv.AppendUint(b, uint64(p.Memory), 10)
+	b = append(b, ",t="...)
+	b = strconv.AppendUint(b, uint64(p.Time), 10)
+	b = append(b, ",p="...)
+	b = strconv.AppendUint(b, uint64(p.Threads), 10)
+	return string(appendSaltAndHash(b, salt, key))
+}
+
+func (p Argon2id) needsRehash(h *phcHash) bool {
+	q, ok := h.params.(Argon2id)
+	return !ok || q != p || len(h.salt) != argon2SaltSize || len(h.hash) != argon2KeySize
+}
+
+// parseArgon2id parses the version and parameters of an Argon2id PHC string.
+func parseArgon2id(h *phcHash, version string, params []phcParam) error {
+	if version != "v=19" {
+		return errors.New("password: unsupported Argon2id version")
+	}
+	var p Argon2id
+	if len(params) != 3 || params[0].name != "m" || params[1].name != "t" || params[2].name != "p" {
+		return errors.New("password: malformed Argon2id parameters")
+	}
+	m, err1 := strconv.ParseUint(params[0].value, 10, 32)
+	t, err2 := strconv.ParseUint(params[1].value, 10, 32)
+	threads, err3 := strconv.ParseUint(params[2].value, 10, 8)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return errors.New("password: malformed Argon2id parameters")
+	}
+	p.Memory, p.Time, p.Threads = uint32(m), uint32(t), uint8(threads)
+	if err := p.check(); err != nil {
+		return err
+	}
+	// RFC 9106, Section 3.1 requires a tag of at least 4 bytes.
+	if len(h.hash) < 4 {
+		return errors.New("password: Argon2id hash is too short")
+	}
+	h.params = p
+	h.derive = func(password []byte) []byte {
+		return argon2id(password, h.salt, nil, nil, p.Time, p.Memory, p.Threads, uint32(len(h.hash)))
+	}
+	return nil
+}
+
+type argon2Block [argon2BlockWords]uint64
+
+// argon2id computes Argon2id as specified in RFC 9106, Section 3, with the
+// optional secret and associated data.
+func argon2id(password, salt, secret, data []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+	lanes := uint32(threads)
+
+	// Compute H_0. See RFC 9106, Section 3.2.
+	h := newBlake2b(blake2bSize)
+	var tmp [4]byte
+	writeUint32 := func(v uint32) {
+		binary.LittleEndian.PutUint32(tmp[:], v)
+		h.write(tmp[:])
+	}
+	writeUint32(lanes)
+	writeUint32(keyLen)
+	writeUint32(memory)
+	writeUint32(time)
+	writeUint32(argon2Version)
+	writeUint32(argon2idType)
+	for _, b := range [][]byte{password, salt, secret, data} {
+		writeUint32(uint32(len(b)))
+		h.write(b)
+	}
+	h0 := h.sum(make([]byte, 0, blake2bSize+8))[:blake2bSize+8]
+
+	// The memory is rounded down to a multiple of 4*p blocks.
+	memory = memory / (argon2SyncPoints * lanes) * (argon2SyncPoints * lanes)
+	laneLength := memory / lanes
+	segmentLength := laneLength / argon2SyncPoints
+
+	// Compute the first two blocks of each lane.
+	B := make([]argon2Block, memory)
+	var block [1024]byte
+	for lane := range lanes {
+		binary.LittleEndian.PutUint32(h0[blake2bSize+4:], lane)
+		for i := range uint32(2) {
+			binary.LittleEndian.PutUint32(h0[blake2bSize:], i)
+			argon2Hash(block[:], h0)
+			for j := range B[lane*laneLength+i] {
+				B[lane*laneLength+i][j] = binary.LittleEndian.Uint64(block[8*j:])
+			}
+		}
+	}
+
+	processSegment := func(pass, slice, lane uint32) {
+		// Argon2id uses data-independent addressing in the first half of the
+		// first pass, and data-dependent addressing afterwards.
+		dataIndependent := pass == 0 && slice < argon2SyncPoints/2
+		var addresses, input, zero argon2Block
+		if dataIndependent {
+			input[0] = uint64(pass)
+			input[1] = uint64(lane)
+			input[2] = uint64(slice)
+			input[3] = uint64(memory)
+			input[4] = uint64(time)
+			input[5] = argon2idType
+		}
+		nextAddresses := func() {
+			input[6]++
+			argon2Compress(&addresses, &input, &zero, false)
+			argon2Compress(&addresses, &addresses, &zero, false)
+		}
+
+		index := uint32(0)
+		if pass == 0 && slice == 0 {
+			index = 2 // the first two blocks are already computed
+			if dataIndependent {
+				nextAddresses()
+			}
+		}
+		offset := lane*laneLength + slice*segmentLength + index
+		for ; index < segmentLength; index, offset = index+1, offset+1 {
+			prev := offset - 1
+			if index == 0 && slice == 0 {
+				prev += laneLength // the last block of the lane
+			}
+			var random uint64
+			if dataIndependent {
+				if index%argon2BlockWords == 0 {
+					nextAddresses()
+				}
+				random = addresses[index%argon2BlockWords]
+			} else {
+				random = B[prev][0]
+			}
+			ref := argon2RefIndex(random, laneLength, segmentLength, lanes, pass, slice, lane, index)
+			argon2Compress(&B[offset], &B[prev], &B[ref], pass > 0)
+		}
+	}
+
+	for pass := range time {
+		for slice := range uint32(argon2SyncPoints) {
+			var wg sync.WaitGroup
+			for lane := range lanes {
+				wg.Go(func() { processSegment(pass, slice, lane) })
+			}
+			wg.Wait()
+		}
+	}
+
+	// XOR the last blocks of all lanes, and hash the result.
+	final := B[memory-1]
+	for lane := range lanes - 1 {
+		for i, v := range B[lane*laneLength+laneLength-1] {
+			final[i] ^= v
+		}
+	}
+	for i, v := range final {
+		binary.LittleEndian.PutUint64(block[8*i:], v)
+	}
+	key := make([]byte, keyLen)
+	argon2Hash(key, block[:])
+	return key
+}
+
+// argon2RefIndex maps a pseudo-random value to the index of the reference
+// block. See RFC 9106, Section 3.4.
+func argon2RefIndex(random uint64, laneLength, segmentLength, lanes, pass, slice, lane, index uint32) uint32 {
+	refLane := uint32(random>>32) % lanes
+	if pass == 0 && slice == 0 {
+		refLane = lane
+	}
+
+	// Compute the size of the reference area and its start position.
+	var area, start uint32
+	if pass == 0 {
+		area = slice * segmentLength
+		if lane == refLane {
+			area += index - 1
+		} else if index == 0 {
+			area--
+		}
+	} else {
+		area = laneLength - segmentLength
+		if lane == refLane {
+			area += index - 1
+		} else if index == 0 {
+			area--
+		}
+		start = ((slice + 1) % argon2SyncPoints) * segmentLength
+	}
+
+	x := random & 0xffffffff
+	x = (x * x) >> 32
+	x = (uint64(area) * x) >> 32
+	relative := uint64(area) - 1 - x
+	return refLane*laneLength + uint32((uint64(start)+relative)%uint64(laneLength))
+}
+
+// argon2Hash computes the variable-length hash function H' of RFC 9106,
+// Section 3.3.
+func argon2Hash(out, in []byte) {
+	var prefix [4]byte
+	binary.LittleEndian.PutUint32(prefix[:], uint32(len(out)))
+	if len(out) <= blake2bSize {
+		h := newBlake2b(len(out))
+		h.write(prefix[:])
+		h.write(in)
+		h.sum(out[:0])
+		return
+	}
+
+	h := newBlake2b(blake2bSize)
+	h.write(prefix[:])
+	h.write(in)
+	v := h.sum(nil)
+	copy(out, v[:32])
+	out = out[32:]
+	for len(out) > blake2bSize {
+		h.reset()
+		h.write(v)
+		v = h.sum(v[:0])
+		copy(out, v[:32])
+		out = out[32:]
+	}
+	h = newBlake2b(len(out))
+	h.write(v)
+	h.sum(out[:0])
+}
+
+// argon2Compress computes the compression function G of RFC 9106, Section
+// 3.5, over x and y, and stores it in out, or XORs it into out if xor is set.
+func argon2Compress(out, x, y *argon2Block, xor bool) {
+	var r, q argon2Block
+	for i := range r {
+		r[i] = x[i] ^ y[i]
+	}
+	q = r
+
+	// Apply the permutation P to the rows, then to the columns, of Q viewed
+	// as an 8x8 matrix of 16-byte registers.
+	var v [16]*uint64
+	for row := range 8 {
+		for i := range v {
+			v[i] = &q[16*row+i]
+		}
+		blamka(&v)
+	}
+	for col := range 8 {
+		for i := range 8 {
+			v[2*i] = &q[2*col+16*i]
+			v[2*i+1] = &q[2*col+16*i+1]
+		}
+		blamka(&v)
+	}
+
+	for i := range out {
+		if xor {
+			out[i] ^= q[i] ^ r[i]
+		} else {
+			out[i] = q[i] ^ r[i]
+		}
+	}
+}
+
+// blamka applies the BLAKE2b-based permutation P of RFC 9106, Section 3.6, to
+// the 16 words pointed to by v.
+func blamka(v *[16]*uint64) {
+	gb := func(a, b, c, d *uint64) {
+		mul := func(x, y uint64) uint64 { return 2 * uint64(uint32(x)) * uint64(uint32(y)) }
+		*a += *b + mul(*a, *b)
+		*d = bits.RotateLeft64(*d^*a, -32)
+		*c += *d + mul(*c, *d)
+		*b = bits.RotateLeft64(*b^*c, -24)
+		*a += *b + mul(*a, *b)
+		*d = bits.RotateLeft64(*d^*a, -16)
+		*c += *d + mul(*c, *d)
+		*b = bits.RotateLeft64(*b^*c, -63)
+	}
+	gb(v[0], v[4], v[8], v[12])
+	gb(v[1], v[5], v[9], v[13])
+	gb(v[2], v[6], v[10], v[14])
+	gb(v[3], v[7], v[11], v[15])
+	gb(v[0], v[5], v[10], v[15])
+	gb(v[1], v[6], v[11], v[12])
+	gb(v[2], v[7], v[8], v[13])
+	gb(v[3], v[4], v[9], v[14])
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// Bcrypt are the parameters of the bcrypt password hashing function, as
+// specified in "A Future-Adaptable Password Scheme" by Provos and Mazières.
+//
+// Hashes are encoded in the Modular Crypt Format, for example
+//
+//	$2b$12$<salt><hash>
+//
+// which is not a PHC string, but is the standard encoding of bcrypt hashes.
+//
+// Passwords longer than 72 bytes can't be hashed with bcrypt, and are
+// rejected with [ErrPasswordTooLong].
+type Bcrypt struct {
+	// Cost is the base-2 logarithm of the number of key expansion rounds.
+	// It must be between 4 and 31.
+	Cost int
+}
+
+const (
+	bcryptMinCost     = 4
+	bcryptMaxCost     = 31
+	bcryptMaxPassword = 72
+	bcryptSaltSize    = 16
+	bcryptHashSize    = 23 // only 23 of the 24 bytes of ciphertext are encoded
+)
+
+// ErrPasswordTooLong is returned when hashing or verifying a password longer
+// than 72 bytes with bcrypt, which would otherwise be silently truncated.
+var ErrPasswordTooLong = errors.New("password: password length exceeds 72 bytes")
+
+// bcryptEncoding is the base64 alphabet of bcrypt, without padding.
+var bcryptEncoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+func (p Bcrypt) check() error {
+	if p.Cost < bcryptMinCost || p.Cost > bcryptMaxCost {
+		return errors.New("password: bcrypt Cost must be between 4 and 31")
+	}
+	return nil
+}
+
+func (p Bcrypt) hash(password string) (string, error) {
+	if err := p.check(); err != nil {
+		return "", err
+	}
+	if len(password) > bcryptMaxPassword {
+		return "", ErrPasswordTooLong
+	}
+	salt, err := randomSalt(bcryptSaltSize)
+	if err != nil {
+		return "", err
+	}
+	return p.encode("2b", salt, bcrypt([]byte(password), p.Cost, salt)), nil
+}
+
+func (p Bcrypt) encode(version string, salt, hash []byte) string {
+	b := append([]byte{'$'}, version...)
+	b = append(b, '$')
+	if p.Cost < 10 {
+		b = append(b, '0')
+	}
+	b = strconv.AppendInt(b, int64(p.Cost), 10)
+	b = append(b, '$')
+	b = bcryptEncoding.AppendEncode(b, salt)
+	b = bcryptEncoding.AppendEncode(b, hash)
+	return string(b)
+}
+
+func (p Bcrypt) needsRehash(h *phcHash) bool {
+	q, ok := h.params.(Bcrypt)
+	return !ok || q != p
+}
+
+// parseBcrypt parses a bcrypt hash in the Modular Crypt Format. The 2a, 2b,
+// and 2y versions are all computed in the same way.
+func parseBcrypt(s string) (*phcHash, error) {
+	// $2b$12$ followed by 22 characters of salt and 31 characters of hash.
+	if len(s) != 60 || s[0] != '$' || s[1] != '2' || s[3] != '$' || s[6] != '$' {
+		return nil, errors.New("password: malformed bcrypt hash")
+	}
+	switch s[2] {
+	case 'a', 'b', 'y':
+	default:
+		return nil, errors.New("password: unsupported bcrypt version")
+	}
+	cost, err := strconv.Atoi(s[4:6])
+	if err != nil {
+		return nil, errors.New("password: malformed bcrypt hash")
+	}
+	p := Bcrypt{Cost: cost}
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	salt, err1 := bcryptEncoding.DecodeString(s[7:29])
+	hash, err2 := bcryptEncoding.DecodeString(s[29:])
+	if err1 != nil || err2 != nil {
+		return nil, errors.New("password: malformed bcrypt hash")
+	}
+	h := &phcHash{params: p, salt: salt, hash: hash}
+	h.derive = func(password []byte) []byte {
+		if len(password) > bcryptMaxPassword {
+			return nil
+		}
+		return bcrypt(password, p.Cost, salt)
+	}
+	return h, nil
+}
+
+// bcrypt computes the 23-byte bcrypt hash of password, which must be at most
+// 72 bytes long.
+func bcrypt(password []byte, cost int, salt []byte) []byte {
+	// The key includes the trailing NUL of the C string.
+	key := append(password[:len(password):len(password)], 0)
+
+	// EksBlowfishSetup.
+	c := newBlowfish()
+	c.expandKey(key, salt)
+	for range uint64(1) << cost {
+		c.expandKey(key, nil)
+		c.expandKey(salt, nil)
+	}
+
+	ciphertext := []byte("OrpheanBeholderScryDoubt")
+	for i := 0; i < len(ciphertext); i += 8 {
+		l := binary.BigEndian.Uint32(ciphertext[i:])
+		r := binary.BigEndian.Uint32(ciphertext[i+4:])
+		for range 64 {
+			l, r = c.encrypt(l, r)
+		}
+		binary.BigEndian.PutUint32(ciphertext[i:], l)
+		binary.BigEndian.PutUint32(ciphertext[i+4:], r)
+	}
+	return ciphertext[:bcryptHashSize]
+}
+
+// blowfish is the state of the Blowfish block cipher.
+type blowfish struct {
+	p [18]uint32
+	s [4][256]uint32
+}
+
+func newBlowfish() *blowfish {
+	return &blowfish{
+		p: blowfishP,
+		s: [4][256]uint32{blowfishS0, blowfishS1, blowfishS2, blowfishS3},
+	}
+}
+
+// nextWord returns the next big-endian 32-bit word of b, cycling through it
+// as needed, starting at *j.
+func nextWord(b []byte, j *int) uint32 {
+	var w uint32
+	for range 4 {
+		w = w<<8 | uint32(b[*j])
+		*j = (*j + 1) % len(b)
+	}
+	return w
+}
+
+// expandKey is the key schedule of Blowfish, with the salt extension of
+// bcrypt. If salt is nil, it's the regular Blowfish key schedule.
+func (c *blowfish) expandKey(key, salt []byte) {
+	j := 0
+	for i := range c.p {
+		c.p[i] ^= nextWord(key, &j)
+	}
+
+	j = 0
+	var l, r uint32
+	next := func() (uint32, uint32) {
+		if salt != nil {
+			l ^= nextWord(salt, &j)
+			r ^= nextWord(salt, &j)
+		}
+		l, r = c.encrypt(l, r)
+		return l, r
+	}
+	for i := 0; i < len(c.p); i += 2 {
+		c.p[i], c.p[i+1] = next()
+	}
+	for k := range c.s {
+		for i := 0; i < len(c.s[k]); i += 2 {
+			c.s[k][i], c.s[k][i+1] = next()
+		}
+	}
+}
+
+func (c *blowfish) f(x uint32) uint32 {
+	return ((c.s[0][x>>24] + c.s[1][byte(x>>16)]) ^ c.s[2][byte(x>>8)]) + c.s[3][byte(x)]
+}
+
+// encrypt encrypts the 64-bit block l, r.
+func (c *blowfish) encrypt(l, r uint32) (uint32, uint32) {
+	for i := 0; i < 16; i += 2 {
+		l ^= c.p[i]
+		r ^= c.f(l)
+		r ^= c.p[i+1]
+		l ^= c.f(r)
+	}
+	l ^= c.p[16]
+	r ^= c.p[17]
+	return r, l
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password_test
+
+import (
+	"crypto/password"
+	"log"
+)
+
+func Example() {
+	// Hash the password at registration, and store the hash.
+	hash, err := password.Hash("correct horse battery staple", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Verify the password at login.
+	if err := password.Verify("correct horse battery staple", hash); err != nil {
+		log.Fatal(err)
+	}
+
+	// If the default parameters changed since the hash was stored, replace
+	// it while the plaintext password is available.
+	if password.NeedsRehash(hash, nil) {
+		hash, err = password.Hash("correct horse battery staple", nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	_ = hash
+}
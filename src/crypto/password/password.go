@@ -0,0 +1,186 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package password implements password hashing, for storing and verifying
+// user passwords, with the Argon2id, scrypt, and bcrypt algorithms.
+//
+// Hashes are self-describing strings, which encode the algorithm, its
+// parameters, a random salt, and the hash. They are PHC strings for Argon2id
+// and scrypt, and Modular Crypt Format strings for bcrypt, compatible with
+// other implementations.
+//
+// New applications should use [Hash] with the default parameters, which use
+// Argon2id. When the parameters change, [NeedsRehash] reports which stored
+// hashes should be replaced after the next successful [Verify].
+//
+// Unlike [crypto/pbkdf2], none of these algorithms is approved by FIPS 140.
+// In FIPS 140-only mode, Hash and Verify return an error.
+package password
+
+import (
+	"crypto/internal/fips140only"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Params are the parameters of a password hashing algorithm. They are
+// implemented by [Argon2id], [Scrypt], and [Bcrypt].
+type Params interface {
+	hash(password string) (string, error)
+	needsRehash(h *phcHash) bool
+}
+
+// DefaultParams returns the default parameters used by [Hash] and
+// [NeedsRehash] when params is nil. They are currently Argon2id with 64 MiB
+// of memory, 3 passes, and 4 threads, as recommended by RFC 9106, Section 4.
+//
+// The default parameters might change over time.
+func DefaultParams() Params {
+	return Argon2id{Memory: 64 * 1024, Time: 3, Threads: 4}
+}
+
+// ErrMismatchedHashAndPassword is returned by [Verify] when the password
+// doesn't match the hash.
+var ErrMismatchedHashAndPassword = errors.New("password: hash does not match password")
+
+// Hash hashes password with a random salt, and returns the encoded hash.
+// If params is nil, [DefaultParams] are used.
+func Hash(password string, params Params) (string, error) {
+	if fips140only.Enforced() {
+		return "", errors.New("password: use of password hashing is not allowed in FIPS 140-only mode")
+	}
+	if params == nil {
+		params = DefaultParams()
+	}
+	return params.hash(password)
+}
+
+// Verify checks password against an encoded hash produced by [Hash], or by
+// another implementation of the supported algorithms. It returns nil if the
+// password matches, and [ErrMismatchedHashAndPassword] if it doesn't.
+//
+// The hash is compared in constant time. Verifying a hash can take an
+// arbitrary amount of time and memory, depending on its parameters, so hash
+// must come from a trusted source, like the application's own database.
+func Verify(password, hash string) error {
+	if fips140only.Enforced() {
+		return errors.New("password: use of password hashing is not allowed in FIPS 140-only mode")
+	}
+	h, err := parseHash(hash)
+	if err != nil {
+		return err
+	}
+	if _, ok := h.params.(Bcrypt); ok && len(password) > bcryptMaxPassword {
+		return ErrPasswordTooLong
+	}
+	if subtle.ConstantTimeCompare(h.derive([]byte(password)), h.hash) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hash was produced with an algorithm, parameters,
+// or salt or hash sizes different from those that [Hash] would use with
+// params. If params is nil, [DefaultParams] are used.
+//
+// Applications can use NeedsRehash after a successful [Verify], while the
+// plaintext password is available, to upgrade stored hashes. NeedsRehash
+// returns true if hash can't be parsed.
+func NeedsRehash(hash string, params Params) bool {
+	if params == nil {
+		params = DefaultParams()
+	}
+	h, err := parseHash(hash)
+	if err != nil {
+		return true
+	}
+	return params.needsRehash(h)
+}
+
+// phcHash is a parsed hash.
+type phcHash struct {
+	params Params
+	salt   []byte
+	hash   []byte
+	// derive computes the hash of password with params and salt.
+	derive func(password []byte) []byte
+}
+
+type phcParam struct {
+	name, value string
+}
+
+// parseHash parses a hash produced by Hash. Argon2id and scrypt hashes are PHC
+// strings with the format
+//
+//	$<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+//
+// See https://github.com/P-H-C/phc-string-format/blob/master/phc-sf-spec.md.
+func parseHash(s string) (*phcHash, error) {
+	if strings.HasPrefix(s, "$2") {
+		return parseBcrypt(s)
+	}
+
+	fields := strings.Split(s, "$")
+	if len(fields) < 2 || fields[0] != "" {
+		return nil, errors.New("password: malformed hash")
+	}
+	id, fields := fields[1], fields[2:]
+	var version string
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "v=") {
+		version, fields = fields[0], fields[1:]
+	}
+	// Both supported algorithms require parameters, a salt, and a hash.
+	if len(fields) != 3 {
+		return nil, errors.New("password: malformed hash")
+	}
+	var params []phcParam
+	for p := range strings.SplitSeq(fields[0], ",") {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, errors.New("password: malformed hash parameters")
+		}
+		params = append(params, phcParam{name, value})
+	}
+	h := &phcHash{}
+	var err error
+	if h.salt, err = base64.RawStdEncoding.Strict().DecodeString(fields[1]); err != nil {
+		return nil, errors.New("password: malformed hash salt")
+	}
+	if h.hash, err = base64.RawStdEncoding.Strict().DecodeString(fields[2]); err != nil {
+		return nil, errors.New("password: malformed hash")
+	}
+
+	switch id {
+	case "argon2id":
+		err = parseArgon2id(h, version, params)
+	case "scrypt":
+		err = parseScrypt(h, version, params)
+	default:
+		err = errors.New("password: unsupported algorithm " + id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func appendSaltAndHash(b, salt, hash []byte) []byte {
+	b = append(b, '$')
+	b = base64.RawStdEncoding.AppendEncode(b, salt)
+	b = append(b, '$')
+	b = base64.RawStdEncoding.AppendEncode(b, hash)
+	return b
+}
+
+func randomSalt(size int) ([]byte, error) {
+	salt := make([]byte, size)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
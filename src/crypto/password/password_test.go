@@ -0,0 +1,230 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestBlake2b(t *testing.T) {
+	h := newBlake2b(blake2bSize)
+	h.write([]byte("abc"))
+	want := "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"
+	if got := hex.EncodeToString(h.sum(nil)); got != want {
+		t.Errorf("BLAKE2b-512(abc) = %s, want %s", got, want)
+	}
+
+	// Writes spanning block boundaries must match a single write.
+	msg := bytes.Repeat([]byte("0123456789"), 100)
+	h1 := newBlake2b(32)
+	h1.write(msg)
+	h2 := newBlake2b(32)
+	for i := 0; i < len(msg); i += 7 {
+		h2.write(msg[i:min(i+7, len(msg))])
+	}
+	if !bytes.Equal(h1.sum(nil), h2.sum(nil)) {
+		t.Errorf("incremental writes produce a different digest")
+	}
+}
+
+func TestArgon2idVector(t *testing.T) {
+	// RFC 9106, Section 5.3.
+	password := bytes.Repeat([]byte{0x01}, 32)
+	salt := bytes.Repeat([]byte{0x02}, 16)
+	secret := bytes.Repeat([]byte{0x03}, 8)
+	data := bytes.Repeat([]byte{0x04}, 12)
+	got := argon2id(password, salt, secret, data, 3, 32, 4, 32)
+	want := decodeHex(t, "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659")
+	if !bytes.Equal(got, want) {
+		t.Errorf("Argon2id = %x, want %x", got, want)
+	}
+}
+
+func TestScryptVectors(t *testing.T) {
+	// RFC 7914, Section 12.
+	tests := []struct {
+		password, salt string
+		N, r, p        int
+		want           string
+	}{
+		{"", "", 16, 1, 1, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906"},
+		{"password", "NaCl", 1024, 8, 16, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+	}
+	for _, tt := range tests {
+		got, err := scrypt(tt.password, []byte(tt.salt), tt.N, tt.r, tt.p, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := decodeHex(t, tt.want); !bytes.Equal(got, want) {
+			t.Errorf("scrypt(%q, %q, %d, %d, %d) = %x, want %x", tt.password, tt.salt, tt.N, tt.r, tt.p, got, want)
+		}
+	}
+}
+
+func TestVerifyVectors(t *testing.T) {
+	tests := []struct {
+		password, hash string
+	}{
+		// From the Argon2 reference implementation.
+		{"password", "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"},
+		// Generated with Python's hashlib.scrypt.
+		{"password", "$scrypt$ln=4,r=2,p=3$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY"},
+		// Generated with OpenBSD-compatible crypt(3) implementations.
+		{"allmine", "$2a$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga"},
+		{"", "$2b$04$abcdefghijklmnopqrstuubyCG3zY1GIXMyxfivm.ClDiInHzxjiq"},
+		{strings.Repeat("x", 72), "$2y$05$XajjQvNhvvRt5GSeFk1xFeOzLLSjYIzUePQBF0kcOPjgRQM3sUJQq"},
+		{"pässwörd", "$2b$06$XajjQvNhvvRt5GSeFk1xFe/6ET7h3ajGCLVhgLQgjrBGBuhoS.yMW"},
+	}
+	for _, tt := range tests {
+		if err := Verify(tt.password, tt.hash); err != nil {
+			t.Errorf("Verify(%q, %q) = %v", tt.password, tt.hash, err)
+		}
+		if err := Verify(tt.password+"!", tt.hash); err != ErrMismatchedHashAndPassword && err != ErrPasswordTooLong {
+			t.Errorf("Verify with wrong password for %q = %v", tt.hash, err)
+		}
+	}
+}
+
+var testParams = []Params{
+	Argon2id{Memory: 64, Time: 1, Threads: 2},
+	Scrypt{N: 16, R: 8, P: 1},
+	Bcrypt{Cost: 4},
+}
+
+func TestHashVerify(t *testing.T) {
+	for _, params := range testParams {
+		h, err := Hash("correct horse battery staple", params)
+		if err != nil {
+			t.Fatalf("%T: %v", params, err)
+		}
+		if err := Verify("correct horse battery staple", h); err != nil {
+			t.Errorf("%T: Verify(%q) = %v", params, h, err)
+		}
+		if err := Verify("correct horse battery stapler", h); err != ErrMismatchedHashAndPassword {
+			t.Errorf("%T: Verify with wrong password = %v, want ErrMismatchedHashAndPassword", params, err)
+		}
+		h2, err := Hash("correct horse battery staple", params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h == h2 {
+			t.Errorf("%T: two hashes are equal, salt is not random", params)
+		}
+	}
+}
+
+func TestHashInvalidParams(t *testing.T) {
+	for _, params := range []Params{
+		Argon2id{Memory: 64, Time: 0, Threads: 1},
+		Argon2id{Memory: 64, Time: 1, Threads: 0},
+		Argon2id{Memory: 15, Time: 1, Threads: 2},
+		Scrypt{N: 15, R: 8, P: 1},
+		Scrypt{N: 1, R: 8, P: 1},
+		Scrypt{N: 16, R: 0, P: 1},
+		Scrypt{N: 16, R: 1 << 20, P: 1 << 10},
+		Bcrypt{Cost: 3},
+		Bcrypt{Cost: 32},
+	} {
+		if _, err := Hash("password", params); err == nil {
+			t.Errorf("Hash with %#v succeeded", params)
+		}
+	}
+}
+
+func TestBcryptPasswordTooLong(t *testing.T) {
+	if _, err := Hash(strings.Repeat("x", 73), Bcrypt{Cost: 4}); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("Hash = %v, want ErrPasswordTooLong", err)
+	}
+	h, err := Hash(strings.Repeat("x", 72), Bcrypt{Cost: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(strings.Repeat("x", 73), h); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("Verify = %v, want ErrPasswordTooLong", err)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	for _, params := range testParams {
+		h, err := Hash("password", params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, other := range testParams {
+			if got, want := NeedsRehash(h, other), params != other; got != want {
+				t.Errorf("NeedsRehash(%q, %#v) = %v, want %v", h, other, got, want)
+			}
+		}
+		if !NeedsRehash(h, nil) {
+			t.Errorf("NeedsRehash(%q, nil) = false", h)
+		}
+	}
+
+	for _, tt := range []struct {
+		hash   string
+		params Params
+		want   bool
+	}{
+		{"$argon2id$v=19$m=64,t=2,p=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY", Argon2id{Memory: 64, Time: 1, Threads: 2}, true},
+		{"$argon2id$v=19$m=64,t=1,p=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY", Argon2id{Memory: 64, Time: 1, Threads: 2}, false},
+		// Short salt.
+		{"$argon2id$v=19$m=64,t=1,p=2$c29tZXNhbHQ$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY", Argon2id{Memory: 64, Time: 1, Threads: 2}, true},
+		{"$scrypt$ln=4,r=2,p=3$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY", Scrypt{N: 16, R: 2, P: 3}, false},
+		{"$scrypt$ln=4,r=2,p=3$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY", Scrypt{N: 32, R: 2, P: 3}, true},
+		{"$2a$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga", Bcrypt{Cost: 10}, false},
+		{"$2a$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga", Bcrypt{Cost: 12}, true},
+		{"garbage", Bcrypt{Cost: 12}, true},
+	} {
+		if got := NeedsRehash(tt.hash, tt.params); got != tt.want {
+			t.Errorf("NeedsRehash(%q, %#v) = %v, want %v", tt.hash, tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	for _, h := range []string{
+		"",
+		"$",
+		"password",
+		"$argon2id",
+		"$argon2i$v=19$m=64,t=1,p=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$argon2id$v=16$m=64,t=1,p=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$argon2id$m=64,t=1,p=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$argon2id$v=19$t=1,m=64,p=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$argon2id$v=19$m=64,t=1,p=256$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$argon2id$v=19$m=64,t=1,p=2$MDEyMzQ1Njc4OWFiY2RlZg==$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$argon2id$v=19$m=64,t=1,p=2$MDEyMzQ1Njc4OWFiY2RlZg$YWI",
+		"$argon2id$v=19$m=64,t=1,p=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY$",
+		"$scrypt$ln=4,r=2$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$scrypt$ln=0,r=2,p=3$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$scrypt$ln=99,r=2,p=3$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$scrypt$v=1$ln=4,r=2,p=3$MDEyMzQ1Njc4OWFiY2RlZg$aRL7xThGxxn7BSjD0cfeWXtxuukuvPZ1eWd7Me1icwY",
+		"$2a$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcg",
+		"$2x$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga",
+		"$2a$03$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga",
+		"$2a$1a$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDcga",
+		"$2a$10$XajjQvNhvvRt5GSeFk1xFeyqRrsxkhBkUiQeg0dt.wU1qD4aFDc!a",
+	} {
+		if err := Verify("password", h); err == nil || err == ErrMismatchedHashAndPassword {
+			t.Errorf("Verify(%q) = %v, want a parsing error", h, err)
+		}
+		if !NeedsRehash(h, nil) {
+			t.Errorf("NeedsRehash(%q) = false", h)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package password
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strconv"
+)
+
+// Scrypt are the parameters of the scrypt password hashing function,
+// specified in RFC 7914.
+//
+// Hashes are encoded as PHC strings, for example
+//
+//	$scrypt$ln=17,r=8,p=1$<salt>$<hash>
+//
+// where ln is the base-2 logarithm of N, with a 16-byte salt and a 32-byte
+// hash.
+type Scrypt struct {
+	// N is the CPU/memory cost. It must be a power of two greater than 1.
+	N int
+
+	// R is the block size. It must be at least 1.
+	R int
+
+	// P is the parallelization parameter. It must be at least 1.
+	P int
+}
+
+const (
+	scryptSaltSize = 16
+	scryptKeySize  = 32
+)
+
+func (p Scrypt) check() error {
+	if p.N <= 1 || p.N&(p.N-1) != 0 {
+		return errors.New("password: scrypt N must be a power of two greater than 1")
+	}
+	if p.R < 1 || p.P < 1 {
+		return errors.New("password: scrypt R and P must be at least 1")
+	}
+	// These limits are from RFC 7914, Section 2, and also prevent overflows
+	// in the memory size computation.
+	if uint64(p.R)*uint64(p.P) >= 1<<30 || p.R > maxInt/128/p.P || p.R > maxInt/256 || p.N > maxInt/128/p.R {
+		return errors.New("password: scrypt parameters are too large")
+	}
+	return nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+func (p Scrypt) hash(password string) (string, error) {
+	if err := p.check(); err != nil {
+		return "", err
+	}
+	salt, err := randomSalt(scryptSaltSize)
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt(password, salt, p.N, p.R, p.P, scryptKeySize)
+	if err != nil {
+		return "", err
+	}
+	return p.encode(salt, key), nil
+}
+
+func (p Scrypt) encode(salt, key []byte) string {
+	b := []byte("$scrypt$ln=")
+	b = strconv.AppendInt(b, int64(bits.TrailingZeros(uint(p.N))), 10)
+	b = append(b, ",r="...)
+	b = strconv.AppendInt(b, int64(p.R), 10)
+	b = append(b, ",p="...)
+	b = strconv.AppendInt(b, int64(p.P), 10)
+	return string(appendSaltAndHash(b, salt, key))
+}
+
+func (p Scrypt) needsRehash(h *phcHash) bool {
+	q, ok := h.params.(Scrypt)
+	return !ok || q != p || len(h.salt) != scryptSaltSize || len(h.hash) != scryptKeySize
+}
+
+// parseScrypt parses the parameters of a scrypt PHC string.
+func parseScrypt(h *phcHash, version string, params []phcParam) error {
+	if version != "" {
+		return errors.New("password: unsupported scrypt version")
+	}
+	if len(params) != 3 || params[0].name != "ln" || params[1].name != "r" || params[2].name != "p" {
+		return errors.New("password: malformed scrypt parameters")
+	}
+	ln, err1 := strconv.ParseUint(params[0].value, 10, 6)
+	r, err2 := strconv.ParseUint(params[1].value, 10, 31)
+	pp, err3 := strconv.ParseUint(params[2].value, 10, 31)
+	if err1 != nil || err2 != nil || err3 != nil || ln >= uint64(bits.UintSize-1) {
+		return errors.New("password: malformed scrypt parameters")
+	}
+	p := Scrypt{N: 1 << ln, R: int(r), P: int(pp)}
+	if err := p.check(); err != nil {
+		return err
+	}
+	if len(h.hash) < 1 {
+		return errors.New("password: scrypt hash is too short")
+	}
+	h.params = p
+	h.derive = func(password []byte) []byte {
+		key, err := scrypt(string(password), h.salt, p.N, p.R, p.P, len(h.hash))
+		if err != nil {
+			return nil
+		}
+		return key
+	}
+	return nil
+}
+
+// scrypt computes scrypt as specified in RFC 7914, Section 6. The parameters
+// must have been checked by Scrypt.check.
+func scrypt(password string, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+	b, err := pbkdf2.Key(sha256.New, password, salt, 1, p*128*r)
+	if err != nil {
+		return nil, err
+	}
+	xy := make([]uint32, 64*r)
+	v := make([]uint32, 32*N*r)
+	for i := range p {
+		scryptROMix(b[i*128*r:], r, N, v, xy)
+	}
+	return pbkdf2.Key(sha256.New, password, b, 1, keyLen)
+}
+
+// scryptROMix computes scryptROMix of RFC 7914, Section 5, in place on the
+// 128*r bytes of b, using v and xy as scratch space.
+func scryptROMix(b []byte, r, N int, v, xy []uint32) {
+	x := xy[:32*r]
+	y := xy[32*r:]
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	for i := range N {
+		copy(v[i*32*r:], x)
+		scryptBlockMix(x, y, r)
+	}
+	for range N {
+		// Integerify(X) mod N, where N is a power of two.
+		j := int(x[(2*r-1)*16] & uint32(N-1))
+		for k := range x {
+			x[k] ^= v[j*32*r+k]
+		}
+		scryptBlockMix(x, y, r)
+	}
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(b[4*i:], w)
+	}
+}
+
+// scryptBlockMix computes scryptBlockMix of RFC 7914, Section 4, in place on
+// the 2*r 64-byte blocks of b, using y as scratch space.
+func scryptBlockMix(b, y []uint32, r int) {
+	var x [16]uint32
+	copy(x[:], b[(2*r-1)*16:])
+	for i := range 2 * r {
+		for j := range x {
+			x[j] ^= b[i*16+j]
+		}
+		salsa208(&x)
+		// Even blocks go to the first half of the output, odd blocks to the
+		// second half.
+		copy(y[(i/2+(i%2)*r)*16:], x[:])
+	}
+	copy(b, y[:32*r])
+}
+
+// salsa208 applies the Salsa20/8 core to x.
+func salsa208(x *[16]uint32) {
+	in := *x
+	for range 4 {
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+	for i := range x {
+		x[i] += in[i]
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(fips140v1.0 || fips140v1.26)
+
+package slhdsa_test
+
+import (
+	"crypto/slhdsa"
+	"fmt"
+	"log"
+)
+
+func Example() {
+	// The signer generates a new SLH-DSA-SHA2-128s key pair.
+	sk, err := slhdsa.GenerateKey(slhdsa.SHA2_128s())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The signer publishes the public key encoding.
+	publicKey := sk.PublicKey().Bytes()
+	fmt.Printf("public key: %d bytes\n", len(publicKey))
+
+	// The signer signs a message and publishes the signature.
+	msg := []byte("hello, world")
+	sig, err := sk.Sign(nil, msg, &slhdsa.Options{Context: "example"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("signature: %d bytes\n", len(sig))
+
+	// The verifier reconstructs the public key and checks the signature.
+	// The context string must match the one used by the signer.
+	pk, err := slhdsa.NewPublicKey(slhdsa.SHA2_128s(), publicKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := slhdsa.Verify(pk, msg, sig, &slhdsa.Options{Context: "example"}); err != nil {
+		log.Fatal("invalid signature: ", err)
+	}
+
+	// Output:
+	// public key: 32 bytes
+	// signature: 7856 bytes
+}
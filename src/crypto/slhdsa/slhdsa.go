@@ -0,0 +1,133 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package slhdsa implements the post-quantum SLH-DSA stateless hash-based
+// signature scheme specified in [FIPS 205].
+//
+// SLH-DSA signatures are large and slow to generate, but the security of the
+// scheme relies only on the security of the underlying hash function.
+//
+// Only the pure variant of SLH-DSA is implemented. HashSLH-DSA, where the
+// message is pre-hashed by the caller, is not supported.
+//
+// This package is unavailable if using the [FIPS 140-3 Go Cryptographic Module]
+// v1.0.0 or v1.26.0, in which case [GenerateKey], [NewPrivateKey],
+// [NewPublicKey], and [Verify] will return an error. It is available if using
+// v1.28.0 or later.
+//
+// [FIPS 205]: https://nvlpubs.nist.gov/nistpubs/FIPS/NIST.FIPS.205.pdf
+// [FIPS 140-3 Go Cryptographic Module]: https://go.dev/doc/security/fips140
+package slhdsa
+
+import "crypto"
+
+// Parameters represents one of the fixed parameter sets defined in FIPS 205.
+//
+// The "s" parameter sets produce smaller signatures, and the "f" parameter
+// sets are faster at signing. The SHA2 and SHAKE parameter sets with the same
+// name have the same sizes and security level. Applications that have no other
+// requirements should use [SHA2_128s].
+//
+// Multiple invocations of the same function, such as [SHA2_128s], will return
+// the same value, which can be used for equality checks and switch
+// statements. The returned value is safe for concurrent use.
+type Parameters struct {
+	name          string
+	n             int
+	signatureSize int
+}
+
+// SHA2_128s returns the SLH-DSA-SHA2-128s parameter set defined in FIPS 205.
+func SHA2_128s() Parameters {
+	return Parameters{name: "SLH-DSA-SHA2-128s", n: 16, signatureSize: 7856}
+}
+
+// SHA2_128f returns the SLH-DSA-SHA2-128f parameter set defined in FIPS 205.
+func SHA2_128f() Parameters {
+	return Parameters{name: "SLH-DSA-SHA2-128f", n: 16, signatureSize: 17088}
+}
+
+// SHA2_192s returns the SLH-DSA-SHA2-192s parameter set defined in FIPS 205.
+func SHA2_192s() Parameters {
+	return Parameters{name: "SLH-DSA-SHA2-192s", n: 24, signatureSize: 16224}
+}
+
+// SHA2_192f returns the SLH-DSA-SHA2-192f parameter set defined in FIPS 205.
+func SHA2_192f() Parameters {
+	return Parameters{name: "SLH-DSA-SHA2-192f", n: 24, signatureSize: 35664}
+}
+
+// SHA2_256s returns the SLH-DSA-SHA2-256s parameter set defined in FIPS 205.
+func SHA2_256s() Parameters {
+	return Parameters{name: "SLH-DSA-SHA2-256s", n: 32, signatureSize: 29792}
+}
+
+// SHA2_256f returns the SLH-DSA-SHA2-256f parameter set defined in FIPS 205.
+func SHA2_256f() Parameters {
+	return Parameters{name: "SLH-DSA-SHA2-256f", n: 32, signatureSize: 49856}
+}
+
+// SHAKE_128s returns the SLH-DSA-SHAKE-128s parameter set defined in FIPS 205.
+func SHAKE_128s() Parameters {
+	return Parameters{name: "SLH-DSA-SHAKE-128s", n: 16, signatureSize: 7856}
+}
+
+// SHAKE_128f returns the SLH-DSA-SHAKE-128f parameter set defined in FIPS 205.
+func SHAKE_128f() Parameters {
+	return Parameters{name: "SLH-DSA-SHAKE-128f", n: 16, signatureSize: 17088}
+}
+
+// SHAKE_192s returns the SLH-DSA-SHAKE-192s parameter set defined in FIPS 205.
+func SHAKE_192s() Parameters {
+	return Parameters{name: "SLH-DSA-SHAKE-192s", n: 24, signatureSize: 16224}
+}
+
+// SHAKE_192f returns the SLH-DSA-SHAKE-192f parameter set defined in FIPS 205.
+func SHAKE_192f() Parameters {
+	return Parameters{name: "SLH-DSA-SHAKE-192f", n: 24, signatureSize: 35664}
+}
+
+// SHAKE_256s returns the SLH-DSA-SHAKE-256s parameter set defined in FIPS 205.
+func SHAKE_256s() Parameters {
+	return Parameters{name: "SLH-DSA-SHAKE-256s", n: 32, signatureSize: 29792}
+}
+
+// SHAKE_256f returns the SLH-DSA-SHAKE-256f parameter set defined in FIPS 205.
+func SHAKE_256f() Parameters {
+	return Parameters{name: "SLH-DSA-SHAKE-256f", n: 32, signatureSize: 49856}
+}
+
+// PublicKeySize returns the size of public keys for this parameter set, in bytes.
+func (params Parameters) PublicKeySize() int {
+	return 2 * params.n
+}
+
+// PrivateKeySize returns the size of private keys for this parameter set, in bytes.
+func (params Parameters) PrivateKeySize() int {
+	return 4 * params.n
+}
+
+// SignatureSize returns the size of signatures for this parameter set, in bytes.
+func (params Parameters) SignatureSize() int {
+	return params.signatureSize
+}
+
+// String returns the name of the parameter set, e.g. "SLH-DSA-SHA2-128s".
+func (params Parameters) String() string {
+	return params.name
+}
+
+// Options contains additional options for signing and verifying SLH-DSA signatures.
+type Options struct {
+	// Context can be used to distinguish signatures created for different
+	// purposes. It must be at most 255 bytes long, and it is empty by default.
+	//
+	// The same context must be used when signing and verifying a signature.
+	Context string
+}
+
+// HashFunc returns zero, to implement the [crypto.SignerOpts] interface.
+func (opts *Options) HashFunc() crypto.Hash {
+	return 0
+}
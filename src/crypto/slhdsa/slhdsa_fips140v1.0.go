@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build fips140v1.0 || fips140v1.26
+
+package slhdsa
+
+import (
+	"crypto"
+	"errors"
+	"io"
+)
+
+// This file provides stub implementations of the SLH-DSA API for building
+// against the FIPS 140-3 Go Cryptographic Module v1.0.0 or v1.26.0, which do
+// not include SLH-DSA. Top-level functions return an error, and methods are
+// unreachable since there is no way to construct a valid PublicKey or PrivateKey.
+
+var errUnavailable = errors.New("slhdsa: unavailable in FIPS 140-3 Go Cryptographic Module v1.0.0 and v1.26.0")
+
+const unreachable = "slhdsa: methods are unreachable in FIPS 140-3 Go Cryptographic Module v1.0.0 and v1.26.0"
+
+// PrivateKey is an in-memory SLH-DSA private key. It implements [crypto.Signer]
+// and the informal extended [crypto.PrivateKey] interface.
+//
+// A PrivateKey is safe for concurrent use.
+type PrivateKey struct{}
+
+// GenerateKey generates a new random SLH-DSA private key.
+func GenerateKey(params Parameters) (*PrivateKey, error) {
+	return nil, errUnavailable
+}
+
+// NewPrivateKey decodes an SLH-DSA private key from the given encoding,
+// as returned by [PrivateKey.Bytes].
+//
+// The encoding must be exactly params.PrivateKeySize() bytes long. The public
+// key it contains is checked against the rest of the private key, which costs
+// about as much as generating a key.
+func NewPrivateKey(params Parameters, encoding []byte) (*PrivateKey, error) {
+	return nil, errUnavailable
+}
+
+// Public returns the corresponding [PublicKey] for this private key.
+//
+// It implements the [crypto.Signer] interface.
+func (sk *PrivateKey) Public() crypto.PublicKey {
+	panic(unreachable)
+}
+
+// Equal reports whether sk and x are the same key.
+//
+// If x is not a *PrivateKey, Equal returns false.
+func (sk *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	panic(unreachable)
+}
+
+// PublicKey returns the corresponding [PublicKey] for this private key.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	panic(unreachable)
+}
+
+// Bytes returns the private key encoding, the concatenation of the
+// SK.seed, SK.prf, PK.seed, and PK.root values defined in FIPS 205.
+func (sk *PrivateKey) Bytes() []byte {
+	panic(unreachable)
+}
+
+// Sign returns a signature of the given message using this private key.
+//
+// opts.HashFunc must return zero, and the message is signed directly. opts can
+// be nil, or of type *[Options] if a context string is desired. The io.Reader
+// argument is ignored.
+func (sk *PrivateKey) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	panic(unreachable)
+}
+
+// SignDeterministic works like [PrivateKey.Sign], but the signature is
+// deterministic.
+func (sk *PrivateKey) SignDeterministic(message []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	panic(unreachable)
+}
+
+// PublicKey is an SLH-DSA public key. It implements the informal extended
+// [crypto.PublicKey] interface.
+//
+// A PublicKey is safe for concurrent use.
+type PublicKey struct{}
+
+// NewPublicKey creates a new SLH-DSA public key from the given encoding.
+func NewPublicKey(params Parameters, encoding []byte) (*PublicKey, error) {
+	return nil, errUnavailable
+}
+
+// Bytes returns the public key encoding, the concatenation of the PK.seed and
+// PK.root values defined in FIPS 205.
+func (pk *PublicKey) Bytes() []byte {
+	panic(unreachable)
+}
+
+// Equal reports whether pk and x are the same key (i.e. they have the same
+// parameters and encoding).
+//
+// If x is not a *PublicKey, Equal returns false.
+func (pk *PublicKey) Equal(x crypto.PublicKey) bool {
+	panic(unreachable)
+}
+
+// Parameters returns the parameters associated with this public key.
+func (pk *PublicKey) Parameters() Parameters {
+	panic(unreachable)
+}
+
+// Verify reports whether signature is a valid signature of message by pk.
+// If opts is nil, it's equivalent to the zero value of Options.
+func Verify(pk *PublicKey, message []byte, signature []byte, opts *Options) error {
+	return errUnavailable
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build fips140v1.0 || fips140v1.26
+
+package slhdsa_test
+
+import (
+	"crypto"
+	. "crypto/slhdsa"
+	"testing"
+)
+
+var _ crypto.Signer = (*PrivateKey)(nil)
+
+func TestUnavailable(t *testing.T) {
+	for _, params := range []Parameters{SHA2_128s(), SHAKE_256f()} {
+		t.Run(params.String(), func(t *testing.T) {
+			if _, err := GenerateKey(params); err == nil {
+				t.Errorf("GenerateKey: want error, got nil")
+			}
+			if _, err := NewPrivateKey(params, make([]byte, params.PrivateKeySize())); err == nil {
+				t.Errorf("NewPrivateKey: want error, got nil")
+			}
+			if _, err := NewPublicKey(params, make([]byte, params.PublicKeySize())); err == nil {
+				t.Errorf("NewPublicKey: want error, got nil")
+			}
+			if err := Verify(&PublicKey{}, nil, nil, nil); err == nil {
+				t.Errorf("Verify: want error, got nil")
+			}
+		})
+	}
+}
+
+func TestMethodsPanic(t *testing.T) {
+	sk := &PrivateKey{}
+	pk := &PublicKey{}
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		{"PrivateKey.Public", func() { sk.Public() }},
+		{"PrivateKey.Equal", func() { sk.Equal(sk) }},
+		{"PrivateKey.PublicKey", func() { sk.PublicKey() }},
+		{"PrivateKey.Bytes", func() { sk.Bytes() }},
+		{"PrivateKey.Sign", func() { sk.Sign(nil, nil, nil) }},
+		{"PrivateKey.SignDeterministic", func() { sk.SignDeterministic(nil, nil) }},
+		{"PublicKey.Bytes", func() { pk.Bytes() }},
+		{"PublicKey.Equal", func() { pk.Equal(pk) }},
+		{"PublicKey.Parameters", func() { pk.Parameters() }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: did not panic", tc.name)
+				}
+			}()
+			tc.fn()
+		})
+	}
+}
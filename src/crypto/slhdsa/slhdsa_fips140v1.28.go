@@ -0,0 +1,225 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(fips140v1.0 || fips140v1.26)
+
+package slhdsa
+
+import (
+	"crypto"
+	"crypto/internal/fips140/slhdsa"
+	"errors"
+	"io"
+)
+
+// PrivateKey is an in-memory SLH-DSA private key. It implements [crypto.Signer]
+// and the informal extended [crypto.PrivateKey] interface.
+//
+// A PrivateKey is safe for concurrent use.
+type PrivateKey struct {
+	k slhdsa.PrivateKey
+}
+
+var errInvalidParameters = errors.New("slhdsa: invalid parameters")
+
+var allParameters = []Parameters{
+	SHA2_128s(), SHA2_128f(), SHA2_192s(), SHA2_192f(), SHA2_256s(), SHA2_256f(),
+	SHAKE_128s(), SHAKE_128f(), SHAKE_192s(), SHAKE_192f(), SHAKE_256s(), SHAKE_256f(),
+}
+
+func fipsParameters(params Parameters) (*slhdsa.Parameters, error) {
+	switch params {
+	case SHA2_128s():
+		return slhdsa.SHA2_128s, nil
+	case SHA2_128f():
+		return slhdsa.SHA2_128f, nil
+	case SHA2_192s():
+		return slhdsa.SHA2_192s, nil
+	case SHA2_192f():
+		return slhdsa.SHA2_192f, nil
+	case SHA2_256s():
+		return slhdsa.SHA2_256s, nil
+	case SHA2_256f():
+		return slhdsa.SHA2_256f, nil
+	case SHAKE_128s():
+		return slhdsa.SHAKE_128s, nil
+	case SHAKE_128f():
+		return slhdsa.SHAKE_128f, nil
+	case SHAKE_192s():
+		return slhdsa.SHAKE_192s, nil
+	case SHAKE_192f():
+		return slhdsa.SHAKE_192f, nil
+	case SHAKE_256s():
+		return slhdsa.SHAKE_256s, nil
+	case SHAKE_256f():
+		return slhdsa.SHAKE_256f, nil
+	default:
+		return nil, errInvalidParameters
+	}
+}
+
+// GenerateKey generates a new random SLH-DSA private key.
+func GenerateKey(params Parameters) (*PrivateKey, error) {
+	p, err := fipsParameters(params)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{k: *slhdsa.GenerateKey(p)}, nil
+}
+
+// NewPrivateKey decodes an SLH-DSA private key from the given encoding,
+// as returned by [PrivateKey.Bytes].
+//
+// The encoding must be exactly params.PrivateKeySize() bytes long. The public
+// key it contains is checked against the rest of the private key, which costs
+// about as much as generating a key.
+func NewPrivateKey(params Parameters, encoding []byte) (*PrivateKey, error) {
+	p, err := fipsParameters(params)
+	if err != nil {
+		return nil, err
+	}
+	k, err := slhdsa.NewPrivateKey(p, encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{k: *k}, nil
+}
+
+// Public returns the corresponding [PublicKey] for this private key.
+//
+// It implements the [crypto.Signer] interface.
+func (sk *PrivateKey) Public() crypto.PublicKey {
+	return sk.PublicKey()
+}
+
+// Equal reports whether sk and x are the same key.
+//
+// If x is not a *PrivateKey, Equal returns false.
+func (sk *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	if !ok || other == nil {
+		return false
+	}
+	return sk.k.Equal(&other.k)
+}
+
+// PublicKey returns the corresponding [PublicKey] for this private key.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{p: *sk.k.PublicKey()}
+}
+
+// Bytes returns the private key encoding, the concatenation of the
+// SK.seed, SK.prf, PK.seed, and PK.root values defined in FIPS 205.
+func (sk *PrivateKey) Bytes() []byte {
+	return sk.k.Bytes()
+}
+
+var errInvalidSignerOpts = errors.New("slhdsa: invalid SignerOpts")
+
+// Sign returns a signature of the given message using this private key.
+//
+// opts.HashFunc must return zero, and the message is signed directly. opts can
+// be nil, or of type *[Options] if a context string is desired. The io.Reader
+// argument is ignored.
+func (sk *PrivateKey) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	context, err := signerContext(&sk.k, opts)
+	if err != nil {
+		return nil, err
+	}
+	return slhdsa.Sign(&sk.k, message, context)
+}
+
+// SignDeterministic works like [PrivateKey.Sign], but the signature is
+// deterministic.
+func (sk *PrivateKey) SignDeterministic(message []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	context, err := signerContext(&sk.k, opts)
+	if err != nil {
+		return nil, err
+	}
+	return slhdsa.SignDeterministic(&sk.k, message, context)
+}
+
+func signerContext(k *slhdsa.PrivateKey, opts crypto.SignerOpts) (string, error) {
+	if k.PublicKey().Parameters() == nil {
+		return "", errors.New("slhdsa: zero private key")
+	}
+	if opts == nil {
+		return "", nil
+	}
+	if opts.HashFunc() != 0 {
+		return "", errInvalidSignerOpts
+	}
+	if opts, ok := opts.(*Options); ok && opts != nil {
+		return opts.Context, nil
+	}
+	return "", nil
+}
+
+// PublicKey is an SLH-DSA public key. It implements the informal extended
+// [crypto.PublicKey] interface.
+//
+// A PublicKey is safe for concurrent use.
+type PublicKey struct {
+	p slhdsa.PublicKey
+}
+
+// NewPublicKey creates a new SLH-DSA public key from the given encoding.
+func NewPublicKey(params Parameters, encoding []byte) (*PublicKey, error) {
+	p, err := fipsParameters(params)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := slhdsa.NewPublicKey(p, encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{p: *pk}, nil
+}
+
+// Bytes returns the public key encoding, the concatenation of the PK.seed and
+// PK.root values defined in FIPS 205.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.p.Bytes()
+}
+
+// Equal reports whether pk and x are the same key (i.e. they have the same
+// parameters and encoding).
+//
+// If x is not a *PublicKey, Equal returns false.
+func (pk *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	if !ok || other == nil {
+		return false
+	}
+	return pk.p.Equal(&other.p)
+}
+
+// Parameters returns the parameters associated with this public key.
+func (pk *PublicKey) Parameters() Parameters {
+	p := pk.p.Parameters()
+	if p == nil {
+		panic("slhdsa: zero public key")
+	}
+	for _, params := range allParameters {
+		if params.name == p.Name() {
+			return params
+		}
+	}
+	panic("slhdsa: invalid parameters in public key")
+}
+
+// Verify reports whether signature is a valid signature of message by pk.
+// If opts is nil, it's equivalent to the zero value of Options.
+func Verify(pk *PublicKey, message []byte, signature []byte, opts *Options) error {
+	if pk == nil {
+		return errors.New("slhdsa: nil public key")
+	}
+	if pk.p.Parameters() == nil {
+		return errors.New("slhdsa: zero public key")
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	return slhdsa.Verify(&pk.p, message, signature, opts.Context)
+}
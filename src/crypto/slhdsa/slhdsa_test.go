@@ -0,0 +1,341 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(fips140v1.0 || fips140v1.26)
+
+package slhdsa_test
+
+import (
+	"bytes"
+	"crypto"
+	. "crypto/slhdsa"
+	"strings"
+	"testing"
+)
+
+var _ crypto.Signer = (*PrivateKey)(nil)
+
+var allParameters = []Parameters{
+	SHA2_128s(), SHA2_128f(), SHA2_192s(), SHA2_192f(), SHA2_256s(), SHA2_256f(),
+	SHAKE_128s(), SHAKE_128f(), SHAKE_192s(), SHAKE_192f(), SHAKE_256s(), SHAKE_256f(),
+}
+
+// testAllParameters runs f for every parameter set. The "s" parameter sets
+// are slow to sign with, so only SLH-DSA-SHA2-128s is tested in short mode.
+func testAllParameters(t *testing.T, f func(*testing.T, Parameters)) {
+	for _, params := range allParameters {
+		t.Run(params.String(), func(t *testing.T) {
+			if testing.Short() && strings.HasSuffix(params.String(), "s") && params != SHA2_128s() {
+				t.Skip("slow parameter set in short mode")
+			}
+			f(t, params)
+		})
+	}
+}
+
+func TestSizes(t *testing.T) {
+	for _, tc := range []struct {
+		params  Parameters
+		pkSize  int
+		skSize  int
+		sigSize int
+	}{
+		{SHA2_128s(), 32, 64, 7856},
+		{SHA2_128f(), 32, 64, 17088},
+		{SHA2_192s(), 48, 96, 16224},
+		{SHA2_192f(), 48, 96, 35664},
+		{SHA2_256s(), 64, 128, 29792},
+		{SHA2_256f(), 64, 128, 49856},
+		{SHAKE_128s(), 32, 64, 7856},
+		{SHAKE_128f(), 32, 64, 17088},
+		{SHAKE_192s(), 48, 96, 16224},
+		{SHAKE_192f(), 48, 96, 35664},
+		{SHAKE_256s(), 64, 128, 29792},
+		{SHAKE_256f(), 64, 128, 49856},
+	} {
+		if got := tc.params.PublicKeySize(); got != tc.pkSize {
+			t.Errorf("%v PublicKeySize() = %d, want %d", tc.params, got, tc.pkSize)
+		}
+		if got := tc.params.PrivateKeySize(); got != tc.skSize {
+			t.Errorf("%v PrivateKeySize() = %d, want %d", tc.params, got, tc.skSize)
+		}
+		if got := tc.params.SignatureSize(); got != tc.sigSize {
+			t.Errorf("%v SignatureSize() = %d, want %d", tc.params, got, tc.sigSize)
+		}
+	}
+}
+
+func TestParametersIdentity(t *testing.T) {
+	for i, a := range allParameters {
+		for j, b := range allParameters {
+			if (a == b) != (i == j) {
+				t.Errorf("%v == %v is %v", a, b, a == b)
+			}
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	testAllParameters(t, testSignVerify)
+}
+
+func testSignVerify(t *testing.T, params Parameters) {
+	sk, err := GenerateKey(params)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	pk := sk.PublicKey()
+	if pk.Parameters() != params {
+		t.Errorf("Parameters() = %v, want %v", pk.Parameters(), params)
+	}
+	if got := len(pk.Bytes()); got != params.PublicKeySize() {
+		t.Errorf("len(PublicKey.Bytes()) = %d, want %d", got, params.PublicKeySize())
+	}
+	if got := len(sk.Bytes()); got != params.PrivateKeySize() {
+		t.Errorf("len(PrivateKey.Bytes()) = %d, want %d", got, params.PrivateKeySize())
+	}
+
+	msg := []byte("message")
+	opts := &Options{Context: "context"}
+	sig, err := sk.Sign(nil, msg, opts)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if len(sig) != params.SignatureSize() {
+		t.Errorf("len(signature) = %d, want %d", len(sig), params.SignatureSize())
+	}
+	if err := Verify(pk, msg, sig, opts); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := Verify(pk, msg, sig, nil); err == nil {
+		t.Errorf("Verify with wrong context succeeded")
+	}
+	if err := Verify(pk, []byte("other message"), sig, opts); err == nil {
+		t.Errorf("Verify with wrong message succeeded")
+	}
+	sig[len(sig)-1] ^= 1
+	if err := Verify(pk, msg, sig, opts); err == nil {
+		t.Errorf("Verify with modified signature succeeded")
+	}
+	if err := Verify(pk, msg, sig[:len(sig)-1], opts); err == nil {
+		t.Errorf("Verify with truncated signature succeeded")
+	}
+}
+
+func TestSignDeterministic(t *testing.T) {
+	sk, err := GenerateKey(SHA2_128f())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("message")
+	sig1, err := sk.SignDeterministic(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := sk.SignDeterministic(msg, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig1, sig2) {
+		t.Errorf("deterministic signatures differ")
+	}
+	sig3, err := sk.Sign(nil, msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sig1, sig3) {
+		t.Errorf("randomized signature equals deterministic signature")
+	}
+	for _, sig := range [][]byte{sig1, sig3} {
+		if err := Verify(sk.PublicKey(), msg, sig, nil); err != nil {
+			t.Errorf("Verify: %v", err)
+		}
+	}
+}
+
+func TestSignerOpts(t *testing.T) {
+	sk, err := GenerateKey(SHA2_128f())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sk.Sign(nil, []byte("message"), crypto.SHA256); err == nil {
+		t.Errorf("Sign with crypto.SHA256 succeeded")
+	}
+	if _, err := sk.SignDeterministic([]byte("message"), crypto.SHA256); err == nil {
+		t.Errorf("SignDeterministic with crypto.SHA256 succeeded")
+	}
+	if _, err := sk.Sign(nil, []byte("message"), &Options{Context: strings.Repeat("x", 256)}); err == nil {
+		t.Errorf("Sign with 256-byte context succeeded")
+	}
+	if _, err := sk.Sign(nil, []byte("message"), crypto.Hash(0)); err != nil {
+		t.Errorf("Sign with crypto.Hash(0): %v", err)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	testAllParameters(t, testEncoding)
+}
+
+func testEncoding(t *testing.T, params Parameters) {
+	sk, err := GenerateKey(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk2, err := NewPrivateKey(params, sk.Bytes())
+	if err != nil {
+		t.Fatalf("NewPrivateKey: %v", err)
+	}
+	if !sk.Equal(sk2) {
+		t.Errorf("re-parsed private key is not equal")
+	}
+	pk, err := NewPublicKey(params, sk.PublicKey().Bytes())
+	if err != nil {
+		t.Fatalf("NewPublicKey: %v", err)
+	}
+	if !pk.Equal(sk.PublicKey()) || !sk.PublicKey().Equal(pk) {
+		t.Errorf("re-parsed public key is not equal")
+	}
+
+	other, err := GenerateKey(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk.Equal(other) || sk.PublicKey().Equal(other.PublicKey()) {
+		t.Errorf("two generated keys are equal")
+	}
+
+	// A private key whose public key doesn't match its seeds is rejected.
+	b := sk.Bytes()
+	b[len(b)-1] ^= 1
+	if _, err := NewPrivateKey(params, b); err == nil {
+		t.Errorf("NewPrivateKey with modified PK.root succeeded")
+	}
+}
+
+func TestInvalidSize(t *testing.T) {
+	params := SHA2_128f()
+	for _, size := range []int{0, params.PublicKeySize() - 1, params.PublicKeySize() + 1} {
+		if _, err := NewPublicKey(params, make([]byte, size)); err == nil {
+			t.Errorf("NewPublicKey with %d bytes succeeded", size)
+		}
+	}
+	for _, size := range []int{0, params.PrivateKeySize() - 1, params.PrivateKeySize() + 1} {
+		if _, err := NewPrivateKey(params, make([]byte, size)); err == nil {
+			t.Errorf("NewPrivateKey with %d bytes succeeded", size)
+		}
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	var params Parameters
+	if _, err := GenerateKey(params); err == nil {
+		t.Errorf("GenerateKey with zero Parameters succeeded")
+	}
+	if _, err := NewPrivateKey(params, nil); err == nil {
+		t.Errorf("NewPrivateKey with zero Parameters succeeded")
+	}
+	if _, err := NewPublicKey(params, nil); err == nil {
+		t.Errorf("NewPublicKey with zero Parameters succeeded")
+	}
+}
+
+func TestMismatchedParameters(t *testing.T) {
+	// The SHA2 and SHAKE parameter sets have the same sizes, but
+	// keys and signatures are not interchangeable.
+	sk, err := GenerateKey(SHA2_128f())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPrivateKey(SHAKE_128f(), sk.Bytes()); err == nil {
+		t.Errorf("NewPrivateKey with mismatched parameters succeeded")
+	}
+	pk, err := NewPublicKey(SHAKE_128f(), sk.PublicKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.Equal(sk.PublicKey()) {
+		t.Errorf("public keys with different parameters are equal")
+	}
+	sig, err := sk.Sign(nil, []byte("message"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(pk, []byte("message"), sig, nil); err == nil {
+		t.Errorf("Verify with mismatched parameters succeeded")
+	}
+}
+
+func TestEqualWrongType(t *testing.T) {
+	sk, err := GenerateKey(SHA2_128f())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk.Equal(sk.PublicKey()) {
+		t.Errorf("PrivateKey.Equal(PublicKey) = true")
+	}
+	if sk.Equal((*PrivateKey)(nil)) {
+		t.Errorf("PrivateKey.Equal(nil) = true")
+	}
+	if sk.PublicKey().Equal(sk) {
+		t.Errorf("PublicKey.Equal(PrivateKey) = true")
+	}
+	if sk.PublicKey().Equal((*PublicKey)(nil)) {
+		t.Errorf("PublicKey.Equal(nil) = true")
+	}
+}
+
+func TestUninitialized(t *testing.T) {
+	var sk PrivateKey
+	if _, err := sk.Sign(nil, []byte("message"), nil); err == nil {
+		t.Errorf("Sign with zero PrivateKey succeeded")
+	}
+	if _, err := sk.SignDeterministic([]byte("message"), nil); err == nil {
+		t.Errorf("SignDeterministic with zero PrivateKey succeeded")
+	}
+	if err := Verify(&PublicKey{}, []byte("message"), nil, nil); err == nil {
+		t.Errorf("Verify with zero PublicKey succeeded")
+	}
+	if err := Verify(nil, []byte("message"), nil, nil); err == nil {
+		t.Errorf("Verify with nil PublicKey succeeded")
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	for _, params := range allParameters {
+		b.Run(params.String(), func(b *testing.B) {
+			sk, err := GenerateKey(params)
+			if err != nil {
+				b.Fatal(err)
+			}
+			msg := []byte("Hello, world!")
+			for b.Loop() {
+				if _, err := sk.Sign(nil, msg, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	for _, params := range allParameters {
+		b.Run(params.String(), func(b *testing.B) {
+			sk, err := GenerateKey(params)
+			if err != nil {
+				b.Fatal(err)
+			}
+			msg := []byte("Hello, world!")
+			sig, err := sk.Sign(nil, msg, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			pk := sk.PublicKey()
+			for b.Loop() {
+				if err := Verify(pk, msg, sig, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"crypto/elliptic"
 	"crypto/mldsa"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"errors"
 	"fmt"
 	"hash"
@@ -54,6 +55,14 @@ func verifyHandshakeSignature(sigType uint8, pubkey crypto.PublicKey, hashFunc c
 		if err := mldsa.Verify(pubKey, signed, sig, nil); err != nil {
 			return fmt.Errorf("ML-DSA verification failure: %w", err)
 		}
+	case signatureSLHDSA:
+		pubKey, ok := pubkey.(*slhdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("expected an SLH-DSA public key, got %T", pubkey)
+		}
+		if err := slhdsa.Verify(pubKey, signed, sig, nil); err != nil {
+			return fmt.Errorf("SLH-DSA verification failure: %w", err)
+		}
 	case signaturePKCS1v15:
 		pubKey, ok := pubkey.(*rsa.PublicKey)
 		if !ok {
@@ -144,6 +153,8 @@ func typeAndHashFromSignatureScheme(signatureAlgorithm SignatureScheme) (sigType
 		sigType = signatureEd25519
 	case MLDSA44, MLDSA65, MLDSA87:
 		sigType = signatureMLDSA
+	case SLHDSA_SHA2_128s, SLHDSA_SHA2_128f, SLHDSA_SHA2_192s, SLHDSA_SHA2_192f, SLHDSA_SHA2_256s, SLHDSA_SHA2_256f, SLHDSA_SHAKE_128s, SLHDSA_SHAKE_128f, SLHDSA_SHAKE_192s, SLHDSA_SHAKE_192f, SLHDSA_SHAKE_256s, SLHDSA_SHAKE_256f:
+		sigType = signatureSLHDSA
 	default:
 		return 0, 0, fmt.Errorf("unsupported signature algorithm: %v", signatureAlgorithm)
 	}
@@ -160,6 +171,8 @@ func typeAndHashFromSignatureScheme(signatureAlgorithm SignatureScheme) (sigType
 		hash = directSigning
 	case MLDSA44, MLDSA65, MLDSA87:
 		hash = directSigning
+	case SLHDSA_SHA2_128s, SLHDSA_SHA2_128f, SLHDSA_SHA2_192s, SLHDSA_SHA2_192f, SLHDSA_SHA2_256s, SLHDSA_SHA2_256f, SLHDSA_SHAKE_128s, SLHDSA_SHAKE_128f, SLHDSA_SHAKE_192s, SLHDSA_SHAKE_192f, SLHDSA_SHAKE_256s, SLHDSA_SHAKE_256f:
+		hash = directSigning
 	default:
 		return 0, 0, fmt.Errorf("unsupported signature algorithm: %v", signatureAlgorithm)
 	}
@@ -183,6 +196,8 @@ func legacyTypeAndHashFromPublicKey(pub crypto.PublicKey) (sigType uint8, hash c
 		return 0, 0, fmt.Errorf("tls: Ed25519 public keys are not supported before TLS 1.2")
 	case *mldsa.PublicKey:
 		return 0, 0, fmt.Errorf("tls: ML-DSA public keys are not supported before TLS 1.3")
+	case *slhdsa.PublicKey:
+		return 0, 0, fmt.Errorf("tls: SLH-DSA public keys are not supported before TLS 1.3")
 	default:
 		return 0, 0, fmt.Errorf("tls: unsupported public key: %T", pub)
 	}
@@ -250,6 +265,35 @@ func signatureSchemesForPublicKey(version uint16, pub crypto.PublicKey) []Signat
 		default:
 			panic("tls: internal error: unknown ML-DSA parameter set: " + pub.Parameters().String())
 		}
+	case *slhdsa.PublicKey:
+		switch pub.Parameters() {
+		case slhdsa.SHA2_128s():
+			return []SignatureScheme{SLHDSA_SHA2_128s}
+		case slhdsa.SHA2_128f():
+			return []SignatureScheme{SLHDSA_SHA2_128f}
+		case slhdsa.SHA2_192s():
+			return []SignatureScheme{SLHDSA_SHA2_192s}
+		case slhdsa.SHA2_192f():
+			return []SignatureScheme{SLHDSA_SHA2_192f}
+		case slhdsa.SHA2_256s():
+			return []SignatureScheme{SLHDSA_SHA2_256s}
+		case slhdsa.SHA2_256f():
+			return []SignatureScheme{SLHDSA_SHA2_256f}
+		case slhdsa.SHAKE_128s():
+			return []SignatureScheme{SLHDSA_SHAKE_128s}
+		case slhdsa.SHAKE_128f():
+			return []SignatureScheme{SLHDSA_SHAKE_128f}
+		case slhdsa.SHAKE_192s():
+			return []SignatureScheme{SLHDSA_SHAKE_192s}
+		case slhdsa.SHAKE_192f():
+			return []SignatureScheme{SLHDSA_SHAKE_192f}
+		case slhdsa.SHAKE_256s():
+			return []SignatureScheme{SLHDSA_SHAKE_256s}
+		case slhdsa.SHAKE_256f():
+			return []SignatureScheme{SLHDSA_SHAKE_256f}
+		default:
+			panic("tls: internal error: unknown SLH-DSA parameter set: " + pub.Parameters().String())
+		}
 	default:
 		return nil
 	}
@@ -328,6 +372,8 @@ func unsupportedCertificateError(cert *Certificate) error {
 	case ed25519.PublicKey:
 	case *mldsa.PublicKey:
 		return errors.New("tls: ML-DSA certificates require TLS 1.3")
+	case *slhdsa.PublicKey:
+		return errors.New("tls: SLH-DSA certificates require TLS 1.3")
 	default:
 		return fmt.Errorf("tls: unsupported certificate key (%T)", pub)
 	}
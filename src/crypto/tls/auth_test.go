@@ -9,10 +9,16 @@ import (
 	"crypto/fips140"
 	"crypto/internal/cryptotest"
 	"crypto/mldsa"
+	"crypto/rand"
+	"crypto/slhdsa"
 	"crypto/tls/internal/fips140tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"internal/testenv"
+	"math/big"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestSignatureSelection(t *testing.T) {
@@ -192,7 +198,7 @@ func TestLegacyTypeAndHash(t *testing.T) {
 // TestSupportedSignatureAlgorithms checks that all supportedSignatureAlgorithms
 // have valid type and hash information.
 func TestSupportedSignatureAlgorithms(t *testing.T) {
-	for _, sigAlg := range supportedSignatureAlgorithms(VersionTLS12, VersionTLS13) {
+	for _, sigAlg := range defaultConfig().supportedSignatureAlgorithms(VersionTLS12, VersionTLS13) {
 		sigType, hash, err := typeAndHashFromSignatureScheme(sigAlg)
 		if err != nil {
 			t.Errorf("%v: unexpected error: %v", sigAlg, err)
@@ -200,8 +206,117 @@ func TestSupportedSignatureAlgorithms(t *testing.T) {
 		if sigType == 0 {
 			t.Errorf("%v: missing signature type", sigAlg)
 		}
-		if hash == 0 && sigAlg != Ed25519 && sigAlg != MLDSA44 && sigAlg != MLDSA65 && sigAlg != MLDSA87 {
+		if hash == 0 && sigAlg != Ed25519 && sigAlg != MLDSA44 && sigAlg != MLDSA65 && sigAlg != MLDSA87 && sigType != signatureSLHDSA {
 			t.Errorf("%v: missing hash", sigAlg)
 		}
 	}
 }
+
+func TestSignatureSelectionSLHDSA(t *testing.T) {
+	cryptotest.MustMinimumFIPS140ModuleVersion(t, "v1.28.0")
+
+	cert := testSLHDSACert(t, slhdsa.SHA2_128f())
+
+	sigAlg, err := selectSignatureScheme(VersionTLS13, &cert, []SignatureScheme{MLDSA44, SLHDSA_SHA2_128f})
+	if err != nil {
+		t.Fatalf("unexpected selectSignatureScheme error: %v", err)
+	}
+	if sigAlg != SLHDSA_SHA2_128f {
+		t.Errorf("expected signature scheme %v, got %v", SLHDSA_SHA2_128f, sigAlg)
+	}
+	sigType, hashFunc, err := typeAndHashFromSignatureScheme(sigAlg)
+	if err != nil {
+		t.Fatalf("unexpected typeAndHashFromSignatureScheme error: %v", err)
+	}
+	if sigType != signatureSLHDSA || hashFunc != directSigning {
+		t.Errorf("expected signature type %#x and direct signing, got %#x and %#x", signatureSLHDSA, sigType, hashFunc)
+	}
+
+	for _, test := range []struct {
+		peerSigAlgs []SignatureScheme
+		tlsVersion  uint16
+	}{
+		// SLH-DSA requires TLS 1.3.
+		{[]SignatureScheme{SLHDSA_SHA2_128f}, VersionTLS12},
+		// The parameter set must match.
+		{[]SignatureScheme{SLHDSA_SHAKE_128f}, VersionTLS13},
+		{[]SignatureScheme{SLHDSA_SHA2_128s}, VersionTLS13},
+		{nil, VersionTLS13},
+	} {
+		if sigAlg, err := selectSignatureScheme(test.tlsVersion, &cert, test.peerSigAlgs); err == nil {
+			t.Errorf("%v %v: unexpected success, got %v", VersionName(test.tlsVersion), test.peerSigAlgs, sigAlg)
+		}
+	}
+
+	if _, _, err := legacyTypeAndHashFromPublicKey(cert.PrivateKey.(crypto.Signer).Public()); err == nil {
+		t.Errorf("SLH-DSA: unexpected success from legacyTypeAndHashFromPublicKey")
+	}
+}
+
+func TestSLHDSAHandshake(t *testing.T) {
+	cryptotest.MustMinimumFIPS140ModuleVersion(t, "v1.28.0")
+
+	serverConfig := &Config{
+		Time:         testTime,
+		Certificates: []Certificate{testSLHDSACert(t, slhdsa.SHA2_128f())},
+		ClientAuth:   RequireAndVerifyClientCert,
+		ClientCAs:    testRootCertPool,
+	}
+	clientConfig := &Config{
+		Time:         testTime,
+		Certificates: []Certificate{testSLHDSACert(t, slhdsa.SHAKE_128f())},
+		RootCAs:      testRootCertPool,
+		ServerName:   "test.golang.example",
+	}
+
+	// SLH-DSA is not advertised by default.
+	if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+		t.Fatal("handshake succeeded without SLH-DSA in SignatureSchemes")
+	}
+
+	clientConfig.SignatureSchemes = []SignatureScheme{SLHDSA_SHA2_128f}
+	serverConfig.SignatureSchemes = []SignatureScheme{SLHDSA_SHAKE_128f}
+	serverState, _, err := testHandshake(t, clientConfig, serverConfig)
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	if len(serverState.PeerCertificates) == 0 || serverState.PeerCertificates[0].PublicKeyAlgorithm != x509.SLHDSA {
+		t.Errorf("server did not receive the SLH-DSA client certificate")
+	}
+
+	// SLH-DSA requires TLS 1.3.
+	clientConfig.MaxVersion = VersionTLS12
+	if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+		t.Fatal("TLS 1.2 handshake with an SLH-DSA certificate succeeded")
+	}
+}
+
+// testSLHDSACert returns an SLH-DSA leaf for test.golang.example issued by
+// testRootCert. SLH-DSA keys are generated at test time rather than checked
+// into certificates_test.go, since signing with the small parameter sets is
+// too slow to use them in every handshake test.
+func testSLHDSACert(t testing.TB, params slhdsa.Parameters) Certificate {
+	t.Helper()
+	key, err := slhdsa.GenerateKey(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: params.String()},
+		DNSNames:     []string{"test.golang.example"},
+		NotBefore:    testTime().Add(-time.Hour),
+		NotAfter:     testTime().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, testRootCert.Leaf, key.PublicKey(), testRootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
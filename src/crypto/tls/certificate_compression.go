@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"compress/zlib"
+	"compress/zstd"
+	"errors"
+	"io"
+	"slices"
+)
+
+// certCompressionAlgorithms returns the supported algorithms in
+// c.CertificateCompression, in order of preference.
+func (c *Config) certCompressionAlgorithms() []CertificateCompressionAlgorithm {
+	if c == nil {
+		return nil
+	}
+	var algs []CertificateCompressionAlgorithm
+	for _, alg := range c.CertificateCompression {
+		switch alg {
+		case CertificateCompressionZlib, CertificateCompressionZstd:
+		default:
+			// Ignore unimplemented entries.
+			continue
+		}
+		if !slices.Contains(algs, alg) {
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// compressCertificate returns certMsg compressed with the most preferred
+// algorithm that the peer advertised in peerAlgs, or certMsg itself if there
+// is no mutual algorithm or if compression doesn't make it any smaller.
+func (c *Conn) compressCertificate(certMsg *certificateMsgTLS13, peerAlgs []CertificateCompressionAlgorithm) (handshakeMessage, error) {
+	algs := c.config.certCompressionAlgorithms()
+	i := slices.IndexFunc(algs, func(alg CertificateCompressionAlgorithm) bool {
+		return slices.Contains(peerAlgs, alg)
+	})
+	if i < 0 || len(certMsg.certificate.Certificate) == 0 {
+		return certMsg, nil
+	}
+	alg := algs[i]
+
+	data, err := certMsg.marshal()
+	if err != nil {
+		return nil, err
+	}
+	body := data[4:] // message type and uint24 length field
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch alg {
+	case CertificateCompressionZlib:
+		w = zlib.NewWriter(&buf)
+	case CertificateCompressionZstd:
+		w = zstd.NewWriter(&buf)
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if buf.Len() >= len(body) {
+		return certMsg, nil
+	}
+
+	return &compressedCertificateMsg{
+		algorithm:          alg,
+		uncompressedLength: uint32(len(body)),
+		compressed:         buf.Bytes(),
+	}, nil
+}
+
+// decompressCertificate decompresses a CompressedCertificate message, which
+// must use one of the algorithms in c.config.CertificateCompression, since
+// those are the ones advertised to the peer.
+func (c *Conn) decompressCertificate(msg *compressedCertificateMsg) (*certificateMsgTLS13, error) {
+	if !slices.Contains(c.config.certCompressionAlgorithms(), msg.algorithm) {
+		c.sendAlert(alertIllegalParameter)
+		return nil, errors.New("tls: peer compressed certificate with an algorithm that was not advertised")
+	}
+	if msg.uncompressedLength > maxHandshakeCertificateMsg {
+		c.sendAlert(alertBadCertificate)
+		return nil, errors.New("tls: compressed certificate message is too large")
+	}
+
+	var r io.Reader
+	switch msg.algorithm {
+	case CertificateCompressionZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(msg.compressed))
+		if err != nil {
+			c.sendAlert(alertBadCertificate)
+			return nil, errors.New("tls: failed to decompress certificate: " + err.Error())
+		}
+		defer zr.Close()
+		r = zr
+	case CertificateCompressionZstd:
+		r = zstd.NewReader(bytes.NewReader(msg.compressed))
+	}
+
+	// Decompress into a buffer that already has room for the Certificate
+	// message header, so that it can be parsed like any other message.
+	data := make([]byte, 4+msg.uncompressedLength)
+	data[0] = typeCertificate
+	data[1] = byte(msg.uncompressedLength >> 16)
+	data[2] = byte(msg.uncompressedLength >> 8)
+	data[3] = byte(msg.uncompressedLength)
+	if _, err := io.ReadFull(r, data[4:]); err != nil {
+		c.sendAlert(alertBadCertificate)
+		return nil, errors.New("tls: failed to decompress certificate: " + err.Error())
+	}
+	if _, err := io.ReadFull(r, make([]byte, 1)); err != io.EOF {
+		c.sendAlert(alertBadCertificate)
+		return nil, errors.New("tls: decompressed certificate does not match the declared length")
+	}
+
+	certMsg := new(certificateMsgTLS13)
+	if !certMsg.unmarshal(data) {
+		c.sendAlert(alertDecodeError)
+		return nil, errors.New("tls: failed to parse decompressed certificate message")
+	}
+	return certMsg, nil
+}
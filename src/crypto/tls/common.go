@@ -17,6 +17,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha512"
+	"crypto/slhdsa"
 	"crypto/tls/internal/fips140tls"
 	"crypto/x509"
 	"errors"
@@ -85,22 +86,23 @@ const (
 
 // TLS handshake message types.
 const (
-	typeHelloRequest        uint8 = 0
-	typeClientHello         uint8 = 1
-	typeServerHello         uint8 = 2
-	typeNewSessionTicket    uint8 = 4
-	typeEndOfEarlyData      uint8 = 5
-	typeEncryptedExtensions uint8 = 8
-	typeCertificate         uint8 = 11
-	typeServerKeyExchange   uint8 = 12
-	typeCertificateRequest  uint8 = 13
-	typeServerHelloDone     uint8 = 14
-	typeCertificateVerify   uint8 = 15
-	typeClientKeyExchange   uint8 = 16
-	typeFinished            uint8 = 20
-	typeCertificateStatus   uint8 = 22
-	typeKeyUpdate           uint8 = 24
-	typeMessageHash         uint8 = 254 // synthetic message
+	typeHelloRequest          uint8 = 0
+	typeClientHello           uint8 = 1
+	typeServerHello           uint8 = 2
+	typeNewSessionTicket      uint8 = 4
+	typeEndOfEarlyData        uint8 = 5
+	typeEncryptedExtensions   uint8 = 8
+	typeCertificate           uint8 = 11
+	typeServerKeyExchange     uint8 = 12
+	typeCertificateRequest    uint8 = 13
+	typeServerHelloDone       uint8 = 14
+	typeCertificateVerify     uint8 = 15
+	typeClientKeyExchange     uint8 = 16
+	typeFinished              uint8 = 20
+	typeCertificateStatus     uint8 = 22
+	typeKeyUpdate             uint8 = 24
+	typeCompressedCertificate uint8 = 25
+	typeMessageHash           uint8 = 254 // synthetic message
 )
 
 // TLS compression types.
@@ -118,6 +120,7 @@ const (
 	extensionALPN                    uint16 = 16
 	extensionSCT                     uint16 = 18
 	extensionExtendedMasterSecret    uint16 = 23
+	extensionCompressCertificate     uint16 = 27
 	extensionSessionTicket           uint16 = 35
 	extensionPreSharedKey            uint16 = 41
 	extensionEarlyData               uint16 = 42
@@ -176,6 +179,15 @@ func isPQKeyExchange(curve CurveID) bool {
 	}
 }
 
+// CertificateCompressionAlgorithm is the type of a TLS identifier for a
+// certificate compression algorithm. See RFC 8879, Section 3.
+type CertificateCompressionAlgorithm uint16
+
+const (
+	CertificateCompressionZlib CertificateCompressionAlgorithm = 1
+	CertificateCompressionZstd CertificateCompressionAlgorithm = 3
+)
+
 // TLS 1.3 Key Share. See RFC 8446, Section 4.2.8.
 type keyShare struct {
 	group CurveID
@@ -220,11 +232,13 @@ const (
 	signatureECDSA
 	signatureEd25519
 	signatureMLDSA
+	signatureSLHDSA
 )
 
 // directSigning is a standard Hash value that signals that no pre-hashing
 // should be performed, and that the input should be signed directly. It is the
-// hash function associated with the Ed25519 and ML-DSA signature schemes.
+// hash function associated with the Ed25519, ML-DSA, and SLH-DSA signature
+// schemes.
 var directSigning crypto.Hash = 0
 
 // helloRetryRequestRandom is set as the Random value of a ServerHello
@@ -285,9 +299,11 @@ type ConnectionState struct {
 	// order in which they were sent. The first element is the leaf certificate
 	// that the connection is verified against.
 	//
-	// On the client side, it can't be empty. On the server side, it can be
-	// empty if Config.ClientAuth is not RequireAnyClientCert or
-	// RequireAndVerifyClientCert.
+	// On the client side, it can't be empty, unless the connection was
+	// authenticated with an external PSK. On the server side, it can be empty
+	// if Config.ClientAuth is not RequireAnyClientCert or
+	// RequireAndVerifyClientCert, or if the connection was authenticated with
+	// an external PSK.
 	//
 	// PeerCertificates and its contents should not be modified.
 	PeerCertificates []*x509.Certificate
@@ -325,11 +341,19 @@ type ConnectionState struct {
 	// are a server, or if we received a HelloRetryRequest if we are a client.
 	HelloRetryRequest bool
 
+	// ExternalPSKIdentity is the identity of the external PSK that
+	// authenticated the connection, if any. See [Config.ExternalPSKs].
+	ExternalPSKIdentity []byte
+
 	// LocalCertificate is the certificate chain presented to the peer, if any,
 	// during the handshake. This field is only populated for connections which
 	// are not resumed (DidResume is false).
 	LocalCertificate [][]byte
 
+	// KernelTLS indicates whether the records sent on the connection are
+	// encrypted by the kernel. See [Config.KernelTLS].
+	KernelTLS bool
+
 	// ekm is a closure exposed via ExportKeyingMaterial.
 	ekm func(label string, context []byte, length int) ([]byte, error)
 
@@ -404,7 +428,7 @@ type ClientSessionCache interface {
 	Put(sessionKey string, cs *ClientSessionState)
 }
 
-//go:generate stringer -linecomment -type=SignatureScheme,CurveID,ClientAuthType -output=common_string.go
+//go:generate stringer -linecomment -type=SignatureScheme,CurveID,ClientAuthType,CertificateCompressionAlgorithm -output=common_string.go
 
 // SignatureScheme identifies a signature algorithm supported by TLS. See
 // RFC 8446, Section 4.2.3.
@@ -434,6 +458,20 @@ const (
 	MLDSA65 SignatureScheme = 0x0905
 	MLDSA87 SignatureScheme = 0x0906
 
+	// SLH-DSA algorithms.
+	SLHDSA_SHA2_128s  SignatureScheme = 0x0911
+	SLHDSA_SHA2_128f  SignatureScheme = 0x0912
+	SLHDSA_SHA2_192s  SignatureScheme = 0x0913
+	SLHDSA_SHA2_192f  SignatureScheme = 0x0914
+	SLHDSA_SHA2_256s  SignatureScheme = 0x0915
+	SLHDSA_SHA2_256f  SignatureScheme = 0x0916
+	SLHDSA_SHAKE_128s SignatureScheme = 0x0917
+	SLHDSA_SHAKE_128f SignatureScheme = 0x0918
+	SLHDSA_SHAKE_192s SignatureScheme = 0x0919
+	SLHDSA_SHAKE_192f SignatureScheme = 0x091A
+	SLHDSA_SHAKE_256s SignatureScheme = 0x091B
+	SLHDSA_SHAKE_256f SignatureScheme = 0x091C
+
 	// Legacy signature and hash algorithms for TLS 1.2.
 	PKCS1WithSHA1 SignatureScheme = 0x0201
 	ECDSAWithSHA1 SignatureScheme = 0x0203
@@ -727,6 +765,44 @@ type Config struct {
 	// testing or in combination with VerifyConnection or VerifyPeerCertificate.
 	InsecureSkipVerify bool
 
+	// CertificateTransparency, if not nil, is a Certificate Transparency
+	// policy that the peer's leaf certificate must satisfy. The SCTs embedded
+	// in the certificate, sent by the peer in the TLS extension, and included
+	// in the stapled OCSP response are considered.
+	//
+	// It applies wherever the peer's certificate chain is verified: by clients
+	// unless InsecureSkipVerify is set, and by servers if ClientAuth is
+	// VerifyClientCertIfGiven or RequireAndVerifyClientCert. It is not checked
+	// again on resumed connections.
+	CertificateTransparency *x509.CTPolicy
+
+	// ExternalPSKs contains TLS 1.3 external pre-shared keys. When a
+	// connection is authenticated with an external PSK, no certificates are
+	// exchanged, and VerifyConnection is still called.
+	//
+	// Clients offer all of them, in order, in place of session resumption.
+	// If the server selects none, the handshake falls back to certificate
+	// authentication, which requires ServerName or InsecureSkipVerify to be
+	// set. Servers accept a client offering any of them, unless
+	// GetExternalPSK is set.
+	//
+	// External PSKs always use an (EC)DHE key exchange, for forward secrecy,
+	// and are not used for 0-RTT. Connections authenticated with an external
+	// PSK don't issue or accept session tickets.
+	ExternalPSKs []ExternalPSK
+
+	// GetExternalPSK, if not nil, is called by servers to look up the
+	// external PSK for an identity offered by the client. It returns nil if
+	// the identity is unknown. If GetExternalPSK is set, ExternalPSKs is
+	// ignored by servers.
+	//
+	// For imported PSKs (see [ExternalPSK.Import]), identity is the external
+	// identity from which the offered identity was imported. GetExternalPSK
+	// may be called multiple times for the same identity in a handshake.
+	//
+	// Clients don't use this field.
+	GetExternalPSK func(info *ClientHelloInfo, identity []byte) (*ExternalPSK, error)
+
 	// CipherSuites is a list of enabled TLS 1.0–1.2 cipher suites. The order of
 	// the list is ignored. Note that TLS 1.3 ciphersuites are not configurable.
 	//
@@ -820,12 +896,58 @@ type Config struct {
 	// GODEBUG=tlsmlkem=0 or the GODEBUG=tlssecpmlkem=0 environment variable.
 	CurvePreferences []CurveID
 
+	// SignatureSchemes contains the signature algorithms that are advertised
+	// in ClientHello and CertificateRequest messages, and accepted for the
+	// signatures sent by the peer. The order of the list is ignored, and
+	// signature algorithms are advertised using an internal preference order.
+	// If empty, the default will be used.
+	//
+	// The default doesn't include the SLH-DSA signature algorithms, such as
+	// [SLHDSA_SHA2_128s], because of the size of their signatures. To enable
+	// them, set SignatureSchemes explicitly. Certificates with SLH-DSA keys
+	// are used with peers that advertise SLH-DSA regardless of this setting.
+	SignatureSchemes []SignatureScheme
+
+	// CertificateCompression contains the certificate compression algorithms
+	// that can be used in TLS 1.3 connections, in order of preference. See
+	// RFC 8879.
+	//
+	// The algorithms are advertised to the peer, which may then send its
+	// certificate chain compressed with one of them, and the first one also
+	// supported by the peer is used to compress the local certificate chain.
+	// Unsupported algorithms are ignored.
+	//
+	// If empty, certificate compression is disabled.
+	CertificateCompression []CertificateCompressionAlgorithm
+
 	// DynamicRecordSizingDisabled disables adaptive sizing of TLS records.
 	// When true, the largest possible TLS record size is always used. When
 	// false, the size of TLS records may be adjusted in an attempt to
 	// improve latency.
 	DynamicRecordSizingDisabled bool
 
+	// KernelTLS enables kernel TLS offload, where supported. When true, after
+	// the handshake the negotiated write keys are installed into the kernel,
+	// which then encrypts the records sent on the connection. This lets
+	// [Conn.ReadFrom] pass data straight to the underlying connection, for
+	// example to send an [*os.File] with sendfile(2), without copying it
+	// through userspace.
+	//
+	// Kernel TLS is currently only supported on Linux, for connections whose
+	// underlying net.Conn is a [*net.TCPConn], using TLS 1.2 or TLS 1.3 with
+	// an AES-GCM or ChaCha20-Poly1305 cipher suite. TLS 1.3 connections
+	// require Linux 6.14 or later, which can update the keys in response to
+	// a KeyUpdate message from the peer; on older kernels, only TLS 1.2
+	// connections use kernel TLS. It is not used for clients that enable
+	// renegotiation, or in FIPS 140-3 mode. If the kernel doesn't support it,
+	// for example because the tls module is not loaded, the connection
+	// silently keeps encrypting in userspace. Whether it is in use is
+	// reported by [ConnectionState.KernelTLS]. Reading from the connection
+	// always happens in userspace.
+	//
+	// When kernel TLS is in use, DynamicRecordSizingDisabled has no effect.
+	KernelTLS bool
+
 	// Renegotiation controls what types of renegotiation are supported.
 	// The default, none, is correct for the vast majority of applications.
 	Renegotiation RenegotiationSupport
@@ -1029,6 +1151,9 @@ func (c *Config) Clone() *Config {
 		ClientAuth:                          c.ClientAuth,
 		ClientCAs:                           c.ClientCAs,
 		InsecureSkipVerify:                  c.InsecureSkipVerify,
+		CertificateTransparency:             c.CertificateTransparency,
+		ExternalPSKs:                        c.ExternalPSKs,
+		GetExternalPSK:                      c.GetExternalPSK,
 		CipherSuites:                        c.CipherSuites,
 		PreferServerCipherSuites:            c.PreferServerCipherSuites,
 		SessionTicketsDisabled:              c.SessionTicketsDisabled,
@@ -1039,7 +1164,10 @@ func (c *Config) Clone() *Config {
 		MinVersion:                          c.MinVersion,
 		MaxVersion:                          c.MaxVersion,
 		CurvePreferences:                    c.CurvePreferences,
+		SignatureSchemes:                    c.SignatureSchemes,
+		CertificateCompression:              c.CertificateCompression,
 		DynamicRecordSizingDisabled:         c.DynamicRecordSizingDisabled,
+		KernelTLS:                           c.KernelTLS,
 		Renegotiation:                       c.Renegotiation,
 		KeyLogWriter:                        c.KeyLogWriter,
 		EncryptedClientHelloConfigList:      c.EncryptedClientHelloConfigList,
@@ -1507,6 +1635,9 @@ func (chi *ClientHelloInfo) SupportsCertificate(c *Certificate) error {
 		case *mldsa.PublicKey:
 			// ML-DSA requires TLS 1.3, which we already excluded above.
 			return errors.New("connection doesn't support ML-DSA")
+		case *slhdsa.PublicKey:
+			// SLH-DSA requires TLS 1.3, which we already excluded above.
+			return errors.New("connection doesn't support SLH-DSA")
 		case *rsa.PublicKey:
 		default:
 			return supportsRSAFallback(unsupportedCertificateError(c))
@@ -1635,7 +1766,7 @@ type Certificate struct {
 	Certificate [][]byte
 	// PrivateKey contains the private key corresponding to the public key in
 	// Leaf. This must implement [crypto.Signer] with an RSA, ECDSA, Ed25519
-	// (TLS 1.2+), or ML-DSA (TLS 1.3) PublicKey.
+	// (TLS 1.2+), ML-DSA (TLS 1.3), or SLH-DSA (TLS 1.3) PublicKey.
 	//
 	// For a server up to TLS 1.2, it can also implement crypto.Decrypter with
 	// an RSA PublicKey.
@@ -1770,12 +1901,24 @@ func unexpectedMessageError(wanted, got any) error {
 
 var testingOnlySupportedSignatureAlgorithms []SignatureScheme
 
+// signatureAlgorithms returns the signature algorithms enabled by
+// c.SignatureSchemes, or the default ones, in preference order.
+func (c *Config) signatureAlgorithms() []SignatureScheme {
+	if c == nil || len(c.SignatureSchemes) == 0 {
+		return defaultSupportedSignatureAlgorithms()
+	}
+	// Ignore unimplemented entries in c.SignatureSchemes.
+	return slices.DeleteFunc(signatureAlgorithmPreferenceOrder(), func(s SignatureScheme) bool {
+		return !slices.Contains(c.SignatureSchemes, s)
+	})
+}
+
 // supportedSignatureAlgorithms returns the supported signature algorithms for
 // the given range of TLS versions, to advertise in ClientHello and
 // CertificateRequest messages. An algorithm is included if it is enabled at any
 // version in the range.
-func supportedSignatureAlgorithms(minVers, maxVers uint16) []SignatureScheme {
-	sigAlgs := defaultSupportedSignatureAlgorithms()
+func (c *Config) supportedSignatureAlgorithms(minVers, maxVers uint16) []SignatureScheme {
+	sigAlgs := c.signatureAlgorithms()
 	if testingOnlySupportedSignatureAlgorithms != nil {
 		sigAlgs = slices.Clone(testingOnlySupportedSignatureAlgorithms)
 	}
@@ -1806,6 +1949,15 @@ func isDisabledSignatureAlgorithm(version uint16, s SignatureScheme, isCert bool
 		if version < VersionTLS13 {
 			return true
 		}
+	case SLHDSA_SHA2_128s, SLHDSA_SHA2_128f, SLHDSA_SHA2_192s, SLHDSA_SHA2_192f, SLHDSA_SHA2_256s, SLHDSA_SHA2_256f, SLHDSA_SHAKE_128s, SLHDSA_SHAKE_128f, SLHDSA_SHAKE_192s, SLHDSA_SHAKE_192f, SLHDSA_SHAKE_256s, SLHDSA_SHAKE_256f:
+		// SLH-DSA is not available in FIPS 140-3 modules v1.0.0 and v1.26.0.
+		if v := fips140.Version(); v == "v1.0.0" || v == "v1.26.0" {
+			return true
+		}
+		// SLH-DSA codepoints are only defined for TLS 1.3.
+		if version < VersionTLS13 {
+			return true
+		}
 	}
 
 	// For the _cert extension we include all algorithms, including SHA-1 and
@@ -1837,8 +1989,8 @@ func isDisabledSignatureAlgorithm(version uint16, s SignatureScheme, isCert bool
 
 // supportedSignatureAlgorithmsCert returns the supported algorithms for
 // signatures in certificates.
-func supportedSignatureAlgorithmsCert(minVers, maxVers uint16) []SignatureScheme {
-	sigAlgs := defaultSupportedSignatureAlgorithms()
+func (c *Config) supportedSignatureAlgorithmsCert(minVers, maxVers uint16) []SignatureScheme {
+	sigAlgs := c.signatureAlgorithms()
 	return slices.DeleteFunc(sigAlgs, func(s SignatureScheme) bool {
 		for v := minVers; v <= maxVers; v++ {
 			if !isDisabledSignatureAlgorithm(v, s, true) {
@@ -1907,6 +2059,28 @@ func fipsAllowChain(chain []*x509.Certificate) bool {
 	return true
 }
 
+// peerSCTs returns the SCTs for the peer's leaf certificate delivered with the
+// TLS extension or in the stapled OCSP response, for use with
+// x509.VerifyOptions.SignedCertificateTimestamps.
+func peerSCTs(scts [][]byte, ocspResponse []byte) [][]byte {
+	if len(ocspResponse) == 0 {
+		return scts
+	}
+	resp, err := x509.ParseOCSPResponse(ocspResponse)
+	if err != nil {
+		return scts
+	}
+	ocspSCTs, err := resp.SignedCertificateTimestamps()
+	if err != nil {
+		return scts
+	}
+	scts = slices.Clip(scts)
+	for _, sct := range ocspSCTs {
+		scts = append(scts, sct.Raw)
+	}
+	return scts
+}
+
 // anyValidVerifiedChain reports if at least one of the chains in verifiedChains
 // is valid, as indicated by none of the certificates being expired and the root
 // being in opts.Roots (or in the system root pool if opts.Roots is nil). If
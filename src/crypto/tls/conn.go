@@ -70,6 +70,9 @@ type Conn struct {
 	// or sending NewSessionTicket messages.
 	resumptionSecret []byte
 	echAccepted      bool
+	// externalPSKIdentity is the identity of the external PSK that
+	// authenticated the connection, if any.
+	externalPSKIdentity []byte
 
 	// ticketKeys is the set of active session ticket keys for this
 	// connection. The first one is used to encrypt new tickets and
@@ -106,6 +109,11 @@ type Conn struct {
 	buffering bool         // whether records are buffered in sendBuf
 	sendBuf   []byte       // a buffer of records waiting to be sent
 
+	// kernelTX is true if records are encrypted by the kernel, rather than
+	// by out. See Config.KernelTLS. It is only set during the handshake, with
+	// both handshakeMutex and out.Mutex held.
+	kernelTX bool
+
 	// bytesSent counts the bytes of application data sent.
 	// packetsSent counts packets.
 	bytesSent   int64
@@ -180,6 +188,11 @@ type halfConn struct {
 	nextCipher any       // next encryption state
 	nextMac    hash.Hash // next MAC algorithm
 
+	// key and iv are the current cipher key and IV, kept for kernel TLS
+	// offload. nextKey and nextIV match nextCipher.
+	key, iv         []byte
+	nextKey, nextIV []byte
+
 	level         QUICEncryptionLevel // current QUIC encryption level
 	trafficSecret []byte              // current TLS 1.3 traffic secret
 }
@@ -204,10 +217,12 @@ func (hc *halfConn) setErrorLocked(err error) error {
 
 // prepareCipherSpec sets the encryption and MAC states
 // that a subsequent changeCipherSpec will use.
-func (hc *halfConn) prepareCipherSpec(version uint16, cipher any, mac hash.Hash) {
+func (hc *halfConn) prepareCipherSpec(version uint16, cipher any, mac hash.Hash, key, iv []byte) {
 	hc.version = version
 	hc.nextCipher = cipher
 	hc.nextMac = mac
+	hc.nextKey = key
+	hc.nextIV = iv
 }
 
 // changeCipherSpec changes the encryption and MAC states
@@ -218,8 +233,12 @@ func (hc *halfConn) changeCipherSpec() error {
 	}
 	hc.cipher = hc.nextCipher
 	hc.mac = hc.nextMac
+	hc.key = hc.nextKey
+	hc.iv = hc.nextIV
 	hc.nextCipher = nil
 	hc.nextMac = nil
+	hc.nextKey = nil
+	hc.nextIV = nil
 	clear(hc.seq[:])
 	return nil
 }
@@ -232,6 +251,7 @@ func (hc *halfConn) setTrafficSecret(suite *cipherSuiteTLS13, level QUICEncrypti
 	hc.level = level
 	key, iv := suite.trafficKey(secret)
 	hc.cipher = suite.aead(key, iv)
+	hc.key, hc.iv = key, iv
 	clear(hc.seq[:])
 }
 
@@ -989,6 +1009,9 @@ func (c *Conn) writeRecordLocked(typ recordType, data []byte) (int, error) {
 		}
 		return len(data), nil
 	}
+	if c.kernelTX {
+		return c.writeKernelRecordLocked(typ, data)
+	}
 
 	outBufPtr := outBufPool.Get().(*[]byte)
 	outBuf := *outBufPtr
@@ -1100,7 +1123,7 @@ func (c *Conn) readHandshake(transcript transcriptHash) (any, error) {
 	// hasVers indicates we're past the first message, forcing someone trying to
 	// make us just allocate a large buffer to at least do the initial part of
 	// the handshake first.
-	if c.haveVers && data[0] == typeCertificate {
+	if c.haveVers && (data[0] == typeCertificate || data[0] == typeCompressedCertificate) {
 		// Since certificate messages are likely to be the only messages that
 		// can be larger than maxHandshake, we use a special limit for just
 		// those messages.
@@ -1140,6 +1163,11 @@ func (c *Conn) unmarshalHandshakeMessage(data []byte, transcript transcriptHash)
 		} else {
 			m = new(certificateMsg)
 		}
+	case typeCompressedCertificate:
+		if c.vers != VersionTLS13 {
+			return nil, c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+		}
+		m = new(compressedCertificateMsg)
 	case typeCertificateRequest:
 		if c.vers == VersionTLS13 {
 			m = new(certificateRequestMsgTLS13)
@@ -1254,6 +1282,51 @@ func (c *Conn) Write(b []byte) (int, error) {
 	return n + m, c.out.setErrorLocked(err)
 }
 
+// ReadFrom implements [io.ReaderFrom], copying data from r to the connection.
+//
+// If records are encrypted by the kernel (see [Config.KernelTLS]), r is copied
+// directly to the underlying connection, which can avoid copying data through
+// userspace, for example when r is an [*os.File] and the underlying connection
+// is a [*net.TCPConn]. Otherwise, ReadFrom is equivalent to calling
+// [Conn.Write] with the data read from r.
+func (c *Conn) ReadFrom(r io.Reader) (int64, error) {
+	// interlock with Close, like Write
+	for {
+		x := c.activeCall.Load()
+		if x&1 != 0 {
+			return 0, net.ErrClosed
+		}
+		if c.activeCall.CompareAndSwap(x, x+2) {
+			break
+		}
+	}
+	defer c.activeCall.Add(-2)
+
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+
+	c.out.Lock()
+	if !c.kernelTX {
+		c.out.Unlock()
+		return io.Copy(struct{ io.Writer }{c}, r)
+	}
+	defer c.out.Unlock()
+
+	if err := c.out.err; err != nil {
+		return 0, err
+	}
+	if c.closeNotifySent {
+		return 0, errShutdown
+	}
+
+	// The kernel splits the plaintext into records, so a short write leaves
+	// the connection in a consistent state, unlike in Write.
+	n, err := io.Copy(c.conn, r)
+	c.bytesSent += n
+	return n, err
+}
+
 // handleRenegotiation processes a HelloRequest handshake message.
 func (c *Conn) handleRenegotiation() error {
 	if c.vers == VersionTLS13 {
@@ -1359,6 +1432,14 @@ func (c *Conn) handleKeyUpdate(keyUpdate *keyUpdateMsg) error {
 
 		newSecret := cipherSuite.nextTrafficSecret(c.out.trafficSecret)
 		c.setWriteTrafficSecret(cipherSuite, QUICEncryptionLevelInitial, newSecret)
+		if c.kernelTX {
+			if err := c.setKernelTXKey(); err != nil {
+				// The kernel is still using the old key. Surface the
+				// error at the next write.
+				c.out.setErrorLocked(err)
+				return nil
+			}
+		}
 	}
 
 	newSecret := cipherSuite.nextTrafficSecret(c.in.trafficSecret)
@@ -1561,6 +1642,9 @@ func (c *Conn) handshakeContext(ctx context.Context) (ret error) {
 	c.handshakeErr = c.handshakeFn(handshakeCtx)
 	if c.handshakeErr == nil {
 		c.handshakes++
+		if c.config.KernelTLS && c.quic == nil {
+			c.enableKernelTLS()
+		}
 	} else {
 		// If an error occurred during the handshake try to flush the
 		// alert that might be left in the buffer.
@@ -1633,6 +1717,7 @@ func (c *Conn) connectionStateLocked() ConnectionState {
 	state.VerifiedChains = c.verifiedChains
 	state.SignedCertificateTimestamps = c.scts
 	state.OCSPResponse = c.ocspResponse
+	state.ExternalPSKIdentity = c.externalPSKIdentity
 	if (!c.didResume || c.extMasterSecret) && c.vers != VersionTLS13 {
 		if c.clientFinishedIsFirst {
 			state.TLSUnique = c.clientFinished[:]
@@ -1648,6 +1733,7 @@ func (c *Conn) connectionStateLocked() ConnectionState {
 		state.ekm = c.ekm
 	}
 	state.ECHAccepted = c.echAccepted
+	state.KernelTLS = c.kernelTX
 	return state
 }
 
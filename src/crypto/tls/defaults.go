@@ -42,11 +42,23 @@ func curvePreferenceOrder() []CurveID {
 	}
 }
 
-// defaultSupportedSignatureAlgorithms returns the signature and hash algorithms that
-// the code advertises and supports in a TLS 1.2+ ClientHello and in a TLS 1.2+
-// CertificateRequest. The two fields are merged to match with TLS 1.3.
-// Note that in TLS 1.2, the ECDSA algorithms are not constrained to P-256, etc.
-func defaultSupportedSignatureAlgorithms() []SignatureScheme {
+// defaultSignatureAlgorithmEnabled returns whether the signature algorithm s
+// is advertised by default. The SLH-DSA algorithms are not, because of the size
+// of their signatures.
+func defaultSignatureAlgorithmEnabled(s SignatureScheme) bool {
+	switch s {
+	case SLHDSA_SHA2_128s, SLHDSA_SHA2_128f, SLHDSA_SHA2_192s, SLHDSA_SHA2_192f,
+		SLHDSA_SHA2_256s, SLHDSA_SHA2_256f, SLHDSA_SHAKE_128s, SLHDSA_SHAKE_128f,
+		SLHDSA_SHAKE_192s, SLHDSA_SHAKE_192f, SLHDSA_SHAKE_256s, SLHDSA_SHAKE_256f:
+		return false
+	default:
+		return true
+	}
+}
+
+// signatureAlgorithmPreferenceOrder is the fixed preference order of signature
+// and hash algorithms. It must include every supported signature algorithm.
+func signatureAlgorithmPreferenceOrder() []SignatureScheme {
 	return []SignatureScheme{
 		MLDSA44,
 		MLDSA65,
@@ -61,11 +73,34 @@ func defaultSupportedSignatureAlgorithms() []SignatureScheme {
 		PKCS1WithSHA512,
 		ECDSAWithP384AndSHA384,
 		ECDSAWithP521AndSHA512,
+		SLHDSA_SHA2_128s,
+		SLHDSA_SHA2_128f,
+		SLHDSA_SHA2_192s,
+		SLHDSA_SHA2_192f,
+		SLHDSA_SHA2_256s,
+		SLHDSA_SHA2_256f,
+		SLHDSA_SHAKE_128s,
+		SLHDSA_SHAKE_128f,
+		SLHDSA_SHAKE_192s,
+		SLHDSA_SHAKE_192f,
+		SLHDSA_SHAKE_256s,
+		SLHDSA_SHAKE_256f,
 		PKCS1WithSHA1,
 		ECDSAWithSHA1,
 	}
 }
 
+// defaultSupportedSignatureAlgorithms returns the signature and hash algorithms that
+// the code advertises and supports in a TLS 1.2+ ClientHello and in a TLS 1.2+
+// CertificateRequest, unless Config.SignatureSchemes is set. The two fields are
+// merged to match with TLS 1.3.
+// Note that in TLS 1.2, the ECDSA algorithms are not constrained to P-256, etc.
+func defaultSupportedSignatureAlgorithms() []SignatureScheme {
+	return slices.DeleteFunc(signatureAlgorithmPreferenceOrder(), func(s SignatureScheme) bool {
+		return !defaultSignatureAlgorithmEnabled(s)
+	})
+}
+
 func supportedCipherSuites(aesGCMPreferred bool) []uint16 {
 	if aesGCMPreferred {
 		return slices.Clone(cipherSuitesPreferenceOrder)
@@ -13,6 +13,7 @@ import (
 	"crypto/internal/boring"
 	"crypto/mldsa"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"crypto/x509"
 )
 
@@ -55,6 +56,18 @@ var (
 		PKCS1WithSHA512,
 		ECDSAWithP384AndSHA384,
 		ECDSAWithP521AndSHA512,
+		SLHDSA_SHA2_128s,
+		SLHDSA_SHA2_128f,
+		SLHDSA_SHA2_192s,
+		SLHDSA_SHA2_192f,
+		SLHDSA_SHA2_256s,
+		SLHDSA_SHA2_256f,
+		SLHDSA_SHAKE_128s,
+		SLHDSA_SHAKE_128f,
+		SLHDSA_SHAKE_192s,
+		SLHDSA_SHAKE_192f,
+		SLHDSA_SHAKE_256s,
+		SLHDSA_SHAKE_256f,
 	}
 	allowedCipherSuitesFIPS = []uint16{
 		TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
@@ -81,6 +94,9 @@ func isCertificateAllowedFIPS(c *x509.Certificate) bool {
 	case *mldsa.PublicKey:
 		// Only for the native module.
 		return !boring.Enabled
+	case *slhdsa.PublicKey:
+		// Only for the native module.
+		return !boring.Enabled
 	default:
 		return false
 	}
@@ -13,6 +13,7 @@ import (
 	"crypto/mldsa"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
@@ -182,7 +183,10 @@ func isFIPSSignatureScheme(alg SignatureScheme) bool {
 		PSSWithSHA384,
 		PSSWithSHA512:
 		return true
-	case Ed25519, MLDSA44, MLDSA65, MLDSA87:
+	case Ed25519, MLDSA44, MLDSA65, MLDSA87,
+		SLHDSA_SHA2_128s, SLHDSA_SHA2_128f, SLHDSA_SHA2_192s, SLHDSA_SHA2_192f,
+		SLHDSA_SHA2_256s, SLHDSA_SHA2_256f, SLHDSA_SHAKE_128s, SLHDSA_SHAKE_128f,
+		SLHDSA_SHAKE_192s, SLHDSA_SHAKE_192f, SLHDSA_SHAKE_256s, SLHDSA_SHAKE_256f:
 		// Only for the native module.
 		return !boring.Enabled
 	case PKCS1WithSHA1, ECDSAWithSHA1:
@@ -282,18 +286,26 @@ func TestFIPSServerSignatureAndHash(t *testing.T) {
 	}()
 	testenv.SetGODEBUG(t, "tlssha1=1")
 
-	for _, sigHash := range defaultSupportedSignatureAlgorithms() {
+	// SLH-DSA is not advertised by default, and signing is slow, so only
+	// test a single parameter set.
+	for _, sigHash := range append(defaultSupportedSignatureAlgorithms(), SLHDSA_SHA2_128f) {
 		t.Run(fmt.Sprintf("%v", sigHash), func(t *testing.T) {
 			isMLDSA := sigHash == MLDSA44 || sigHash == MLDSA65 || sigHash == MLDSA87
 			if isMLDSA {
 				cryptotest.MustMinimumFIPS140ModuleVersion(t, "v1.26.0")
 			}
+			isSLHDSA := sigHash == SLHDSA_SHA2_128f
 			serverConfig := testConfigFIPS140.Clone()
+			if isSLHDSA {
+				cryptotest.MustMinimumFIPS140ModuleVersion(t, "v1.28.0")
+				serverConfig.Certificates = append(serverConfig.Certificates,
+					testSLHDSACert(t, slhdsa.SHA2_128f()))
+			}
 			testingOnlySupportedSignatureAlgorithms = []SignatureScheme{sigHash}
 			// PKCS#1 v1.5 signature algorithms can't be used standalone in TLS
 			// 1.3, and the ECDSA ones bind to the curve used. However, ML-DSA
-			// requires TLS 1.3.
-			if !isMLDSA {
+			// and SLH-DSA require TLS 1.3.
+			if !isMLDSA && !isSLHDSA {
 				serverConfig.MaxVersion = VersionTLS12
 			}
 
@@ -14,6 +14,7 @@ import (
 	"crypto/internal/fips140/tls13"
 	"crypto/mldsa"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"crypto/subtle"
 	"crypto/tls/internal/fips140tls"
 	"crypto/x509"
@@ -43,8 +44,8 @@ type clientHandshakeState struct {
 
 func (c *Conn) makeClientHello() (*clientHelloMsg, *keySharePrivateKeys, *echClientContext, error) {
 	config := c.config
-	if len(config.ServerName) == 0 && !config.InsecureSkipVerify {
-		return nil, nil, nil, errors.New("tls: either ServerName or InsecureSkipVerify must be specified in the tls.Config")
+	if len(config.ServerName) == 0 && !config.InsecureSkipVerify && len(config.ExternalPSKs) == 0 {
+		return nil, nil, nil, errors.New("tls: either ServerName, InsecureSkipVerify, or ExternalPSKs must be specified in the tls.Config")
 	}
 
 	nextProtosLength := 0
@@ -120,8 +121,8 @@ func (c *Conn) makeClientHello() (*clientHelloMsg, *keySharePrivateKeys, *echCli
 	}
 
 	if maxVersion >= VersionTLS12 {
-		hello.supportedSignatureAlgorithms = supportedSignatureAlgorithms(minVersion, maxVersion)
-		hello.supportedSignatureAlgorithmsCert = supportedSignatureAlgorithmsCert(minVersion, maxVersion)
+		hello.supportedSignatureAlgorithms = config.supportedSignatureAlgorithms(minVersion, maxVersion)
+		hello.supportedSignatureAlgorithmsCert = config.supportedSignatureAlgorithmsCert(minVersion, maxVersion)
 	}
 
 	var keyShareKeys *keySharePrivateKeys
@@ -158,6 +159,8 @@ func (c *Conn) makeClientHello() (*clientHelloMsg, *keySharePrivateKeys, *echCli
 		if len(hello.keyShares) == 2 && !slices.Contains(hello.supportedCurves, hello.keyShares[1].group) {
 			hello.keyShares = hello.keyShares[:1]
 		}
+
+		hello.certCompressionAlgorithms = config.certCompressionAlgorithms()
 	}
 
 	if c.quic != nil {
@@ -228,16 +231,27 @@ func (c *Conn) clientHandshake(ctx context.Context) (err error) {
 	// need to be reset.
 	c.didResume = false
 	c.curveID = 0
+	c.externalPSKIdentity = nil
 
 	hello, keyShareKeys, ech, err := c.makeClientHello()
 	if err != nil {
 		return err
 	}
 
-	session, earlySecret, binderKey, err := c.loadSession(hello)
+	// External PSKs are offered in place of session resumption.
+	externalPSKs, err := c.loadExternalPSKs(hello)
 	if err != nil {
 		return err
 	}
+	var session *SessionState
+	var earlySecret *tls13.EarlySecret
+	var binderKey []byte
+	if externalPSKs == nil {
+		session, earlySecret, binderKey, err = c.loadSession(hello)
+		if err != nil {
+			return err
+		}
+	}
 	if session != nil {
 		defer func() {
 			// If we got a handshake failure when resuming a session, throw away
@@ -335,6 +349,7 @@ func (c *Conn) clientHandshake(ctx context.Context) (err error) {
 			session:      session,
 			earlySecret:  earlySecret,
 			binderKey:    binderKey,
+			externalPSKs: externalPSKs,
 			echContext:   ech,
 		}
 		return hs.handshake()
@@ -866,8 +881,8 @@ func (hs *clientHandshakeState) establishKeys() error {
 		serverCipher = hs.suite.aead(serverKey, serverIV)
 	}
 
-	c.in.prepareCipherSpec(c.vers, serverCipher, serverHash)
-	c.out.prepareCipherSpec(c.vers, clientCipher, clientHash)
+	c.in.prepareCipherSpec(c.vers, serverCipher, serverHash, serverKey, serverIV)
+	c.out.prepareCipherSpec(c.vers, clientCipher, clientHash, clientKey, clientIV)
 	return nil
 }
 
@@ -1103,6 +1118,12 @@ func checkKeySize(n int) (max int, ok bool) {
 // verifyServerCertificate parses and verifies the provided chain, setting
 // c.verifiedChains and c.peerCertificates or sending the appropriate alert.
 func (c *Conn) verifyServerCertificate(certificates [][]byte) error {
+	// Without a ServerName, only external PSK authentication is possible.
+	if len(c.config.ServerName) == 0 && !c.config.InsecureSkipVerify {
+		c.sendAlert(alertHandshakeFailure)
+		return errors.New("tls: server did not select an external PSK and ServerName is not set")
+	}
+
 	certs := make([]*x509.Certificate, len(certificates))
 	for i, asn1Data := range certificates {
 		cert, err := globalCertCache.newCert(asn1Data)
@@ -1157,6 +1178,10 @@ func (c *Conn) verifyServerCertificate(certificates [][]byte) error {
 			DNSName:       c.config.ServerName,
 			Intermediates: x509.NewCertPool(),
 		}
+		if c.config.CertificateTransparency != nil {
+			opts.CertificateTransparency = c.config.CertificateTransparency
+			opts.SignedCertificateTimestamps = peerSCTs(c.scts, c.ocspResponse)
+		}
 
 		for _, cert := range certs[1:] {
 			opts.Intermediates.AddCert(cert)
@@ -1187,6 +1212,11 @@ func (c *Conn) verifyServerCertificate(certificates [][]byte) error {
 			c.sendAlert(alertIllegalParameter)
 			return errors.New("tls: server's certificate uses ML-DSA, which requires TLS 1.3")
 		}
+	case *slhdsa.PublicKey:
+		if c.vers < VersionTLS13 {
+			c.sendAlert(alertIllegalParameter)
+			return errors.New("tls: server's certificate uses SLH-DSA, which requires TLS 1.3")
+		}
 	default:
 		c.sendAlert(alertUnsupportedCertificate)
 		return fmt.Errorf("tls: server's certificate contains an unsupported type of public key: %T", certs[0].PublicKey)
@@ -30,6 +30,11 @@ type clientHandshakeStateTLS13 struct {
 	earlySecret *tls13.EarlySecret
 	binderKey   []byte
 
+	// externalPSKs are the external PSKs offered in hello, if any, in the
+	// same order as hello.pskIdentities. They are never offered along with
+	// a session.
+	externalPSKs []*externalPSK
+
 	certReq       *certificateRequestMsgTLS13
 	usingPSK      bool
 	sentDummyCCS  bool
@@ -42,7 +47,8 @@ type clientHandshakeStateTLS13 struct {
 }
 
 // handshake requires hs.c, hs.hello, hs.serverHello, hs.keyShareKeys, and,
-// optionally, hs.session, hs.earlySecret and hs.binderKey to be set.
+// optionally, hs.session, hs.earlySecret and hs.binderKey, or hs.externalPSKs
+// to be set.
 func (hs *clientHandshakeStateTLS13) handshake() error {
 	c := hs.c
 
@@ -334,7 +340,31 @@ func (hs *clientHandshakeStateTLS13) processHelloRetryRequest() error {
 		hello.keyShares = hello.keyShares[:1]
 	}
 
-	if len(hello.pskIdentities) > 0 {
+	if len(hello.pskIdentities) > 0 && hs.externalPSKs != nil {
+		// Only external PSKs compatible with the selected cipher suite can
+		// still be used, and their binders must cover the new transcript.
+		var psks []*externalPSK
+		hello.pskIdentities, hello.pskBinders = nil, nil
+		for _, psk := range hs.externalPSKs {
+			if psk.suite.hash == hs.suite.hash {
+				psks = append(psks, psk)
+				hello.pskIdentities = append(hello.pskIdentities, pskIdentity{label: psk.identity})
+				hello.pskBinders = append(hello.pskBinders, make([]byte, hs.suite.hash.Size()))
+			}
+		}
+		hs.externalPSKs = psks
+		if len(psks) > 0 {
+			var transcript bytes.Buffer
+			transcript.Write([]byte{typeMessageHash, 0, 0, uint8(len(chHash))})
+			transcript.Write(chHash)
+			if err := transcriptMsg(hs.serverHello, &transcript); err != nil {
+				return err
+			}
+			if err := computeAndUpdateExternalPSKs(hello, psks, transcript.Bytes()); err != nil {
+				return err
+			}
+		}
+	} else if len(hello.pskIdentities) > 0 {
 		pskSuite := cipherSuiteTLS13ByID(hs.session.cipherSuite)
 		if pskSuite == nil {
 			return c.sendAlert(alertInternalError)
@@ -447,6 +477,21 @@ func (hs *clientHandshakeStateTLS13) processServerHello() error {
 		return errors.New("tls: server selected an invalid PSK")
 	}
 
+	if hs.externalPSKs != nil {
+		if len(hs.externalPSKs) != len(hs.hello.pskIdentities) {
+			return c.sendAlert(alertInternalError)
+		}
+		psk := hs.externalPSKs[hs.serverHello.selectedIdentity]
+		if psk.suite.hash != hs.suite.hash {
+			c.sendAlert(alertIllegalParameter)
+			return errors.New("tls: server selected an invalid PSK and cipher suite pair")
+		}
+		hs.usingPSK = true
+		hs.earlySecret = tls13.NewEarlySecret(hs.suite.hash.New, psk.key)
+		c.externalPSKIdentity = psk.config.Identity
+		return nil
+	}
+
 	if len(hs.hello.pskIdentities) != 1 || hs.session == nil {
 		return c.sendAlert(alertInternalError)
 	}
@@ -620,6 +665,13 @@ func (hs *clientHandshakeStateTLS13) readServerCertificate() error {
 		}
 	}
 
+	if compressedMsg, ok := msg.(*compressedCertificateMsg); ok {
+		msg, err = c.decompressCertificate(compressedMsg)
+		if err != nil {
+			return err
+		}
+	}
+
 	certMsg, ok := msg.(*certificateMsgTLS13)
 	if !ok {
 		c.sendAlert(alertUnexpectedMessage)
@@ -654,7 +706,7 @@ func (hs *clientHandshakeStateTLS13) readServerCertificate() error {
 	// See RFC 8446, Section 4.4.3.
 	// We don't use hs.hello.supportedSignatureAlgorithms because it might
 	// include PKCS#1 v1.5 and SHA-1 if the ClientHello also supported TLS 1.2.
-	if !isSupportedSignatureAlgorithm(certVerify.signatureAlgorithm, supportedSignatureAlgorithms(c.vers, c.vers)) ||
+	if !isSupportedSignatureAlgorithm(certVerify.signatureAlgorithm, c.config.supportedSignatureAlgorithms(c.vers, c.vers)) ||
 		!isSupportedSignatureAlgorithm(certVerify.signatureAlgorithm, signatureSchemesForPublicKey(c.vers, c.peerCertificates[0].PublicKey)) {
 		c.sendAlert(alertIllegalParameter)
 		return errors.New("tls: certificate used with invalid signature algorithm")
@@ -766,7 +818,12 @@ func (hs *clientHandshakeStateTLS13) sendClientCertificate() error {
 	certMsg.scts = hs.certReq.scts && len(cert.SignedCertificateTimestamps) > 0
 	certMsg.ocspStapling = hs.certReq.ocspStapling && len(cert.OCSPStaple) > 0
 
-	if _, err := hs.c.writeHandshakeRecord(certMsg, hs.transcript); err != nil {
+	msg, err := c.compressCertificate(certMsg, hs.certReq.certCompressionAlgorithms)
+	if err != nil {
+		c.sendAlert(alertInternalError)
+		return err
+	}
+	if _, err := hs.c.writeHandshakeRecord(msg, hs.transcript); err != nil {
 		return err
 	}
 
@@ -844,6 +901,11 @@ func (c *Conn) handleNewSessionTicket(msg *newSessionTicketMsgTLS13) error {
 		return nil
 	}
 
+	// Sessions authenticated with an external PSK are not resumed.
+	if c.externalPSKIdentity != nil {
+		return nil
+	}
+
 	// See RFC 8446, Section 4.6.1.
 	if msg.lifetime == 0 {
 		return nil
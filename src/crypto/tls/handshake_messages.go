@@ -98,6 +98,7 @@ type clientHelloMsg struct {
 	pskBinders                       [][]byte
 	quicTransportParameters          []byte
 	encryptedClientHello             []byte
+	certCompressionAlgorithms        []CertificateCompressionAlgorithm
 	// extensions are only populated on the server-side of a handshake
 	extensions []uint16
 }
@@ -308,6 +309,17 @@ func (m *clientHelloMsg) marshalMsg(echInner bool) ([]byte, error) {
 			})
 		}
 	}
+	if len(m.certCompressionAlgorithms) > 0 {
+		// RFC 8879, Section 3
+		if echInner {
+			echOuterExts = append(echOuterExts, extensionCompressCertificate)
+		} else {
+			exts.AddUint16(extensionCompressCertificate)
+			exts.AddUint16LengthPrefixed(func(exts *cryptobyte.Builder) {
+				marshalCertCompressionAlgorithms(exts, m.certCompressionAlgorithms)
+			})
+		}
+	}
 	if len(echOuterExts) > 0 && echInner {
 		exts.AddUint16(extensionECHOuterExtensions)
 		exts.AddUint16LengthPrefixed(func(exts *cryptobyte.Builder) {
@@ -634,6 +646,11 @@ func (m *clientHelloMsg) unmarshal(data []byte) bool {
 			if !extData.CopyBytes(m.quicTransportParameters) {
 				return false
 			}
+		case extensionCompressCertificate:
+			// RFC 8879, Section 3
+			if !unmarshalCertCompressionAlgorithms(&extData, &m.certCompressionAlgorithms) {
+				return false
+			}
 		case extensionPreSharedKey:
 			// RFC 8446, Section 4.2.11
 			if !extensions.Empty() {
@@ -715,6 +732,7 @@ func (m *clientHelloMsg) clone() *clientHelloMsg {
 		pskBinders:                       slices.Clone(m.pskBinders),
 		quicTransportParameters:          slices.Clone(m.quicTransportParameters),
 		encryptedClientHello:             slices.Clone(m.encryptedClientHello),
+		certCompressionAlgorithms:        slices.Clone(m.certCompressionAlgorithms),
 	}
 }
 
@@ -1114,7 +1132,7 @@ func (m *encryptedExtensionsMsg) unmarshal(data []byte) bool {
 			extensionCookie, extensionPSKModes,
 			extensionCertificateAuthorities, extensionSignatureAlgorithmsCert,
 			extensionKeyShare, extensionRenegotiationInfo,
-			extensionECHOuterExtensions:
+			extensionECHOuterExtensions, extensionCompressCertificate:
 			// Not allowed in EncryptedExtensions.
 			return false
 		default:
@@ -1250,7 +1268,7 @@ func (m *newSessionTicketMsgTLS13) unmarshal(data []byte) bool {
 			extensionCertificateAuthorities, extensionSignatureAlgorithmsCert,
 			extensionKeyShare, extensionQUICTransportParameters,
 			extensionRenegotiationInfo, extensionECHOuterExtensions,
-			extensionEncryptedClientHello:
+			extensionEncryptedClientHello, extensionCompressCertificate:
 			// Not allowed in TLS 1.3 NewSessionTicket.
 			return false
 		default:
@@ -1272,6 +1290,7 @@ type certificateRequestMsgTLS13 struct {
 	supportedSignatureAlgorithms     []SignatureScheme
 	supportedSignatureAlgorithmsCert []SignatureScheme
 	certificateAuthorities           [][]byte
+	certCompressionAlgorithms        []CertificateCompressionAlgorithm
 }
 
 func (m *certificateRequestMsgTLS13) marshal() ([]byte, error) {
@@ -1328,6 +1347,13 @@ func (m *certificateRequestMsgTLS13) marshal() ([]byte, error) {
 					})
 				})
 			}
+			if len(m.certCompressionAlgorithms) > 0 {
+				// RFC 8879, Section 3
+				b.AddUint16(extensionCompressCertificate)
+				b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+					marshalCertCompressionAlgorithms(b, m.certCompressionAlgorithms)
+				})
+			}
 		})
 	})
 
@@ -1397,6 +1423,10 @@ func (m *certificateRequestMsgTLS13) unmarshal(data []byte) bool {
 				}
 				m.certificateAuthorities = append(m.certificateAuthorities, ca)
 			}
+		case extensionCompressCertificate:
+			if !unmarshalCertCompressionAlgorithms(&extData, &m.certCompressionAlgorithms) {
+				return false
+			}
 		case extensionSupportedCurves, extensionSupportedPoints,
 			extensionALPN, extensionExtendedMasterSecret,
 			extensionSessionTicket, extensionPreSharedKey,
@@ -1625,7 +1655,7 @@ func unmarshalCertificate(s *cryptobyte.String, certificate *Certificate) bool {
 				extensionCertificateAuthorities, extensionSignatureAlgorithmsCert,
 				extensionKeyShare, extensionQUICTransportParameters,
 				extensionRenegotiationInfo, extensionECHOuterExtensions,
-				extensionEncryptedClientHello:
+				extensionEncryptedClientHello, extensionCompressCertificate:
 				// Not allowed in Certificate.
 				return false
 			default:
@@ -1641,6 +1671,65 @@ func unmarshalCertificate(s *cryptobyte.String, certificate *Certificate) bool {
 	return true
 }
 
+func marshalCertCompressionAlgorithms(b *cryptobyte.Builder, algs []CertificateCompressionAlgorithm) {
+	b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+		for _, alg := range algs {
+			b.AddUint16(uint16(alg))
+		}
+	})
+}
+
+func unmarshalCertCompressionAlgorithms(s *cryptobyte.String, algs *[]CertificateCompressionAlgorithm) bool {
+	var list cryptobyte.String
+	if !s.ReadUint8LengthPrefixed(&list) || list.Empty() {
+		return false
+	}
+	for !list.Empty() {
+		var alg uint16
+		if !list.ReadUint16(&alg) {
+			return false
+		}
+		*algs = append(*algs, CertificateCompressionAlgorithm(alg))
+	}
+	return true
+}
+
+// compressedCertificateMsg is a TLS 1.3 CompressedCertificate message, which
+// carries a compressed Certificate message body. See RFC 8879, Section 4.
+type compressedCertificateMsg struct {
+	algorithm          CertificateCompressionAlgorithm
+	uncompressedLength uint32
+	compressed         []byte
+}
+
+func (m *compressedCertificateMsg) marshal() ([]byte, error) {
+	var b cryptobyte.Builder
+	b.AddUint8(typeCompressedCertificate)
+	b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddUint16(uint16(m.algorithm))
+		b.AddUint24(m.uncompressedLength)
+		b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes(m.compressed)
+		})
+	})
+
+	return b.Bytes()
+}
+
+func (m *compressedCertificateMsg) unmarshal(data []byte) bool {
+	*m = compressedCertificateMsg{}
+	s := cryptobyte.String(data)
+
+	if !s.Skip(4) || // message type and uint24 length field
+		!s.ReadUint16((*uint16)(&m.algorithm)) ||
+		!s.ReadUint24(&m.uncompressedLength) ||
+		!readUint24LengthPrefixed(&s, &m.compressed) ||
+		len(m.compressed) == 0 || !s.Empty() {
+		return false
+	}
+	return true
+}
+
 type serverKeyExchangeMsg struct {
 	key []byte
 }
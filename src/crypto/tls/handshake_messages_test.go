@@ -36,6 +36,7 @@ var tests = []handshakeMessage{
 	&newSessionTicketMsgTLS13{},
 	&certificateRequestMsgTLS13{},
 	&certificateMsgTLS13{},
+	&compressedCertificateMsg{},
 	&SessionState{},
 }
 
@@ -179,10 +180,10 @@ func (*clientHelloMsg) Generate(rand *rand.Rand, size int) reflect.Value {
 		}
 	}
 	if rand.Intn(10) > 5 {
-		m.supportedSignatureAlgorithms = supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
+		m.supportedSignatureAlgorithms = defaultConfig().supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
 	}
 	if rand.Intn(10) > 5 {
-		m.supportedSignatureAlgorithmsCert = supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
+		m.supportedSignatureAlgorithmsCert = defaultConfig().supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
 	}
 	for i := 0; i < rand.Intn(5); i++ {
 		m.alpnProtocols = append(m.alpnProtocols, randomString(rand.Intn(20)+1, rand))
@@ -237,6 +238,9 @@ func (*clientHelloMsg) Generate(rand *rand.Rand, size int) reflect.Value {
 	if rand.Intn(10) > 5 {
 		m.earlyData = true
 	}
+	if rand.Intn(10) > 5 {
+		m.certCompressionAlgorithms = []CertificateCompressionAlgorithm{CertificateCompressionZstd, CertificateCompressionZlib}
+	}
 
 	return reflect.ValueOf(m)
 }
@@ -471,10 +475,10 @@ func (*certificateRequestMsgTLS13) Generate(rand *rand.Rand, size int) reflect.V
 		m.scts = true
 	}
 	if rand.Intn(10) > 5 {
-		m.supportedSignatureAlgorithms = supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
+		m.supportedSignatureAlgorithms = defaultConfig().supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
 	}
 	if rand.Intn(10) > 5 {
-		m.supportedSignatureAlgorithmsCert = supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
+		m.supportedSignatureAlgorithmsCert = defaultConfig().supportedSignatureAlgorithms(VersionTLS12, VersionTLS13)
 	}
 	if rand.Intn(10) > 5 {
 		m.certificateAuthorities = make([][]byte, 3)
@@ -482,6 +486,9 @@ func (*certificateRequestMsgTLS13) Generate(rand *rand.Rand, size int) reflect.V
 			m.certificateAuthorities[i] = randomBytes(rand.Intn(10)+1, rand)
 		}
 	}
+	if rand.Intn(10) > 5 {
+		m.certCompressionAlgorithms = []CertificateCompressionAlgorithm{CertificateCompressionZlib}
+	}
 	return reflect.ValueOf(m)
 }
 
@@ -505,6 +512,14 @@ func (*certificateMsgTLS13) Generate(rand *rand.Rand, size int) reflect.Value {
 	return reflect.ValueOf(m)
 }
 
+func (*compressedCertificateMsg) Generate(rand *rand.Rand, size int) reflect.Value {
+	m := &compressedCertificateMsg{}
+	m.algorithm = CertificateCompressionAlgorithm(rand.Intn(65536))
+	m.uncompressedLength = uint32(rand.Intn(1 << 24))
+	m.compressed = randomBytes(rand.Intn(500)+1, rand)
+	return reflect.ValueOf(m)
+}
+
 func TestRejectEmptySCTList(t *testing.T) {
 	// RFC 6962, Section 3.3.1 specifies that empty SCT lists are invalid.
 
@@ -11,6 +11,7 @@ import (
 	"crypto/ed25519"
 	"crypto/mldsa"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"crypto/subtle"
 	"crypto/tls/internal/fips140tls"
 	"crypto/x509"
@@ -312,6 +313,11 @@ func (hs *serverHandshakeState) processClientHello() error {
 			c.sendAlert(alertInternalError)
 			return fmt.Errorf("tls: ML-DSA certificates require TLS 1.3, but client negotiated %s",
 				VersionName(c.vers))
+		case *slhdsa.PublicKey:
+			// SLH-DSA can only be used with TLS 1.3.
+			c.sendAlert(alertInternalError)
+			return fmt.Errorf("tls: SLH-DSA certificates require TLS 1.3, but client negotiated %s",
+				VersionName(c.vers))
 		default:
 			c.sendAlert(alertInternalError)
 			return fmt.Errorf("tls: unsupported signing key type (%T)", priv.Public())
@@ -659,7 +665,7 @@ func (hs *serverHandshakeState) doFullHandshake() error {
 		}
 		if c.vers >= VersionTLS12 {
 			certReq.hasSignatureAlgorithm = true
-			certReq.supportedSignatureAlgorithms = supportedSignatureAlgorithms(c.vers, c.vers)
+			certReq.supportedSignatureAlgorithms = c.config.supportedSignatureAlgorithms(c.vers, c.vers)
 		}
 
 		// An empty list of certificateAuthorities signals to
@@ -840,8 +846,8 @@ func (hs *serverHandshakeState) establishKeys() error {
 		serverCipher = hs.suite.aead(serverKey, serverIV)
 	}
 
-	c.in.prepareCipherSpec(c.vers, clientCipher, clientHash)
-	c.out.prepareCipherSpec(c.vers, serverCipher, serverHash)
+	c.in.prepareCipherSpec(c.vers, clientCipher, clientHash, clientKey, clientIV)
+	c.out.prepareCipherSpec(c.vers, serverCipher, serverHash, serverKey, serverIV)
 
 	return nil
 }
@@ -974,6 +980,10 @@ func (c *Conn) processCertsFromClient(certificate Certificate) error {
 			Intermediates: x509.NewCertPool(),
 			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 		}
+		if c.config.CertificateTransparency != nil {
+			opts.CertificateTransparency = c.config.CertificateTransparency
+			opts.SignedCertificateTimestamps = peerSCTs(certificate.SignedCertificateTimestamps, certificate.OCSPStaple)
+		}
 
 		for _, cert := range certs[1:] {
 			opts.Intermediates.AddCert(cert)
@@ -1016,6 +1026,11 @@ func (c *Conn) processCertsFromClient(certificate Certificate) error {
 				c.sendAlert(alertIllegalParameter)
 				return errors.New("tls: client certificate uses ML-DSA, which requires TLS 1.3")
 			}
+		case *slhdsa.PublicKey:
+			if c.vers < VersionTLS13 {
+				c.sendAlert(alertIllegalParameter)
+				return errors.New("tls: client certificate uses SLH-DSA, which requires TLS 1.3")
+			}
 		default:
 			c.sendAlert(alertUnsupportedCertificate)
 			return fmt.Errorf("tls: client certificate contains an unsupported public key of type %T", certs[0].PublicKey)
@@ -189,6 +189,17 @@ func (hs *serverHandshakeStateTLS13) processClientHello() error {
 			break
 		}
 	}
+	if hs.suite != nil {
+		// An external PSK can only be used with cipher suites with its hash,
+		// so prefer those if the client offered one we know.
+		suite, err := hs.pickExternalPSKSuite(preferenceList)
+		if err != nil {
+			return err
+		}
+		if suite != nil {
+			hs.suite = suite
+		}
+	}
 	if hs.suite == nil {
 		c.sendAlert(alertHandshakeFailure)
 		return fmt.Errorf("tls: no cipher suite supported by both client and server; client offered: %x",
@@ -292,7 +303,8 @@ func (hs *serverHandshakeStateTLS13) processClientHello() error {
 func (hs *serverHandshakeStateTLS13) checkForResumption() error {
 	c := hs.c
 
-	if c.config.SessionTicketsDisabled {
+	externalPSKs := len(c.config.ExternalPSKs) > 0 || c.config.GetExternalPSK != nil
+	if c.config.SessionTicketsDisabled && !externalPSKs {
 		return nil
 	}
 
@@ -320,6 +332,31 @@ func (hs *serverHandshakeStateTLS13) checkForResumption() error {
 			break
 		}
 
+		if externalPSKs {
+			psk, err := hs.lookupExternalPSK(identity.label, hs.suite)
+			if err != nil {
+				c.sendAlert(alertInternalError)
+				return err
+			}
+			if psk != nil {
+				if err := hs.checkPSKBinder(hs.clientHello.pskBinders[i], psk.binderKey); err != nil {
+					return err
+				}
+
+				hs.earlySecret = tls13.NewEarlySecret(hs.suite.hash.New, psk.key)
+				c.externalPSKIdentity = psk.config.Identity
+
+				hs.hello.selectedIdentityPresent = true
+				hs.hello.selectedIdentity = uint16(i)
+				hs.usingPSK = true
+				return nil
+			}
+		}
+
+		if c.config.SessionTicketsDisabled {
+			continue
+		}
+
 		var sessionState *SessionState
 		if c.config.UnwrapSession != nil {
 			var err error
@@ -388,23 +425,9 @@ func (hs *serverHandshakeStateTLS13) checkForResumption() error {
 
 		hs.earlySecret = tls13.NewEarlySecret(hs.suite.hash.New, sessionState.secret)
 		binderKey := hs.earlySecret.ResumptionBinderKey()
-		// Clone the transcript in case a HelloRetryRequest was recorded.
-		transcript := cloneHash(hs.transcript, hs.suite.hash)
-		if transcript == nil {
-			c.sendAlert(alertInternalError)
-			return errors.New("tls: internal error: failed to clone hash")
-		}
-		clientHelloBytes, err := hs.clientHello.marshalWithoutBinders()
-		if err != nil {
-			c.sendAlert(alertInternalError)
+		if err := hs.checkPSKBinder(hs.clientHello.pskBinders[i], binderKey); err != nil {
 			return err
 		}
-		transcript.Write(clientHelloBytes)
-		pskBinder := hs.suite.finishedHash(binderKey, transcript)
-		if !hmac.Equal(hs.clientHello.pskBinders[i], pskBinder) {
-			c.sendAlert(alertDecryptError)
-			return errors.New("tls: invalid PSK binder")
-		}
 
 		if c.quic != nil && hs.clientHello.earlyData && i == 0 &&
 			sessionState.EarlyData && sessionState.cipherSuite == hs.suite.id &&
@@ -436,6 +459,31 @@ func (hs *serverHandshakeStateTLS13) checkForResumption() error {
 	return nil
 }
 
+// checkPSKBinder verifies a PSK binder sent by the client, computed with
+// binderKey over the transcript up to the partial ClientHello.
+func (hs *serverHandshakeStateTLS13) checkPSKBinder(binder, binderKey []byte) error {
+	c := hs.c
+
+	// Clone the transcript in case a HelloRetryRequest was recorded.
+	transcript := cloneHash(hs.transcript, hs.suite.hash)
+	if transcript == nil {
+		c.sendAlert(alertInternalError)
+		return errors.New("tls: internal error: failed to clone hash")
+	}
+	clientHelloBytes, err := hs.clientHello.marshalWithoutBinders()
+	if err != nil {
+		c.sendAlert(alertInternalError)
+		return err
+	}
+	transcript.Write(clientHelloBytes)
+	pskBinder := hs.suite.finishedHash(binderKey, transcript)
+	if !hmac.Equal(binder, pskBinder) {
+		c.sendAlert(alertDecryptError)
+		return errors.New("tls: invalid PSK binder")
+	}
+	return nil
+}
+
 // cloneHash uses [hash.Cloner] to clone in. If [hash.Cloner]
 // is not implemented or not supported, then it falls back to the
 // [encoding.BinaryMarshaler] and [encoding.BinaryUnmarshaler]
@@ -835,11 +883,12 @@ func (hs *serverHandshakeStateTLS13) sendServerCertificate() error {
 		certReq := new(certificateRequestMsgTLS13)
 		certReq.ocspStapling = true
 		certReq.scts = true
-		certReq.supportedSignatureAlgorithms = supportedSignatureAlgorithms(c.vers, c.vers)
-		certReq.supportedSignatureAlgorithmsCert = supportedSignatureAlgorithmsCert(c.vers, c.vers)
+		certReq.supportedSignatureAlgorithms = c.config.supportedSignatureAlgorithms(c.vers, c.vers)
+		certReq.supportedSignatureAlgorithmsCert = c.config.supportedSignatureAlgorithmsCert(c.vers, c.vers)
 		if c.config.ClientCAs != nil {
 			certReq.certificateAuthorities = c.config.ClientCAs.Subjects()
 		}
+		certReq.certCompressionAlgorithms = c.config.certCompressionAlgorithms()
 
 		if _, err := hs.c.writeHandshakeRecord(certReq, hs.transcript); err != nil {
 			return err
@@ -852,7 +901,12 @@ func (hs *serverHandshakeStateTLS13) sendServerCertificate() error {
 	certMsg.scts = hs.clientHello.scts && len(hs.cert.SignedCertificateTimestamps) > 0
 	certMsg.ocspStapling = hs.clientHello.ocspStapling && len(hs.cert.OCSPStaple) > 0
 
-	if _, err := hs.c.writeHandshakeRecord(certMsg, hs.transcript); err != nil {
+	msg, err := c.compressCertificate(certMsg, hs.clientHello.certCompressionAlgorithms)
+	if err != nil {
+		c.sendAlert(alertInternalError)
+		return err
+	}
+	if _, err := hs.c.writeHandshakeRecord(msg, hs.transcript); err != nil {
 		return err
 	}
 
@@ -948,6 +1002,12 @@ func (hs *serverHandshakeStateTLS13) shouldSendSessionTickets() bool {
 		return false
 	}
 
+	// Sessions don't carry the external PSK identity, so they can't be
+	// resumed consistently.
+	if hs.c.externalPSKIdentity != nil {
+		return false
+	}
+
 	// Don't send tickets the client wouldn't use. See RFC 8446, Section 4.2.9.
 	return slices.Contains(hs.clientHello.pskModes, pskModeDHE)
 }
@@ -1050,6 +1110,13 @@ func (hs *serverHandshakeStateTLS13) readClientCertificate() error {
 		return err
 	}
 
+	if compressedMsg, ok := msg.(*compressedCertificateMsg); ok {
+		msg, err = c.decompressCertificate(compressedMsg)
+		if err != nil {
+			return err
+		}
+	}
+
 	certMsg, ok := msg.(*certificateMsgTLS13)
 	if !ok {
 		c.sendAlert(alertUnexpectedMessage)
@@ -1085,7 +1152,7 @@ func (hs *serverHandshakeStateTLS13) readClientCertificate() error {
 		// See RFC 8446, Section 4.4.3.
 		// We don't use certReq.supportedSignatureAlgorithms because it would
 		// require keeping the certificateRequestMsgTLS13 around in the hs.
-		if !isSupportedSignatureAlgorithm(certVerify.signatureAlgorithm, supportedSignatureAlgorithms(c.vers, c.vers)) ||
+		if !isSupportedSignatureAlgorithm(certVerify.signatureAlgorithm, c.config.supportedSignatureAlgorithms(c.vers, c.vers)) ||
 			!isSupportedSignatureAlgorithm(certVerify.signatureAlgorithm, signatureSchemesForPublicKey(c.vers, c.peerCertificates[0].PublicKey)) {
 			c.sendAlert(alertIllegalParameter)
 			return errors.New("tls: client certificate used with invalid signature algorithm")
@@ -297,6 +297,8 @@ func (ka *ecdheKeyAgreement) processServerKeyExchange(config *Config, clientHell
 		switch ka.signatureAlgorithm {
 		case MLDSA44, MLDSA65, MLDSA87:
 			return errors.New("tls: server selected ML-DSA with TLS version < 1.3")
+		case SLHDSA_SHA2_128s, SLHDSA_SHA2_128f, SLHDSA_SHA2_192s, SLHDSA_SHA2_192f, SLHDSA_SHA2_256s, SLHDSA_SHA2_256f, SLHDSA_SHAKE_128s, SLHDSA_SHAKE_128f, SLHDSA_SHAKE_192s, SLHDSA_SHAKE_192f, SLHDSA_SHAKE_256s, SLHDSA_SHAKE_256f:
+			return errors.New("tls: server selected SLH-DSA with TLS version < 1.3")
 		}
 	}
 	sigLen := int(sig[0])<<8 | int(sig[1])
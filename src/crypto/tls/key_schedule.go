@@ -8,6 +8,7 @@ import (
 	"crypto"
 	"crypto/ecdh"
 	"crypto/fips140"
+	"crypto/hkdf"
 	"crypto/hmac"
 	"crypto/internal/fips140/tls13"
 	"crypto/mlkem"
@@ -42,6 +43,23 @@ func (c *cipherSuiteTLS13) finishedHash(baseKey []byte, transcript hash.Hash) []
 	return verifyData.Sum(nil)
 }
 
+// externalBinderKey derives the binder_key of an external PSK according to
+// RFC 8446, Section 7.1, or of an imported PSK according to RFC 9258, Section
+// 4.2. This matches the binder key derived from the Early Secret of
+// [tls13.NewEarlySecret] for the same PSK.
+func (c *cipherSuiteTLS13) externalBinderKey(psk []byte, imported bool) ([]byte, error) {
+	earlySecret, err := hkdf.Extract(c.hash.New, psk, nil)
+	if err != nil {
+		return nil, err
+	}
+	label := "ext binder"
+	if imported {
+		label = "imp binder"
+	}
+	emptyHash := c.hash.New().Sum(nil)
+	return tls13.ExpandLabel(c.hash.New, earlySecret, label, emptyHash, c.hash.Size()), nil
+}
+
 // exportKeyingMaterial implements RFC5705 exporters for TLS 1.3 according to
 // RFC 8446, Section 7.5.
 func (c *cipherSuiteTLS13) exportKeyingMaterial(s *tls13.MasterSecret, transcript hash.Hash) func(string, []byte, int) ([]byte, error) {
@@ -0,0 +1,200 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"crypto/tls/internal/fips140tls"
+	"encoding/binary"
+	"errors"
+	"internal/syscall/unix"
+	"syscall"
+	"unsafe"
+)
+
+// Kernel TLS offload, see https://docs.kernel.org/networking/tls.html.
+//
+// Only the write side is offloaded. Records are read and decrypted in
+// userspace as usual, so post-handshake messages like KeyUpdate and
+// NewSessionTicket don't need special handling on the read side.
+
+// Constants from linux/tcp.h and linux/tls.h.
+const (
+	tcpULP           = 31
+	solTLS           = 282
+	tlsTX            = 1
+	tlsSetRecordType = 1
+
+	tlsCipherAESGCM128        = 51
+	tlsCipherAESGCM256        = 52
+	tlsCipherChaCha20Poly1305 = 54
+)
+
+// enableKernelTLS tries to install the write keys into the kernel, after a
+// successful handshake. If it fails, records keep being encrypted in userspace.
+func (c *Conn) enableKernelTLS() {
+	if fips140tls.Required() {
+		return
+	}
+	switch c.vers {
+	case VersionTLS12:
+	case VersionTLS13:
+		// Without support for updating the keys, which was added in Linux
+		// 6.14, the connection would break when the peer sends a KeyUpdate.
+		if !unix.KernelVersionGE(6, 14) {
+			return
+		}
+	default:
+		return
+	}
+	if c.isClient && c.vers == VersionTLS12 && c.config.Renegotiation != RenegotiateNever {
+		return
+	}
+	if _, ok := c.conn.(syscall.Conn); !ok {
+		return
+	}
+
+	c.out.Lock()
+	defer c.out.Unlock()
+
+	if c.out.err != nil || kernelCryptoInfo(c.vers, c.cipherSuite, &c.out) == nil {
+		return
+	}
+	err := c.controlKernel(func(fd int) error {
+		return syscall.SetsockoptString(fd, syscall.IPPROTO_TCP, tcpULP, "tls")
+	})
+	if err != nil {
+		return
+	}
+	// If this fails, the ULP stays attached but inactive, and the socket
+	// keeps working as a regular TCP socket.
+	if c.setKernelTXKey() == nil {
+		c.kernelTX = true
+	}
+}
+
+// setKernelTXKey installs the current key and sequence number of c.out into
+// the kernel. It is called when enabling kernel TLS, and after a TLS 1.3 key
+// update, which requires Linux 6.14 or later.
+func (c *Conn) setKernelTXKey() error {
+	info := kernelCryptoInfo(c.vers, c.cipherSuite, &c.out)
+	if info == nil {
+		return errors.New("tls: internal error: unsupported cipher suite for kernel TLS")
+	}
+	defer clear(info)
+	return c.controlKernel(func(fd int) error {
+		return syscall.SetsockoptString(fd, solTLS, tlsTX, string(info))
+	})
+}
+
+// kernelCryptoInfo returns the tls12_crypto_info_* structure for the current
+// state of hc, or nil if the cipher suite is not supported by the kernel.
+func kernelCryptoInfo(vers, suite uint16, hc *halfConn) []byte {
+	var cipherType uint16
+	var salt, iv []byte
+	switch suite {
+	case TLS_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_AES_128_GCM_SHA256:
+		cipherType = tlsCipherAESGCM128
+	case TLS_RSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_AES_256_GCM_SHA384:
+		cipherType = tlsCipherAESGCM256
+	case TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256, TLS_CHACHA20_POLY1305_SHA256:
+		cipherType = tlsCipherChaCha20Poly1305
+	default:
+		return nil
+	}
+
+	switch {
+	case cipherType == tlsCipherChaCha20Poly1305 && len(hc.iv) == 12:
+		// The nonce is the IV XORed with the sequence number.
+		iv = hc.iv
+	case vers == VersionTLS13 && len(hc.iv) == 12:
+		// The nonce is the IV XORed with the sequence number, but the
+		// kernel takes it as a 4-byte salt and an 8-byte IV.
+		salt, iv = hc.iv[:4], hc.iv[4:]
+	case vers == VersionTLS12 && len(hc.iv) == 4:
+		// The nonce is the fixed IV followed by an explicit nonce, which
+		// starts at the sequence number, like in conn.encrypt.
+		salt, iv = hc.iv, hc.seq[:]
+	default:
+		return nil
+	}
+	if len(hc.key) != 16 && len(hc.key) != 32 {
+		return nil
+	}
+
+	var info []byte
+	info = binary.NativeEndian.AppendUint16(info, vers)
+	info = binary.NativeEndian.AppendUint16(info, cipherType)
+	info = append(info, iv...)
+	info = append(info, hc.key...)
+	info = append(info, salt...)
+	info = append(info, hc.seq[:]...)
+	return info
+}
+
+// writeKernelRecordLocked writes a record to be encrypted by the kernel.
+// Records other than application data need their type to be passed as
+// ancillary data.
+func (c *Conn) writeKernelRecordLocked(typ recordType, data []byte) (int, error) {
+	if typ == recordTypeApplicationData {
+		n, err := c.conn.Write(data)
+		c.bytesSent += int64(n)
+		return n, err
+	}
+
+	oob := make([]byte, syscall.CmsgSpace(1))
+	h := (*syscall.Cmsghdr)(unsafe.Pointer(&oob[0]))
+	h.Level = solTLS
+	h.Type = tlsSetRecordType
+	h.SetLen(syscall.CmsgLen(1))
+	oob[syscall.CmsgLen(0)] = byte(typ)
+
+	sc, ok := c.conn.(syscall.Conn)
+	if !ok {
+		return 0, errors.New("tls: internal error: kernel TLS enabled on a non-syscall.Conn")
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for n < len(data) {
+		var m int
+		var opErr error
+		err := rc.Write(func(fd uintptr) bool {
+			m, opErr = syscall.SendmsgN(int(fd), data[n:], oob, nil, 0)
+			return opErr != syscall.EAGAIN
+		})
+		if err == nil {
+			err = opErr
+		}
+		if err != nil {
+			return n, err
+		}
+		n += m
+	}
+	return n, nil
+}
+
+// controlKernel calls f with the file descriptor of the underlying connection.
+func (c *Conn) controlKernel(f func(fd int) error) error {
+	sc, ok := c.conn.(syscall.Conn)
+	if !ok {
+		return errors.New("tls: underlying connection doesn't support kernel TLS")
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var opErr error
+	if err := rc.Control(func(fd uintptr) {
+		opErr = f(int(fd))
+	}); err != nil {
+		return err
+	}
+	return opErr
+}
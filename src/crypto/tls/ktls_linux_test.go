@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestKernelCryptoInfo(t *testing.T) {
+	key16 := bytes.Repeat([]byte{0x11}, 16)
+	key32 := bytes.Repeat([]byte{0x22}, 32)
+	iv4 := []byte{1, 2, 3, 4}
+	iv12 := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	seq := [8]byte{0, 0, 0, 0, 0, 0, 0, 7}
+	header := func(vers, cipherType uint16) []byte {
+		b := binary.NativeEndian.AppendUint16(nil, vers)
+		return binary.NativeEndian.AppendUint16(b, cipherType)
+	}
+	cat := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+	for _, tt := range []struct {
+		name  string
+		vers  uint16
+		suite uint16
+		key   []byte
+		iv    []byte
+		want  []byte
+	}{
+		{"TLSv12-AES128GCM", VersionTLS12, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, key16, iv4,
+			cat(header(VersionTLS12, tlsCipherAESGCM128), seq[:], key16, iv4, seq[:])},
+		{"TLSv12-AES256GCM", VersionTLS12, TLS_RSA_WITH_AES_256_GCM_SHA384, key32, iv4,
+			cat(header(VersionTLS12, tlsCipherAESGCM256), seq[:], key32, iv4, seq[:])},
+		{"TLSv12-ChaCha20Poly1305", VersionTLS12, TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256, key32, iv12,
+			cat(header(VersionTLS12, tlsCipherChaCha20Poly1305), iv12, key32, seq[:])},
+		{"TLSv13-AES128GCM", VersionTLS13, TLS_AES_128_GCM_SHA256, key16, iv12,
+			cat(header(VersionTLS13, tlsCipherAESGCM128), iv12[4:], key16, iv12[:4], seq[:])},
+		{"TLSv13-ChaCha20Poly1305", VersionTLS13, TLS_CHACHA20_POLY1305_SHA256, key32, iv12,
+			cat(header(VersionTLS13, tlsCipherChaCha20Poly1305), iv12, key32, seq[:])},
+		{"TLSv12-CBC", VersionTLS12, TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA, key16, iv4, nil},
+	} {
+		hc := &halfConn{key: tt.key, iv: tt.iv, seq: seq}
+		got := kernelCryptoInfo(tt.vers, tt.suite, hc)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: got %x, want %x", tt.name, got, tt.want)
+		}
+	}
+}
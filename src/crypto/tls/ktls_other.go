@@ -0,0 +1,20 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package tls
+
+import "errors"
+
+// enableKernelTLS is a no-op: kernel TLS offload is only supported on Linux.
+func (c *Conn) enableKernelTLS() {}
+
+func (c *Conn) setKernelTXKey() error {
+	return errors.New("tls: kernel TLS is not supported on this platform")
+}
+
+func (c *Conn) writeKernelRecordLocked(typ recordType, data []byte) (int, error) {
+	return 0, errors.New("tls: kernel TLS is not supported on this platform")
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKernelTLS(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		vers    uint16
+		suite   uint16
+		offload bool
+		fips    bool
+	}{
+		{"TLSv12-AES128GCM", VersionTLS12, TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, true, true},
+		{"TLSv12-AES256GCM", VersionTLS12, TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, true, true},
+		{"TLSv12-ChaCha20Poly1305", VersionTLS12, TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256, true, false},
+		{"TLSv12-CBC", VersionTLS12, TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA, false, false},
+		{"TLSv13", VersionTLS13, 0, true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.fips {
+				skipFIPS(t)
+			}
+			serverConfig := testConfigServer.Clone()
+			serverConfig.KernelTLS = true
+			serverConfig.MaxVersion = tt.vers
+			if tt.suite != 0 {
+				serverConfig.CipherSuites = []uint16{tt.suite}
+			}
+			clientConfig := testConfigClient.Clone()
+			clientConfig.KernelTLS = true
+			testKernelTLS(t, serverConfig, clientConfig, tt.offload)
+		})
+	}
+}
+
+func testKernelTLS(t *testing.T, serverConfig, clientConfig *Config, offload bool) {
+	content := make([]byte, 1<<20+123)
+	rand.Read(content)
+	name := filepath.Join(t.TempDir(), "content")
+	if err := os.WriteFile(name, content, 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	c, s := localPipe(t)
+	c.SetDeadline(time.Now().Add(10 * time.Second))
+	s.SetDeadline(time.Now().Add(10 * time.Second))
+	client := Client(c, clientConfig)
+	server := Server(s, serverConfig)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- func() error {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := server.Write([]byte("hello")); err != nil {
+				return err
+			}
+			n, err := server.ReadFrom(f)
+			if err != nil {
+				return err
+			}
+			if n != int64(len(content)) {
+				return fmt.Errorf("ReadFrom copied %d bytes, want %d", n, len(content))
+			}
+			if _, err := server.Write([]byte("bye")); err != nil {
+				return err
+			}
+			return server.Close()
+		}()
+	}()
+
+	got, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	want := append(append([]byte("hello"), content...), "bye"...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("received %d bytes, want %d", len(got), len(want))
+	}
+
+	kernelTX := server.ConnectionState().KernelTLS
+	t.Logf("kernel TLS offload: %v", kernelTX)
+	if kernelTX && !offload {
+		t.Errorf("kernel TLS offload used with unsupported cipher suite %s", CipherSuiteName(server.cipherSuite))
+	}
+	if kernelTX && server.bytesSent != int64(len(want)) {
+		t.Errorf("bytesSent = %d, want %d", server.bytesSent, len(want))
+	}
+}
+
+func TestKernelTLSKeyUpdate(t *testing.T) {
+	serverConfig := testConfigServer.Clone()
+	serverConfig.KernelTLS = true
+	serverConfig.MinVersion = VersionTLS13
+
+	c, s := localPipe(t)
+	c.SetDeadline(time.Now().Add(10 * time.Second))
+	s.SetDeadline(time.Now().Add(10 * time.Second))
+	client := Client(c, testConfigClient)
+	server := Server(s, serverConfig)
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Handshake() }()
+	if err := client.Handshake(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	// Ask the server to update its keys.
+	kuMsg, err := (&keyUpdateMsg{updateRequested: true}).marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := cipherSuiteTLS13ByID(client.cipherSuite)
+	client.out.Lock()
+	_, err = client.writeRecordLocked(recordTypeHandshake, kuMsg)
+	client.setWriteTrafficSecret(cs, QUICEncryptionLevelInitial, cs.nextTrafficSecret(client.out.trafficSecret))
+	client.out.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(server, buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Write([]byte("pong")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(client, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "pong" {
+		t.Errorf("got %q, want pong", buf)
+	}
+}
+
+func TestKernelTLSDisabled(t *testing.T) {
+	c, s := localPipe(t)
+	client := Client(c, testConfigClient)
+	server := Server(s, testConfigServer)
+	errc := make(chan error, 1)
+	go func() { errc <- server.Handshake() }()
+	if err := client.Handshake(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if client.ConnectionState().KernelTLS || server.ConnectionState().KernelTLS {
+		t.Errorf("kernel TLS offload used without Config.KernelTLS")
+	}
+}
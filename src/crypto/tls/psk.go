@@ -0,0 +1,308 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hkdf"
+	"crypto/internal/fips140/tls13"
+	"errors"
+	"slices"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// An ExternalPSK is a TLS 1.3 pre-shared key established out of band, which
+// authenticates both peers in place of certificates. See RFC 8446, Section 2.2.
+//
+// External PSKs should be high-entropy keys, shared by exactly one client and
+// one server. See RFC 9257 for guidance on their use.
+type ExternalPSK struct {
+	// Identity is the PSK identity sent in the clear in the ClientHello. It
+	// must not be empty.
+	Identity []byte
+
+	// Key is the secret pre-shared key. It must not be empty.
+	Key []byte
+
+	// Hash is the hash function associated with the PSK, either
+	// crypto.SHA256 or crypto.SHA384. If zero, crypto.SHA256 is used.
+	//
+	// Unless Import is true, the PSK can only be used with TLS 1.3 cipher
+	// suites that use the same hash.
+	Hash crypto.Hash
+
+	// Import, if true, causes the PSK to be imported according to RFC 9258,
+	// deriving a distinct PSK for each TLS 1.3 cipher suite hash. Both peers
+	// must agree on whether the PSK is imported.
+	Import bool
+
+	// Context is the optional context of an imported PSK. It is ignored
+	// unless Import is true.
+	Context []byte
+}
+
+func (p *ExternalPSK) hash() (crypto.Hash, error) {
+	if len(p.Identity) == 0 || len(p.Identity) > 0xffff-8 {
+		return 0, errors.New("tls: ExternalPSK has an empty or too long Identity")
+	}
+	if len(p.Key) == 0 {
+		return 0, errors.New("tls: ExternalPSK has an empty Key")
+	}
+	if p.Import && len(p.Context) > 0xffff-8-len(p.Identity) {
+		return 0, errors.New("tls: ExternalPSK has a too long Context")
+	}
+	switch p.Hash {
+	case 0:
+		return crypto.SHA256, nil
+	case crypto.SHA256, crypto.SHA384:
+		return p.Hash, nil
+	default:
+		return 0, errors.New("tls: ExternalPSK has an unsupported Hash")
+	}
+}
+
+// TLS KDF identifiers, used as the target_kdf of an imported PSK. See RFC 9258,
+// Section 3.
+const (
+	kdfHKDFSHA256 uint16 = 0x0001
+	kdfHKDFSHA384 uint16 = 0x0002
+)
+
+func kdfForHash(h crypto.Hash) uint16 {
+	switch h {
+	case crypto.SHA256:
+		return kdfHKDFSHA256
+	case crypto.SHA384:
+		return kdfHKDFSHA384
+	default:
+		return 0
+	}
+}
+
+// importedIdentity returns the ImportedIdentity structure for p and the target
+// KDF. See RFC 9258, Section 3.
+func importedIdentity(p *ExternalPSK, kdf uint16) []byte {
+	var b cryptobyte.Builder
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(p.Identity)
+	})
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(p.Context)
+	})
+	b.AddUint16(VersionTLS13) // target_protocol
+	b.AddUint16(kdf)
+	return b.BytesOrPanic()
+}
+
+// parseImportedIdentity parses an ImportedIdentity structure, and returns its
+// external_identity, context, and target_kdf.
+func parseImportedIdentity(identity []byte) (external, context []byte, kdf uint16, ok bool) {
+	s := cryptobyte.String(identity)
+	var protocol uint16
+	if !readUint16LengthPrefixed(&s, &external) || len(external) == 0 ||
+		!readUint16LengthPrefixed(&s, &context) ||
+		!s.ReadUint16(&protocol) || !s.ReadUint16(&kdf) || !s.Empty() {
+		return nil, nil, 0, false
+	}
+	return external, context, kdf, protocol == VersionTLS13
+}
+
+// externalPSK is an external PSK offered by the client, or selected by the
+// server, for a specific TLS 1.3 cipher suite hash.
+type externalPSK struct {
+	config *ExternalPSK
+	// identity is the PSK identity on the wire, which is an ImportedIdentity
+	// structure for imported PSKs.
+	identity []byte
+	// suite is any TLS 1.3 cipher suite with the PSK hash.
+	suite *cipherSuiteTLS13
+	// key is the PSK to use in the key schedule.
+	key       []byte
+	binderKey []byte
+}
+
+// newExternalPSK prepares p for use with cipher suites that have the same
+// hash as suite. It returns nil if that's not possible.
+func newExternalPSK(p *ExternalPSK, suite *cipherSuiteTLS13) (*externalPSK, error) {
+	h, err := p.hash()
+	if err != nil {
+		return nil, err
+	}
+	psk := &externalPSK{config: p, suite: suite}
+	if p.Import {
+		// See RFC 9258, Section 4.1.
+		kdf := kdfForHash(suite.hash)
+		if kdf == 0 {
+			return nil, nil
+		}
+		psk.identity = importedIdentity(p, kdf)
+		epskx, err := hkdf.Extract(h.New, p.Key, nil)
+		if err != nil {
+			return nil, err
+		}
+		identityHash := h.New()
+		identityHash.Write(psk.identity)
+		psk.key = tls13.ExpandLabel(h.New, epskx, "derived psk", identityHash.Sum(nil), suite.hash.Size())
+	} else {
+		if h != suite.hash {
+			return nil, nil
+		}
+		psk.identity = p.Identity
+		psk.key = p.Key
+	}
+	psk.binderKey, err = suite.externalBinderKey(psk.key, p.Import)
+	if err != nil {
+		return nil, err
+	}
+	return psk, nil
+}
+
+// loadExternalPSKs sets the pre_shared_key extension of hello to offer the
+// external PSKs in c.config.ExternalPSKs, and returns them in the same order as
+// hello.pskIdentities.
+func (c *Conn) loadExternalPSKs(hello *clientHelloMsg) ([]*externalPSK, error) {
+	if len(c.config.ExternalPSKs) == 0 || hello.supportedVersions[0] != VersionTLS13 ||
+		c.handshakes != 0 {
+		return nil, nil
+	}
+
+	// Pick one cipher suite per offered hash, to compute binders with.
+	var suites []*cipherSuiteTLS13
+	for _, id := range hello.cipherSuites {
+		suite := cipherSuiteTLS13ByID(id)
+		if suite != nil && !slices.ContainsFunc(suites, func(s *cipherSuiteTLS13) bool {
+			return s.hash == suite.hash
+		}) {
+			suites = append(suites, suite)
+		}
+	}
+
+	var psks []*externalPSK
+	for i := range c.config.ExternalPSKs {
+		for _, suite := range suites {
+			psk, err := newExternalPSK(&c.config.ExternalPSKs[i], suite)
+			if err != nil {
+				return nil, err
+			}
+			if psk != nil {
+				psks = append(psks, psk)
+			}
+		}
+	}
+	if len(psks) == 0 {
+		return nil, nil
+	}
+
+	// External PSKs, like resumption, always require (EC)DHE. See RFC 8446,
+	// Section 4.2.9.
+	hello.pskModes = []uint8{pskModeDHE}
+	hello.pskIdentities = make([]pskIdentity, 0, len(psks))
+	hello.pskBinders = make([][]byte, 0, len(psks))
+	for _, psk := range psks {
+		// The obfuscated_ticket_age of external PSKs is zero. See RFC 8446,
+		// Section 4.2.11.
+		hello.pskIdentities = append(hello.pskIdentities, pskIdentity{label: psk.identity})
+		hello.pskBinders = append(hello.pskBinders, make([]byte, psk.suite.hash.Size()))
+	}
+
+	// Compute the PSK binders. See RFC 8446, Section 4.2.11.2.
+	if err := computeAndUpdateExternalPSKs(hello, psks, nil); err != nil {
+		return nil, err
+	}
+	return psks, nil
+}
+
+// computeAndUpdateExternalPSKs computes the binders of the external PSKs in
+// m, which must match psks, over the transcript (the messages preceding m, if
+// there was a HelloRetryRequest) followed by the partial ClientHello.
+func computeAndUpdateExternalPSKs(m *clientHelloMsg, psks []*externalPSK, prefix []byte) error {
+	helloBytes, err := m.marshalWithoutBinders()
+	if err != nil {
+		return err
+	}
+	binders := make([][]byte, 0, len(psks))
+	for _, psk := range psks {
+		transcript := psk.suite.hash.New()
+		transcript.Write(prefix)
+		transcript.Write(helloBytes)
+		binders = append(binders, psk.suite.finishedHash(psk.binderKey, transcript))
+	}
+	return m.updateBinders(binders)
+}
+
+// pickExternalPSKSuite returns the most preferred cipher suite in
+// preferenceList that can be used with one of the external PSKs offered by the
+// client, or nil if there is none.
+func (hs *serverHandshakeStateTLS13) pickExternalPSKSuite(preferenceList []uint16) (*cipherSuiteTLS13, error) {
+	c := hs.c
+	if len(c.config.ExternalPSKs) == 0 && c.config.GetExternalPSK == nil ||
+		!slices.Contains(hs.clientHello.pskModes, pskModeDHE) {
+		return nil, nil
+	}
+
+	var hashes []crypto.Hash
+	for _, suiteID := range preferenceList {
+		suite := mutualCipherSuiteTLS13(hs.clientHello.cipherSuites, suiteID)
+		if suite == nil || slices.Contains(hashes, suite.hash) {
+			continue
+		}
+		hashes = append(hashes, suite.hash)
+		for i, identity := range hs.clientHello.pskIdentities {
+			if i >= maxClientPSKIdentities {
+				break
+			}
+			psk, err := hs.lookupExternalPSK(identity.label, suite)
+			if err != nil {
+				c.sendAlert(alertInternalError)
+				return nil, err
+			}
+			if psk != nil {
+				return suite, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// lookupExternalPSK returns the external PSK matching a PSK identity offered
+// by the client, if any, for use with suite.
+func (hs *serverHandshakeStateTLS13) lookupExternalPSK(identity []byte, suite *cipherSuiteTLS13) (*externalPSK, error) {
+	c := hs.c
+
+	lookup := func(identity []byte) (*ExternalPSK, error) {
+		if c.config.GetExternalPSK != nil {
+			return c.config.GetExternalPSK(clientHelloInfo(hs.ctx, c, hs.clientHello), identity)
+		}
+		for i := range c.config.ExternalPSKs {
+			if bytes.Equal(c.config.ExternalPSKs[i].Identity, identity) {
+				return &c.config.ExternalPSKs[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	p, err := lookup(identity)
+	if err != nil {
+		return nil, err
+	}
+	if p != nil && !p.Import {
+		return newExternalPSK(p, suite)
+	}
+
+	external, context, kdf, ok := parseImportedIdentity(identity)
+	if !ok || kdf != kdfForHash(suite.hash) {
+		return nil, nil
+	}
+	p, err = lookup(external)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || !p.Import || !bytes.Equal(p.Context, context) {
+		return nil, nil
+	}
+	return newExternalPSK(p, suite)
+}
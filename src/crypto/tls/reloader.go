@@ -0,0 +1,188 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A CertificateReloader is a source of a certificate and private key, and
+// optionally of a pool of client CAs, loaded from PEM files and reloaded when
+// they change.
+//
+// Reloading is atomic: handshakes use either the previous or the new contents
+// of all files. If the new files can't be loaded, for example because the
+// certificate and key don't match while they are being replaced, the previous
+// certificate and pool remain in use.
+//
+// A CertificateReloader is safe for concurrent use by multiple goroutines.
+type CertificateReloader struct {
+	// OnError, if not nil, is called by Run with any error encountered while
+	// reloading the files. The previous certificate and pool are still in use.
+	// It must not be modified after Run is called.
+	OnError func(error)
+
+	certFile, keyFile, clientCAFile string
+
+	mu    sync.Mutex // serializes reloads
+	state atomic.Pointer[reloaderState]
+
+	// config caches the Config returned by ConfigForClient for the current
+	// state.
+	config atomic.Pointer[reloaderConfig]
+}
+
+type reloaderState struct {
+	certPEM, keyPEM, clientCAPEM []byte
+
+	cert      *Certificate
+	clientCAs *x509.CertPool
+}
+
+type reloaderConfig struct {
+	base, config *Config
+	state        *reloaderState
+}
+
+// NewCertificateReloader returns a CertificateReloader for the certificate
+// chain in certFile and the private key in keyFile, which are loaded like
+// [LoadX509KeyPair]. If clientCAFile is not empty, it must contain one or
+// more PEM encoded certificates, which are loaded as the pool of client CAs.
+//
+// The files are loaded before NewCertificateReloader returns. They are only
+// reloaded by [CertificateReloader.Reload] and [CertificateReloader.Run].
+func NewCertificateReloader(certFile, keyFile, clientCAFile string) (*CertificateReloader, error) {
+	r := &CertificateReloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the files again and, if any of them changed, replaces the
+// certificate and pool of client CAs. If an error is returned, the previous
+// certificate and pool remain in use.
+func (r *CertificateReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	certPEM, err := os.ReadFile(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := os.ReadFile(r.keyFile)
+	if err != nil {
+		return err
+	}
+	var clientCAPEM []byte
+	if r.clientCAFile != "" {
+		clientCAPEM, err = os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	old := r.state.Load()
+	if old != nil && bytes.Equal(old.certPEM, certPEM) && bytes.Equal(old.keyPEM, keyPEM) &&
+		bytes.Equal(old.clientCAPEM, clientCAPEM) {
+		return nil
+	}
+
+	// X509KeyPair checks that the private key matches the leaf certificate.
+	cert, err := X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return errors.New("tls: failed to reload " + r.certFile + " and " + r.keyFile + ": " + err.Error())
+	}
+	state := &reloaderState{
+		certPEM:     certPEM,
+		keyPEM:      keyPEM,
+		clientCAPEM: clientCAPEM,
+		cert:        &cert,
+	}
+	if r.clientCAFile != "" {
+		state.clientCAs = x509.NewCertPool()
+		if !state.clientCAs.AppendCertsFromPEM(clientCAPEM) {
+			return errors.New("tls: failed to reload " + r.clientCAFile + ": no certificates found")
+		}
+	}
+	r.state.Store(state)
+	return nil
+}
+
+// Run calls Reload every interval, until ctx is done. Errors are reported to
+// OnError, and don't stop Run. Run returns ctx.Err(), or an error without
+// reloading if interval is not positive.
+func (r *CertificateReloader) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("tls: non-positive interval for CertificateReloader.Run")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.Reload(); err != nil && r.OnError != nil {
+				r.OnError(err)
+			}
+		}
+	}
+}
+
+// Certificate returns the current certificate. It must not be modified.
+func (r *CertificateReloader) Certificate() *Certificate {
+	return r.state.Load().cert
+}
+
+// ClientCAs returns the current pool of client CAs, or nil if no clientCAFile
+// was provided to [NewCertificateReloader]. It must not be modified.
+func (r *CertificateReloader) ClientCAs() *x509.CertPool {
+	return r.state.Load().clientCAs
+}
+
+// GetCertificate returns the current certificate. It can be used as
+// [Config.GetCertificate].
+func (r *CertificateReloader) GetCertificate(*ClientHelloInfo) (*Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// GetClientCertificate returns the current certificate. It can be used as
+// [Config.GetClientCertificate].
+func (r *CertificateReloader) GetClientCertificate(*CertificateRequestInfo) (*Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// ConfigForClient returns a function that can be used as
+// [Config.GetConfigForClient]. It returns a clone of base that uses the
+// current certificate and, if a clientCAFile was provided to
+// [NewCertificateReloader], the current pool of client CAs as ClientCAs.
+//
+// base must not be modified after calling ConfigForClient, and must not itself
+// have a GetConfigForClient callback.
+func (r *CertificateReloader) ConfigForClient(base *Config) func(*ClientHelloInfo) (*Config, error) {
+	return func(*ClientHelloInfo) (*Config, error) {
+		state := r.state.Load()
+		if cached := r.config.Load(); cached != nil && cached.base == base && cached.state == state {
+			return cached.config, nil
+		}
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		config.Certificates = nil
+		config.GetCertificate = r.GetCertificate
+		if state.clientCAs != nil {
+			config.ClientCAs = state.clientCAs
+		}
+		r.config.Store(&reloaderConfig{base: base, config: config, state: state})
+		return config, nil
+	}
+}
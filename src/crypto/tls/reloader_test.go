@@ -0,0 +1,161 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeReloaderFiles(t *testing.T, dir, certPEM, keyPEM, caPEM string) (certFile, keyFile, caFile string) {
+	t.Helper()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	caFile = filepath.Join(dir, "ca.pem")
+	for file, data := range map[string]string{certFile: certPEM, keyFile: keyPEM, caFile: caPEM} {
+		if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return certFile, keyFile, caFile
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := writeReloaderFiles(t, dir, testECDSAP256CertPEM, testingKey(testECDSAP256KeyPEM), testClientRootCertPEM)
+
+	r, err := NewCertificateReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ClientCAs() == nil {
+		t.Fatal("ClientCAs is nil")
+	}
+
+	base := testConfigServer.Clone()
+	base.Certificates = nil
+	base.ClientCAs = nil
+	base.ClientAuth = RequireAndVerifyClientCert
+	serverConfig := &Config{GetConfigForClient: r.ConfigForClient(base)}
+	clientConfig := testConfigClient.Clone()
+
+	handshake := func(wantLeaf []byte) {
+		t.Helper()
+		ss, cs, err := testHandshake(t, clientConfig, serverConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if !bytes.Equal(cs.PeerCertificates[0].Raw, wantLeaf) {
+			t.Error("client saw an unexpected server certificate")
+		}
+		if len(ss.VerifiedChains) == 0 {
+			t.Error("client certificate was not verified")
+		}
+	}
+	handshake(testECDSAP256Cert.Certificate[0])
+
+	// Reloading unchanged files is a no-op.
+	cert := r.Certificate()
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Certificate() != cert {
+		t.Error("certificate replaced although the files didn't change")
+	}
+
+	// A key that doesn't match the certificate is rejected, and the previous
+	// certificate is kept.
+	writeReloaderFiles(t, dir, testECDSAP256CertPEM, testingKey(testRSA2048KeyPEM), testClientRootCertPEM)
+	if err := r.Reload(); err == nil {
+		t.Error("Reload succeeded with a mismatched key")
+	}
+	if r.Certificate() != cert {
+		t.Error("certificate replaced after a failed reload")
+	}
+	handshake(testECDSAP256Cert.Certificate[0])
+
+	// An empty CA file is rejected.
+	writeReloaderFiles(t, dir, testRSA2048CertPEM, testingKey(testRSA2048KeyPEM), "")
+	if err := r.Reload(); err == nil {
+		t.Error("Reload succeeded with an empty client CA file")
+	}
+
+	writeReloaderFiles(t, dir, testRSA2048CertPEM, testingKey(testRSA2048KeyPEM), testClientRootCertPEM)
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	handshake(testRSA2048Cert.Certificate[0])
+}
+
+func TestCertificateReloaderRun(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeReloaderFiles(t, dir, testECDSAP256CertPEM, testingKey(testECDSAP256KeyPEM), "")
+
+	r, err := NewCertificateReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ClientCAs() != nil {
+		t.Error("ClientCAs is not nil without a client CA file")
+	}
+	errs := make(chan error, 1)
+	r.OnError = func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx, time.Millisecond) }()
+
+	writeReloaderFiles(t, dir, testECDSAP256CertPEM, testingKey(testRSA2048KeyPEM), "")
+	if err := <-errs; err == nil {
+		t.Error("OnError called with a nil error")
+	}
+
+	writeReloaderFiles(t, dir, testRSA2048CertPEM, testingKey(testRSA2048KeyPEM), "")
+	for {
+		cert, err := r.GetCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(cert.Certificate[0], testRSA2048Cert.Certificate[0]) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCertificateReloaderRunInterval(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeReloaderFiles(t, dir, testECDSAP256CertPEM, testingKey(testECDSAP256KeyPEM), "")
+
+	r, err := NewCertificateReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := r.Run(context.Background(), interval); err == nil {
+			t.Errorf("Run(%v) succeeded", interval)
+		}
+	}
+}
+
+func TestCertificateReloaderMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewCertificateReloader(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), ""); err == nil {
+		t.Error("NewCertificateReloader succeeded with missing files")
+	}
+}
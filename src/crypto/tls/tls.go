@@ -30,6 +30,7 @@ import (
 	"crypto/ed25519"
 	"crypto/mldsa"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
@@ -96,12 +97,14 @@ func NewListener(inner net.Listener, config *Config) net.Listener {
 // Listen creates a TLS listener accepting connections on the
 // given network address using net.Listen.
 // The configuration config must be non-nil and must include
-// at least one certificate or else set GetCertificate.
+// at least one certificate or else set GetCertificate, GetConfigForClient,
+// ExternalPSKs, or GetExternalPSK.
 func Listen(network, laddr string, config *Config) (net.Listener, error) {
 	// If this condition changes, consider updating http.Server.ServeTLS too.
 	if config == nil || len(config.Certificates) == 0 &&
-		config.GetCertificate == nil && config.GetConfigForClient == nil {
-		return nil, errors.New("tls: neither Certificates, GetCertificate, nor GetConfigForClient set in Config")
+		config.GetCertificate == nil && config.GetConfigForClient == nil &&
+		len(config.ExternalPSKs) == 0 && config.GetExternalPSK == nil {
+		return nil, errors.New("tls: neither Certificates, GetCertificate, GetConfigForClient, nor ExternalPSKs set in Config")
 	}
 	l, err := net.Listen(network, laddr)
 	if err != nil {
@@ -348,6 +351,14 @@ func X509KeyPair(certPEMBlock, keyPEMBlock []byte) (Certificate, error) {
 		if !priv.PublicKey().Equal(pub) {
 			return fail(errors.New("tls: private key does not match public key"))
 		}
+	case *slhdsa.PublicKey:
+		priv, ok := cert.PrivateKey.(*slhdsa.PrivateKey)
+		if !ok {
+			return fail(errors.New("tls: private key type does not match public key type"))
+		}
+		if !priv.PublicKey().Equal(pub) {
+			return fail(errors.New("tls: private key does not match public key"))
+		}
 	default:
 		return fail(errors.New("tls: unknown public key algorithm"))
 	}
@@ -371,7 +382,7 @@ func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
 		return nil, fmt.Errorf("tls: failed to parse private key: %w", pkcs8Err)
 	}
 	switch key := key.(type) {
-	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, *mldsa.PrivateKey:
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, *mldsa.PrivateKey, *slhdsa.PrivateKey:
 		return key, nil
 	default:
 		return nil, errors.New("tls: found unknown private key type in PKCS#8 wrapping")
@@ -16,6 +16,7 @@ import (
 	"crypto/internal/cryptotest"
 	"crypto/mldsa"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls/internal/fips140tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -879,7 +880,7 @@ func TestWarningAlertFlood(t *testing.T) {
 }
 
 func TestCloneFuncFields(t *testing.T) {
-	const expectedCount = 10
+	const expectedCount = 11
 	called := 0
 
 	c1 := Config{
@@ -923,6 +924,10 @@ func TestCloneFuncFields(t *testing.T) {
 			called |= 1 << 9
 			return nil, nil
 		},
+		GetExternalPSK: func(*ClientHelloInfo, []byte) (*ExternalPSK, error) {
+			called |= 1 << 10
+			return nil, nil
+		},
 	}
 
 	c2 := c1.Clone()
@@ -937,6 +942,7 @@ func TestCloneFuncFields(t *testing.T) {
 	c2.WrapSession(ConnectionState{}, nil)
 	c2.EncryptedClientHelloRejectionVerify(ConnectionState{})
 	c2.GetEncryptedClientHelloKeys(nil)
+	c2.GetExternalPSK(nil, nil)
 
 	if called != (1<<expectedCount)-1 {
 		t.Fatalf("expected %d calls but saw calls %b", expectedCount, called)
@@ -955,7 +961,7 @@ func TestCloneNonFuncFields(t *testing.T) {
 		switch fn := typ.Field(i).Name; fn {
 		case "Rand":
 			f.Set(reflect.ValueOf(io.Reader(os.Stdin)))
-		case "Time", "GetCertificate", "GetConfigForClient", "VerifyPeerCertificate", "VerifyConnection", "GetClientCertificate", "WrapSession", "UnwrapSession", "EncryptedClientHelloRejectionVerify", "GetEncryptedClientHelloKeys":
+		case "Time", "GetCertificate", "GetConfigForClient", "VerifyPeerCertificate", "VerifyConnection", "GetClientCertificate", "WrapSession", "UnwrapSession", "EncryptedClientHelloRejectionVerify", "GetEncryptedClientHelloKeys", "GetExternalPSK":
 			// DeepEqual can't compare functions. If you add a
 			// function field to this list, you must also change
 			// TestCloneFuncFields to ensure that the func field is
@@ -968,6 +974,8 @@ func TestCloneNonFuncFields(t *testing.T) {
 			f.Set(reflect.ValueOf(map[string]*Certificate{"a": nil}))
 		case "RootCAs", "ClientCAs":
 			f.Set(reflect.ValueOf(x509.NewCertPool()))
+		case "CertificateTransparency":
+			f.Set(reflect.ValueOf(&x509.CTPolicy{MinSCTs: 2}))
 		case "ClientSessionCache":
 			f.Set(reflect.ValueOf(NewLRUClientSessionCache(10)))
 		case "KeyLogWriter":
@@ -978,7 +986,7 @@ func TestCloneNonFuncFields(t *testing.T) {
 			f.Set(reflect.ValueOf("b"))
 		case "ClientAuth":
 			f.Set(reflect.ValueOf(VerifyClientCertIfGiven))
-		case "InsecureSkipVerify", "SessionTicketsDisabled", "DynamicRecordSizingDisabled", "PreferServerCipherSuites":
+		case "InsecureSkipVerify", "SessionTicketsDisabled", "DynamicRecordSizingDisabled", "PreferServerCipherSuites", "KernelTLS":
 			f.Set(reflect.ValueOf(true))
 		case "MinVersion", "MaxVersion":
 			f.Set(reflect.ValueOf(uint16(VersionTLS12)))
@@ -988,6 +996,12 @@ func TestCloneNonFuncFields(t *testing.T) {
 			f.Set(reflect.ValueOf([]uint16{1, 2}))
 		case "CurvePreferences":
 			f.Set(reflect.ValueOf([]CurveID{CurveP256}))
+		case "SignatureSchemes":
+			f.Set(reflect.ValueOf([]SignatureScheme{SLHDSA_SHA2_128s}))
+		case "CertificateCompression":
+			f.Set(reflect.ValueOf([]CertificateCompressionAlgorithm{CertificateCompressionZlib}))
+		case "ExternalPSKs":
+			f.Set(reflect.ValueOf([]ExternalPSK{{Identity: []byte{'a'}, Key: []byte{'b'}}}))
 		case "Renegotiation":
 			f.Set(reflect.ValueOf(RenegotiateOnceAsClient))
 		case "EncryptedClientHelloConfigList":
@@ -2297,8 +2311,8 @@ func TestSupportedSignatureAlgorithmsMLDSAGating(t *testing.T) {
 	mldsaSchemes := []SignatureScheme{MLDSA44, MLDSA65, MLDSA87}
 
 	if fips140.Version() == "v1.0.0" {
-		fullRange := supportedSignatureAlgorithms(VersionTLS10, VersionTLS13)
-		certExt := supportedSignatureAlgorithmsCert(VersionTLS10, VersionTLS13)
+		fullRange := defaultConfig().supportedSignatureAlgorithms(VersionTLS10, VersionTLS13)
+		certExt := defaultConfig().supportedSignatureAlgorithmsCert(VersionTLS10, VersionTLS13)
 		for _, s := range mldsaSchemes {
 			if slices.Contains(fullRange, s) {
 				t.Errorf("supportedSignatureAlgorithms contains %v under FIPS 140-3 v1.0.0", s)
@@ -2310,8 +2324,8 @@ func TestSupportedSignatureAlgorithmsMLDSAGating(t *testing.T) {
 		return
 	}
 
-	tls12Only := supportedSignatureAlgorithms(VersionTLS12, VersionTLS12)
-	tls12OnlyCert := supportedSignatureAlgorithmsCert(VersionTLS12, VersionTLS12)
+	tls12Only := defaultConfig().supportedSignatureAlgorithms(VersionTLS12, VersionTLS12)
+	tls12OnlyCert := defaultConfig().supportedSignatureAlgorithmsCert(VersionTLS12, VersionTLS12)
 	for _, s := range mldsaSchemes {
 		if slices.Contains(tls12Only, s) {
 			t.Errorf("supportedSignatureAlgorithms(TLS12, TLS12) contains %v; ML-DSA must not be advertised in TLS 1.2", s)
@@ -2320,8 +2334,8 @@ func TestSupportedSignatureAlgorithmsMLDSAGating(t *testing.T) {
 			t.Errorf("supportedSignatureAlgorithmsCert(TLS12, TLS12) contains %v; ML-DSA must not be advertised in TLS 1.2", s)
 		}
 	}
-	tls13Only := supportedSignatureAlgorithms(VersionTLS13, VersionTLS13)
-	tls13OnlyCert := supportedSignatureAlgorithmsCert(VersionTLS13, VersionTLS13)
+	tls13Only := defaultConfig().supportedSignatureAlgorithms(VersionTLS13, VersionTLS13)
+	tls13OnlyCert := defaultConfig().supportedSignatureAlgorithmsCert(VersionTLS13, VersionTLS13)
 	for _, s := range mldsaSchemes {
 		if !slices.Contains(tls13Only, s) {
 			t.Errorf("supportedSignatureAlgorithms(TLS13, TLS13) is missing %v", s)
@@ -2332,6 +2346,42 @@ func TestSupportedSignatureAlgorithmsMLDSAGating(t *testing.T) {
 	}
 }
 
+func TestSupportedSignatureAlgorithmsSLHDSAGating(t *testing.T) {
+	isSLHDSA := func(s SignatureScheme) bool {
+		sigType, _, _ := typeAndHashFromSignatureScheme(s)
+		return sigType == signatureSLHDSA
+	}
+
+	if slices.ContainsFunc(defaultConfig().supportedSignatureAlgorithms(VersionTLS13, VersionTLS13), isSLHDSA) {
+		t.Errorf("supportedSignatureAlgorithms(TLS13, TLS13) contains SLH-DSA by default")
+	}
+
+	slhdsaSchemes := []SignatureScheme{
+		SLHDSA_SHA2_128s, SLHDSA_SHA2_128f, SLHDSA_SHA2_192s, SLHDSA_SHA2_192f,
+		SLHDSA_SHA2_256s, SLHDSA_SHA2_256f, SLHDSA_SHAKE_128s, SLHDSA_SHAKE_128f,
+		SLHDSA_SHAKE_192s, SLHDSA_SHAKE_192f, SLHDSA_SHAKE_256s, SLHDSA_SHAKE_256f,
+	}
+	config := &Config{SignatureSchemes: append([]SignatureScheme{ECDSAWithP256AndSHA256}, slhdsaSchemes...)}
+	if slices.ContainsFunc(config.supportedSignatureAlgorithms(VersionTLS12, VersionTLS12), isSLHDSA) {
+		t.Errorf("supportedSignatureAlgorithms(TLS12, TLS12) contains SLH-DSA; SLH-DSA must not be advertised in TLS 1.2")
+	}
+	tls13Only := config.supportedSignatureAlgorithms(VersionTLS13, VersionTLS13)
+	if slices.Contains(tls13Only, PSSWithSHA256) {
+		t.Errorf("supportedSignatureAlgorithms(TLS13, TLS13) contains %v, which is not in SignatureSchemes", PSSWithSHA256)
+	}
+	if v := fips140.Version(); v == "v1.0.0" || v == "v1.26.0" {
+		if slices.ContainsFunc(tls13Only, isSLHDSA) {
+			t.Errorf("supportedSignatureAlgorithms contains SLH-DSA under FIPS 140-3 %s", v)
+		}
+		return
+	}
+	for _, s := range slhdsaSchemes {
+		if !slices.Contains(tls13Only, s) {
+			t.Errorf("supportedSignatureAlgorithms(TLS13, TLS13) is missing %v", s)
+		}
+	}
+}
+
 func TestHandshakeMLDSA(t *testing.T) {
 	for _, tt := range []struct {
 		name   string
@@ -3072,3 +3122,375 @@ func TestLocalCertificateResumption(t *testing.T) {
 		})
 	}
 }
+
+func TestCertificateTransparency(t *testing.T) {
+	logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverCert := testCTCert(t)
+	sct := testSCT(t, logKey, serverCert.Certificate[0])
+
+	var sctList cryptobyte.Builder
+	sctList.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) { b.AddBytes(sct) })
+	})
+	sctListValue, err := asn1.Marshal(sctList.BytesOrPanic())
+	if err != nil {
+		t.Fatal(err)
+	}
+	staple, err := x509.CreateOCSPResponse(rand.Reader, &x509.OCSPResponse{
+		Status:       x509.OCSPGood,
+		SerialNumber: serverCert.Leaf.SerialNumber,
+		ThisUpdate:   testTime().Add(-time.Hour),
+		NextUpdate:   testTime().Add(time.Hour),
+		ExtraSingleExtensions: []pkix.Extension{{
+			Id:    asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 5},
+			Value: sctListValue,
+		}},
+	}, testRootCert.Leaf, testRootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &x509.CTPolicy{Logs: []*x509.CTLog{{PublicKey: logKey.Public()}}}
+
+	for _, test := range []struct {
+		name    string
+		scts    [][]byte
+		staple  []byte
+		policy  *x509.CTPolicy
+		wantErr bool
+	}{
+		{name: "TLS extension", scts: [][]byte{sct}, policy: policy},
+		{name: "OCSP staple", staple: staple, policy: policy},
+		{name: "missing", policy: policy, wantErr: true},
+		{name: "too few", scts: [][]byte{sct}, policy: &x509.CTPolicy{Logs: policy.Logs, MinSCTs: 2}, wantErr: true},
+		{name: "unknown log", scts: [][]byte{sct}, policy: &x509.CTPolicy{}, wantErr: true},
+		{name: "no policy"},
+	} {
+		for _, version := range []uint16{VersionTLS12, VersionTLS13} {
+			t.Run(fmt.Sprintf("%s/%s", test.name, VersionName(version)), func(t *testing.T) {
+				cert := serverCert
+				cert.SignedCertificateTimestamps = test.scts
+				cert.OCSPStaple = test.staple
+				serverConfig := &Config{
+					Time:         testTime,
+					Certificates: []Certificate{cert},
+				}
+				clientConfig := &Config{
+					Time:                    testTime,
+					RootCAs:                 testRootCertPool,
+					ServerName:              "test.golang.example",
+					MinVersion:              version,
+					MaxVersion:              version,
+					CertificateTransparency: test.policy,
+				}
+				_, _, err := testHandshake(t, clientConfig, serverConfig)
+				if test.wantErr {
+					if err == nil || !strings.Contains(err.Error(), "Certificate Transparency policy") {
+						t.Fatalf("handshake error = %v, want Certificate Transparency policy error", err)
+					}
+				} else if err != nil {
+					t.Fatalf("handshake failed: %v", err)
+				}
+			})
+		}
+	}
+
+	// In TLS 1.3 the server can request SCTs for the client certificate.
+	clientCert := serverCert
+	clientCert.SignedCertificateTimestamps = [][]byte{sct}
+	serverConfig := &Config{
+		Time:                    testTime,
+		Certificates:            []Certificate{serverCert},
+		ClientAuth:              RequireAndVerifyClientCert,
+		ClientCAs:               testRootCertPool,
+		CertificateTransparency: policy,
+	}
+	clientConfig := &Config{
+		Time:               testTime,
+		Certificates:       []Certificate{clientCert},
+		InsecureSkipVerify: true,
+	}
+	if _, _, err := testHandshake(t, clientConfig, serverConfig); err != nil {
+		t.Fatalf("TLS 1.3 handshake with client SCTs failed: %v", err)
+	}
+	clientConfig.MaxVersion = VersionTLS12
+	if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+		t.Fatal("TLS 1.2 handshake without client SCTs succeeded")
+	}
+}
+
+// testCTCert returns an ECDSA leaf for test.golang.example issued by
+// testRootCert.
+func testCTCert(t *testing.T) Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test.golang.example"},
+		DNSNames:     []string{"test.golang.example"},
+		NotBefore:    testTime().Add(-time.Hour),
+		NotAfter:     testTime().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, testRootCert.Leaf, key.Public(), testRootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// testSCT returns a serialized RFC 6962 SignedCertificateTimestamp by logKey
+// over the X.509 certificate der.
+func testSCT(t *testing.T, logKey *ecdsa.PrivateKey, der []byte) []byte {
+	t.Helper()
+	timestamp := uint64(testTime().Add(-time.Minute).UnixMilli())
+
+	signed := cryptobyte.NewBuilder(nil)
+	signed.AddUint8(0) // sct_version v1
+	signed.AddUint8(0) // signature_type certificate_timestamp
+	signed.AddUint64(timestamp)
+	signed.AddUint16(0) // entry_type x509_entry
+	signed.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) { b.AddBytes(der) })
+	signed.AddUint16(0) // extensions
+	digest := sha256.Sum256(signed.BytesOrPanic())
+	sig, err := ecdsa.SignASN1(rand.Reader, logKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spki, err := x509.MarshalPKIXPublicKey(logKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logID := sha256.Sum256(spki)
+
+	b := cryptobyte.NewBuilder(nil)
+	b.AddUint8(0)
+	b.AddBytes(logID[:])
+	b.AddUint64(timestamp)
+	b.AddUint16(0)
+	b.AddUint8(4) // sha256
+	b.AddUint8(3) // ecdsa
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) { b.AddBytes(sig) })
+	return b.BytesOrPanic()
+}
+
+func TestCertificateCompression(t *testing.T) {
+	zlib, zstd := CertificateCompressionZlib, CertificateCompressionZstd
+	for _, tt := range []struct {
+		name           string
+		client, server []CertificateCompressionAlgorithm
+	}{
+		{"Zlib", []CertificateCompressionAlgorithm{zlib}, []CertificateCompressionAlgorithm{zlib}},
+		{"Zstd", []CertificateCompressionAlgorithm{zstd}, []CertificateCompressionAlgorithm{zstd}},
+		{"Both", []CertificateCompressionAlgorithm{zstd, zlib}, []CertificateCompressionAlgorithm{zlib, zstd}},
+		{"Mismatch", []CertificateCompressionAlgorithm{zlib}, []CertificateCompressionAlgorithm{zstd}},
+		{"ClientOnly", []CertificateCompressionAlgorithm{zlib}, nil},
+		{"ServerOnly", nil, []CertificateCompressionAlgorithm{zstd}},
+		{"Unsupported", []CertificateCompressionAlgorithm{2}, []CertificateCompressionAlgorithm{2}},
+	} {
+		for _, version := range []uint16{VersionTLS12, VersionTLS13} {
+			t.Run(tt.name+"/"+VersionName(version), func(t *testing.T) {
+				serverConfig := testConfigServer.Clone()
+				serverConfig.Certificates = []Certificate{testECDSAP256Cert}
+				serverConfig.ClientAuth = RequireAndVerifyClientCert
+				serverConfig.CertificateCompression = tt.server
+				clientConfig := testConfigClient.Clone()
+				clientConfig.Certificates = []Certificate{testClientECDSAP256Cert}
+				clientConfig.MaxVersion = version
+				clientConfig.CertificateCompression = tt.client
+				ss, cs, err := testHandshake(t, clientConfig, serverConfig)
+				if err != nil {
+					t.Fatalf("handshake failed: %v", err)
+				}
+				if len(ss.PeerCertificates) == 0 || len(cs.PeerCertificates) == 0 {
+					t.Errorf("peer certificates missing after handshake")
+				}
+			})
+		}
+	}
+}
+
+func TestCompressCertificate(t *testing.T) {
+	certMsg := &certificateMsgTLS13{certificate: Certificate{
+		// A chain with a repeated certificate is sure to compress.
+		Certificate: [][]byte{testRSA2048Cert.Certificate[0], testRSA2048Cert.Certificate[0]},
+	}}
+	peer := []CertificateCompressionAlgorithm{CertificateCompressionZstd, CertificateCompressionZlib}
+
+	for _, alg := range []CertificateCompressionAlgorithm{CertificateCompressionZlib, CertificateCompressionZstd} {
+		t.Run(alg.String(), func(t *testing.T) {
+			c := Client(&discardConn{}, &Config{CertificateCompression: []CertificateCompressionAlgorithm{alg}})
+			msg, err := c.compressCertificate(certMsg, peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compressed, ok := msg.(*compressedCertificateMsg)
+			if !ok {
+				t.Fatalf("got %T, want *compressedCertificateMsg", msg)
+			}
+			if compressed.algorithm != alg {
+				t.Errorf("algorithm = %v, want %v", compressed.algorithm, alg)
+			}
+			var parsed compressedCertificateMsg
+			if !parsed.unmarshal(mustMarshal(t, compressed)) {
+				t.Fatal("failed to parse CompressedCertificate message")
+			}
+			got, err := c.decompressCertificate(&parsed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.certificate.Certificate, certMsg.certificate.Certificate) {
+				t.Error("decompressed certificate chain does not match")
+			}
+
+			// The declared length must match exactly.
+			parsed.uncompressedLength++
+			if _, err := c.decompressCertificate(&parsed); err == nil {
+				t.Error("decompression with wrong length succeeded")
+			}
+
+			// The peer must not use algorithms that were not advertised.
+			c = Client(&discardConn{}, &Config{})
+			if _, err := c.decompressCertificate(compressed); err == nil {
+				t.Error("decompression with an algorithm that was not advertised succeeded")
+			}
+			if msg, err := c.compressCertificate(certMsg, peer); err != nil || msg != certMsg {
+				t.Errorf("certificate compressed without a mutual algorithm")
+			}
+		})
+	}
+}
+
+func TestExternalPSK(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	psk := ExternalPSK{Identity: []byte("device-1"), Key: key}
+	other := ExternalPSK{Identity: []byte("device-2"), Key: bytes.Repeat([]byte{0x43}, 32)}
+
+	newConfigs := func() (clientConfig, serverConfig *Config) {
+		serverConfig = testConfigServer.Clone()
+		serverConfig.Certificates = nil
+		clientConfig = testConfigClient.Clone()
+		clientConfig.ServerName = ""
+		return clientConfig, serverConfig
+	}
+
+	for _, tt := range []struct {
+		name           string
+		client, server []ExternalPSK
+		curves         []CurveID
+	}{
+		{"SHA256", []ExternalPSK{psk}, []ExternalPSK{other, psk}, nil},
+		{"SHA384", []ExternalPSK{{Identity: psk.Identity, Key: key, Hash: crypto.SHA384}},
+			[]ExternalPSK{{Identity: psk.Identity, Key: key, Hash: crypto.SHA384}}, nil},
+		{"Imported", []ExternalPSK{{Identity: psk.Identity, Key: key, Import: true, Context: []byte("ctx")}},
+			[]ExternalPSK{{Identity: psk.Identity, Key: key, Import: true, Context: []byte("ctx")}}, nil},
+		{"SecondOffered", []ExternalPSK{other, psk}, []ExternalPSK{psk}, nil},
+		{"HelloRetryRequest", []ExternalPSK{psk}, []ExternalPSK{psk}, []CurveID{CurveP384}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConfig, serverConfig := newConfigs()
+			clientConfig.ExternalPSKs = tt.client
+			clientConfig.ClientSessionCache = NewLRUClientSessionCache(1)
+			serverConfig.ExternalPSKs = tt.server
+			if tt.curves != nil {
+				serverConfig.CurvePreferences = tt.curves
+			}
+			var verified bool
+			serverConfig.VerifyConnection = func(cs ConnectionState) error {
+				verified = bytes.Equal(cs.ExternalPSKIdentity, psk.Identity)
+				return nil
+			}
+			ss, cs, err := testHandshake(t, clientConfig, serverConfig)
+			if err != nil {
+				t.Fatalf("handshake failed: %v", err)
+			}
+			if !bytes.Equal(ss.ExternalPSKIdentity, psk.Identity) || !bytes.Equal(cs.ExternalPSKIdentity, psk.Identity) {
+				t.Errorf("ExternalPSKIdentity = %q (server), %q (client), want %q",
+					ss.ExternalPSKIdentity, cs.ExternalPSKIdentity, psk.Identity)
+			}
+			if !verified {
+				t.Error("VerifyConnection was not called with the external PSK identity")
+			}
+			if cs.DidResume || len(cs.PeerCertificates) != 0 || len(ss.PeerCertificates) != 0 {
+				t.Error("unexpected resumption or certificates")
+			}
+			if tt.curves != nil && !cs.HelloRetryRequest {
+				t.Error("expected a HelloRetryRequest")
+			}
+			if _, ok := clientConfig.ClientSessionCache.Get(clientConfig.ServerName); ok {
+				t.Error("session ticket stored for an external PSK connection")
+			}
+		})
+	}
+
+	t.Run("GetExternalPSK", func(t *testing.T) {
+		clientConfig, serverConfig := newConfigs()
+		clientConfig.ExternalPSKs = []ExternalPSK{other, psk}
+		serverConfig.GetExternalPSK = func(info *ClientHelloInfo, identity []byte) (*ExternalPSK, error) {
+			if bytes.Equal(identity, psk.Identity) {
+				return &psk, nil
+			}
+			return nil, nil
+		}
+		ss, _, err := testHandshake(t, clientConfig, serverConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if !bytes.Equal(ss.ExternalPSKIdentity, psk.Identity) {
+			t.Errorf("ExternalPSKIdentity = %q, want %q", ss.ExternalPSKIdentity, psk.Identity)
+		}
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		clientConfig, serverConfig := newConfigs()
+		clientConfig.ExternalPSKs = []ExternalPSK{psk}
+		serverConfig.ExternalPSKs = []ExternalPSK{{Identity: psk.Identity, Key: other.Key}}
+		_, _, err := testHandshake(t, clientConfig, serverConfig)
+		if err == nil || !strings.Contains(err.Error(), "invalid PSK binder") {
+			t.Fatalf("expected a binder error, got %v", err)
+		}
+	})
+
+	t.Run("ImportMismatch", func(t *testing.T) {
+		clientConfig, serverConfig := newConfigs()
+		clientConfig.ExternalPSKs = []ExternalPSK{{Identity: psk.Identity, Key: key, Import: true}}
+		serverConfig.ExternalPSKs = []ExternalPSK{psk}
+		if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+			t.Fatal("handshake succeeded without a mutual PSK or certificate")
+		}
+	})
+
+	t.Run("CertificateFallback", func(t *testing.T) {
+		serverConfig := testConfigServer.Clone()
+		serverConfig.ExternalPSKs = []ExternalPSK{other}
+		clientConfig := testConfigClient.Clone()
+		clientConfig.ExternalPSKs = []ExternalPSK{psk}
+		ss, cs, err := testHandshake(t, clientConfig, serverConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if ss.ExternalPSKIdentity != nil || cs.ExternalPSKIdentity != nil || len(cs.PeerCertificates) == 0 {
+			t.Error("expected certificate authentication")
+		}
+
+		// Without a ServerName, the client can't authenticate the server
+		// certificate.
+		clientConfig.ServerName = ""
+		_, _, err = testHandshake(t, clientConfig, serverConfig)
+		if err == nil || !strings.Contains(err.Error(), "did not select an external PSK") {
+			t.Fatalf("expected an error without ServerName, got %v", err)
+		}
+	})
+}
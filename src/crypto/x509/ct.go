@@ -0,0 +1,386 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// This file implements verification of the Certificate Transparency signed
+// certificate timestamps (SCTs) specified in RFC 6962.
+
+var (
+	oidExtensionSCTList     = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+	oidExtensionOCSPSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 5}
+)
+
+// SignedCertificateTimestamp is a version 1 Certificate Transparency signed
+// certificate timestamp (SCT), as specified by RFC 6962, Section 3.2. It is a
+// promise by a log to include a certificate in its public record.
+type SignedCertificateTimestamp struct {
+	// Raw contains the complete serialized SCT.
+	Raw []byte
+
+	// LogID is the SHA-256 hash of the DER encoded public key of the log.
+	LogID [sha256.Size]byte
+	// Timestamp is the time at which the log issued the SCT, with
+	// millisecond precision.
+	Timestamp time.Time
+	// Extensions contains the raw CtExtensions of the SCT.
+	Extensions []byte
+
+	// SignatureAlgorithm is ECDSAWithSHA256 or SHA256WithRSA, the only
+	// algorithms allowed by RFC 6962, or UnknownSignatureAlgorithm.
+	SignatureAlgorithm SignatureAlgorithm
+	Signature          []byte
+}
+
+// TLS SignatureAndHashAlgorithm values, from RFC 5246, Section 7.4.1.4.1.
+const (
+	sctHashSHA256 = 4
+	sctSigRSA     = 1
+	sctSigECDSA   = 3
+)
+
+// ParseSignedCertificateTimestamp parses a single serialized SCT, such as
+// one of ConnectionState.SignedCertificateTimestamps in crypto/tls.
+func ParseSignedCertificateTimestamp(b []byte) (*SignedCertificateTimestamp, error) {
+	s := cryptobyte.String(b)
+	sct := &SignedCertificateTimestamp{Raw: b}
+	var version, hash, sig uint8
+	var timestamp uint64
+	var extensions, signature cryptobyte.String
+	if !s.ReadUint8(&version) {
+		return nil, errors.New("x509: malformed SCT")
+	}
+	if version != 0 {
+		return nil, fmt.Errorf("x509: unsupported SCT version %d", version+1)
+	}
+	if !s.CopyBytes(sct.LogID[:]) || !s.ReadUint64(&timestamp) ||
+		!s.ReadUint16LengthPrefixed(&extensions) ||
+		!s.ReadUint8(&hash) || !s.ReadUint8(&sig) ||
+		!s.ReadUint16LengthPrefixed(&signature) || !s.Empty() {
+		return nil, errors.New("x509: malformed SCT")
+	}
+	if timestamp > math.MaxInt64 {
+		return nil, errors.New("x509: malformed SCT timestamp")
+	}
+	sct.Timestamp = time.UnixMilli(int64(timestamp))
+	sct.Extensions = extensions
+	sct.Signature = signature
+	switch {
+	case hash == sctHashSHA256 && sig == sctSigECDSA:
+		sct.SignatureAlgorithm = ECDSAWithSHA256
+	case hash == sctHashSHA256 && sig == sctSigRSA:
+		sct.SignatureAlgorithm = SHA256WithRSA
+	}
+	return sct, nil
+}
+
+// ParseSignedCertificateTimestampList parses a serialized
+// SignedCertificateTimestampList, as carried by the TLS extension, the X.509
+// extension and the OCSP extension defined in RFC 6962, Section 3.3. SCTs with
+// a version other than 1 are skipped, as required by RFC 6962.
+func ParseSignedCertificateTimestampList(b []byte) ([]*SignedCertificateTimestamp, error) {
+	s := cryptobyte.String(b)
+	var list cryptobyte.String
+	if !s.ReadUint16LengthPrefixed(&list) || !s.Empty() {
+		return nil, errors.New("x509: malformed SCT list")
+	}
+	var scts []*SignedCertificateTimestamp
+	for !list.Empty() {
+		var sctBytes cryptobyte.String
+		if !list.ReadUint16LengthPrefixed(&sctBytes) || len(sctBytes) == 0 {
+			return nil, errors.New("x509: malformed SCT list")
+		}
+		if sctBytes[0] != 0 {
+			continue
+		}
+		sct, err := ParseSignedCertificateTimestamp(sctBytes)
+		if err != nil {
+			return nil, err
+		}
+		scts = append(scts, sct)
+	}
+	return scts, nil
+}
+
+// parseSCTListExtension parses the value of an X.509 or OCSP SCT list
+// extension, which wraps the serialized list in an OCTET STRING.
+func parseSCTListExtension(value []byte) ([]*SignedCertificateTimestamp, error) {
+	s := cryptobyte.String(value)
+	var list cryptobyte.String
+	if !s.ReadASN1(&list, cryptobyte_asn1.OCTET_STRING) || !s.Empty() {
+		return nil, errors.New("x509: malformed SCT list extension")
+	}
+	return ParseSignedCertificateTimestampList(list)
+}
+
+// SignedCertificateTimestamps returns the SCTs embedded in c, or nil if c
+// doesn't have the SCT list extension.
+//
+// Embedded SCTs are issued for the precertificate corresponding to c, so
+// verifying them requires the issuer of c, see
+// [SignedCertificateTimestamp.CheckSignature].
+func (c *Certificate) SignedCertificateTimestamps() ([]*SignedCertificateTimestamp, error) {
+	for _, ext := range c.Extensions {
+		if ext.Id.Equal(oidExtensionSCTList) {
+			return parseSCTListExtension(ext.Value)
+		}
+	}
+	return nil, nil
+}
+
+// SignedCertificateTimestamps returns the SCTs included in resp, or nil if
+// resp doesn't have the SCT list single extension.
+func (resp *OCSPResponse) SignedCertificateTimestamps() ([]*SignedCertificateTimestamp, error) {
+	for _, ext := range resp.SingleExtensions {
+		if ext.Id.Equal(oidExtensionOCSPSCTList) {
+			return parseSCTListExtension(ext.Value)
+		}
+	}
+	return nil, nil
+}
+
+// CheckSignature verifies that sct is a valid SCT for cert signed by the log
+// with public key pub.
+//
+// If issuer is nil, sct must have been issued for cert itself, as is the case
+// for SCTs delivered with the TLS extension or in an OCSP response. Otherwise,
+// sct must have been issued for the precertificate corresponding to cert,
+// issued by issuer, as is the case for SCTs embedded in cert.
+func (sct *SignedCertificateTimestamp) CheckSignature(pub crypto.PublicKey, cert, issuer *Certificate) error {
+	if sct.SignatureAlgorithm == UnknownSignatureAlgorithm {
+		return ErrUnsupportedAlgorithm
+	}
+	signed, err := sct.signedData(cert, issuer)
+	if err != nil {
+		return err
+	}
+	return checkSignature(sct.SignatureAlgorithm, signed, sct.Signature, pub, false)
+}
+
+// signedData returns the digitally-signed structure of sct, as specified by
+// RFC 6962, Section 3.2.
+func (sct *SignedCertificateTimestamp) signedData(cert, issuer *Certificate) ([]byte, error) {
+	var b cryptobyte.Builder
+	b.AddUint8(0) // v1
+	b.AddUint8(0) // certificate_timestamp
+	b.AddUint64(uint64(sct.Timestamp.UnixMilli()))
+	if issuer == nil {
+		b.AddUint16(0) // x509_entry
+		b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes(cert.Raw)
+		})
+	} else {
+		spki := issuer.RawSubjectPublicKeyInfo
+		if len(spki) == 0 {
+			var err error
+			if spki, err = MarshalPKIXPublicKey(issuer.PublicKey); err != nil {
+				return nil, err
+			}
+		}
+		issuerKeyHash := sha256.Sum256(spki)
+		tbs, err := precertificateTBS(cert.RawTBSCertificate)
+		if err != nil {
+			return nil, err
+		}
+		b.AddUint16(1) // precert_entry
+		b.AddBytes(issuerKeyHash[:])
+		b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes(tbs)
+		})
+	}
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(sct.Extensions)
+	})
+	return b.Bytes()
+}
+
+// precertificateTBS returns the TBSCertificate of the precertificate
+// corresponding to a certificate with the given TBSCertificate, which is the
+// same with the SCT list extension removed, as specified by RFC 6962,
+// Section 3.1.
+func precertificateTBS(rawTBS []byte) ([]byte, error) {
+	input := cryptobyte.String(rawTBS)
+	var tbs cryptobyte.String
+	if !input.ReadASN1(&tbs, cryptobyte_asn1.SEQUENCE) || !input.Empty() {
+		return nil, errors.New("x509: malformed tbs certificate")
+	}
+	extensionsTag := cryptobyte_asn1.Tag(3).Constructed().ContextSpecific()
+
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		for !tbs.Empty() {
+			var element cryptobyte.String
+			var tag cryptobyte_asn1.Tag
+			if !tbs.ReadAnyASN1Element(&element, &tag) {
+				b.SetError(errors.New("x509: malformed tbs certificate"))
+				return
+			}
+			if tag != extensionsTag {
+				b.AddBytes(element)
+				continue
+			}
+			var extensions cryptobyte.String
+			if !element.ReadASN1(&element, extensionsTag) ||
+				!element.ReadASN1(&extensions, cryptobyte_asn1.SEQUENCE) {
+				b.SetError(errors.New("x509: malformed extensions"))
+				return
+			}
+			var kept [][]byte
+			for !extensions.Empty() {
+				var ext cryptobyte.String
+				var oid asn1.ObjectIdentifier
+				if !extensions.ReadASN1Element(&ext, cryptobyte_asn1.SEQUENCE) {
+					b.SetError(errors.New("x509: malformed extension"))
+					return
+				}
+				if contents := ext; !contents.ReadASN1(&contents, cryptobyte_asn1.SEQUENCE) ||
+					!contents.ReadASN1ObjectIdentifier(&oid) {
+					b.SetError(errors.New("x509: malformed extension OID field"))
+					return
+				}
+				if !oid.Equal(oidExtensionSCTList) {
+					kept = append(kept, ext)
+				}
+			}
+			if len(kept) == 0 {
+				continue
+			}
+			b.AddASN1(extensionsTag, func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					for _, ext := range kept {
+						b.AddBytes(ext)
+					}
+				})
+			})
+		}
+	})
+	return b.Bytes()
+}
+
+// CTLog is a Certificate Transparency log trusted by a [CTPolicy].
+type CTLog struct {
+	// PublicKey is the public key of the log, an *ecdsa.PublicKey or an
+	// *rsa.PublicKey.
+	PublicKey crypto.PublicKey
+
+	// Operator is the name of the organization operating the log. Logs with
+	// an empty Operator are each considered to be operated by a different
+	// organization.
+	Operator string
+}
+
+// CTPolicy is a Certificate Transparency policy, enforced by
+// [Certificate.Verify] when set in VerifyOptions.CertificateTransparency.
+//
+// A chain satisfies the policy if its leaf certificate comes with enough valid
+// SCTs, issued by the logs in Logs no later than VerifyOptions.CurrentTime.
+// SCTs are taken from the SCT list extension of the leaf, and from
+// VerifyOptions.SignedCertificateTimestamps.
+type CTPolicy struct {
+	// Logs are the trusted logs. SCTs from other logs are ignored.
+	Logs []*CTLog
+
+	// MinSCTs is the minimum number of valid SCTs, each from a different
+	// log. If zero, at least one SCT is required.
+	MinSCTs int
+
+	// MinOperators is the minimum number of different operators among the
+	// logs that issued the valid SCTs. For example, a policy requiring two
+	// SCTs from distinct operators sets MinSCTs and MinOperators to 2.
+	MinOperators int
+}
+
+// checkCertificateTransparency discards the chains that don't satisfy
+// opts.CertificateTransparency. If no chains are left, it returns the error
+// that caused the first one to be discarded.
+func checkCertificateTransparency(chains [][]*Certificate, opts *VerifyOptions) ([][]*Certificate, error) {
+	policy := opts.CertificateTransparency
+	if policy == nil || len(chains) == 0 {
+		return chains, nil
+	}
+	now := opts.CurrentTime
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	type logInfo struct {
+		log      *CTLog
+		operator string
+	}
+	logs := make(map[[sha256.Size]byte]logInfo)
+	for _, log := range policy.Logs {
+		spki, err := MarshalPKIXPublicKey(log.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("x509: invalid CT log public key: %w", err)
+		}
+		id := sha256.Sum256(spki)
+		operator := log.Operator
+		if operator == "" {
+			operator = string(id[:])
+		}
+		logs[id] = logInfo{log, operator}
+	}
+
+	leaf := chains[0][0]
+	var delivered []*SignedCertificateTimestamp
+	for _, b := range opts.SignedCertificateTimestamps {
+		if sct, err := ParseSignedCertificateTimestamp(b); err == nil {
+			delivered = append(delivered, sct)
+		}
+	}
+	// A malformed extension is equivalent to no embedded SCTs.
+	embedded, _ := leaf.SignedCertificateTimestamps()
+
+	minSCTs := max(policy.MinSCTs, 1)
+	var firstErr error
+	chains = slices.DeleteFunc(chains, func(chain []*Certificate) bool {
+		validLogs := make(map[[sha256.Size]byte]bool)
+		operators := make(map[string]bool)
+		check := func(sct *SignedCertificateTimestamp, issuer *Certificate) {
+			info, ok := logs[sct.LogID]
+			if !ok || validLogs[sct.LogID] || sct.Timestamp.After(now) {
+				return
+			}
+			if sct.CheckSignature(info.log.PublicKey, leaf, issuer) != nil {
+				return
+			}
+			validLogs[sct.LogID] = true
+			operators[info.operator] = true
+		}
+		for _, sct := range delivered {
+			check(sct, nil)
+		}
+		if len(chain) > 1 {
+			for _, sct := range embedded {
+				check(sct, chain[1])
+			}
+		}
+		if len(validLogs) >= minSCTs && len(operators) >= policy.MinOperators {
+			return false
+		}
+		if firstErr == nil {
+			firstErr = CertificateInvalidError{leaf, InsufficientSCTs,
+				fmt.Sprintf("%d valid SCTs from %d log operators", len(validLogs), len(operators))}
+		}
+		return true
+	})
+	if len(chains) == 0 {
+		return nil, firstErr
+	}
+	return chains, nil
+}
@@ -0,0 +1,327 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// argon2023PublicKey is the public key of Google's Argon2023 log, which
+// issued one of the SCTs embedded in googleLeaf.
+const argon2023PublicKey = "MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE0JCPZFJOQqyEti5M8j13ALN3CAVHqkVM4yyOcKWCu2yye5yYeqDpEXYoALIgtM3TmHtNlifmt+4iatGwLpF3eA=="
+
+func TestEmbeddedSCTs(t *testing.T) {
+	leaf, err := certificateFromPEM(googleLeaf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer, err := certificateFromPEM(gtsIntermediate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scts, err := leaf.SignedCertificateTimestamps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scts) != 2 {
+		t.Fatalf("got %d SCTs, want 2", len(scts))
+	}
+
+	der, err := base64.StdEncoding.DecodeString(argon2023PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePKIXPublicKey(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var argon *SignedCertificateTimestamp
+	for _, sct := range scts {
+		if sct.LogID == sha256.Sum256(der) {
+			argon = sct
+		}
+	}
+	if argon == nil {
+		t.Fatal("no SCT from Argon2023")
+	}
+	if want := time.Date(2023, 1, 2, 9, 19, 20, 52*1e6, time.UTC); !argon.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", argon.Timestamp, want)
+	}
+	if argon.SignatureAlgorithm != ECDSAWithSHA256 {
+		t.Errorf("SignatureAlgorithm = %v, want %v", argon.SignatureAlgorithm, ECDSAWithSHA256)
+	}
+	if err := argon.CheckSignature(pub, leaf, issuer); err != nil {
+		t.Errorf("CheckSignature failed: %v", err)
+	}
+	if err := argon.CheckSignature(pub, leaf, nil); err == nil {
+		t.Errorf("CheckSignature succeeded for an embedded SCT without issuer")
+	}
+	if err := argon.CheckSignature(pub, leaf, leaf); err == nil {
+		t.Errorf("CheckSignature succeeded with the wrong issuer")
+	}
+
+	for _, c := range []string{gtsIntermediate, gtsRoot} {
+		cert, err := certificateFromPEM(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scts, err := cert.SignedCertificateTimestamps(); err != nil || scts != nil {
+			t.Errorf("SignedCertificateTimestamps() = %v, %v; want nil, nil", scts, err)
+		}
+	}
+}
+
+type ctTestLog struct {
+	key *ecdsa.PrivateKey
+	id  [sha256.Size]byte
+}
+
+func newCTTestLog(t *testing.T) *ctTestLog {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spki, err := MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ctTestLog{key: key, id: sha256.Sum256(spki)}
+}
+
+// sign returns a serialized SCT for cert, or for the precertificate
+// corresponding to cert if issuer is not nil.
+func (l *ctTestLog) sign(t *testing.T, timestamp time.Time, cert, issuer *Certificate) []byte {
+	t.Helper()
+	sct := &SignedCertificateTimestamp{LogID: l.id, Timestamp: timestamp}
+	signed, err := sct.signedData(cert, issuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.Sum256(signed)
+	sig, err := ecdsa.SignASN1(rand.Reader, l.key, h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b cryptobyte.Builder
+	b.AddUint8(0)
+	b.AddBytes(l.id[:])
+	b.AddUint64(uint64(timestamp.UnixMilli()))
+	b.AddUint16(0)
+	b.AddUint8(sctHashSHA256)
+	b.AddUint8(sctSigECDSA)
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) { b.AddBytes(sig) })
+	return b.BytesOrPanic()
+}
+
+func sctListExtension(t *testing.T, id asn1.ObjectIdentifier, scts ...[]byte) pkix.Extension {
+	t.Helper()
+	var b cryptobyte.Builder
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		for _, sct := range scts {
+			b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) { b.AddBytes(sct) })
+		}
+	})
+	value, err := asn1.Marshal(b.BytesOrPanic())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkix.Extension{Id: id, Value: value}
+}
+
+func TestVerifyCertificateTransparency(t *testing.T) {
+	p := newOCSPTestPKI(t)
+	roots := NewCertPool()
+	roots.AddCert(p.root)
+	intermediates := NewCertPool()
+	intermediates.AddCert(p.intermediate)
+
+	logA1, logA2, logB := newCTTestLog(t), newCTTestLog(t), newCTTestLog(t)
+	ctLogs := []*CTLog{
+		{PublicKey: logA1.key.Public(), Operator: "A"},
+		{PublicKey: logA2.key.Public(), Operator: "A"},
+		{PublicKey: logB.key.Public(), Operator: "B"},
+	}
+	untrustedLog := newCTTestLog(t)
+
+	// Issue a certificate with embedded SCTs from logA1 and logB, signed over
+	// the precertificate, which is the same certificate without them.
+	now := time.Now()
+	template := &Certificate{
+		SerialNumber: big.NewInt(100),
+		Subject:      pkix.Name{CommonName: "CT Test Leaf"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		DNSNames:     []string{"example.com"},
+		ExtKeyUsage:  []ExtKeyUsage{ExtKeyUsageServerAuth},
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := func(template *Certificate) *Certificate {
+		der, err := CreateCertificate(rand.Reader, template, p.intermediate, leafKey.Public(), p.intermediateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+	plain := issue(template)
+	sctTime := now.Add(-time.Minute)
+	template.ExtraExtensions = []pkix.Extension{sctListExtension(t, oidExtensionSCTList,
+		logA1.sign(t, sctTime, plain, p.intermediate),
+		logB.sign(t, sctTime, plain, p.intermediate))}
+	embedded := issue(template)
+
+	tbs, err := precertificateTBS(embedded.RawTBSCertificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tbs, plain.RawTBSCertificate) {
+		t.Fatal("precertificateTBS didn't remove the SCT list extension")
+	}
+
+	// SCTs delivered out of band are issued for the final certificate.
+	deliveredA2 := logA2.sign(t, sctTime, plain, nil)
+	deliveredB := logB.sign(t, sctTime, plain, nil)
+	deliveredUntrusted := untrustedLog.sign(t, sctTime, plain, nil)
+	deliveredFuture := logA2.sign(t, now.Add(time.Hour), plain, nil)
+	deliveredForPrecert := logA2.sign(t, sctTime, plain, p.intermediate)
+	deliveredForEmbedded := logA2.sign(t, sctTime, embedded, nil)
+
+	twoOperators := &CTPolicy{Logs: ctLogs, MinSCTs: 2, MinOperators: 2}
+	for _, tt := range []struct {
+		name      string
+		leaf      *Certificate
+		policy    *CTPolicy
+		delivered [][]byte
+		wantErr   bool
+	}{
+		{"no policy", plain, nil, nil, false},
+		{"no SCTs", plain, &CTPolicy{Logs: ctLogs}, nil, true},
+		{"embedded", embedded, &CTPolicy{Logs: ctLogs}, nil, false},
+		{"embedded/two operators", embedded, twoOperators, nil, false},
+		{"embedded/three SCTs", embedded, &CTPolicy{Logs: ctLogs, MinSCTs: 3}, nil, true},
+		{"embedded/untrusted logs", embedded, &CTPolicy{Logs: ctLogs[1:2]}, nil, true},
+		{"delivered", plain, &CTPolicy{Logs: ctLogs}, [][]byte{deliveredA2}, false},
+		{"delivered/two operators", plain, twoOperators, [][]byte{deliveredA2, deliveredB}, false},
+		{"delivered/same operator", plain, &CTPolicy{Logs: ctLogs, MinOperators: 2}, [][]byte{deliveredA2}, true},
+		{"delivered/untrusted log", plain, &CTPolicy{Logs: ctLogs}, [][]byte{deliveredUntrusted}, true},
+		{"delivered/future", plain, &CTPolicy{Logs: ctLogs}, [][]byte{deliveredFuture}, true},
+		{"delivered/for precertificate", plain, &CTPolicy{Logs: ctLogs}, [][]byte{deliveredForPrecert}, true},
+		{"delivered/garbage", plain, &CTPolicy{Logs: ctLogs}, [][]byte{[]byte("garbage")}, true},
+		{"delivered/duplicate log", plain, &CTPolicy{Logs: ctLogs, MinSCTs: 2}, [][]byte{deliveredB, deliveredB}, true},
+		{"embedded and delivered", embedded, &CTPolicy{Logs: ctLogs, MinSCTs: 3}, [][]byte{deliveredForEmbedded}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.leaf.Verify(VerifyOptions{
+				Roots:                       roots,
+				Intermediates:               intermediates,
+				CurrentTime:                 now,
+				CertificateTransparency:     tt.policy,
+				SignedCertificateTimestamps: tt.delivered,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Verify failed: %v", err)
+				}
+				return
+			}
+			var invalidErr CertificateInvalidError
+			if !errors.As(err, &invalidErr) || invalidErr.Reason != InsufficientSCTs {
+				t.Fatalf("Verify error = %v, want InsufficientSCTs", err)
+			}
+		})
+	}
+
+	t.Run("ocsp", func(t *testing.T) {
+		der := p.response(t, &OCSPResponse{
+			Status: OCSPGood,
+			ExtraSingleExtensions: []pkix.Extension{sctListExtension(t, oidExtensionOCSPSCTList,
+				deliveredA2, deliveredB)},
+		})
+		resp, err := ParseOCSPResponse(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scts, err := resp.SignedCertificateTimestamps()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var delivered [][]byte
+		for _, sct := range scts {
+			delivered = append(delivered, sct.Raw)
+		}
+		if _, err := plain.Verify(VerifyOptions{
+			Roots:                       roots,
+			Intermediates:               intermediates,
+			CurrentTime:                 now,
+			CertificateTransparency:     twoOperators,
+			SignedCertificateTimestamps: delivered,
+		}); err != nil {
+			t.Errorf("Verify failed: %v", err)
+		}
+	})
+}
+
+func TestParseSignedCertificateTimestampList(t *testing.T) {
+	log := newCTTestLog(t)
+	p := newOCSPTestPKI(t)
+	sct := log.sign(t, time.Now(), p.leaf, nil)
+	v2 := append([]byte{1}, sct[1:]...)
+
+	var b cryptobyte.Builder
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		for _, s := range [][]byte{sct, v2, sct} {
+			b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) { b.AddBytes(s) })
+		}
+	})
+	list := b.BytesOrPanic()
+
+	scts, err := ParseSignedCertificateTimestampList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scts) != 2 {
+		t.Fatalf("got %d SCTs, want 2", len(scts))
+	}
+	for _, s := range scts {
+		if !bytes.Equal(s.Raw, sct) || s.LogID != log.id {
+			t.Errorf("unexpected SCT %+v", s)
+		}
+		if err := s.CheckSignature(log.key.Public(), p.leaf, nil); err != nil {
+			t.Errorf("CheckSignature failed: %v", err)
+		}
+	}
+
+	if _, err := ParseSignedCertificateTimestamp(v2); err == nil {
+		t.Error("ParseSignedCertificateTimestamp succeeded for a v2 SCT")
+	}
+	for i := range list {
+		if _, err := ParseSignedCertificateTimestampList(list[:i]); err == nil {
+			t.Fatalf("ParseSignedCertificateTimestampList succeeded on a list truncated to %d bytes", i)
+		}
+	}
+	if _, err := ParseSignedCertificateTimestamp(append(sct, 0)); err == nil {
+		t.Error("ParseSignedCertificateTimestamp succeeded with trailing data")
+	}
+}
@@ -0,0 +1,859 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// This file implements the Online Certificate Status Protocol (OCSP) messages
+// specified in RFC 6960. Only the basic response type is supported, and
+// requests and responses are limited to a single certificate.
+
+var (
+	oidSHA1      = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+)
+
+var ocspHashes = []struct {
+	hash crypto.Hash
+	oid  asn1.ObjectIdentifier
+}{
+	{crypto.SHA1, oidSHA1},
+	{crypto.SHA256, oidSHA256},
+	{crypto.SHA384, oidSHA384},
+	{crypto.SHA512, oidSHA512},
+}
+
+// OCSPStatus is the status of a certificate, as reported by an OCSP responder.
+type OCSPStatus int
+
+const (
+	// OCSPGood indicates that the certificate is not revoked.
+	OCSPGood OCSPStatus = iota
+	// OCSPRevoked indicates that the certificate has been revoked.
+	OCSPRevoked
+	// OCSPUnknown indicates that the responder doesn't know about the
+	// certificate.
+	OCSPUnknown
+)
+
+func (s OCSPStatus) String() string {
+	switch s {
+	case OCSPGood:
+		return "good"
+	case OCSPRevoked:
+		return "revoked"
+	case OCSPUnknown:
+		return "unknown"
+	}
+	return "OCSPStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// OCSPResponseStatus is the status of an OCSP response, as defined in RFC 6960,
+// Section 4.2.1. Only successful responses carry certificate status
+// information.
+type OCSPResponseStatus int
+
+const (
+	OCSPSuccessful        OCSPResponseStatus = 0
+	OCSPMalformedRequest  OCSPResponseStatus = 1
+	OCSPInternalError     OCSPResponseStatus = 2
+	OCSPTryLater          OCSPResponseStatus = 3
+	OCSPSignatureRequired OCSPResponseStatus = 5
+	OCSPUnauthorized      OCSPResponseStatus = 6
+)
+
+func (s OCSPResponseStatus) String() string {
+	switch s {
+	case OCSPSuccessful:
+		return "successful"
+	case OCSPMalformedRequest:
+		return "malformed request"
+	case OCSPInternalError:
+		return "internal error"
+	case OCSPTryLater:
+		return "try later"
+	case OCSPSignatureRequired:
+		return "signature required"
+	case OCSPUnauthorized:
+		return "unauthorized"
+	}
+	return "OCSPResponseStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// OCSPResponseError is returned by [ParseOCSPResponse] when the responder
+// returned an error status instead of a certificate status.
+type OCSPResponseError struct {
+	Status OCSPResponseStatus
+}
+
+func (e OCSPResponseError) Error() string {
+	return "x509: OCSP responder returned error status: " + e.Status.String()
+}
+
+// OCSPRequest represents an OCSP request for the status of a single
+// certificate, as specified by RFC 6960, Section 4.1.
+type OCSPRequest struct {
+	// Raw contains the complete ASN.1 DER content of the request. It is set
+	// when parsing a request.
+	Raw []byte
+
+	// HashAlgorithm is the hash function used to compute IssuerNameHash and
+	// IssuerKeyHash.
+	HashAlgorithm crypto.Hash
+	// IssuerNameHash is the hash of the DER encoded subject of the issuer.
+	IssuerNameHash []byte
+	// IssuerKeyHash is the hash of the subject public key of the issuer.
+	IssuerKeyHash []byte
+	// SerialNumber is the serial number of the certificate being checked.
+	SerialNumber *big.Int
+}
+
+// ocspCertID is the CertID structure, which identifies a certificate by its
+// serial number and by hashes of the name and key of its issuer.
+type ocspCertID struct {
+	hash           crypto.Hash
+	issuerNameHash []byte
+	issuerKeyHash  []byte
+	serialNumber   *big.Int
+}
+
+// newOCSPCertID returns the CertID of the certificate with the given serial
+// number issued by issuer. If hash is zero, SHA-1 is used.
+func newOCSPCertID(hash crypto.Hash, serial *big.Int, issuer *Certificate) (*ocspCertID, error) {
+	if hash == 0 {
+		hash = crypto.SHA1
+	}
+	if ocspHashOID(hash) == nil {
+		return nil, fmt.Errorf("x509: unsupported OCSP hash function %v", hash)
+	}
+	issuerSubject, err := subjectBytes(issuer)
+	if err != nil {
+		return nil, err
+	}
+	issuerKey, err := subjectPublicKeyBytes(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write(issuerSubject)
+	nameHash := h.Sum(nil)
+	h.Reset()
+	h.Write(issuerKey)
+	keyHash := h.Sum(nil)
+
+	return &ocspCertID{
+		hash:           hash,
+		issuerNameHash: nameHash,
+		issuerKeyHash:  keyHash,
+		serialNumber:   serial,
+	}, nil
+}
+
+// matches reports whether id identifies the certificate with the given
+// serial number issued by issuer.
+func (id *ocspCertID) matches(serial *big.Int, issuer *Certificate) bool {
+	if id.serialNumber.Cmp(serial) != 0 {
+		return false
+	}
+	other, err := newOCSPCertID(id.hash, serial, issuer)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(id.issuerNameHash, other.issuerNameHash) &&
+		bytes.Equal(id.issuerKeyHash, other.issuerKeyHash)
+}
+
+// subjectPublicKeyBytes returns the contents of the subjectPublicKey BIT
+// STRING of cert, which is what OCSP and key identifiers hash.
+func subjectPublicKeyBytes(cert *Certificate) ([]byte, error) {
+	spkiBytes := cert.RawSubjectPublicKeyInfo
+	if len(spkiBytes) == 0 {
+		var err error
+		spkiBytes, err = MarshalPKIXPublicKey(cert.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	spki := cryptobyte.String(spkiBytes)
+	var key asn1.BitString
+	if !spki.ReadASN1(&spki, cryptobyte_asn1.SEQUENCE) ||
+		!spki.SkipASN1(cryptobyte_asn1.SEQUENCE) ||
+		!spki.ReadASN1BitString(&key) {
+		return nil, errors.New("x509: malformed subject public key info")
+	}
+	return key.Bytes, nil
+}
+
+func ocspHashOID(hash crypto.Hash) asn1.ObjectIdentifier {
+	for _, h := range ocspHashes {
+		if h.hash == hash {
+			return h.oid
+		}
+	}
+	return nil
+}
+
+func (id *ocspCertID) marshal(b *cryptobyte.Builder) {
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(ocspHashOID(id.hash))
+			b.AddASN1NULL()
+		})
+		b.AddASN1OctetString(id.issuerNameHash)
+		b.AddASN1OctetString(id.issuerKeyHash)
+		b.AddASN1BigInt(id.serialNumber)
+	})
+}
+
+func parseOCSPCertID(der *cryptobyte.String) (*ocspCertID, error) {
+	var certID, hashAI cryptobyte.String
+	if !der.ReadASN1(&certID, cryptobyte_asn1.SEQUENCE) ||
+		!certID.ReadASN1(&hashAI, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed OCSP CertID")
+	}
+	ai, err := parseAI(hashAI)
+	if err != nil {
+		return nil, err
+	}
+	id := &ocspCertID{serialNumber: new(big.Int)}
+	for _, h := range ocspHashes {
+		if ai.Algorithm.Equal(h.oid) {
+			id.hash = h.hash
+		}
+	}
+	if id.hash == 0 {
+		return nil, fmt.Errorf("x509: unsupported OCSP hash algorithm %v", ai.Algorithm)
+	}
+	if !certID.ReadASN1Bytes(&id.issuerNameHash, cryptobyte_asn1.OCTET_STRING) ||
+		!certID.ReadASN1Bytes(&id.issuerKeyHash, cryptobyte_asn1.OCTET_STRING) ||
+		!certID.ReadASN1Integer(id.serialNumber) || !certID.Empty() {
+		return nil, errors.New("x509: malformed OCSP CertID")
+	}
+	return id, nil
+}
+
+// CreateOCSPRequest returns a DER encoded OCSP request for the status of cert,
+// which must have been issued by issuer. If hash is zero, SHA-1 is used to
+// identify the issuer, as most responders expect.
+func CreateOCSPRequest(cert, issuer *Certificate, hash crypto.Hash) ([]byte, error) {
+	if cert == nil || cert.SerialNumber == nil {
+		return nil, errors.New("x509: certificate can not be nil")
+	}
+	if issuer == nil {
+		return nil, errors.New("x509: issuer can not be nil")
+	}
+	id, err := newOCSPCertID(hash, cert.SerialNumber, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // OCSPRequest
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // TBSRequest
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // requestList
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // Request
+					id.marshal(b)
+				})
+			})
+		})
+	})
+	return b.Bytes()
+}
+
+// ParseOCSPRequest parses an OCSP request from the given ASN.1 DER data.
+// Requests for the status of more than one certificate are not supported.
+// Request signatures and extensions are ignored.
+func ParseOCSPRequest(der []byte) (*OCSPRequest, error) {
+	input := cryptobyte.String(der)
+	var raw, tbs cryptobyte.String
+	if !input.ReadASN1Element(&raw, cryptobyte_asn1.SEQUENCE) || !input.Empty() {
+		return nil, errors.New("x509: malformed OCSP request")
+	}
+	req := &OCSPRequest{Raw: raw}
+	if !raw.ReadASN1(&raw, cryptobyte_asn1.SEQUENCE) ||
+		!raw.ReadASN1(&tbs, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed OCSP request")
+	}
+
+	var version int64
+	if !tbs.ReadOptionalASN1Integer(&version, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), int64(0)) {
+		return nil, errors.New("x509: malformed OCSP request version")
+	}
+	if version != 0 {
+		return nil, fmt.Errorf("x509: unsupported OCSP request version %d", version)
+	}
+	if !tbs.SkipOptionalASN1(cryptobyte_asn1.Tag(1).Constructed().ContextSpecific()) {
+		return nil, errors.New("x509: malformed OCSP requestor name")
+	}
+
+	var requestList, request cryptobyte.String
+	if !tbs.ReadASN1(&requestList, cryptobyte_asn1.SEQUENCE) ||
+		!requestList.ReadASN1(&request, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed OCSP request list")
+	}
+	if !requestList.Empty() {
+		return nil, errors.New("x509: OCSP requests for multiple certificates are not supported")
+	}
+	id, err := parseOCSPCertID(&request)
+	if err != nil {
+		return nil, err
+	}
+	req.HashAlgorithm = id.hash
+	req.IssuerNameHash = id.issuerNameHash
+	req.IssuerKeyHash = id.issuerKeyHash
+	req.SerialNumber = id.serialNumber
+
+	return req, nil
+}
+
+// OCSPResponse represents a basic OCSP response for a single certificate, as
+// specified by RFC 6960, Section 4.2.
+type OCSPResponse struct {
+	// Raw contains the complete ASN.1 DER content of the response. It is set
+	// when parsing a response.
+	Raw []byte
+	// RawTBSResponseData contains just the signed tbsResponseData portion of
+	// the ASN.1 DER. It is set when parsing a response.
+	RawTBSResponseData []byte
+	// RawResponderName contains the DER encoded subject of the responder, if
+	// the responder identified itself by name. Otherwise, ResponderKeyHash
+	// contains the SHA-1 hash of its subject public key. They are set when
+	// parsing a response.
+	RawResponderName []byte
+	ResponderKeyHash []byte
+
+	Signature []byte
+	// SignatureAlgorithm is used to determine the signature algorithm to be
+	// used when signing the response. If 0 the default algorithm for the
+	// signing key will be used.
+	SignatureAlgorithm SignatureAlgorithm
+
+	// Certificate is the certificate of a delegated responder that signed the
+	// response on behalf of the issuer, if any. When creating a response, it
+	// is embedded in the response and the signing key must match it. When
+	// parsing a response, it is set to the first embedded certificate.
+	Certificate *Certificate
+
+	// Status is the revocation status of the certificate.
+	Status OCSPStatus
+	// SerialNumber is the serial number of the certificate. It must not be
+	// nil when creating a response.
+	SerialNumber *big.Int
+	// HashAlgorithm is the hash function used to identify the issuer of the
+	// certificate. When creating a response, if zero SHA-1 is used.
+	HashAlgorithm crypto.Hash
+	// IssuerNameHash and IssuerKeyHash are the hashes of the subject and of
+	// the subject public key of the issuer. They are set when parsing a
+	// response; when creating a response they are computed from the issuer.
+	IssuerNameHash []byte
+	IssuerKeyHash  []byte
+
+	// ProducedAt is the time at which the response was signed. When creating
+	// a response, if zero the current time is used.
+	ProducedAt time.Time
+	// ThisUpdate is the time at which the status was known to be correct.
+	ThisUpdate time.Time
+	// NextUpdate is the time by which newer status information will be
+	// available. It may be zero, meaning that newer information is always
+	// available.
+	NextUpdate time.Time
+
+	// RevokedAt is the time at which the certificate was revoked. It is used
+	// only if Status is OCSPRevoked.
+	RevokedAt time.Time
+	// RevocationReason is the reason for revocation, using the integer enum
+	// values specified in RFC 5280 Section 5.3.1. It is used only if Status is
+	// OCSPRevoked. When creating a response, the zero value results in the
+	// revocationReason field being omitted.
+	RevocationReason int
+
+	// Extensions contains the raw responseExtensions of the response. When
+	// creating a response, the Extensions field is ignored, see
+	// ExtraExtensions.
+	Extensions []pkix.Extension
+	// ExtraExtensions contains extensions to be copied, raw, into the
+	// responseExtensions of any marshaled response. It is not populated when
+	// parsing a response, see Extensions.
+	ExtraExtensions []pkix.Extension
+
+	// SingleExtensions contains the raw singleExtensions of the status of the
+	// certificate. When creating a response, the SingleExtensions field is
+	// ignored, see ExtraSingleExtensions.
+	SingleExtensions []pkix.Extension
+	// ExtraSingleExtensions contains extensions to be copied, raw, into the
+	// singleExtensions of any marshaled response. It is not populated when
+	// parsing a response, see SingleExtensions.
+	ExtraSingleExtensions []pkix.Extension
+}
+
+// ParseOCSPResponse parses an OCSP response from the given ASN.1 DER data.
+//
+// If the responder returned an error status, ParseOCSPResponse returns an
+// [OCSPResponseError]. Responses containing the status of more than one
+// certificate are not supported.
+//
+// ParseOCSPResponse does not verify the signature on the response, see
+// [OCSPResponse.CheckSignatureFrom].
+func ParseOCSPResponse(der []byte) (*OCSPResponse, error) {
+	return parseOCSPResponse(der, nil, nil)
+}
+
+// parseOCSPResponse parses der. If cert is not nil, it selects the single
+// response for cert, issued by issuer, and returns an error if there is none.
+func parseOCSPResponse(der []byte, cert, issuer *Certificate) (*OCSPResponse, error) {
+	input := cryptobyte.String(der)
+	var raw cryptobyte.String
+	if !input.ReadASN1Element(&raw, cryptobyte_asn1.SEQUENCE) || !input.Empty() {
+		return nil, errors.New("x509: malformed OCSP response")
+	}
+	resp := &OCSPResponse{Raw: raw}
+
+	var status int
+	if !raw.ReadASN1(&raw, cryptobyte_asn1.SEQUENCE) || !raw.ReadASN1Enum(&status) {
+		return nil, errors.New("x509: malformed OCSP response")
+	}
+	if OCSPResponseStatus(status) != OCSPSuccessful {
+		return nil, OCSPResponseError{OCSPResponseStatus(status)}
+	}
+
+	var responseBytes cryptobyte.String
+	var responseType asn1.ObjectIdentifier
+	var basic cryptobyte.String
+	if !raw.ReadASN1(&responseBytes, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) ||
+		!responseBytes.ReadASN1(&responseBytes, cryptobyte_asn1.SEQUENCE) ||
+		!responseBytes.ReadASN1ObjectIdentifier(&responseType) ||
+		!responseBytes.ReadASN1(&basic, cryptobyte_asn1.OCTET_STRING) {
+		return nil, errors.New("x509: malformed OCSP response bytes")
+	}
+	if !responseType.Equal(oidOCSPBasic) {
+		return nil, fmt.Errorf("x509: unsupported OCSP response type %v", responseType)
+	}
+
+	var tbs cryptobyte.String
+	if !basic.ReadASN1(&basic, cryptobyte_asn1.SEQUENCE) ||
+		!basic.ReadASN1Element(&tbs, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed OCSP basic response")
+	}
+	resp.RawTBSResponseData = tbs
+
+	var sigAISeq cryptobyte.String
+	if !basic.ReadASN1(&sigAISeq, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed signature algorithm identifier")
+	}
+	sigAI, err := parseAI(sigAISeq)
+	if err != nil {
+		return nil, err
+	}
+	resp.SignatureAlgorithm = getSignatureAlgorithmFromAI(sigAI)
+
+	var signature asn1.BitString
+	if !basic.ReadASN1BitString(&signature) {
+		return nil, errors.New("x509: malformed signature")
+	}
+	resp.Signature = signature.RightAlign()
+
+	var certs cryptobyte.String
+	var hasCerts bool
+	if !basic.ReadOptionalASN1(&certs, &hasCerts, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) {
+		return nil, errors.New("x509: malformed OCSP responder certificates")
+	}
+	if hasCerts {
+		var certDER cryptobyte.String
+		if !certs.ReadASN1(&certs, cryptobyte_asn1.SEQUENCE) {
+			return nil, errors.New("x509: malformed OCSP responder certificates")
+		}
+		if !certs.Empty() {
+			if !certs.ReadASN1Element(&certDER, cryptobyte_asn1.SEQUENCE) {
+				return nil, errors.New("x509: malformed OCSP responder certificate")
+			}
+			resp.Certificate, err = ParseCertificate(certDER)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !tbs.ReadASN1(&tbs, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed OCSP response data")
+	}
+	var version int64
+	if !tbs.ReadOptionalASN1Integer(&version, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), int64(0)) {
+		return nil, errors.New("x509: malformed OCSP response version")
+	}
+	if version != 0 {
+		return nil, fmt.Errorf("x509: unsupported OCSP response version %d", version)
+	}
+
+	var responderID cryptobyte.String
+	switch {
+	case tbs.PeekASN1Tag(cryptobyte_asn1.Tag(1).Constructed().ContextSpecific()):
+		if !tbs.ReadASN1(&responderID, cryptobyte_asn1.Tag(1).Constructed().ContextSpecific()) ||
+			!responderID.ReadASN1Element((*cryptobyte.String)(&resp.RawResponderName), cryptobyte_asn1.SEQUENCE) {
+			return nil, errors.New("x509: malformed OCSP responder name")
+		}
+	case tbs.PeekASN1Tag(cryptobyte_asn1.Tag(2).Constructed().ContextSpecific()):
+		if !tbs.ReadASN1(&responderID, cryptobyte_asn1.Tag(2).Constructed().ContextSpecific()) ||
+			!responderID.ReadASN1Bytes(&resp.ResponderKeyHash, cryptobyte_asn1.OCTET_STRING) {
+			return nil, errors.New("x509: malformed OCSP responder key hash")
+		}
+	default:
+		return nil, errors.New("x509: malformed OCSP responder ID")
+	}
+
+	if !tbs.ReadASN1GeneralizedTime(&resp.ProducedAt) {
+		return nil, errors.New("x509: malformed OCSP producedAt time")
+	}
+
+	var responses cryptobyte.String
+	if !tbs.ReadASN1(&responses, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed OCSP responses")
+	}
+	var found bool
+	for !responses.Empty() {
+		var single cryptobyte.String
+		if !responses.ReadASN1(&single, cryptobyte_asn1.SEQUENCE) {
+			return nil, errors.New("x509: malformed OCSP single response")
+		}
+		if found {
+			if cert == nil {
+				return nil, errors.New("x509: OCSP responses for multiple certificates are not supported")
+			}
+			continue
+		}
+		id, err := parseOCSPCertID(&single)
+		if err != nil {
+			return nil, err
+		}
+		if cert != nil && !id.matches(cert.SerialNumber, issuer) {
+			continue
+		}
+		found = true
+		resp.HashAlgorithm = id.hash
+		resp.IssuerNameHash = id.issuerNameHash
+		resp.IssuerKeyHash = id.issuerKeyHash
+		resp.SerialNumber = id.serialNumber
+		if err := parseOCSPSingleResponse(single, resp); err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		if cert != nil {
+			return nil, errors.New("x509: OCSP response does not cover the certificate")
+		}
+		return nil, errors.New("x509: OCSP response does not contain any certificate status")
+	}
+
+	var extensions cryptobyte.String
+	var present bool
+	if !tbs.ReadOptionalASN1(&extensions, &present, cryptobyte_asn1.Tag(1).Constructed().ContextSpecific()) {
+		return nil, errors.New("x509: malformed extensions")
+	}
+	if present {
+		resp.Extensions, err = parseOCSPExtensions(extensions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// parseOCSPSingleResponse parses the fields of a SingleResponse that follow
+// the CertID into resp.
+func parseOCSPSingleResponse(single cryptobyte.String, resp *OCSPResponse) error {
+	var tag cryptobyte_asn1.Tag
+	var certStatus cryptobyte.String
+	if !single.ReadAnyASN1(&certStatus, &tag) {
+		return errors.New("x509: malformed OCSP certificate status")
+	}
+	switch tag {
+	case cryptobyte_asn1.Tag(0).ContextSpecific():
+		resp.Status = OCSPGood
+	case cryptobyte_asn1.Tag(1).Constructed().ContextSpecific():
+		resp.Status = OCSPRevoked
+		if !certStatus.ReadASN1GeneralizedTime(&resp.RevokedAt) {
+			return errors.New("x509: malformed OCSP revocation time")
+		}
+		var reason cryptobyte.String
+		var present bool
+		if !certStatus.ReadOptionalASN1(&reason, &present, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) {
+			return errors.New("x509: malformed OCSP revocation reason")
+		}
+		if present && !reason.ReadASN1Enum(&resp.RevocationReason) {
+			return errors.New("x509: malformed OCSP revocation reason")
+		}
+	case cryptobyte_asn1.Tag(2).ContextSpecific():
+		resp.Status = OCSPUnknown
+	default:
+		return errors.New("x509: malformed OCSP certificate status")
+	}
+
+	if !single.ReadASN1GeneralizedTime(&resp.ThisUpdate) {
+		return errors.New("x509: malformed OCSP thisUpdate time")
+	}
+	var nextUpdate cryptobyte.String
+	var present bool
+	if !single.ReadOptionalASN1(&nextUpdate, &present, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) {
+		return errors.New("x509: malformed OCSP nextUpdate time")
+	}
+	if present && !nextUpdate.ReadASN1GeneralizedTime(&resp.NextUpdate) {
+		return errors.New("x509: malformed OCSP nextUpdate time")
+	}
+	var extensions cryptobyte.String
+	if !single.ReadOptionalASN1(&extensions, &present, cryptobyte_asn1.Tag(1).Constructed().ContextSpecific()) {
+		return errors.New("x509: malformed extensions")
+	}
+	if present {
+		var err error
+		resp.SingleExtensions, err = parseOCSPExtensions(extensions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseOCSPExtensions(der cryptobyte.String) ([]pkix.Extension, error) {
+	var exts []pkix.Extension
+	if !der.ReadASN1(&der, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed extensions")
+	}
+	for !der.Empty() {
+		var extension cryptobyte.String
+		if !der.ReadASN1(&extension, cryptobyte_asn1.SEQUENCE) {
+			return nil, errors.New("x509: malformed extension")
+		}
+		ext, err := parseExtension(extension)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
+
+// CheckSignatureFrom verifies that the signature on resp is a valid signature
+// from issuer, the issuer of the certificate the response is about.
+//
+// If resp was signed by a delegated responder, CheckSignatureFrom also checks
+// that resp.Certificate was issued by issuer and is authorized for OCSP
+// signing. It does not check the validity period of resp.Certificate.
+func (resp *OCSPResponse) CheckSignatureFrom(issuer *Certificate) error {
+	signer := issuer
+	if resp.Certificate != nil && !bytes.Equal(resp.Certificate.Raw, issuer.Raw) {
+		if err := resp.Certificate.CheckSignatureFrom(issuer); err != nil {
+			return err
+		}
+		if !slices.Contains(resp.Certificate.ExtKeyUsage, ExtKeyUsageOCSPSigning) {
+			return errors.New("x509: OCSP responder certificate is not authorized to sign OCSP responses")
+		}
+		signer = resp.Certificate
+	}
+	if signer.PublicKeyAlgorithm == UnknownPublicKeyAlgorithm {
+		return ErrUnsupportedAlgorithm
+	}
+	return signer.CheckSignature(resp.SignatureAlgorithm, resp.RawTBSResponseData, resp.Signature)
+}
+
+// checkCurrent returns an error if resp is not signed by issuer, or by a
+// delegated responder valid at now, or if resp is not current at now.
+func (resp *OCSPResponse) checkCurrent(issuer *Certificate, now time.Time) error {
+	if err := resp.CheckSignatureFrom(issuer); err != nil {
+		return err
+	}
+	if resp.Certificate != nil && (now.Before(resp.Certificate.NotBefore) || now.After(resp.Certificate.NotAfter)) {
+		return errors.New("x509: OCSP responder certificate has expired or is not yet valid")
+	}
+	if now.Before(resp.ThisUpdate) || !resp.NextUpdate.IsZero() && !now.Before(resp.NextUpdate) {
+		return errors.New("x509: OCSP response is not current")
+	}
+	return nil
+}
+
+// CreateOCSPResponse returns a DER encoded basic OCSP response for the
+// certificate identified by template.SerialNumber, issued by issuer, and
+// signed by priv.
+//
+// If template.Certificate is nil, priv must be the private key of issuer.
+// Otherwise, template.Certificate must be a delegated responder certificate
+// issued by issuer with the OCSPSigning extended key usage, priv must be its
+// private key, and it is included in the response.
+//
+// The returned response can be parsed with [ParseOCSPResponse].
+func CreateOCSPResponse(rand io.Reader, template *OCSPResponse, issuer *Certificate, priv crypto.Signer) ([]byte, error) {
+	if template == nil {
+		return nil, errors.New("x509: template can not be nil")
+	}
+	if issuer == nil {
+		return nil, errors.New("x509: issuer can not be nil")
+	}
+	if template.SerialNumber == nil {
+		return nil, errors.New("x509: template contains nil SerialNumber field")
+	}
+	if !template.NextUpdate.IsZero() && template.NextUpdate.Before(template.ThisUpdate) {
+		return nil, errors.New("x509: template.ThisUpdate is after template.NextUpdate")
+	}
+	if template.Status == OCSPRevoked && template.RevokedAt.IsZero() {
+		return nil, errors.New("x509: template contains zero RevokedAt field")
+	}
+
+	responder := issuer
+	if template.Certificate != nil {
+		responder = template.Certificate
+	}
+	if pub, ok := priv.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(responder.PublicKey) {
+		return nil, errors.New("x509: provided PrivateKey doesn't match responder certificate's PublicKey")
+	}
+
+	signatureAlgorithm, algorithmIdentifier, err := signingParamsForKey(priv, template.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newOCSPCertID(template.HashAlgorithm, template.SerialNumber, issuer)
+	if err != nil {
+		return nil, err
+	}
+	responderKey, err := subjectPublicKeyBytes(responder)
+	if err != nil {
+		return nil, err
+	}
+	responderKeyHash := crypto.SHA1.New()
+	responderKeyHash.Write(responderKey)
+
+	producedAt := template.ProducedAt
+	if producedAt.IsZero() {
+		producedAt = time.Now()
+	}
+
+	var tbs cryptobyte.Builder
+	tbs.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // ResponseData
+		b.AddASN1(cryptobyte_asn1.Tag(2).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddASN1OctetString(responderKeyHash.Sum(nil))
+		})
+		b.AddASN1GeneralizedTime(producedAt.UTC())
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // responses
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // SingleResponse
+				id.marshal(b)
+				switch template.Status {
+				case OCSPGood:
+					b.AddASN1(cryptobyte_asn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {})
+				case OCSPRevoked:
+					b.AddASN1(cryptobyte_asn1.Tag(1).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+						b.AddASN1GeneralizedTime(template.RevokedAt.UTC())
+						if template.RevocationReason != 0 {
+							b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+								b.AddASN1Enum(int64(template.RevocationReason))
+							})
+						}
+					})
+				case OCSPUnknown:
+					b.AddASN1(cryptobyte_asn1.Tag(2).ContextSpecific(), func(b *cryptobyte.Builder) {})
+				default:
+					b.SetError(fmt.Errorf("x509: invalid OCSP status %v", template.Status))
+				}
+				b.AddASN1GeneralizedTime(template.ThisUpdate.UTC())
+				if !template.NextUpdate.IsZero() {
+					b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+						b.AddASN1GeneralizedTime(template.NextUpdate.UTC())
+					})
+				}
+				if len(template.ExtraSingleExtensions) > 0 {
+					b.AddASN1(cryptobyte_asn1.Tag(1).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+						b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+							for _, ext := range template.ExtraSingleExtensions {
+								b.MarshalASN1(ext)
+							}
+						})
+					})
+				}
+			})
+		})
+		if len(template.ExtraExtensions) > 0 {
+			b.AddASN1(cryptobyte_asn1.Tag(1).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					for _, ext := range template.ExtraExtensions {
+						b.MarshalASN1(ext)
+					}
+				})
+			})
+		}
+	})
+	tbsResponseData, err := tbs.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signTBS(tbsResponseData, priv, signatureAlgorithm, rand)
+	if err != nil {
+		return nil, err
+	}
+	sigAI, err := asn1.Marshal(algorithmIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var basic cryptobyte.Builder
+	basic.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // BasicOCSPResponse
+		b.AddBytes(tbsResponseData)
+		b.AddBytes(sigAI)
+		b.AddASN1BitString(signature)
+		if template.Certificate != nil {
+			b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddBytes(template.Certificate.Raw)
+				})
+			})
+		}
+	})
+	basicResponse, err := basic.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalOCSPResponse(OCSPSuccessful, basicResponse)
+}
+
+// CreateOCSPErrorResponse returns a DER encoded OCSP response carrying an
+// error status instead of certificate status information. status must not be
+// OCSPSuccessful.
+func CreateOCSPErrorResponse(status OCSPResponseStatus) ([]byte, error) {
+	if status == OCSPSuccessful {
+		return nil, errors.New("x509: OCSP error response can't have a successful status")
+	}
+	return marshalOCSPResponse(status, nil)
+}
+
+func marshalOCSPResponse(status OCSPResponseStatus, basicResponse []byte) ([]byte, error) {
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // OCSPResponse
+		b.AddASN1Enum(int64(status))
+		if basicResponse != nil {
+			b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // ResponseBytes
+					b.AddASN1ObjectIdentifier(oidOCSPBasic)
+					b.AddASN1OctetString(basicResponse)
+				})
+			})
+		}
+	})
+	return b.Bytes()
+}
@@ -0,0 +1,482 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ocspTestPKI struct {
+	root, intermediate, leaf, responder    *Certificate
+	rootKey, intermediateKey, responderKey crypto.Signer
+}
+
+func newOCSPTestPKI(t *testing.T) *ocspTestPKI {
+	t.Helper()
+	now := time.Now()
+	var serial int64
+	newCert := func(cn string, template *Certificate, parent *Certificate, parentKey crypto.Signer) (*Certificate, crypto.Signer) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		serial++
+		template.SerialNumber = big.NewInt(serial)
+		template.Subject = pkix.Name{CommonName: cn}
+		template.NotBefore = now.Add(-time.Hour)
+		template.NotAfter = now.Add(24 * time.Hour)
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		der, err := CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert, key
+	}
+	caTemplate := func() *Certificate {
+		return &Certificate{
+			KeyUsage:              KeyUsageCertSign | KeyUsageCRLSign | KeyUsageDigitalSignature,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+	}
+
+	p := &ocspTestPKI{}
+	p.root, p.rootKey = newCert("OCSP Test Root", caTemplate(), nil, nil)
+	p.intermediate, p.intermediateKey = newCert("OCSP Test Intermediate", caTemplate(), p.root, p.rootKey)
+	p.leaf, _ = newCert("OCSP Test Leaf", &Certificate{
+		KeyUsage:    KeyUsageDigitalSignature,
+		ExtKeyUsage: []ExtKeyUsage{ExtKeyUsageClientAuth},
+		OCSPServer:  []string{"http://ocsp.example.com"},
+	}, p.intermediate, p.intermediateKey)
+	p.responder, p.responderKey = newCert("OCSP Test Responder", &Certificate{
+		KeyUsage:    KeyUsageDigitalSignature,
+		ExtKeyUsage: []ExtKeyUsage{ExtKeyUsageOCSPSigning},
+	}, p.intermediate, p.intermediateKey)
+	return p
+}
+
+func (p *ocspTestPKI) response(t *testing.T, template *OCSPResponse) []byte {
+	t.Helper()
+	if template.SerialNumber == nil {
+		template.SerialNumber = p.leaf.SerialNumber
+	}
+	if template.ThisUpdate.IsZero() {
+		template.ThisUpdate = time.Now().Add(-time.Hour)
+		template.NextUpdate = time.Now().Add(time.Hour)
+	}
+	key := p.intermediateKey
+	if template.Certificate != nil {
+		key = p.responderKey
+	}
+	der, err := CreateOCSPResponse(rand.Reader, template, p.intermediate, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestOCSPRequest(t *testing.T) {
+	p := newOCSPTestPKI(t)
+	for _, hash := range []crypto.Hash{0, crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		der, err := CreateOCSPRequest(p.leaf, p.intermediate, hash)
+		if err != nil {
+			t.Fatalf("%v: %v", hash, err)
+		}
+		req, err := ParseOCSPRequest(der)
+		if err != nil {
+			t.Fatalf("%v: %v", hash, err)
+		}
+		if !bytes.Equal(req.Raw, der) {
+			t.Errorf("%v: Raw doesn't match the encoded request", hash)
+		}
+		wantHash := hash
+		if wantHash == 0 {
+			wantHash = crypto.SHA1
+		}
+		if req.HashAlgorithm != wantHash {
+			t.Errorf("%v: HashAlgorithm = %v, want %v", hash, req.HashAlgorithm, wantHash)
+		}
+		if req.SerialNumber.Cmp(p.leaf.SerialNumber) != 0 {
+			t.Errorf("%v: SerialNumber = %v, want %v", hash, req.SerialNumber, p.leaf.SerialNumber)
+		}
+		h := wantHash.New()
+		h.Write(p.intermediate.RawSubject)
+		if !bytes.Equal(req.IssuerNameHash, h.Sum(nil)) {
+			t.Errorf("%v: unexpected IssuerNameHash", hash)
+		}
+		if len(req.IssuerKeyHash) != wantHash.Size() {
+			t.Errorf("%v: unexpected IssuerKeyHash length %d", hash, len(req.IssuerKeyHash))
+		}
+	}
+
+	if _, err := CreateOCSPRequest(p.leaf, p.intermediate, crypto.MD5); err == nil {
+		t.Error("CreateOCSPRequest with MD5 succeeded")
+	}
+}
+
+func TestOCSPResponse(t *testing.T) {
+	p := newOCSPTestPKI(t)
+	thisUpdate := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	nextUpdate := thisUpdate.Add(2 * time.Hour)
+	revokedAt := thisUpdate.Add(-24 * time.Hour)
+	ext := pkix.Extension{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}, Value: []byte{0x04, 0x02, 0x01, 0x02}}
+
+	for _, tt := range []struct {
+		name     string
+		template OCSPResponse
+	}{
+		{"good", OCSPResponse{Status: OCSPGood}},
+		{"revoked", OCSPResponse{Status: OCSPRevoked, RevokedAt: revokedAt, RevocationReason: 1}},
+		{"revoked/no reason", OCSPResponse{Status: OCSPRevoked, RevokedAt: revokedAt}},
+		{"unknown", OCSPResponse{Status: OCSPUnknown}},
+		{"no next update", OCSPResponse{Status: OCSPGood}},
+		{"delegated", OCSPResponse{Status: OCSPGood, Certificate: p.responder}},
+		{"sha256", OCSPResponse{Status: OCSPGood, HashAlgorithm: crypto.SHA256}},
+		{"extensions", OCSPResponse{Status: OCSPGood, ExtraExtensions: []pkix.Extension{ext}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			template := tt.template
+			template.ThisUpdate = thisUpdate
+			if tt.name != "no next update" {
+				template.NextUpdate = nextUpdate
+			}
+			der := p.response(t, &template)
+			resp, err := ParseOCSPResponse(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := resp.CheckSignatureFrom(p.intermediate); err != nil {
+				t.Errorf("CheckSignatureFrom failed: %v", err)
+			}
+			if err := resp.CheckSignatureFrom(p.root); err == nil {
+				t.Errorf("CheckSignatureFrom succeeded with the wrong issuer")
+			}
+
+			if !bytes.Equal(resp.Raw, der) {
+				t.Errorf("Raw doesn't match the encoded response")
+			}
+			if resp.Status != template.Status {
+				t.Errorf("Status = %v, want %v", resp.Status, template.Status)
+			}
+			if resp.SerialNumber.Cmp(p.leaf.SerialNumber) != 0 {
+				t.Errorf("SerialNumber = %v, want %v", resp.SerialNumber, p.leaf.SerialNumber)
+			}
+			if !resp.ThisUpdate.Equal(template.ThisUpdate) {
+				t.Errorf("ThisUpdate = %v, want %v", resp.ThisUpdate, template.ThisUpdate)
+			}
+			if !resp.NextUpdate.Equal(template.NextUpdate) {
+				t.Errorf("NextUpdate = %v, want %v", resp.NextUpdate, template.NextUpdate)
+			}
+			if !resp.RevokedAt.Equal(template.RevokedAt) {
+				t.Errorf("RevokedAt = %v, want %v", resp.RevokedAt, template.RevokedAt)
+			}
+			if resp.RevocationReason != template.RevocationReason {
+				t.Errorf("RevocationReason = %d, want %d", resp.RevocationReason, template.RevocationReason)
+			}
+			if resp.ProducedAt.IsZero() {
+				t.Errorf("ProducedAt is zero")
+			}
+			if template.Certificate != nil {
+				if resp.Certificate == nil || !resp.Certificate.Equal(template.Certificate) {
+					t.Errorf("Certificate doesn't match the delegated responder")
+				}
+			} else if resp.Certificate != nil {
+				t.Errorf("unexpected Certificate in response")
+			}
+			if len(resp.ResponderKeyHash) != 20 {
+				t.Errorf("unexpected ResponderKeyHash %x", resp.ResponderKeyHash)
+			}
+			if len(template.ExtraExtensions) > 0 {
+				if len(resp.Extensions) != 1 || !resp.Extensions[0].Id.Equal(ext.Id) || !bytes.Equal(resp.Extensions[0].Value, ext.Value) {
+					t.Errorf("Extensions = %v, want %v", resp.Extensions, template.ExtraExtensions)
+				}
+			}
+			if _, err := parseOCSPResponse(der, p.leaf, p.intermediate); err != nil {
+				t.Errorf("response doesn't match the certificate: %v", err)
+			}
+			if _, err := parseOCSPResponse(der, p.intermediate, p.root); err == nil {
+				t.Errorf("response matches the wrong certificate")
+			}
+		})
+	}
+}
+
+// ocspResponseOpenSSL was generated with "openssl ocsp -index" for a
+// certificate with serial number 0x1234, and is signed by the embedded issuer.
+const ocspResponseOpenSSL = `MIICsAoBAKCCAqkwggKlBgkrBgEFBQcwAQEEggKWMIICkjCBpKEUMBIxEDAOBgNV
+BAMMB09TU0wtQ0EYDzIwMjYxMDE2MTYyMzQ5WjB7MHkwOzAJBgUrDgMCGgUABBQ4
+FzVqxvIviZdvI2rW6dspbqJ7EQQUN6x+PKwPvPSNw5GaLt31v8odzKACAhI0oRYY
+DzIwMjYxMDAxMDAwMDAwWqADCgEBGA8yMDI2MTAxNjE2MjM0OVqgERgPMjAyNjEw
+MTcxNjIzNDlaMAoGCCqGSM49BAMCA0cAMEQCIFAUuxF+XU0Qfdg8++osNfiuCDv4
+XypeCbCbssKmG2uDAiB5NfqJqAQTBseydTjfWA1VotjOmgBHxeDyvVrLdjfuVqCC
+AZIwggGOMIIBijCCAS+gAwIBAgIUKBeqCsCADrnDqHTLU9OODtz8T4MwCgYIKoZI
+zj0EAwIwEjEQMA4GA1UEAwwHT1NTTC1DQTAeFw0yNjEwMTYxNjIzNDlaFw0yNjEw
+MTkxNjIzNDlaMBIxEDAOBgNVBAMMB09TU0wtQ0EwWTATBgcqhkjOPQIBBggqhkjO
+PQMBBwNCAATosClP84CjdvTtwNAV8kNTFmhWGEm6gyy7jKTQOhMCJUunqT6XVTe9
+d80XcfVlVeVGrvoK49qwf95qoHFmH+Jgo2MwYTAdBgNVHQ4EFgQUN6x+PKwPvPSN
+w5GaLt31v8odzKAwHwYDVR0jBBgwFoAUN6x+PKwPvPSNw5GaLt31v8odzKAwDwYD
+VR0TAQH/BAUwAwEB/zAOBgNVHQ8BAf8EBAMCAYYwCgYIKoZIzj0EAwIDSQAwRgIh
+AJ5UF2+zO+Jp88FMtX+1ERaGNiPV1BBzFqKG+H3cO/BeAiEAwXz3f4YkmLXQq9K2
+T3bNkfQZz38FfGXjfG9crGtYSMk=`
+
+func TestParseOCSPResponseOpenSSL(t *testing.T) {
+	der, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(ocspResponseOpenSSL, "\n", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ParseOCSPResponse(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Certificate == nil {
+		t.Fatal("missing embedded certificate")
+	}
+	issuer := resp.Certificate
+	if err := resp.CheckSignatureFrom(issuer); err != nil {
+		t.Errorf("CheckSignatureFrom failed: %v", err)
+	}
+	if resp.Status != OCSPRevoked || resp.RevocationReason != 1 {
+		t.Errorf("Status = %v, RevocationReason = %d, want revoked, 1", resp.Status, resp.RevocationReason)
+	}
+	if want := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC); !resp.RevokedAt.Equal(want) {
+		t.Errorf("RevokedAt = %v, want %v", resp.RevokedAt, want)
+	}
+	if !bytes.Equal(resp.RawResponderName, issuer.RawSubject) {
+		t.Errorf("RawResponderName doesn't match the issuer")
+	}
+	if resp.HashAlgorithm != crypto.SHA1 || resp.SerialNumber.Cmp(big.NewInt(0x1234)) != 0 {
+		t.Errorf("unexpected CertID: %v %v", resp.HashAlgorithm, resp.SerialNumber)
+	}
+	cert := &Certificate{SerialNumber: big.NewInt(0x1234)}
+	if _, err := parseOCSPResponse(der, cert, issuer); err != nil {
+		t.Errorf("response doesn't match the certificate: %v", err)
+	}
+	if err := resp.checkCurrent(issuer, resp.ThisUpdate.Add(time.Hour)); err != nil {
+		t.Errorf("response is not current: %v", err)
+	}
+	if err := resp.checkCurrent(issuer, resp.NextUpdate); err == nil {
+		t.Errorf("response is current at NextUpdate")
+	}
+}
+
+func TestOCSPResponseDelegatedWithoutEKU(t *testing.T) {
+	p := newOCSPTestPKI(t)
+	// The leaf is issued by the intermediate, but it's not authorized to sign
+	// OCSP responses.
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "Not A Responder"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []ExtKeyUsage{ExtKeyUsageServerAuth},
+	}
+	der, err := CreateCertificate(rand.Reader, template, p.intermediate, leafKey.Public(), p.intermediateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notResponder, err := ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	respDER, err := CreateOCSPResponse(rand.Reader, &OCSPResponse{
+		Status:       OCSPGood,
+		SerialNumber: p.leaf.SerialNumber,
+		ThisUpdate:   time.Now(),
+		Certificate:  notResponder,
+	}, p.intermediate, leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ParseOCSPResponse(respDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.CheckSignatureFrom(p.intermediate); err == nil {
+		t.Error("CheckSignatureFrom succeeded for a responder without the OCSPSigning EKU")
+	}
+
+	if _, err := CreateOCSPResponse(rand.Reader, &OCSPResponse{
+		Status:       OCSPGood,
+		SerialNumber: p.leaf.SerialNumber,
+		ThisUpdate:   time.Now(),
+	}, p.intermediate, leafKey); err == nil {
+		t.Error("CreateOCSPResponse succeeded with a key that doesn't match the issuer")
+	}
+}
+
+func TestOCSPErrorResponse(t *testing.T) {
+	der, err := CreateOCSPErrorResponse(OCSPTryLater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ParseOCSPResponse(der)
+	var respErr OCSPResponseError
+	if !errors.As(err, &respErr) || respErr.Status != OCSPTryLater {
+		t.Errorf("ParseOCSPResponse error = %v, want OCSPResponseError{OCSPTryLater}", err)
+	}
+
+	if _, err := CreateOCSPErrorResponse(OCSPSuccessful); err == nil {
+		t.Error("CreateOCSPErrorResponse(OCSPSuccessful) succeeded")
+	}
+}
+
+func TestParseOCSPResponseMalformed(t *testing.T) {
+	p := newOCSPTestPKI(t)
+	der := p.response(t, &OCSPResponse{Status: OCSPGood})
+	for i := range der {
+		if _, err := ParseOCSPResponse(der[:i]); err == nil {
+			t.Fatalf("ParseOCSPResponse succeeded on a response truncated to %d bytes", i)
+		}
+	}
+	if _, err := ParseOCSPResponse(append(der, 0)); err == nil {
+		t.Error("ParseOCSPResponse succeeded with trailing data")
+	}
+}
+
+func TestVerifyRevocation(t *testing.T) {
+	p := newOCSPTestPKI(t)
+	roots := NewCertPool()
+	roots.AddCert(p.root)
+	intermediates := NewCertPool()
+	intermediates.AddCert(p.intermediate)
+
+	newCRLWithExtensions := func(issuer *Certificate, key crypto.Signer, thisUpdate time.Time, exts []pkix.Extension, revoked ...*Certificate) *RevocationList {
+		template := &RevocationList{
+			Number:          big.NewInt(1),
+			ThisUpdate:      thisUpdate,
+			NextUpdate:      thisUpdate.Add(2 * time.Hour),
+			ExtraExtensions: exts,
+		}
+		for _, c := range revoked {
+			template.RevokedCertificateEntries = append(template.RevokedCertificateEntries, RevocationListEntry{
+				SerialNumber:   c.SerialNumber,
+				RevocationTime: thisUpdate,
+			})
+		}
+		der, err := CreateRevocationList(rand.Reader, template, issuer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		crl, err := ParseRevocationList(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crl
+	}
+	newCRL := func(issuer *Certificate, key crypto.Signer, thisUpdate time.Time, revoked ...*Certificate) *RevocationList {
+		return newCRLWithExtensions(issuer, key, thisUpdate, nil, revoked...)
+	}
+
+	good := p.response(t, &OCSPResponse{Status: OCSPGood})
+	revoked := p.response(t, &OCSPResponse{Status: OCSPRevoked, RevokedAt: time.Now().Add(-time.Hour)})
+	delegatedRevoked := p.response(t, &OCSPResponse{Status: OCSPRevoked, RevokedAt: time.Now().Add(-time.Hour), Certificate: p.responder})
+	unknown := p.response(t, &OCSPResponse{Status: OCSPUnknown})
+	stale := p.response(t, &OCSPResponse{Status: OCSPRevoked, RevokedAt: time.Now().Add(-time.Hour),
+		ThisUpdate: time.Now().Add(-3 * time.Hour), NextUpdate: time.Now().Add(-2 * time.Hour)})
+	otherCert := p.response(t, &OCSPResponse{Status: OCSPRevoked, RevokedAt: time.Now().Add(-time.Hour), SerialNumber: big.NewInt(1)})
+	crlRevoked := newCRL(p.intermediate, p.intermediateKey, time.Now().Add(-time.Hour), p.leaf)
+	crlEmpty := newCRL(p.intermediate, p.intermediateKey, time.Now().Add(-time.Hour))
+	crlStale := newCRL(p.intermediate, p.intermediateKey, time.Now().Add(-3*time.Hour), p.leaf)
+	crlWrongIssuer := newCRL(p.root, p.rootKey, time.Now().Add(-time.Hour), p.leaf)
+	crlRootRevokesIntermediate := newCRL(p.root, p.rootKey, time.Now().Add(-time.Hour), p.intermediate)
+	// A delta CRL (base CRL number 1) and a CRL partitioned to CA
+	// certificates by an issuing distribution point, neither listing the leaf.
+	crlDelta := newCRLWithExtensions(p.intermediate, p.intermediateKey, time.Now().Add(-time.Hour),
+		[]pkix.Extension{{Id: oidExtensionDeltaCRLIndicator, Critical: true, Value: []byte{0x02, 0x01, 0x01}}})
+	crlPartitioned := newCRLWithExtensions(p.intermediate, p.intermediateKey, time.Now().Add(-time.Hour),
+		[]pkix.Extension{{Id: oidExtensionIssuingDistributionPoint, Critical: true, Value: []byte{0x30, 0x03, 0x82, 0x01, 0xff}}})
+	crlUnknownCritical := newCRLWithExtensions(p.intermediate, p.intermediateKey, time.Now().Add(-time.Hour),
+		[]pkix.Extension{{Id: asn1.ObjectIdentifier{1, 2, 3}, Critical: true, Value: []byte{0x05, 0x00}}})
+
+	fetch := func(resp []byte, err error) func(cert, issuer *Certificate) ([]byte, error) {
+		return func(cert, issuer *Certificate) ([]byte, error) {
+			if cert != p.leaf || issuer != p.intermediate {
+				t.Errorf("FetchOCSP called for %v issued by %v", cert.Subject, issuer.Subject)
+			}
+			return resp, err
+		}
+	}
+
+	for _, tt := range []struct {
+		name       string
+		revocation *RevocationOptions
+		wantReason InvalidReason // -1 for success
+	}{
+		{"disabled", nil, -1},
+		{"no sources", &RevocationOptions{}, -1},
+		{"no sources/require", &RevocationOptions{RequireStatus: true}, RevocationStatusUnknown},
+		{"ocsp good", &RevocationOptions{OCSPResponses: [][]byte{good}, RequireStatus: true}, -1},
+		{"ocsp revoked", &RevocationOptions{OCSPResponses: [][]byte{revoked}}, Revoked},
+		{"ocsp delegated revoked", &RevocationOptions{OCSPResponses: [][]byte{delegatedRevoked}}, Revoked},
+		{"ocsp unknown", &RevocationOptions{OCSPResponses: [][]byte{unknown}}, -1},
+		{"ocsp unknown/require", &RevocationOptions{OCSPResponses: [][]byte{unknown}, RequireStatus: true}, RevocationStatusUnknown},
+		{"ocsp stale", &RevocationOptions{OCSPResponses: [][]byte{stale}}, -1},
+		{"ocsp other certificate", &RevocationOptions{OCSPResponses: [][]byte{otherCert}}, -1},
+		{"ocsp garbage", &RevocationOptions{OCSPResponses: [][]byte{[]byte("garbage")}, RequireStatus: true}, RevocationStatusUnknown},
+		{"ocsp good before crl", &RevocationOptions{OCSPResponses: [][]byte{good}, CRLs: []*RevocationList{crlRevoked}}, -1},
+		{"crl revoked", &RevocationOptions{CRLs: []*RevocationList{crlRevoked}}, Revoked},
+		{"crl not revoked", &RevocationOptions{CRLs: []*RevocationList{crlEmpty}, RequireStatus: true}, -1},
+		{"crl stale", &RevocationOptions{CRLs: []*RevocationList{crlStale}}, -1},
+		{"crl wrong issuer", &RevocationOptions{CRLs: []*RevocationList{crlWrongIssuer}, RequireStatus: true}, RevocationStatusUnknown},
+		{"crl delta", &RevocationOptions{CRLs: []*RevocationList{crlDelta}, RequireStatus: true}, RevocationStatusUnknown},
+		{"crl delta before revoked", &RevocationOptions{CRLs: []*RevocationList{crlDelta, crlRevoked}}, Revoked},
+		{"crl partitioned", &RevocationOptions{CRLs: []*RevocationList{crlPartitioned}, RequireStatus: true}, RevocationStatusUnknown},
+		{"crl partitioned before revoked", &RevocationOptions{CRLs: []*RevocationList{crlPartitioned, crlRevoked}}, Revoked},
+		{"crl unknown critical extension", &RevocationOptions{CRLs: []*RevocationList{crlUnknownCritical}, RequireStatus: true}, RevocationStatusUnknown},
+		{"fetch revoked", &RevocationOptions{FetchOCSP: fetch(revoked, nil)}, Revoked},
+		{"fetch good", &RevocationOptions{FetchOCSP: fetch(good, nil), RequireStatus: true}, -1},
+		{"fetch error", &RevocationOptions{FetchOCSP: fetch(nil, errors.New("network down"))}, -1},
+		{"fetch error/require", &RevocationOptions{FetchOCSP: fetch(nil, errors.New("network down")), RequireStatus: true}, RevocationStatusUnknown},
+		{"fetch after stale", &RevocationOptions{OCSPResponses: [][]byte{stale}, FetchOCSP: fetch(revoked, nil)}, Revoked},
+		{"intermediate not checked", &RevocationOptions{CRLs: []*RevocationList{crlRootRevokesIntermediate}}, -1},
+		{"intermediate revoked", &RevocationOptions{CRLs: []*RevocationList{crlRootRevokesIntermediate}, CheckIntermediates: true}, Revoked},
+		{"intermediate unknown/require", &RevocationOptions{OCSPResponses: [][]byte{good}, CheckIntermediates: true, RequireStatus: true}, RevocationStatusUnknown},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			chains, err := p.leaf.Verify(VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+				KeyUsages:     []ExtKeyUsage{ExtKeyUsageClientAuth},
+				Revocation:    tt.revocation,
+			})
+			if tt.wantReason == -1 {
+				if err != nil {
+					t.Fatalf("Verify failed: %v", err)
+				}
+				if len(chains) != 1 {
+					t.Fatalf("Verify returned %d chains, want 1", len(chains))
+				}
+				return
+			}
+			var invalidErr CertificateInvalidError
+			if !errors.As(err, &invalidErr) || invalidErr.Reason != tt.wantReason {
+				t.Fatalf("Verify error = %v, want reason %d", err, tt.wantReason)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"crypto/mldsa"
 	"crypto/mlkem"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"errors"
@@ -373,6 +374,14 @@ func parsePublicKey(keyData *publicKeyInfo) (any, error) {
 			return nil, errors.New("x509: unsupported ML-DSA parameters")
 		}
 		return mldsa.NewPublicKey(params, data)
+	case isSLHDSAOID(oid):
+		// RFC 9909, Section 3
+		// > The contents of the parameters component for each algorithm MUST be absent.
+		if len(params.FullBytes) != 0 {
+			return nil, errors.New("x509: SLH-DSA key encoded with illegal parameters")
+		}
+		params, _ := slhdsaParametersFromOID(oid)
+		return slhdsa.NewPublicKey(params, data)
 	case oid.Equal(oidPublicKeyX25519):
 		// RFC 8410, Section 3
 		// > For all of the OIDs, the parameters MUST be absent.
@@ -71,3 +71,21 @@ func TestMLDSAUnavailableErrorsNotPanics(t *testing.T) {
 		})
 	}
 }
+
+// TestParseSLHDSACertificateFIPS140v1_0 is like
+// TestParseMLDSACertificateFIPS140v1_0, but for SLH-DSA.
+func TestParseSLHDSACertificateFIPS140v1_0(t *testing.T) {
+	cert, err := ParseCertificate(pemDecode(t, testCertificateSLHDSASHA2128s))
+	if err != nil {
+		t.Fatalf("ParseCertificate failed: %v", err)
+	}
+	if cert.PublicKeyAlgorithm != UnknownPublicKeyAlgorithm {
+		t.Errorf("PublicKeyAlgorithm = %v, want UnknownPublicKeyAlgorithm", cert.PublicKeyAlgorithm)
+	}
+	if cert.PublicKey != nil {
+		t.Errorf("PublicKey = %v, want nil", cert.PublicKey)
+	}
+	if _, err := ParsePKCS8PrivateKey(pemDecode(t, testPrivateKeySLHDSASHA2128s)); err == nil {
+		t.Error("ParsePKCS8PrivateKey: expected error, got nil")
+	}
+}
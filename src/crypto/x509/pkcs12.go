@@ -0,0 +1,977 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"errors"
+	"hash"
+	"io"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// This file implements PKCS #12 files, also known as PFX files, as specified
+// in RFC 7292. Only password integrity and privacy modes are supported.
+
+var (
+	oidPKCS7Data          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS7EncryptedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidKeyBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidSafeContentsBag     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 6}
+	oidX509CertificateBag  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+
+	oidFriendlyName = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	oidLocalKeyID   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+
+	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHAAnd2KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 4}
+	oidPBEWithSHAAnd128BitRC2CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 5}
+	oidPBEWithSHAAnd40BitRC2CBC      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidPBMAC1 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 14}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// PKCS12 is the contents of a PKCS #12 file.
+type PKCS12 struct {
+	Certificates []PKCS12Certificate
+	PrivateKeys  []PKCS12PrivateKey
+}
+
+// PKCS12Certificate is a certificate stored in a PKCS #12 file.
+type PKCS12Certificate struct {
+	Certificate *Certificate
+
+	// FriendlyName is the optional name of the certificate.
+	FriendlyName string
+
+	// LocalKeyID, if not nil, identifies the private key with the same
+	// LocalKeyID as the key of this certificate.
+	LocalKeyID []byte
+}
+
+// PKCS12PrivateKey is a private key stored in a PKCS #12 file.
+type PKCS12PrivateKey struct {
+	// Key is a private key of one of the types supported by
+	// [ParsePKCS8PrivateKey] and [MarshalPKCS8PrivateKey].
+	Key any
+
+	// FriendlyName is the optional name of the private key.
+	FriendlyName string
+
+	// LocalKeyID, if not nil, identifies the certificate with the same
+	// LocalKeyID as the certificate of this key.
+	LocalKeyID []byte
+}
+
+// PKCS12Options are the options of [MarshalPKCS12].
+type PKCS12Options struct {
+	// Iterations is the iteration count of the key derivation functions. If
+	// zero, 2048 is used.
+	Iterations int
+
+	// PlaintextCertificates, if true, causes the certificates not to be
+	// encrypted. Private keys are always encrypted.
+	PlaintextCertificates bool
+}
+
+// pkcs12DefaultIterations matches the OpenSSL default.
+const pkcs12DefaultIterations = 2048
+
+// pkcs12MaxIterations bounds the work done by a key derivation function.
+const pkcs12MaxIterations = 10_000_000
+
+// pkcs12MaxTotalIterations bounds the work done to parse a file, regardless of
+// the number of encrypted bags. It allows for a MAC, an encrypted safe, and a
+// shrouded key bag, each at the maximum iteration count.
+const pkcs12MaxTotalIterations = 3 * pkcs12MaxIterations
+
+// pkcs12Work accounts for the key derivation work done to parse a file.
+type pkcs12Work struct {
+	iterations int
+}
+
+// spend records a key derivation with the given (already validated) number
+// of iterations, and returns an error if the file exceeds its budget.
+func (w *pkcs12Work) spend(iterations int) error {
+	w.iterations += iterations
+	if w.iterations > pkcs12MaxTotalIterations {
+		return errors.New("x509: PKCS #12 file requires too many key derivation iterations")
+	}
+	return nil
+}
+
+// ParsePKCS12 parses a PKCS #12 file (also known as a PFX or P12 file) in DER
+// form, as specified in RFC 7292, decrypting it with password.
+//
+// Both modern files, encrypted with PBES2 (PBKDF2 and AES-CBC or 3DES-CBC),
+// and legacy files, encrypted with the PKCS #12 key derivation function and
+// 3DES-CBC or RC2-CBC, are supported. The integrity MAC must be valid: if it
+// isn't, [IncorrectPasswordError] is returned. Files without a MAC are only
+// accepted if password is empty. Files protected with public-key integrity or
+// privacy modes are not supported.
+//
+// CRL bags, secret bags, and unknown bags and attributes are ignored.
+func ParsePKCS12(der []byte, password string) (*PKCS12, error) {
+	input := cryptobyte.String(der)
+	var pfx, authSafe, macData cryptobyte.String
+	var version int64
+	var hasMacData bool
+	if !input.ReadASN1(&pfx, cryptobyte_asn1.SEQUENCE) || !input.Empty() ||
+		!pfx.ReadASN1Integer(&version) ||
+		!pfx.ReadASN1Element(&authSafe, cryptobyte_asn1.SEQUENCE) ||
+		!pfx.ReadOptionalASN1(&macData, &hasMacData, cryptobyte_asn1.SEQUENCE) ||
+		!pfx.Empty() {
+		return nil, errors.New("x509: malformed PKCS #12 file")
+	}
+	if version != 3 {
+		return nil, errors.New("x509: unsupported PKCS #12 version")
+	}
+
+	contentType, content, err := parsePKCS12ContentInfo(authSafe)
+	if err != nil {
+		return nil, err
+	}
+	if !contentType.Equal(oidPKCS7Data) {
+		return nil, errors.New("x509: unsupported PKCS #12 integrity mode")
+	}
+	var authSafeData cryptobyte.String
+	if !content.ReadASN1(&authSafeData, cryptobyte_asn1.OCTET_STRING) || !content.Empty() {
+		return nil, errors.New("x509: malformed PKCS #12 authenticated safe")
+	}
+
+	work := new(pkcs12Work)
+	if hasMacData {
+		if err := verifyPKCS12MAC(macData, authSafeData, password, work); err != nil {
+			return nil, err
+		}
+	} else if password != "" {
+		// Without a MAC, the contents could have been tampered with, even if
+		// they are encrypted.
+		return nil, errors.New("x509: PKCS #12 file has no integrity MAC")
+	}
+
+	var contents cryptobyte.String
+	if !authSafeData.ReadASN1(&contents, cryptobyte_asn1.SEQUENCE) || !authSafeData.Empty() {
+		return nil, errors.New("x509: malformed PKCS #12 authenticated safe")
+	}
+	p := new(PKCS12)
+	for !contents.Empty() {
+		var contentInfo cryptobyte.String
+		if !contents.ReadASN1Element(&contentInfo, cryptobyte_asn1.SEQUENCE) {
+			return nil, errors.New("x509: malformed PKCS #12 authenticated safe")
+		}
+		contentType, content, err := parsePKCS12ContentInfo(contentInfo)
+		if err != nil {
+			return nil, err
+		}
+		var safeContents []byte
+		switch {
+		case contentType.Equal(oidPKCS7Data):
+			var data cryptobyte.String
+			if !content.ReadASN1(&data, cryptobyte_asn1.OCTET_STRING) || !content.Empty() {
+				return nil, errors.New("x509: malformed PKCS #12 safe contents")
+			}
+			safeContents = data
+		case contentType.Equal(oidPKCS7EncryptedData):
+			safeContents, err = decryptPKCS12EncryptedData(content, password, work)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.New("x509: unsupported PKCS #12 privacy mode")
+		}
+		if err := p.parseSafeContents(safeContents, password, work, 0); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func parsePKCS12ContentInfo(der cryptobyte.String) (asn1.ObjectIdentifier, cryptobyte.String, error) {
+	var contentInfo, content cryptobyte.String
+	var contentType asn1.ObjectIdentifier
+	if !der.ReadASN1(&contentInfo, cryptobyte_asn1.SEQUENCE) || !der.Empty() ||
+		!contentInfo.ReadASN1ObjectIdentifier(&contentType) ||
+		!contentInfo.ReadASN1(&content, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) ||
+		!contentInfo.Empty() {
+		return nil, nil, errors.New("x509: malformed PKCS #12 content info")
+	}
+	return contentType, content, nil
+}
+
+func decryptPKCS12EncryptedData(der cryptobyte.String, password string, work *pkcs12Work) ([]byte, error) {
+	var encryptedData, encryptedContentInfo, algorithm cryptobyte.String
+	var contentType asn1.ObjectIdentifier
+	var version int64
+	var encryptedContent []byte
+	if !der.ReadASN1(&encryptedData, cryptobyte_asn1.SEQUENCE) || !der.Empty() ||
+		!encryptedData.ReadASN1Integer(&version) ||
+		!encryptedData.ReadASN1(&encryptedContentInfo, cryptobyte_asn1.SEQUENCE) ||
+		!encryptedContentInfo.ReadASN1ObjectIdentifier(&contentType) ||
+		!encryptedContentInfo.ReadASN1Element(&algorithm, cryptobyte_asn1.SEQUENCE) ||
+		!encryptedContentInfo.ReadASN1Bytes(&encryptedContent, cryptobyte_asn1.Tag(0).ContextSpecific()) ||
+		!encryptedContentInfo.Empty() {
+		return nil, errors.New("x509: malformed PKCS #12 encrypted data")
+	}
+	if !contentType.Equal(oidPKCS7Data) {
+		return nil, errors.New("x509: unsupported PKCS #12 encrypted content type")
+	}
+	return pkcs12Decrypt(algorithm, encryptedContent, password, work)
+}
+
+// pkcs12MaxNesting limits the depth of nested SafeContents bags.
+const pkcs12MaxNesting = 4
+
+func (p *PKCS12) parseSafeContents(der cryptobyte.String, password string, work *pkcs12Work, depth int) error {
+	var bags cryptobyte.String
+	if !der.ReadASN1(&bags, cryptobyte_asn1.SEQUENCE) || !der.Empty() {
+		return errors.New("x509: malformed PKCS #12 safe contents")
+	}
+	for !bags.Empty() {
+		var bag, value, attributes cryptobyte.String
+		var bagID asn1.ObjectIdentifier
+		if !bags.ReadASN1(&bag, cryptobyte_asn1.SEQUENCE) ||
+			!bag.ReadASN1ObjectIdentifier(&bagID) ||
+			!bag.ReadASN1(&value, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) ||
+			!bag.ReadOptionalASN1(&attributes, nil, cryptobyte_asn1.SET) ||
+			!bag.Empty() {
+			return errors.New("x509: malformed PKCS #12 safe bag")
+		}
+		friendlyName, localKeyID, err := parsePKCS12Attributes(attributes)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case bagID.Equal(oidCertBag):
+			var certBag, certValue cryptobyte.String
+			var certID asn1.ObjectIdentifier
+			var der []byte
+			if !value.ReadASN1(&certBag, cryptobyte_asn1.SEQUENCE) || !value.Empty() ||
+				!certBag.ReadASN1ObjectIdentifier(&certID) ||
+				!certBag.ReadASN1(&certValue, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) ||
+				!certBag.Empty() {
+				return errors.New("x509: malformed PKCS #12 certificate bag")
+			}
+			if !certID.Equal(oidX509CertificateBag) {
+				continue
+			}
+			if !certValue.ReadASN1Bytes(&der, cryptobyte_asn1.OCTET_STRING) || !certValue.Empty() {
+				return errors.New("x509: malformed PKCS #12 certificate bag")
+			}
+			cert, err := ParseCertificate(der)
+			if err != nil {
+				return err
+			}
+			p.Certificates = append(p.Certificates, PKCS12Certificate{
+				Certificate:  cert,
+				FriendlyName: friendlyName,
+				LocalKeyID:   localKeyID,
+			})
+
+		case bagID.Equal(oidKeyBag), bagID.Equal(oidPKCS8ShroudedKeyBag):
+			var keyBag cryptobyte.String
+			if !value.ReadASN1Element(&keyBag, cryptobyte_asn1.SEQUENCE) || !value.Empty() {
+				return errors.New("x509: malformed PKCS #12 key bag")
+			}
+			der := []byte(keyBag)
+			if bagID.Equal(oidPKCS8ShroudedKeyBag) {
+				var encryptedKeyInfo, algorithm cryptobyte.String
+				var encryptedKey []byte
+				if !keyBag.ReadASN1(&encryptedKeyInfo, cryptobyte_asn1.SEQUENCE) ||
+					!encryptedKeyInfo.ReadASN1Element(&algorithm, cryptobyte_asn1.SEQUENCE) ||
+					!encryptedKeyInfo.ReadASN1Bytes(&encryptedKey, cryptobyte_asn1.OCTET_STRING) ||
+					!encryptedKeyInfo.Empty() {
+					return errors.New("x509: malformed PKCS #12 shrouded key bag")
+				}
+				der, err = pkcs12Decrypt(algorithm, encryptedKey, password, work)
+				if err != nil {
+					return err
+				}
+			}
+			key, err := ParsePKCS8PrivateKey(der)
+			if err != nil {
+				// Without a MAC, an incorrect password is likely to be
+				// detected only here.
+				return errors.New("x509: failed to parse PKCS #12 private key, possibly due to an incorrect password: " + err.Error())
+			}
+			p.PrivateKeys = append(p.PrivateKeys, PKCS12PrivateKey{
+				Key:          key,
+				FriendlyName: friendlyName,
+				LocalKeyID:   localKeyID,
+			})
+
+		case bagID.Equal(oidSafeContentsBag):
+			if depth >= pkcs12MaxNesting {
+				return errors.New("x509: too many nested PKCS #12 safe contents")
+			}
+			if err := p.parseSafeContents(value, password, work, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parsePKCS12Attributes(attributes cryptobyte.String) (friendlyName string, localKeyID []byte, err error) {
+	for !attributes.Empty() {
+		var attribute, values cryptobyte.String
+		var attrID asn1.ObjectIdentifier
+		if !attributes.ReadASN1(&attribute, cryptobyte_asn1.SEQUENCE) ||
+			!attribute.ReadASN1ObjectIdentifier(&attrID) ||
+			!attribute.ReadASN1(&values, cryptobyte_asn1.SET) ||
+			!attribute.Empty() {
+			return "", nil, errors.New("x509: malformed PKCS #12 attribute")
+		}
+		switch {
+		case attrID.Equal(oidFriendlyName):
+			var name cryptobyte.String
+			if !values.ReadASN1(&name, cryptobyte_asn1.Tag(30)) || !values.Empty() { // BMPString
+				return "", nil, errors.New("x509: malformed PKCS #12 friendly name")
+			}
+			friendlyName, err = parseBMPString(name)
+			if err != nil {
+				return "", nil, err
+			}
+		case attrID.Equal(oidLocalKeyID):
+			if !values.ReadASN1Bytes(&localKeyID, cryptobyte_asn1.OCTET_STRING) || !values.Empty() {
+				return "", nil, errors.New("x509: malformed PKCS #12 local key ID")
+			}
+		}
+	}
+	return friendlyName, localKeyID, nil
+}
+
+// pkcs12Decrypt decrypts ciphertext with the password-based encryption scheme
+// identified by the AlgorithmIdentifier in algorithm.
+func pkcs12Decrypt(algorithm cryptobyte.String, ciphertext []byte, password string, work *pkcs12Work) ([]byte, error) {
+	var algo asn1.ObjectIdentifier
+	var algorithmID, params cryptobyte.String
+	if !algorithm.ReadASN1(&algorithmID, cryptobyte_asn1.SEQUENCE) || !algorithm.Empty() ||
+		!algorithmID.ReadASN1ObjectIdentifier(&algo) ||
+		!algorithmID.ReadASN1(&params, cryptobyte_asn1.SEQUENCE) ||
+		!algorithmID.Empty() {
+		return nil, errors.New("x509: malformed PKCS #12 encryption algorithm")
+	}
+
+	var block cipher.Block
+	var iv []byte
+	switch {
+	case algo.Equal(oidPBES2):
+		var err error
+		block, iv, err = pbes2Cipher(params, password, work)
+		if err != nil {
+			return nil, err
+		}
+
+	case algo.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC), algo.Equal(oidPBEWithSHAAnd2KeyTripleDESCBC),
+		algo.Equal(oidPBEWithSHAAnd128BitRC2CBC), algo.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+		var salt []byte
+		var iterations int
+		if !params.ReadASN1Bytes(&salt, cryptobyte_asn1.OCTET_STRING) ||
+			!params.ReadASN1Integer(&iterations) || !params.Empty() {
+			return nil, errors.New("x509: malformed PKCS #12 encryption parameters")
+		}
+		if iterations <= 0 || iterations > pkcs12MaxIterations {
+			return nil, errors.New("x509: invalid PKCS #12 iteration count")
+		}
+		// The key and the IV are derived separately.
+		if err := work.spend(2 * iterations); err != nil {
+			return nil, err
+		}
+		bmpPassword, err := bmpStringZeroTerminated(password)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case algo.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC):
+			key := pkcs12KDF(sha1.New, bmpPassword, salt, iterations, 1, 24)
+			block, _ = des.NewTripleDESCipher(key)
+		case algo.Equal(oidPBEWithSHAAnd2KeyTripleDESCBC):
+			key := pkcs12KDF(sha1.New, bmpPassword, salt, iterations, 1, 16)
+			block, _ = des.NewTripleDESCipher(append(key, key[:8]...))
+		case algo.Equal(oidPBEWithSHAAnd128BitRC2CBC):
+			key := pkcs12KDF(sha1.New, bmpPassword, salt, iterations, 1, 16)
+			block = newRC2Cipher(key, 128)
+		case algo.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+			key := pkcs12KDF(sha1.New, bmpPassword, salt, iterations, 1, 5)
+			block = newRC2Cipher(key, 40)
+		}
+		iv = pkcs12KDF(sha1.New, bmpPassword, salt, iterations, 2, block.BlockSize())
+
+	default:
+		return nil, errors.New("x509: unsupported PKCS #12 encryption algorithm " + algo.String())
+	}
+
+	if len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, errors.New("x509: invalid PKCS #12 encrypted data length")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// Remove the PKCS #7 padding. An invalid padding is most likely caused
+	// by an incorrect password.
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > block.BlockSize() {
+		return nil, IncorrectPasswordError
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return nil, IncorrectPasswordError
+		}
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
+
+// pbes2Cipher returns the block cipher and IV for the PBES2 parameters in
+// params. See RFC 8018, Appendix A.4.
+func pbes2Cipher(params cryptobyte.String, password string, work *pkcs12Work) (cipher.Block, []byte, error) {
+	var kdf, scheme cryptobyte.String
+	var schemeOID asn1.ObjectIdentifier
+	var iv []byte
+	if !params.ReadASN1Element(&kdf, cryptobyte_asn1.SEQUENCE) ||
+		!params.ReadASN1(&scheme, cryptobyte_asn1.SEQUENCE) || !params.Empty() ||
+		!scheme.ReadASN1ObjectIdentifier(&schemeOID) ||
+		!scheme.ReadASN1Bytes(&iv, cryptobyte_asn1.OCTET_STRING) || !scheme.Empty() {
+		return nil, nil, errors.New("x509: malformed PBES2 parameters")
+	}
+
+	var keyLen int
+	switch {
+	case schemeOID.Equal(oidAES128CBC):
+		keyLen = 16
+	case schemeOID.Equal(oidAES192CBC):
+		keyLen = 24
+	case schemeOID.Equal(oidAES256CBC):
+		keyLen = 32
+	case schemeOID.Equal(oidDESEDE3CBC):
+		keyLen = 24
+	default:
+		return nil, nil, errors.New("x509: unsupported PBES2 encryption scheme " + schemeOID.String())
+	}
+
+	key, err := pbkdf2Key(kdf, password, keyLen, work)
+	if err != nil {
+		return nil, nil, err
+	}
+	var block cipher.Block
+	if schemeOID.Equal(oidDESEDE3CBC) {
+		block, err = des.NewTripleDESCipher(key)
+	} else {
+		block, err = aes.NewCipher(key)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, nil, errors.New("x509: invalid PBES2 IV length")
+	}
+	return block, iv, nil
+}
+
+// pbkdf2Key derives a key of keyLen bytes from password with the PBKDF2
+// AlgorithmIdentifier in kdf. See RFC 8018, Appendix A.2.
+func pbkdf2Key(kdf cryptobyte.String, password string, keyLen int, work *pkcs12Work) ([]byte, error) {
+	var kdfOID asn1.ObjectIdentifier
+	var kdfID, kdfParams, prf cryptobyte.String
+	var salt []byte
+	var iterations int
+	var hasPRF bool
+	if !kdf.ReadASN1(&kdfID, cryptobyte_asn1.SEQUENCE) || !kdf.Empty() ||
+		!kdfID.ReadASN1ObjectIdentifier(&kdfOID) ||
+		!kdfID.ReadASN1(&kdfParams, cryptobyte_asn1.SEQUENCE) || !kdfID.Empty() {
+		return nil, errors.New("x509: malformed PBKDF2 parameters")
+	}
+	if !kdfOID.Equal(oidPBKDF2) {
+		return nil, errors.New("x509: unsupported key derivation function " + kdfOID.String())
+	}
+	if !kdfParams.ReadASN1Bytes(&salt, cryptobyte_asn1.OCTET_STRING) ||
+		!kdfParams.ReadASN1Integer(&iterations) {
+		return nil, errors.New("x509: malformed PBKDF2 parameters")
+	}
+	if kdfParams.PeekASN1Tag(cryptobyte_asn1.INTEGER) {
+		var explicitKeyLen int
+		if !kdfParams.ReadASN1Integer(&explicitKeyLen) {
+			return nil, errors.New("x509: malformed PBKDF2 parameters")
+		}
+		if explicitKeyLen != keyLen {
+			return nil, errors.New("x509: invalid PBKDF2 key length")
+		}
+	}
+	if !kdfParams.ReadOptionalASN1(&prf, &hasPRF, cryptobyte_asn1.SEQUENCE) || !kdfParams.Empty() {
+		return nil, errors.New("x509: malformed PBKDF2 parameters")
+	}
+	if iterations <= 0 || iterations > pkcs12MaxIterations {
+		return nil, errors.New("x509: invalid PBKDF2 iteration count")
+	}
+	if err := work.spend(iterations); err != nil {
+		return nil, err
+	}
+
+	h := sha1.New
+	if hasPRF {
+		var prfOID asn1.ObjectIdentifier
+		if !prf.ReadASN1ObjectIdentifier(&prfOID) {
+			return nil, errors.New("x509: malformed PBKDF2 parameters")
+		}
+		// The parameters must be NULL or absent.
+		var null cryptobyte.String
+		if !prf.Empty() && (!prf.ReadASN1(&null, cryptobyte_asn1.NULL) || !prf.Empty()) {
+			return nil, errors.New("x509: malformed PBKDF2 parameters")
+		}
+		h = hmacHash(prfOID)
+		if h == nil {
+			return nil, errors.New("x509: unsupported PBKDF2 pseudorandom function " + prfOID.String())
+		}
+	}
+	return pbkdf2.Key(h, password, salt, iterations, keyLen)
+}
+
+// hmacHash returns the hash function of the HMAC identified by oid.
+func hmacHash(oid asn1.ObjectIdentifier) func() hash.Hash {
+	switch {
+	case oid.Equal(oidHMACWithSHA1):
+		return sha1.New
+	case oid.Equal(oidHMACWithSHA256):
+		return sha256.New
+	case oid.Equal(oidHMACWithSHA384):
+		return sha512.New384
+	case oid.Equal(oidHMACWithSHA512):
+		return sha512.New
+	}
+	return nil
+}
+
+// verifyPKCS12MAC verifies the MacData structure in macData over data.
+func verifyPKCS12MAC(macData cryptobyte.String, data []byte, password string, work *pkcs12Work) error {
+	var digestInfo, algorithm cryptobyte.String
+	var algo asn1.ObjectIdentifier
+	var digest, salt []byte
+	iterations := 1
+	if !macData.ReadASN1(&digestInfo, cryptobyte_asn1.SEQUENCE) ||
+		!digestInfo.ReadASN1(&algorithm, cryptobyte_asn1.SEQUENCE) ||
+		!digestInfo.ReadASN1Bytes(&digest, cryptobyte_asn1.OCTET_STRING) || !digestInfo.Empty() ||
+		!algorithm.ReadASN1ObjectIdentifier(&algo) ||
+		!macData.ReadASN1Bytes(&salt, cryptobyte_asn1.OCTET_STRING) {
+		return errors.New("x509: malformed PKCS #12 MAC")
+	}
+	if !macData.Empty() && (!macData.ReadASN1Integer(&iterations) || !macData.Empty()) {
+		return errors.New("x509: malformed PKCS #12 MAC")
+	}
+	if iterations <= 0 || iterations > pkcs12MaxIterations {
+		return errors.New("x509: invalid PKCS #12 MAC iteration count")
+	}
+	// PBMAC1 ignores the salt and iterations of MacData. See RFC 9579.
+	if algo.Equal(oidPBMAC1) {
+		var pbmac1, kdf, scheme cryptobyte.String
+		var schemeOID asn1.ObjectIdentifier
+		if !algorithm.ReadASN1(&pbmac1, cryptobyte_asn1.SEQUENCE) || !algorithm.Empty() ||
+			!pbmac1.ReadASN1Element(&kdf, cryptobyte_asn1.SEQUENCE) ||
+			!pbmac1.ReadASN1(&scheme, cryptobyte_asn1.SEQUENCE) || !pbmac1.Empty() ||
+			!scheme.ReadASN1ObjectIdentifier(&schemeOID) {
+			return errors.New("x509: malformed PBMAC1 parameters")
+		}
+		h := hmacHash(schemeOID)
+		if h == nil {
+			return errors.New("x509: unsupported PBMAC1 message authentication scheme " + schemeOID.String())
+		}
+		key, err := pbkdf2Key(kdf, password, h().Size(), work)
+		if err != nil {
+			return err
+		}
+		mac := hmac.New(h, key)
+		mac.Write(data)
+		if !hmac.Equal(mac.Sum(nil), digest) {
+			return IncorrectPasswordError
+		}
+		return nil
+	}
+
+	var h func() hash.Hash
+	switch {
+	case algo.Equal(oidSHA1):
+		h = sha1.New
+	case algo.Equal(oidSHA256):
+		h = sha256.New
+	case algo.Equal(oidSHA384):
+		h = sha512.New384
+	case algo.Equal(oidSHA512):
+		h = sha512.New
+	default:
+		return errors.New("x509: unsupported PKCS #12 MAC algorithm " + algo.String())
+	}
+	if err := work.spend(iterations); err != nil {
+		return err
+	}
+	bmpPassword, err := bmpStringZeroTerminated(password)
+	if err != nil {
+		return err
+	}
+	check := func(password []byte) bool {
+		key := pkcs12KDF(h, password, salt, iterations, 3, h().Size())
+		mac := hmac.New(h, key)
+		mac.Write(data)
+		return hmac.Equal(mac.Sum(nil), digest)
+	}
+	// Some implementations encode the empty password as an empty string
+	// rather than as a single NUL character.
+	if !check(bmpPassword) && (password != "" || !check(nil)) {
+		return IncorrectPasswordError
+	}
+	return nil
+}
+
+// pkcs12KDF implements the PKCS #12 key derivation function, specified in
+// RFC 7292, Appendix B.2, with the given purpose ID.
+func pkcs12KDF(h func() hash.Hash, password, salt []byte, iterations int, id byte, size int) []byte {
+	u := h().Size()
+	v := h().BlockSize()
+
+	fill := func(in []byte) []byte {
+		if len(in) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(in)+v-1)/v))
+		for i := range out {
+			out[i] = in[i%len(in)]
+		}
+		return out
+	}
+	d := bytes.Repeat([]byte{id}, v)
+	i := append(fill(salt), fill(password)...)
+
+	out := make([]byte, 0, size+u)
+	for len(out) < size {
+		hh := h()
+		hh.Write(d)
+		hh.Write(i)
+		a := hh.Sum(nil)
+		for range iterations - 1 {
+			hh.Reset()
+			hh.Write(a)
+			a = hh.Sum(a[:0])
+		}
+		out = append(out, a...)
+		if len(out) >= size {
+			break
+		}
+
+		// Set each v-byte block I_j of I to (I_j + B + 1) mod 2^(8v), where B
+		// is A repeated to v bytes.
+		b := fill(a)
+		for j := 0; j < len(i); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				carry += int(i[j+k]) + int(b[k])
+				i[j+k] = byte(carry)
+				carry >>= 8
+			}
+		}
+	}
+	return out[:size]
+}
+
+// bmpStringZeroTerminated returns s encoded as a big-endian UTF-16 string
+// with a trailing NUL, as used for passwords by the PKCS #12 key derivation
+// function.
+func bmpStringZeroTerminated(s string) ([]byte, error) {
+	b, err := bmpString(s)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, 0, 0), nil
+}
+
+// bmpString returns s encoded as a big-endian UTF-16 BMPString, which can't
+// represent characters outside the Basic Multilingual Plane.
+func bmpString(s string) ([]byte, error) {
+	var b []byte
+	for _, r := range s {
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			return nil, errors.New("x509: string contains characters that can't be encoded as a BMPString")
+		}
+		b = append(b, byte(r>>8), byte(r))
+	}
+	return b, nil
+}
+
+func parseBMPString(b []byte) (string, error) {
+	if len(b)%2 != 0 {
+		return "", errors.New("x509: invalid BMPString")
+	}
+	// Strip a terminating NUL character, which some implementations include.
+	if l := len(b); l >= 2 && b[l-1] == 0 && b[l-2] == 0 {
+		b = b[:l-2]
+	}
+	s := make([]uint16, 0, len(b)/2)
+	for len(b) > 0 {
+		s = append(s, uint16(b[0])<<8|uint16(b[1]))
+		b = b[2:]
+	}
+	return string(utf16.Decode(s)), nil
+}
+
+// MarshalPKCS12 encodes p as a PKCS #12 file in DER form, protected with
+// password, as specified in RFC 7292.
+//
+// Private keys are encrypted in shrouded key bags, and certificates in an
+// encrypted safe, unless opts.PlaintextCertificates is set. Encryption uses
+// PBES2 with PBKDF2-HMAC-SHA256 and AES-256-CBC, and the integrity MAC uses
+// HMAC-SHA256. These are the OpenSSL 3 defaults, and they are supported by
+// most modern implementations. opts may be nil.
+//
+// rand is used as a source of entropy for the salts and IVs.
+func MarshalPKCS12(rand io.Reader, p *PKCS12, password string, opts *PKCS12Options) ([]byte, error) {
+	iterations := pkcs12DefaultIterations
+	if opts != nil && opts.Iterations != 0 {
+		iterations = opts.Iterations
+	}
+	if iterations < 0 || iterations > pkcs12MaxIterations {
+		return nil, errors.New("x509: invalid PKCS #12 iteration count")
+	}
+	bmpPassword, err := bmpStringZeroTerminated(password)
+	if err != nil {
+		return nil, err
+	}
+
+	var certBags cryptobyte.Builder
+	certBags.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		for _, c := range p.Certificates {
+			if c.Certificate == nil {
+				b.SetError(errors.New("x509: PKCS #12 certificate is nil"))
+				return
+			}
+			marshalPKCS12Bag(b, oidCertBag, c.FriendlyName, c.LocalKeyID, func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1ObjectIdentifier(oidX509CertificateBag)
+					b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+						b.AddASN1OctetString(c.Certificate.Raw)
+					})
+				})
+			})
+		}
+	})
+	certSafe, err := certBags.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var keyBags cryptobyte.Builder
+	keyBags.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		for _, k := range p.PrivateKeys {
+			der, err := MarshalPKCS8PrivateKey(k.Key)
+			if err != nil {
+				b.SetError(err)
+				return
+			}
+			algorithm, encrypted, err := pbes2Encrypt(rand, der, password, iterations)
+			if err != nil {
+				b.SetError(err)
+				return
+			}
+			marshalPKCS12Bag(b, oidPKCS8ShroudedKeyBag, k.FriendlyName, k.LocalKeyID, func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddBytes(algorithm)
+					b.AddASN1OctetString(encrypted)
+				})
+			})
+		}
+	})
+	keySafe, err := keyBags.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var authSafeBuilder cryptobyte.Builder
+	authSafeBuilder.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		if len(p.Certificates) > 0 {
+			if opts != nil && opts.PlaintextCertificates {
+				marshalPKCS12ContentInfo(b, oidPKCS7Data, func(b *cryptobyte.Builder) {
+					b.AddASN1OctetString(certSafe)
+				})
+			} else {
+				algorithm, encrypted, err := pbes2Encrypt(rand, certSafe, password, iterations)
+				if err != nil {
+					b.SetError(err)
+					return
+				}
+				marshalPKCS12ContentInfo(b, oidPKCS7EncryptedData, func(b *cryptobyte.Builder) {
+					b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+						b.AddASN1Int64(0) // version
+						b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+							b.AddASN1ObjectIdentifier(oidPKCS7Data)
+							b.AddBytes(algorithm)
+							b.AddASN1(cryptobyte_asn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+								b.AddBytes(encrypted)
+							})
+						})
+					})
+				})
+			}
+		}
+		if len(p.PrivateKeys) > 0 {
+			marshalPKCS12ContentInfo(b, oidPKCS7Data, func(b *cryptobyte.Builder) {
+				b.AddASN1OctetString(keySafe)
+			})
+		}
+	})
+	authSafe, err := authSafeBuilder.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand, salt); err != nil {
+		return nil, err
+	}
+	key := pkcs12KDF(sha256.New, bmpPassword, salt, iterations, 3, sha256.Size)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(authSafe)
+
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(3) // version
+		marshalPKCS12ContentInfo(b, oidPKCS7Data, func(b *cryptobyte.Builder) {
+			b.AddASN1OctetString(authSafe)
+		})
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1ObjectIdentifier(oidSHA256)
+					b.AddASN1NULL()
+				})
+				b.AddASN1OctetString(mac.Sum(nil))
+			})
+			b.AddASN1OctetString(salt)
+			b.AddASN1Int64(int64(iterations))
+		})
+	})
+	return b.Bytes()
+}
+
+func marshalPKCS12ContentInfo(b *cryptobyte.Builder, contentType asn1.ObjectIdentifier, content cryptobyte.BuilderContinuation) {
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(contentType)
+		b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), content)
+	})
+}
+
+func marshalPKCS12Bag(b *cryptobyte.Builder, bagID asn1.ObjectIdentifier, friendlyName string, localKeyID []byte, value cryptobyte.BuilderContinuation) {
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(bagID)
+		b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), value)
+		if friendlyName == "" && localKeyID == nil {
+			return
+		}
+		name, err := bmpString(friendlyName)
+		if err != nil {
+			b.SetError(err)
+			return
+		}
+		b.AddASN1(cryptobyte_asn1.SET, func(b *cryptobyte.Builder) {
+			// The attributes are DER-sorted by encoding, and the encoding of
+			// the friendlyName OID sorts before that of localKeyID.
+			if friendlyName != "" {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1ObjectIdentifier(oidFriendlyName)
+					b.AddASN1(cryptobyte_asn1.SET, func(b *cryptobyte.Builder) {
+						b.AddASN1(cryptobyte_asn1.Tag(30), func(b *cryptobyte.Builder) {
+							b.AddBytes(name)
+						})
+					})
+				})
+			}
+			if localKeyID != nil {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1ObjectIdentifier(oidLocalKeyID)
+					b.AddASN1(cryptobyte_asn1.SET, func(b *cryptobyte.Builder) {
+						b.AddASN1OctetString(localKeyID)
+					})
+				})
+			}
+		})
+	})
+}
+
+// pbes2Encrypt encrypts plaintext with PBES2, using PBKDF2-HMAC-SHA256 and
+// AES-256-CBC, and returns the AlgorithmIdentifier and the ciphertext.
+func pbes2Encrypt(rand io.Reader, plaintext []byte, password string, iterations int) (algorithm, ciphertext []byte, err error) {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand, salt); err != nil {
+		return nil, nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand, iv); err != nil {
+		return nil, nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext = make([]byte, len(plaintext)+pad)
+	copy(ciphertext, plaintext)
+	copy(ciphertext[len(plaintext):], bytes.Repeat([]byte{byte(pad)}, pad))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oidPBES2)
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1ObjectIdentifier(oidPBKDF2)
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1OctetString(salt)
+					b.AddASN1Int64(int64(iterations))
+					b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+						b.AddASN1ObjectIdentifier(oidHMACWithSHA256)
+						b.AddASN1NULL()
+					})
+				})
+			})
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1ObjectIdentifier(oidAES256CBC)
+				b.AddASN1OctetString(iv)
+			})
+		})
+	})
+	algorithm, err = b.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	return algorithm, ciphertext, nil
+}
@@ -0,0 +1,300 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+func TestParsePKCS12(t *testing.T) {
+	for _, tt := range []struct {
+		file         string
+		certs        int
+		friendlyName string
+	}{
+		// OpenSSL 3 defaults: PBES2 with AES-256-CBC, and an HMAC-SHA256 MAC.
+		{"pkcs12_modern.p12", 2, "leaf ☃"},
+		// openssl pkcs12 -legacy: 40-bit RC2 and 3DES, and an HMAC-SHA1 MAC.
+		{"pkcs12_legacy.p12", 2, "leaf"},
+	} {
+		t.Run(tt.file, func(t *testing.T) {
+			der, err := os.ReadFile("testdata/" + tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := ParsePKCS12(der, "password")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(p.Certificates) != tt.certs || len(p.PrivateKeys) != 1 {
+				t.Fatalf("got %d certificates and %d keys, want %d and 1",
+					len(p.Certificates), len(p.PrivateKeys), tt.certs)
+			}
+			leaf, key := p.Certificates[0], p.PrivateKeys[0]
+			if leaf.Certificate.Subject.CommonName != "leaf.example" {
+				t.Errorf("unexpected leaf certificate %v", leaf.Certificate.Subject)
+			}
+			if tt.certs > 1 && p.Certificates[1].Certificate.Subject.CommonName != "PKCS12 Test CA" {
+				t.Errorf("unexpected CA certificate %v", p.Certificates[1].Certificate.Subject)
+			}
+			if !key.Key.(*ecdsa.PrivateKey).PublicKey.Equal(leaf.Certificate.PublicKey) {
+				t.Error("private key doesn't match the leaf certificate")
+			}
+			if leaf.LocalKeyID == nil || !bytes.Equal(leaf.LocalKeyID, key.LocalKeyID) {
+				t.Errorf("LocalKeyID = %x and %x, want equal", leaf.LocalKeyID, key.LocalKeyID)
+			}
+			if leaf.FriendlyName != tt.friendlyName || key.FriendlyName != tt.friendlyName {
+				t.Errorf("FriendlyName = %q and %q, want %q", leaf.FriendlyName, key.FriendlyName, tt.friendlyName)
+			}
+
+			if _, err := ParsePKCS12(der, "wrong"); err != IncorrectPasswordError {
+				t.Errorf("got %v with the wrong password, want IncorrectPasswordError", err)
+			}
+			if _, err := ParsePKCS12(der[:len(der)-1], "password"); err == nil {
+				t.Error("ParsePKCS12 succeeded with a truncated file")
+			}
+		})
+	}
+}
+
+func testPKCS12Contents(t *testing.T) *PKCS12 {
+	t.Helper()
+	p := new(PKCS12)
+	for i, name := range []string{"first", "sécond"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &Certificate{
+			SerialNumber: big.NewInt(int64(i + 1)),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := []byte{byte(i)}
+		p.Certificates = append(p.Certificates, PKCS12Certificate{Certificate: cert, FriendlyName: name, LocalKeyID: id})
+		p.PrivateKeys = append(p.PrivateKeys, PKCS12PrivateKey{Key: key, FriendlyName: name, LocalKeyID: id})
+	}
+	// A certificate without attributes.
+	p.Certificates = append(p.Certificates, PKCS12Certificate{Certificate: p.Certificates[0].Certificate})
+	return p
+}
+
+func TestMarshalPKCS12(t *testing.T) {
+	p := testPKCS12Contents(t)
+	for _, tt := range []struct {
+		name     string
+		password string
+		opts     *PKCS12Options
+	}{
+		{"Default", "password", nil},
+		{"Iterations", "pässwörd", &PKCS12Options{Iterations: 10}},
+		{"PlaintextCertificates", "password", &PKCS12Options{Iterations: 10, PlaintextCertificates: true}},
+		{"EmptyPassword", "", &PKCS12Options{Iterations: 10}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := MarshalPKCS12(rand.Reader, p, tt.password, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParsePKCS12(der, tt.password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, p) {
+				t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, p)
+			}
+			if _, err := ParsePKCS12(der, tt.password+"x"); err != IncorrectPasswordError {
+				t.Errorf("got %v with the wrong password, want IncorrectPasswordError", err)
+			}
+		})
+	}
+
+	if _, err := MarshalPKCS12(rand.Reader, &PKCS12{PrivateKeys: []PKCS12PrivateKey{{Key: "not a key"}}}, "", nil); err == nil {
+		t.Error("MarshalPKCS12 succeeded with an invalid key")
+	}
+	if _, err := MarshalPKCS12(rand.Reader, p, "\U0001F600", nil); err == nil {
+		t.Error("MarshalPKCS12 succeeded with a password outside the BMP")
+	}
+}
+
+func TestParsePKCS12PBMAC1(t *testing.T) {
+	p := testPKCS12Contents(t)
+	der, err := MarshalPKCS12(rand.Reader, p, "password", &PKCS12Options{Iterations: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the MAC with a PBMAC1 one, as specified in RFC 9579.
+	input := cryptobyte.String(der)
+	var pfx, authSafe cryptobyte.String
+	var version int64
+	if !input.ReadASN1(&pfx, cryptobyte_asn1.SEQUENCE) || !pfx.ReadASN1Integer(&version) ||
+		!pfx.ReadASN1Element(&authSafe, cryptobyte_asn1.SEQUENCE) {
+		t.Fatal("failed to parse PKCS #12 file")
+	}
+	_, content, err := parsePKCS12ContentInfo(authSafe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []byte
+	if !content.ReadASN1Bytes(&data, cryptobyte_asn1.OCTET_STRING) {
+		t.Fatal("failed to parse authenticated safe")
+	}
+
+	withPBMAC1 := func(password string) []byte {
+		salt := []byte("saltsaltsaltsalt")
+		key, err := pbkdf2.Key(sha256.New, password, salt, 10, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		var b cryptobyte.Builder
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1Int64(version)
+			b.AddBytes(authSafe)
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+						b.AddASN1ObjectIdentifier(oidPBMAC1)
+						b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+							b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+								b.AddASN1ObjectIdentifier(oidPBKDF2)
+								b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+									b.AddASN1OctetString(salt)
+									b.AddASN1Int64(10)
+									b.AddASN1Int64(32)
+									b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+										b.AddASN1ObjectIdentifier(oidHMACWithSHA256)
+									})
+								})
+							})
+							b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+								b.AddASN1ObjectIdentifier(oidHMACWithSHA256)
+							})
+						})
+					})
+					b.AddASN1OctetString(mac.Sum(nil))
+				})
+				// The MacData salt and iterations are ignored.
+				b.AddASN1OctetString([]byte("ignored"))
+				b.AddASN1Int64(1)
+			})
+		})
+		return b.BytesOrPanic()
+	}
+
+	got, err := ParsePKCS12(withPBMAC1("password"), "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Error("round trip mismatch")
+	}
+	if _, err := ParsePKCS12(withPBMAC1("wrong"), "password"); err != IncorrectPasswordError {
+		t.Errorf("got %v with the wrong MAC key, want IncorrectPasswordError", err)
+	}
+}
+
+func TestParsePKCS12NoMAC(t *testing.T) {
+	// openssl pkcs12 -nomac
+	der, err := os.ReadFile("testdata/pkcs12_nomac.p12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParsePKCS12(der, "password"); err == nil {
+		t.Error("ParsePKCS12 accepted a password protected file without a MAC")
+	}
+
+	p := testPKCS12Contents(t)
+	der, err = MarshalPKCS12(rand.Reader, p, "", &PKCS12Options{Iterations: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := cryptobyte.String(der)
+	var pfx, authSafe cryptobyte.String
+	var version int64
+	if !input.ReadASN1(&pfx, cryptobyte_asn1.SEQUENCE) || !pfx.ReadASN1Integer(&version) ||
+		!pfx.ReadASN1Element(&authSafe, cryptobyte_asn1.SEQUENCE) {
+		t.Fatal("failed to parse PKCS #12 file")
+	}
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(version)
+		b.AddBytes(authSafe)
+	})
+	got, err := ParsePKCS12(b.BytesOrPanic(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Error("round trip mismatch")
+	}
+}
+
+func TestPKCS12TotalIterations(t *testing.T) {
+	algorithm, ciphertext, err := pbes2Encrypt(rand.Reader, []byte("plaintext"), "password", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := &pkcs12Work{iterations: pkcs12MaxTotalIterations - 10}
+	if _, err := pkcs12Decrypt(algorithm, ciphertext, "password", work); err != nil {
+		t.Fatalf("pkcs12Decrypt failed within the budget: %v", err)
+	}
+	if _, err := pkcs12Decrypt(algorithm, ciphertext, "password", work); err == nil {
+		t.Error("pkcs12Decrypt succeeded past the budget")
+	}
+}
+
+func TestRC2Decrypt(t *testing.T) {
+	// Test vectors from RFC 2268, Section 5.
+	for _, tt := range []struct {
+		key           string
+		effectiveBits int
+		plaintext     string
+		ciphertext    string
+	}{
+		{"0000000000000000", 63, "0000000000000000", "ebb773f993278eff"},
+		{"ffffffffffffffff", 64, "ffffffffffffffff", "278b27e42e2f0d49"},
+		{"3000000000000000", 64, "1000000000000001", "30649edf9be7d2c2"},
+		{"88", 64, "0000000000000000", "61a8a244adacccf0"},
+		{"88bca90e90875a", 64, "0000000000000000", "6ccf4308974c267f"},
+		{"88bca90e90875a7f0f79c384627bafb2", 64, "0000000000000000", "1a807d272bbe5db1"},
+		{"88bca90e90875a7f0f79c384627bafb2", 128, "0000000000000000", "2269552ab0f85ca6"},
+	} {
+		key, _ := hex.DecodeString(tt.key)
+		ciphertext, _ := hex.DecodeString(tt.ciphertext)
+		got := make([]byte, rc2BlockSize)
+		newRC2Cipher(key, tt.effectiveBits).Decrypt(got, ciphertext)
+		if hex.EncodeToString(got) != tt.plaintext {
+			t.Errorf("RC2(%s, %d): got %x, want %s", tt.key, tt.effectiveBits, got, tt.plaintext)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"crypto/mldsa"
 	"crypto/mlkem"
 	"crypto/rsa"
+	"crypto/slhdsa"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"errors"
@@ -30,9 +31,9 @@ type pkcs8 struct {
 // ParsePKCS8PrivateKey parses an unencrypted private key in PKCS #8, ASN.1 DER form.
 //
 // It returns a *[rsa.PrivateKey], an *[ecdsa.PrivateKey], an [ed25519.PrivateKey]
-// (not a pointer), a *[mldsa.PrivateKey], an *[ecdh.PrivateKey] (for X25519), a
-// *[mlkem.DecapsulationKey768], or a *[mlkem.DecapsulationKey1024]. More types
-// might be supported in the future.
+// (not a pointer), a *[mldsa.PrivateKey], a *[slhdsa.PrivateKey], an
+// *[ecdh.PrivateKey] (for X25519), a *[mlkem.DecapsulationKey768], or a
+// *[mlkem.DecapsulationKey1024]. More types might be supported in the future.
 //
 // This kind of key is commonly encoded in PEM blocks of type "PRIVATE KEY".
 //
@@ -112,6 +113,19 @@ func ParsePKCS8PrivateKey(der []byte) (key any, err error) {
 		}
 		return mldsa.NewPrivateKey(params, privKey.PrivateKey[2:])
 
+	case isSLHDSAOID(privKey.Algo.Algorithm):
+		if l := len(privKey.Algo.Parameters.FullBytes); l != 0 {
+			return nil, errors.New("x509: invalid SLH-DSA private key parameters")
+		}
+		// Unlike ML-DSA, the RFC 9909 privateKey OCTET STRING is the raw
+		// SK.seed || SK.prf || PK.seed || PK.root encoding, with no ASN.1
+		// structure inside it.
+		params, _ := slhdsaParametersFromOID(privKey.Algo.Algorithm)
+		if l := len(privKey.PrivateKey); l != params.PrivateKeySize() {
+			return nil, fmt.Errorf("x509: invalid SLH-DSA private key length: %d", l)
+		}
+		return slhdsa.NewPrivateKey(params, privKey.PrivateKey)
+
 	case privKey.Algo.Algorithm.Equal(oidPublicKeyX25519):
 		if l := len(privKey.Algo.Parameters.FullBytes); l != 0 {
 			return nil, errors.New("x509: invalid X25519 private key parameters")
@@ -166,7 +180,7 @@ func ParsePKCS8PrivateKey(der []byte) (key any, err error) {
 //
 // The following key types are currently supported: *[rsa.PrivateKey],
 // *[ecdsa.PrivateKey], [ed25519.PrivateKey] (not a pointer), *[mldsa.PrivateKey],
-// *[ecdh.PrivateKey], *[mlkem.DecapsulationKey768], and
+// *[slhdsa.PrivateKey], *[ecdh.PrivateKey], *[mlkem.DecapsulationKey768], and
 // *[mlkem.DecapsulationKey1024]. Unsupported key types result in an error.
 //
 // This kind of key is commonly encoded in PEM blocks of type "PRIVATE KEY".
@@ -226,6 +240,16 @@ func MarshalPKCS8PrivateKey(key any) ([]byte, error) {
 		}
 		privKey.PrivateKey = append([]byte{0x80, mldsa.PrivateKeySize}, k.Bytes()...)
 
+	case *slhdsa.PrivateKey:
+		oid, ok := oidFromSLHDSAParameters(k.PublicKey().Parameters())
+		if !ok {
+			return nil, errors.New("x509: unknown SLH-DSA parameters while marshaling to PKCS#8")
+		}
+		privKey.Algo = pkix.AlgorithmIdentifier{
+			Algorithm: oid,
+		}
+		privKey.PrivateKey = k.Bytes()
+
 	case *mlkem.DecapsulationKey768:
 		privKey.Algo = pkix.AlgorithmIdentifier{
 			Algorithm: oidPublicKeyMLKEM768,
@@ -60,6 +60,17 @@ const (
 	CANotAuthorizedForExtKeyUsage
 	// NoValidChains results when there are no valid chains to return.
 	NoValidChains
+	// Revoked results when a certificate in the chain has been revoked,
+	// according to VerifyOptions.Revocation.
+	Revoked
+	// RevocationStatusUnknown results when the revocation status of a
+	// certificate in the chain could not be determined, and
+	// VerifyOptions.Revocation requires it.
+	RevocationStatusUnknown
+	// InsufficientSCTs results when the leaf certificate doesn't come with
+	// enough valid signed certificate timestamps to satisfy
+	// VerifyOptions.CertificateTransparency.
+	InsufficientSCTs
 )
 
 // CertificateInvalidError results when an odd error occurs. Users of this
@@ -96,6 +107,12 @@ func (e CertificateInvalidError) Error() string {
 			s = fmt.Sprintf("%s: %s", s, e.Detail)
 		}
 		return s
+	case Revoked:
+		return "x509: certificate has been revoked: " + e.Detail
+	case RevocationStatusUnknown:
+		return "x509: certificate revocation status could not be determined: " + e.Detail
+	case InsufficientSCTs:
+		return "x509: certificate does not satisfy the Certificate Transparency policy: " + e.Detail
 	}
 	return "x509: unknown error"
 }
@@ -224,6 +241,22 @@ type VerifyOptions struct {
 	// field implies any valid policy is acceptable.
 	CertificatePolicies []OID
 
+	// Revocation, if not nil, enables revocation checking of the chains
+	// built by Verify. Chains that contain a revoked certificate are
+	// discarded.
+	Revocation *RevocationOptions
+
+	// CertificateTransparency, if not nil, is a Certificate Transparency
+	// policy that the leaf certificate must satisfy. Chains that don't
+	// satisfy it are discarded.
+	CertificateTransparency *CTPolicy
+
+	// SignedCertificateTimestamps are serialized SCTs for the leaf
+	// certificate that were delivered separately from it, such as with the
+	// TLS extension or in an OCSP response. They are only used to enforce
+	// CertificateTransparency.
+	SignedCertificateTimestamps [][]byte
+
 	// The following policy fields are unexported, because we do not expect
 	// users to actually need to use them, but are useful for testing the
 	// policy validation code.
@@ -241,6 +274,42 @@ type VerifyOptions struct {
 	inhibitAnyPolicy bool
 }
 
+// RevocationOptions configures revocation checking in [Certificate.Verify].
+//
+// The status of a certificate is established from the first applicable source
+// among OCSPResponses, CRLs, and FetchOCSP, in that order. A source is
+// applicable if it is about that certificate, is signed by its issuer (or a
+// delegated OCSP responder), and is current at VerifyOptions.CurrentTime.
+// Only complete CRLs are applicable: delta CRLs, CRLs with an issuing
+// distribution point, and CRLs with other unhandled critical extensions are
+// ignored.
+type RevocationOptions struct {
+	// OCSPResponses are DER encoded OCSP responses, such as the one stapled
+	// by the peer to a TLS handshake (see ConnectionState.OCSPResponse in
+	// crypto/tls). Responses that are not applicable are ignored.
+	OCSPResponses [][]byte
+
+	// CRLs are parsed certificate revocation lists. CRLs that are not
+	// applicable are ignored.
+	CRLs []*RevocationList
+
+	// FetchOCSP, if not nil, is called to obtain a DER encoded OCSP response
+	// for cert, issued by issuer, when neither OCSPResponses nor CRLs
+	// establish its status. It would typically send a request created with
+	// [CreateOCSPRequest] to one of cert.OCSPServer. An error or a response
+	// that is not applicable leaves the status undetermined.
+	FetchOCSP func(cert, issuer *Certificate) ([]byte, error)
+
+	// CheckIntermediates causes the intermediate certificates to be checked
+	// too. By default, only the leaf certificate is checked.
+	CheckIntermediates bool
+
+	// RequireStatus causes chains to be rejected if the status of a checked
+	// certificate can't be determined. By default, such certificates are
+	// assumed not to be revoked.
+	RequireStatus bool
+}
+
 const (
 	leafCertificate = iota
 	intermediateCertificate
@@ -544,7 +613,10 @@ func (c *Certificate) isValid(certType int, currentChain []*Certificate, opts *V
 //
 // Certificates other than c in the returned chains should not be modified.
 //
-// WARNING: this function doesn't do any revocation checking.
+// Revocation is only checked if opts.Revocation is set, and then only using
+// the sources it provides. Similarly, Certificate Transparency is only
+// enforced if opts.CertificateTransparency is set. The Go verifier and the
+// platform verifier behave the same in this regard.
 func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
 	// Platform-specific verification needs the ASN.1 contents so
 	// this makes the behavior consistent across platforms.
@@ -567,14 +639,21 @@ func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
 		// i.e. if SetFallbackRoots was called with x509usefallbackroots=1.
 		systemPool := systemRootsPool()
 		if opts.Roots == nil && (systemPool == nil || systemPool.systemPool) {
-			return c.systemVerify(&opts)
+			chains, err := c.systemVerify(&opts)
+			if err != nil {
+				return nil, err
+			}
+			return filterChains(chains, &opts)
 		}
 		if opts.Roots != nil && opts.Roots.systemPool {
 			platformChains, err := c.systemVerify(&opts)
 			// If the platform verifier succeeded, or there are no additional
 			// roots, return the platform verifier result. Otherwise, continue
 			// with the Go verifier.
-			if err == nil || opts.Roots.len() == 0 {
+			if err == nil {
+				return filterChains(platformChains, &opts)
+			}
+			if opts.Roots.len() == 0 {
 				return platformChains, err
 			}
 		}
@@ -663,7 +742,163 @@ func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
 		return nil, err
 	}
 
-	return candidateChains, nil
+	return filterChains(candidateChains, &opts)
+}
+
+// filterChains applies the checks that Verify layers on top of both the Go
+// and the platform verifiers, discarding the chains that fail them.
+func filterChains(chains [][]*Certificate, opts *VerifyOptions) ([][]*Certificate, error) {
+	chains, err := checkCertificateTransparency(chains, opts)
+	if err != nil {
+		return nil, err
+	}
+	return checkRevocation(chains, opts)
+}
+
+// checkRevocation discards the chains that contain a revoked certificate,
+// according to opts.Revocation. If no chains are left, it returns the error
+// that caused the first one to be discarded.
+func checkRevocation(chains [][]*Certificate, opts *VerifyOptions) ([][]*Certificate, error) {
+	if opts.Revocation == nil {
+		return chains, nil
+	}
+	now := opts.CurrentTime
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	// Chains often share certificates, so only check each pair once, which
+	// also avoids fetching the same OCSP response repeatedly.
+	type pair struct{ cert, issuer *Certificate }
+	checked := make(map[pair]error)
+	var firstErr error
+	chains = slices.DeleteFunc(chains, func(chain []*Certificate) bool {
+		for i := 0; i < len(chain)-1; i++ {
+			if i > 0 && !opts.Revocation.CheckIntermediates {
+				break
+			}
+			p := pair{chain[i], chain[i+1]}
+			err, ok := checked[p]
+			if !ok {
+				err = opts.Revocation.check(chain[i], chain[i+1], now)
+				checked[p] = err
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return true
+			}
+		}
+		return false
+	})
+	if len(chains) == 0 {
+		return nil, firstErr
+	}
+	return chains, nil
+}
+
+// check returns an error if cert, issued by issuer, is revoked at now or if
+// its status is required but can't be determined.
+func (ro *RevocationOptions) check(cert, issuer *Certificate, now time.Time) error {
+	var details []string
+	checkOCSP := func(der []byte) (bool, error) {
+		resp, err := parseOCSPResponse(der, cert, issuer)
+		if err == nil {
+			err = resp.checkCurrent(issuer, now)
+		}
+		if err != nil {
+			details = append(details, err.Error())
+			return false, nil
+		}
+		switch resp.Status {
+		case OCSPGood:
+			return true, nil
+		case OCSPRevoked:
+			return true, CertificateInvalidError{cert, Revoked,
+				fmt.Sprintf("OCSP response reports revocation at %s", resp.RevokedAt.Format(time.RFC3339))}
+		}
+		details = append(details, "OCSP responder reported unknown status")
+		return false, nil
+	}
+
+	for _, der := range ro.OCSPResponses {
+		if ok, err := checkOCSP(der); ok {
+			return err
+		}
+	}
+
+	for _, crl := range ro.CRLs {
+		if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) {
+			continue
+		}
+		if crl.ThisUpdate.After(now) || !crl.NextUpdate.IsZero() && !now.Before(crl.NextUpdate) {
+			details = append(details, "CRL is not current")
+			continue
+		}
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			details = append(details, err.Error())
+			continue
+		}
+		if err := crl.checkComplete(); err != nil {
+			details = append(details, err.Error())
+			continue
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return CertificateInvalidError{cert, Revoked,
+					fmt.Sprintf("CRL reports revocation at %s", entry.RevocationTime.Format(time.RFC3339))}
+			}
+		}
+		return nil
+	}
+
+	if ro.FetchOCSP != nil {
+		der, err := ro.FetchOCSP(cert, issuer)
+		if err != nil {
+			details = append(details, err.Error())
+		} else if ok, err := checkOCSP(der); ok {
+			return err
+		}
+	}
+
+	if ro.RequireStatus {
+		if len(details) == 0 {
+			details = append(details, "no OCSP response or CRL available")
+		}
+		return CertificateInvalidError{cert, RevocationStatusUnknown, strings.Join(details, ", ")}
+	}
+	return nil
+}
+
+var (
+	oidExtensionDeltaCRLIndicator        = []int{2, 5, 29, 27}
+	oidExtensionIssuingDistributionPoint = []int{2, 5, 29, 28}
+)
+
+// checkComplete returns an error if crl might not list every revoked
+// certificate of its issuer, such as a delta CRL or a CRL partitioned by an
+// issuing distribution point, in which case the absence of a certificate
+// from it doesn't mean that the certificate is not revoked.
+func (crl *RevocationList) checkComplete() error {
+	for _, ext := range crl.Extensions {
+		switch {
+		case ext.Id.Equal(oidExtensionDeltaCRLIndicator):
+			return errors.New("x509: delta CRLs are not supported")
+		case ext.Id.Equal(oidExtensionIssuingDistributionPoint):
+			return errors.New("x509: CRLs with an issuing distribution point are not supported")
+		case ext.Critical && !ext.Id.Equal(oidExtensionAuthorityKeyId) && !ext.Id.Equal(oidExtensionCRLNumber):
+			return errors.New("x509: CRL has an unhandled critical extension " + ext.Id.String())
+		}
+	}
+	for _, entry := range crl.RevokedCertificateEntries {
+		for _, ext := range entry.Extensions {
+			if ext.Critical && !ext.Id.Equal(oidExtensionReasonCode) {
+				return errors.New("x509: CRL entry has an unhandled critical extension " + ext.Id.String())
+			}
+		}
+	}
+	return nil
 }
 
 func appendToFreshChain(chain []*Certificate, cert *Certificate) []*Certificate {
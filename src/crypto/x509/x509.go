@@ -33,6 +33,7 @@ import (
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/slhdsa"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/pem"
@@ -68,9 +69,9 @@ type pkixPublicKey struct {
 // public key is a SubjectPublicKeyInfo structure (see RFC 5280, Section 4.1).
 //
 // It returns a *[rsa.PublicKey], *[dsa.PublicKey], *[ecdsa.PublicKey],
-// [ed25519.PublicKey] (not a pointer), *[mldsa.PublicKey], *[ecdh.PublicKey]
-// (for X25519), *[mlkem.EncapsulationKey768], or *[mlkem.EncapsulationKey1024].
-// More types might be supported in the future.
+// [ed25519.PublicKey] (not a pointer), *[mldsa.PublicKey], *[slhdsa.PublicKey],
+// *[ecdh.PublicKey] (for X25519), *[mlkem.EncapsulationKey768], or
+// *[mlkem.EncapsulationKey1024]. More types might be supported in the future.
 //
 // This kind of key is commonly encoded in PEM blocks of type "PUBLIC KEY".
 func ParsePKIXPublicKey(derBytes []byte) (pub any, err error) {
@@ -126,6 +127,13 @@ func marshalPublicKey(pub any) (publicKeyBytes []byte, publicKeyAlgorithm pkix.A
 		}
 		publicKeyBytes = pub.Bytes()
 		publicKeyAlgorithm.Algorithm = oid
+	case *slhdsa.PublicKey:
+		oid, ok := oidFromSLHDSAParameters(pub.Parameters())
+		if !ok {
+			return nil, pkix.AlgorithmIdentifier{}, errors.New("x509: unsupported SLH-DSA parameters")
+		}
+		publicKeyBytes = pub.Bytes()
+		publicKeyAlgorithm.Algorithm = oid
 	case *ecdh.PublicKey:
 		publicKeyBytes = pub.Bytes()
 		if pub.Curve() == ecdh.X25519() {
@@ -162,7 +170,7 @@ func marshalPublicKey(pub any) (publicKeyBytes []byte, publicKeyAlgorithm pkix.A
 //
 // The following key types are currently supported: *[rsa.PublicKey],
 // *[ecdsa.PublicKey], [ed25519.PublicKey] (not a pointer), *[mldsa.PublicKey],
-// *[ecdh.PublicKey], *[mlkem.EncapsulationKey768], and
+// *[slhdsa.PublicKey], *[ecdh.PublicKey], *[mlkem.EncapsulationKey768], and
 // *[mlkem.EncapsulationKey1024]. Unsupported key types result in an error.
 //
 // This kind of key is commonly encoded in PEM blocks of type "PUBLIC KEY".
@@ -252,6 +260,18 @@ const (
 	MLDSA44
 	MLDSA65
 	MLDSA87
+	SLHDSA_SHA2_128s
+	SLHDSA_SHA2_128f
+	SLHDSA_SHA2_192s
+	SLHDSA_SHA2_192f
+	SLHDSA_SHA2_256s
+	SLHDSA_SHA2_256f
+	SLHDSA_SHAKE_128s
+	SLHDSA_SHAKE_128f
+	SLHDSA_SHAKE_192s
+	SLHDSA_SHAKE_192f
+	SLHDSA_SHAKE_256s
+	SLHDSA_SHAKE_256f
 )
 
 func (algo SignatureAlgorithm) isRSAPSS() bool {
@@ -290,6 +310,7 @@ const (
 	ECDSA
 	Ed25519
 	MLDSA
+	SLHDSA
 )
 
 var publicKeyAlgoName = [...]string{
@@ -298,6 +319,7 @@ var publicKeyAlgoName = [...]string{
 	ECDSA:   "ECDSA",
 	Ed25519: "Ed25519",
 	MLDSA:   "ML-DSA",
+	SLHDSA:  "SLH-DSA",
 }
 
 func (algo PublicKeyAlgorithm) String() string {
@@ -410,6 +432,18 @@ var signatureAlgorithmDetails = []struct {
 	{MLDSA44, "ML-DSA-44", oidPublicKeyMLDSA44, emptyRawValue, MLDSA, crypto.Hash(0) /* no pre-hashing */, false},
 	{MLDSA65, "ML-DSA-65", oidPublicKeyMLDSA65, emptyRawValue, MLDSA, crypto.Hash(0) /* no pre-hashing */, false},
 	{MLDSA87, "ML-DSA-87", oidPublicKeyMLDSA87, emptyRawValue, MLDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHA2_128s, "SLH-DSA-SHA2-128s", oidPublicKeySLHDSA_SHA2_128s, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHA2_128f, "SLH-DSA-SHA2-128f", oidPublicKeySLHDSA_SHA2_128f, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHA2_192s, "SLH-DSA-SHA2-192s", oidPublicKeySLHDSA_SHA2_192s, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHA2_192f, "SLH-DSA-SHA2-192f", oidPublicKeySLHDSA_SHA2_192f, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHA2_256s, "SLH-DSA-SHA2-256s", oidPublicKeySLHDSA_SHA2_256s, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHA2_256f, "SLH-DSA-SHA2-256f", oidPublicKeySLHDSA_SHA2_256f, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHAKE_128s, "SLH-DSA-SHAKE-128s", oidPublicKeySLHDSA_SHAKE_128s, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHAKE_128f, "SLH-DSA-SHAKE-128f", oidPublicKeySLHDSA_SHAKE_128f, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHAKE_192s, "SLH-DSA-SHAKE-192s", oidPublicKeySLHDSA_SHAKE_192s, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHAKE_192f, "SLH-DSA-SHAKE-192f", oidPublicKeySLHDSA_SHAKE_192f, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHAKE_256s, "SLH-DSA-SHAKE-256s", oidPublicKeySLHDSA_SHAKE_256s, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
+	{SLHDSA_SHAKE_256f, "SLH-DSA-SHAKE-256f", oidPublicKeySLHDSA_SHAKE_256f, emptyRawValue, SLHDSA, crypto.Hash(0) /* no pre-hashing */, false},
 }
 
 var emptyRawValue = asn1.RawValue{}
@@ -443,11 +477,14 @@ func getSignatureAlgorithmFromAI(ai pkix.AlgorithmIdentifier) SignatureAlgorithm
 	if ai.Algorithm.Equal(oidSignatureEd25519) ||
 		ai.Algorithm.Equal(oidPublicKeyMLDSA44) ||
 		ai.Algorithm.Equal(oidPublicKeyMLDSA65) ||
-		ai.Algorithm.Equal(oidPublicKeyMLDSA87) {
+		ai.Algorithm.Equal(oidPublicKeyMLDSA87) ||
+		isSLHDSAOID(ai.Algorithm) {
 		// RFC 8410, Section 3
 		// > For all of the OIDs, the parameters MUST be absent.
 		// RFC 9881, Section 2
 		// > The contents of the parameters component for each algorithm MUST be absent.
+		// RFC 9909, Section 3
+		// > The contents of the parameters component for each algorithm MUST be absent.
 		if len(ai.Parameters.FullBytes) != 0 {
 			return UnknownSignatureAlgorithm
 		}
@@ -539,6 +576,36 @@ var (
 	oidPublicKeyMLDSA44 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 17}
 	oidPublicKeyMLDSA65 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 18}
 	oidPublicKeyMLDSA87 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 19}
+	// RFC 9909, Section 3
+	//
+	//	sigAlgs OBJECT IDENTIFIER ::= { joint-iso-itu-t(2)
+	//		country(16) us(840) organization(1) gov(101) csor(3)
+	//		nistAlgorithm(4) 3 }
+	//
+	//	id-slh-dsa-sha2-128s   OBJECT IDENTIFIER ::= { sigAlgs 20 }
+	//	id-slh-dsa-sha2-128f   OBJECT IDENTIFIER ::= { sigAlgs 21 }
+	//	id-slh-dsa-sha2-192s   OBJECT IDENTIFIER ::= { sigAlgs 22 }
+	//	id-slh-dsa-sha2-192f   OBJECT IDENTIFIER ::= { sigAlgs 23 }
+	//	id-slh-dsa-sha2-256s   OBJECT IDENTIFIER ::= { sigAlgs 24 }
+	//	id-slh-dsa-sha2-256f   OBJECT IDENTIFIER ::= { sigAlgs 25 }
+	//	id-slh-dsa-shake-128s  OBJECT IDENTIFIER ::= { sigAlgs 26 }
+	//	id-slh-dsa-shake-128f  OBJECT IDENTIFIER ::= { sigAlgs 27 }
+	//	id-slh-dsa-shake-192s  OBJECT IDENTIFIER ::= { sigAlgs 28 }
+	//	id-slh-dsa-shake-192f  OBJECT IDENTIFIER ::= { sigAlgs 29 }
+	//	id-slh-dsa-shake-256s  OBJECT IDENTIFIER ::= { sigAlgs 30 }
+	//	id-slh-dsa-shake-256f  OBJECT IDENTIFIER ::= { sigAlgs 31 }
+	oidPublicKeySLHDSA_SHA2_128s  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 20}
+	oidPublicKeySLHDSA_SHA2_128f  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 21}
+	oidPublicKeySLHDSA_SHA2_192s  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 22}
+	oidPublicKeySLHDSA_SHA2_192f  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 23}
+	oidPublicKeySLHDSA_SHA2_256s  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 24}
+	oidPublicKeySLHDSA_SHA2_256f  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 25}
+	oidPublicKeySLHDSA_SHAKE_128s = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 26}
+	oidPublicKeySLHDSA_SHAKE_128f = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 27}
+	oidPublicKeySLHDSA_SHAKE_192s = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 28}
+	oidPublicKeySLHDSA_SHAKE_192f = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 29}
+	oidPublicKeySLHDSA_SHAKE_256s = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 30}
+	oidPublicKeySLHDSA_SHAKE_256f = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 31}
 	// RFC 9935, Section 3
 	//
 	//	id-alg-ml-kem-768  OBJECT IDENTIFIER ::= { joint-iso-itu-t(2)
@@ -573,6 +640,12 @@ func getPublicKeyAlgorithmFromOID(oid asn1.ObjectIdentifier) PublicKeyAlgorithm
 			return UnknownPublicKeyAlgorithm
 		}
 		return MLDSA
+	case isSLHDSAOID(oid):
+		// SLH-DSA is not available in FIPS 140-3 modules v1.0.0 and v1.26.0.
+		if v := fips140.Version(); v == "v1.0.0" || v == "v1.26.0" {
+			return UnknownPublicKeyAlgorithm
+		}
+		return SLHDSA
 	}
 	return UnknownPublicKeyAlgorithm
 }
@@ -668,6 +741,59 @@ func oidFromMLDSAParameters(params mldsa.Parameters) (asn1.ObjectIdentifier, boo
 	return nil, false
 }
 
+// slhdsaAlgorithms maps each SLH-DSA parameter set to its OID, which
+// identifies both the public key and signature algorithm.
+var slhdsaAlgorithms = []struct {
+	params func() slhdsa.Parameters
+	oid    asn1.ObjectIdentifier
+	algo   SignatureAlgorithm
+}{
+	{slhdsa.SHA2_128s, oidPublicKeySLHDSA_SHA2_128s, SLHDSA_SHA2_128s},
+	{slhdsa.SHA2_128f, oidPublicKeySLHDSA_SHA2_128f, SLHDSA_SHA2_128f},
+	{slhdsa.SHA2_192s, oidPublicKeySLHDSA_SHA2_192s, SLHDSA_SHA2_192s},
+	{slhdsa.SHA2_192f, oidPublicKeySLHDSA_SHA2_192f, SLHDSA_SHA2_192f},
+	{slhdsa.SHA2_256s, oidPublicKeySLHDSA_SHA2_256s, SLHDSA_SHA2_256s},
+	{slhdsa.SHA2_256f, oidPublicKeySLHDSA_SHA2_256f, SLHDSA_SHA2_256f},
+	{slhdsa.SHAKE_128s, oidPublicKeySLHDSA_SHAKE_128s, SLHDSA_SHAKE_128s},
+	{slhdsa.SHAKE_128f, oidPublicKeySLHDSA_SHAKE_128f, SLHDSA_SHAKE_128f},
+	{slhdsa.SHAKE_192s, oidPublicKeySLHDSA_SHAKE_192s, SLHDSA_SHAKE_192s},
+	{slhdsa.SHAKE_192f, oidPublicKeySLHDSA_SHAKE_192f, SLHDSA_SHAKE_192f},
+	{slhdsa.SHAKE_256s, oidPublicKeySLHDSA_SHAKE_256s, SLHDSA_SHAKE_256s},
+	{slhdsa.SHAKE_256f, oidPublicKeySLHDSA_SHAKE_256f, SLHDSA_SHAKE_256f},
+}
+
+func isSLHDSAOID(oid asn1.ObjectIdentifier) bool {
+	_, ok := slhdsaParametersFromOID(oid)
+	return ok
+}
+
+func slhdsaParametersFromOID(oid asn1.ObjectIdentifier) (slhdsa.Parameters, bool) {
+	for _, a := range slhdsaAlgorithms {
+		if oid.Equal(a.oid) {
+			return a.params(), true
+		}
+	}
+	return slhdsa.Parameters{}, false
+}
+
+func oidFromSLHDSAParameters(params slhdsa.Parameters) (asn1.ObjectIdentifier, bool) {
+	for _, a := range slhdsaAlgorithms {
+		if params == a.params() {
+			return a.oid, true
+		}
+	}
+	return nil, false
+}
+
+func signatureAlgorithmFromSLHDSAParameters(params slhdsa.Parameters) (SignatureAlgorithm, bool) {
+	for _, a := range slhdsaAlgorithms {
+		if params == a.params() {
+			return a.algo, true
+		}
+	}
+	return UnknownSignatureAlgorithm, false
+}
+
 // KeyUsage represents the set of actions that are valid for a given key. It's
 // a bitmap of the KeyUsage* constants.
 type KeyUsage int
@@ -1086,7 +1212,7 @@ func checkSignature(algo SignatureAlgorithm, signed, signature []byte, publicKey
 
 	switch hashType {
 	case crypto.Hash(0):
-		if pubKeyAlgo != Ed25519 && pubKeyAlgo != MLDSA {
+		if pubKeyAlgo != Ed25519 && pubKeyAlgo != MLDSA && pubKeyAlgo != SLHDSA {
 			return ErrUnsupportedAlgorithm
 		}
 	case crypto.MD5:
@@ -1156,6 +1282,19 @@ func checkSignature(algo SignatureAlgorithm, signed, signature []byte, publicKey
 			return fmt.Errorf("x509: ML-DSA verification failure: %w", err)
 		}
 		return
+	case *slhdsa.PublicKey:
+		if pubKeyAlgo != SLHDSA {
+			return signaturePublicKeyAlgoMismatchError(pubKeyAlgo, pub)
+		}
+		if expected, ok := signatureAlgorithmFromSLHDSAParameters(pub.Parameters()); !ok {
+			return fmt.Errorf("x509: unknown SLH-DSA parameters: %s", pub.Parameters())
+		} else if algo != expected {
+			return fmt.Errorf("x509: signature algorithm specifies an SLH-DSA public key with %s parameters, but have a public key with %s parameters", algo, pub.Parameters())
+		}
+		if err := slhdsa.Verify(pub, signed, signature, nil); err != nil {
+			return fmt.Errorf("x509: SLH-DSA verification failure: %w", err)
+		}
+		return
 	}
 	return ErrUnsupportedAlgorithm
 }
@@ -1699,8 +1838,16 @@ func signingParamsForKey(key crypto.Signer, sigAlgo SignatureAlgorithm) (Signatu
 			return 0, ai, fmt.Errorf("x509: unsupported ML-DSA parameters: %s", pub.Parameters())
 		}
 
+	case *slhdsa.PublicKey:
+		pubType = SLHDSA
+		var ok bool
+		defaultAlgo, ok = signatureAlgorithmFromSLHDSAParameters(pub.Parameters())
+		if !ok {
+			return 0, ai, fmt.Errorf("x509: unsupported SLH-DSA parameters: %s", pub.Parameters())
+		}
+
 	default:
-		return 0, ai, errors.New("x509: only RSA, ECDSA, ML-DSA and Ed25519 keys supported")
+		return 0, ai, errors.New("x509: only RSA, ECDSA, ML-DSA, SLH-DSA and Ed25519 keys supported")
 	}
 
 	if sigAlgo == 0 {
@@ -1715,6 +1862,9 @@ func signingParamsForKey(key crypto.Signer, sigAlgo SignatureAlgorithm) (Signatu
 			if pubType == MLDSA && sigAlgo != defaultAlgo {
 				return 0, ai, errors.New("x509: requested SignatureAlgorithm does not match ML-DSA parameters")
 			}
+			if pubType == SLHDSA && sigAlgo != defaultAlgo {
+				return 0, ai, errors.New("x509: requested SignatureAlgorithm does not match SLH-DSA parameters")
+			}
 			if details.hash == crypto.MD5 {
 				return 0, ai, errors.New("x509: signing with MD5 is not supported")
 			}
@@ -1801,9 +1951,9 @@ var emptyASN1Subject = []byte{0x30, 0}
 // The returned slice is the certificate in DER encoding.
 //
 // The currently supported key types are *rsa.PublicKey, *ecdsa.PublicKey,
-// ed25519.PublicKey, and *mldsa.PublicKey. pub must be a supported key type,
-// and priv must be a crypto.Signer or crypto.MessageSigner with a supported
-// public key.
+// ed25519.PublicKey, *mldsa.PublicKey, and *slhdsa.PublicKey. pub must be a
+// supported key type, and priv must be a crypto.Signer or crypto.MessageSigner
+// with a supported public key.
 //
 // The AuthorityKeyId will be taken from the SubjectKeyId of parent, if any,
 // unless the resulting certificate is self-signed. Otherwise the value from
@@ -2214,9 +2364,10 @@ func parseCSRExtensions(rawAttributes []asn1.RawValue) ([]pkix.Extension, error)
 // priv is the private key to sign the CSR with, and the corresponding public
 // key will be included in the CSR. It must implement crypto.Signer or
 // crypto.MessageSigner and its Public() method must return a *rsa.PublicKey or
-// a *ecdsa.PublicKey or a ed25519.PublicKey or a *mldsa.PublicKey.
-// (A *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey or
-// *mldsa.PrivateKey satisfies this.)
+// a *ecdsa.PublicKey or a ed25519.PublicKey or a *mldsa.PublicKey or a
+// *slhdsa.PublicKey. (A *rsa.PrivateKey, *ecdsa.PrivateKey or
+// ed25519.PrivateKey or *mldsa.PrivateKey or *slhdsa.PrivateKey satisfies
+// this.)
 //
 // The returned slice is the certificate request in DER encoding.
 func CreateCertificateRequest(rand io.Reader, template *CertificateRequest, priv any) (csr []byte, err error) {
@@ -20,6 +20,7 @@ import (
 	"crypto/rsa"
 	_ "crypto/sha256"
 	_ "crypto/sha512"
+	"crypto/slhdsa"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/base64"
@@ -3403,6 +3404,20 @@ func TestMismatchedTemplateSignatureAlgorithm(t *testing.T) {
 		)
 	}
 
+	if v := fips140.Version(); v != "v1.0.0" && v != "v1.26.0" {
+		slhdsaPriv, err := slhdsa.GenerateKey(slhdsa.SHA2_128f())
+		if err != nil {
+			t.Fatalf("Failed to generate SLH-DSA-SHA2-128f key: %s", err)
+		}
+		tests = append(tests,
+			test{"SLHDSA-key/Ed25519-algo", slhdsaPriv, PureEd25519, mismatchErr, 0},
+			test{"Ed25519-key/SLHDSA-algo", ed25519Priv, SLHDSA_SHA2_128f, mismatchErr, 0},
+			test{"SLHDSA-SHA2-128f-key/SLHDSA-SHAKE-128f-algo", slhdsaPriv, SLHDSA_SHAKE_128f,
+				"x509: requested SignatureAlgorithm does not match SLH-DSA parameters", 0},
+			test{"SLHDSA-SHA2-128f-key/SLHDSA-SHA2-128f-algo", slhdsaPriv, SLHDSA_SHA2_128f, "", SLHDSA_SHA2_128f},
+		)
+	}
+
 	check := func(t *testing.T, op string, err error, wantErr string) {
 		t.Helper()
 		if wantErr == "" {
@@ -4720,6 +4735,118 @@ func testMLDSA(t *testing.T, privateKeyPEM, publicKeyPEM, certPEM string) {
 	}
 }
 
+func TestSLHDSA(t *testing.T) {
+	cryptotest.MustMinimumFIPS140ModuleVersion(t, "v1.28.0")
+
+	privKey, err := ParsePKCS8PrivateKey(pemDecode(t, testPrivateKeySLHDSASHA2128s))
+	if err != nil {
+		t.Fatalf("ParsePKCS8PrivateKey failed: %s", err)
+	}
+	if _, ok := privKey.(*slhdsa.PrivateKey); !ok {
+		t.Fatalf("ParsePKCS8PrivateKey returned wrong type: got %T, want *slhdsa.PrivateKey", privKey)
+	}
+	if hex.EncodeToString(privKey.(*slhdsa.PrivateKey).Bytes()[:48]) != "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f" {
+		t.Fatal("ParsePKCS8PrivateKey returned wrong private key value")
+	}
+
+	got, err := MarshalPKCS8PrivateKey(privKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey failed: %s", err)
+	}
+	if !bytes.Equal(got, pemDecode(t, testPrivateKeySLHDSASHA2128s)) {
+		t.Fatal("MarshalPKCS8PrivateKey did not return original DER bytes")
+	}
+
+	pubKey, err := ParsePKIXPublicKey(pemDecode(t, testPublicKeySLHDSASHA2128s))
+	if err != nil {
+		t.Fatalf("ParsePKIXPublicKey failed: %s", err)
+	}
+	if _, ok := pubKey.(*slhdsa.PublicKey); !ok {
+		t.Fatalf("ParsePKIXPublicKey returned wrong type: got %T, want *slhdsa.PublicKey", pubKey)
+	}
+	if !pubKey.(*slhdsa.PublicKey).Equal(privKey.(*slhdsa.PrivateKey).PublicKey()) {
+		t.Fatal("ParsePKIXPublicKey returned public key that does not match private key")
+	}
+
+	got, err = MarshalPKIXPublicKey(pubKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey failed: %s", err)
+	}
+	if !bytes.Equal(got, pemDecode(t, testPublicKeySLHDSASHA2128s)) {
+		t.Fatal("MarshalPKIXPublicKey did not return original DER bytes")
+	}
+
+	cert, err := ParseCertificate(pemDecode(t, testCertificateSLHDSASHA2128s))
+	if err != nil {
+		t.Fatalf("ParseCertificate failed: %s", err)
+	}
+	if !cert.PublicKey.(*slhdsa.PublicKey).Equal(pubKey) {
+		t.Fatal("ParseCertificate returned certificate with public key that does not match private key")
+	}
+	if cert.PublicKeyAlgorithm != SLHDSA {
+		t.Fatalf("ParseCertificate returned certificate with wrong public key algorithm: got %v, want SLHDSA", cert.PublicKeyAlgorithm)
+	}
+	if cert.SignatureAlgorithm != SLHDSA_SHA2_128s {
+		t.Fatalf("ParseCertificate returned certificate with wrong signature algorithm: got %v, want SLHDSA_SHA2_128s", cert.SignatureAlgorithm)
+	}
+	if err := cert.CheckSignatureFrom(cert); err != nil {
+		t.Fatalf("CheckSignatureFrom failed: %s", err)
+	}
+	if err := cert.CheckSignature(SLHDSA_SHAKE_128s, cert.RawTBSCertificate, cert.Signature); err == nil || !strings.Contains(err.Error(), "SLH-DSA-SHAKE-128s") {
+		t.Errorf("CheckSignature with mismatched parameters: got %v, want parameter mismatch error", err)
+	}
+
+	// Parameters must be absent from the AlgorithmIdentifier.
+	spki := pemDecode(t, testPublicKeySLHDSASHA2128s)
+	var pki publicKeyInfo
+	if _, err := asn1.Unmarshal(spki, &pki); err != nil {
+		t.Fatal(err)
+	}
+	pki.Raw = nil
+	pki.Algorithm.Parameters = asn1.NullRawValue
+	spki, err = asn1.Marshal(pki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParsePKIXPublicKey(spki); err == nil {
+		t.Error("ParsePKIXPublicKey accepted an SLH-DSA key with NULL parameters")
+	}
+
+	// Use a fast parameter set to exercise CreateCertificate.
+	priv, err := slhdsa.GenerateKey(slhdsa.SHAKE_128f())
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "SLH-DSA-SHAKE-128f"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     KeyUsageCertSign,
+
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := CreateCertificate(rand.Reader, template, template, priv.PublicKey(), priv)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %s", err)
+	}
+	cert2, err := ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate failed: %s", err)
+	}
+	if cert2.SignatureAlgorithm != SLHDSA_SHAKE_128f {
+		t.Fatalf("ParseCertificate returned certificate with wrong signature algorithm: got %v, want SLHDSA_SHAKE_128f", cert2.SignatureAlgorithm)
+	}
+	if err := cert2.CheckSignatureFrom(cert2); err != nil {
+		t.Fatalf("CheckSignatureFrom failed: %s", err)
+	}
+	template.SignatureAlgorithm = SLHDSA_SHA2_128f
+	if _, err := CreateCertificate(rand.Reader, template, template, priv.PublicKey(), priv); err == nil {
+		t.Error("CreateCertificate accepted a SignatureAlgorithm that does not match the SLH-DSA parameters")
+	}
+}
+
 func TestMLKEM(t *testing.T) {
 	t.Run("ML-KEM-768", func(t *testing.T) {
 		testMLKEM[*mlkem.DecapsulationKey768](t,
@@ -6013,3 +6140,195 @@ HVT854OIkNIUoqfnESODrczb3C5kjJ230df4V156qMbJBwwcJFtzf5ObyO3ycnd/
 kNggIp4AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQIDxcdKS4x
 -----END CERTIFICATE-----
 `
+
+// testPrivateKeySLHDSASHA2128s, testPublicKeySLHDSASHA2128s, and
+// testCertificateSLHDSASHA2128s were cross-checked against OpenSSL 3.5. The
+// private key uses the seeds 0x00..0x2f.
+var testPrivateKeySLHDSASHA2128s = testingKey(`
+-----BEGIN TESTING KEY-----
+MFICAQAwCwYJYIZIAWUDBAMUBEAAAQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRob
+HB0eHyAhIiMkJSYnKCkqKywtLi+ZDOYph5KxKIRqjko6aJVM
+-----END TESTING KEY-----
+`)
+
+var testPublicKeySLHDSASHA2128s = `
+-----BEGIN PUBLIC KEY-----
+MDAwCwYJYIZIAWUDBAMUAyEAICEiIyQlJicoKSorLC0uL5kM5imHkrEohGqOSjpo
+lUw=
+-----END PUBLIC KEY-----
+`
+
+var testCertificateSLHDSASHA2128s = `
+-----BEGIN CERTIFICATE-----
+MIIf0TCCAQugAwIBAgIBATALBglghkgBZQMEAxQwLjELMAkGA1UEChMCR28xHzAd
+BgNVBAMTFlNMSC1EU0EtU0hBMi0xMjhzIHRlc3QwHhcNMjYwMTAxMDAwMDAwWhcN
+NDYwMTAxMDAwMDAwWjAuMQswCQYDVQQKEwJHbzEfMB0GA1UEAxMWU0xILURTQS1T
+SEEyLTEyOHMgdGVzdDAwMAsGCWCGSAFlAwQDFAMhACAhIiMkJSYnKCkqKywtLi+Z
+DOYph5KxKIRqjko6aJVMo0IwQDAOBgNVHQ8BAf8EBAMCAoQwDwYDVR0TAQH/BAUw
+AwEB/zAdBgNVHQ4EFgQUnVtpqnTEk5RouuUQhWik0JJvJTIwCwYJYIZIAWUDBAMU
+A4IesQBfK4y0Sd1alK6anMmNdz9DXil6++fNU2FngPvZA2ZGbANrq0in11FcaJsB
+RAfBJ0Db0z1LPEWXFknM/OT0gZWJoqr6uvxsWJmafI8/DrQ4HV16CdOAUpL3bhvc
+wXNVliGEZQbC38Z3Wi5evoCPleMRaIArdHFh558mjQwHdXx7elo6EFN+5vLRveM0
+kwTnk+HjT2M8Rm/ceyB6KY6/gve9af3LvjBphsgJwlVWyIFwNaKEOfKzfyABK8kc
+m6aiEWG7A5nb1A9K8zVUbattKkOKdT4SshQiU3VvYxm6P9VyBN444/Pd6IU4SxPG
+4bN9xjLWVuMqNxwgwGMen9T7hhJecZj5VU2QO6KlCJMH/Nb1o1HoslFTuU/oxMCg
+o31GcNq37T3ONnV6NsMwWhQnDA4ldiqWu7jOgTDhJDjg746hQD/DPInGJYty+YSB
+8aWwdBuh6jSp54PLifkU4iK8Mi0LqgzAlggZGEhevdA9kZqQN2aJP1LNrztIircP
+Nu4OVHxKvOSc6abFHCMpHOPIKC5q0MqhI3FC7ba2IWLoppxQTm3oEAEIQaBj8PYY
+4yV4wqvSlXGdGDctM288eNgmnx3JbYTNFZsZKJOopj8GV0kkHRImlddLTodDubC2
+4PMfU807nzYDrELiPU+0KjFnvkPLWLZquHn2w4lBz9sWA+KnwkE3uq9/J59liGWn
+Hs39a186L/+7hOZxpbBhgjMNr3Fh/GJ027lCV8P524Kqs2/s8mZM7QExXMqxPpTW
+3fxq0m2Aytc3ZVWXV/YhITYE/Kp5Fv/YDK7/waBxXkoJvAiyThkUsOBes+bYvn2h
+ixataJNQtIDCngbaNUAbjalI+66ajT5CQ7CQ0qfUanlgLIuaQMuvT28u/o2rUvl/
+ev+Muvx02vxn8gyccEVMHPYypN1BP5vbU+QGy9xwtCjjdPz71xyWBfEL0UX9fiOT
+J4SUc4c/DIJKv3THawdbT/+PUCdBgrw36HKlM3seuvPwG5qxX8wGmRYXcx1kzYxb
+Bfe6II3nvYl5dDgYHJbWu//knhH7usAgDY5ktCKT6uGyTVUHKd3bUYFrpUgdv0hv
+xZxLDFX6b20meOoqahlRJwt7CYokl6jUr7v6aCy5sYvxEf8A0E1GGwO3qh7/UjGD
+1Xw5vtFToWWc5GfBrsY7/75FH4ga7kDXhM0ro43eOUruVwqooxXMiUdUvfAGt15Q
+6UoMUI+x5MAv9IIL536oLSjRjjnzavozkmrhbe0clPmdJXvL5nL/holOxX/83DPP
+r7uZul5oUjCsGXXih3etqWmN2kzX9VPQUOUO0Kde0I6603IBzWbK1nWbKUBSjoSR
+TZVsnYiDJQN9v/L03KG83G+WSaxXY6xfO8xl43xS0TOt++r6cHILioEQr+hplech
+1bR8Sd9nV0h4wZKxp8SIq2sBNVLXFSSKAYg7DmtpXOTzRdvpO5KBvv7LEpjGwEwU
+B0OdgSoR98JUiG3Jgy1VxqY/xC8DQ2XI0x/GhZkRxT8chSwNQNqKLRQUOIz7dSmu
+ITlVq44Hu9IuqdSQZnWofSn9+hvIZJ+4O9Ktkbicp0lcnrX2z+6BKSNW8eANi5ep
+Oh6hOtbjTyaWUMe3/pBywnGRZXsqSwIkSLP9HVRtosgYGtGzl+sLzrv0/geG7mkz
+fHoxipPKDGbp0GIjkIyzVzVMJWtLBrONRoLYsz2iNTrxQJJKOcDXk3HjGTSA48IF
+vjPFTFH78Aiglfrebb3w0c5GArLuOpYQdglmEbpeF2hTT+kPM76UAaYIpnskkeOP
+zZNR3k79+epIgASZwkUZeR1XQcvWhjcfVMRVMHdfGUuNABRilog9U3n7ZD7vsPGZ
+nAKpS9hycT50dSq8LBQIVE1g0xEHtYV5eq3I88E8XVbioqlTIZ+59XgZ9eoJZqiY
+2wbmgHNpPP/60EvgC5ttGZGY5WFjvHc07D+Jv5FnL7Xj136qf0Vne1c4cDThbVSP
+NeANfPOhr+kWt5nVMLoTobolk+sU3vxPv+vspCbtCqLEFqAmzsaC25NAIdH+ZGB5
+DnOjAr5BvtUxSxdQ3Gf3UKw1Ns0Yh8jLKYK8PGtrs4IoyxXNj7WsmDoVBDfEKR3X
+V7Ty6y0Bq8HX8HvKXJc4a/8TjFrpxU3NNNKW7ZZ1Nh3J2uR4QytlfWJ+DARYtbV2
+WzbjtYysTnI9i333hL7YAvKytx3czhuFBHKGcWa9D/tNtyXU+9yssiA8c+3c567l
+eQKxyCttVk/8ubHMH59BlZuJ8UR/ghB4B9RDe62SIL3K/2FCfIILrlpDQHQm3AFw
+OZde8c9p8CO86ftNNzsk3gNeI89rtf70LFK0VRL01ajuWFFtYp0T345LDONA8ab1
+lxnFQ5CQifWvLfo9Nn2/ctqdsjXS3dQfMnL3phSANrw4ZNJVnI69Uvvfa0NIaoOX
+uybzLq7TpWBUDRM6bXsK3I9yEuDBv8tF9vwaUnxNKwrjjntBRXVFs8OmKxX672II
+9KSABBWwjlAqvm5TAQ0wUhF2dm/6duhe0X1KgXIt1el6uMrBrrJtrW17D8nWlW29
+aBXPUi+5wL2gCXKi864lwRkL1MCuqxn0i3Xr6Y3U7zuzb2/5KYx69iPHwZ++ecx4
+mv5Ut2VnR8/X0bGuhkUZvUY0PVup9Brh2P0QcrjyNOFxtez9Mzu7WBP99QFCzcMQ
+X8aOjOLsYB778txq3qFHUa8cEJJAX8MRNpjSCeThloIfqySlN7SWziu7fizyAzb7
+MougTAgJvzXo2hEkE+FdEBEq4vbCV8eJf06tWCrbqMaZIW9eLWxpI9KhisvztThr
+DooypN6Ms95Q7nl0GjMIRFKJalshQ3k6zRfkiprjbtleJj6ODe5sgQuMpSsKGUVz
+u6sz1/1qCXlbnE/zHhfEaQOonuBCN7ymsm36tvPEOKz535m7gAQvVu7LEOaH0Aa8
+t2wJBOFfO2PntJnhPlAX1ierEBk1C/3nJcW7CtFGBtvda6QoOv+JoHiZB6Vw1N/W
+dBVtzq7SA+9q7CkCrNI3bBkhhXRknzthPpBcj+YdhT/p2qIL4/OXVlb6fZH/w6vX
+x91K7ZcU5IKojY6NjoxHWb2jdk57sGfewAxLSuGSpyxcVpGY8ZWMeMt3Sm/guae6
+NSILj0cUUV2ygh6n5G83GPaKp9fCYLBmtRpQhgRUz/5mY8hdLXRTGgkvXFx5dL3v
+F2EecDu1pKvxinqYZ1rlNX0MAtk9uuNmCVaVfMxR+3AWGy1ILqPZ9l13pUg6INb/
+6h5PI3I5T1obqalDJgYgl3OAh4GDOWaDW0ASJM4Je1X4Bt7Q4xa+R1LQUNo5eorm
+bc0zBgNvbGGkZ3jEcvDOC80+AKxkvtCNtFGmFQZyzNMZ0S3POzHaZKeFlLRdYUuI
+95NijdGESe/kTvveAHwSYwI/7nz6OwqDkt3whGMTb7Ujum7Ifi80l0vzoycKb8QR
+eD4JLw/jDhh7bfGWgzMi+wVhkJUxSSe3dL2uDgzp4heVcIbX2d+RgK62yDIbWSO9
+a0YfEtjLb0jyMW7K55CaUmwCKoQXEElNh7d33pDOXwNPsRyFTbubJ7gWQgJVUBd/
+yL3THI5hxsDyr+va4tGZn6SJvStYkQk2ZmqKdwx6yeLrW5ulQALbExkoemGU1Rw5
+QE8Op9SNKRrYxlIU1cYe5w+ZdeipWRUJgGa/0d4wooBYVkbD2eTYRB6RqS4EnyAm
+OtEp7Z9oxZVj6CFHGr5xK98SQruuoheWHfyVNXuyOFRot9E5BubZDJZ4Fs/NPlxo
+F/2RyIOS6+IJMzDAgf84297XjoN9n26mMvpKUdW4Fxk65fxvya32ObuQc2JEIKhz
+V2+ZhV+IvYL7GU53hVCkju6UNsLlCFx3sJ1X3ODz8i9XaYM2m2cwMJ2NiQeCmZgk
+vzd+fgrBsyAx4k93mE/M1vq32iG5pAytvqIVM7wceoYhdcowUrY5G7Nl23jGp2JD
+i3fY4BK754SXU3ZWOguSDs1OetrqCOZQU3jkSKk2X+f8AASqvS9wZGH1GvRNxy37
+g5RVIoy8H/GnKZAf1P1twnXV8SIveE9ZI86xB4URR3Q/j541f5dJZTXHY7RhbmSO
+Wnk/Y/D39B4vvv9y40ViBk7D8D8KSPP3FZaemwvEdkAlFy0z5TWa9muC8jhaE0Eb
+8xEjKoIBEX4UsXiASnKsY07VcMLXS76IXinf/XOkDAgzC8KOAj/2KtwB2GhHpLB+
+6nQqJXpYgkJd8oAZJg6RG1AaQZkNU6kpr/lEnx6FpQ8QDmbivc1GozJEDuzjt4eb
+NB/6fDMytqSo+hwHGLwBtueSA5mn21aCV3bsMtwKGNL9y6VmUT1zYANbpqyKncvc
+ZTLiOPuaQghTTcyCYrMQtOGfnWcTuxvLeSa5maxgbSgHaMKt3M7N43JMMMulGx9U
+VRmO5XIEm3bh7pN20HmtadNbM69EpiHXIKOMYosmvXfxEEAmsJVGGXbZdq1LOkNi
+5QX3vkdzAa0HDG9q2YdkMLV9O8PqXKGqH8bfL+NOfS31E2h1ylMMyE1cT+yFuTm6
+s6KC/wbypaXBxrwWSq9/mI1DGOZNW0fE9xJbqreuVv1DVgEppWly7/S4DjVJIK+G
+3iGcBx7HTnk4XFvEe6UzJG6msYqn/bQJ6KSfexEZTU7Mxjbz92JX/jzDpObIVY3E
+5DZX6prkzhAbrZlpzqcyRpNYNSVTvGFSvAta7rlUXRM6dvxSBAQE382aCVfz797T
+qCvXpOGkg2yb779C8hs4R97VqoQj+mjWyzF7oE5eQrVI2iEqiaI/M8TphW2sg+xE
+HSkm4ErAMLGPKl5ku5e82qDwIwlxYXkzmkX9HNOk9PJqaUa1kkPnESc/mlUUZOJ1
+C0uP28lTmYxouVh0UdOLw+dOvdrCiPv4RE1eYFI0Bs+MQb/e5lipQ833iL4h2ICF
+b60WPoyu1C8llYAEGyO6veug7v3M2QS+GTEF9UP3Ryve0+hPjWFxi6xqqrXzpFhb
+8DUVHi6EUFKHukrf/T2oaN+QXsBOmmTsLHxNU+ID344F3Zon3EXXtArWDbwIhc8u
+8F5XO8mos986/+Fl267tb+M2ygnWgh7wkmoR8PoVLo86Olb1W/IGQMTTntJHDznI
+4in6v/pNKMu/OIlEacJ+Xlzl1Ou5AHnBG1tcMBuwPQrnmrxPXbd1R4QHatOox76Z
+KYHi//49yQiDA6lCNYEe3/m7uNbh8rKTU51CblYYLJwBMFhfGvchWlsbdr1C/CK4
+BnYiiQDEJAwvqJTXhiVUrTE10nYyZP0oWvBaKMXaIPn5fA/1V5R+QiuNJtmKeKgQ
+S2YGERQ3I4QlMvjn2+XAUdIEorvO6Wd1GzbLDB0EZBQWU3dvqjbVcHc7Kl2yK6Nt
+rdTihHqfETWnuRiFT8sbz2y3soeORc5RREl5VBS+f7frfywr5ujLQmWi9S27u9Hf
+okQLpfp830lxeKtQnZ0pCamDof5dtVjE+wa4ksSRi9WXbSW/bvhS6tq9VvMixrv6
+dlrisjDMk+hVLV49TyAe0ayKpZjEYijtGQ4UZTUafQ6i3L8BBsAvxkmTcaGSrf/C
+M1pEhSVxEUl6PbgEVBE9g0BYSzT7Ypo1GFjmnFry/nKmrC3Bd0lNt03BJlcOl6K0
+pYU/Gfn2ocyt1pLuuch9h28ro3wWeAX7m6iWBwn+KpMrY3t78wj99n+5BSfN9E+9
+O6flKGrnO0SuMmbLEKMtYeBtR6YcSOVlxDein5Jc0DzmMPFWOutFDwKX8PDO/oDT
+q5Q4MUj9KJlmsJP6dLPEdmgQ5GOd9EJk9dAB2tj6XDbLq1evkTpkFJ23q3EmhN28
+Ctt/KUMPr9QRafpJxZTcXAHpO9kgHU9k+SpqYc4KmcmjMEs7W7KkvSrLflXb71Ri
+RKMOaVwYBtgURNr22l/2PA0hO6VxywI/GQTQg0GKpL9qffj4BZgVXOM09GDSE5uq
+AxMSGR+0WpYu6OMDiBTq+nKf/f1IfVXX9xemjRO0Zg+piBrvTns5SZFZYHPAA2NB
+NbqJ49UQ5LAm9SPt9mTBxGT5Xq8YuLBQVQUXq7zw4vAi6K56KGUEtE0UiDtI7VX3
+rC4h27A3LkRdgaH/vCEyUMjSehWhgQNsqSySFPWpEcHlmlz7qNIsNX/STywi+dFh
+inhspR+3skblItE5txQls5lsuzXpsziq6M8pardfjneTHw+HLXuks3+nLx+CG1y/
+WhcFTcO/A0FxviGaYtMYUnlQDvwYCrRPMvrdRw6zKYRygX8v7wcxJxfDC2QawyOp
+HKcnOh8xQgyKPusUkhVohI2CCaGLAPoIgVosMkSlng4zcgNdIHMNQ5I6EI2cm4aC
+f0orO7lZU950O+VjOj4Aw0LTEmvYqh4RFwbHZXbcxrwXJtbOV+NsZrgJ5FynF+aq
+thcz/eBi3wk2DEu4rR70iaxcuCEwftyReAOcVtfPQMGUG+ETMAg3zncZz13ZQJcf
+5Axqa6BOW/7TnKJNYrbuQmxCOQIo0DMlIgYSt5jNJk5CjJBQ2FfSS1w3rdQktkY1
+kjVMfGc9477A0WWmBA58NwwGGt/Mve7wOzgE//qrDoObYi/mFUizOyYPmgNymBUR
+H34Usmi8+lclji0O1dpcfRtvw+j8HHV/8Lymt3SEzz02onkh1N5NGotYJ0PdZeCJ
+uhnxMx+Dm3BDWEC/8+AzG33d7HX0obUTiNuHGwYZhMZAzU/t7AOj3k+SBEKt0YH8
+aes78SzVFmqKdFttbPlxdPmR0FfdhrJY4/zIaK6qUUxMa7O/2nDa9xmanbQ739eL
+xTxVTsGy7X42zmEHqhyeNWxPJn1pzpn6erGsTIosG4bIw10w+186ClYKSyGpsc21
+RI1nBJiR7724PWkPcbCLN6kyEnSP+Cp8M91MDxIlH2FzDG+Cb0Rra+XRSx93mU0e
+xpJiQAsT6IE7jCcgN55697HM3G/AHcG+0MvbiHk0Xc9u+DOePYYNqf48gDVuXTrr
+E4GLc1Pz+P70DpwOEAlUqmJ2joLHb2KkfWGPRbyFj3xKKP/uPPcXShbG5mFMZ/BL
+PeDyQTR95bCOZqDVe2Y0AMciy45qftJ12ZsODnMnIdxobMjdP04WfUmS7QguPi/E
+hNFDm1/MB40dv1XPlk5fl3ZGUQQhypfs+Q+56XSm3KJlrTk6h1as/abhWDhcbpnw
+8OyXMou/PcX9Wjw6QVjocK55ySP6rqrxmV0uiqqmx3cSLkMmhzNduLijsh1T53sV
+QItDRwDCmRPluKbLLQtn6yuq9swBUQt/stGySpGC4/bl1oNK7KzDfRPMXq8c6yg/
+9m6MoB3KgBN97XTjsH7iQ13YAHrtKpHPQnfYNh2r7qyszOLkJscw8ITig1W3tYqr
+VrudbtAyl1o7bNF0+aLzLDnzoSNQuGGasCd7jo/BqdcLUCQ8++SAeS8mCzya5UaU
+V8hmc0Lgtjy4jQI2blVJbCqTvs8DSAwuZK5/+q7Lm/jnXM8alnjUpjfRHHS7/1DV
+wA1gopLw5BK0uWOWcBoQa868oDCcfJnQ24r2DLTq2Aimt8QGGu7Y+L/ii0y3w149
+/sT9FVaN35iNuKKzXxqpVvoTDHqaOVkmdeO9m3hHhkZKwX1hGWtuEEXzs6nKwWIl
+fa7f5WdYzpgvcVjn6f0gQS20BcnU2ihZfw9wQhX9JUVE69PdBmuZmYaVgJvPThKo
+3xIQaPZx0C2eXOioZ8GEnTKtaQLL9AI74N5lr+o9chKcV+LgQQtTghnzqTBAeERY
+jWKgBLLTDQQMp2UtRTFe9WUeRv+4pKByteyjTwkNLc0PTJVI+1adRbGQvxAkAVgT
+s7fCp1fqPpUc7Frn64Bq0Y+qvHtACMGmlrJsRbsrBeEh0F1BFQdIFDhzZClVms5+
+rQAvbWNxngt6T/dgpvamDXwAWb4hS7uwvUXWkGDsu3pNnpwkZc2YsnO9Inl5OzEA
+AMJNpqckERssqIBD36a8ihQfQzgtPHaWEjyrMubuS1pk7qnapc8SJg0U7J6RHDo3
+RHgLX/mLDOFWq/e1NQur7PbgCaQ9hKVqvh2mLoTOm6DAtKQBIY/mrTblS3/FrHu3
+WL1hTR1G4QeoVg7GiukDHy0b3Cg0Arg72IQwV6ghwikJV/ReGAu+VCRFg6aXo4JK
+p3kCrxexjrTVcNEjVxX8+hHUSmXbluQ95t4kkI7+wm7htKH9Nz8DNwkgMY/q8xGL
+6o+ZTnTrOOio6eof0iTvfmjpphyjrftlMr2sNTwI78IEvhKAYp+zQPCip8uoLAir
+aU0WTH19VLr0L+P8bjrEEOkm/E4gw4L+CgNXWAOQVQLCc1vmvoKwF9WHuGfQjP3Z
+BnJtZEfZedj4RtctBjPpBioPFmwE4xEzuOQStz7NcUEj8xfchqVJmik4YsQaPx/Q
+i6DQ0oy9rfG7SO0/h/6hdEFTsfNJWRH6vhCazP2JUshIX0Zj3TtJ1gTyCEuHgvm3
+ezhjEwQuvCGJVQdHmAZXxmYgtv29scxzpPpMm9SkZ3c7VF0xGyRTOCRuWA+61gZs
+GETOzTYuE3SVTLYxwFZ8QMwu3xZm0/mEOLsNtaSClGf0jQVBltKH/pt3LWiB2mEo
+wyTEEo8mLQH+MB5/8G6WNE38PLH5bDhU3wyayf1mJfcYYtxOMHoHXJ6L5OrVsyrw
+vdWH0VUd3WXfmsbP/s9OlOEW3VzHWvzyihBLtao+4R7npOj5mfRQV983yJ7lxZfl
+8+AdqpFHFz2FrmLUcoafHNNo4duhT/mM7kVaS9QU3QOsGOHYZgwdVj/fwZS++Bzd
+bUGAoB2gJET8mF6Rd3/6XPTk5xmYj5PsMrkemJ0wWj0TRl8Ibj2z9rrNW02U9x7K
+cQJt9CfgX9d9YHq6smRQGaBrdyzFQsH2T7jiATLkakHgJFzOxAJFkBbFj5F+xYak
+iXoF8UxeMfM06FGB9UK9ZgpVnvYQpGF6yHqm2ywo+Pz7sg64Ti+BXnHYpjsLcLtE
+paPtiYO1ON87Vnjjjj6Ecb0UIHn311SacHH5NcpG5yUdAwEZy7r7lP8SpbTmd03w
+rcevc4WMv3hsaicsBBKh8ILmkJwHdBB/ApA2EV+tUsroC+gAjzC1PCSaAMe6dB3H
+UOB8Gl2v0302Bx7bGa7lVvtCo8s/sGluNzUeCNXz0qbtXinsy9ndC+jM6APpuzE/
+/E2WJEvz0uXARV9dKdNV/IsjTLwHFqX2cSAjmcHpkY2VJwFuyZTgu25ho2CEQBxP
+htBscXWmG1qUC0k9qp+aXFROAX0+d73D6AyTwVAGnY+osn8dZrlnNx2rFU10iNDg
+aa5ihMDe8RHhJEiu1eq/Fs918ghuB+pxc379fk4jYQl0ZxoQi9eTuk/8CTAO39fD
+nQUz1Zvroe/mJRa+kGMRxfSf69Cl42iCXkfSLoMP1TSFzQQoiKRwVlxIKXAAx6xj
+DZuj+fmoZ1b3Pf+bnf34vKgCL5tsGyg5e6kdzCZPWgq0AerM0hyTsOUaQ/zoRIbp
+NhONRshcvBOhdmdr5Ho5wiAu3ppgmxJwBjfX+6utBrTEVMT/cLGxKs1KeGw2rU+3
+CsF89IjAuNXFlwSHa6wzTGZFz51DNsQxpP6eYUGsd7CZYAS5WaiqtO1Y279vQVnI
+pdCbSJzcSj+VOL5U75XQObU9UeHUbWtDH/5clFVPdJtWyYDVh72V7YM0d4dIS7JD
+fMTgJZZdNYmN70NIuBUCZaPdvbhbWhSzaOOCaJ6Au4j04cNPR46DnXFJMKlh4T1f
+FFTmIWIvRQbgiHcntO4CXiDYD+Wkb8sPgc7raH3uCkR2VFju34WZjEOgQiBSGmCJ
+slVii0i4jgOG4sRfC9gPjhv0BUuQOy3yDcr4T+PryHVtbFX2xfIwyo+Jfr8lMnpA
+JpCTr1Gx0sB02xxRrd9Q3PP+m6FRpMbJtrCiurl1erX970x77h60BTVVCk4ynIdn
+wvdWuHvXtyJ4IFF0hbDqQ99PXszmYIrqP8hKX18H8JArKG6OJYxiv7/yCT6jtg+1
+XCatmFNxcbl73cisEwzOzFXDOYzniRXhuYHL65ytCW84mcG8fflzlw8LzkmE3f4R
+pMXG76P8wqQTnytt9cLI3/+2gQYy4EB6QNtn5PSvHppdBRs0mKjIWdyL5x9WDJav
+F0UQ1DbXN8h6n6+yD3kTsSDtPn7SzmIKGc7D+PuiytvtoaKwIdVp7eu/YI7BHYmT
+ctGE+tGWKG8SE2kgXCPPQ+PSfiwC8WD9KfxMbrSvu65nsdpaWz2SAWsOimM5PNQi
+A1rqL3BLBqByZmXKNrcpeSHLEdBhmNqfso5LIjIjvkcsaNaOrdR8kOvH9rmeqShs
+WAaIIwFuBt/PV0uwcNb5Dg5z6DPaUO2K/0+m9yYipHB6Cbxkit7vfCyKf8gCeyyf
+FGqvGCDkjWmR8ofTXVevicssj79LSy4TawhI3o1WlMI0/EZ/V17hkqMV7DuFoiUc
+Jt19WaJWftg2JsAK6wUnJ6mvBze62Fy6YwBdlA7ZEAV4mP+Lpw==
+-----END CERTIFICATE-----
+`
@@ -9,9 +9,9 @@ package jsontest
 
 import (
 	"bytes"
+	"compress/zstd"
 	"embed"
 	"errors"
-	"internal/zstd"
 	"io"
 	"io/fs"
 	"path"
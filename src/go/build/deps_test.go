@@ -188,6 +188,9 @@ var depsRules = `
 
 	unicode !< strconv;
 
+	TIME, strconv
+	< container/cache;
+
 	# STR is basic string and buffer manipulation.
 	RUNTIME, io, unicode/utf8, unicode/utf16, unicode
 	< bytes, strings
@@ -306,7 +309,7 @@ var depsRules = `
 
 	# compression
 	FMT, encoding/binary, hash/adler32, hash/crc32, sort
-	< compress/bzip2, compress/flate, compress/lzw, internal/zstd
+	< compress/bzip2, compress/flate, compress/lzw, compress/zstd
 	< archive/zip, compress/gzip, compress/zlib;
 
 	# templates
@@ -337,7 +340,7 @@ var depsRules = `
 	< index/suffixarray;
 
 	# executable parsing
-	FMT, encoding/binary, compress/zlib, internal/saferio, internal/zstd, sort
+	FMT, encoding/binary, compress/zlib, internal/saferio, compress/zstd, sort
 	< runtime/debug
 	< debug/dwarf
 	< debug/elf, debug/gosym, debug/macho, debug/pe, debug/plan9obj, internal/xcoff
@@ -431,6 +434,7 @@ var depsRules = `
 	# This is a long-looking list but most of these
 	# are small with few dependencies.
 	CGO,
+	container/cache,
 	golang.org/x/net/dns/dnsmessage,
 	golang.org/x/net/lif,
 	internal/godebug,
@@ -523,6 +527,7 @@ var depsRules = `
 	< crypto/internal/fips140/hkdf
 	< crypto/internal/fips140/mlkem
 	< crypto/internal/fips140/mldsa
+	< crypto/internal/fips140/slhdsa
 	< crypto/internal/fips140/ssh
 	< crypto/internal/fips140/tls12
 	< crypto/internal/fips140/tls13
@@ -570,12 +575,10 @@ var depsRules = `
 	  crypto/pbkdf2,
 	  crypto/ecdh,
 	  crypto/mlkem,
-	  crypto/mldsa
+	  crypto/mldsa,
+	  crypto/slhdsa
 	< CRYPTO;
 
-	CRYPTO
-	< golang.org/x/crypto/hkdf;
-
 	CGO, fmt, net !< CRYPTO;
 
 	# CRYPTO-MATH is crypto that exposes math/big APIs - no cgo, net; fmt now ok.
@@ -598,6 +601,10 @@ var depsRules = `
 	crypto/rand, errors, encoding/binary, encoding/hex
 	< uuid;
 
+	# password hashing
+	crypto/rand, encoding/base64, encoding/binary
+	< crypto/password;
+
 	# databases
 	FMT, uuid
 	< database/sql/internal
@@ -621,11 +628,16 @@ var depsRules = `
 	CRYPTO-MATH, golang.org/x/crypto/chacha20poly1305
 	< crypto/hpke;
 
+	CRYPTO-MATH, encoding/json
+	< crypto/jose;
+
 	CRYPTO-MATH, NET, container/list, encoding/hex, encoding/pem, crypto/hpke,
 	golang.org/x/crypto/chacha20poly1305, crypto/tls/internal/fips140tls
 	< crypto/x509/internal/macos
 	< crypto/x509/pkix
-	< crypto/x509
+	< crypto/x509;
+
+	crypto/x509, compress/zlib, compress/zstd
 	< crypto/tls;
 
 	# crypto-aware packages
@@ -667,6 +679,10 @@ var depsRules = `
 	NET, crypto/tls
 	< net/http/httptrace;
 
+	log/slog, NET, crypto/tls
+	< net/quic/internal/quicwire
+	< net/quic;
+
 	compress/gzip,
 	golang.org/x/net/http/httpguts,
 	golang.org/x/net/http/httpproxy,
@@ -675,25 +691,26 @@ var depsRules = `
 	net/http/internal/ascii,
 	net/http/internal/testcert,
 	net/http/httptrace,
+	net/quic,
 	mime/multipart,
 	log
 	< net/http/internal/httpcommon, net/http/internal/httpsfv
 	< net/http/internal/http2
 	< net/http;
 
-	net/http, golang.org/x/crypto/hkdf, log/slog
-	< golang.org/x/net/internal/quic/quicwire
-	< golang.org/x/net/quic, golang.org/x/net/internal/httpcommon
-	< golang.org/x/net/internal/http3
-	< golang.org/x/net/http3;
-
 	# HTTP-aware packages
 
 	encoding/json, net/http
 	< expvar;
 
-	net/http, net/http/internal/ascii
-	< net/http/cookiejar, net/http/httputil;
+	net/http, net/http/internal/ascii, hash/fnv
+	< net/http/cookiejar, net/http/httputil, net/http/sse;
+
+	net/http
+	< net/securedns;
+
+	net/http, net/http/internal, net/http/internal/ascii
+	< net/http/websocket;
 
 	net/http, regexp
 	< net/http/cgi
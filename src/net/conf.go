@@ -194,7 +194,7 @@ func goosPrefersCgo() bool {
 // required to use the go resolver. The provided Resolver is optional.
 // This will report true if the cgo resolver is not available.
 func (c *conf) mustUseGoResolver(r *Resolver) bool {
-	if !cgoAvailable {
+	if !cgoAvailable || r.hasExchange() {
 		return true
 	}
 
@@ -0,0 +1,194 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"container/cache"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	defaultDNSCacheEntries = 1000
+	defaultDNSCacheMaxTTL  = 24 * time.Hour
+
+	// dnsCachePrefetchDivisor controls prefetching: an entry is refreshed
+	// when it is used with less than 1/dnsCachePrefetchDivisor of its time
+	// to live remaining.
+	dnsCachePrefetchDivisor = 10
+)
+
+// A DNSCache caches responses to the queries made by Go's built-in DNS
+// resolver. It is used by setting the Cache field of a [Resolver].
+//
+// Responses are cached for the time to live of their records, as specified
+// in RFC 1035, and negative responses, for names or records that don't
+// exist, as specified in RFC 2308. Errors, such as timeouts and SERVFAIL
+// responses, are not cached. When a cached response is used shortly before
+// it expires, it is refreshed in the background, so that names in frequent
+// use don't miss the cache.
+//
+// A DNSCache is safe for concurrent use by multiple goroutines, and may be
+// shared by Resolvers that query the same name servers. The zero DNSCache
+// is ready to use. A DNSCache must not be copied after first use.
+type DNSCache struct {
+	// MaxEntries is the maximum number of responses in the cache. When it
+	// is reached, the least recently used responses are evicted.
+	// If zero, a default of 1000 is used.
+	MaxEntries int
+
+	// MaxTTL is the maximum time a response is cached, regardless of the
+	// time to live of its records. If zero, a default of 24 hours is used.
+	MaxTTL time.Duration
+
+	once    sync.Once
+	entries *cache.Cache[dnsCacheKey, *dnsCacheEntry]
+	now     func() time.Time // for testing
+}
+
+type dnsCacheKey struct {
+	name  string // rooted and in lower case
+	qtype dnsmessage.Type
+}
+
+type dnsCacheEntry struct {
+	// p is the response, positioned at the start of the answer section.
+	// It is unused if notFound is set.
+	p        dnsmessage.Parser
+	server   string
+	notFound bool
+
+	ttl         time.Duration
+	expires     time.Time
+	prefetching atomic.Bool
+}
+
+func (c *DNSCache) init() {
+	c.once.Do(func() {
+		maxEntries := c.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultDNSCacheEntries
+		}
+		c.entries = cache.New(&cache.Options[dnsCacheKey, *dnsCacheEntry]{
+			MaxEntries: maxEntries,
+		})
+		if c.now == nil {
+			c.now = time.Now
+		}
+	})
+}
+
+// Flush removes all responses from the cache.
+func (c *DNSCache) Flush() {
+	c.init()
+	c.entries.Clear()
+}
+
+func newDNSCacheKey(name string, qtype dnsmessage.Type) dnsCacheKey {
+	b := []byte(name)
+	lowerASCIIBytes(b)
+	return dnsCacheKey{name: string(b), qtype: qtype}
+}
+
+// get returns the cached response for key, if any, and whether it should be
+// prefetched. Only the first caller told to prefetch an entry is.
+func (c *DNSCache) get(key dnsCacheKey) (e *dnsCacheEntry, prefetch bool) {
+	c.init()
+	e, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+	remaining := e.expires.Sub(c.now())
+	if remaining <= 0 {
+		c.entries.Delete(key)
+		return nil, false
+	}
+	if remaining < e.ttl/dnsCachePrefetchDivisor {
+		prefetch = e.prefetching.CompareAndSwap(false, true)
+	}
+	return e, prefetch
+}
+
+// put caches a response. start is positioned at the start of the answer
+// section of the response, and notFound reports whether it is negative.
+// Responses without a time to live, such as negative responses without an
+// SOA record, are not cached.
+func (c *DNSCache) put(key dnsCacheKey, start dnsmessage.Parser, server string, notFound bool) {
+	ttl, ok := dnsResponseTTL(start, notFound)
+	if !ok || ttl == 0 {
+		return
+	}
+	c.init()
+	maxTTL := c.MaxTTL
+	if maxTTL <= 0 {
+		maxTTL = defaultDNSCacheMaxTTL
+	}
+	d := min(time.Duration(ttl)*time.Second, maxTTL)
+	e := &dnsCacheEntry{
+		server:   server,
+		notFound: notFound,
+		ttl:      d,
+		expires:  c.now().Add(d),
+	}
+	if !notFound {
+		e.p = start
+	}
+	c.entries.SetWithTTL(key, e, d)
+}
+
+// cacheResponse stores a response in r.Cache, if set.
+func (r *Resolver) cacheResponse(name string, qtype dnsmessage.Type, start dnsmessage.Parser, server string, notFound bool) {
+	if c := r.getCache(); c != nil {
+		c.put(newDNSCacheKey(name, qtype), start, server, notFound)
+	}
+}
+
+// dnsResponseTTL returns the number of seconds a response can be cached,
+// given a parser positioned at the start of its answer section. That is the
+// lowest TTL of the answer records, or for a negative response, the lower of
+// the TTL and the MINIMUM field of the SOA record in the authority section,
+// as specified in RFC 2308, Section 5.
+func dnsResponseTTL(p dnsmessage.Parser, notFound bool) (ttl uint32, ok bool) {
+	if notFound {
+		if err := p.SkipAllAnswers(); err != nil {
+			return 0, false
+		}
+		for {
+			h, err := p.AuthorityHeader()
+			if err != nil {
+				return 0, false
+			}
+			if h.Type != dnsmessage.TypeSOA {
+				if err := p.SkipAuthority(); err != nil {
+					return 0, false
+				}
+				continue
+			}
+			soa, err := p.SOAResource()
+			if err != nil {
+				return 0, false
+			}
+			return min(h.TTL, soa.MinTTL), true
+		}
+	}
+	for {
+		h, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			return ttl, ok
+		}
+		if err != nil {
+			return 0, false
+		}
+		if !ok || h.TTL < ttl {
+			ttl, ok = h.TTL, true
+		}
+		if err := p.SkipAnswer(); err != nil {
+			return 0, false
+		}
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package net
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsCacheTestServer answers queries for A records with TestAddr, and for
+// other records with an empty answer. Names starting with "nx" don't exist,
+// and names starting with "fail" get a SERVFAIL response. Negative responses
+// have an SOA record unless the name contains "nosoa".
+type dnsCacheTestServer struct {
+	ttl, soaTTL, soaMinTTL uint32
+
+	mu      sync.Mutex
+	queries map[dnsCacheKey]int
+}
+
+func (s *dnsCacheTestServer) exchange(ctx context.Context, query []byte) ([]byte, error) {
+	var q dnsmessage.Message
+	if err := q.Unpack(query); err != nil {
+		return nil, err
+	}
+	if len(q.Questions) != 1 {
+		return nil, errors.New("unexpected number of questions")
+	}
+	name := q.Questions[0].Name.String()
+	qtype := q.Questions[0].Type
+	s.mu.Lock()
+	if s.queries == nil {
+		s.queries = make(map[dnsCacheKey]int)
+	}
+	s.queries[dnsCacheKey{name, qtype}]++
+	s.mu.Unlock()
+
+	r := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 q.Header.ID,
+			Response:           true,
+			RecursionAvailable: true,
+		},
+		Questions: q.Questions,
+	}
+	switch {
+	case strings.HasPrefix(name, "fail"):
+		r.Header.RCode = dnsmessage.RCodeServerFailure
+		return r.Pack()
+	case strings.HasPrefix(name, "nx"):
+		r.Header.RCode = dnsmessage.RCodeNameError
+	case qtype == dnsmessage.TypeA:
+		r.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{
+				Name:  q.Questions[0].Name,
+				Type:  dnsmessage.TypeA,
+				Class: dnsmessage.ClassINET,
+				TTL:   s.ttl,
+			},
+			Body: &dnsmessage.AResource{A: TestAddr},
+		}}
+		return r.Pack()
+	}
+	if !strings.HasSuffix(name, "nosoa.go.dev.") {
+		r.Authorities = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{
+				Name:  dnsmessage.MustNewName("go.dev."),
+				Type:  dnsmessage.TypeSOA,
+				Class: dnsmessage.ClassINET,
+				TTL:   s.soaTTL,
+			},
+			Body: &dnsmessage.SOAResource{
+				NS:     dnsmessage.MustNewName("ns.go.dev."),
+				MBox:   dnsmessage.MustNewName("hostmaster.go.dev."),
+				MinTTL: s.soaMinTTL,
+			},
+		}}
+	}
+	return r.Pack()
+}
+
+func (s *dnsCacheTestServer) count(name string, qtype dnsmessage.Type) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries[dnsCacheKey{name, qtype}]
+}
+
+// newDNSCacheTest returns a resolver using a cache whose clock is advanced
+// by the returned function.
+func newDNSCacheTest(s *dnsCacheTestServer, c *DNSCache) (r *Resolver, advance func(time.Duration)) {
+	start := time.Now()
+	var elapsed atomic.Int64
+	c.now = func() time.Time {
+		return start.Add(time.Duration(elapsed.Load()))
+	}
+	r = &Resolver{Exchange: s.exchange, Cache: c}
+	return r, func(d time.Duration) { elapsed.Add(int64(d)) }
+}
+
+func TestDNSCache(t *testing.T) {
+	defer dnsWaitGroup.Wait()
+	s := &dnsCacheTestServer{ttl: 60, soaTTL: 120, soaMinTTL: 30}
+	r, advance := newDNSCacheTest(s, &DNSCache{})
+	ctx := context.Background()
+	const name = "cached.go.dev."
+
+	lookup := func(host string, wantA, wantAAAA int) {
+		t.Helper()
+		addrs, err := r.LookupHost(ctx, host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := IP(TestAddr[:]).String(); len(addrs) != 1 || addrs[0] != want {
+			t.Fatalf("LookupHost(%q) = %v, want [%v]", host, addrs, want)
+		}
+		if got := s.count(name, dnsmessage.TypeA); got != wantA {
+			t.Errorf("after LookupHost(%q): %d A queries, want %d", host, got, wantA)
+		}
+		if got := s.count(name, dnsmessage.TypeAAAA); got != wantAAAA {
+			t.Errorf("after LookupHost(%q): %d AAAA queries, want %d", host, got, wantAAAA)
+		}
+	}
+
+	lookup(name, 1, 1)
+	lookup(name, 1, 1)
+	lookup("CACHED.go.dev.", 1, 1)
+
+	// The AAAA response is negative, cached for the MINIMUM of the SOA.
+	advance(31 * time.Second)
+	lookup(name, 1, 2)
+
+	advance(30 * time.Second)
+	lookup(name, 2, 3)
+
+	r.Cache.Flush()
+	lookup(name, 3, 4)
+}
+
+func TestDNSCacheNegative(t *testing.T) {
+	s := &dnsCacheTestServer{ttl: 60, soaTTL: 20, soaMinTTL: 300}
+	r, advance := newDNSCacheTest(s, &DNSCache{})
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name      string
+		cached    bool
+		temporary bool
+	}{
+		{"nx.go.dev.", true, false},
+		{"nx.nosoa.go.dev.", false, false},
+		{"fail.go.dev.", false, true},
+	} {
+		for i := range 2 {
+			_, err := r.LookupIP(ctx, "ip4", tt.name)
+			de, ok := errors.AsType[*DNSError](err)
+			if !ok {
+				t.Fatalf("LookupIP(%q) error = %v, want DNSError", tt.name, err)
+			}
+			if de.IsNotFound == tt.temporary || de.IsTemporary != tt.temporary {
+				t.Errorf("LookupIP(%q) error = %#v", tt.name, de)
+			}
+			// Temporary errors are retried.
+			perLookup := 1
+			if tt.temporary {
+				perLookup = getSystemDNSConfig().attempts
+			}
+			want := (i + 1) * perLookup
+			if tt.cached {
+				want = 1
+			}
+			if got := s.count(tt.name, dnsmessage.TypeA); got != want {
+				t.Errorf("%s: %d queries after %d lookups, want %d", tt.name, got, i+1, want)
+			}
+		}
+	}
+
+	// The negative response is cached for the TTL of the SOA record.
+	advance(21 * time.Second)
+	r.LookupIP(ctx, "ip4", "nx.go.dev.")
+	if got := s.count("nx.go.dev.", dnsmessage.TypeA); got != 2 {
+		t.Errorf("nx.go.dev.: %d queries after expiration, want 2", got)
+	}
+}
+
+func TestDNSCacheZeroTTL(t *testing.T) {
+	s := &dnsCacheTestServer{ttl: 0}
+	r, _ := newDNSCacheTest(s, &DNSCache{})
+	for range 2 {
+		if _, err := r.LookupIP(context.Background(), "ip4", "zero.go.dev."); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := s.count("zero.go.dev.", dnsmessage.TypeA); got != 2 {
+		t.Errorf("%d queries, want 2", got)
+	}
+}
+
+func TestDNSCachePrefetch(t *testing.T) {
+	s := &dnsCacheTestServer{ttl: 100}
+	r, advance := newDNSCacheTest(s, &DNSCache{})
+	ctx := context.Background()
+	const name = "prefetch.go.dev."
+
+	lookup := func(want int) {
+		t.Helper()
+		if _, err := r.LookupIP(ctx, "ip4", name); err != nil {
+			t.Fatal(err)
+		}
+		dnsWaitGroup.Wait()
+		if got := s.count(name, dnsmessage.TypeA); got != want {
+			t.Errorf("%d queries, want %d", got, want)
+		}
+	}
+
+	lookup(1)
+	advance(89 * time.Second)
+	lookup(1)
+
+	// With less than 10% of the TTL remaining, the cached response is
+	// used, and refreshed in the background, only once.
+	advance(2 * time.Second)
+	lookup(2)
+	lookup(2)
+
+	// The refreshed response is used after the first one would have
+	// expired.
+	advance(20 * time.Second)
+	lookup(2)
+}
+
+func TestDNSCacheLimits(t *testing.T) {
+	s := &dnsCacheTestServer{ttl: 60}
+	r, advance := newDNSCacheTest(s, &DNSCache{MaxEntries: 2, MaxTTL: 10 * time.Second})
+	ctx := context.Background()
+
+	lookup := func(name string, want int) {
+		t.Helper()
+		if _, err := r.LookupIP(ctx, "ip4", name); err != nil {
+			t.Fatal(err)
+		}
+		if got := s.count(name, dnsmessage.TypeA); got != want {
+			t.Errorf("%s: %d queries, want %d", name, got, want)
+		}
+	}
+
+	lookup("a.go.dev.", 1)
+	lookup("b.go.dev.", 1)
+	lookup("a.go.dev.", 1)
+	lookup("c.go.dev.", 1) // evicts b, the least recently used
+	lookup("a.go.dev.", 1)
+	lookup("b.go.dev.", 2)
+
+	advance(11 * time.Second)
+	lookup("a.go.dev.", 2)
+}
+
+func TestDNSResponseTTL(t *testing.T) {
+	name := dnsmessage.MustNewName("ttl.go.dev.")
+	a := func(ttl uint32) dnsmessage.Resource {
+		return dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: ttl},
+			Body:   &dnsmessage.AResource{A: TestAddr},
+		}
+	}
+	cname := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: 5},
+		Body:   &dnsmessage.CNAMEResource{CNAME: name},
+	}
+	ns := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeNS, Class: dnsmessage.ClassINET, TTL: 1},
+		Body:   &dnsmessage.NSResource{NS: name},
+	}
+	soa := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET, TTL: 50},
+		Body:   &dnsmessage.SOAResource{NS: name, MBox: name, MinTTL: 40},
+	}
+
+	for _, tt := range []struct {
+		desc        string
+		notFound    bool
+		answers     []dnsmessage.Resource
+		authorities []dnsmessage.Resource
+		ttl         uint32
+		ok          bool
+	}{
+		{"single", false, []dnsmessage.Resource{a(30)}, nil, 30, true},
+		{"lowest", false, []dnsmessage.Resource{a(30), a(20), a(40)}, nil, 20, true},
+		{"cname", false, []dnsmessage.Resource{cname, a(30)}, nil, 5, true},
+		{"empty", false, nil, nil, 0, false},
+		{"negative", true, nil, []dnsmessage.Resource{ns, soa}, 40, true},
+		{"negative-cname", true, []dnsmessage.Resource{cname}, []dnsmessage.Resource{soa}, 40, true},
+		{"negative-no-soa", true, nil, []dnsmessage.Resource{ns}, 0, false},
+	} {
+		msg := dnsmessage.Message{
+			Header:      dnsmessage.Header{Response: true},
+			Questions:   []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+			Answers:     tt.answers,
+			Authorities: tt.authorities,
+		}
+		b, err := msg.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var p dnsmessage.Parser
+		if _, err := p.Start(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.SkipAllQuestions(); err != nil {
+			t.Fatal(err)
+		}
+		ttl, ok := dnsResponseTTL(p, tt.notFound)
+		if ttl != tt.ttl || ok != tt.ok {
+			t.Errorf("%s: dnsResponseTTL = %d, %v, want %d, %v", tt.desc, ttl, ok, tt.ttl, tt.ok)
+		}
+	}
+}
@@ -172,6 +172,9 @@ func (r *Resolver) exchange(ctx context.Context, server string, q dnsmessage.Que
 	if err != nil {
 		return dnsmessage.Parser{}, dnsmessage.Header{}, errCannotMarshalDNSMessage
 	}
+	if r.hasExchange() {
+		return r.customExchange(ctx, id, q, udpReq, timeout)
+	}
 	var networks []string
 	if useTCP {
 		networks = []string{"tcp"}
@@ -218,6 +221,33 @@ func (r *Resolver) exchange(ctx context.Context, server string, q dnsmessage.Que
 	return dnsmessage.Parser{}, dnsmessage.Header{}, errNoAnswerFromDNSServer
 }
 
+// customExchange sends a query with r.Exchange.
+func (r *Resolver) customExchange(ctx context.Context, id uint16, q dnsmessage.Question, req []byte, timeout time.Duration) (dnsmessage.Parser, dnsmessage.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	b, err := r.Exchange(ctx, req)
+	if err != nil {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, mapErr(err)
+	}
+	var p dnsmessage.Parser
+	h, err := p.Start(b)
+	if err != nil {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errCannotUnmarshalDNSMessage
+	}
+	respQ, err := p.Question()
+	if err != nil {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errCannotUnmarshalDNSMessage
+	}
+	if !checkResponse(id, q, h, respQ) {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errInvalidDNSResponse
+	}
+	if err := p.SkipQuestion(); err != dnsmessage.ErrSectionDone {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errInvalidDNSResponse
+	}
+	return p, h, nil
+}
+
 // checkHeader performs basic sanity checks on the header.
 func checkHeader(p *dnsmessage.Parser, h dnsmessage.Header) error {
 	rcode, hasAdd := extractExtendedRCode(*p, h)
@@ -294,10 +324,46 @@ func extractExtendedRCode(p dnsmessage.Parser, hdr dnsmessage.Header) (dnsmessag
 
 // Do a lookup for a single name, which must be rooted
 // (otherwise answer will not find the answers).
+// The response is taken from r.Cache, if possible.
 func (r *Resolver) tryOneName(ctx context.Context, cfg *dnsConfig, name string, qtype dnsmessage.Type) (dnsmessage.Parser, string, error) {
+	c := r.getCache()
+	if c == nil {
+		return r.queryOneName(ctx, cfg, name, qtype)
+	}
+	e, prefetch := c.get(newDNSCacheKey(name, qtype))
+	if e == nil {
+		return r.queryOneName(ctx, cfg, name, qtype)
+	}
+	if prefetch {
+		dnsWaitGroup.Add(1)
+		go func() {
+			defer dnsWaitGroup.Done()
+			// Each attempt has its own timeout.
+			r.queryOneName(context.Background(), cfg, name, qtype)
+		}()
+	}
+	if e.notFound {
+		return dnsmessage.Parser{}, e.server, newDNSError(errNoSuchHost, name, e.server)
+	}
+	p := e.p
+	if err := skipToAnswer(&p, qtype); err != nil {
+		// Not reached: the response was checked before it was cached.
+		return r.queryOneName(ctx, cfg, name, qtype)
+	}
+	return p, e.server, nil
+}
+
+// queryOneName is like tryOneName, but always queries the name servers.
+// The response is stored in r.Cache, if set.
+func (r *Resolver) queryOneName(ctx context.Context, cfg *dnsConfig, name string, qtype dnsmessage.Type) (dnsmessage.Parser, string, error) {
 	var lastErr error
+	servers := cfg.servers
+	if r.hasExchange() {
+		// The upstream servers are chosen by r.Exchange.
+		servers = []string{""}
+	}
 	serverOffset := cfg.serverOffset()
-	sLen := uint32(len(cfg.servers))
+	sLen := uint32(len(servers))
 
 	n, err := dnsmessage.NewName(name)
 	if err != nil {
@@ -311,7 +377,7 @@ func (r *Resolver) tryOneName(ctx context.Context, cfg *dnsConfig, name string,
 
 	for i := 0; i < cfg.attempts; i++ {
 		for j := uint32(0); j < sLen; j++ {
-			server := cfg.servers[(serverOffset+j)%sLen]
+			server := servers[(serverOffset+j)%sLen]
 
 			p, h, err := r.exchange(ctx, server, q, cfg.timeout, cfg.useTCP, cfg.trustAD)
 			if err != nil {
@@ -324,11 +390,13 @@ func (r *Resolver) tryOneName(ctx context.Context, cfg *dnsConfig, name string,
 				lastErr = dnsErr
 				continue
 			}
+			start := p
 
 			if err := checkHeader(&p, h); err != nil {
 				if err == errNoSuchHost {
 					// The name does not exist, so trying
 					// another server won't help.
+					r.cacheResponse(name, qtype, start, server, true)
 					return p, server, newDNSError(errNoSuchHost, name, server)
 				}
 				lastErr = newDNSError(err, name, server)
@@ -339,12 +407,14 @@ func (r *Resolver) tryOneName(ctx context.Context, cfg *dnsConfig, name string,
 				if err == errNoSuchHost {
 					// The name does not exist, so trying
 					// another server won't help.
+					r.cacheResponse(name, qtype, start, server, true)
 					return p, server, newDNSError(errNoSuchHost, name, server)
 				}
 				lastErr = newDNSError(err, name, server)
 				continue
 			}
 
+			r.cacheResponse(name, qtype, start, server, false)
 			return p, server, nil
 		}
 	}
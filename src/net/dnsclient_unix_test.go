@@ -2881,3 +2881,80 @@ func TestEmptyResolvConfReplacedWithConfHaingNameservers(t *testing.T) {
 		t.Fatal("resolv.conf was not re-loaded")
 	}
 }
+
+func TestDNSExchange(t *testing.T) {
+	var badID atomic.Bool
+	exchange := func(ctx context.Context, query []byte) ([]byte, error) {
+		var q dnsmessage.Message
+		if err := q.Unpack(query); err != nil {
+			return nil, err
+		}
+		if len(q.Questions) != 1 {
+			return nil, errors.New("unexpected number of questions")
+		}
+		if q.Questions[0].Name.String() == "fail.go.dev." {
+			return nil, errors.New("exchange failed")
+		}
+		r := dnsmessage.Message{
+			Header: dnsmessage.Header{
+				ID:                 q.Header.ID,
+				Response:           true,
+				RecursionAvailable: true,
+				RCode:              dnsmessage.RCodeSuccess,
+			},
+			Questions: q.Questions,
+		}
+		if badID.Load() {
+			r.Header.ID++
+		}
+		switch {
+		case q.Questions[0].Name.String() != "exchange.go.dev.":
+			r.Header.RCode = dnsmessage.RCodeNameError
+		case q.Questions[0].Type == dnsmessage.TypeA:
+			r.Answers = []dnsmessage.Resource{{
+				Header: dnsmessage.ResourceHeader{
+					Name:  q.Questions[0].Name,
+					Type:  dnsmessage.TypeA,
+					Class: dnsmessage.ClassINET,
+				},
+				Body: &dnsmessage.AResource{A: TestAddr},
+			}}
+		}
+		return r.Pack()
+	}
+	r := &Resolver{
+		Exchange: exchange,
+		Dial: func(ctx context.Context, network, address string) (Conn, error) {
+			t.Errorf("unexpected Dial(%q, %q)", network, address)
+			return nil, errors.New("unexpected dial")
+		},
+	}
+	if !systemConf().mustUseGoResolver(r) {
+		t.Errorf("Go resolver not used with Resolver.Exchange")
+	}
+
+	ctx := context.Background()
+	addrs, err := r.LookupHost(ctx, "exchange.go.dev.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := IP(TestAddr[:]).String(); len(addrs) != 1 || addrs[0] != want {
+		t.Errorf("LookupHost = %v, want [%v]", addrs, want)
+	}
+
+	_, err = r.LookupHost(ctx, "nonexistent.go.dev.")
+	if dnsErr, ok := errors.AsType[*DNSError](err); !ok || !dnsErr.IsNotFound {
+		t.Errorf("LookupHost of a nonexistent name: got %v, want a not found DNSError", err)
+	}
+
+	_, err = r.LookupHost(ctx, "fail.go.dev.")
+	if dnsErr, ok := errors.AsType[*DNSError](err); !ok || dnsErr.Err != "exchange failed" {
+		t.Errorf("LookupHost with a failing Exchange: got %v, want a DNSError", err)
+	}
+
+	badID.Store(true)
+	_, err = r.LookupHost(ctx, "exchange.go.dev.")
+	if dnsErr, ok := errors.AsType[*DNSError](err); !ok || dnsErr.Err != errInvalidDNSResponse.Error() {
+		t.Errorf("LookupHost with a mismatched response ID: got %v, want %v", err, errInvalidDNSResponse)
+	}
+}
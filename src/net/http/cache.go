@@ -0,0 +1,816 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// HTTP caching, as described in RFC 9111.
+
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"io"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A CacheStorage stores the responses cached by a [CachingTransport].
+//
+// Entries are opaque byte slices identified by string keys.
+// An implementation may discard entries at any time,
+// for example to limit its size.
+//
+// A CacheStorage must be safe for concurrent use by multiple goroutines.
+type CacheStorage interface {
+	// Get returns the entry stored under key, if any.
+	// The caller must not modify the returned slice.
+	Get(key string) (value []byte, ok bool)
+
+	// Set stores value under key, replacing any existing entry.
+	// The caller does not modify value after calling Set.
+	Set(key string, value []byte)
+
+	// Delete removes the entry stored under key, if any.
+	Delete(key string)
+}
+
+// A MemoryCacheStorage is a [CacheStorage] that keeps entries in memory.
+// When its size limit is reached, the least recently used entries
+// are discarded.
+type MemoryCacheStorage struct {
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     list.List // of *memoryCacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryCacheStorage returns a new [MemoryCacheStorage] holding up to
+// maxSize bytes of keys and values. Entries larger than maxSize are not
+// stored.
+func NewMemoryCacheStorage(maxSize int64) *MemoryCacheStorage {
+	return &MemoryCacheStorage{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get implements [CacheStorage].
+func (s *MemoryCacheStorage) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	s.lru.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).value, true
+}
+
+// Set implements [CacheStorage].
+func (s *MemoryCacheStorage) Set(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteLocked(key)
+	size := int64(len(key) + len(value))
+	if size > s.maxSize {
+		return
+	}
+	for s.size+size > s.maxSize {
+		s.deleteLocked(s.lru.Back().Value.(*memoryCacheEntry).key)
+	}
+	s.entries[key] = s.lru.PushFront(&memoryCacheEntry{key, value})
+	s.size += size
+}
+
+// Delete implements [CacheStorage].
+func (s *MemoryCacheStorage) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteLocked(key)
+}
+
+func (s *MemoryCacheStorage) deleteLocked(key string) {
+	e, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	ent := s.lru.Remove(e).(*memoryCacheEntry)
+	delete(s.entries, key)
+	s.size -= int64(len(ent.key) + len(ent.value))
+}
+
+const (
+	// defaultCacheStorageSize is the size of the storage used by a
+	// CachingTransport with a nil Storage.
+	defaultCacheStorageSize = 64 << 20
+
+	// maxCacheBodySize is the largest response body that is cached.
+	maxCacheBodySize = 8 << 20
+
+	// maxHeuristicFreshness limits the freshness lifetime that is
+	// computed from a response's Last-Modified header.
+	maxHeuristicFreshness = 24 * time.Hour
+)
+
+// CachingTransport is a [RoundTripper] that implements an HTTP cache,
+// as described in RFC 9111. It stores the responses to GET requests and
+// uses them to answer later requests for the same URL, either directly
+// while they are fresh or after revalidating them with the server.
+//
+// CachingTransport honors the Cache-Control directives of requests and
+// responses, including max-age, no-cache, no-store, must-revalidate,
+// max-stale, min-fresh, only-if-cached and stale-while-revalidate, as
+// well as the Expires, Vary and Age headers. When a response has no
+// explicit expiration time, its freshness is estimated from its
+// Last-Modified header. Stale responses are revalidated with a
+// conditional request using their ETag and Last-Modified headers.
+// A successful response to an unsafe request, such as POST, invalidates
+// the stored response for its URL.
+//
+// Requests with a Range header or conditional headers such as
+// If-None-Match are not answered from the cache, and their responses are
+// not stored. Neither are responses whose bodies are larger than 8 MiB.
+//
+// Responses served from the cache have an Age header, whose value is
+// the estimated time in seconds since the response was generated by the
+// server.
+//
+// A CachingTransport is safe for concurrent use by multiple goroutines.
+type CachingTransport struct {
+	// Transport is used to make the requests that can't be answered
+	// from the cache.
+	// If nil, DefaultTransport is used.
+	Transport RoundTripper
+
+	// Storage holds the cached responses.
+	// If nil, a MemoryCacheStorage of 64 MiB is used.
+	Storage CacheStorage
+
+	// Shared specifies whether the cache is shared between users,
+	// as in a proxy. A shared cache does not store responses marked
+	// private, honors the s-maxage and proxy-revalidate directives,
+	// and does not cache requests with an Authorization header.
+	// By default, the cache is private to a single user.
+	Shared bool
+
+	storageOnce    sync.Once
+	defaultStorage CacheStorage
+
+	now func() time.Time // for testing; nil means time.Now
+
+	mu           sync.Mutex
+	revalidating map[string]bool // keys with a background revalidation
+}
+
+func (t *CachingTransport) transport() RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return DefaultTransport
+}
+
+func (t *CachingTransport) storage() CacheStorage {
+	if t.Storage != nil {
+		return t.Storage
+	}
+	t.storageOnce.Do(func() {
+		t.defaultStorage = NewMemoryCacheStorage(defaultCacheStorageSize)
+	})
+	return t.defaultStorage
+}
+
+func (t *CachingTransport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// CloseIdleConnections closes any idle connections of the underlying
+// Transport, if it has a CloseIdleConnections method.
+func (t *CachingTransport) CloseIdleConnections() {
+	type closeIdler interface {
+		CloseIdleConnections()
+	}
+	if tr, ok := t.transport().(closeIdler); ok {
+		tr.CloseIdleConnections()
+	}
+}
+
+// RoundTrip implements the [RoundTripper] interface.
+func (t *CachingTransport) RoundTrip(req *Request) (*Response, error) {
+	switch valueOrDefault(req.Method, "GET") {
+	case "GET":
+	case "HEAD", "OPTIONS", "TRACE":
+		return t.transport().RoundTrip(req)
+	default:
+		// An unsafe method may change the resource. RFC 9111, Section 4.4.
+		resp, err := t.transport().RoundTrip(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 400 {
+			t.invalidate(req, resp)
+		}
+		return resp, err
+	}
+
+	reqCC := parseCacheControl(req.Header["Cache-Control"])
+	if _, ok := req.Header["Cache-Control"]; !ok && hasToken(req.Header.get("Pragma"), "no-cache") {
+		reqCC["no-cache"] = ""
+	}
+	if !t.cacheableRequest(req, reqCC) {
+		return t.transport().RoundTrip(req)
+	}
+
+	key := cacheKey(req.URL)
+	ent := t.lookup(key, req)
+	if ent == nil {
+		if reqCC.has("only-if-cached") {
+			return gatewayTimeoutResponse(req), nil
+		}
+		reqTime := t.clock()
+		resp, err := t.transport().RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		return t.store(key, req, resp, reqTime, t.clock()), nil
+	}
+
+	// Decide whether the stored response can be used. RFC 9111, Section 4.2.
+	age := ent.age(t.clock())
+	lifetime := ent.freshnessLifetime(t.Shared)
+	if v, ok := reqCC["max-age"]; ok {
+		d, _ := parseDeltaSeconds(v)
+		lifetime = min(lifetime, d)
+	}
+	if v, ok := reqCC["min-fresh"]; ok {
+		d, _ := parseDeltaSeconds(v)
+		lifetime -= d
+	}
+	if !reqCC.has("no-cache") && !ent.cc.has("no-cache") {
+		if age < lifetime {
+			return ent.serve(age), nil
+		}
+		mustRevalidate := ent.cc.has("must-revalidate") ||
+			t.Shared && (ent.cc.has("proxy-revalidate") || ent.cc.has("s-maxage"))
+		staleness := age - lifetime
+		if !mustRevalidate {
+			if v, ok := reqCC["max-stale"]; ok {
+				if d, ok := parseDeltaSeconds(v); v == "" || ok && staleness <= d {
+					return ent.serve(age), nil
+				}
+			}
+			if v, ok := ent.cc["stale-while-revalidate"]; ok {
+				if d, ok := parseDeltaSeconds(v); ok && staleness <= d {
+					t.revalidateInBackground(key, req)
+					return ent.serve(age), nil
+				}
+			}
+		}
+	}
+	if reqCC.has("only-if-cached") {
+		ent.resp.Body.Close()
+		return gatewayTimeoutResponse(req), nil
+	}
+	return t.revalidate(key, req, ent)
+}
+
+// cacheableRequest reports whether req may be answered from the cache.
+func (t *CachingTransport) cacheableRequest(req *Request, reqCC cacheControl) bool {
+	if reqCC.has("no-store") {
+		return false
+	}
+	if t.Shared && req.Header.has("Authorization") {
+		return false
+	}
+	for _, h := range []string{"Range", "If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since", "If-Range"} {
+		if req.Header.has(h) {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKey returns the storage key for the response to a GET request for u.
+func cacheKey(u *url.URL) string {
+	if u.Fragment == "" && u.RawFragment == "" {
+		return u.String()
+	}
+	u2 := *u
+	u2.Fragment, u2.RawFragment = "", ""
+	return u2.String()
+}
+
+// invalidate removes the stored responses that are invalidated by resp,
+// a successful response to an unsafe request.
+func (t *CachingTransport) invalidate(req *Request, resp *Response) {
+	t.storage().Delete(cacheKey(req.URL))
+	for _, h := range []string{"Location", "Content-Location"} {
+		v := resp.Header.Get(h)
+		if v == "" {
+			continue
+		}
+		u, err := req.URL.Parse(v)
+		if err != nil || u.Scheme != req.URL.Scheme || u.Host != req.URL.Host {
+			continue
+		}
+		t.storage().Delete(cacheKey(u))
+	}
+}
+
+// lookup returns the stored response for req, or nil if there is none.
+func (t *CachingTransport) lookup(key string, req *Request) *cacheEntry {
+	data, ok := t.storage().Get(key)
+	if !ok {
+		return nil
+	}
+	ent, err := decodeCacheEntry(data, req)
+	if err != nil {
+		t.storage().Delete(key)
+		return nil
+	}
+	if !ent.varyMatches(req) {
+		return nil
+	}
+	return ent
+}
+
+// store arranges for resp, the response to req, to be stored when its
+// body has been read, if it may be stored. It returns the response to
+// return to the caller.
+func (t *CachingTransport) store(key string, req *Request, resp *Response, reqTime, respTime time.Time) *Response {
+	cc := parseCacheControl(resp.Header["Cache-Control"])
+	if !t.storable(resp, cc) {
+		return resp
+	}
+	if resp.Header.Get("Date") == "" {
+		// A cache must add a missing Date header. RFC 9110, Section 6.6.1.
+		resp.Header.Set("Date", respTime.UTC().Format(TimeFormat))
+	}
+	ent := &cacheEntry{
+		resp: &Response{
+			Status:       resp.Status,
+			StatusCode:   resp.StatusCode,
+			Proto:        resp.Proto,
+			ProtoMajor:   resp.ProtoMajor,
+			ProtoMinor:   resp.ProtoMinor,
+			Header:       resp.Header.Clone(),
+			Uncompressed: resp.Uncompressed,
+		},
+		reqTime:  reqTime,
+		respTime: respTime,
+	}
+	vary := varyHeader(req, ent.resp.Header)
+	resp.Body = &cacheBody{
+		body: resp.Body,
+		done: func(body []byte) {
+			ent.body = body
+			t.storage().Set(key, ent.encode(vary))
+		},
+	}
+	return resp
+}
+
+// storable reports whether a response may be stored. RFC 9111, Section 3.
+func (t *CachingTransport) storable(resp *Response, cc cacheControl) bool {
+	if resp.StatusCode < 200 || resp.StatusCode == StatusPartialContent || resp.StatusCode == StatusNotModified {
+		return false
+	}
+	if resp.ContentLength > maxCacheBodySize {
+		return false
+	}
+	if cc.has("no-store") || t.Shared && cc.has("private") {
+		return false
+	}
+	for _, v := range resp.Header["Vary"] {
+		if hasToken(v, "*") {
+			return false
+		}
+	}
+	if _, ok := resp.Header["Expires"]; ok {
+		return true
+	}
+	if cc.has("max-age") || cc.has("public") || t.Shared && cc.has("s-maxage") {
+		return true
+	}
+	return heuristicallyCacheable(resp.StatusCode)
+}
+
+// heuristicallyCacheable reports whether responses with the status code
+// may be stored without explicit freshness information.
+// RFC 9110, Section 15.1.
+func heuristicallyCacheable(code int) bool {
+	switch code {
+	case 200, 203, 204, 206, 300, 301, 308, 404, 405, 410, 414, 501:
+		return true
+	}
+	return false
+}
+
+// revalidate sends a conditional request for the stored response ent
+// and returns either the updated stored response or the new response.
+// RFC 9111, Section 4.3.
+func (t *CachingTransport) revalidate(key string, req *Request, ent *cacheEntry) (*Response, error) {
+	creq := req.Clone(req.Context())
+	if etag := ent.resp.Header.Get("Etag"); etag != "" {
+		creq.Header.Set("If-None-Match", etag)
+	}
+	if lm := ent.resp.Header.Get("Last-Modified"); lm != "" {
+		creq.Header.Set("If-Modified-Since", lm)
+	}
+	reqTime := t.clock()
+	resp, err := t.transport().RoundTrip(creq)
+	if err != nil {
+		ent.resp.Body.Close()
+		return nil, err
+	}
+	respTime := t.clock()
+	resp.Request = req
+	if resp.StatusCode != StatusNotModified {
+		ent.resp.Body.Close()
+		return t.store(key, req, resp, reqTime, respTime), nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	// Update the stored response with the new header fields.
+	// RFC 9111, Section 3.2.
+	h := ent.resp.Header
+	h.Del("Age")
+	for k, vv := range resp.Header {
+		switch k {
+		case "Content-Length", "Content-Encoding", "Content-Range", "Transfer-Encoding", "Trailer",
+			"Connection", "Keep-Alive", "Proxy-Connection", "Upgrade":
+			continue
+		}
+		h[k] = vv
+	}
+	if resp.Header.Get("Date") == "" {
+		h.Set("Date", respTime.UTC().Format(TimeFormat))
+	}
+	ent.reqTime, ent.respTime = reqTime, respTime
+	ent.cc = parseCacheControl(h["Cache-Control"])
+	if ent.cc.has("no-store") {
+		t.storage().Delete(key)
+	} else {
+		t.storage().Set(key, ent.encode(varyHeader(req, h)))
+	}
+	return ent.serve(ent.age(t.clock())), nil
+}
+
+// revalidateInBackground revalidates the stored response for req
+// without delaying the caller, as permitted by the
+// stale-while-revalidate directive. RFC 5861, Section 3.
+func (t *CachingTransport) revalidateInBackground(key string, req *Request) {
+	t.mu.Lock()
+	if t.revalidating[key] {
+		t.mu.Unlock()
+		return
+	}
+	if t.revalidating == nil {
+		t.revalidating = make(map[string]bool)
+	}
+	t.revalidating[key] = true
+	t.mu.Unlock()
+
+	req = req.Clone(context.WithoutCancel(req.Context()))
+	go func() {
+		defer func() {
+			t.mu.Lock()
+			delete(t.revalidating, key)
+			t.mu.Unlock()
+		}()
+		ent := t.lookup(key, req)
+		if ent == nil {
+			return
+		}
+		resp, err := t.revalidate(key, req, ent)
+		if err != nil {
+			return
+		}
+		// Reading the body stores a new response.
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+}
+
+// gatewayTimeoutResponse returns the response to a request with the
+// only-if-cached directive that can't be answered from the cache.
+// RFC 9111, Section 5.2.1.7.
+func gatewayTimeoutResponse(req *Request) *Response {
+	return &Response{
+		Status:     "504 " + StatusText(StatusGatewayTimeout),
+		StatusCode: StatusGatewayTimeout,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(Header),
+		Body:       NoBody,
+		Request:    req,
+	}
+}
+
+// cacheBody is the body of a response that is being stored.
+// It calls done with the complete body when the body has been read
+// to the end.
+type cacheBody struct {
+	body    io.ReadCloser
+	buf     []byte
+	done    func(body []byte)
+	stopped bool
+}
+
+func (b *cacheBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if b.stopped {
+		return n, err
+	}
+	b.buf = append(b.buf, p[:n]...)
+	switch {
+	case len(b.buf) > maxCacheBodySize:
+		b.stopped, b.buf = true, nil
+	case err == io.EOF:
+		b.stopped = true
+		b.done(b.buf)
+		b.buf = nil
+	case err != nil:
+		b.stopped, b.buf = true, nil
+	}
+	return n, err
+}
+
+func (b *cacheBody) Close() error {
+	return b.body.Close()
+}
+
+// A cacheEntry is a stored response.
+type cacheEntry struct {
+	resp *Response
+	body []byte
+	cc   cacheControl // of resp
+
+	// reqTime and respTime are the times at which the request
+	// was sent and the response received.
+	reqTime, respTime time.Time
+
+	// vary holds the values of the request header fields named by
+	// the Vary header of resp, as returned by varyHeader.
+	vary Header
+}
+
+// varyHeader returns the values of the fields of the request header
+// that are named by the Vary field of the response header h.
+func varyHeader(req *Request, h Header) Header {
+	var vary Header
+	for _, v := range h["Vary"] {
+		for name := range strings.SplitSeq(v, ",") {
+			name = CanonicalHeaderKey(textproto.TrimString(name))
+			if name == "" {
+				continue
+			}
+			if vary == nil {
+				vary = make(Header)
+			}
+			if vv := req.Header[name]; len(vv) > 0 {
+				vary[name] = []string{strings.Join(vv, ", ")}
+			} else {
+				vary[name] = nil
+			}
+		}
+	}
+	return vary
+}
+
+// varyMatches reports whether req has the same values as the
+// request that the stored response was sent in response to,
+// for the fields named by the Vary header. RFC 9111, Section 4.1.
+func (ent *cacheEntry) varyMatches(req *Request) bool {
+	for name, vv := range varyHeader(req, ent.resp.Header) {
+		if name == "*" {
+			return false
+		}
+		want := ent.vary[name]
+		if len(vv) != len(want) || len(vv) > 0 && vv[0] != want[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// The metadata fields stored before each response.
+const (
+	cacheMetaRequestTime  = "Request-Time"
+	cacheMetaResponseTime = "Response-Time"
+	cacheMetaUncompressed = "Uncompressed"
+	cacheMetaVaryPrefix   = "Vary-"
+)
+
+// encode returns the stored form of ent: a header holding metadata,
+// followed by the response in HTTP/1.1 wire format.
+func (ent *cacheEntry) encode(vary Header) []byte {
+	meta := make(Header)
+	meta.Set(cacheMetaRequestTime, strconv.FormatInt(ent.reqTime.UnixNano(), 10))
+	meta.Set(cacheMetaResponseTime, strconv.FormatInt(ent.respTime.UnixNano(), 10))
+	if ent.resp.Uncompressed {
+		meta.Set(cacheMetaUncompressed, "1")
+	}
+	for name, vv := range vary {
+		if len(vv) > 0 {
+			meta.Set(cacheMetaVaryPrefix+name, vv[0])
+		}
+	}
+	var buf bytes.Buffer
+	meta.Write(&buf)
+	buf.WriteString("\r\n")
+
+	resp := *ent.resp
+	resp.Body = io.NopCloser(bytes.NewReader(ent.body))
+	resp.ContentLength = int64(len(ent.body))
+	resp.TransferEncoding = nil
+	resp.Trailer = nil
+	resp.Close = false
+	resp.Write(&buf)
+	return buf.Bytes()
+}
+
+// decodeCacheEntry parses the stored form of a response to req.
+func decodeCacheEntry(data []byte, req *Request) (*cacheEntry, error) {
+	br := bufio.NewReader(bytes.NewReader(data))
+	mh, err := textproto.NewReader(br).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	meta := Header(mh)
+	resp, err := ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.Uncompressed = meta.Get(cacheMetaUncompressed) != ""
+	ent := &cacheEntry{
+		resp: resp,
+		body: body,
+		cc:   parseCacheControl(resp.Header["Cache-Control"]),
+	}
+	for name, vv := range meta {
+		if name, ok := strings.CutPrefix(name, cacheMetaVaryPrefix); ok {
+			if ent.vary == nil {
+				ent.vary = make(Header)
+			}
+			ent.vary[name] = vv
+		}
+	}
+	reqTime, err1 := strconv.ParseInt(meta.Get(cacheMetaRequestTime), 10, 64)
+	respTime, err2 := strconv.ParseInt(meta.Get(cacheMetaResponseTime), 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, errors.New("http: malformed cache entry")
+	}
+	ent.reqTime = time.Unix(0, reqTime)
+	ent.respTime = time.Unix(0, respTime)
+	return ent, nil
+}
+
+// serve returns the stored response to the caller.
+func (ent *cacheEntry) serve(age time.Duration) *Response {
+	ent.resp.Header.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	return ent.resp
+}
+
+// date returns the value of the response's Date header.
+func (ent *cacheEntry) date() time.Time {
+	if t, err := ParseTime(ent.resp.Header.Get("Date")); err == nil {
+		return t
+	}
+	return ent.respTime
+}
+
+// age returns the age of the stored response at the time now.
+// RFC 9111, Section 4.2.3.
+func (ent *cacheEntry) age(now time.Time) time.Duration {
+	apparentAge := max(ent.respTime.Sub(ent.date()), 0)
+	ageValue, _ := parseDeltaSeconds(ent.resp.Header.Get("Age"))
+	responseDelay := ent.respTime.Sub(ent.reqTime)
+	correctedAgeValue := ageValue + responseDelay
+	correctedInitialAge := max(apparentAge, correctedAgeValue)
+	residentTime := now.Sub(ent.respTime)
+	return correctedInitialAge + residentTime
+}
+
+// freshnessLifetime returns the time for which the stored response
+// is fresh after it was generated. RFC 9111, Section 4.2.1.
+func (ent *cacheEntry) freshnessLifetime(shared bool) time.Duration {
+	if shared {
+		if v, ok := ent.cc["s-maxage"]; ok {
+			d, _ := parseDeltaSeconds(v)
+			return d
+		}
+	}
+	if v, ok := ent.cc["max-age"]; ok {
+		d, _ := parseDeltaSeconds(v)
+		return d
+	}
+	if v, ok := ent.resp.Header["Expires"]; ok {
+		expires, err := ParseTime(v[0])
+		if err != nil {
+			// An invalid Expires value means the response is stale.
+			return 0
+		}
+		return max(expires.Sub(ent.date()), 0)
+	}
+	if heuristicallyCacheable(ent.resp.StatusCode) || ent.cc.has("public") {
+		// RFC 9111, Section 4.2.2.
+		lm, err := ParseTime(ent.resp.Header.Get("Last-Modified"))
+		if err != nil {
+			return 0
+		}
+		return min(max(ent.date().Sub(lm)/10, 0), maxHeuristicFreshness)
+	}
+	return 0
+}
+
+// cacheControl holds the directives of a Cache-Control header,
+// mapping each lower-case directive name to its unquoted argument.
+// RFC 9111, Section 5.2.
+type cacheControl map[string]string
+
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// parseCacheControl parses the values of a Cache-Control header.
+// When a directive appears more than once, the first one is used.
+func parseCacheControl(values []string) cacheControl {
+	cc := make(cacheControl)
+	for _, v := range values {
+		for v != "" {
+			var elem string
+			elem, v = cutCacheDirective(v)
+			name, arg, _ := strings.Cut(elem, "=")
+			name, _ = ascii.ToLower(textproto.TrimString(name))
+			if name == "" || cc.has(name) {
+				continue
+			}
+			arg = textproto.TrimString(arg)
+			if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+				arg = arg[1 : len(arg)-1]
+			}
+			cc[name] = arg
+		}
+	}
+	return cc
+}
+
+// cutCacheDirective slices s around the first comma
+// that is not inside a quoted string.
+func cutCacheDirective(s string) (elem, rest string) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			return s[:i], s[i+1:]
+		}
+	}
+	return s, ""
+}
+
+// parseDeltaSeconds parses a delta-seconds value. RFC 9111, Section 1.2.2.
+func parseDeltaSeconds(v string) (time.Duration, bool) {
+	// Values too large to represent are treated as 2^31 seconds.
+	const maxDelta = 1 << 31
+	v = textproto.TrimString(v)
+	if v == "" {
+		return 0, false
+	}
+	for i := 0; i < len(v); i++ {
+		if v[i] < '0' || v[i] > '9' {
+			return 0, false
+		}
+	}
+	secs, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || secs > maxDelta {
+		secs = maxDelta
+	}
+	return time.Duration(secs) * time.Second, true
+}
@@ -0,0 +1,410 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	. "net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want map[string]string
+	}{
+		{nil, map[string]string{}},
+		{[]string{"max-age=60"}, map[string]string{"max-age": "60"}},
+		{[]string{"No-Cache, MAX-AGE=0"}, map[string]string{"no-cache": "", "max-age": "0"}},
+		{[]string{"public", "max-age=10, max-age=20"}, map[string]string{"public": "", "max-age": "10"}},
+		{[]string{`private="Set-Cookie, X-Foo", max-age=5`}, map[string]string{"private": "Set-Cookie, X-Foo", "max-age": "5"}},
+		{[]string{` , stale-while-revalidate = 30 ,,`}, map[string]string{"stale-while-revalidate": "30"}},
+		{[]string{`no-cache="a\"b", must-revalidate`}, map[string]string{"no-cache": `a\"b`, "must-revalidate": ""}},
+	}
+	for _, test := range tests {
+		got := map[string]string(ExportParseCacheControl(test.in))
+		if !maps.Equal(got, test.want) {
+			t.Errorf("parseCacheControl(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestMemoryCacheStorage(t *testing.T) {
+	s := NewMemoryCacheStorage(30)
+	s.Set("a", []byte("123456789"))
+	s.Set("b", []byte("123456789"))
+	s.Set("c", []byte("123456789"))
+	if _, ok := s.Get("a"); !ok {
+		t.Fatal("a not found")
+	}
+	// Storing d must evict b, the least recently used entry.
+	s.Set("d", []byte("123456789"))
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok := s.Get(key); ok != want {
+			t.Errorf("Get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+	s.Delete("a")
+	if _, ok := s.Get("a"); ok {
+		t.Error("a found after Delete")
+	}
+	s.Set("big", make([]byte, 100))
+	if _, ok := s.Get("big"); ok {
+		t.Error("entry larger than the storage was stored")
+	}
+	if v, ok := s.Get("c"); !ok || string(v) != "123456789" {
+		t.Errorf("Get(c) = %q, %v", v, ok)
+	}
+}
+
+// cacheTestClock is a clock for CachingTransport that only moves
+// when advanced.
+type cacheTestClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *cacheTestClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *cacheTestClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type cacheTest struct {
+	t     *testing.T
+	cst   *clientServerTest
+	c     *Client
+	clock *cacheTestClock
+	hits  atomic.Int32 // requests seen by the server
+}
+
+func newCacheTest(t *testing.T, mode testMode, shared bool, h HandlerFunc) *cacheTest {
+	ct := &cacheTest{t: t, clock: &cacheTestClock{now: time.Now()}}
+	ct.cst = newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		ct.hits.Add(1)
+		w.Header().Set("Date", ct.clock.Now().UTC().Format(TimeFormat))
+		h(w, r)
+	}))
+	tr := &CachingTransport{Transport: ct.cst.tr, Shared: shared}
+	tr.SetNowForTesting(ct.clock.Now)
+	ct.c = &Client{Transport: tr}
+	return ct
+}
+
+// get makes a request and returns the response and its body.
+func (ct *cacheTest) get(method, path string, header ...string) (*Response, string) {
+	ct.t.Helper()
+	req, err := NewRequest(method, ct.cst.ts.URL+path, nil)
+	if err != nil {
+		ct.t.Fatal(err)
+	}
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	res, err := ct.c.Do(req)
+	if err != nil {
+		ct.t.Fatal(err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		ct.t.Fatal(err)
+	}
+	return res, string(body)
+}
+
+func (ct *cacheTest) wantHits(want int32) {
+	ct.t.Helper()
+	if got := ct.hits.Load(); got != want {
+		ct.t.Errorf("server saw %v requests, want %v", got, want)
+	}
+}
+
+func TestCachingTransport(t *testing.T) { run(t, testCachingTransport) }
+func testCachingTransport(t *testing.T, mode testMode) {
+	var version atomic.Int32
+	ct := newCacheTest(t, mode, false, func(w ResponseWriter, r *Request) {
+		etag := fmt.Sprintf(`"v%d"`, version.Load())
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(StatusNotModified)
+			return
+		}
+		io.WriteString(w, "body "+etag)
+	})
+
+	res, body := ct.get("GET", "/")
+	if body != `body "v0"` {
+		t.Errorf("body = %q", body)
+	}
+	ct.wantHits(1)
+
+	// Fresh: served from the cache.
+	ct.clock.Advance(30 * time.Second)
+	res, body = ct.get("GET", "/")
+	if body != `body "v0"` {
+		t.Errorf("cached body = %q", body)
+	}
+	if age := res.Header.Get("Age"); age != "30" && age != "31" {
+		t.Errorf("Age = %q, want 30", age)
+	}
+	ct.wantHits(1)
+
+	// Stale: revalidated, and the server responds with 304.
+	ct.clock.Advance(time.Minute)
+	res, body = ct.get("GET", "/")
+	if res.StatusCode != StatusOK || body != `body "v0"` {
+		t.Errorf("revalidated response = %v %q", res.Status, body)
+	}
+	ct.wantHits(2)
+
+	// Fresh again after the revalidation.
+	ct.get("GET", "/")
+	ct.wantHits(2)
+
+	// Stale, and the resource has changed.
+	version.Store(1)
+	ct.clock.Advance(2 * time.Minute)
+	_, body = ct.get("GET", "/")
+	if body != `body "v1"` {
+		t.Errorf("body after change = %q", body)
+	}
+	ct.wantHits(3)
+	_, body = ct.get("GET", "/")
+	if body != `body "v1"` {
+		t.Errorf("cached body after change = %q", body)
+	}
+	ct.wantHits(3)
+}
+
+func TestCachingTransportLastModified(t *testing.T) {
+	run(t, testCachingTransportLastModified, []testMode{http1Mode})
+}
+func testCachingTransportLastModified(t *testing.T, mode testMode) {
+	lastModified := time.Now().Add(-100 * time.Hour).UTC().Format(TimeFormat)
+	ct := newCacheTest(t, mode, false, func(w ResponseWriter, r *Request) {
+		w.Header().Set("Last-Modified", lastModified)
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(StatusNotModified)
+			return
+		}
+		io.WriteString(w, "body")
+	})
+	ct.get("GET", "/")
+	ct.get("GET", "/")
+	// The heuristic freshness lifetime is 10 hours.
+	ct.wantHits(1)
+	ct.clock.Advance(11 * time.Hour)
+	_, body := ct.get("GET", "/")
+	if body != "body" {
+		t.Errorf("body = %q", body)
+	}
+	ct.wantHits(2)
+}
+
+func TestCachingTransportNotStored(t *testing.T) {
+	run(t, testCachingTransportNotStored, []testMode{http1Mode})
+}
+func testCachingTransportNotStored(t *testing.T, mode testMode) {
+	ct := newCacheTest(t, mode, true, func(w ResponseWriter, r *Request) {
+		switch r.URL.Path {
+		case "/nostore":
+			w.Header().Set("Cache-Control", "no-store, max-age=60")
+		case "/private":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		case "/vary":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "*")
+		case "/error":
+			w.WriteHeader(StatusInternalServerError)
+		case "/large":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Write(make([]byte, 9<<20))
+			return
+		}
+		io.WriteString(w, "body")
+	})
+	for _, path := range []string{"/nostore", "/private", "/vary", "/error", "/large"} {
+		ct.hits.Store(0)
+		ct.get("GET", path)
+		ct.get("GET", path)
+		if got := ct.hits.Load(); got != 2 {
+			t.Errorf("%v: server saw %v requests, want 2", path, got)
+		}
+	}
+}
+
+func TestCachingTransportVary(t *testing.T) { run(t, testCachingTransportVary, []testMode{http1Mode}) }
+func testCachingTransportVary(t *testing.T, mode testMode) {
+	ct := newCacheTest(t, mode, false, func(w ResponseWriter, r *Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		io.WriteString(w, "lang="+r.Header.Get("Accept-Language"))
+	})
+	_, body := ct.get("GET", "/", "Accept-Language", "fr")
+	ct.wantHits(1)
+	_, body = ct.get("GET", "/", "Accept-Language", "fr")
+	if body != "lang=fr" {
+		t.Errorf("body = %q, want lang=fr", body)
+	}
+	ct.wantHits(1)
+	_, body = ct.get("GET", "/", "Accept-Language", "de")
+	if body != "lang=de" {
+		t.Errorf("body = %q, want lang=de", body)
+	}
+	ct.wantHits(2)
+	_, body = ct.get("GET", "/")
+	if body != "lang=" {
+		t.Errorf("body = %q, want lang=", body)
+	}
+	ct.wantHits(3)
+	ct.get("GET", "/")
+	ct.wantHits(3)
+}
+
+func TestCachingTransportRequestDirectives(t *testing.T) {
+	run(t, testCachingTransportRequestDirectives, []testMode{http1Mode})
+}
+func testCachingTransportRequestDirectives(t *testing.T, mode testMode) {
+	ct := newCacheTest(t, mode, false, func(w ResponseWriter, r *Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		io.WriteString(w, "body")
+	})
+
+	res, _ := ct.get("GET", "/", "Cache-Control", "only-if-cached")
+	if res.StatusCode != StatusGatewayTimeout {
+		t.Errorf("only-if-cached with empty cache: status = %v, want 504", res.Status)
+	}
+	ct.wantHits(0)
+
+	ct.get("GET", "/")
+	ct.wantHits(1)
+	res, body := ct.get("GET", "/", "Cache-Control", "only-if-cached")
+	if res.StatusCode != StatusOK || body != "body" {
+		t.Errorf("only-if-cached: %v %q", res.Status, body)
+	}
+	ct.wantHits(1)
+
+	ct.get("GET", "/", "Cache-Control", "no-cache")
+	ct.wantHits(2)
+	ct.get("GET", "/", "Pragma", "no-cache")
+	ct.wantHits(3)
+	ct.get("GET", "/", "Cache-Control", "no-store")
+	ct.wantHits(4)
+
+	ct.clock.Advance(30 * time.Second)
+	ct.get("GET", "/", "Cache-Control", "min-fresh=20")
+	ct.wantHits(4)
+	ct.get("GET", "/", "Cache-Control", "min-fresh=45")
+	ct.wantHits(5)
+	ct.clock.Advance(30 * time.Second)
+	ct.get("GET", "/", "Cache-Control", "max-age=40")
+	ct.wantHits(5)
+	ct.get("GET", "/", "Cache-Control", "max-age=10")
+	ct.wantHits(6)
+
+	ct.clock.Advance(90 * time.Second)
+	ct.get("GET", "/", "Cache-Control", "max-stale=60")
+	ct.wantHits(6)
+	ct.get("GET", "/", "Cache-Control", "max-stale=10")
+	ct.wantHits(7)
+}
+
+func TestCachingTransportInvalidate(t *testing.T) {
+	run(t, testCachingTransportInvalidate, []testMode{http1Mode})
+}
+func testCachingTransportInvalidate(t *testing.T, mode testMode) {
+	ct := newCacheTest(t, mode, false, func(w ResponseWriter, r *Request) {
+		if r.Method == "POST" {
+			w.Header().Set("Location", "/other")
+			w.WriteHeader(StatusCreated)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		io.WriteString(w, "body")
+	})
+	ct.get("GET", "/")
+	ct.get("GET", "/other")
+	ct.get("GET", "/")
+	ct.get("GET", "/other")
+	ct.wantHits(2)
+	ct.get("POST", "/")
+	ct.wantHits(3)
+	ct.get("GET", "/")
+	ct.get("GET", "/other")
+	ct.wantHits(5)
+}
+
+func TestCachingTransportStaleWhileRevalidate(t *testing.T) {
+	run(t, testCachingTransportStaleWhileRevalidate, []testMode{http1Mode})
+}
+func testCachingTransportStaleWhileRevalidate(t *testing.T, mode testMode) {
+	var version atomic.Int32
+	ct := newCacheTest(t, mode, false, func(w ResponseWriter, r *Request) {
+		w.Header().Set("Cache-Control", "max-age=60, stale-while-revalidate=60")
+		fmt.Fprintf(w, "v%d", version.Load())
+	})
+	ct.get("GET", "/")
+	version.Store(1)
+	ct.clock.Advance(90 * time.Second)
+
+	// The stale response is returned while it is revalidated.
+	res, body := ct.get("GET", "/")
+	if body != "v0" {
+		t.Errorf("body = %q, want stale v0", body)
+	}
+	if age := res.Header.Get("Age"); age != "90" && age != "91" {
+		t.Errorf("Age = %q, want 90", age)
+	}
+	for {
+		_, body = ct.get("GET", "/", "Cache-Control", "only-if-cached, max-stale")
+		if body == "v1" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ct.wantHits(2)
+
+	// Beyond the stale-while-revalidate window, the caller waits for
+	// the revalidation.
+	version.Store(2)
+	ct.clock.Advance(150 * time.Second)
+	_, body = ct.get("GET", "/")
+	if body != "v2" {
+		t.Errorf("body = %q, want v2", body)
+	}
+	ct.wantHits(3)
+}
+
+func TestCachingTransportMustRevalidate(t *testing.T) {
+	run(t, testCachingTransportMustRevalidate, []testMode{http1Mode})
+}
+func testCachingTransportMustRevalidate(t *testing.T, mode testMode) {
+	ct := newCacheTest(t, mode, false, func(w ResponseWriter, r *Request) {
+		w.Header().Set("Cache-Control", "max-age=60, must-revalidate, stale-while-revalidate=600")
+		io.WriteString(w, "body")
+	})
+	ct.get("GET", "/")
+	ct.clock.Advance(2 * time.Minute)
+	ct.get("GET", "/", "Cache-Control", "max-stale")
+	ct.wantHits(2)
+	res, body := ct.get("GET", "/")
+	if res.StatusCode != StatusOK || body != "body" {
+		t.Errorf("got %v %q", res.Status, body)
+	}
+	ct.wantHits(2)
+}
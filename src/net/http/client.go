@@ -105,6 +105,17 @@ type Client struct {
 	// RoundTripper implementations should use the Request's Context
 	// for cancellation instead of implementing CancelRequest.
 	Timeout time.Duration
+
+	// Retry specifies the policy for retrying requests that fail
+	// with a transient error, such as a reset connection or a 503
+	// (Service Unavailable) response. Each redirect is retried
+	// separately. The Timeout, if any, covers all attempts and the
+	// delays between them.
+	//
+	// If Retry is nil, requests are not retried by the Client.
+	// The Transport may still retry a request internally when a
+	// connection it reused fails before the request was written.
+	Retry *RetryPolicy
 }
 
 // DefaultClient is the default [Client] and is used by [Get], [Head], and [Post].
@@ -752,7 +763,7 @@ func (c *Client) do(req *Request) (retres *Response, reterr error) {
 		reqs = append(reqs, req)
 		var err error
 		var didTimeout func() bool
-		if resp, didTimeout, err = c.send(req, deadline); err != nil {
+		if resp, didTimeout, err = c.sendWithRetry(req, deadline); err != nil {
 			// c.send() always closes req.Body
 			reqBodyClosed = true
 			if !deadline.IsZero() && didTimeout() {
@@ -1568,7 +1568,7 @@ func testClientCopyHeadersOnRedirect(t *testing.T, mode testMode) {
 			"User-Agent":      []string{ua},
 			"X-Foo":           []string{xfoo},
 			"Referer":         []string{redirectURL},
-			"Accept-Encoding": []string{"gzip"},
+			"Accept-Encoding": []string{"gzip, zstd"},
 			"Cookie":          []string{"foo=bar"},
 			"Authorization":   []string{"secretpassword"},
 		}
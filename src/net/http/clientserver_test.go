@@ -20,7 +20,6 @@ import (
 	"log"
 	"maps"
 	"net"
-	"net/http"
 	. "net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
@@ -38,18 +37,8 @@ import (
 	"testing"
 	"testing/synctest"
 	"time"
-
-	_ "unsafe" // for linkname
-
-	_ "golang.org/x/net/http3"
 )
 
-//go:linkname registerHTTP3Transport
-func registerHTTP3Transport(*http.Transport, any) error
-
-//go:linkname registerHTTP3Server
-func registerHTTP3Server(*http.Server, any) error
-
 type testMode string
 
 const (
@@ -244,10 +233,7 @@ func newClientServerTest(t testing.TB, mode testMode, h Handler, opts ...any) *c
 
 	var transportFuncs []func(*Transport)
 
-	type HTTP3TransportOpts struct {
-		ListenPacket func(network, addr string) (net.PacketConn, error)
-	}
-	var http3TransportOpts HTTP3TransportOpts
+	var http3ListenPacket func(network, addr string) (net.PacketConn, error)
 
 	fakeNet := true
 	if idx := slices.Index(opts, any(optRealNet)); idx >= 0 {
@@ -267,7 +253,7 @@ func newClientServerTest(t testing.TB, mode testMode, h Handler, opts ...any) *c
 			cliPort.Store(100)
 			pconn, _ := pnet.NewConn(srvAddr)
 			cst.ts.Listener = http3ServeConn{conn: pconn}
-			http3TransportOpts.ListenPacket = func(network, addr string) (net.PacketConn, error) {
+			http3ListenPacket = func(network, addr string) (net.PacketConn, error) {
 				port := uint16(cliPort.Add(1))
 				cliAddr := net.UDPAddrFromAddrPort(netip.AddrPortFrom(
 					netip.MustParseAddr("127.0.0.1"), port))
@@ -315,17 +301,15 @@ func newClientServerTest(t testing.TB, mode testMode, h Handler, opts ...any) *c
 		cst.ts.EnableHTTP2 = true
 		cst.ts.TLS = cst.ts.Config.TLSConfig
 	case http3Mode:
-		http.ProtocolSetHTTP3(p)
+		ProtocolSetHTTP3(p)
 		cst.ts.TLS = cst.ts.Config.TLSConfig
-		type HTTP3ServerOpts struct{}
-		if err := registerHTTP3Server(cst.ts.Config, HTTP3ServerOpts{}); err != nil {
-			t.Fatal(err)
-		}
 		cst.ts.StartTLS()
 
-		tr := cst.ts.Client().Transport.(*Transport)
-		if err := registerHTTP3Transport(tr, http3TransportOpts); err != nil {
-			t.Fatal(err)
+		if http3ListenPacket != nil {
+			tr := cst.ts.Client().Transport.(*Transport)
+			if err := tr.RegisterHTTP3ForTesting(http3ListenPacket); err != nil {
+				t.Fatal(err)
+			}
 		}
 	default:
 		t.Fatalf("unknown test mode %v", mode)
@@ -786,8 +770,8 @@ func testHandlerWritesTooMuch(t *testing.T, mode testMode) {
 func TestH12_AutoGzip(t *testing.T) {
 	h12Compare{
 		Handler: func(w ResponseWriter, r *Request) {
-			if ae := r.Header.Get("Accept-Encoding"); ae != "gzip" {
-				t.Errorf("%s Accept-Encoding = %q; want gzip", r.Proto, ae)
+			if ae := r.Header.Get("Accept-Encoding"); ae != "gzip, zstd" {
+				t.Errorf("%s Accept-Encoding = %q; want gzip, zstd", r.Proto, ae)
 			}
 			w.Header().Set("Content-Encoding", "gzip")
 			gz := gzip.NewWriter(w)
@@ -0,0 +1,316 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"compress/gzip"
+	"compress/zstd"
+	"io"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// compressMinSize is the smallest response body that CompressHandler
+// compresses. Smaller bodies gain little from compression.
+const compressMinSize = 1024
+
+// CompressHandler returns a [Handler] that compresses the responses of h
+// with gzip or zstd, choosing between them according to the
+// Accept-Encoding header of the request. If the client accepts neither,
+// the response is sent uncompressed.
+//
+// A response is compressed only if it has a body of at least 1024 bytes,
+// or if it is flushed before that much has been written. Responses to
+// HEAD requests, partial content and responses that already have a
+// Content-Encoding header are never compressed, nor are responses whose
+// Content-Type indicates data that is usually already compressed, such
+// as most images, audio and video.
+//
+// When compressing, the handler removes any Content-Length and
+// Accept-Ranges headers and weakens a strong ETag, since the header
+// values describe the uncompressed content. It adds Accept-Encoding to
+// the Vary header of every response. If h does not set a Content-Type,
+// one is detected from the uncompressed content, as
+// [ResponseWriter.Write] would.
+//
+// The [ResponseWriter] passed to h supports flushing, and is
+// compatible with [ResponseController].
+func CompressHandler(h Handler) Handler {
+	return HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		enc := negotiateContentEncoding(r.Header["Accept-Encoding"])
+		if enc == "" || r.Method == "HEAD" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{rw: w, enc: enc}
+		h.ServeHTTP(cw, r)
+		cw.close()
+	})
+}
+
+// negotiateContentEncoding returns the content coding to use for a
+// response, given the values of the request's Accept-Encoding header:
+// "zstd", "gzip", or "" if neither is acceptable.
+// When the client prefers neither, zstd is chosen.
+func negotiateContentEncoding(accept []string) string {
+	var qZstd, qGzip, qAny float64
+	var sawZstd, sawGzip, sawAny bool
+	for _, v := range accept {
+		for elem := range strings.SplitSeq(v, ",") {
+			coding, params, _ := strings.Cut(elem, ";")
+			q := 1.0
+			for param := range strings.SplitSeq(params, ";") {
+				name, value, _ := strings.Cut(param, "=")
+				if ascii.EqualFold(textproto.TrimString(name), "q") {
+					var err error
+					q, err = strconv.ParseFloat(textproto.TrimString(value), 64)
+					if err != nil || q < 0 || q > 1 {
+						q = 0
+					}
+				}
+			}
+			switch coding = textproto.TrimString(coding); {
+			case ascii.EqualFold(coding, "zstd"):
+				qZstd, sawZstd = q, true
+			case ascii.EqualFold(coding, "gzip"), ascii.EqualFold(coding, "x-gzip"):
+				qGzip, sawGzip = q, true
+			case coding == "*":
+				qAny, sawAny = q, true
+			}
+		}
+	}
+	if sawAny {
+		if !sawZstd {
+			qZstd = qAny
+		}
+		if !sawGzip {
+			qGzip = qAny
+		}
+	}
+	switch {
+	case qZstd > 0 && qZstd >= qGzip:
+		return "zstd"
+	case qGzip > 0:
+		return "gzip"
+	}
+	return ""
+}
+
+// A compressor is a gzip.Writer or zstd.Writer.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+var (
+	gzipWriterPool = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+	zstdWriterPool = sync.Pool{New: func() any { return zstd.NewWriter(nil) }}
+)
+
+// compressWriter is the ResponseWriter passed to the handler
+// wrapped by CompressHandler.
+type compressWriter struct {
+	rw  ResponseWriter
+	enc string // content coding to use, "gzip" or "zstd"
+
+	code    int        // status code, 0 until WriteHeader or Write is called
+	started bool       // response header has been written to rw
+	buf     []byte     // body written before started
+	zw      compressor // non-nil if compressing
+	err     error      // sticky error from zw
+}
+
+func (cw *compressWriter) Header() Header {
+	return cw.rw.Header()
+}
+
+func (cw *compressWriter) Unwrap() ResponseWriter {
+	return cw.rw
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.code != 0 {
+		if cw.started {
+			// Let the underlying ResponseWriter report the superfluous call.
+			cw.rw.WriteHeader(code)
+		}
+		return
+	}
+	if code >= 100 && code <= 199 && code != StatusSwitchingProtocols {
+		// Informational responses are sent immediately.
+		cw.rw.WriteHeader(code)
+		return
+	}
+	cw.code = code
+	if !cw.mayCompress() {
+		cw.start(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.code == 0 {
+		cw.WriteHeader(StatusOK)
+	}
+	if !cw.started {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) >= compressMinSize {
+			if err := cw.start(cw.shouldCompress()); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+	if cw.zw == nil {
+		return cw.rw.Write(p)
+	}
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.zw.Write(p)
+	cw.err = err
+	return n, err
+}
+
+func (cw *compressWriter) Flush() {
+	cw.FlushError()
+}
+
+func (cw *compressWriter) FlushError() error {
+	if cw.code == 0 {
+		cw.WriteHeader(StatusOK)
+	}
+	if !cw.started {
+		// A flushed response is probably being streamed,
+		// so compress it even if it is small so far.
+		if err := cw.start(len(cw.buf) > 0 && cw.shouldCompress()); err != nil {
+			return err
+		}
+	}
+	if cw.zw != nil {
+		if cw.err != nil {
+			return cw.err
+		}
+		if cw.err = cw.zw.Flush(); cw.err != nil {
+			return cw.err
+		}
+	}
+	return NewResponseController(cw.rw).Flush()
+}
+
+// close finishes the response after the handler returns.
+func (cw *compressWriter) close() {
+	if !cw.started {
+		if cw.code == 0 {
+			// The handler wrote nothing.
+			return
+		}
+		cw.start(len(cw.buf) >= compressMinSize && cw.shouldCompress())
+	}
+	if cw.zw != nil {
+		if cw.err == nil {
+			cw.zw.Close()
+		}
+		cw.zw.Reset(nil)
+		if cw.enc == "zstd" {
+			zstdWriterPool.Put(cw.zw)
+		} else {
+			gzipWriterPool.Put(cw.zw)
+		}
+		cw.zw = nil
+	}
+}
+
+// mayCompress reports whether the status code and headers
+// permit compressing the response.
+func (cw *compressWriter) mayCompress() bool {
+	switch cw.code {
+	case StatusNoContent, StatusNotModified, StatusPartialContent, StatusSwitchingProtocols:
+		return false
+	}
+	h := cw.rw.Header()
+	return h.Get("Content-Encoding") == "" && h.Get("Content-Range") == ""
+}
+
+// shouldCompress reports whether the response should be compressed,
+// based on its headers and the start of its body in cw.buf.
+func (cw *compressWriter) shouldCompress() bool {
+	if !cw.mayCompress() {
+		return false
+	}
+	ct := cw.rw.Header().Get("Content-Type")
+	if ct == "" {
+		ct = DetectContentType(cw.buf)
+	}
+	return compressibleContentType(ct)
+}
+
+// compressibleContentType reports whether data of type ct
+// is likely to benefit from compression.
+func compressibleContentType(ct string) bool {
+	ct, _, _ = strings.Cut(ct, ";")
+	ct, _ = ascii.ToLower(textproto.TrimString(ct))
+	typ, subtype, _ := strings.Cut(ct, "/")
+	switch typ {
+	case "image":
+		return subtype == "svg+xml" || subtype == "bmp" || subtype == "x-icon"
+	case "audio", "video":
+		return false
+	case "font":
+		return subtype != "woff" && subtype != "woff2"
+	case "application":
+		switch subtype {
+		case "gzip", "x-gzip", "zip", "zstd", "x-bzip2", "x-xz", "x-7z-compressed", "vnd.rar", "pdf":
+			return false
+		}
+	}
+	return true
+}
+
+// start writes the response header to the underlying ResponseWriter,
+// followed by any buffered body data.
+func (cw *compressWriter) start(compress bool) error {
+	cw.started = true
+	h := cw.rw.Header()
+	if compress {
+		if _, ok := h["Content-Type"]; !ok {
+			h.Set("Content-Type", DetectContentType(cw.buf))
+		}
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		h.Set("Content-Encoding", cw.enc)
+		if etag := h.Get("Etag"); strings.HasPrefix(etag, `"`) {
+			h.Set("Etag", "W/"+etag)
+		}
+		if !httpguts.HeaderValuesContainsToken(h["Vary"], "Accept-Encoding") {
+			h.Add("Vary", "Accept-Encoding")
+		}
+		if cw.enc == "zstd" {
+			cw.zw = zstdWriterPool.Get().(compressor)
+		} else {
+			cw.zw = gzipWriterPool.Get().(compressor)
+		}
+		cw.zw.Reset(cw.rw)
+	}
+	cw.rw.WriteHeader(cw.code)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if cw.zw == nil {
+		_, err := cw.rw.Write(buf)
+		return err
+	}
+	_, cw.err = cw.zw.Write(buf)
+	return cw.err
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zstd"
+	"io"
+	. "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateContentEncoding(t *testing.T) {
+	tests := []struct {
+		accept []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{""}, ""},
+		{[]string{"identity"}, ""},
+		{[]string{"gzip"}, "gzip"},
+		{[]string{"x-gzip"}, "gzip"},
+		{[]string{"zstd"}, "zstd"},
+		{[]string{"gzip, zstd"}, "zstd"},
+		{[]string{"gzip", "zstd"}, "zstd"},
+		{[]string{"GZIP, br"}, "gzip"},
+		{[]string{"gzip;q=1.0, zstd;q=0.5"}, "gzip"},
+		{[]string{"gzip;q=0.5, zstd;q=0.8"}, "zstd"},
+		{[]string{"gzip, zstd;q=0"}, "gzip"},
+		{[]string{"gzip;q=0, zstd;q=0"}, ""},
+		{[]string{"*"}, "zstd"},
+		{[]string{"*;q=0"}, ""},
+		{[]string{"gzip, *;q=0"}, "gzip"},
+		{[]string{"zstd;q=0, *"}, "gzip"},
+		{[]string{"gzip; q=0.9 , zstd ; q=0.1"}, "gzip"},
+		{[]string{"zstd;q=bogus, gzip"}, "gzip"},
+	}
+	for _, test := range tests {
+		if got := ExportNegotiateContentEncoding(test.accept); got != test.want {
+			t.Errorf("negotiateContentEncoding(%q) = %q, want %q", test.accept, got, test.want)
+		}
+	}
+}
+
+// compressTestBody is a response body that is large enough to be compressed.
+var compressTestBody = strings.Repeat("Hello, compressed world! ", 200)
+
+// compressGet makes a request for url with an Accept-Encoding header
+// and returns the response and its decoded body.
+func compressGet(t *testing.T, c *Client, method, url, accept string) (*Response, string) {
+	t.Helper()
+	req, err := NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept != "" {
+		// Setting the header prevents the Transport from decoding the body.
+		req.Header.Set("Accept-Encoding", accept)
+	}
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var r io.Reader = res.Body
+	switch res.Header.Get("Content-Encoding") {
+	case "gzip":
+		r, err = gzip.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+	case "zstd":
+		r = zstd.NewReader(r)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, string(body)
+}
+
+func TestCompressHandler(t *testing.T) { run(t, testCompressHandler) }
+func testCompressHandler(t *testing.T, mode testMode) {
+	mux := NewServeMux()
+	mux.HandleFunc("/large", func(w ResponseWriter, r *Request) {
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Content-Length", "5000")
+		io.WriteString(w, compressTestBody)
+	})
+	mux.HandleFunc("/small", func(w ResponseWriter, r *Request) {
+		io.WriteString(w, "small")
+	})
+	mux.HandleFunc("/image", func(w ResponseWriter, r *Request) {
+		w.Header().Set("Content-Type", "image/png")
+		io.WriteString(w, compressTestBody)
+	})
+	mux.HandleFunc("/encoded", func(w ResponseWriter, r *Request) {
+		w.Header().Set("Content-Encoding", "br")
+		io.WriteString(w, compressTestBody)
+	})
+	mux.HandleFunc("/notmodified", func(w ResponseWriter, r *Request) {
+		w.WriteHeader(StatusNotModified)
+	})
+	mux.HandleFunc("/status", func(w ResponseWriter, r *Request) {
+		w.WriteHeader(StatusTeapot)
+		io.WriteString(w, compressTestBody)
+	})
+	cst := newClientServerTest(t, mode, CompressHandler(mux))
+	c := cst.c
+
+	tests := []struct {
+		method, path, accept string
+		wantEncoding         string
+		wantStatus           int
+		wantBody             string
+	}{
+		{"GET", "/large", "gzip, zstd", "zstd", 200, compressTestBody},
+		{"GET", "/large", "gzip", "gzip", 200, compressTestBody},
+		{"GET", "/large", "zstd;q=0.5, gzip", "gzip", 200, compressTestBody},
+		{"GET", "/large", "br", "", 200, compressTestBody},
+		{"GET", "/large", "identity", "", 200, compressTestBody},
+		{"HEAD", "/large", "gzip, zstd", "", 200, ""},
+		{"GET", "/small", "gzip, zstd", "", 200, "small"},
+		{"GET", "/image", "gzip, zstd", "", 200, compressTestBody},
+		{"GET", "/encoded", "gzip, zstd", "br", 200, compressTestBody},
+		{"GET", "/notmodified", "gzip, zstd", "", 304, ""},
+		{"GET", "/status", "gzip", "gzip", StatusTeapot, compressTestBody},
+	}
+	for _, test := range tests {
+		res, body := compressGet(t, c, test.method, cst.ts.URL+test.path, test.accept)
+		name := test.method + " " + test.path + " " + test.accept
+		if res.StatusCode != test.wantStatus {
+			t.Errorf("%s: status = %v, want %v", name, res.StatusCode, test.wantStatus)
+		}
+		if got := res.Header.Get("Content-Encoding"); got != test.wantEncoding {
+			t.Errorf("%s: Content-Encoding = %q, want %q", name, got, test.wantEncoding)
+		}
+		if body != test.wantBody {
+			t.Errorf("%s: body = %q, want %q", name, body, test.wantBody)
+		}
+		if got := res.Header.Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("%s: Vary = %q, want Accept-Encoding", name, got)
+		}
+		if test.wantEncoding == "gzip" || test.wantEncoding == "zstd" {
+			if res.ContentLength == 5000 {
+				t.Errorf("%s: ContentLength = %v, the uncompressed length", name, res.ContentLength)
+			}
+			if test.path == "/large" {
+				if got := res.Header.Get("ETag"); got != `W/"abc"` {
+					t.Errorf("%s: ETag = %q, want %q", name, got, `W/"abc"`)
+				}
+			}
+			if got := res.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+				t.Errorf("%s: Content-Type = %q, want text/plain", name, got)
+			}
+		}
+	}
+}
+
+func TestCompressHandlerTransport(t *testing.T) { run(t, testCompressHandlerTransport) }
+func testCompressHandlerTransport(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, compressTestBody)
+	})))
+	// The Transport requests and decodes the compressed response.
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != compressTestBody {
+		t.Errorf("body = %q, want %q", body, compressTestBody)
+	}
+	if !res.Uncompressed {
+		t.Errorf("Uncompressed = false, want true")
+	}
+}
+
+func TestCompressHandlerFlush(t *testing.T) { run(t, testCompressHandlerFlush) }
+func testCompressHandlerFlush(t *testing.T, mode testMode) {
+	next := make(chan bool)
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		rc := NewResponseController(w)
+		for i := 0; i < 3; i++ {
+			io.WriteString(w, "data: event\n")
+			if err := rc.Flush(); err != nil {
+				t.Errorf("Flush: %v", err)
+			}
+			<-next
+		}
+	})))
+	req, _ := NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "zstd")
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if got := res.Header.Get("Content-Encoding"); got != "zstd" {
+		t.Fatalf("Content-Encoding = %q, want zstd", got)
+	}
+	br := bufio.NewReader(zstd.NewReader(res.Body))
+	for i := 0; i < 3; i++ {
+		// Each event must be readable before the handler continues.
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line != "data: event\n" {
+			t.Errorf("line = %q, want %q", line, "data: event\n")
+		}
+		next <- true
+	}
+}
+
+func TestCompressHandlerContentType(t *testing.T) {
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, "<html><body>"+compressTestBody+"</body></html>")
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := rec.Result()
+	if got, want := res.Header.Get("Content-Type"), "text/html; charset=utf-8"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+	if got := res.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", got)
+	}
+}
@@ -29,6 +29,10 @@ var (
 	ExportErrServerClosedIdle         = errServerClosedIdle
 	ExportServeFile                   = serveFile
 	ExportScanETag                    = scanETag
+	ExportNegotiateContentEncoding    = negotiateContentEncoding
+	ExportParseRetryAfter             = parseRetryAfter
+	ExportParseCacheControl           = parseCacheControl
+	ExportParseAltSvcHTTP3            = parseAltSvcHTTP3
 	Export_shouldCopyHeaderOnRedirect = shouldCopyHeaderOnRedirect
 	Export_writeStatusLine            = writeStatusLine
 	Export_is408Message               = is408Message
@@ -38,6 +42,8 @@ var (
 
 var MaxWriteWaitBeforeConnReuse = &maxWriteWaitBeforeConnReuse
 
+var HTTP3FallbackTimeout = &http3FallbackTimeout
+
 func init() {
 	// We only want to pay for this cost during testing.
 	// When not under test, these values are always nil
@@ -106,6 +112,17 @@ func ResetCachedEnvironment() {
 	resetProxyConfig()
 }
 
+// SetNowForTesting sets the function used by t to get the current time.
+func (t *CachingTransport) SetNowForTesting(now func() time.Time) {
+	t.now = now
+}
+
+// RegisterHTTP3ForTesting registers the bundled HTTP/3 implementation
+// on t, using listenPacket to create its UDP socket.
+func (t *Transport) RegisterHTTP3ForTesting(listenPacket func(network, addr string) (net.PacketConn, error)) error {
+	return http3RegisterTransport(t, http3TransportOpts{ListenPacket: listenPacket})
+}
+
 func (t *Transport) NumPendingRequestsForTesting() int {
 	t.reqMu.Lock()
 	defer t.reqMu.Unlock()
@@ -151,7 +168,7 @@ func (t *Transport) IdleConnStrsForTesting() []string {
 func (t *Transport) IdleConnCountForTesting(scheme, addr string) int {
 	t.idleMu.Lock()
 	defer t.idleMu.Unlock()
-	key := connectMethodKey{"", scheme, addr, false}
+	key := connectMethodKey{"", scheme, addr, false, false}
 	cacheKey := key.String()
 	for k, conns := range t.idleConn {
 		if k.String() == cacheKey {
@@ -181,7 +198,7 @@ func (t *Transport) QueueForIdleConnForTesting() {
 // persistConn for scheme, addr into the idle connection pool.
 func (t *Transport) PutIdleTestConn(scheme, addr string) bool {
 	c, _ := net.Pipe()
-	key := connectMethodKey{"", scheme, addr, false}
+	key := connectMethodKey{"", scheme, addr, false, false}
 
 	if t.MaxConnsPerHost > 0 {
 		// Transport is tracking conns-per-host.
@@ -206,7 +223,7 @@ func (t *Transport) PutIdleTestConn(scheme, addr string) bool {
 // PutIdleTestConnH2 reports whether it was able to insert a fresh
 // HTTP/2 persistConn for scheme, addr into the idle connection pool.
 func (t *Transport) PutIdleTestConnH2(scheme, addr string, alt RoundTripper) bool {
-	key := connectMethodKey{"", scheme, addr, false}
+	key := connectMethodKey{"", scheme, addr, false, false}
 
 	if t.MaxConnsPerHost > 0 {
 		// Transport is tracking conns-per-host.
@@ -312,3 +329,15 @@ func SetRSTAvoidanceDelay(t *testing.T, d time.Duration) {
 	})
 	rstAvoidanceDelay = d
 }
+
+// SetNowForTesting sets the function used by l to get the current time.
+func (l *LimitHandler) SetNowForTesting(now func() time.Time) {
+	l.now = now
+}
+
+// KeysForTesting returns the number of keys with rate limiting state.
+func (l *LimitHandler) KeysForTesting() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
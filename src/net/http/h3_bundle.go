@@ -0,0 +1,3537 @@
+// Code generated by golang.org/x/tools/cmd/bundle. DO NOT EDIT.
+//go:generate bundle -o h3_bundle.go -prefix http3 -import golang.org/x/net/internal/httpcommon=net/http/internal/httpcommon -import golang.org/x/net/quic=net/quic golang.org/x/net/internal/http3
+
+// Package http3 implements the HTTP/3 protocol.
+//
+// This package is a work in progress.
+// It is not ready for production usage.
+// Its API is subject to change without notice.
+//
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package http
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"math"
+	"math/bits"
+	"net"
+	"net/http/httptrace"
+	"net/http/internal/httpcommon"
+	"net/quic"
+	"net/textproto"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
+	"golang.org/x/net/http2/hpack"
+)
+
+// extractTrailerFromHeader extracts the "Trailer" header values from a header
+// map, and populates a trailer map with those values as keys. The extracted
+// header values will be canonicalized.
+func http3extractTrailerFromHeader(header, trailer Header) {
+	for _, names := range header["Trailer"] {
+		names = textproto.TrimString(names)
+		for name := range strings.SplitSeq(names, ",") {
+			name = textproto.CanonicalMIMEHeaderKey(textproto.TrimString(name))
+			if !httpguts.ValidTrailerHeader(name) {
+				continue
+			}
+			trailer[name] = nil
+		}
+	}
+}
+
+// A bodyWriter writes a request or response body to a stream
+// as a series of DATA frames.
+type http3bodyWriter struct {
+	st      *http3stream
+	remain  int64              // -1 when content-length is not known
+	flush   bool               // flush the stream after every write
+	name    string             // "request" or "response"
+	trailer Header             // trailer headers that will be written once bodyWriter is closed.
+	enc     *http3qpackEncoder // QPACK encoder used by the connection.
+}
+
+func (w *http3bodyWriter) write(ps ...[]byte) (n int, err error) {
+	var size int64
+	for _, p := range ps {
+		size += int64(len(p))
+	}
+	// If write is called with empty byte slices, just return instead of
+	// sending out a DATA frame containing nothing.
+	if size == 0 {
+		return 0, nil
+	}
+	if w.remain >= 0 && size > w.remain {
+		return 0, &http3streamError{
+			code:    http3errH3InternalError,
+			message: w.name + " body longer than specified content length",
+		}
+	}
+	w.st.writeVarint(int64(http3frameTypeData))
+	w.st.writeVarint(size)
+	for _, p := range ps {
+		var n2 int
+		n2, err = w.st.Write(p)
+		n += n2
+		if w.remain >= 0 {
+			w.remain -= int64(n)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if w.flush && err == nil {
+		err = w.st.Flush()
+	}
+	if err != nil {
+		err = fmt.Errorf("writing %v body: %w", w.name, err)
+	}
+	return n, err
+}
+
+func (w *http3bodyWriter) Write(p []byte) (n int, err error) {
+	return w.write(p)
+}
+
+func (w *http3bodyWriter) Close() error {
+	if w.remain > 0 {
+		return errors.New(w.name + " body shorter than specified content length")
+	}
+	if len(w.trailer) > 0 {
+		encTrailer := w.enc.encode(func(f func(itype http3indexType, name, value string)) {
+			for name, values := range w.trailer {
+				if !httpguts.ValidHeaderFieldName(name) {
+					continue
+				}
+				for _, val := range values {
+					if !httpguts.ValidHeaderFieldValue(val) {
+						continue
+					}
+					f(http3mayIndex, name, val)
+				}
+			}
+		})
+		w.st.writeVarint(int64(http3frameTypeHeaders))
+		w.st.writeVarint(int64(len(encTrailer)))
+		w.st.Write(encTrailer)
+	}
+	if w.st != nil {
+		w.st.CloseWrite()
+	}
+	return nil
+}
+
+// A bodyReader reads a request or response body from a stream.
+type http3bodyReader struct {
+	st *http3stream
+
+	mu     sync.Mutex
+	remain int64
+	err    error
+	// A map where the key represents the trailer header names we expect. If
+	// there is a HEADERS frame after reading DATA frames to EOF, the value of
+	// the headers will be written here. Keys in the map are assumed to be
+	// canonicalized.
+	// If filterTrailer is true, headers that are not already in the map will
+	// be ignored; otherwise, all headers will be added to the map.
+	trailer       Header
+	filterTrailer bool
+}
+
+func (r *http3bodyReader) Read(p []byte) (n int, err error) {
+	// The HTTP/1 and HTTP/2 implementations both permit concurrent reads from a body,
+	// in the sense that the race detector won't complain.
+	// Use a mutex here to provide the same behavior.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	defer func() {
+		if err != nil {
+			r.err = err
+		}
+	}()
+	if r.st.lim == 0 {
+		// We've finished reading the previous DATA frame, so end it.
+		if err := r.st.endFrame(); err != nil {
+			return 0, err
+		}
+	}
+	// Read the next DATA frame header,
+	// if we aren't already in the middle of one.
+	for r.st.lim < 0 {
+		ftype, err := r.st.readFrameHeader()
+		if err == io.EOF && r.remain > 0 {
+			return 0, &http3streamError{
+				code:    http3errH3MessageError,
+				message: "body shorter than content-length",
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch ftype {
+		case http3frameTypeData:
+			if r.remain >= 0 && r.st.lim > r.remain {
+				return 0, &http3streamError{
+					code:    http3errH3MessageError,
+					message: "body longer than content-length",
+				}
+			}
+			// Fall out of the loop and process the frame body below.
+		case http3frameTypeHeaders:
+			// This HEADERS frame contains the message trailers.
+			if r.remain > 0 {
+				return 0, &http3streamError{
+					code:    http3errH3MessageError,
+					message: "body shorter than content-length",
+				}
+			}
+			var dec http3qpackDecoder
+			if err := dec.decode(r.st, func(_ http3indexType, name, value string) error {
+				if r.trailer == nil {
+					return nil
+				}
+				if !http3validWireHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+					return nil
+				}
+				name = textproto.CanonicalMIMEHeaderKey(textproto.TrimString(name))
+				if !r.filterTrailer {
+					r.trailer.Add(name, value)
+				} else if _, ok := r.trailer[name]; ok {
+					r.trailer.Add(name, value)
+				}
+				return nil
+			}); err != nil {
+				return 0, err
+			}
+			if err := r.st.discardFrame(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		default:
+			if err := r.st.discardUnknownFrame(ftype); err != nil {
+				return 0, err
+			}
+		}
+	}
+	// We are now reading the content of a DATA frame.
+	// Fill the read buffer or read to the end of the frame,
+	// whichever comes first.
+	if int64(len(p)) > r.st.lim {
+		p = p[:r.st.lim]
+	}
+	n, err = r.st.Read(p)
+	if r.remain > 0 {
+		r.remain -= int64(n)
+	}
+	return n, err
+}
+
+func (r *http3bodyReader) Close() error {
+	// Unlike the HTTP/1 and HTTP/2 body readers (at the time of this comment being written),
+	// calling Close concurrently with Read will interrupt the read.
+	r.st.CloseRead()
+	// Make sure that any data that has already been written to bodyReader
+	// cannot be read after it has been closed.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = net.ErrClosed
+	r.remain = 0
+	return nil
+}
+
+type http3streamHandler interface {
+	handleControlStream(*http3stream) error
+	handlePushStream(*http3stream) error
+	handleEncoderStream(*http3stream) error
+	handleDecoderStream(*http3stream) error
+	handleRequestStream(*http3stream) error
+	abort(error)
+}
+
+type http3genericConn struct {
+	mu sync.Mutex
+
+	// The peer may create exactly one control, encoder, and decoder stream.
+	// streamsCreated is a bitset of streams created so far.
+	// Bits are 1 << streamType.
+	streamsCreated uint8
+}
+
+func (c *http3genericConn) acceptStreams(qconn *quic.Conn, h http3streamHandler) {
+	for {
+		// Use context.Background: This blocks until a stream is accepted
+		// or the connection closes.
+		st, err := qconn.AcceptStream(context.Background())
+		if err != nil {
+			return // connection closed
+		}
+		if st.IsReadOnly() {
+			go c.handleUnidirectionalStream(http3newStream(st), h)
+		} else {
+			go c.handleRequestStream(http3newStream(st), h)
+		}
+	}
+}
+
+func (c *http3genericConn) handleUnidirectionalStream(st *http3stream, h http3streamHandler) {
+	// Unidirectional stream header: One varint with the stream type.
+	v, err := st.readVarint()
+	if err != nil {
+		h.abort(&http3connectionError{
+			code:    http3errH3StreamCreationError,
+			message: "error reading unidirectional stream header",
+		})
+		return
+	}
+	stype := http3streamType(v)
+	if err := c.checkStreamCreation(stype); err != nil {
+		h.abort(err)
+		return
+	}
+	switch stype {
+	case http3streamTypeControl:
+		err = h.handleControlStream(st)
+	case http3streamTypePush:
+		err = h.handlePushStream(st)
+	case http3streamTypeEncoder:
+		err = h.handleEncoderStream(st)
+	case http3streamTypeDecoder:
+		err = h.handleDecoderStream(st)
+	default:
+		// "Recipients of unknown stream types MUST either abort reading
+		// of the stream or discard incoming data without further processing."
+		// https://www.rfc-editor.org/rfc/rfc9114.html#section-6.2-7
+		//
+		// We should send the H3_STREAM_CREATION_ERROR error code,
+		// but the quic package currently doesn't allow setting error codes
+		// for STOP_SENDING frames.
+		// TODO: Should CloseRead take an error code?
+		err = nil
+	}
+	if err == io.EOF {
+		err = &http3connectionError{
+			code:    http3errH3ClosedCriticalStream,
+			message: http3streamType(stype).String() + " stream closed",
+		}
+	}
+	c.handleStreamError(st, h, err)
+}
+
+func (c *http3genericConn) handleRequestStream(st *http3stream, h http3streamHandler) {
+	c.handleStreamError(st, h, h.handleRequestStream(st))
+}
+
+func (c *http3genericConn) handleStreamError(st *http3stream, h http3streamHandler, err error) {
+	switch err := err.(type) {
+	case *http3connectionError:
+		h.abort(err)
+	case nil:
+		st.CloseRead()
+		st.CloseWrite()
+	case *http3streamError:
+		st.CloseRead()
+		st.Reset(uint64(err.code))
+	default:
+		st.CloseRead()
+		st.Reset(uint64(http3errH3InternalError))
+	}
+}
+
+func (c *http3genericConn) checkStreamCreation(stype http3streamType) error {
+	switch stype {
+	case http3streamTypeControl, http3streamTypeEncoder, http3streamTypeDecoder:
+		// The peer may create exactly one control, encoder, and decoder stream.
+	default:
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bit := uint8(1) << stype
+	if c.streamsCreated&bit != 0 {
+		return &http3connectionError{
+			code:    http3errH3StreamCreationError,
+			message: "multiple " + stype.String() + " streams created",
+		}
+	}
+	c.streamsCreated |= bit
+	return nil
+}
+
+// http3Error is an HTTP/3 error code.
+type http3http3Error int
+
+const (
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-8.1
+	http3errH3NoError              = http3http3Error(0x0100)
+	http3errH3GeneralProtocolError = http3http3Error(0x0101)
+	http3errH3InternalError        = http3http3Error(0x0102)
+	http3errH3StreamCreationError  = http3http3Error(0x0103)
+	http3errH3ClosedCriticalStream = http3http3Error(0x0104)
+	http3errH3FrameUnexpected      = http3http3Error(0x0105)
+	http3errH3FrameError           = http3http3Error(0x0106)
+	http3errH3ExcessiveLoad        = http3http3Error(0x0107)
+	http3errH3IDError              = http3http3Error(0x0108)
+	http3errH3SettingsError        = http3http3Error(0x0109)
+	http3errH3MissingSettings      = http3http3Error(0x010a)
+	http3errH3RequestRejected      = http3http3Error(0x010b)
+	http3errH3RequestCancelled     = http3http3Error(0x010c)
+	http3errH3RequestIncomplete    = http3http3Error(0x010d)
+	http3errH3MessageError         = http3http3Error(0x010e)
+	http3errH3ConnectError         = http3http3Error(0x010f)
+	http3errH3VersionFallback      = http3http3Error(0x0110)
+
+	// https://www.rfc-editor.org/rfc/rfc9204.html#section-8.3
+	http3errQPACKDecompressionFailed = http3http3Error(0x0200)
+	http3errQPACKEncoderStreamError  = http3http3Error(0x0201)
+	http3errQPACKDecoderStreamError  = http3http3Error(0x0202)
+)
+
+func (e http3http3Error) Error() string {
+	switch e {
+	case http3errH3NoError:
+		return "H3_NO_ERROR"
+	case http3errH3GeneralProtocolError:
+		return "H3_GENERAL_PROTOCOL_ERROR"
+	case http3errH3InternalError:
+		return "H3_INTERNAL_ERROR"
+	case http3errH3StreamCreationError:
+		return "H3_STREAM_CREATION_ERROR"
+	case http3errH3ClosedCriticalStream:
+		return "H3_CLOSED_CRITICAL_STREAM"
+	case http3errH3FrameUnexpected:
+		return "H3_FRAME_UNEXPECTED"
+	case http3errH3FrameError:
+		return "H3_FRAME_ERROR"
+	case http3errH3ExcessiveLoad:
+		return "H3_EXCESSIVE_LOAD"
+	case http3errH3IDError:
+		return "H3_ID_ERROR"
+	case http3errH3SettingsError:
+		return "H3_SETTINGS_ERROR"
+	case http3errH3MissingSettings:
+		return "H3_MISSING_SETTINGS"
+	case http3errH3RequestRejected:
+		return "H3_REQUEST_REJECTED"
+	case http3errH3RequestCancelled:
+		return "H3_REQUEST_CANCELLED"
+	case http3errH3RequestIncomplete:
+		return "H3_REQUEST_INCOMPLETE"
+	case http3errH3MessageError:
+		return "H3_MESSAGE_ERROR"
+	case http3errH3ConnectError:
+		return "H3_CONNECT_ERROR"
+	case http3errH3VersionFallback:
+		return "H3_VERSION_FALLBACK"
+	case http3errQPACKDecompressionFailed:
+		return "QPACK_DECOMPRESSION_FAILED"
+	case http3errQPACKEncoderStreamError:
+		return "QPACK_ENCODER_STREAM_ERROR"
+	case http3errQPACKDecoderStreamError:
+		return "QPACK_DECODER_STREAM_ERROR"
+	}
+	return fmt.Sprintf("H3_ERROR_%v", int(e))
+}
+
+// A streamError is an error which terminates a stream, but not the connection.
+// https://www.rfc-editor.org/rfc/rfc9114.html#section-8-1
+type http3streamError struct {
+	code    http3http3Error
+	message string
+}
+
+func (e *http3streamError) Error() string { return e.message }
+
+func (e *http3streamError) Unwrap() error { return e.code }
+
+// A connectionError is an error which results in the entire connection closing.
+// https://www.rfc-editor.org/rfc/rfc9114.html#section-8-2
+type http3connectionError struct {
+	code    http3http3Error
+	message string
+}
+
+func (e *http3connectionError) Error() string { return e.message }
+
+func (e *http3connectionError) Unwrap() error { return e.code }
+
+var http3errConcurrentReadOnResBody = errors.New("http3: concurrent read on response body")
+
+// gzipReader wraps a response body so it can lazily
+// get gzip.Reader from the pool on the first call to Read.
+// After Close is called it puts gzip.Reader to the pool immediately
+// if there is no Read in progress or later when Read completes.
+type http3gzipReader struct {
+	body io.ReadCloser // underlying Response.Body
+	mu   sync.Mutex    // guards zr and zerr
+	zr   *gzip.Reader  // stores gzip reader from the pool between reads
+	zerr error         // sticky gzip reader init error or sentinel value to detect concurrent read and read after close
+}
+
+type http3eofReader struct{}
+
+func (http3eofReader) Read([]byte) (int, error) { return 0, io.EOF }
+
+func (http3eofReader) ReadByte() (byte, error) { return 0, io.EOF }
+
+var http3gzipPool = sync.Pool{New: func() any { return new(gzip.Reader) }}
+
+// gzipPoolGet gets a gzip.Reader from the pool and resets it to read from r.
+func http3gzipPoolGet(r io.Reader) (*gzip.Reader, error) {
+	zr := http3gzipPool.Get().(*gzip.Reader)
+	if err := zr.Reset(r); err != nil {
+		http3gzipPoolPut(zr)
+		return nil, err
+	}
+	return zr, nil
+}
+
+// gzipPoolPut puts a gzip.Reader back into the pool.
+func http3gzipPoolPut(zr *gzip.Reader) {
+	// Reset will allocate bufio.Reader if we pass it anything
+	// other than a flate.Reader, so ensure that it's getting one.
+	var r flate.Reader = http3eofReader{}
+	zr.Reset(r)
+	http3gzipPool.Put(zr)
+}
+
+// acquire returns a gzip.Reader for reading response body.
+// The reader must be released after use.
+func (gz *http3gzipReader) acquire() (*gzip.Reader, error) {
+	gz.mu.Lock()
+	defer gz.mu.Unlock()
+	if gz.zerr != nil {
+		return nil, gz.zerr
+	}
+	if gz.zr == nil {
+		// gzipPoolGet might block indefinitely since it reads the gzip header.
+		// Therefore, drop mu temporarily when using gzipPoolGet.
+		// We set zerr to errConcurrentReadOnResBody to prevent concurrent read
+		// even when mu is temporarily dropped.
+		gz.zerr = http3errConcurrentReadOnResBody
+		gz.mu.Unlock()
+		zr, err := http3gzipPoolGet(gz.body)
+		gz.mu.Lock()
+		// Guard against Close being called while gzipPoolGet is running.
+		if gz.zerr != http3errConcurrentReadOnResBody {
+			if zr != nil {
+				http3gzipPoolPut(zr)
+			}
+			return nil, gz.zerr
+		}
+		gz.zr, gz.zerr = zr, err
+		if gz.zerr != nil {
+			return nil, gz.zerr
+		}
+	}
+	ret := gz.zr
+	gz.zr, gz.zerr = nil, http3errConcurrentReadOnResBody
+	return ret, nil
+}
+
+// release returns the gzip.Reader to the pool if Close was called during Read.
+func (gz *http3gzipReader) release(zr *gzip.Reader) {
+	gz.mu.Lock()
+	defer gz.mu.Unlock()
+	if gz.zerr == http3errConcurrentReadOnResBody {
+		gz.zr, gz.zerr = zr, nil
+	} else { // fs.ErrClosed
+		http3gzipPoolPut(zr)
+	}
+}
+
+// close returns the gzip.Reader to the pool immediately or
+// signals release to do so after Read completes.
+func (gz *http3gzipReader) close() {
+	gz.mu.Lock()
+	defer gz.mu.Unlock()
+	if gz.zerr == nil && gz.zr != nil {
+		http3gzipPoolPut(gz.zr)
+		gz.zr = nil
+	}
+	gz.zerr = fs.ErrClosed
+}
+
+func (gz *http3gzipReader) Read(p []byte) (n int, err error) {
+	zr, err := gz.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer gz.release(zr)
+
+	return zr.Read(p)
+}
+
+func (gz *http3gzipReader) Close() error {
+	gz.close()
+
+	return gz.body.Close()
+}
+
+// Stream types.
+//
+// For unidirectional streams, the value is the stream type sent over the wire.
+//
+// For bidirectional streams (which are always request streams),
+// the value is arbitrary and never sent on the wire.
+type http3streamType int64
+
+const (
+	// Bidirectional request stream.
+	// All bidirectional streams are request streams.
+	// This stream type is never sent over the wire.
+	//
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-6.1
+	http3streamTypeRequest = http3streamType(-1)
+
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-6.2
+	http3streamTypeControl = http3streamType(0x00)
+	http3streamTypePush    = http3streamType(0x01)
+
+	// https://www.rfc-editor.org/rfc/rfc9204.html#section-4.2
+	http3streamTypeEncoder = http3streamType(0x02)
+	http3streamTypeDecoder = http3streamType(0x03)
+)
+
+// canceledCtx is a canceled Context.
+// Used for performing non-blocking QUIC operations.
+var http3canceledCtx = func() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}()
+
+func (stype http3streamType) String() string {
+	switch stype {
+	case http3streamTypeRequest:
+		return "request"
+	case http3streamTypeControl:
+		return "control"
+	case http3streamTypePush:
+		return "push"
+	case http3streamTypeEncoder:
+		return "encoder"
+	case http3streamTypeDecoder:
+		return "decoder"
+	default:
+		return "unknown"
+	}
+}
+
+// Frame types.
+type http3frameType int64
+
+const (
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2
+	http3frameTypeData        = http3frameType(0x00)
+	http3frameTypeHeaders     = http3frameType(0x01)
+	http3frameTypeCancelPush  = http3frameType(0x03)
+	http3frameTypeSettings    = http3frameType(0x04)
+	http3frameTypePushPromise = http3frameType(0x05)
+	http3frameTypeGoaway      = http3frameType(0x07)
+	http3frameTypeMaxPushID   = http3frameType(0x0d)
+)
+
+func (ftype http3frameType) String() string {
+	switch ftype {
+	case http3frameTypeData:
+		return "DATA"
+	case http3frameTypeHeaders:
+		return "HEADERS"
+	case http3frameTypeCancelPush:
+		return "CANCEL_PUSH"
+	case http3frameTypeSettings:
+		return "SETTINGS"
+	case http3frameTypePushPromise:
+		return "PUSH_PROMISE"
+	case http3frameTypeGoaway:
+		return "GOAWAY"
+	case http3frameTypeMaxPushID:
+		return "MAX_PUSH_ID"
+	default:
+		return fmt.Sprintf("UNKNOWN_%d", int64(ftype))
+	}
+}
+
+// QPACK (RFC 9204) header compression wire encoding.
+// https://www.rfc-editor.org/rfc/rfc9204.html
+
+// tableType is the static or dynamic table.
+//
+// The T bit in QPACK instructions indicates whether a table index refers to
+// the dynamic (T=0) or static (T=1) table. tableTypeForTBit and tableType.tbit
+// convert a T bit from the wire encoding to/from a tableType.
+type http3tableType byte
+
+const (
+	http3dynamicTable = 0x00 // T=0, dynamic table
+	http3staticTable  = 0xff // T=1, static table
+)
+
+// tableTypeForTbit returns the table type corresponding to a T bit value.
+// The input parameter contains a byte masked to contain only the T bit.
+func http3tableTypeForTbit(bit byte) http3tableType {
+	if bit == 0 {
+		return http3dynamicTable
+	}
+	return http3staticTable
+}
+
+// tbit produces the T bit corresponding to the table type.
+// The input parameter contains a byte with the T bit set to 1,
+// and the return is either the input or 0 depending on the table type.
+func (t http3tableType) tbit(bit byte) byte {
+	return bit & byte(t)
+}
+
+// indexType indicates a literal's indexing status.
+//
+// The N bit in QPACK instructions indicates whether a literal is "never-indexed".
+// A never-indexed literal (N=1) must not be encoded as an indexed literal if it
+// forwarded on another connection.
+//
+// (See https://www.rfc-editor.org/rfc/rfc9204.html#section-7.1 for details on the
+// security reasons for never-indexed literals.)
+type http3indexType byte
+
+const (
+	http3mayIndex   = 0x00 // N=0, not a never-indexed literal
+	http3neverIndex = 0xff // N=1, never-indexed literal
+)
+
+// indexTypeForNBit returns the index type corresponding to a N bit value.
+// The input parameter contains a byte masked to contain only the N bit.
+func http3indexTypeForNBit(bit byte) http3indexType {
+	if bit == 0 {
+		return http3mayIndex
+	}
+	return http3neverIndex
+}
+
+// nbit produces the N bit corresponding to the table type.
+// The input parameter contains a byte with the N bit set to 1,
+// and the return is either the input or 0 depending on the table type.
+func (t http3indexType) nbit(bit byte) byte {
+	return bit & byte(t)
+}
+
+// Indexed Field Line:
+//
+//       0   1   2   3   4   5   6   7
+//     +---+---+---+---+---+---+---+---+
+//     | 1 | T |      Index (6+)       |
+//     +---+---+-----------------------+
+//
+// https://www.rfc-editor.org/rfc/rfc9204.html#section-4.5.2
+
+func http3appendIndexedFieldLine(b []byte, ttype http3tableType, index int) []byte {
+	const tbit = 0b_01000000
+	return http3appendPrefixedInt(b, 0b_1000_0000|ttype.tbit(tbit), 6, int64(index))
+}
+
+func (st *http3stream) decodeIndexedFieldLine(b byte) (itype http3indexType, name, value string, err error) {
+	index, err := st.readPrefixedIntWithByte(b, 6)
+	if err != nil {
+		return 0, "", "", err
+	}
+	const tbit = 0b_0100_0000
+	if http3tableTypeForTbit(b&tbit) == http3staticTable {
+		ent, err := http3staticTableEntry(index)
+		if err != nil {
+			return 0, "", "", err
+		}
+		return http3mayIndex, ent.name, ent.value, nil
+	} else {
+		return 0, "", "", errors.New("dynamic table is not supported yet")
+	}
+}
+
+// Literal Field Line With Name Reference:
+//
+//      0   1   2   3   4   5   6   7
+//     +---+---+---+---+---+---+---+---+
+//     | 0 | 1 | N | T |Name Index (4+)|
+//     +---+---+---+---+---------------+
+//     | H |     Value Length (7+)     |
+//     +---+---------------------------+
+//     |  Value String (Length bytes)  |
+//     +-------------------------------+
+//
+// https://www.rfc-editor.org/rfc/rfc9204.html#section-4.5.4
+
+func http3appendLiteralFieldLineWithNameReference(b []byte, ttype http3tableType, itype http3indexType, nameIndex int, value string) []byte {
+	const tbit = 0b_0001_0000
+	const nbit = 0b_0010_0000
+	b = http3appendPrefixedInt(b, 0b_0100_0000|itype.nbit(nbit)|ttype.tbit(tbit), 4, int64(nameIndex))
+	b = http3appendPrefixedString(b, 0, 7, value)
+	return b
+}
+
+func (st *http3stream) decodeLiteralFieldLineWithNameReference(b byte) (itype http3indexType, name, value string, err error) {
+	nameIndex, err := st.readPrefixedIntWithByte(b, 4)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	const tbit = 0b_0001_0000
+	if http3tableTypeForTbit(b&tbit) == http3staticTable {
+		ent, err := http3staticTableEntry(nameIndex)
+		if err != nil {
+			return 0, "", "", err
+		}
+		name = ent.name
+	} else {
+		return 0, "", "", errors.New("dynamic table is not supported yet")
+	}
+
+	_, value, err = st.readPrefixedString(7)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	const nbit = 0b_0010_0000
+	itype = http3indexTypeForNBit(b & nbit)
+
+	return itype, name, value, nil
+}
+
+// Literal Field Line with Literal Name:
+//
+//       0   1   2   3   4   5   6   7
+//     +---+---+---+---+---+---+---+---+
+//     | 0 | 0 | 1 | N | H |NameLen(3+)|
+//     +---+---+---+---+---+-----------+
+//     |  Name String (Length bytes)   |
+//     +---+---------------------------+
+//     | H |     Value Length (7+)     |
+//     +---+---------------------------+
+//     |  Value String (Length bytes)  |
+//     +-------------------------------+
+//
+// https://www.rfc-editor.org/rfc/rfc9204.html#section-4.5.6
+
+func http3appendLiteralFieldLineWithLiteralName(b []byte, itype http3indexType, name, value string) []byte {
+	const nbit = 0b_0001_0000
+	b = http3appendPrefixedString(b, 0b_0010_0000|itype.nbit(nbit), 3, name)
+	b = http3appendPrefixedString(b, 0, 7, value)
+	return b
+}
+
+func (st *http3stream) decodeLiteralFieldLineWithLiteralName(b byte) (itype http3indexType, name, value string, err error) {
+	name, err = st.readPrefixedStringWithByte(b, 3)
+	if err != nil {
+		return 0, "", "", err
+	}
+	_, value, err = st.readPrefixedString(7)
+	if err != nil {
+		return 0, "", "", err
+	}
+	const nbit = 0b_0001_0000
+	itype = http3indexTypeForNBit(b & nbit)
+	return itype, name, value, nil
+}
+
+// Prefixed-integer encoding from RFC 7541, section 5.1
+//
+// Prefixed integers consist of some number of bits of data,
+// N bits of encoded integer, and 0 or more additional bytes of
+// encoded integer.
+//
+// The RFCs represent this as, for example:
+//
+//       0   1   2   3   4   5   6   7
+//     +---+---+---+---+---+---+---+---+
+//     | 0 | 0 | 1 |   Capacity (5+)   |
+//     +---+---+---+-------------------+
+//
+// "Capacity" is an integer with a 5-bit prefix.
+//
+// In the following functions, a "prefixLen" parameter is the number
+// of integer bits in the first byte (5 in the above example), and
+// a "firstByte" parameter is a byte containing the first byte of
+// the encoded value (0x001x_xxxx in the above example).
+//
+// https://www.rfc-editor.org/rfc/rfc9204.html#section-4.1.1
+// https://www.rfc-editor.org/rfc/rfc7541#section-5.1
+
+// readPrefixedInt reads an RFC 7541 prefixed integer from st.
+func (st *http3stream) readPrefixedInt(prefixLen uint8) (firstByte byte, v int64, err error) {
+	firstByte, err = st.ReadByte()
+	if err != nil {
+		return 0, 0, http3errQPACKDecompressionFailed
+	}
+	v, err = st.readPrefixedIntWithByte(firstByte, prefixLen)
+	return firstByte, v, err
+}
+
+// readPrefixedIntWithByte reads an RFC 7541 prefixed integer from st.
+// The first byte has already been read from the stream.
+func (st *http3stream) readPrefixedIntWithByte(firstByte byte, prefixLen uint8) (int64, error) {
+	prefixMask := (byte(1) << prefixLen) - 1
+	if v := firstByte & prefixMask; v != prefixMask {
+		return int64(v), nil
+	}
+	v, err := binary.ReadUvarint(st)
+	if err != nil {
+		return 0, http3errQPACKDecompressionFailed
+	}
+	if v > math.MaxInt64-uint64(prefixMask) {
+		return 0, http3errQPACKDecompressionFailed
+	}
+	return int64(v + uint64(prefixMask)), nil
+}
+
+// appendPrefixedInt appends an RFC 7541 prefixed integer to b.
+//
+// The firstByte parameter includes the non-integer bits of the first byte.
+// The other bits must be zero.
+func http3appendPrefixedInt(b []byte, firstByte byte, prefixLen uint8, i int64) []byte {
+	u := uint64(i)
+	prefixMask := (uint64(1) << prefixLen) - 1
+	if u < prefixMask {
+		return append(b, firstByte|byte(u))
+	}
+	b = append(b, firstByte|byte(prefixMask))
+	u -= prefixMask
+	return binary.AppendUvarint(b, u)
+}
+
+// String literal encoding from RFC 7541, section 5.2
+//
+// String literals consist of a single bit flag indicating
+// whether the string is Huffman-encoded, a prefixed integer (see above),
+// and the string.
+//
+// https://www.rfc-editor.org/rfc/rfc9204.html#section-4.1.2
+// https://www.rfc-editor.org/rfc/rfc7541#section-5.2
+
+// readPrefixedString reads an RFC 7541 string from st.
+func (st *http3stream) readPrefixedString(prefixLen uint8) (firstByte byte, s string, err error) {
+	firstByte, err = st.ReadByte()
+	if err != nil {
+		return 0, "", http3errQPACKDecompressionFailed
+	}
+	s, err = st.readPrefixedStringWithByte(firstByte, prefixLen)
+	return firstByte, s, err
+}
+
+// readPrefixedStringWithByte reads an RFC 7541 string from st.
+// The first byte has already been read from the stream.
+func (st *http3stream) readPrefixedStringWithByte(firstByte byte, prefixLen uint8) (s string, err error) {
+	size, err := st.readPrefixedIntWithByte(firstByte, prefixLen)
+	if err != nil {
+		return "", http3errQPACKDecompressionFailed
+	}
+	if st.lim >= 0 && size > st.lim {
+		return "", http3errQPACKDecompressionFailed
+	}
+
+	hbit := byte(1) << prefixLen
+	isHuffman := firstByte&hbit != 0
+
+	// TODO: Avoid allocating here.
+	data := make([]byte, size)
+	if _, err := io.ReadFull(st, data); err != nil {
+		return "", http3errQPACKDecompressionFailed
+	}
+	if isHuffman {
+		// TODO: Move Huffman functions into a new package that hpack (HTTP/2)
+		// and this package can both import. Most of the hpack package isn't
+		// relevant to HTTP/3.
+		s, err := hpack.HuffmanDecodeToString(data)
+		if err != nil {
+			return "", http3errQPACKDecompressionFailed
+		}
+		return s, nil
+	}
+	return string(data), nil
+}
+
+// appendPrefixedString appends an RFC 7541 string to st,
+// applying Huffman encoding and setting the H bit (indicating Huffman encoding)
+// when appropriate.
+//
+// The firstByte parameter includes the non-integer bits of the first byte.
+// The other bits must be zero.
+func http3appendPrefixedString(b []byte, firstByte byte, prefixLen uint8, s string) []byte {
+	huffmanLen := hpack.HuffmanEncodeLength(s)
+	if huffmanLen < uint64(len(s)) {
+		hbit := byte(1) << prefixLen
+		b = http3appendPrefixedInt(b, firstByte|hbit, prefixLen, int64(huffmanLen))
+		b = hpack.AppendHuffmanString(b, s)
+	} else {
+		b = http3appendPrefixedInt(b, firstByte, prefixLen, int64(len(s)))
+		b = append(b, s...)
+	}
+	return b
+}
+
+// validWireHeaderFieldName reports whether v is a valid header field
+// name (key). See httpguts.ValidHeaderFieldName for the base rules.
+//
+// Further, http3 says:
+// "A request or response containing uppercase characters in field names MUST
+// be treated as malformed."
+//
+// This function does not validate whether a pseudo-header field name is valid.
+func http3validWireHeaderFieldName(v string) bool {
+	if len(v) == 0 {
+		return false
+	}
+	for _, r := range v {
+		if !httpguts.IsTokenRune(r) {
+			return false
+		}
+		if 'A' <= r && r <= 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+type http3qpackDecoder struct {
+	// The decoder has no state for now,
+	// but that'll change once we add dynamic table support.
+	//
+	// TODO: dynamic table support.
+}
+
+func (qd *http3qpackDecoder) decode(st *http3stream, f func(itype http3indexType, name, value string) error) error {
+	// Encoded Field Section prefix.
+
+	// We set SETTINGS_QPACK_MAX_TABLE_CAPACITY to 0,
+	// so the Required Insert Count must be 0.
+	_, requiredInsertCount, err := st.readPrefixedInt(8)
+	if err != nil {
+		return err
+	}
+	if requiredInsertCount != 0 {
+		return http3errQPACKDecompressionFailed
+	}
+
+	// Delta Base. We don't use the dynamic table yet, so this may be ignored.
+	_, _, err = st.readPrefixedInt(7)
+	if err != nil {
+		return err
+	}
+
+	sawNonPseudo := false
+	for st.lim > 0 {
+		firstByte, err := st.ReadByte()
+		if err != nil {
+			return err
+		}
+		var name, value string
+		var itype http3indexType
+		switch bits.LeadingZeros8(firstByte) {
+		case 0:
+			// Indexed Field Line
+			itype, name, value, err = st.decodeIndexedFieldLine(firstByte)
+		case 1:
+			// Literal Field Line With Name Reference
+			itype, name, value, err = st.decodeLiteralFieldLineWithNameReference(firstByte)
+		case 2:
+			// Literal Field Line with Literal Name
+			itype, name, value, err = st.decodeLiteralFieldLineWithLiteralName(firstByte)
+		case 3:
+			// Indexed Field Line With Post-Base Index
+			err = errors.New("dynamic table is not supported yet")
+		case 4:
+			// Indexed Field Line With Post-Base Name Reference
+			err = errors.New("dynamic table is not supported yet")
+		}
+		if err != nil {
+			return err
+		}
+		if len(name) == 0 {
+			return http3errH3MessageError
+		}
+		if name[0] == ':' {
+			if sawNonPseudo {
+				return http3errH3MessageError
+			}
+		} else {
+			sawNonPseudo = true
+		}
+		if err := f(itype, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type http3qpackEncoder struct {
+	// The encoder has no state for now,
+	// but that'll change once we add dynamic table support.
+	//
+	// TODO: dynamic table support.
+}
+
+func (qe *http3qpackEncoder) init() {
+	http3staticTableOnce.Do(http3initStaticTableMaps)
+}
+
+// encode encodes a list of headers into a QPACK encoded field section.
+//
+// The headers func must produce the same headers on repeated calls,
+// although the order may vary.
+func (qe *http3qpackEncoder) encode(headers func(func(itype http3indexType, name, value string))) []byte {
+	// Encoded Field Section prefix.
+	//
+	// We don't yet use the dynamic table, so both values here are zero.
+	var b []byte
+	b = http3appendPrefixedInt(b, 0, 8, 0) // Required Insert Count
+	b = http3appendPrefixedInt(b, 0, 7, 0) // Delta Base
+
+	headers(func(itype http3indexType, name, value string) {
+		// Technically, it is the responsibility of the protocol using HTTP/3
+		// to ensure that all field names are already in lowercase. However,
+		// this QPACK implementation is solely used by and live in the http3
+		// package. So, we might as well do the lowercasing here to make sure
+		// we do not miss any callsites or need to create yet another struct
+		// wrapping the qpackEncoder.
+		name, ascii := httpcommon.LowerHeader(name)
+		// Skip writing invalid headers. Per RFC 9114 section 4.2: "Field
+		// names are strings containing a subset of ASCII characters."
+		if !ascii {
+			return
+		}
+		if itype == http3mayIndex {
+			if i, ok := http3staticTableByNameValue[http3tableEntry{name, value}]; ok {
+				b = http3appendIndexedFieldLine(b, http3staticTable, i)
+				return
+			}
+		}
+		if i, ok := http3staticTableByName[name]; ok {
+			b = http3appendLiteralFieldLineWithNameReference(b, http3staticTable, itype, i, value)
+		} else {
+			b = http3appendLiteralFieldLineWithLiteralName(b, itype, name, value)
+		}
+	})
+
+	return b
+}
+
+type http3tableEntry struct {
+	name  string
+	value string
+}
+
+// staticTableEntry returns the static table entry with the given index.
+func http3staticTableEntry(index int64) (http3tableEntry, error) {
+	if index < 0 || index >= int64(len(http3staticTableEntries)) {
+		return http3tableEntry{}, http3errQPACKDecompressionFailed
+	}
+	return http3staticTableEntries[index], nil
+}
+
+func http3initStaticTableMaps() {
+	http3staticTableByName = make(map[string]int)
+	http3staticTableByNameValue = make(map[http3tableEntry]int)
+	for i, ent := range http3staticTableEntries {
+		if _, ok := http3staticTableByName[ent.name]; !ok {
+			http3staticTableByName[ent.name] = i
+		}
+		http3staticTableByNameValue[ent] = i
+	}
+}
+
+var (
+	http3staticTableOnce        sync.Once
+	http3staticTableByName      map[string]int
+	http3staticTableByNameValue map[http3tableEntry]int
+)
+
+// https://www.rfc-editor.org/rfc/rfc9204.html#appendix-A
+//
+// Note that this is different from the HTTP/2 static table.
+var http3staticTableEntries = [...]http3tableEntry{
+	0:  {":authority", ""},
+	1:  {":path", "/"},
+	2:  {"age", "0"},
+	3:  {"content-disposition", ""},
+	4:  {"content-length", "0"},
+	5:  {"cookie", ""},
+	6:  {"date", ""},
+	7:  {"etag", ""},
+	8:  {"if-modified-since", ""},
+	9:  {"if-none-match", ""},
+	10: {"last-modified", ""},
+	11: {"link", ""},
+	12: {"location", ""},
+	13: {"referer", ""},
+	14: {"set-cookie", ""},
+	15: {":method", "CONNECT"},
+	16: {":method", "DELETE"},
+	17: {":method", "GET"},
+	18: {":method", "HEAD"},
+	19: {":method", "OPTIONS"},
+	20: {":method", "POST"},
+	21: {":method", "PUT"},
+	22: {":scheme", "http"},
+	23: {":scheme", "https"},
+	24: {":status", "103"},
+	25: {":status", "200"},
+	26: {":status", "304"},
+	27: {":status", "404"},
+	28: {":status", "503"},
+	29: {"accept", "*/*"},
+	30: {"accept", "application/dns-message"},
+	31: {"accept-encoding", "gzip, deflate, br"},
+	32: {"accept-ranges", "bytes"},
+	33: {"access-control-allow-headers", "cache-control"},
+	34: {"access-control-allow-headers", "content-type"},
+	35: {"access-control-allow-origin", "*"},
+	36: {"cache-control", "max-age=0"},
+	37: {"cache-control", "max-age=2592000"},
+	38: {"cache-control", "max-age=604800"},
+	39: {"cache-control", "no-cache"},
+	40: {"cache-control", "no-store"},
+	41: {"cache-control", "public, max-age=31536000"},
+	42: {"content-encoding", "br"},
+	43: {"content-encoding", "gzip"},
+	44: {"content-type", "application/dns-message"},
+	45: {"content-type", "application/javascript"},
+	46: {"content-type", "application/json"},
+	47: {"content-type", "application/x-www-form-urlencoded"},
+	48: {"content-type", "image/gif"},
+	49: {"content-type", "image/jpeg"},
+	50: {"content-type", "image/png"},
+	51: {"content-type", "text/css"},
+	52: {"content-type", "text/html; charset=utf-8"},
+	53: {"content-type", "text/plain"},
+	54: {"content-type", "text/plain;charset=utf-8"},
+	55: {"range", "bytes=0-"},
+	56: {"strict-transport-security", "max-age=31536000"},
+	57: {"strict-transport-security", "max-age=31536000; includesubdomains"},
+	58: {"strict-transport-security", "max-age=31536000; includesubdomains; preload"},
+	59: {"vary", "accept-encoding"},
+	60: {"vary", "origin"},
+	61: {"x-content-type-options", "nosniff"},
+	62: {"x-xss-protection", "1; mode=block"},
+	63: {":status", "100"},
+	64: {":status", "204"},
+	65: {":status", "206"},
+	66: {":status", "302"},
+	67: {":status", "400"},
+	68: {":status", "403"},
+	69: {":status", "421"},
+	70: {":status", "425"},
+	71: {":status", "500"},
+	72: {"accept-language", ""},
+	73: {"access-control-allow-credentials", "FALSE"},
+	74: {"access-control-allow-credentials", "TRUE"},
+	75: {"access-control-allow-headers", "*"},
+	76: {"access-control-allow-methods", "get"},
+	77: {"access-control-allow-methods", "get, post, options"},
+	78: {"access-control-allow-methods", "options"},
+	79: {"access-control-expose-headers", "content-length"},
+	80: {"access-control-request-headers", "content-type"},
+	81: {"access-control-request-method", "get"},
+	82: {"access-control-request-method", "post"},
+	83: {"alt-svc", "clear"},
+	84: {"authorization", ""},
+	85: {"content-security-policy", "script-src 'none'; object-src 'none'; base-uri 'none'"},
+	86: {"early-data", "1"},
+	87: {"expect-ct", ""},
+	88: {"forwarded", ""},
+	89: {"if-range", ""},
+	90: {"origin", ""},
+	91: {"purpose", "prefetch"},
+	92: {"server", ""},
+	93: {"timing-allow-origin", "*"},
+	94: {"upgrade-insecure-requests", "1"},
+	95: {"user-agent", ""},
+	96: {"x-forwarded-for", ""},
+	97: {"x-frame-options", "deny"},
+	98: {"x-frame-options", "sameorigin"},
+}
+
+func http3newQUICConfig(config *quic.Config, tlsConfig *tls.Config) *quic.Config {
+	config = config.Clone()
+	if config == nil {
+		config = &quic.Config{}
+	}
+	if !slices.Equal(tlsConfig.NextProtos, []string{"h3"}) {
+		tlsConfig = tlsConfig.Clone()
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		tlsConfig.NextProtos = []string{"h3"}
+	}
+	config.TLSConfig = tlsConfig
+	return config
+}
+
+type http3roundTripState struct {
+	cc *http3clientConn
+	st *http3stream
+
+	// Request body, provided by the caller.
+	onceCloseReqBody sync.Once
+	reqBody          io.ReadCloser
+
+	reqBodyWriter http3bodyWriter
+
+	// Response.Body, provided to the caller.
+	respBody io.ReadCloser
+
+	trace *httptrace.ClientTrace
+
+	errOnce sync.Once
+	err     error
+}
+
+// abort terminates the RoundTrip.
+// It returns the first fatal error encountered by the RoundTrip call.
+func (rt *http3roundTripState) abort(err error) error {
+	rt.errOnce.Do(func() {
+		rt.err = err
+
+		rt.cc.mu.Lock()
+		rt.cc.active--
+		rt.cc.mu.Unlock()
+		rt.cc.maybeCallStateHook()
+
+		switch e := err.(type) {
+		case *http3connectionError:
+			rt.cc.abort(e)
+		case *http3streamError:
+			rt.st.CloseRead()
+			rt.st.Reset(uint64(e.code))
+		default:
+			rt.st.CloseRead()
+			rt.st.Reset(uint64(http3errH3NoError))
+		}
+	})
+	return rt.err
+}
+
+// closeReqBody closes the Request.Body, at most once.
+func (rt *http3roundTripState) closeReqBody() {
+	if rt.reqBody != nil {
+		rt.onceCloseReqBody.Do(func() {
+			rt.reqBody.Close()
+		})
+	}
+}
+
+// TODO: Set up the rest of the hooks that might be in rt.trace.
+func (rt *http3roundTripState) maybeCallGot1xxResponse(status int, h Header) error {
+	if rt.trace == nil || rt.trace.Got1xxResponse == nil {
+		return nil
+	}
+	return rt.trace.Got1xxResponse(status, textproto.MIMEHeader(h))
+}
+
+func (rt *http3roundTripState) maybeCallGot100Continue() {
+	if rt.trace == nil || rt.trace.Got100Continue == nil {
+		return
+	}
+	rt.trace.Got100Continue()
+}
+
+func (rt *http3roundTripState) maybeCallWait100Continue() {
+	if rt.trace == nil || rt.trace.Wait100Continue == nil {
+		return
+	}
+	rt.trace.Wait100Continue()
+}
+
+// RoundTrip sends a request on the connection.
+func (cc *http3clientConn) RoundTrip(req *Request) (_ *Response, err error) {
+	cc.mu.Lock()
+	if cc.reserved > 0 {
+		cc.reserved--
+	}
+	cc.active++
+	cc.mu.Unlock()
+
+	// Each request gets its own QUIC stream.
+	st, err := http3newConnStream(req.Context(), cc.qconn, http3streamTypeRequest)
+	if err != nil {
+		cc.mu.Lock()
+		cc.active--
+		cc.mu.Unlock()
+		cc.maybeCallStateHook()
+		return nil, err
+	}
+	rt := &http3roundTripState{
+		cc:      cc,
+		st:      st,
+		trace:   httptrace.ContextClientTrace(req.Context()),
+		reqBody: req.Body,
+	}
+	if rt.reqBody == nil {
+		rt.reqBody = NoBody
+	}
+	defer func() {
+		if err != nil {
+			err = rt.abort(err)
+		}
+	}()
+
+	// Cancel reads/writes on the stream when the request expires.
+	st.stream.SetReadContext(req.Context())
+	st.stream.SetWriteContext(req.Context())
+
+	addedGzip := httpcommon.IsRequestGzip(req.Method, req.Header, cc.tr.tr1.DisableCompression)
+	headers := cc.enc.encode(func(yield func(itype http3indexType, name, value string)) {
+		_, err = httpcommon.EncodeHeaders(req.Context(), httpcommon.EncodeHeadersParam{
+			Request: httpcommon.Request{
+				URL:                 req.URL,
+				Method:              req.Method,
+				Host:                req.Host,
+				Header:              req.Header,
+				Trailer:             req.Trailer,
+				ActualContentLength: http3actualContentLength(req),
+			},
+			AddGzipHeader:         addedGzip,
+			PeerMaxHeaderListSize: 0,
+			DefaultUserAgent:      "Go-http-client/3",
+		}, func(name, value string) {
+			// Issue #71374: Consider supporting never-indexed fields.
+			yield(http3mayIndex, name, value)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the HEADERS frame.
+	st.writeVarint(int64(http3frameTypeHeaders))
+	st.writeVarint(int64(len(headers)))
+	st.Write(headers)
+	if err := st.Flush(); err != nil {
+		return nil, err
+	}
+
+	var bodyAndTrailerWritten bool
+	is100ContinueReq := httpguts.HeaderValuesContainsToken(req.Header["Expect"], "100-continue")
+	if is100ContinueReq {
+		rt.maybeCallWait100Continue()
+	} else {
+		bodyAndTrailerWritten = true
+		go cc.writeBodyAndTrailer(rt, req)
+	}
+
+	// Read the response headers.
+	for {
+		ftype, err := st.readFrameHeader()
+		if err != nil {
+			return nil, err
+		}
+		switch ftype {
+		case http3frameTypeHeaders:
+			statusCode, h, err := cc.handleHeaders(st)
+			if err != nil {
+				return nil, err
+			}
+
+			// TODO: Handle 1xx responses.
+			if http3isInfoStatus(statusCode) {
+				if err := rt.maybeCallGot1xxResponse(statusCode, h); err != nil {
+					return nil, err
+				}
+				switch statusCode {
+				case 100:
+					rt.maybeCallGot100Continue()
+					if is100ContinueReq && !bodyAndTrailerWritten {
+						bodyAndTrailerWritten = true
+						go cc.writeBodyAndTrailer(rt, req)
+						continue
+					}
+					// If we did not send "Expect: 100-continue" request but
+					// received status 100 anyways, just continue per usual and
+					// let the caller decide what to do with the response.
+				default:
+					continue
+				}
+			}
+
+			// We have the response headers.
+			// Set up the response and return it to the caller.
+			contentLength, err := http3parseResponseContentLength(req.Method, statusCode, h)
+			if err != nil {
+				return nil, err
+			}
+
+			trailer := make(Header)
+			http3extractTrailerFromHeader(h, trailer)
+			delete(h, "Trailer")
+
+			if (contentLength != 0 && req.Method != MethodHead) || len(trailer) > 0 {
+				rt.respBody = &http3bodyReader{
+					st:      st,
+					remain:  contentLength,
+					trailer: trailer,
+				}
+			} else {
+				rt.respBody = NoBody
+			}
+			resp := &Response{
+				Proto:         "HTTP/3.0",
+				ProtoMajor:    3,
+				Header:        h,
+				StatusCode:    statusCode,
+				Status:        strconv.Itoa(statusCode) + " " + StatusText(statusCode),
+				ContentLength: contentLength,
+				Trailer:       trailer,
+				Body:          (*http3transportResponseBody)(rt),
+			}
+			if addedGzip && strings.EqualFold(h.Get("Content-Encoding"), "gzip") {
+				resp.Body = &http3gzipReader{body: resp.Body}
+				h.Del("Content-Encoding")
+				h.Del("Content-Length")
+				resp.ContentLength = -1
+				resp.Uncompressed = true
+			}
+			return resp, nil
+		case http3frameTypePushPromise:
+			if err := cc.handlePushPromise(st); err != nil {
+				return nil, err
+			}
+		default:
+			if err := st.discardUnknownFrame(ftype); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// actualContentLength returns a sanitized version of req.ContentLength,
+// where 0 actually means zero (not unknown) and -1 means unknown.
+func http3actualContentLength(req *Request) int64 {
+	if req.Body == nil || req.Body == NoBody {
+		return 0
+	}
+	if req.ContentLength != 0 {
+		return req.ContentLength
+	}
+	return -1
+}
+
+// writeBodyAndTrailer handles writing the body and trailer for a given
+// request, if any. This function will close the write direction of the stream.
+func (cc *http3clientConn) writeBodyAndTrailer(rt *http3roundTripState, req *Request) {
+	defer rt.closeReqBody()
+
+	declaredTrailer := req.Trailer.Clone()
+
+	rt.reqBodyWriter.st = rt.st
+	rt.reqBodyWriter.remain = http3actualContentLength(req)
+	rt.reqBodyWriter.flush = true
+	rt.reqBodyWriter.name = "request"
+	rt.reqBodyWriter.trailer = req.Trailer
+	rt.reqBodyWriter.enc = &cc.enc
+
+	if _, err := io.Copy(&rt.reqBodyWriter, rt.reqBody); err != nil {
+		rt.abort(err)
+	}
+	// Get rid of any trailer that was not declared beforehand, before we
+	// close the request body which will cause the trailer headers to be
+	// written.
+	for name := range req.Trailer {
+		if _, ok := declaredTrailer[name]; !ok {
+			delete(req.Trailer, name)
+		}
+	}
+	if err := rt.reqBodyWriter.Close(); err != nil {
+		rt.abort(err)
+	}
+}
+
+// transportResponseBody is the Response.Body returned by RoundTrip.
+type http3transportResponseBody http3roundTripState
+
+// Read is Response.Body.Read.
+
+// Read is Response.Body.Read.
+func (b *http3transportResponseBody) Read(p []byte) (n int, err error) {
+	return b.respBody.Read(p)
+}
+
+var http3errRespBodyClosed = errors.New("response body closed")
+
+// Close is Response.Body.Close.
+// Closing the response body is how the caller signals that they're done with a request.
+func (b *http3transportResponseBody) Close() error {
+	rt := (*http3roundTripState)(b)
+	// Close the request body, which should wake up copyRequestBody if it's
+	// currently blocked reading the body.
+	rt.closeReqBody()
+	// Close the request stream, since we're done with the request.
+	// Reset closes the sending half of the stream.
+	rt.st.Reset(uint64(http3errH3NoError))
+	// respBody.Close is responsible for closing the receiving half.
+	err := rt.respBody.Close()
+	if err == nil {
+		err = http3errRespBodyClosed
+	}
+	err = rt.abort(err)
+	if err == http3errRespBodyClosed {
+		// No other errors occurred before closing Response.Body,
+		// so consider this a successful request.
+		return nil
+	}
+	return err
+}
+
+func http3parseResponseContentLength(method string, statusCode int, h Header) (int64, error) {
+	clens := h["Content-Length"]
+	if len(clens) == 0 {
+		return -1, nil
+	}
+
+	// We allow duplicate Content-Length headers,
+	// but only if they all have the same value.
+	for _, v := range clens[1:] {
+		if clens[0] != v {
+			return -1, &http3streamError{http3errH3MessageError, "mismatching Content-Length headers"}
+		}
+	}
+
+	// "A server MUST NOT send a Content-Length header field in any response
+	// with a status code of 1xx (Informational) or 204 (No Content).
+	// A server MUST NOT send a Content-Length header field in any 2xx (Successful)
+	// response to a CONNECT request [...]"
+	// https://www.rfc-editor.org/rfc/rfc9110#section-8.6-8
+	if (statusCode >= 100 && statusCode < 200) ||
+		statusCode == 204 ||
+		(method == "CONNECT" && statusCode >= 200 && statusCode < 300) {
+		// This is a protocol violation, but a fairly harmless one.
+		// Just ignore the header.
+		return -1, nil
+	}
+
+	contentLen, err := strconv.ParseUint(clens[0], 10, 63)
+	if err != nil {
+		return -1, &http3streamError{http3errH3MessageError, "invalid Content-Length header"}
+	}
+	return int64(contentLen), nil
+}
+
+func (cc *http3clientConn) handleHeaders(st *http3stream) (statusCode int, h Header, err error) {
+	haveStatus := false
+	cookie := ""
+	// Issue #71374: Consider tracking the never-indexed status of headers
+	// with the N bit set in their QPACK encoding.
+	err = cc.dec.decode(st, func(_ http3indexType, name, value string) error {
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return &http3streamError{http3errH3MessageError, "invalid field value"}
+		}
+		switch {
+		case name == ":status":
+			if haveStatus {
+				return &http3streamError{http3errH3MessageError, "duplicate :status"}
+			}
+			haveStatus = true
+			statusCode, err = strconv.Atoi(value)
+			if err != nil {
+				return &http3streamError{http3errH3MessageError, "invalid :status"}
+			}
+		case name[0] == ':':
+			// "Endpoints MUST treat a request or response
+			// that contains undefined or invalid
+			// pseudo-header fields as malformed."
+			// https://www.rfc-editor.org/rfc/rfc9114.html#section-4.3-3
+			return &http3streamError{http3errH3MessageError, "undefined pseudo-header"}
+		case name == "cookie":
+			// "If a decompressed field section contains multiple cookie field lines,
+			// these MUST be concatenated into a single byte string [...]"
+			// using the two-byte delimiter of "; "''
+			// https://www.rfc-editor.org/rfc/rfc9114.html#section-4.2.1-2
+			if cookie == "" {
+				cookie = value
+			} else {
+				cookie += "; " + value
+			}
+		default:
+			if !http3validWireHeaderFieldName(name) {
+				return &http3streamError{http3errH3MessageError, "invalid field name"}
+			}
+			if h == nil {
+				h = make(Header)
+			}
+			// TODO: Use a per-connection canonicalization cache as we do in HTTP/2.
+			// Maybe we could put this in the QPACK decoder and have it deliver
+			// pre-canonicalized headers to us here?
+			cname := httpcommon.CanonicalHeader(name)
+			// TODO: Consider using a single []string slice for all headers,
+			// as we do in the HTTP/1 and HTTP/2 cases.
+			// This is a bit tricky, since we don't know the number of headers
+			// at the start of decoding. Perhaps it's worth doing a two-pass decode,
+			// or perhaps we should just allocate header value slices in
+			// reasonably-sized chunks.
+			h[cname] = append(h[cname], value)
+		}
+		return nil
+	})
+	if !haveStatus {
+		// "[The :status] pseudo-header field MUST be included in all responses [...]"
+		// https://www.rfc-editor.org/rfc/rfc9114.html#section-4.3.2-1
+		err = http3errH3MessageError
+	}
+	if cookie != "" {
+		if h == nil {
+			h = make(Header)
+		}
+		h["Cookie"] = []string{cookie}
+	}
+	if err := st.endFrame(); err != nil {
+		return 0, nil, err
+	}
+	return statusCode, h, err
+}
+
+func (cc *http3clientConn) handlePushPromise(st *http3stream) error {
+	// "A client MUST treat receipt of a PUSH_PROMISE frame that contains a
+	// larger push ID than the client has advertised as a connection error of H3_ID_ERROR."
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.5-5
+	return &http3connectionError{
+		code:    http3errH3IDError,
+		message: "PUSH_PROMISE received when no MAX_PUSH_ID has been sent",
+	}
+}
+
+// A server is an HTTP/3 server.
+// The zero value for server is a valid server.
+type http3server struct {
+	srv1 *Server
+	opts http3ServerOpts
+
+	initOnce sync.Once
+
+	// connClosed is used to signal that a connection has been unregistered
+	// from activeConns. That way, when shutting down gracefully, the server
+	// can avoid busy-waiting for activeConns to be empty.
+	connClosed  chan any
+	mu          sync.Mutex // Guards fields below.
+	activeConns map[*http3serverConn]struct{}
+}
+
+// netHTTPServer implements the net/http.http3Server interface,
+// allowing our HTTP/3 server to integrate with net/http.
+type http3netHTTPServer struct {
+	*http3server
+}
+
+// Implement net.Listener, so we can pass a netHTTPServer to net/http.Server.Serve.
+func (http3netHTTPServer) Accept() (net.Conn, error) { return nil, net.ErrClosed }
+
+func (http3netHTTPServer) Close() error { return nil }
+
+func (http3netHTTPServer) Addr() net.Addr { return nil }
+
+// ServeHTTP3 starts serving HTTP/3 on a UDP port.
+//
+// The ctx parameter is used as the base context for request handlers
+// for requests receieved via this port.
+func (s http3netHTTPServer) ServeHTTP3(ctx context.Context, conn net.PacketConn, tlsConfig *tls.Config, h Handler) error {
+	s.init()
+	e, err := quic.NewEndpoint(conn, http3newQUICConfig(s.opts.QUICConfig, tlsConfig))
+	if err != nil {
+		return err
+	}
+	return s.serve(ctx, e, h)
+}
+
+// Shutdown shuts down the server.
+func (s http3netHTTPServer) Shutdown(ctx context.Context) error {
+	s.shutdown(ctx)
+	return nil
+}
+
+type http3ServerOpts struct {
+	// QUICConfig is the QUIC configuration used by the server.
+	// QUICConfig may be nil and should not be modified after calling
+	// RegisterServer.
+	// If QUICConfig.TLSConfig is nil, the TLSConfig of the net/http Server
+	// given to RegisterServer will be used.
+	QUICConfig *quic.Config
+}
+
+// RegisterServer adds HTTP/3 support to a net/http Server.
+//
+// RegisterServer must be called before s begins serving, and only affects
+// s.ListenAndServeTLS.
+func http3RegisterServer(s *Server, opts http3ServerOpts) error {
+	if err := s.Serve(http3netHTTPServer{&http3server{
+		opts: opts,
+		srv1: s,
+	}}); err != nil {
+		return errors.New("http3: net/http does not support HTTP/3")
+	}
+	return nil
+}
+
+func (s *http3server) init() {
+	s.initOnce.Do(func() {
+		s.activeConns = make(map[*http3serverConn]struct{})
+		s.connClosed = make(chan any, 1)
+	})
+}
+
+// serve accepts incoming connections on the QUIC endpoint e,
+// and handles requests from those connections.
+func (s *http3server) serve(ctx context.Context, e *quic.Endpoint, h Handler) error {
+	s.init()
+	defer e.Close(http3canceledCtx)
+	for {
+		qconn, err := e.Accept(ctx)
+		if err != nil {
+			return err
+		}
+		go s.newServerConn(ctx, qconn, h)
+	}
+}
+
+// shutdown attempts a graceful shutdown for the server.
+func (s *http3server) shutdown(ctx context.Context) {
+	// Set a reasonable default in case ctx is nil.
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+	}
+
+	// Send GOAWAY frames to all active connections to give a chance for them
+	// to gracefully terminate.
+	s.mu.Lock()
+	for sc := range s.activeConns {
+		// TODO: Modify x/net/quic stream API so that write errors from context
+		// deadline are sticky.
+		go sc.sendGoaway()
+	}
+	s.mu.Unlock()
+
+	// Complete shutdown as soon as there are no more active connections or ctx
+	// is done, whichever comes first.
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for sc := range s.activeConns {
+			sc.abort(&http3connectionError{
+				code:    http3errH3NoError,
+				message: "server is shutting down",
+			})
+		}
+	}()
+	noMoreConns := func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.activeConns) == 0
+	}
+	for {
+		if noMoreConns() {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.connClosed:
+		}
+	}
+}
+
+func (s *http3server) registerConn(sc *http3serverConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activeConns[sc] = struct{}{}
+}
+
+func (s *http3server) unregisterConn(sc *http3serverConn) {
+	s.mu.Lock()
+	delete(s.activeConns, sc)
+	s.mu.Unlock()
+	select {
+	case s.connClosed <- struct{}{}:
+	default:
+		// Channel already full. No need to send more values since we are just
+		// using this channel as a simpler sync.Cond.
+	}
+}
+
+func (s *http3server) readHeaderTimeout() time.Duration {
+	if s.srv1 == nil || s.srv1.ReadHeaderTimeout == 0 {
+		return s.readTimeout()
+	}
+	return s.srv1.ReadHeaderTimeout
+}
+
+func (s *http3server) readTimeout() time.Duration {
+	if s.srv1 == nil {
+		return 0
+	}
+	return s.srv1.ReadTimeout
+}
+
+func (s *http3server) writeTimeout() time.Duration {
+	if s.srv1 == nil {
+		return 0
+	}
+	return s.srv1.WriteTimeout
+}
+
+// TODO: this is currently unused, enforce it.
+func (s *http3server) idleTimeout() time.Duration {
+	if s.srv1 == nil || s.srv1.IdleTimeout == 0 {
+		return s.readTimeout()
+	}
+	return s.srv1.IdleTimeout
+}
+
+type http3serverConn struct {
+	qconn   *quic.Conn
+	srv     *http3server
+	baseCtx context.Context
+	handler Handler
+
+	http3genericConn // for handleUnidirectionalStream
+	enc              http3qpackEncoder
+	dec              http3qpackDecoder
+
+	// For handling shutdown.
+	controlStream      *http3stream
+	mu                 sync.Mutex // Guards everything below.
+	maxRequestStreamID int64
+	goawaySent         bool
+}
+
+// newServerConn handles a new connection.
+// The baseCtx parameter is the base context for request handlers on this connection.
+func (s *http3server) newServerConn(baseCtx context.Context, qconn *quic.Conn, h Handler) {
+	sc := &http3serverConn{
+		qconn:   qconn,
+		srv:     s,
+		baseCtx: baseCtx,
+		handler: h,
+	}
+	s.registerConn(sc)
+	defer s.unregisterConn(sc)
+	sc.enc.init()
+
+	// Create control stream and send SETTINGS frame.
+	// TODO: Time out on creating stream.
+	var err error
+	sc.controlStream, err = http3newConnStream(context.Background(), sc.qconn, http3streamTypeControl)
+	if err != nil {
+		return
+	}
+	sc.controlStream.writeSettings()
+	sc.controlStream.Flush()
+
+	sc.acceptStreams(sc.qconn, sc)
+}
+
+func (sc *http3serverConn) handleControlStream(st *http3stream) error {
+	// "A SETTINGS frame MUST be sent as the first frame of each control stream [...]"
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.4-2
+	if err := st.readSettings(func(settingsType, settingsValue int64) error {
+		switch settingsType {
+		case http3settingsMaxFieldSectionSize:
+			_ = settingsValue // TODO
+		case http3settingsQPACKMaxTableCapacity:
+			_ = settingsValue // TODO
+		case http3settingsQPACKBlockedStreams:
+			_ = settingsValue // TODO
+		default:
+			// Unknown settings types are ignored.
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for {
+		ftype, err := st.readFrameHeader()
+		if err != nil {
+			return err
+		}
+		switch ftype {
+		case http3frameTypeCancelPush:
+			// "If a server receives a CANCEL_PUSH frame for a push ID
+			// that has not yet been mentioned by a PUSH_PROMISE frame,
+			// this MUST be treated as a connection error of type H3_ID_ERROR."
+			// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.3-8
+			return &http3connectionError{
+				code:    http3errH3IDError,
+				message: "CANCEL_PUSH for unsent push ID",
+			}
+		case http3frameTypeGoaway:
+			return http3errH3NoError
+		default:
+			// Unknown frames are ignored.
+			if err := st.discardUnknownFrame(ftype); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (sc *http3serverConn) handleEncoderStream(*http3stream) error {
+	// TODO
+	return nil
+}
+
+func (sc *http3serverConn) handleDecoderStream(*http3stream) error {
+	// TODO
+	return nil
+}
+
+func (sc *http3serverConn) handlePushStream(*http3stream) error {
+	// "[...] if a server receives a client-initiated push stream,
+	// this MUST be treated as a connection error of type H3_STREAM_CREATION_ERROR."
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-6.2.2-3
+	return &http3connectionError{
+		code:    http3errH3StreamCreationError,
+		message: "client created push stream",
+	}
+}
+
+// hasDisallowedConnectionHeader reports whether h contains connection headers
+// that are not allowed in HTTP/3:
+//
+// "An endpoint MUST NOT generate an HTTP/3 field section containing
+// connection-specific fields; any message containing connection-specific
+// fields MUST be treated as malformed."
+//
+// "The only exception to this is the TE header field, which MAY be present in
+// an HTTP/3 request header; when it is, it MUST NOT contain any value other
+// than "trailers"."
+func http3hasDisallowedConnectionHeader(h Header) bool {
+	neverAllowed := []string{
+		"Connection",
+		"Keep-Alive",
+		"Proxy-Connection",
+		"Transfer-Encoding",
+		"Upgrade",
+	}
+	for _, k := range neverAllowed {
+		if _, ok := h[k]; ok {
+			return true
+		}
+	}
+	if te, ok := h["Te"]; ok && (len(te) != 1 || te[0] != "trailers") {
+		return true
+	}
+	return false
+}
+
+type http3pseudoHeader struct {
+	method    string
+	scheme    string
+	path      string
+	authority string
+}
+
+func (sc *http3serverConn) parseHeader(st *http3stream) (Header, http3pseudoHeader, error) {
+	ftype, err := st.readFrameHeader()
+	if err != nil {
+		return nil, http3pseudoHeader{}, err
+	}
+	if ftype != http3frameTypeHeaders {
+		return nil, http3pseudoHeader{}, &http3streamError{http3errH3MessageError, "received other frames when expecting HEADERS"}
+	}
+	header := make(Header)
+	var pHeader http3pseudoHeader
+	var dec http3qpackDecoder
+	var hasMethod, hasScheme, hasPath, hasAuthority bool
+	if err := dec.decode(st, func(_ http3indexType, name, value string) error {
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return &http3streamError{http3errH3MessageError, "invalid field value"}
+		}
+		switch name {
+		case ":method":
+			if hasMethod {
+				return &http3streamError{http3errH3MessageError, "duplicate :method"}
+			}
+			hasMethod = true
+			pHeader.method = value
+		case ":scheme":
+			if hasScheme {
+				return &http3streamError{http3errH3MessageError, "duplicate :scheme"}
+			}
+			hasScheme = true
+			pHeader.scheme = value
+		case ":path":
+			if hasPath {
+				return &http3streamError{http3errH3MessageError, "duplicate :path"}
+			}
+			hasPath = true
+			pHeader.path = value
+		case ":authority":
+			if hasAuthority {
+				return &http3streamError{http3errH3MessageError, "duplicate :authority"}
+			}
+			hasAuthority = true
+			pHeader.authority = value
+		default:
+			if !http3validWireHeaderFieldName(name) {
+				return &http3streamError{http3errH3MessageError, "invalid field name"}
+			}
+			header.Add(name, value)
+		}
+		return nil
+	}); err != nil {
+		return nil, http3pseudoHeader{}, err
+	}
+	if err := st.endFrame(); err != nil {
+		return nil, http3pseudoHeader{}, err
+	}
+	if http3hasDisallowedConnectionHeader(header) {
+		return nil, http3pseudoHeader{}, &http3streamError{http3errH3MessageError, "invalid connection-related header"}
+	}
+
+	// "All HTTP/3 requests MUST include exactly one value for the :method,
+	// :scheme, and :path pseudo-header fields, unless the request is a CONNECT
+	// request"
+	//
+	// "A CONNECT request MUST be constructed as follows:
+	// - The :method pseudo-header field is set to "CONNECT"
+	// - The :scheme and :path pseudo-header fields are omitted
+	// - The :authority pseudo-header field contains the host and port to connect to"
+	if !hasMethod {
+		return nil, http3pseudoHeader{}, &http3streamError{http3errH3MessageError, "missing :method"}
+	}
+	if pHeader.method != "CONNECT" && (!hasScheme || !hasPath) {
+		return nil, http3pseudoHeader{}, &http3streamError{http3errH3MessageError, "missing :scheme or :path for non-CONNECT requests"}
+	}
+	if pHeader.method == "CONNECT" && (hasScheme || hasPath || !hasAuthority) {
+		return nil, http3pseudoHeader{}, &http3streamError{
+			http3errH3MessageError, "CONNECT request must only have :method and :authority pseudo-headers",
+		}
+	}
+	return header, pHeader, nil
+}
+
+func (sc *http3serverConn) sendGoaway() {
+	sc.mu.Lock()
+	if sc.goawaySent || sc.controlStream == nil {
+		sc.mu.Unlock()
+		return
+	}
+	sc.goawaySent = true
+	sc.mu.Unlock()
+
+	// No lock in this section in case writing to stream blocks. This is safe
+	// since sc.maxRequestStreamID is only updated when sc.goawaySent is false.
+	sc.controlStream.writeVarint(int64(http3frameTypeGoaway))
+	sc.controlStream.writeVarint(int64(http3sizeVarint(uint64(sc.maxRequestStreamID))))
+	sc.controlStream.writeVarint(sc.maxRequestStreamID)
+	sc.controlStream.Flush()
+}
+
+// requestShouldGoAway returns true if st has a stream ID that is equal or
+// greater than the ID we have sent in a GOAWAY frame, if any.
+func (sc *http3serverConn) requestShouldGoaway(st *http3stream) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.goawaySent {
+		return st.stream.ID() >= sc.maxRequestStreamID
+	} else {
+		sc.maxRequestStreamID = max(sc.maxRequestStreamID, st.stream.ID())
+		return false
+	}
+}
+
+func (sc *http3serverConn) handleRequestStream(st *http3stream) error {
+	if sc.requestShouldGoaway(st) {
+		return &http3streamError{
+			code:    http3errH3RequestRejected,
+			message: "GOAWAY request with equal or lower ID than the stream has been sent",
+		}
+	}
+
+	readStartTime := time.Now()
+	if t := sc.srv.readHeaderTimeout(); t > 0 {
+		st.readDeadline.set(readStartTime.Add(t))
+	}
+	header, pHeader, err := sc.parseHeader(st)
+	if err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return &http3streamError{
+				code:    http3errH3RequestRejected,
+				message: "exceeded deadline while parsing header",
+			}
+		}
+		return err
+	}
+
+	if t := sc.srv.readTimeout(); t > 0 {
+		st.readDeadline.set(readStartTime.Add(t))
+	} else {
+		st.readDeadline.set(time.Time{})
+	}
+	reqInfo := httpcommon.NewServerRequest(httpcommon.ServerRequestParam{
+		Method:    pHeader.method,
+		Scheme:    pHeader.scheme,
+		Authority: pHeader.authority,
+		Path:      pHeader.path,
+		Header:    header,
+	})
+	if reqInfo.InvalidReason != "" {
+		return &http3streamError{
+			code:    http3errH3MessageError,
+			message: reqInfo.InvalidReason,
+		}
+	}
+
+	contentLength := int64(-1)
+	if n, err := strconv.Atoi(header.Get("Content-Length")); err == nil {
+		contentLength = int64(n)
+	}
+
+	req := (&Request{
+		Proto:         "HTTP/3.0",
+		Method:        pHeader.method,
+		Host:          pHeader.authority,
+		URL:           reqInfo.URL,
+		RequestURI:    reqInfo.RequestURI,
+		Trailer:       reqInfo.Trailer,
+		ProtoMajor:    3,
+		RemoteAddr:    sc.qconn.RemoteAddr().String(),
+		Header:        header,
+		ContentLength: contentLength,
+	}).WithContext(sc.baseCtx)
+
+	rw := &http3responseWriter{
+		st:             st,
+		headers:        make(Header),
+		trailer:        make(Header),
+		bb:             make(http3bodyBuffer, 0, http3defaultBodyBufferCap),
+		cannotHaveBody: req.Method == "HEAD",
+		bw: &http3bodyWriter{
+			st:     st,
+			remain: -1,
+			flush:  false,
+			name:   "response",
+			enc:    &sc.enc,
+		},
+	}
+
+	if contentLength != 0 || len(reqInfo.Trailer) != 0 {
+		req.Body = &http3serverRequestReader{
+			rw: rw,
+			br: http3bodyReader{
+				st:            st,
+				remain:        contentLength,
+				trailer:       reqInfo.Trailer,
+				filterTrailer: true,
+			},
+			needsContinue: reqInfo.NeedsContinue,
+		}
+		defer req.Body.Close()
+	} else {
+		req.Body = NoBody
+	}
+
+	// TODO: handle panic coming from the HTTP handler.
+	if t := sc.srv.writeTimeout(); t > 0 {
+		st.writeDeadline.set(time.Now().Add(t))
+	}
+	sc.handler.ServeHTTP(rw, req)
+	return rw.close()
+}
+
+// abort closes the connection with an error.
+func (sc *http3serverConn) abort(err error) {
+	if e, ok := err.(*http3connectionError); ok {
+		sc.qconn.Abort(&quic.ApplicationError{
+			Code:   uint64(e.code),
+			Reason: e.message,
+		})
+	} else {
+		sc.qconn.Abort(err)
+	}
+}
+
+// responseCanHaveBody reports whether a given response status code permits a
+// body. See RFC 7230, section 3.3.
+func http3responseCanHaveBody(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == 204:
+		return false
+	case status == 304:
+		return false
+	}
+	return true
+}
+
+// trailerPrefix is a magic prefix for [responseWriter.Header] map keys that,
+// if present, signals that the map entry is actually for the response
+// trailers, and not the response headers. See [net/http.TrailerPrefix] for
+// details.
+const http3trailerPrefix = "Trailer:"
+
+type http3responseWriter struct {
+	st             *http3stream
+	bw             *http3bodyWriter
+	mu             sync.Mutex
+	headers        Header
+	snapHeaders    Header // Snapshot of headers at WriteHeader time
+	trailer        Header
+	bb             http3bodyBuffer
+	wroteHeader    bool // Non-1xx header has been (logically) written.
+	statusCode     int  // Non-1xx status of the response that will be sent in HEADERS frame. Zero means none has been set.
+	sent100        bool // Status 100 has been sent by the server.
+	cannotHaveBody bool // Response should not have a body (e.g. response to a HEAD request).
+	bodyLenLeft    int  // How much of the content body is left to be sent, set via "Content-Length" header. -1 if unknown.
+}
+
+func (rw *http3responseWriter) Header() Header {
+	return rw.headers
+}
+
+// prepareTrailerForWriteLocked populates any pre-declared trailer header with
+// its value, and passes it to bodyWriter so it can be written after body EOF.
+// Caller must hold rw.mu.
+func (rw *http3responseWriter) prepareTrailerForWriteLocked() {
+	for name := range rw.trailer {
+		if val, ok := rw.headers[name]; ok {
+			rw.trailer[name] = val
+		} else {
+			delete(rw.trailer, name)
+		}
+	}
+	for name, vals := range rw.headers {
+		if name, found := strings.CutPrefix(name, http3trailerPrefix); found {
+			name = textproto.CanonicalMIMEHeaderKey(textproto.TrimString(name))
+			rw.trailer[name] = vals
+		}
+	}
+	if len(rw.trailer) > 0 {
+		rw.bw.trailer = rw.trailer
+	}
+}
+
+// writeHeaderLockedOnce writes the final response header. If rw.wroteHeader is
+// true, calling this method is a no-op. Sending informational status headers
+// should be done using writeInfoHeaderLocked, rather than this method.
+// Caller must hold rw.mu.
+func (rw *http3responseWriter) writeHeaderLockedOnce() {
+	if rw.wroteHeader {
+		return
+	}
+	if !http3responseCanHaveBody(rw.statusCode) {
+		rw.cannotHaveBody = true
+	}
+	// If there is any Trailer declared, save them so we know which trailers
+	// have been pre-declared. Also, write back the extracted value, which is
+	// canonicalized, for consistency.
+	if _, ok := rw.snapHeaders["Trailer"]; ok {
+		http3extractTrailerFromHeader(rw.snapHeaders, rw.trailer)
+		rw.snapHeaders.Set("Trailer", strings.Join(slices.Sorted(maps.Keys(rw.trailer)), ", "))
+	}
+
+	rw.bb.inferHeader(rw.snapHeaders, rw.statusCode)
+	encHeaders := rw.bw.enc.encode(func(f func(itype http3indexType, name, value string)) {
+		f(http3mayIndex, ":status", strconv.Itoa(rw.statusCode))
+		for name, values := range rw.snapHeaders {
+			if !httpguts.ValidHeaderFieldName(name) {
+				continue
+			}
+			for _, val := range values {
+				if !httpguts.ValidHeaderFieldValue(val) {
+					continue
+				}
+				// Issue #71374: Consider supporting never-indexed fields.
+				f(http3mayIndex, name, val)
+			}
+		}
+	})
+
+	rw.st.writeVarint(int64(http3frameTypeHeaders))
+	rw.st.writeVarint(int64(len(encHeaders)))
+	rw.st.Write(encHeaders)
+	rw.wroteHeader = true
+}
+
+// writeHeaderLocked writes informational status headers (i.e. status 1XX).
+// If a non-informational status header has been written via
+// writeHeaderLockedOnce, this method is a no-op.
+// Caller must hold rw.mu.
+func (rw *http3responseWriter) writeHeaderLocked(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	if statusCode == 100 {
+		if rw.sent100 {
+			return
+		}
+		rw.sent100 = true
+	}
+	encHeaders := rw.bw.enc.encode(func(f func(itype http3indexType, name, value string)) {
+		f(http3mayIndex, ":status", strconv.Itoa(statusCode))
+		for name, values := range rw.headers {
+			if name == "Content-Length" || name == "Transfer-Encoding" {
+				continue
+			}
+			if !httpguts.ValidHeaderFieldName(name) {
+				continue
+			}
+			for _, val := range values {
+				if !httpguts.ValidHeaderFieldValue(val) {
+					continue
+				}
+				// Issue #71374: Consider supporting never-indexed fields.
+				f(http3mayIndex, name, val)
+			}
+		}
+	})
+	rw.st.writeVarint(int64(http3frameTypeHeaders))
+	rw.st.writeVarint(int64(len(encHeaders)))
+	rw.st.Write(encHeaders)
+}
+
+func http3isInfoStatus(status int) bool {
+	return status >= 100 && status < 200
+}
+
+// checkWriteHeaderCode is a copy of net/http's checkWriteHeaderCode.
+func http3checkWriteHeaderCode(code int) {
+	// Issue 22880: require valid WriteHeader status codes.
+	// For now we only enforce that it's three digits.
+	// In the future we might block things over 599 (600 and above aren't defined
+	// at http://httpwg.org/specs/rfc7231.html#status.codes).
+	// But for now any three digits.
+	//
+	// We used to send "HTTP/1.1 000 0" on the wire in responses but there's
+	// no equivalent bogus thing we can realistically send in HTTP/3,
+	// so we'll consistently panic instead and help people find their bugs
+	// early. (We can't return an error from WriteHeader even if we wanted to.)
+	if code < 100 || code > 999 {
+		panic(fmt.Sprintf("invalid WriteHeader code %v", code))
+	}
+}
+
+func (rw *http3responseWriter) WriteHeader(statusCode int) {
+	// TODO: handle sending informational status headers (e.g. 103).
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.statusCode != 0 {
+		return
+	}
+	http3checkWriteHeaderCode(statusCode)
+
+	// Informational headers can be sent multiple times, and should be flushed
+	// immediately.
+	if http3isInfoStatus(statusCode) {
+		rw.writeHeaderLocked(statusCode)
+		rw.st.Flush()
+		return
+	}
+
+	// Non-informational headers should only be set once, and should be
+	// buffered.
+	rw.statusCode = statusCode
+	rw.snapHeaders = rw.headers.Clone()
+	if n, err := strconv.Atoi(rw.Header().Get("Content-Length")); err == nil {
+		rw.bodyLenLeft = n
+	} else {
+		rw.bodyLenLeft = -1 // Unknown.
+	}
+}
+
+// trimWriteLocked trims a byte slice, b, such that the length of b will not
+// exceed rw.bodyLenLeft. This method will update rw.bodyLenLeft when trimming
+// b, and will also return whether b was trimmed or not.
+// Caller must hold rw.mu.
+func (rw *http3responseWriter) trimWriteLocked(b []byte) ([]byte, bool) {
+	if rw.bodyLenLeft < 0 {
+		return b, false
+	}
+	n := min(len(b), rw.bodyLenLeft)
+	rw.bodyLenLeft -= n
+	return b[:n], n != len(b)
+}
+
+func (rw *http3responseWriter) Write(b []byte) (n int, err error) {
+	// Calling Write implicitly calls WriteHeader(200) if WriteHeader has not
+	// been called before.
+	rw.WriteHeader(StatusOK)
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.statusCode == StatusNotModified {
+		return 0, ErrBodyNotAllowed
+	}
+
+	b, trimmed := rw.trimWriteLocked(b)
+	if trimmed {
+		defer func() {
+			err = ErrContentLength
+		}()
+	}
+
+	// If b fits entirely in our body buffer, save it to the buffer and return
+	// early so we can coalesce small writes.
+	// As a special case, we always want to save b to the buffer even when b is
+	// big if we had yet to write our header, so we can infer headers like
+	// "Content-Type" with as much information as possible.
+	initialBLen := len(b)
+	initialBufLen := len(rw.bb)
+	if !rw.wroteHeader || len(b) <= cap(rw.bb)-len(rw.bb) {
+		b = rw.bb.write(b)
+		if len(b) == 0 {
+			return initialBLen, nil
+		}
+	}
+
+	// Reaching this point means that our buffer has been sufficiently filled.
+	// Therefore, we now want to:
+	// 1. Infer and write response headers based on our body buffer, if not
+	// done yet.
+	// 2. Write our body buffer and the rest of b (if any).
+	// 3. Reset the current body buffer so it can be used again.
+	rw.writeHeaderLockedOnce()
+	if rw.cannotHaveBody {
+		return initialBLen, nil
+	}
+	if n, err := rw.bw.write(rw.bb, b); err != nil {
+		return max(0, n-initialBufLen), err
+	}
+	rw.bb.discard()
+	return initialBLen, nil
+}
+
+func (rw *http3responseWriter) SetReadDeadline(deadline time.Time) error {
+	rw.st.readDeadline.set(deadline)
+	return nil
+}
+
+func (rw *http3responseWriter) SetWriteDeadline(deadline time.Time) error {
+	rw.st.writeDeadline.set(deadline)
+	return nil
+}
+
+func (rw *http3responseWriter) EnableFullDuplex() error {
+	return nil
+}
+
+func (rw *http3responseWriter) Flush() { rw.FlushError() }
+
+func (rw *http3responseWriter) FlushError() error {
+	// Calling Flush implicitly calls WriteHeader(200) if WriteHeader has not
+	// been called before.
+	rw.WriteHeader(StatusOK)
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.writeHeaderLockedOnce()
+	if !rw.cannotHaveBody {
+		_, err := rw.bw.Write(rw.bb)
+		rw.bb.discard()
+		if err != nil {
+			return err
+		}
+	}
+	return rw.st.Flush()
+}
+
+func (rw *http3responseWriter) close() error {
+	if errors.Is(rw.st.writeDeadline.err(), os.ErrDeadlineExceeded) {
+		return &http3streamError{
+			code:    http3errH3RequestCancelled,
+			message: "exceeded deadline while writing response",
+		}
+	}
+
+	retErr := rw.FlushError()
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.prepareTrailerForWriteLocked()
+	if err := rw.bw.Close(); retErr == nil {
+		retErr = err
+	}
+	if errors.Is(retErr, os.ErrDeadlineExceeded) {
+		return &http3streamError{
+			code:    http3errH3RequestCancelled,
+			message: retErr.Error(),
+		}
+	}
+	return retErr
+}
+
+// defaultBodyBufferCap is the default number of bytes of body that we are
+// willing to save in a buffer for the sake of inferring headers and coalescing
+// small writes. 512 was chosen to be consistent with how much
+// http.DetectContentType is willing to read.
+const http3defaultBodyBufferCap = 512
+
+// bodyBuffer is a buffer used to store body content of a response.
+type http3bodyBuffer []byte
+
+// write writes b to the buffer. It returns a new slice of b, which contains
+// any remaining data that could not be written to the buffer, if any.
+func (bb *http3bodyBuffer) write(b []byte) []byte {
+	n := min(len(b), cap(*bb)-len(*bb))
+	*bb = append(*bb, b[:n]...)
+	return b[n:]
+}
+
+// discard resets the buffer so it can be used again.
+func (bb *http3bodyBuffer) discard() {
+	*bb = (*bb)[:0]
+}
+
+// inferHeader populates h with the header values that we can infer from our
+// current buffer content, if not already explicitly set. This method should be
+// called only once with as much body content as possible in the buffer, before
+// a HEADERS frame is sent, and before discard has been called. Doing so
+// properly is the responsibility of the caller.
+func (bb *http3bodyBuffer) inferHeader(h Header, status int) {
+	if _, ok := h["Date"]; !ok {
+		h.Set("Date", time.Now().UTC().Format(TimeFormat))
+	}
+	// If the Content-Encoding is non-blank, we shouldn't
+	// sniff the body. See Issue golang.org/issue/31753.
+	_, hasCE := h["Content-Encoding"]
+	_, hasCT := h["Content-Type"]
+	if !hasCE && !hasCT && http3responseCanHaveBody(status) && len(*bb) > 0 {
+		h.Set("Content-Type", DetectContentType(*bb))
+	}
+	// We can technically infer Content-Length too here, as long as the entire
+	// response body fits within hi.buf and does not require flushing. However,
+	// we have chosen not to do so for now as Content-Length is not very
+	// important for HTTP/3, and such inconsistent behavior might be confusing.
+}
+
+// serverRequestReader wraps around bodyReader, allowing Read and Close calls
+// done from within a server handler to coordinate correctly with the
+// responseWriter; for example, sending status 100 on Read when appropriate.
+type http3serverRequestReader struct {
+	rw            *http3responseWriter
+	br            http3bodyReader
+	needsContinue bool
+}
+
+// maybeSendContinue attempts to send a 100 Continue status code. It
+// ensures that status 100 will only be sent once and when appropriate. If a
+// non-1xx header has been set before 100 was ever set, it also ensures that
+// all subsequent Read will fail.
+func (srr *http3serverRequestReader) maybeSendContinue() {
+	if !srr.needsContinue {
+		return
+	}
+	srr.rw.mu.Lock()
+	defer srr.rw.mu.Unlock()
+	if srr.rw.sent100 {
+		return
+	}
+	if srr.rw.statusCode != 0 {
+		srr.br.Close()
+		return
+	}
+	srr.rw.writeHeaderLocked(100)
+	srr.rw.st.Flush()
+}
+
+func (srr *http3serverRequestReader) Read(p []byte) (int, error) {
+	srr.maybeSendContinue()
+	return srr.br.Read(p)
+}
+
+func (srr *http3serverRequestReader) Close() error {
+	return srr.br.Close()
+}
+
+const (
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.4.1
+	http3settingsMaxFieldSectionSize = 0x06
+
+	// https://www.rfc-editor.org/rfc/rfc9204.html#section-5
+	http3settingsQPACKMaxTableCapacity = 0x01
+	http3settingsQPACKBlockedStreams   = 0x07
+)
+
+// writeSettings writes a complete SETTINGS frame.
+// Its parameter is a list of alternating setting types and values.
+func (st *http3stream) writeSettings(settings ...int64) {
+	var size int64
+	for _, s := range settings {
+		// Settings values that don't fit in a QUIC varint ([0,2^62)) will panic here.
+		size += int64(http3sizeVarint(uint64(s)))
+	}
+	st.writeVarint(int64(http3frameTypeSettings))
+	st.writeVarint(size)
+	for _, s := range settings {
+		st.writeVarint(s)
+	}
+}
+
+// readSettings reads a complete SETTINGS frame, including the frame header.
+func (st *http3stream) readSettings(f func(settingType, value int64) error) error {
+	frameType, err := st.readFrameHeader()
+	if err != nil || frameType != http3frameTypeSettings {
+		return &http3connectionError{
+			code:    http3errH3MissingSettings,
+			message: "settings not sent on control stream",
+		}
+	}
+	for st.lim > 0 {
+		settingsType, err := st.readVarint()
+		if err != nil {
+			return err
+		}
+		settingsValue, err := st.readVarint()
+		if err != nil {
+			return err
+		}
+
+		// Use of HTTP/2 settings where there is no corresponding HTTP/3 setting
+		// is an error.
+		// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.4.1-5
+		switch settingsType {
+		case 0x02, 0x03, 0x04, 0x05:
+			return &http3connectionError{
+				code:    http3errH3SettingsError,
+				message: "use of reserved setting",
+			}
+		}
+
+		if err := f(settingsType, settingsValue); err != nil {
+			return err
+		}
+	}
+	return st.endFrame()
+}
+
+// A stream wraps a QUIC stream, providing methods to read/write various values.
+type http3stream struct {
+	stream *quic.Stream
+
+	// lim is the current read limit.
+	// Reading a frame header sets the limit to the end of the frame.
+	// Reading past the limit or reading less than the limit and ending the frame
+	// results in an error.
+	// -1 indicates no limit.
+	lim int64
+
+	readDeadline  http3deadline
+	writeDeadline http3deadline
+}
+
+// newConnStream creates a new stream on a connection.
+// It writes the stream header for unidirectional streams.
+//
+// The stream returned by newStream is not flushed,
+// and will not be sent to the peer until the caller calls
+// Flush or writes enough data to the stream.
+func http3newConnStream(ctx context.Context, qconn *quic.Conn, stype http3streamType) (*http3stream, error) {
+	var qs *quic.Stream
+	var err error
+	if stype == http3streamTypeRequest {
+		// Request streams are bidirectional.
+		qs, err = qconn.NewStream(ctx)
+	} else {
+		// All other streams are unidirectional.
+		qs, err = qconn.NewSendOnlyStream(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	st := http3newStream(qs)
+	if stype != http3streamTypeRequest {
+		// Unidirectional stream header.
+		st.writeVarint(int64(stype))
+	}
+	return st, err
+}
+
+func http3newStream(qs *quic.Stream) *http3stream {
+	readCtx, readCancel := context.WithCancelCause(context.Background())
+	writeCtx, writeCancel := context.WithCancelCause(context.Background())
+	st := &http3stream{
+		stream: qs,
+		lim:    -1, // no limit
+		readDeadline: http3deadline{
+			ctx:    readCtx,
+			cancel: readCancel,
+		},
+		writeDeadline: http3deadline{
+			ctx:    writeCtx,
+			cancel: writeCancel,
+		},
+	}
+	qs.SetReadContext(readCtx)
+	qs.SetWriteContext(writeCtx)
+	return st
+}
+
+func (st *http3stream) Close() error {
+	st.readDeadline.stop()
+	st.writeDeadline.stop()
+	return st.stream.Close()
+}
+
+func (st *http3stream) CloseRead() {
+	st.readDeadline.stop()
+	st.stream.CloseRead()
+}
+
+func (st *http3stream) CloseWrite() {
+	st.writeDeadline.stop()
+	st.stream.CloseWrite()
+}
+
+func (st *http3stream) Reset(code uint64) {
+	st.readDeadline.stop()
+	st.writeDeadline.stop()
+	st.stream.Reset(code)
+}
+
+// deadline manages ctx, and cancels it when timer expires, with
+// [os.ErrDeadlineExceeded] as the cause. If the deadline is manually stopped
+// before timer expires, the context will be canceled with [context.Canceled]
+// as the cause. Once a deadline is exceeded, its timer can no longer be
+// extended.
+// Practically, this lets the http3 package support time-based deadlines by
+// utilizing the quic package's support for context-based deadlines.
+type http3deadline struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mu    sync.Mutex // Guards below.
+	timer *time.Timer
+}
+
+// stopTimerLocked stops the deadline timer and sets it to nil.
+// The caller must hold d.mu.
+func (d *http3deadline) stopTimerLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// stop stops the deadline timer and cancels the context with
+// [context.Canceled] as the cause.
+func (d *http3deadline) stop() {
+	d.mu.Lock()
+	d.stopTimerLocked()
+	d.mu.Unlock()
+	d.cancel(context.Canceled)
+}
+
+// err returns the deadline's context cancelation cause, if any.
+func (d *http3deadline) err() error {
+	return context.Cause(d.ctx)
+}
+
+// errOf returns the deadline's context cancelation cause if the given err is
+// non-nil. This can be used to check whether an error value returned by I/O
+// operations at the QUIC layer is non-nil because the deadline has expired.
+func (d *http3deadline) errOf(err error) error {
+	if dErr := d.err(); err != nil && dErr != nil {
+		return dErr
+	}
+	return err
+}
+
+// set configures a new deadline using the given deadlineTime.
+// Once deadline is exceeded, it remains in the expired (sticky) state, and
+// subsequent attempts to extend or reset the deadline are ignored.
+func (d *http3deadline) set(deadlineTime time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.ctx.Err() != nil { // Already expired, sticky error.
+		return
+	}
+	if deadlineTime.IsZero() {
+		d.stopTimerLocked()
+		return
+	}
+	dur := time.Until(deadlineTime)
+	if dur <= 0 {
+		d.stopTimerLocked()
+		d.cancel(os.ErrDeadlineExceeded)
+		return
+	}
+	if d.timer == nil {
+		d.timer = time.AfterFunc(dur, func() {
+			d.cancel(os.ErrDeadlineExceeded)
+		})
+	} else {
+		d.timer.Reset(dur)
+	}
+}
+
+// readFrameHeader reads the type and length fields of an HTTP/3 frame.
+// It sets the read limit to the end of the frame.
+//
+// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.1
+func (st *http3stream) readFrameHeader() (ftype http3frameType, err error) {
+	if st.lim >= 0 {
+		// We shouldn't call readFrameHeader before ending the previous frame.
+		return 0, http3errH3FrameError
+	}
+	ftype, err = http3readVarint[http3frameType](st)
+	if err != nil {
+		return 0, err
+	}
+	size, err := st.readVarint()
+	if err != nil {
+		return 0, err
+	}
+	st.lim = size
+	return ftype, nil
+}
+
+// endFrame is called after reading a frame to reset the read limit.
+// It returns an error if the entire contents of a frame have not been read.
+func (st *http3stream) endFrame() error {
+	if st.lim != 0 {
+		return &http3connectionError{
+			code:    http3errH3FrameError,
+			message: "invalid HTTP/3 frame",
+		}
+	}
+	st.lim = -1
+	return nil
+}
+
+// readFrameData returns the remaining data in the current frame.
+func (st *http3stream) readFrameData() ([]byte, error) {
+	if st.lim < 0 {
+		return nil, http3errH3FrameError
+	}
+	// TODO: Pool buffers to avoid allocation here.
+	b := make([]byte, st.lim)
+	_, err := io.ReadFull(st, b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ReadByte reads one byte from the stream.
+func (st *http3stream) ReadByte() (b byte, err error) {
+	// Check the deadline before doing I/O operations on the QUIC layer. We do
+	// this because the QUIC layer implements a fast path for I/O operations,
+	// allowing Read & Write to succeed depending on the state of buffer, even
+	// if its context has been canceled. By always checking the deadline here,
+	// we make it so that I/O operations fail as soon as its relevant deadline
+	// has been exceeded.
+	if err := st.readDeadline.err(); err != nil {
+		return 0, err
+	}
+	if err := st.recordBytesRead(1); err != nil {
+		return 0, err
+	}
+	b, err = st.stream.ReadByte()
+	if err == io.EOF && st.lim >= 0 {
+		return 0, http3errH3FrameError
+	}
+	return b, st.readDeadline.errOf(err)
+}
+
+// Read reads from the stream.
+func (st *http3stream) Read(b []byte) (int, error) {
+	// Check the deadline before doing I/O operations on the QUIC layer. We do
+	// this because the QUIC layer implements a fast path for I/O operations,
+	// allowing Read & Write to succeed depending on the state of buffer, even
+	// if its context has been canceled. By always checking the deadline here,
+	// we make it so that I/O operations fail as soon as its relevant deadline
+	// has been exceeded.
+	if err := st.readDeadline.err(); err != nil {
+		return 0, err
+	}
+	n, err := st.stream.Read(b)
+	if e2 := st.recordBytesRead(n); e2 != nil {
+		return 0, e2
+	}
+	if err == io.EOF {
+		if st.lim == 0 {
+			// EOF at end of frame, ignore.
+			return n, nil
+		} else if st.lim > 0 {
+			// EOF inside frame, error.
+			return 0, http3errH3FrameError
+		} else {
+			// EOF outside of frame, surface to caller.
+			return n, io.EOF
+		}
+	}
+	return n, st.readDeadline.errOf(err)
+}
+
+// discardUnknownFrame discards an unknown frame.
+//
+// HTTP/3 requires that unknown frames be ignored on all streams.
+// However, a known frame appearing in an unexpected place is a fatal error,
+// so this returns an error if the frame is one we know.
+func (st *http3stream) discardUnknownFrame(ftype http3frameType) error {
+	switch ftype {
+	case http3frameTypeData,
+		http3frameTypeHeaders,
+		http3frameTypeCancelPush,
+		http3frameTypeSettings,
+		http3frameTypePushPromise,
+		http3frameTypeGoaway,
+		http3frameTypeMaxPushID:
+		return &http3connectionError{
+			code:    http3errH3FrameUnexpected,
+			message: "unexpected " + ftype.String() + " frame",
+		}
+	}
+	return st.discardFrame()
+}
+
+// discardFrame discards any remaining data in the current frame and resets the read limit.
+func (st *http3stream) discardFrame() error {
+	// TODO: Consider adding a *quic.Stream method to discard some amount of data.
+	for range st.lim {
+		_, err := st.ReadByte()
+		if err != nil {
+			return &http3streamError{http3errH3FrameError, err.Error()}
+		}
+	}
+	st.lim = -1
+	return nil
+}
+
+// Write writes to the stream.
+func (st *http3stream) Write(b []byte) (int, error) {
+	// Check the deadline before doing I/O operations on the QUIC layer. We do
+	// this because the QUIC layer implements a fast path for I/O operations,
+	// allowing Read & Write to succeed depending on the state of buffer, even
+	// if its context has been canceled. By always checking the deadline here,
+	// we make it so that I/O operations fail as soon as its relevant deadline
+	// has been exceeded.
+	if err := st.writeDeadline.err(); err != nil {
+		return 0, err
+	}
+	n, err := st.stream.Write(b)
+	return n, st.writeDeadline.errOf(err)
+}
+
+// Flush commits data written to the stream.
+func (st *http3stream) Flush() error {
+	// Check the deadline before doing I/O operations on the QUIC layer. We do
+	// this because the QUIC layer implements a fast path for I/O operations,
+	// allowing Read & Write to succeed depending on the state of buffer, even
+	// if its context has been canceled. By always checking the deadline here,
+	// we make it so that I/O operations fail as soon as its relevant deadline
+	// has been exceeded.
+	if err := st.writeDeadline.err(); err != nil {
+		return err
+	}
+	return st.writeDeadline.errOf(st.stream.Flush())
+}
+
+// WriteByte writes one byte to the stream.
+func (st *http3stream) WriteByte(c byte) error {
+	// Check the deadline before doing I/O operations on the QUIC layer. We do
+	// this because the QUIC layer implements a fast path for I/O operations,
+	// allowing Read & Write to succeed depending on the state of buffer, even
+	// if its context has been canceled. By always checking the deadline here,
+	// we make it so that I/O operations fail as soon as its relevant deadline
+	// has been exceeded.
+	if err := st.writeDeadline.err(); err != nil {
+		return err
+	}
+	return st.writeDeadline.errOf(st.stream.WriteByte(c))
+}
+
+// readVarint reads a QUIC variable-length integer from the stream.
+func (st *http3stream) readVarint() (v int64, err error) {
+	b, err := st.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	v = int64(b & 0x3f)
+	n := 1 << (b >> 6)
+	for i := 1; i < n; i++ {
+		b, err := st.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				return 0, http3errH3FrameError
+			}
+			return 0, err
+		}
+		v = (v << 8) | int64(b)
+	}
+	return v, nil
+}
+
+// readVarint reads a varint of a particular type.
+func http3readVarint[T ~int64 | ~uint64](st *http3stream) (T, error) {
+	v, err := st.readVarint()
+	return T(v), err
+}
+
+// writeVarint writes a QUIC variable-length integer to the stream.
+func (st *http3stream) writeVarint(v int64) {
+	switch {
+	case v <= (1<<6)-1:
+		st.WriteByte(byte(v))
+	case v <= (1<<14)-1:
+		st.WriteByte((1 << 6) | byte(v>>8))
+		st.WriteByte(byte(v))
+	case v <= (1<<30)-1:
+		st.WriteByte((2 << 6) | byte(v>>24))
+		st.WriteByte(byte(v >> 16))
+		st.WriteByte(byte(v >> 8))
+		st.WriteByte(byte(v))
+	case v <= (1<<62)-1:
+		st.WriteByte((3 << 6) | byte(v>>56))
+		st.WriteByte(byte(v >> 48))
+		st.WriteByte(byte(v >> 40))
+		st.WriteByte(byte(v >> 32))
+		st.WriteByte(byte(v >> 24))
+		st.WriteByte(byte(v >> 16))
+		st.WriteByte(byte(v >> 8))
+		st.WriteByte(byte(v))
+	default:
+		panic("varint too large")
+	}
+}
+
+// recordBytesRead records that n bytes have been read.
+// It returns an error if the read passes the current limit.
+func (st *http3stream) recordBytesRead(n int) error {
+	if st.lim < 0 {
+		return nil
+	}
+	st.lim -= int64(n)
+	if st.lim < 0 {
+		st.stream = nil // panic if we try to read again
+		return &http3connectionError{
+			code:    http3errH3FrameError,
+			message: "invalid HTTP/3 frame",
+		}
+	}
+	return nil
+}
+
+// A transport is an HTTP/3 transport.
+//
+// It does not manage a pool of connections,
+// and therefore does not implement net/http.RoundTripper.
+//
+// TODO: Provide a way to register an HTTP/3 transport with a net/http.transport's
+// connection pool.
+type http3transport struct {
+	tr1  *Transport
+	opts http3TransportOpts
+
+	mu sync.Mutex // Guards fields below.
+	// endpoint is the QUIC endpoint used by connections created by the
+	// transport. If CloseIdleConnections is called when activeConns is empty,
+	// endpoint will be unset. If unset, endpoint will be initialized by any
+	// call to dial.
+	endpoint      *quic.Endpoint
+	activeConns   map[*http3clientConn]struct{}
+	inFlightDials int
+}
+
+// netHTTPTransport implements the net/http.dialClientConner interface,
+// allowing our HTTP/3 transport to integrate with net/http.
+type http3netHTTPTransport struct {
+	*http3transport
+}
+
+// Registered is called to record successful registration with a net/http Transport.
+func (t http3netHTTPTransport) Registered(tr1 *Transport) {
+	t.http3transport.tr1 = tr1
+}
+
+// RoundTrip is defined since Transport.RegisterProtocol takes in a
+// RoundTripper. However, this method will never be used as net/http's
+// dialClientConner interface does not have a RoundTrip method and will only
+// use DialClientConn to create a new RoundTripper.
+func (t http3netHTTPTransport) RoundTrip(*Request) (*Response, error) {
+	panic("netHTTPTransport.RoundTrip should never be called")
+}
+
+func (t http3netHTTPTransport) DialClientConn(ctx context.Context, addr string, _ *url.URL, tlsConfig *tls.Config, stateHook func()) (RoundTripper, error) {
+	return t.http3transport.dial(ctx, addr, tlsConfig, stateHook)
+}
+
+type http3TransportOpts struct {
+	// ListenQUIC determines how the transport will open a QUIC endpoint.
+	// By default, quic.Listen("udp", addr, config) is used.
+	// ListenQUIC might be called multiple times.
+	ListenQUIC func(addr string, config *quic.Config) (*quic.Endpoint, error)
+
+	// ListenPacket specifies the function for creating a UDP listener.
+	// If ListenPacket is nil, then the transport listens using net.ListenPacket.
+	//
+	// If ListenQUIC and ListenPacket are both set, ListenQUIC takes priority.
+	ListenPacket func(network, addr string) (net.PacketConn, error)
+
+	// QUICConfig is the QUIC configuration used by the transport.
+	// QUICConfig may be nil and should not be modified after calling
+	// RegisterTransport.
+	//
+	// The QUICConfig's TLSConfig is not used.
+	// Set the TLSConfig on the net/http Transport instead.
+	QUICConfig *quic.Config
+}
+
+// RegisterTransport configures a net/http HTTP/1 Transport to use HTTP/3.
+func http3RegisterTransport(tr *Transport, opts http3TransportOpts) error {
+	tr3 := &http3transport{
+		opts:        opts,
+		activeConns: make(map[*http3clientConn]struct{}),
+	}
+	// RegisterProtocol will set tr3.tr1.
+	tr.RegisterProtocol("http/3", http3netHTTPTransport{tr3})
+	if tr3.tr1 != tr {
+		return errors.New("http3: net/http does not support HTTP/3")
+	}
+	return nil
+}
+
+func (tr *http3transport) incInFlightDials() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.inFlightDials++
+}
+
+func (tr *http3transport) decInFlightDials() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.inFlightDials--
+}
+
+func (tr *http3transport) initEndpoint() (err error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	// This might cause rare issues on Darwin. Unlike Linux, Darwin kernel
+	// seems to have the following behaviors:
+	// - After closing a UDP socket, the port that was bound to the socket
+	//   might not be immediately usable again.
+	// - When doing IPv6 dual-stack binding (e.g., bind to ":0"), it will
+	//   happily bind the IPv6 port, even when the IPv4 port is unavailable.
+	//
+	// When both of these are combined, in practice, it is possible for the
+	// following to happen:
+	// 1. Transport binds ":0", creating a dual-stack IPv6 UDP socket.
+	//    Everything works as expected.
+	// 2. At some point, CloseIdleConnections is called and the socket is
+	//    closed.
+	// 3. Soon after, a new dial is started, and a new dual-stack IPv6 socket
+	//    is coincidentally assigned the same port as the previous socket.
+	// 4. If the IPv4 port is still unavailable, Darwin's permissive binding
+	//    behavior will cause us to have a socket that silently is unable to
+	//    receive packets on its IPv4 address.
+	// 5. If the dial target is an IPv4 address, transport will be able to send
+	//    packets to the target, but will be unable to receive its reply.
+	//
+	// TransportOpts.ListenQUIC can technically be configured to avoid
+	// dual-stack binding to avoid this issue, and high socket churn is
+	// probably uncommon for regular use cases. However, finding a workaround
+	// for this eventually would be ideal.
+	if tr.endpoint == nil {
+		quicConfig := http3newQUICConfig(tr.opts.QUICConfig, tr.tr1.TLSClientConfig)
+		if tr.opts.ListenQUIC != nil {
+			tr.endpoint, err = tr.opts.ListenQUIC(":0", quicConfig)
+		} else if tr.opts.ListenPacket != nil {
+			conn, err := tr.opts.ListenPacket("udp", ":0")
+			if err != nil {
+				return err
+			}
+			tr.endpoint, err = quic.NewEndpoint(conn, quicConfig)
+			if err != nil {
+				conn.Close()
+			}
+		} else {
+			tr.endpoint, err = quic.Listen("udp", ":0", quicConfig)
+		}
+	}
+	return err
+}
+
+// dial creates a new HTTP/3 client connection.
+func (tr *http3transport) dial(ctx context.Context, target string, tlsConfig *tls.Config, stateHook func()) (*http3clientConn, error) {
+	tr.incInFlightDials()
+	defer tr.decInFlightDials()
+
+	if err := tr.initEndpoint(); err != nil {
+		return nil, err
+	}
+	qconn, err := tr.endpoint.Dial(ctx, "udp", target, http3newQUICConfig(tr.opts.QUICConfig, tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	return tr.newClientConn(ctx, qconn, stateHook)
+}
+
+// CloseIdleConnections is called by net/http.Transport.CloseIdleConnections
+// after all existing idle connections are closed using http3.clientConn.Close.
+//
+// When the transport has no active connections anymore, calling this method
+// will make the transport clean up any shared resources that are no longer
+// required, such as its QUIC endpoint.
+func (tr *http3transport) CloseIdleConnections() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.endpoint == nil || len(tr.activeConns) > 0 || tr.inFlightDials > 0 {
+		return
+	}
+	tr.endpoint.Close(http3canceledCtx)
+	tr.endpoint = nil
+}
+
+// A clientConn is a client HTTP/3 connection.
+//
+// Multiple goroutines may invoke methods on a clientConn simultaneously.
+type http3clientConn struct {
+	tr *http3transport
+
+	qconn *quic.Conn
+	http3genericConn
+
+	enc http3qpackEncoder
+	dec http3qpackDecoder
+
+	// Guarded by genericConn.mu
+	reserved int
+	active   int
+	closed   bool
+
+	stateHook func()
+}
+
+func (tr *http3transport) registerConn(cc *http3clientConn) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.activeConns[cc] = struct{}{}
+}
+
+func (tr *http3transport) unregisterConn(cc *http3clientConn) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(tr.activeConns, cc)
+}
+
+func (tr *http3transport) newClientConn(ctx context.Context, qconn *quic.Conn, stateHook func()) (*http3clientConn, error) {
+	cc := &http3clientConn{
+		tr:        tr,
+		qconn:     qconn,
+		stateHook: stateHook,
+	}
+	tr.registerConn(cc)
+	cc.enc.init()
+
+	// Create control stream and send SETTINGS frame.
+	controlStream, err := http3newConnStream(ctx, cc.qconn, http3streamTypeControl)
+	if err != nil {
+		tr.unregisterConn(cc)
+		return nil, fmt.Errorf("http3: cannot create control stream: %v", err)
+	}
+	controlStream.writeSettings()
+	controlStream.Flush()
+
+	go func() {
+		cc.acceptStreams(qconn, cc)
+		cc.mu.Lock()
+		cc.closed = true
+		cc.mu.Unlock()
+		tr.unregisterConn(cc)
+		cc.maybeCallStateHook()
+	}()
+	return cc, nil
+}
+
+func (cc *http3clientConn) Close() error {
+	// We need to use Close rather than Abort on the QUIC connection.
+	// Otherwise, when a net/http.Transport.CloseIdleConnections is called, it
+	// might call the http3.transport.CloseIdleConnections prior to all idle
+	// connections being fully closed; this would make it unable to close its
+	// QUIC endpoint, making http3.transport.CloseIdleConnections a no-op
+	// unintentionally.
+	return cc.qconn.Close()
+}
+
+func (cc *http3clientConn) Err() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.closed {
+		return errors.New("connection closed")
+	}
+	return nil
+}
+
+func (cc *http3clientConn) Reserve() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.closed {
+		return errors.New("connection closed")
+	}
+	cc.reserved++
+	return nil
+}
+
+func (cc *http3clientConn) Release() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	// This is consistent with RoundTrip: both Release and RoundTrip will
+	// consume a reservation iff one exists.
+	if cc.reserved > 0 {
+		cc.reserved--
+	}
+}
+
+func (cc *http3clientConn) Available() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.closed {
+		return 0
+	}
+	// The general recommendation for HTTP/3 is to reuse the same connection
+	// for multiple requests rather than creating new connections. As of now,
+	// we don't have a good understanding of when one might want to create
+	// multiple HTTP/3 connections to the same server.
+	// Therefore, for ClientConn API, let HTTP/3 connections have no limit.
+	// Starting a new RoundTrip when we are at the connection limit will just
+	// block until a new max stream limit is received.
+	return math.MaxInt
+}
+
+func (cc *http3clientConn) InFlight() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.closed {
+		return 0
+	}
+	return cc.reserved + cc.active
+}
+
+func (cc *http3clientConn) maybeCallStateHook() {
+	if cc.stateHook != nil {
+		cc.stateHook()
+	}
+}
+
+func (cc *http3clientConn) handleControlStream(st *http3stream) error {
+	// "A SETTINGS frame MUST be sent as the first frame of each control stream [...]"
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.4-2
+	if err := st.readSettings(func(settingsType, settingsValue int64) error {
+		switch settingsType {
+		case http3settingsMaxFieldSectionSize:
+			_ = settingsValue // TODO
+		case http3settingsQPACKMaxTableCapacity:
+			_ = settingsValue // TODO
+		case http3settingsQPACKBlockedStreams:
+			_ = settingsValue // TODO
+		default:
+			// Unknown settings types are ignored.
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for {
+		ftype, err := st.readFrameHeader()
+		if err != nil {
+			return err
+		}
+		switch ftype {
+		case http3frameTypeCancelPush:
+			// "If a CANCEL_PUSH frame is received that references a push ID
+			// greater than currently allowed on the connection,
+			// this MUST be treated as a connection error of type H3_ID_ERROR."
+			// https://www.rfc-editor.org/rfc/rfc9114.html#section-7.2.3-7
+			return &http3connectionError{
+				code:    http3errH3IDError,
+				message: "CANCEL_PUSH received when no MAX_PUSH_ID has been sent",
+			}
+		case http3frameTypeGoaway:
+			// TODO: Wait for requests to complete before closing connection.
+			return http3errH3NoError
+		default:
+			// Unknown frames are ignored.
+			if err := st.discardUnknownFrame(ftype); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (cc *http3clientConn) handleEncoderStream(*http3stream) error {
+	// TODO
+	return nil
+}
+
+func (cc *http3clientConn) handleDecoderStream(*http3stream) error {
+	// TODO
+	return nil
+}
+
+func (cc *http3clientConn) handlePushStream(*http3stream) error {
+	// "A client MUST treat receipt of a push stream as a connection error
+	// of type H3_ID_ERROR when no MAX_PUSH_ID frame has been sent [...]"
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-4.6-3
+	return &http3connectionError{
+		code:    http3errH3IDError,
+		message: "push stream created when no MAX_PUSH_ID has been sent",
+	}
+}
+
+func (cc *http3clientConn) handleRequestStream(st *http3stream) error {
+	// "Clients MUST treat receipt of a server-initiated bidirectional
+	// stream as a connection error of type H3_STREAM_CREATION_ERROR [...]"
+	// https://www.rfc-editor.org/rfc/rfc9114.html#section-6.1-3
+	return &http3connectionError{
+		code:    http3errH3StreamCreationError,
+		message: "server created bidirectional stream",
+	}
+}
+
+// abort closes the connection with an error.
+func (cc *http3clientConn) abort(err error) {
+	if e, ok := err.(*http3connectionError); ok {
+		cc.qconn.Abort(&quic.ApplicationError{
+			Code:   uint64(e.code),
+			Reason: e.message,
+		})
+	} else {
+		cc.qconn.Abort(err)
+	}
+}
+
+// sizeVarint returns the size of the variable-length integer encoding of f.
+// Copied from internal/quic/quicwire to break dependency that makes bundling
+// into std more complicated.
+func http3sizeVarint(v uint64) int {
+	switch {
+	case v <= 63:
+		return 1
+	case v <= 16383:
+		return 2
+	case v <= 1073741823:
+		return 4
+	case v <= 4611686018427387903:
+		return 8
+	default:
+		panic("varint too large")
+	}
+}
@@ -302,6 +302,14 @@ type HTTP2Config struct {
 	// cipher suites prohibited by the HTTP/2 spec.
 	PermitProhibitedCipherSuites bool
 
+	// EnableConnectProtocol, if true, permits clients to use the
+	// extended CONNECT method defined in RFC 8441, which carries
+	// WebSocket connections over HTTP/2 (see [net/http/websocket]).
+	// It may also be enabled with the GODEBUG setting http2xconnect=1.
+	//
+	// This parameter only applies to Servers.
+	EnableConnectProtocol bool
+
 	// CountError, if non-nil, is called on HTTP/2 errors.
 	// It is intended to increment a metric for monitoring.
 	// The errType contains only lowercase letters, digits, and underscores
@@ -539,6 +539,9 @@ func mergeHTTP2Config(c1 *HTTP2Config, confer http2Configer) http2.Config {
 	if c2.PermitProhibitedCipherSuites {
 		c.PermitProhibitedCipherSuites = true
 	}
+	if c2.EnableConnectProtocol {
+		c.EnableConnectProtocol = true
+	}
 	if c.CountError == nil {
 		c.CountError = c2.CountError
 	}
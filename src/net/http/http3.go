@@ -7,11 +7,43 @@ package http
 import (
 	"context"
 	"crypto/tls"
+	"errors"
+	"math"
 	"net"
+	"net/http/internal/ascii"
+	"net/http/internal/httpcommon"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// net/http supports HTTP/3 using the implementation in h3_bundle.go,
+// which is bundled from golang.org/x/net/internal/http3.
+// The bundled implementation is used when Server.Protocols or
+// Transport.Protocols includes HTTP/3, unless another implementation
+// has been registered.
+//
+// The upstream implementation is still a work in progress, so HTTP/3
+// can't be added to Protocols outside of tests yet.
+//
+// A Server with HTTP/3 serves it from ListenAndServeTLS on the UDP port
+// with the same number as its TCP port, and advertises it in an Alt-Svc
+// header on HTTP/1 and HTTP/2 responses. A Transport with only HTTP/3
+// uses it for https:// URLs. A Transport with HTTP/3 and HTTP/1 or HTTP/2
+// uses HTTP/3 for new connections to origins that advertised it with an
+// Alt-Svc header (RFC 7838), and falls back to HTTP/1 or HTTP/2 if the
+// HTTP/3 connection fails.
+//
+// Changes to the HTTP/3 client behavior that net/http needs, such as
+// support for zstd-compressed responses, are made in this file rather
+// than in h3_bundle.go, which is generated.
+
 // http3Server is an HTTP/3 server implementation.
-// x/net/http3 registers an implementation of this interface by passing it to Server.Serve.
+// setupHTTP3 installs the bundled implementation. x/net/http3 registers
+// another implementation of this interface by passing it to Server.Serve.
 type http3Server interface {
 	ServeHTTP3(context.Context, net.PacketConn, *tls.Config, Handler) error
 	Shutdown(ctx context.Context) error
@@ -24,6 +56,65 @@ func (s *Server) setHTTP3Server(h3 http3Server) {
 	s.h3Server = h3
 }
 
+// setupHTTP3 configures s to serve HTTP/3 using the bundled implementation,
+// unless an implementation has already been registered.
+func (s *Server) setupHTTP3() error {
+	// Configure HTTP/2 first, since it may set s.TLSConfig.
+	if err := s.setupHTTP2_ServeTLS(); err != nil {
+		return err
+	}
+	s.h3Once.Do(func() {
+		if s.h3Server == nil {
+			s.setHTTP3Server(&bundledHTTP3Server{
+				http3netHTTPServer: http3netHTTPServer{&http3server{srv1: s}},
+			})
+		}
+	})
+	return nil
+}
+
+// bundledHTTP3Server is the bundled HTTP/3 server.
+// Unlike the x/net implementation, which leaves closing PacketConns to
+// its caller, it closes the PacketConns it serves once it has shut down.
+// Closing them any earlier would abort the connections being shut down.
+type bundledHTTP3Server struct {
+	http3netHTTPServer
+
+	mu     sync.Mutex
+	conns  map[net.PacketConn]struct{}
+	closed bool
+}
+
+func (s *bundledHTTP3Server) ServeHTTP3(ctx context.Context, conn net.PacketConn, tlsConfig *tls.Config, h Handler) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	if s.conns == nil {
+		s.conns = make(map[net.PacketConn]struct{})
+	}
+	s.conns[conn] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+	return s.http3netHTTPServer.ServeHTTP3(ctx, conn, tlsConfig, h)
+}
+
+func (s *bundledHTTP3Server) Shutdown(ctx context.Context) error {
+	err := s.http3netHTTPServer.Shutdown(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	return err
+}
+
 // http3PacketConn is a net.Listener we pass to Server.BaseContext for HTTP/3 ports.
 type http3PacketConn struct {
 	conn net.PacketConn
@@ -47,5 +138,214 @@ func (s *Server) serveHTTP3(conn net.PacketConn, certFile, keyFile string) error
 	if err != nil {
 		return err
 	}
-	return s.h3Server.ServeHTTP3(baseCtx, conn, tlsConfig, serverHandler{s})
+	if s.shuttingDown() {
+		return ErrServerClosed
+	}
+	err = s.h3Server.ServeHTTP3(baseCtx, conn, tlsConfig, serverHandler{s})
+	if s.shuttingDown() {
+		return ErrServerClosed
+	}
+	return err
+}
+
+// dialHTTP3 dials an HTTP/3 connection to addr for cm.
+func (t *Transport) dialHTTP3(ctx context.Context, cm connectMethod, addr string, internalStateHook func()) (*persistConn, error) {
+	if t.h3Transport == nil {
+		return nil, errors.New("http: Transport.Protocols contains HTTP3, but Transport does not support HTTP/3")
+	}
+	if cm.targetScheme != "https" {
+		return nil, errors.New("http: HTTP/3 requires an https URL")
+	}
+	tlsConfig, err := t.tlsConfigForDial(cm.addr())
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.NextProtos = []string{"h3"}
+	rt, err := t.h3Transport.DialClientConn(ctx, addr, cm.proxyURL, tlsConfig, internalStateHook)
+	if err != nil {
+		return nil, err
+	}
+	if cc, ok := rt.(genericClientConn); ok {
+		rt = http3ClientConn{cc, t}
+	}
+	return &persistConn{
+		t:        t,
+		cacheKey: cm.key(),
+		alt:      rt,
+	}, nil
+}
+
+// http3ClientConn wraps an HTTP/3 client connection to decompress
+// zstd-encoded responses. The bundled implementation only decompresses
+// gzip, but sends the Accept-Encoding header computed by httpcommon,
+// which includes zstd.
+type http3ClientConn struct {
+	genericClientConn
+	t *Transport
+}
+
+func (cc http3ClientConn) RoundTrip(req *Request) (*Response, error) {
+	addedGzip := httpcommon.IsRequestGzip(req.Method, req.Header, cc.t.DisableCompression)
+	resp, err := cc.genericClientConn.RoundTrip(req)
+	if err != nil || !addedGzip {
+		return resp, err
+	}
+	if ok, zstd := httpcommon.IsResponseCompressed(resp.Header.Get("Content-Encoding")); ok && zstd {
+		resp.Body = &gzipReader{body: resp.Body, zstd: true}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// http3FallbackTimeout is how long the Transport tries to connect to
+// an HTTP/3 alternative before falling back to HTTP/1 or HTTP/2.
+var http3FallbackTimeout = 5 * time.Second
+
+// altSvcBrokenDuration is how long the Transport ignores the HTTP/3
+// alternatives advertised by an origin after connecting to one failed.
+const altSvcBrokenDuration = 5 * time.Minute
+
+// maxAltSvcEntries is the number of origins above which the Transport
+// stops remembering new HTTP/3 alternatives until some expire.
+const maxAltSvcEntries = 1000
+
+// An altSvcEntry is an HTTP/3 alternative for an origin,
+// advertised by an Alt-Svc header (RFC 7838).
+type altSvcEntry struct {
+	addr    string // "host:port" to connect to, or "" if broken
+	expires time.Time
+}
+
+// altSvcHTTP3 returns the address of the HTTP/3 alternative for the
+// origin with the given "host:port" address, or "" if there is none.
+func (t *Transport) altSvcHTTP3(origin string) string {
+	if !t.useAltSvc() {
+		return ""
+	}
+	t.altSvcMu.Lock()
+	defer t.altSvcMu.Unlock()
+	e, ok := t.altSvc[origin]
+	if !ok {
+		return ""
+	}
+	if !time.Now().Before(e.expires) {
+		delete(t.altSvc, origin)
+		return ""
+	}
+	return e.addr
+}
+
+// hasAltSvcHTTP3 reports whether there is an HTTP/3 alternative for u's origin.
+func (t *Transport) hasAltSvcHTTP3(u *url.URL) bool {
+	return u.Scheme == "https" && t.altSvcHTTP3(canonicalAddr(u)) != ""
+}
+
+// useAltSvc reports whether t switches to HTTP/3 alternatives.
+// It does so when HTTP/3 is enabled alongside another protocol.
+func (t *Transport) useAltSvc() bool {
+	p := t.protocols()
+	return p.http3() && (p.HTTP1() || p.HTTP2()) && t.h3Transport != nil
+}
+
+// altSvcBroken records that connecting to the HTTP/3 alternative
+// for origin failed.
+func (t *Transport) altSvcBroken(origin string) {
+	t.altSvcMu.Lock()
+	defer t.altSvcMu.Unlock()
+	if _, ok := t.altSvc[origin]; ok {
+		t.altSvc[origin] = altSvcEntry{expires: time.Now().Add(altSvcBrokenDuration)}
+	}
+}
+
+// noteAltSvc records the HTTP/3 alternative advertised by the
+// Alt-Svc header of a response received over a connection for cm.
+// Only alternatives on the origin's host are used.
+func (t *Transport) noteAltSvc(cm connectMethod, h Header) {
+	v, ok := h["Alt-Svc"]
+	if !ok || cm.proxyURL != nil || cm.targetScheme != "https" || !t.useAltSvc() {
+		return
+	}
+	host, _, err := net.SplitHostPort(cm.targetAddr)
+	if err != nil {
+		return
+	}
+	port, maxAge, cleared := parseAltSvcHTTP3(v, host)
+	t.altSvcMu.Lock()
+	defer t.altSvcMu.Unlock()
+	if cleared {
+		delete(t.altSvc, cm.targetAddr)
+		return
+	}
+	if port == "" {
+		return
+	}
+	now := time.Now()
+	e, ok := t.altSvc[cm.targetAddr]
+	if ok && e.addr == "" && now.Before(e.expires) {
+		return // broken
+	}
+	if !ok && len(t.altSvc) >= maxAltSvcEntries {
+		for origin, e := range t.altSvc {
+			if !now.Before(e.expires) {
+				delete(t.altSvc, origin)
+			}
+		}
+		if len(t.altSvc) >= maxAltSvcEntries {
+			return
+		}
+	}
+	if t.altSvc == nil {
+		t.altSvc = make(map[string]altSvcEntry)
+	}
+	t.altSvc[cm.targetAddr] = altSvcEntry{
+		addr:    net.JoinHostPort(host, port),
+		expires: now.Add(maxAge),
+	}
+}
+
+// parseAltSvcHTTP3 parses the values of Alt-Svc headers (RFC 7838,
+// Section 3) in a response from host. It returns the port and max age
+// of the first HTTP/3 alternative on the same host, and whether the
+// values clear all alternatives.
+func parseAltSvcHTTP3(values []string, host string) (port string, maxAge time.Duration, cleared bool) {
+	for _, v := range values {
+		if textproto.TrimString(v) == "clear" {
+			return "", 0, true
+		}
+		for alt := range strings.SplitSeq(v, ",") {
+			params := strings.Split(alt, ";")
+			proto, authority, ok := strings.Cut(textproto.TrimString(params[0]), "=")
+			if !ok || proto != "h3" {
+				continue
+			}
+			authority, err := strconv.Unquote(authority)
+			if err != nil {
+				continue
+			}
+			altHost, altPort, err := net.SplitHostPort(authority)
+			if err != nil || altHost != "" && !ascii.EqualFold(altHost, host) {
+				continue
+			}
+			if n, err := strconv.ParseUint(altPort, 10, 16); err != nil || n == 0 {
+				continue
+			}
+			maxAge = 24 * time.Hour
+			for _, param := range params[1:] {
+				name, val, _ := strings.Cut(textproto.TrimString(param), "=")
+				if name == "ma" {
+					if secs, err := strconv.ParseInt(val, 10, 64); err == nil && secs >= 0 {
+						maxAge = time.Duration(min(secs, int64(math.MaxInt64/time.Second))) * time.Second
+					}
+				}
+			}
+			if maxAge == 0 {
+				continue
+			}
+			return altPort, maxAge, false
+		}
+	}
+	return "", 0, false
 }
@@ -0,0 +1,255 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	. "net/http"
+	"net/http/httptest"
+	"net/http/internal/testcert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAltSvcHTTP3(t *testing.T) {
+	for _, test := range []struct {
+		values  []string
+		port    string
+		maxAge  time.Duration
+		cleared bool
+	}{
+		{values: nil},
+		{values: []string{`h3=":443"`}, port: "443", maxAge: 24 * time.Hour},
+		{values: []string{`h3=":8443"; ma=60`}, port: "8443", maxAge: time.Minute},
+		{values: []string{`h3="example.com:443"`}, port: "443", maxAge: 24 * time.Hour},
+		{values: []string{`h3="EXAMPLE.COM:443"`}, port: "443", maxAge: 24 * time.Hour},
+		{values: []string{`h2=":443", h3-29=":443", h3=":444"`}, port: "444", maxAge: 24 * time.Hour},
+		{values: []string{`h2=":443"`, `h3=":444";persist=1;ma=10`}, port: "444", maxAge: 10 * time.Second},
+		{values: []string{`h3=":443"; ma=0, h3=":444"`}, port: "444", maxAge: 24 * time.Hour},
+		{values: []string{`h3=":443"; ma=x`}, port: "443", maxAge: 24 * time.Hour},
+		{values: []string{`h3="other.example.com:443"`}},
+		{values: []string{`h3=":0"`}},
+		{values: []string{`h3=":99999"`}},
+		{values: []string{`h3=:443`}},
+		{values: []string{`h3`}},
+		{values: []string{`clear`}, cleared: true},
+		{values: []string{` clear `}, cleared: true},
+	} {
+		port, maxAge, cleared := ExportParseAltSvcHTTP3(test.values, "example.com")
+		if port != test.port || maxAge != test.maxAge || cleared != test.cleared {
+			t.Errorf("parseAltSvcHTTP3(%q) = %q, %v, %v; want %q, %v, %v",
+				test.values, port, maxAge, cleared, test.port, test.maxAge, test.cleared)
+		}
+	}
+}
+
+func newHTTP3TestClient(t *testing.T, protocols *Protocols) (*Client, *Transport) {
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(testcert.LocalhostCert)
+	tr := &Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+		Protocols:       protocols,
+	}
+	t.Cleanup(tr.CloseIdleConnections)
+	return &Client{Transport: tr}, tr
+}
+
+func getProto(t *testing.T, c *Client, url string) (proto, altSvc string) {
+	t.Helper()
+	res, err := c.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != res.Proto {
+		t.Errorf("server saw request as %q, client got response as %q", body, res.Proto)
+	}
+	return res.Proto, res.Header.Get("Alt-Svc")
+}
+
+func TestListenAndServeTLSHTTP3(t *testing.T) {
+	// Not parallel: uses global test hooks.
+	defer afterTest(t)
+	defer SetTestHookServerServe(nil)
+	lnc := make(chan net.Listener, 1)
+	SetTestHookServerServe(func(s *Server, ln net.Listener) {
+		lnc <- ln
+	})
+
+	cert, err := tls.X509KeyPair(testcert.LocalhostCert, testcert.LocalhostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var protocols Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	ProtocolSetHTTP3(&protocols)
+	s := &Server{
+		Addr:      "127.0.0.1:0",
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		Protocols: &protocols,
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {
+			io.WriteString(w, r.Proto)
+		}),
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- s.ListenAndServeTLS("", "") }()
+	var ln net.Listener
+	select {
+	case err := <-errc:
+		t.Fatalf("ListenAndServeTLS: %v", err)
+	case ln = <-lnc:
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	url := "https://" + ln.Addr().String()
+
+	c, tr := newHTTP3TestClient(t, &protocols)
+	proto, altSvc := getProto(t, c, url)
+	if want := `h3=":` + port + `"`; proto != "HTTP/2.0" || altSvc != want {
+		t.Errorf("first response: Proto = %q, Alt-Svc = %q; want HTTP/2.0, %q", proto, altSvc, want)
+	}
+	// The next request uses the advertised alternative.
+	proto, altSvc = getProto(t, c, url)
+	if proto != "HTTP/3.0" || altSvc != "" {
+		t.Errorf("second response: Proto = %q, Alt-Svc = %q; want HTTP/3.0, no Alt-Svc", proto, altSvc)
+	}
+	tr.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+	if err := <-errc; err != ErrServerClosed {
+		t.Errorf("ListenAndServeTLS = %v, want ErrServerClosed", err)
+	}
+}
+
+func TestListenAndServeTLSOnlyHTTP3(t *testing.T) {
+	// Not parallel: uses global test hooks.
+	defer afterTest(t)
+	defer SetTestHookServerServe(nil)
+	lnc := make(chan net.Listener, 1)
+	SetTestHookServerServe(func(s *Server, ln net.Listener) {
+		lnc <- ln
+	})
+
+	// Reserve a UDP port to serve on.
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := pc.LocalAddr().String()
+	pc.Close()
+
+	cert, err := tls.X509KeyPair(testcert.LocalhostCert, testcert.LocalhostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var protocols Protocols
+	ProtocolSetHTTP3(&protocols)
+	s := &Server{
+		Addr:      addr,
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		Protocols: &protocols,
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {
+			io.WriteString(w, r.Proto)
+		}),
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- s.ListenAndServeTLS("", "") }()
+
+	c, tr := newHTTP3TestClient(t, &protocols)
+	var res *Response
+	for {
+		res, err = c.Get("https://" + addr)
+		if err == nil {
+			break
+		}
+		select {
+		case err := <-errc:
+			t.Fatalf("ListenAndServeTLS: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	res.Body.Close()
+	if res.Proto != "HTTP/3.0" || res.Header.Get("Alt-Svc") != "" {
+		t.Errorf("Proto = %q, Alt-Svc = %q; want HTTP/3.0, no Alt-Svc", res.Proto, res.Header.Get("Alt-Svc"))
+	}
+	select {
+	case ln := <-lnc:
+		t.Errorf("server listening on TCP address %v with only HTTP/3 enabled", ln.Addr())
+	default:
+	}
+	tr.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+	if err := <-errc; err != ErrServerClosed {
+		t.Errorf("ListenAndServeTLS = %v, want ErrServerClosed", err)
+	}
+}
+
+func TestTransportHTTP3Fallback(t *testing.T) {
+	// Not parallel: modifies the fallback timeout.
+	defer func(d time.Duration) { *HTTP3FallbackTimeout = d }(*HTTP3FallbackTimeout)
+	*HTTP3FallbackTimeout = 100 * time.Millisecond
+
+	// Advertise an alternative that nothing answers on.
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	_, port, _ := net.SplitHostPort(pc.LocalAddr().String())
+
+	ts := httptest.NewUnstartedServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Alt-Svc", `h3=":`+port+`"`)
+		io.WriteString(w, r.Proto)
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	var protocols Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	ProtocolSetHTTP3(&protocols)
+	c, _ := newHTTP3TestClient(t, &protocols)
+
+	for i := range 3 {
+		start := time.Now()
+		proto, _ := getProto(t, c, ts.URL)
+		if proto != "HTTP/2.0" {
+			t.Errorf("request %v: Proto = %q, want HTTP/2.0", i, proto)
+		}
+		// Only the second request tries the alternative.
+		if d := time.Since(start); i == 1 && d < *HTTP3FallbackTimeout {
+			t.Errorf("request %v took %v, want at least the fallback timeout", i, d)
+		}
+	}
+}
+
+func TestTransportHTTP3RequiresTLS(t *testing.T) {
+	var protocols Protocols
+	ProtocolSetHTTP3(&protocols)
+	c := &Client{Transport: &Transport{Protocols: &protocols}}
+	_, err := c.Get("http://127.0.0.1:1/")
+	if err == nil || !strings.Contains(err.Error(), "HTTP/3") {
+		t.Errorf("GET http:// URL with only HTTP/3 enabled: err = %v, want HTTP/3 error", err)
+	}
+}
@@ -140,6 +140,10 @@ func TestNoUnicodeStrings(t *testing.T) {
 			strings.HasSuffix(path, "_test.go") ||
 			path == "internal/http2/ascii.go" ||
 			path == "internal/httpcommon/ascii.go" ||
+			// h3_bundle.go is generated from golang.org/x/net/internal/http3,
+			// which compares Content-Encoding to "gzip" with strings.EqualFold.
+			// It must be fixed there.
+			path == "h3_bundle.go" ||
 			d.IsDir() {
 			return nil
 		}
@@ -0,0 +1,588 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httputil
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNoHealthyBackend is returned by [BackendPool.RoundTrip] when
+// a request can't be sent because no backend of the pool is healthy.
+var ErrNoHealthyBackend = errors.New("httputil: no healthy backend")
+
+// A BalancePolicy selects the backend of a [BackendPool]
+// that receives a request.
+type BalancePolicy int
+
+const (
+	// RoundRobin sends requests to the healthy backends in turn.
+	RoundRobin BalancePolicy = iota
+
+	// LeastConnections sends each request to the healthy backend
+	// with the fewest requests in progress.
+	LeastConnections
+
+	// ConsistentHash sends requests with the same key, as returned
+	// by [BackendPool.HashKey], to the same backend for as long as it
+	// is healthy. Adding or removing a backend only moves the keys
+	// of a small fraction of the requests to other backends.
+	ConsistentHash
+)
+
+// Defaults for the health checking parameters of a BackendPool.
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+	defaultMaxFails            = 3
+	defaultFailTimeout         = 30 * time.Second
+)
+
+// hashReplicas is the number of points on the consistent hash ring
+// for each backend.
+const hashReplicas = 100
+
+// A BackendPool distributes the requests proxied by a [ReverseProxy]
+// among a set of backend servers, keeping track of their health.
+//
+// A BackendPool is used by setting the Rewrite, Transport and
+// ErrorHandler fields of a ReverseProxy:
+//
+//	proxy := &httputil.ReverseProxy{
+//		Rewrite: func(r *httputil.ProxyRequest) {
+//			pool.Rewrite(r)
+//			r.SetXForwarded()
+//		},
+//		Transport:    pool,
+//		ErrorHandler: pool.ErrorHandler,
+//	}
+//
+// Backends are health checked passively, by observing the results of
+// proxied requests, and optionally actively, by periodically sending a
+// request to each of them; see [BackendPool.RunHealthChecks].
+// A backend that fails MaxFails requests in a row is not used for
+// FailTimeout. A backend that fails an active health check is not used
+// until it passes one.
+//
+// When a request can't be sent because connecting to its backend
+// failed, it is sent to another healthy backend instead, until every
+// backend has been tried. Only the scheme and host of the request's URL
+// are changed, so the backends of a pool should have the same path.
+// Requests with a body are only retried in this way if their GetBody
+// field is set.
+//
+// The fields of a BackendPool must not be modified after it has been
+// used. A BackendPool is safe for concurrent use by multiple goroutines.
+type BackendPool struct {
+	// Policy selects the backend for each request.
+	Policy BalancePolicy
+
+	// HashKey returns the key used by the ConsistentHash policy
+	// to select a backend for a request. If nil, the IP address
+	// of the client, from the request's RemoteAddr, is used.
+	// The request passed to HashKey is the inbound request
+	// received by the proxy. HashKey is called by [BackendPool.Rewrite],
+	// without holding any lock of the pool.
+	HashKey func(*http.Request) string
+
+	// Transport is used to send proxied requests and health checks.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// HealthCheckPath is the path requested by active health checks,
+	// relative to each backend's URL. A backend is healthy if it
+	// responds with a 2xx or 3xx status code.
+	// If empty, "/" is used.
+	HealthCheckPath string
+
+	// HealthCheckInterval is the time between the active health checks
+	// of a backend. If zero, a default of 10 seconds is used.
+	HealthCheckInterval time.Duration
+
+	// HealthCheckTimeout is the time limit for an active health check.
+	// If zero, a default of 5 seconds is used.
+	HealthCheckTimeout time.Duration
+
+	// MaxFails is the number of consecutive failed requests after
+	// which a backend is considered unhealthy. A request fails if the
+	// backend can't be reached, or if it responds with a 502 (Bad
+	// Gateway), 503 (Service Unavailable) or 504 (Gateway Timeout)
+	// status code. If zero, a default of 3 is used. If negative,
+	// backends are not health checked passively.
+	MaxFails int
+
+	// FailTimeout is the time for which a backend that failed MaxFails
+	// requests is not used. If zero, a default of 30 seconds is used.
+	FailTimeout time.Duration
+
+	// ErrorLog specifies an optional logger for proxy errors and
+	// changes in the health of backends.
+	// If nil, logging is done via the log package's standard logger.
+	ErrorLog *log.Logger
+
+	mu       sync.Mutex
+	backends []*backend
+	ring     []hashPoint // sorted by hash; for ConsistentHash
+	next     int         // for RoundRobin
+}
+
+// backend is a server in a BackendPool.
+// Its fields are guarded by the pool's mu.
+type backend struct {
+	url       *url.URL
+	active    int       // requests in progress
+	fails     int       // consecutive failed requests
+	downUntil time.Time // end of the passive health check timeout
+	unhealthy bool      // failed the last active health check
+}
+
+// hashPoint is a point on the consistent hash ring.
+type hashPoint struct {
+	hash uint64
+	b    *backend
+}
+
+// A BackendStatus describes the state of a backend of a [BackendPool].
+type BackendStatus struct {
+	URL *url.URL
+
+	// Healthy reports whether the backend is currently used
+	// for new requests.
+	Healthy bool
+
+	// ActiveRequests is the number of requests in progress.
+	ActiveRequests int
+}
+
+// Add adds a backend with the given URL to the pool. Requests are
+// routed to the backend as by [ProxyRequest.SetURL].
+// Adding a backend that is already in the pool has no effect.
+func (p *BackendPool) Add(target *url.URL) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := target.String()
+	for _, b := range p.backends {
+		if b.url.String() == s {
+			return
+		}
+	}
+	u := *target
+	p.backends = append(p.backends, &backend{url: &u})
+	p.buildRingLocked()
+}
+
+// Remove removes the backend with the given URL from the pool.
+// Requests already sent to the backend are not affected.
+func (p *BackendPool) Remove(target *url.URL) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := target.String()
+	p.backends = slices.DeleteFunc(p.backends, func(b *backend) bool {
+		return b.url.String() == s
+	})
+	p.buildRingLocked()
+}
+
+// Status returns the state of each backend in the pool,
+// in the order they were added.
+func (p *BackendPool) Status() []BackendStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	status := make([]BackendStatus, len(p.backends))
+	for i, b := range p.backends {
+		u := *b.url
+		status[i] = BackendStatus{
+			URL:            &u,
+			Healthy:        b.healthy(now),
+			ActiveRequests: b.active,
+		}
+	}
+	return status
+}
+
+func (b *backend) healthy(now time.Time) bool {
+	return !b.unhealthy && !now.Before(b.downUntil)
+}
+
+func (p *BackendPool) buildRingLocked() {
+	p.ring = p.ring[:0]
+	for _, b := range p.backends {
+		for i := range hashReplicas {
+			p.ring = append(p.ring, hashPoint{hashString(strconv.Itoa(i) + " " + b.url.String()), b})
+		}
+	}
+	sort.Slice(p.ring, func(i, j int) bool { return p.ring[i].hash < p.ring[j].hash })
+}
+
+// hashString returns a hash of s for the consistent hash ring.
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	io.WriteString(h, s)
+	// FNV leaves the high bits of the hashes of similar short
+	// strings close together. Mix them as in MurmurHash3's fmix64,
+	// so that such strings are spread around the ring.
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// hashKey returns the key of the inbound request in for the ConsistentHash
+// policy. It calls HashKey, so it must not be called with p.mu held.
+func (p *BackendPool) hashKey(in *http.Request) string {
+	if p.HashKey != nil {
+		return p.HashKey(in)
+	}
+	if host, _, err := net.SplitHostPort(in.RemoteAddr); err == nil {
+		return host
+	}
+	return in.RemoteAddr
+}
+
+// pick selects a healthy backend for a request with the given hash key,
+// other than those in exclude. It returns nil if there is none.
+func (p *BackendPool) pick(key string, exclude []*backend) *backend {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	usable := func(b *backend) bool {
+		return b.healthy(now) && !slices.Contains(exclude, b)
+	}
+	var picked *backend
+	switch p.Policy {
+	case LeastConnections:
+		n := len(p.backends)
+		for i := range n {
+			// Start at a rotating position to spread ties.
+			b := p.backends[(p.next+i)%n]
+			if usable(b) && (picked == nil || b.active < picked.active) {
+				picked = b
+			}
+		}
+		p.next++
+	case ConsistentHash:
+		if len(p.ring) == 0 {
+			break
+		}
+		h := hashString(key)
+		start := sort.Search(len(p.ring), func(i int) bool { return p.ring[i].hash >= h })
+		for i := range p.ring {
+			if b := p.ring[(start+i)%len(p.ring)].b; usable(b) {
+				picked = b
+				break
+			}
+		}
+	default:
+		n := len(p.backends)
+		for i := range n {
+			if b := p.backends[(p.next+i)%n]; usable(b) {
+				picked = b
+				p.next += i + 1
+				break
+			}
+		}
+	}
+	return picked
+}
+
+// acquire records the start of a request to b.
+func (p *BackendPool) acquire(b *backend) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b.active++
+}
+
+// release records the end of a request to b, started by acquire.
+// failed reports whether the request failed.
+func (p *BackendPool) release(b *backend, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b.active--
+	maxFails := p.MaxFails
+	if maxFails == 0 {
+		maxFails = defaultMaxFails
+	}
+	if maxFails < 0 {
+		return
+	}
+	if !failed {
+		b.fails = 0
+		return
+	}
+	b.fails++
+	if b.fails >= maxFails {
+		b.fails = 0
+		timeout := p.FailTimeout
+		if timeout <= 0 {
+			timeout = defaultFailTimeout
+		}
+		b.downUntil = time.Now().Add(timeout)
+		p.logf("httputil: backend %v failed %d requests; disabled for %v", b.url, maxFails, timeout)
+	}
+}
+
+// poolRequest is the value stored in the context of an outbound request
+// by BackendPool.Rewrite.
+type poolRequest struct {
+	key     string   // hash key, for the ConsistentHash policy
+	backend *backend // nil if no backend was available
+}
+
+type poolRequestKey struct{}
+
+// Rewrite routes the outbound request r.Out to a healthy backend
+// selected by the pool's Policy, as by [ProxyRequest.SetURL].
+//
+// If there is no healthy backend, Rewrite leaves r.Out's URL unchanged,
+// and [BackendPool.RoundTrip] returns [ErrNoHealthyBackend].
+//
+// The outbound request should be sent with [BackendPool.RoundTrip].
+// Requests sent by another RoundTripper are not counted by the
+// LeastConnections policy or by passive health checks.
+func (p *BackendPool) Rewrite(r *ProxyRequest) {
+	pr := new(poolRequest)
+	if p.Policy == ConsistentHash {
+		pr.key = p.hashKey(r.In)
+	}
+	pr.backend = p.pick(pr.key, nil)
+	if pr.backend != nil {
+		r.SetURL(pr.backend.url)
+	}
+	r.Out = r.Out.WithContext(context.WithValue(r.Out.Context(), poolRequestKey{}, pr))
+}
+
+func (p *BackendPool) transport() http.RoundTripper {
+	if p.Transport != nil {
+		return p.Transport
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements the [http.RoundTripper] interface.
+//
+// RoundTrip sends requests that were rewritten by [BackendPool.Rewrite]
+// to their backend, recording the outcome for passive health checking.
+// If connecting to the backend fails, the request is sent to another
+// backend. Other requests are sent unchanged using the pool's Transport.
+func (p *BackendPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	pr, _ := req.Context().Value(poolRequestKey{}).(*poolRequest)
+	if pr == nil {
+		return p.transport().RoundTrip(req)
+	}
+	b := pr.backend
+	if b == nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrNoHealthyBackend
+	}
+	var tried []*backend
+	for {
+		p.acquire(b)
+		resp, err := p.transport().RoundTrip(req)
+		if err == nil {
+			switch resp.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				p.release(b, true)
+				return resp, nil
+			}
+			// Count the request as active until its response body is closed.
+			body := &backendBody{ReadCloser: resp.Body, release: func() { p.release(b, false) }}
+			if rwc, ok := resp.Body.(io.ReadWriteCloser); ok {
+				resp.Body = backendBodyWriter{body, rwc}
+			} else {
+				resp.Body = body
+			}
+			return resp, nil
+		}
+		p.release(b, true)
+		if !isDialError(err) || req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return nil, err
+		}
+		tried = append(tried, b)
+		next := p.pick(pr.key, tried)
+		if next == nil {
+			return nil, err
+		}
+		p.logf("httputil: backend %v unreachable, retrying request on %v: %v", b.url, next.url, err)
+		b = next
+		req = retarget(req, b.url)
+		if req.GetBody != nil {
+			body, gerr := req.GetBody()
+			if gerr != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retarget returns a copy of req sent to the scheme and host of target.
+// The rest of the URL is kept, including any changes made to it after
+// BackendPool.Rewrite.
+func retarget(req *http.Request, target *url.URL) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *req
+	u2 := *req.URL
+	u2.Scheme = target.Scheme
+	u2.Host = target.Host
+	r2.URL = &u2
+	return r2
+}
+
+// isDialError reports whether err is an error connecting to a server,
+// before any part of a request was sent.
+func isDialError(err error) bool {
+	var oe *net.OpError
+	return errors.As(err, &oe) && oe.Op == "dial"
+}
+
+// backendBody is the body of a response from a backend.
+// It releases the backend when it is read to the end or closed.
+type backendBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *backendBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *backendBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// backendBodyWriter is a backendBody for a 101 Switching Protocols
+// response, whose body is writable.
+type backendBodyWriter struct {
+	*backendBody
+	w io.Writer
+}
+
+func (b backendBodyWriter) Write(p []byte) (int, error) {
+	return b.w.Write(p)
+}
+
+// ErrorHandler writes an error response for a request that could not
+// be proxied. It is suitable for use as a ReverseProxy's ErrorHandler.
+//
+// ErrorHandler logs err, and responds with a 503 (Service Unavailable)
+// status code if err is [ErrNoHealthyBackend], and with a 502 (Bad
+// Gateway) status code otherwise.
+func (p *BackendPool) ErrorHandler(rw http.ResponseWriter, req *http.Request, err error) {
+	p.logf("http: proxy error: %v", err)
+	if errors.Is(err, ErrNoHealthyBackend) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	rw.WriteHeader(http.StatusBadGateway)
+}
+
+// RunHealthChecks actively checks the health of the pool's backends
+// until ctx is done, and then returns ctx's error. Each backend is
+// checked every HealthCheckInterval by sending a GET request for
+// HealthCheckPath. The first checks are made immediately.
+//
+// Active health checks are optional. If RunHealthChecks is not called,
+// only the results of proxied requests are used to determine the health
+// of backends.
+func (p *BackendPool) RunHealthChecks(ctx context.Context) error {
+	interval := p.HealthCheckInterval
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.mu.Lock()
+		backends := slices.Clone(p.backends)
+		p.mu.Unlock()
+		var wg sync.WaitGroup
+		for _, b := range backends {
+			wg.Go(func() {
+				healthy := p.checkHealth(ctx, b)
+				p.mu.Lock()
+				if b.unhealthy == healthy {
+					if healthy {
+						p.logf("httputil: backend %v passed health check", b.url)
+					} else {
+						p.logf("httputil: backend %v failed health check", b.url)
+					}
+				}
+				b.unhealthy = !healthy
+				p.mu.Unlock()
+			})
+		}
+		wg.Wait()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHealth sends a health check request to b
+// and reports whether it succeeded.
+func (p *BackendPool) checkHealth(ctx context.Context, b *backend) bool {
+	timeout := p.HealthCheckTimeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	path := p.HealthCheckPath
+	if path == "" {
+		path = "/"
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return false
+	}
+	u := *b.url
+	u.Path, u.RawPath = joinURLPath(b.url, ref)
+	u.RawQuery = ref.RawQuery
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := p.transport().RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 400
+}
+
+func (p *BackendPool) logf(format string, args ...any) {
+	if p.ErrorLog != nil {
+		p.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newPoolBackends starts n backends that respond with their index.
+func newPoolBackends(t *testing.T, n int, h http.HandlerFunc) []*url.URL {
+	var urls []*url.URL
+	for i := range n {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Backend", strconv.Itoa(i))
+			if h != nil {
+				h(w, r)
+			}
+		}))
+		t.Cleanup(ts.Close)
+		u, _ := url.Parse(ts.URL)
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// newPoolProxy returns a proxy server using pool.
+func newPoolProxy(t *testing.T, pool *BackendPool) *httptest.Server {
+	if pool.ErrorLog == nil {
+		pool.ErrorLog = log.New(io.Discard, "", 0) // quiet for tests
+	}
+	proxy := httptest.NewServer(&ReverseProxy{
+		Rewrite:      pool.Rewrite,
+		Transport:    pool,
+		ErrorHandler: pool.ErrorHandler,
+	})
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+// poolGet makes a request to the proxy and returns the status code
+// and the index of the backend that served it, or -1.
+func poolGet(t *testing.T, c *http.Client, url string) (int, int) {
+	t.Helper()
+	res, err := c.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	backend := -1
+	if v := res.Header.Get("X-Backend"); v != "" {
+		backend, _ = strconv.Atoi(v)
+	}
+	return res.StatusCode, backend
+}
+
+func TestBackendPoolRoundRobin(t *testing.T) {
+	pool := &BackendPool{Policy: RoundRobin}
+	for _, u := range newPoolBackends(t, 3, nil) {
+		pool.Add(u)
+	}
+	proxy := newPoolProxy(t, pool)
+	counts := make([]int, 3)
+	for range 9 {
+		code, b := poolGet(t, proxy.Client(), proxy.URL)
+		if code != http.StatusOK || b < 0 {
+			t.Fatalf("got status %v from backend %v", code, b)
+		}
+		counts[b]++
+	}
+	for i, n := range counts {
+		if n != 3 {
+			t.Errorf("backend %v served %v requests, want 3", i, n)
+		}
+	}
+}
+
+func TestBackendPoolLeastConnections(t *testing.T) {
+	release := make(chan struct{})
+	urls := newPoolBackends(t, 2, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			w.(http.Flusher).Flush()
+			<-release
+		}
+	})
+	pool := &BackendPool{Policy: LeastConnections}
+	for _, u := range urls {
+		pool.Add(u)
+	}
+	proxy := newPoolProxy(t, pool)
+
+	// A slow request keeps one backend busy
+	// while its response body is being read.
+	res, err := proxy.Client().Get(proxy.URL + "/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy, _ := strconv.Atoi(res.Header.Get("X-Backend"))
+	for range 4 {
+		if _, b := poolGet(t, proxy.Client(), proxy.URL); b == busy {
+			t.Errorf("request sent to busy backend %v", b)
+		}
+	}
+	close(release)
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+}
+
+func TestBackendPoolConsistentHash(t *testing.T) {
+	urls := newPoolBackends(t, 4, nil)
+	pool := &BackendPool{
+		Policy:  ConsistentHash,
+		HashKey: func(r *http.Request) string { return r.URL.Query().Get("key") },
+	}
+	for _, u := range urls {
+		pool.Add(u)
+	}
+	proxy := newPoolProxy(t, pool)
+	c := proxy.Client()
+
+	assigned := make(map[string]int)
+	used := make(map[int]bool)
+	for i := range 50 {
+		key := "k" + strconv.Itoa(i)
+		_, b := poolGet(t, c, proxy.URL+"/?key="+key)
+		assigned[key] = b
+		used[b] = true
+		if _, again := poolGet(t, c, proxy.URL+"/?key="+key); again != b {
+			t.Errorf("key %v sent to backend %v, then %v", key, b, again)
+		}
+	}
+	if len(used) != 4 {
+		t.Errorf("keys spread over %v backends, want 4", len(used))
+	}
+
+	// Removing a backend only moves its own keys.
+	pool.Remove(urls[0])
+	for key, b := range assigned {
+		_, now := poolGet(t, c, proxy.URL+"/?key="+key)
+		if b != 0 && now != b {
+			t.Errorf("key %v moved from backend %v to %v", key, b, now)
+		}
+		if now == 0 {
+			t.Errorf("key %v sent to removed backend", key)
+		}
+	}
+}
+
+func TestBackendPoolDialFailover(t *testing.T) {
+	urls := newPoolBackends(t, 2, nil)
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL, _ := url.Parse(down.URL)
+	down.Close()
+
+	pool := &BackendPool{Policy: RoundRobin, MaxFails: 2, FailTimeout: time.Hour}
+	pool.Add(downURL)
+	pool.Add(urls[0])
+	pool.Add(urls[1])
+	proxy := newPoolProxy(t, pool)
+	for range 6 {
+		if code, b := poolGet(t, proxy.Client(), proxy.URL); code != http.StatusOK || b < 0 {
+			t.Fatalf("got status %v from backend %v", code, b)
+		}
+	}
+	status := pool.Status()
+	if status[0].Healthy {
+		t.Errorf("unreachable backend is healthy")
+	}
+	if !status[1].Healthy || !status[2].Healthy {
+		t.Errorf("reachable backends unhealthy: %+v", status)
+	}
+	for _, s := range status {
+		if s.ActiveRequests != 0 {
+			t.Errorf("backend %v has %v active requests after all requests completed", s.URL, s.ActiveRequests)
+		}
+	}
+}
+
+// A request retried on another backend keeps the changes made to its URL
+// after BackendPool.Rewrite.
+func TestBackendPoolDialFailoverKeepsRewrite(t *testing.T) {
+	urls := newPoolBackends(t, 1, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.RequestURI())
+	})
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL, _ := url.Parse(down.URL)
+	down.Close()
+
+	pool := &BackendPool{Policy: RoundRobin, ErrorLog: log.New(io.Discard, "", 0)}
+	pool.Add(downURL)
+	pool.Add(urls[0])
+	proxy := httptest.NewServer(&ReverseProxy{
+		Rewrite: func(r *ProxyRequest) {
+			pool.Rewrite(r)
+			r.Out.URL.Path = "/rewritten"
+		},
+		Transport: pool,
+	})
+	t.Cleanup(proxy.Close)
+	res, err := proxy.Client().Get(proxy.URL + "/orig?q=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if got, want := res.Header.Get("X-Path"), "/rewritten?q=1"; got != want {
+		t.Errorf("backend received %q, want %q", got, want)
+	}
+}
+
+// HashKey may use the pool.
+func TestBackendPoolHashKeyReentrant(t *testing.T) {
+	pool := &BackendPool{Policy: ConsistentHash}
+	pool.HashKey = func(r *http.Request) string {
+		return strconv.Itoa(len(pool.Status()))
+	}
+	for _, u := range newPoolBackends(t, 2, nil) {
+		pool.Add(u)
+	}
+	proxy := newPoolProxy(t, pool)
+	if code, b := poolGet(t, proxy.Client(), proxy.URL); code != http.StatusOK || b < 0 {
+		t.Fatalf("got status %v from backend %v", code, b)
+	}
+}
+
+// Requests rewritten by the pool but sent by another Transport
+// are not counted as active.
+func TestBackendPoolRewriteOnly(t *testing.T) {
+	pool := &BackendPool{Policy: LeastConnections}
+	for _, u := range newPoolBackends(t, 2, nil) {
+		pool.Add(u)
+	}
+	proxy := httptest.NewServer(&ReverseProxy{Rewrite: pool.Rewrite})
+	t.Cleanup(proxy.Close)
+	for range 4 {
+		if code, b := poolGet(t, proxy.Client(), proxy.URL); code != http.StatusOK || b < 0 {
+			t.Fatalf("got status %v from backend %v", code, b)
+		}
+	}
+	for _, s := range pool.Status() {
+		if s.ActiveRequests != 0 {
+			t.Errorf("backend %v has %v active requests after all requests completed", s.URL, s.ActiveRequests)
+		}
+	}
+}
+
+func TestBackendPoolPassiveHealth(t *testing.T) {
+	urls := newPoolBackends(t, 2, func(w http.ResponseWriter, r *http.Request) {
+		if w.Header().Get("X-Backend") == "0" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	pool := &BackendPool{MaxFails: 2}
+	pool.Add(urls[0])
+	pool.Add(urls[1])
+	proxy := newPoolProxy(t, pool)
+	unavailable := 0
+	for range 8 {
+		if code, _ := poolGet(t, proxy.Client(), proxy.URL); code == http.StatusServiceUnavailable {
+			unavailable++
+		}
+	}
+	if unavailable != 2 {
+		t.Errorf("got %v 503 responses, want 2", unavailable)
+	}
+	if pool.Status()[0].Healthy {
+		t.Errorf("failing backend is healthy")
+	}
+}
+
+func TestBackendPoolNoHealthyBackend(t *testing.T) {
+	pool := &BackendPool{}
+	proxy := newPoolProxy(t, pool)
+	if code, _ := poolGet(t, proxy.Client(), proxy.URL); code != http.StatusServiceUnavailable {
+		t.Errorf("empty pool: status = %v, want 503", code)
+	}
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL, _ := url.Parse(down.URL)
+	down.Close()
+	pool.Add(downURL)
+	if code, _ := poolGet(t, proxy.Client(), proxy.URL); code != http.StatusBadGateway {
+		t.Errorf("unreachable backend: status = %v, want 502", code)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	out := req.Clone(req.Context())
+	pr := &ProxyRequest{In: req, Out: out}
+	pool.Remove(downURL)
+	pool.Rewrite(pr)
+	if _, err := pool.RoundTrip(pr.Out); !errors.Is(err, ErrNoHealthyBackend) {
+		t.Errorf("RoundTrip = %v, want ErrNoHealthyBackend", err)
+	}
+}
+
+func TestBackendPoolActiveHealthChecks(t *testing.T) {
+	healthy := make(chan bool, 1)
+	healthy <- false
+	urls := newPoolBackends(t, 2, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/base/health" {
+			return
+		}
+		if w.Header().Get("X-Backend") == "1" {
+			h := <-healthy
+			healthy <- h
+			if !h {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	})
+	base := urls[1].JoinPath("base")
+	pool := &BackendPool{
+		HealthCheckPath:     "/health",
+		HealthCheckInterval: time.Millisecond,
+	}
+	pool.Add(urls[0].JoinPath("base"))
+	pool.Add(base)
+	pool.ErrorLog = log.New(io.Discard, "", 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- pool.RunHealthChecks(ctx) }()
+
+	waitHealthy := func(want bool) {
+		t.Helper()
+		for {
+			if pool.Status()[1].Healthy == want {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitHealthy(false)
+	if !pool.Status()[0].Healthy {
+		t.Errorf("healthy backend marked unhealthy")
+	}
+	<-healthy
+	healthy <- true
+	waitHealthy(true)
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("RunHealthChecks = %v, want context.Canceled", err)
+	}
+}
@@ -11,4 +11,8 @@ var (
 	ErrBodyNotAllowed  = errors.New("http: request method or response status code does not allow body")
 	ErrRequestCanceled = errors.New("net/http: request canceled")
 	ErrSkipAltProtocol = errors.New("net/http: skip alternate protocol")
+
+	// ErrExtendedConnectHTTP1 is returned by Transport.RoundTrip
+	// for an extended CONNECT request on an HTTP/1 connection.
+	ErrExtendedConnectHTTP1 = errors.New("net/http: extended CONNECT requires HTTP/2")
 )
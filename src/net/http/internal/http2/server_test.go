@@ -2857,6 +2857,29 @@ func testServer_MaxDecoderHeaderTableSize(t *testing.T) {
 	}
 }
 
+func TestServer_EnableConnectProtocol(t *testing.T) {
+	synctest.Test(t, testServer_EnableConnectProtocol)
+}
+func testServer_EnableConnectProtocol(t *testing.T) {
+	SetDisableExtendedConnectProtocol(t, true)
+	for _, enable := range []bool{false, true} {
+		st := newServerTester(t, func(w http.ResponseWriter, r *http.Request) {}, func(h2 *http.HTTP2Config) {
+			h2.EnableConnectProtocol = enable
+		})
+		var advertised bool
+		st.greetAndCheckSettings(func(s Setting) error {
+			if s.ID == SettingEnableConnectProtocol && s.Val == 1 {
+				advertised = true
+			}
+			return nil
+		})
+		if advertised != enable {
+			t.Errorf("EnableConnectProtocol = %v: server advertised SETTINGS_ENABLE_CONNECT_PROTOCOL = %v", enable, advertised)
+		}
+		st.Close()
+	}
+}
+
 func TestServer_MaxEncoderHeaderTableSize(t *testing.T) {
 	synctest.Test(t, testServer_MaxEncoderHeaderTableSize)
 }
@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"compress/flate"
 	"compress/gzip"
+	"compress/zstd"
 	"context"
 	"crypto/rand"
 	"crypto/tls"
@@ -2434,11 +2435,11 @@ func (rl *clientConnReadLoop) handleResponse(cs *clientStream, f *MetaHeadersFra
 	cs.bytesRemain = res.ContentLength
 	res.Body = transportResponseBody{cs}
 
-	if cs.requestedGzip && asciiEqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+	if ok, zstd := httpcommon.IsResponseCompressed(res.Header.Get("Content-Encoding")); cs.requestedGzip && ok {
 		res.Header.Del("Content-Encoding")
 		res.Header.Del("Content-Length")
 		res.ContentLength = -1
-		res.Body = &gzipReader{body: res.Body}
+		res.Body = &gzipReader{body: res.Body, zstd: zstd}
 		res.Uncompressed = true
 	}
 	return res, nil
@@ -3092,14 +3093,15 @@ func (rt erringRoundTripper) RoundTrip(*ClientRequest) (*ClientResponse, error)
 var errConcurrentReadOnResBody = errors.New("http2: concurrent read on response body")
 
 // gzipReader wraps a response body so it can lazily
-// get gzip.Reader from the pool on the first call to Read.
-// After Close is called it puts gzip.Reader to the pool immediately
+// get a gzip.Reader or zstd.Reader from the pool on the first call to Read.
+// After Close is called it puts the reader to the pool immediately
 // if there is no Read in progress or later when Read completes.
 type gzipReader struct {
 	_    incomparable
 	body io.ReadCloser // underlying Response.Body
+	zstd bool          // body is zstd rather than gzip compressed
 	mu   sync.Mutex    // guards zr and zerr
-	zr   *gzip.Reader  // stores gzip reader from the pool between reads
+	zr   io.Reader     // stores gzip or zstd reader from the pool between reads
 	zerr error         // sticky gzip reader init error or sentinel value to detect concurrent read and read after close
 }
 
@@ -3129,9 +3131,34 @@ func gzipPoolPut(zr *gzip.Reader) {
 	gzipPool.Put(zr)
 }
 
-// acquire returns a gzip.Reader for reading response body.
+var zstdPool = sync.Pool{New: func() any { return new(zstd.Reader) }}
+
+// decompressPoolGet gets a gzip.Reader or zstd.Reader from the pool
+// and resets it to read from r.
+func (gz *gzipReader) decompressPoolGet(r io.Reader) (io.Reader, error) {
+	if gz.zstd {
+		zr := zstdPool.Get().(*zstd.Reader)
+		zr.Reset(r)
+		return zr, nil
+	}
+	return gzipPoolGet(r)
+}
+
+// decompressPoolPut puts a reader returned by decompressPoolGet
+// back into its pool.
+func decompressPoolPut(zr io.Reader) {
+	switch zr := zr.(type) {
+	case *gzip.Reader:
+		gzipPoolPut(zr)
+	case *zstd.Reader:
+		zr.Reset(nil)
+		zstdPool.Put(zr)
+	}
+}
+
+// acquire returns a reader for reading response body.
 // The reader must be released after use.
-func (gz *gzipReader) acquire() (*gzip.Reader, error) {
+func (gz *gzipReader) acquire() (io.Reader, error) {
 	gz.mu.Lock()
 	defer gz.mu.Unlock()
 	if gz.zerr != nil {
@@ -3144,12 +3171,12 @@ func (gz *gzipReader) acquire() (*gzip.Reader, error) {
 		// even when mu is temporarily dropped.
 		gz.zerr = errConcurrentReadOnResBody
 		gz.mu.Unlock()
-		zr, err := gzipPoolGet(gz.body)
+		zr, err := gz.decompressPoolGet(gz.body)
 		gz.mu.Lock()
 		// Guard against Close being called while gzipPoolGet is running.
 		if gz.zerr != errConcurrentReadOnResBody {
 			if zr != nil {
-				gzipPoolPut(zr)
+				decompressPoolPut(zr)
 			}
 			return nil, gz.zerr
 		}
@@ -3163,24 +3190,24 @@ func (gz *gzipReader) acquire() (*gzip.Reader, error) {
 	return ret, nil
 }
 
-// release returns the gzip.Reader to the pool if Close was called during Read.
-func (gz *gzipReader) release(zr *gzip.Reader) {
+// release returns the reader to the pool if Close was called during Read.
+func (gz *gzipReader) release(zr io.Reader) {
 	gz.mu.Lock()
 	defer gz.mu.Unlock()
 	if gz.zerr == errConcurrentReadOnResBody {
 		gz.zr, gz.zerr = zr, nil
 	} else { // fs.ErrClosed
-		gzipPoolPut(zr)
+		decompressPoolPut(zr)
 	}
 }
 
-// close returns the gzip.Reader to the pool immediately or
+// close returns the reader to the pool immediately or
 // signals release to do so after Read completes.
 func (gz *gzipReader) close() {
 	gz.mu.Lock()
 	defer gz.mu.Unlock()
 	if gz.zerr == nil && gz.zr != nil {
-		gzipPoolPut(gz.zr)
+		decompressPoolPut(gz.zr)
 		gz.zr = nil
 	}
 	gz.zerr = fs.ErrClosed
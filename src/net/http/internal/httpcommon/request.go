@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"internal/godebug"
 	"net/http/httptrace"
 	"net/textproto"
 	"net/url"
@@ -23,6 +24,8 @@ var (
 	ErrRequestHeaderListSize = errors.New("request header list larger than peer's advertised limit")
 )
 
+var httpzstd = godebug.New("httpzstd")
+
 // Request is a subset of http.Request.
 // It'd be simpler to pass an *http.Request, of course, but we can't depend on net/http
 // without creating a dependency cycle.
@@ -39,8 +42,8 @@ type Request struct {
 type EncodeHeadersParam struct {
 	Request Request
 
-	// AddGzipHeader indicates that an "accept-encoding: gzip" header should be
-	// added to the request.
+	// AddGzipHeader indicates that an "accept-encoding: gzip, zstd" header
+	// should be added to the request.
 	AddGzipHeader bool
 
 	// PeerMaxHeaderListSize, when non-zero, is the peer's MAX_HEADER_LIST_SIZE setting.
@@ -217,7 +220,7 @@ func EncodeHeaders(ctx context.Context, param EncodeHeadersParam, headerf func(n
 			f("content-length", strconv.FormatInt(req.ActualContentLength, 10))
 		}
 		if param.AddGzipHeader {
-			f("accept-encoding", "gzip")
+			f("accept-encoding", AcceptEncoding())
 		}
 		if !didUA {
 			f("user-agent", param.DefaultUserAgent)
@@ -263,15 +266,15 @@ func EncodeHeaders(ctx context.Context, param EncodeHeadersParam, headerf func(n
 	return res, nil
 }
 
-// IsRequestGzip reports whether we should add an Accept-Encoding: gzip header
-// for a request.
+// IsRequestGzip reports whether we should add an Accept-Encoding: gzip, zstd
+// header for a request.
 func IsRequestGzip(method string, header map[string][]string, disableCompression bool) bool {
 	// TODO(bradfitz): this is a copy of the logic in net/http. Unify somewhere?
 	if !disableCompression &&
 		len(header["Accept-Encoding"]) == 0 &&
 		len(header["Range"]) == 0 &&
 		method != "HEAD" {
-		// Request gzip only, not deflate. Deflate is ambiguous and
+		// Request gzip and zstd, not deflate. Deflate is ambiguous and
 		// not as universally supported anyway.
 		// See: https://zlib.net/zlib_faq.html#faq39
 		//
@@ -280,14 +283,38 @@ func IsRequestGzip(method string, header map[string][]string, disableCompression
 		//   http://trac.nginx.org/nginx/ticket/358
 		//   https://golang.org/issue/5522
 		//
-		// We don't request gzip if the request is for a range, since
-		// auto-decoding a portion of a gzipped document will just fail
-		// anyway. See https://golang.org/issue/8923
+		// We don't request compression if the request is for a range,
+		// since auto-decoding a portion of a compressed document will
+		// just fail anyway. See https://golang.org/issue/8923
 		return true
 	}
 	return false
 }
 
+// AcceptEncoding returns the value of the Accept-Encoding header added to
+// requests by transports that request compression: "gzip, zstd", or "gzip"
+// if GODEBUG=httpzstd=0.
+func AcceptEncoding() string {
+	if httpzstd.Value() == "0" {
+		httpzstd.IncNonDefault()
+		return "gzip"
+	}
+	return "gzip, zstd"
+}
+
+// IsResponseCompressed reports whether a response with the given
+// Content-Encoding header value is compressed with an encoding that
+// AcceptEncoding requests, and whether that encoding is zstd.
+func IsResponseCompressed(contentEncoding string) (ok, zstd bool) {
+	switch {
+	case asciiEqualFold(contentEncoding, "gzip"):
+		return true, false
+	case asciiEqualFold(contentEncoding, "zstd"):
+		return httpzstd.Value() != "0", true
+	}
+	return false, false
+}
+
 // checkConnHeaders checks whether req has any invalid connection-level headers.
 //
 // https://www.rfc-editor.org/rfc/rfc9114.html#section-4.2-3
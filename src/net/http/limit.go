@@ -0,0 +1,194 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// minLimitSweep is the number of rate limiting keys
+// above which LimitHandler looks for keys to forget.
+const minLimitSweep = 1024
+
+// A LimitHandler is a [Handler] that protects another Handler from
+// overload, by limiting the rate of requests from each client and the
+// number of requests served at once.
+//
+// The request rate is limited with a token bucket for each key, as
+// returned by the Key function. A bucket holds up to Burst tokens and
+// is refilled at Rate tokens per second. Each request takes a token
+// from its bucket, and a request that finds its bucket empty is
+// rejected with a 429 (Too Many Requests) response. A request that
+// would exceed MaxInFlight concurrent requests is rejected with a 503
+// (Service Unavailable) response. Both responses have a Retry-After
+// header suggesting when the client may try again.
+//
+// To apply different limits to different routes, register a separate
+// LimitHandler for each pattern of a [ServeMux]. Since the ServeMux
+// sets the request's pattern and path values before calling the
+// LimitHandler, the Key function may use [Request.Pattern] and
+// [Request.PathValue]:
+//
+//	mux.Handle("POST /login", &http.LimitHandler{
+//		Handler: login,
+//		Rate:    1,
+//		Burst:   5,
+//	})
+//	mux.Handle("GET /api/{tenant}/", &http.LimitHandler{
+//		Handler:     api,
+//		Rate:        100,
+//		Key:         func(r *http.Request) string { return r.PathValue("tenant") },
+//		MaxInFlight: 50,
+//	})
+//
+// A LimitHandler must not be copied after first use.
+type LimitHandler struct {
+	// Handler serves the requests that are within the limits.
+	Handler Handler
+
+	// Rate is the number of requests per second allowed for each key,
+	// on average. If Rate is zero or negative, the request rate is not
+	// limited.
+	Rate float64
+
+	// Burst is the number of requests for a key that may be served
+	// at once after a period of inactivity, exceeding Rate.
+	// If Burst is zero or negative, it defaults to Rate rounded up,
+	// or 1 if that is smaller.
+	Burst int
+
+	// Key returns the key identifying the client of a request.
+	// Requests with the same key share a rate limit.
+	// If Key is nil, RemoteAddrKey is used.
+	Key func(*Request) string
+
+	// MaxInFlight is the maximum number of requests
+	// passed to Handler concurrently.
+	// If MaxInFlight is zero or negative, there is no limit.
+	MaxInFlight int
+
+	inFlight atomic.Int64
+	now      func() time.Time // for testing
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	sweepAt int // len(buckets) at which to sweep
+}
+
+// A tokenBucket is the rate limiting state of a key.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // time tokens was last updated
+}
+
+// RemoteAddrKey returns the IP address of the client that sent r,
+// from [Request.RemoteAddr], for use as a [LimitHandler.Key].
+// It doesn't consult headers set by proxies, such as X-Forwarded-For.
+func RemoteAddrKey(r *Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// HeaderKey returns a [LimitHandler.Key] function that
+// returns the value of the named request header.
+// Requests without the header share a rate limit.
+func HeaderKey(name string) func(*Request) string {
+	return func(r *Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// ServeHTTP passes r to l.Handler if it is within the limits,
+// and otherwise rejects it.
+func (l *LimitHandler) ServeHTTP(w ResponseWriter, r *Request) {
+	if l.MaxInFlight > 0 {
+		n := l.inFlight.Add(1)
+		defer l.inFlight.Add(-1)
+		if n > int64(l.MaxInFlight) {
+			w.Header().Set("Retry-After", "1")
+			Error(w, StatusText(StatusServiceUnavailable), StatusServiceUnavailable)
+			return
+		}
+	}
+	if l.Rate > 0 {
+		key := RemoteAddrKey
+		if l.Key != nil {
+			key = l.Key
+		}
+		now := time.Now
+		if l.now != nil {
+			now = l.now
+		}
+		if ok, wait := l.take(key(r), now()); !ok {
+			secs := max(1, int64(math.Ceil(wait.Seconds())))
+			w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+			Error(w, StatusText(StatusTooManyRequests), StatusTooManyRequests)
+			return
+		}
+	}
+	l.Handler.ServeHTTP(w, r)
+}
+
+// burst returns the capacity of a token bucket.
+func (l *LimitHandler) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return max(1, math.Ceil(l.Rate))
+}
+
+// take takes a token from the bucket for key. If the bucket is empty,
+// it returns false and the time until a token will be available.
+func (l *LimitHandler) take(key string, now time.Time) (ok bool, wait time.Duration) {
+	burst := l.burst()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[key]
+	if b == nil {
+		if l.buckets == nil {
+			l.buckets = make(map[string]*tokenBucket)
+		}
+		if len(l.buckets) >= max(l.sweepAt, minLimitSweep) {
+			l.sweep(now, burst)
+		}
+		b = &tokenBucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	} else {
+		b.refill(now, l.Rate, burst)
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.Rate * float64(time.Second))
+}
+
+// sweep forgets the keys with full buckets,
+// which are the same as the buckets of new keys.
+func (l *LimitHandler) sweep(now time.Time, burst float64) {
+	for key, b := range l.buckets {
+		b.refill(now, l.Rate, burst)
+		if b.tokens >= burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.sweepAt = 2 * len(l.buckets)
+}
+
+// refill adds the tokens accumulated since the bucket was last updated.
+func (b *tokenBucket) refill(now time.Time, rate, burst float64) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(burst, b.tokens+elapsed.Seconds()*rate)
+		b.last = now
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"fmt"
+	. "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimitHandlerRate(t *testing.T) {
+	now := time.Unix(1e9, 0)
+	l := &LimitHandler{
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {}),
+		Rate:    2,
+		Burst:   3,
+	}
+	l.SetNowForTesting(func() time.Time { return now })
+	serve := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		l.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The burst is served at once, then the rate applies.
+	for i := range 3 {
+		if rec := serve("192.0.2.1:1234"); rec.Code != 200 {
+			t.Fatalf("request %d: status %d, want 200", i, rec.Code)
+		}
+	}
+	rec := serve("192.0.2.1:5678")
+	if rec.Code != StatusTooManyRequests {
+		t.Fatalf("request over burst: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+
+	// Other clients have their own bucket.
+	if rec := serve("192.0.2.2:1234"); rec.Code != 200 {
+		t.Errorf("other client: status %d, want 200", rec.Code)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if rec := serve("192.0.2.1:1234"); rec.Code != 200 {
+		t.Errorf("after refill: status %d, want 200", rec.Code)
+	}
+	if rec := serve("192.0.2.1:1234"); rec.Code != StatusTooManyRequests {
+		t.Errorf("after refill: status %d, want 429", rec.Code)
+	}
+}
+
+func TestLimitHandlerRetryAfter(t *testing.T) {
+	now := time.Unix(1e9, 0)
+	l := &LimitHandler{
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {}),
+		Rate:    0.1, // one request every 10 seconds
+	}
+	l.SetNowForTesting(func() time.Time { return now })
+	for _, want := range []string{"", "10"} {
+		rec := httptest.NewRecorder()
+		l.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		if got := rec.Header().Get("Retry-After"); got != want {
+			t.Errorf("Retry-After = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestLimitHandlerKey(t *testing.T) {
+	mux := NewServeMux()
+	mux.Handle("/api/{tenant}/", &LimitHandler{
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {}),
+		Rate:    1,
+		Key:     func(r *Request) string { return r.PathValue("tenant") },
+	})
+	mux.Handle("/h/", &LimitHandler{
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {}),
+		Rate:    1,
+		Key:     HeaderKey("Api-Key"),
+	})
+	for _, test := range []struct {
+		path   string
+		apiKey string
+		want   int
+	}{
+		{"/api/a/x", "", 200},
+		{"/api/a/y", "", 429},
+		{"/api/b/x", "", 200},
+		{"/h/", "k1", 200},
+		{"/h/", "k1", 429},
+		{"/h/", "k2", 200},
+		{"/h/", "", 200},
+	} {
+		req := httptest.NewRequest("GET", test.path, nil)
+		if test.apiKey != "" {
+			req.Header.Set("Api-Key", test.apiKey)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != test.want {
+			t.Errorf("%v %q: status %d, want %d", test.path, test.apiKey, rec.Code, test.want)
+		}
+	}
+}
+
+func TestLimitHandlerMaxInFlight(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{})
+	l := &LimitHandler{
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {
+			started <- struct{}{}
+			<-block
+		}),
+		MaxInFlight: 2,
+	}
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			l.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		})
+		<-started
+	}
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("request over limit: status %d, Retry-After %q; want 503 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	close(block)
+	wg.Wait()
+
+	// Rejected requests don't count against the limit.
+	go func() { <-started }()
+	rec = httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != 200 {
+		t.Errorf("request after others finished: status %d, want 200", rec.Code)
+	}
+}
+
+func TestLimitHandlerForgetsKeys(t *testing.T) {
+	now := time.Unix(1e9, 0)
+	l := &LimitHandler{
+		Handler: HandlerFunc(func(w ResponseWriter, r *Request) {}),
+		Rate:    1,
+	}
+	l.SetNowForTesting(func() time.Time { return now })
+	for i := range 5000 {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = fmt.Sprintf("10.0.%d.%d:1", i/256, i%256)
+		l.ServeHTTP(httptest.NewRecorder(), req)
+		if i%1000 == 999 {
+			now = now.Add(time.Second)
+		}
+	}
+	if n := l.KeysForTesting(); n > 2048 {
+		t.Errorf("LimitHandler remembers %d keys, want at most 2048", n)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// A RetryPolicy specifies when and how a [Client] retries requests that
+// fail with a transient error. See [Client.Retry].
+//
+// Retries are attempted only for requests whose body can be sent again:
+// requests with no body, or with a non-nil GetBody function.
+// Each retry is sent with a body obtained from GetBody.
+//
+// A RetryPolicy must not be modified while it is in use by a Client.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried
+	// after the first attempt. If zero or negative, requests are
+	// not retried.
+	MaxRetries int
+
+	// MinBackoff is the delay before the first retry. The delay
+	// doubles for every following retry, up to MaxBackoff.
+	// If zero, a default of 100 milliseconds is used.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between two attempts.
+	// If zero, a default of 10 seconds is used.
+	//
+	// A response with a Retry-After header is retried after the delay
+	// the server asks for rather than the computed delay. If that delay
+	// is longer than MaxBackoff, the response is returned to the caller
+	// instead of being retried.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction of each computed delay that is chosen at
+	// random, to avoid many clients retrying in lockstep: a delay d is
+	// replaced by a random duration between d*(1-Jitter) and d.
+	// Jitter is not applied to delays requested by a Retry-After header.
+	// If zero, a default of 0.5 is used. If negative, no jitter is applied.
+	// Values greater than 1 are treated as 1.
+	Jitter float64
+
+	// ShouldRetry, if non-nil, reports whether a request should be
+	// retried after an attempt that returned the response resp or the
+	// error err. Exactly one of resp and err is non-nil. ShouldRetry
+	// must not read or close resp.Body.
+	//
+	// If ShouldRetry is nil, requests are retried if they use an
+	// idempotent method (GET, HEAD, OPTIONS, TRACE, PUT or DELETE) or
+	// have an Idempotency-Key header, and the attempt failed with a
+	// network error, such as a failure to connect or the connection
+	// being closed before the response was received, or returned a 429
+	// (Too Many Requests) or 503 (Service Unavailable) response. Other
+	// errors, such as an unsupported URL scheme or a malformed
+	// response, are not retried.
+	ShouldRetry func(req *Request, resp *Response, err error) bool
+}
+
+const (
+	defaultRetryMinBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 10 * time.Second
+	defaultRetryJitter     = 0.5
+)
+
+func (p *RetryPolicy) shouldRetry(req *Request, resp *Response, err error) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(req, resp, err)
+	}
+	if !req.isIdempotent() {
+		return false
+	}
+	if err != nil {
+		return isTransientError(err)
+	}
+	return resp.StatusCode == StatusTooManyRequests || resp.StatusCode == StatusServiceUnavailable
+}
+
+// isTransientError reports whether err, returned by an attempt to send a
+// request, may not occur again: a network error, or the connection being
+// closed before the response was received. Other errors, such as an
+// unsupported URL scheme or a malformed response, are permanent.
+func isTransientError(err error) bool {
+	var (
+		alertErr tls.AlertError
+		netErr   net.Error
+	)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.As(err, &alertErr):
+		// The server rejected the TLS handshake.
+		return false
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+	return errors.As(err, &netErr)
+}
+
+// backoff returns the delay before the retry following the given
+// number of previous retries, and whether to retry at all.
+func (p *RetryPolicy) backoff(retries int, resp *Response) (time.Duration, bool) {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d, d <= maxBackoff
+		}
+	}
+
+	d := p.MinBackoff
+	if d <= 0 {
+		d = defaultRetryMinBackoff
+	}
+	for range retries {
+		if d >= maxBackoff/2 {
+			d = maxBackoff
+			break
+		}
+		d *= 2
+	}
+	d = min(d, maxBackoff)
+
+	jitter := p.Jitter
+	switch {
+	case jitter == 0:
+		jitter = defaultRetryJitter
+	case jitter > 1:
+		jitter = 1
+	}
+	if jitter > 0 {
+		if n := int64(float64(d) * jitter); n > 0 {
+			d -= time.Duration(rand.Int64N(n + 1))
+		}
+	}
+	return d, true
+}
+
+// parseRetryAfter parses the value of a Retry-After header,
+// which is either a number of seconds or an HTTP-date,
+// and returns the delay it requests relative to now.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = textproto.TrimString(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseUint(v, 10, 63); err == nil {
+		if secs > uint64(1<<63-1)/uint64(time.Second) {
+			return 1<<63 - 1, true
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
+
+// isIdempotent reports whether r may be sent more than once
+// with the same effect as sending it once. RFC 9110, Section 9.2.2.
+func (r *Request) isIdempotent() bool {
+	switch valueOrDefault(r.Method, "GET") {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return r.Header.has("Idempotency-Key") || r.Header.has("X-Idempotency-Key")
+}
+
+// sendWithRetry sends req like c.send, retrying it according to c.Retry.
+func (c *Client) sendWithRetry(req *Request, deadline time.Time) (resp *Response, didTimeout func() bool, err error) {
+	p := c.Retry
+	if p == nil || p.MaxRetries <= 0 {
+		return c.send(req, deadline)
+	}
+	hasBody := req.Body != nil && req.Body != NoBody
+	if hasBody && req.GetBody == nil {
+		return c.send(req, deadline)
+	}
+
+	// c.send adds the Jar's cookies to the request header,
+	// so keep a copy of the original header for later attempts.
+	ireq := req
+	ihdr := ireq.Header.Clone()
+	for retries := 0; ; retries++ {
+		resp, didTimeout, err = c.send(req, deadline)
+		if retries >= p.MaxRetries || !p.shouldRetry(req, resp, err) {
+			return resp, didTimeout, err
+		}
+		delay, ok := p.backoff(retries, resp)
+		if !ok || !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+			return resp, didTimeout, err
+		}
+		next := new(Request)
+		*next = *ireq
+		next.Header = ihdr.Clone()
+		if hasBody {
+			body, gerr := ireq.GetBody()
+			if gerr != nil {
+				return resp, didTimeout, err
+			}
+			next.Body = body
+		}
+		if resp != nil {
+			// Read some of the body, so that the connection
+			// can be reused if the body is small.
+			const maxBodySlurpSize = 2 << 10
+			if resp.ContentLength == -1 || resp.ContentLength <= maxBodySlurpSize {
+				io.CopyN(io.Discard, resp.Body, maxBodySlurpSize)
+			}
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ireq.Context().Done():
+			t.Stop()
+			next.closeBody()
+			return nil, alwaysFalse, ireq.Context().Err()
+		}
+		req = next
+	}
+}
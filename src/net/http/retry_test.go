@@ -0,0 +1,304 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"io"
+	. "net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		v    string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{" 5 ", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"1.5", 0, false},
+		{"soon", 0, false},
+		{"Fri, 02 Jan 2026 15:05:05 GMT", time.Minute, true},
+		{"Fri, 02 Jan 2026 15:00:00 GMT", 0, true},
+		{"Friday, 02-Jan-26 15:04:15 GMT", 10 * time.Second, true},
+		{"99999999999999999999", 0, false},
+		{"9999999999999", 1<<63 - 1, true},
+	}
+	for _, test := range tests {
+		got, ok := ExportParseRetryAfter(test.v, now)
+		if got != test.want || ok != test.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", test.v, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+// failingHandler returns a handler that responds with status code
+// to the first failures requests and with "ok" to the rest, and
+// a counter of the requests it has served.
+func failingHandler(failures int32, code int, retryAfter string) (HandlerFunc, *atomic.Int32) {
+	var n atomic.Int32
+	return func(w ResponseWriter, r *Request) {
+		if n.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(code)
+			io.WriteString(w, "try again")
+			return
+		}
+		io.WriteString(w, "ok")
+	}, &n
+}
+
+func TestClientRetry(t *testing.T) { run(t, testClientRetry) }
+func testClientRetry(t *testing.T, mode testMode) {
+	h, n := failingHandler(2, StatusServiceUnavailable, "0")
+	cst := newClientServerTest(t, mode, h)
+	cst.c.Retry = &RetryPolicy{MaxRetries: 3}
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != StatusOK || string(body) != "ok" {
+		t.Errorf("got %v %q, want 200 %q", res.Status, body, "ok")
+	}
+	if got := n.Load(); got != 3 {
+		t.Errorf("server saw %v requests, want 3", got)
+	}
+}
+
+func TestClientRetryExhausted(t *testing.T) { run(t, testClientRetryExhausted) }
+func testClientRetryExhausted(t *testing.T, mode testMode) {
+	h, n := failingHandler(10, StatusTooManyRequests, "")
+	cst := newClientServerTest(t, mode, h)
+	cst.c.Retry = &RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != StatusTooManyRequests || string(body) != "try again" {
+		t.Errorf("got %v %q, want 429 %q", res.Status, body, "try again")
+	}
+	if got := n.Load(); got != 3 {
+		t.Errorf("server saw %v requests, want 3", got)
+	}
+}
+
+func TestClientRetryNotIdempotent(t *testing.T) { run(t, testClientRetryNotIdempotent) }
+func testClientRetryNotIdempotent(t *testing.T, mode testMode) {
+	h, n := failingHandler(1, StatusServiceUnavailable, "0")
+	cst := newClientServerTest(t, mode, h)
+	cst.c.Retry = &RetryPolicy{MaxRetries: 3}
+	res, err := cst.c.Post(cst.ts.URL, "text/plain", strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusServiceUnavailable {
+		t.Errorf("status = %v, want 503", res.Status)
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("server saw %v requests, want 1", got)
+	}
+}
+
+func TestClientRetryBody(t *testing.T) { run(t, testClientRetryBody) }
+func testClientRetryBody(t *testing.T, mode testMode) {
+	var n atomic.Int32
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %v", err)
+		}
+		if string(body) != "payload" {
+			t.Errorf("request body = %q, want %q", body, "payload")
+		}
+		if r.Header.Get("X-Test") != "value" {
+			t.Errorf("X-Test = %q, want %q", r.Header.Get("X-Test"), "value")
+		}
+		if n.Add(1) == 1 {
+			w.WriteHeader(StatusServiceUnavailable)
+		}
+	}))
+	cst.c.Retry = &RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}
+
+	for _, method := range []string{"PUT", "POST"} {
+		n.Store(0)
+		req, err := NewRequest(method, cst.ts.URL, strings.NewReader("payload"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Test", "value")
+		if method == "POST" {
+			req.Header.Set("Idempotency-Key", "123")
+		}
+		res, err := cst.c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != StatusOK {
+			t.Errorf("%v: status = %v, want 200", method, res.Status)
+		}
+		if got := n.Load(); got != 2 {
+			t.Errorf("%v: server saw %v requests, want 2", method, got)
+		}
+	}
+
+	// A body that can't be sent again prevents retries.
+	n.Store(0)
+	req, err := NewRequest("PUT", cst.ts.URL, io.NopCloser(strings.NewReader("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Test", "value")
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusServiceUnavailable {
+		t.Errorf("status = %v, want 503", res.Status)
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("server saw %v requests, want 1", got)
+	}
+}
+
+func TestClientRetryAfterTooLong(t *testing.T) { run(t, testClientRetryAfterTooLong) }
+func testClientRetryAfterTooLong(t *testing.T, mode testMode) {
+	h, n := failingHandler(1, StatusServiceUnavailable, "3600")
+	cst := newClientServerTest(t, mode, h)
+	cst.c.Retry = &RetryPolicy{MaxRetries: 3, MaxBackoff: time.Minute}
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusServiceUnavailable {
+		t.Errorf("status = %v, want 503", res.Status)
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("server saw %v requests, want 1", got)
+	}
+}
+
+func TestClientRetryShouldRetry(t *testing.T) { run(t, testClientRetryShouldRetry) }
+func testClientRetryShouldRetry(t *testing.T, mode testMode) {
+	h, n := failingHandler(1, StatusBadGateway, "")
+	cst := newClientServerTest(t, mode, h)
+	cst.c.Retry = &RetryPolicy{
+		MaxRetries: 1,
+		MinBackoff: time.Millisecond,
+		ShouldRetry: func(req *Request, res *Response, err error) bool {
+			return err == nil && res.StatusCode == StatusBadGateway
+		},
+	}
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusOK {
+		t.Errorf("status = %v, want 200", res.Status)
+	}
+	if got := n.Load(); got != 2 {
+		t.Errorf("server saw %v requests, want 2", got)
+	}
+}
+
+func TestClientRetryConnectionError(t *testing.T) {
+	run(t, testClientRetryConnectionError, []testMode{http1Mode})
+}
+func testClientRetryConnectionError(t *testing.T, mode testMode) {
+	var n atomic.Int32
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if n.Add(1) == 1 {
+			conn, _, err := w.(Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	cst.c.Retry = &RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusOK {
+		t.Errorf("status = %v, want 200", res.Status)
+	}
+	if got := n.Load(); got != 2 {
+		t.Errorf("server saw %v requests, want 2", got)
+	}
+}
+
+func TestClientRetryPermanentError(t *testing.T) {
+	run(t, testClientRetryPermanentError, []testMode{http1Mode})
+}
+func testClientRetryPermanentError(t *testing.T, mode testMode) {
+	var n atomic.Int32
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		n.Add(1)
+		conn, _, err := w.(Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "HTTP/1.1 malformed\r\n\r\n")
+	}))
+	cst.c.Retry = &RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}
+	if _, err := cst.c.Get(cst.ts.URL); err == nil {
+		t.Fatal("Get succeeded, want error")
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("server saw %v requests, want 1", got)
+	}
+}
+
+func TestClientRetryCanceled(t *testing.T) { run(t, testClientRetryCanceled) }
+func testClientRetryCanceled(t *testing.T, mode testMode) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var n atomic.Int32
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		n.Add(1)
+		// Cancel the request while the Client waits to retry it.
+		w.WriteHeader(StatusServiceUnavailable)
+		cancel()
+	}))
+	cst.c.Retry = &RetryPolicy{MaxRetries: 1, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	req, _ := NewRequestWithContext(ctx, "GET", cst.ts.URL, nil)
+	_, err := cst.c.Do(req)
+	if err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("Do = %v, want context canceled error", err)
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("server saw %v requests, want 1", got)
+	}
+}
@@ -612,8 +612,12 @@ func (w *response) ReadFrom(src io.Reader) (n int64, err error) {
 
 	// Our underlying w.conn.rwc is usually a *TCPConn (with its
 	// own ReadFrom method). If not, just fall back to the normal
-	// copy method.
+	// copy method. A *tls.Conn only benefits from ReadFrom when its
+	// records are encrypted by the kernel.
 	rf, ok := w.conn.rwc.(io.ReaderFrom)
+	if _, isTLS := w.conn.rwc.(*tls.Conn); isTLS && !w.conn.tlsState.KernelTLS {
+		ok = false
+	}
 	if !ok {
 		return io.CopyBuffer(writerOnly{w}, src, buf)
 	}
@@ -3181,6 +3185,8 @@ type Server struct {
 	h2Config      http2ExternalServerConfig
 	h2IdleTimeout time.Duration
 	h3Server      http3Server
+	h3Once        sync.Once              // guards setupHTTP3 init
+	h3AltSvc      atomic.Pointer[string] // Alt-Svc header advertising HTTP/3
 
 	listenerGroup sync.WaitGroup
 }
@@ -3413,6 +3419,10 @@ func (sh serverHandler) ServeHTTP(rw ResponseWriter, req *Request) {
 		handler = globalOptionsHandler{}
 	}
 
+	if altSvc := sh.srv.h3AltSvc.Load(); altSvc != nil && req.ProtoMajor < 3 && req.TLS != nil {
+		rw.Header()["Alt-Svc"] = []string{*altSvc}
+	}
+
 	defer func() {
 		if req.MultipartForm != nil {
 			req.MultipartForm.RemoveAll()
@@ -3632,8 +3642,11 @@ func (s *Server) ServeTLS(l net.Listener, certFile, keyFile string) error {
 	if err := s.setupHTTP2_ServeTLS(); err != nil {
 		return err
 	}
-	if s.h3Server != nil {
-		// Temporary, test-only way to serve HTTP/3 from a PacketConn:
+	if s.protocols().http3() {
+		if err := s.setupHTTP3(); err != nil {
+			return err
+		}
+		// Test-only way to serve HTTP/3 from a PacketConn:
 		// Pass it to ServeTLS wrapped in a net.Listener.
 		// The caller should pass a net.Listener that immediately returns an error
 		// if passed to a Server that doesn't support this path.
@@ -3852,9 +3865,9 @@ func ListenAndServeTLS(addr, certFile, keyFile string, handler Handler) error {
 // supports HTTP/3, allowing an external implementation of HTTP/3 to be used
 // via net/http. See https://go.dev/issue/77440 for details.
 //
-// This is currently only used with golang.org/x/net/internal/http3, to allow
-// us to test our HTTP/3 implementation against tests in net/http. HTTP/3 is
-// not yet accessible to end-users.
+// It is used by the HTTP/3 implementation bundled in h3_bundle.go, and by
+// golang.org/x/net/internal/http3 to test that implementation against the
+// tests in net/http.
 type http3ServerHandler struct {
 	handler     serverHandler
 	tlsConfig   *tls.Config
@@ -3928,22 +3941,65 @@ func (s *Server) ListenAndServeTLS(certFile, keyFile string) error {
 	}
 
 	p := s.protocols()
-	if p.http3() {
-		// TODO: Support HTTP/3 here.
-		// For now, tests use Server.ServeTLS.
-		return errors.New("http: Server.Protocols contains HTTP3, but Server does not support HTTP/3")
-	}
 	// Only start a TCP listener if HTTP/1 or HTTP/2 is used.
-	if !p.HTTP1() && !p.HTTP2() && !p.UnencryptedHTTP2() {
+	useTCP := p.HTTP1() || p.HTTP2() || p.UnencryptedHTTP2()
+	if !useTCP && !p.http3() {
 		return errors.New("http: no protocols configured")
 	}
 
-	ln, err := net.Listen("tcp", addr)
+	var ln net.Listener
+	if useTCP {
+		var err error
+		ln, err = net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		defer ln.Close()
+		if !p.http3() {
+			return s.ServeTLS(ln, certFile, keyFile)
+		}
+		// Serve HTTP/3 on the same port as HTTP/1 and HTTP/2,
+		// which matters when addr has port 0.
+		host, _, _ := net.SplitHostPort(addr)
+		_, port, _ := net.SplitHostPort(ln.Addr().String())
+		addr = net.JoinHostPort(host, port)
+	}
+
+	if err := s.setupHTTP3(); err != nil {
+		return err
+	}
+	pc, err := net.ListenPacket("udp", addr)
 	if err != nil {
 		return err
 	}
-	defer ln.Close()
-	return s.ServeTLS(ln, certFile, keyFile)
+	if !useTCP {
+		defer pc.Close()
+		return s.serveHTTP3(pc, certFile, keyFile)
+	}
+
+	_, port, _ := net.SplitHostPort(pc.LocalAddr().String())
+	altSvc := `h3=":` + port + `"`
+	s.h3AltSvc.Store(&altSvc)
+	defer s.h3AltSvc.Store(nil)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.serveHTTP3(pc, certFile, keyFile)
+		ln.Close() // stop serving HTTP/1 and HTTP/2 too
+	}()
+	err = s.ServeTLS(ln, certFile, keyFile)
+	if err == ErrServerClosed {
+		// HTTP/3 connections are shut down in the background.
+		return err
+	}
+	select {
+	case err3 := <-errc:
+		// Serving HTTP/3 failed, and closed ln.
+		err = err3
+	default:
+	}
+	pc.Close()
+	return err
 }
 
 // setupHTTP2_ServeTLS conditionally configures HTTP/2 on
@@ -0,0 +1,71 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse_test
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/sse"
+	"strings"
+	"time"
+)
+
+func ExampleWriter() {
+	http.HandleFunc("/events", func(rw http.ResponseWriter, r *http.Request) {
+		w := sse.NewWriter(rw, r)
+		defer w.Close()
+		w.SetHeartbeat(15 * time.Second)
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-r.Context().Done():
+				return
+			case t := <-ticker.C:
+				err := w.Send(sse.Event{
+					ID:   fmt.Sprint(i),
+					Type: "tick",
+					Data: t.Format(time.RFC3339),
+				})
+				if err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+func ExampleReader() {
+	stream := "event: greeting\ndata: hello\ndata: world\n\n: a comment\nid: 42\ndata: second event\n\n"
+	r := sse.NewReader(strings.NewReader(stream))
+	for {
+		e, err := r.Next()
+		if err != nil {
+			break
+		}
+		fmt.Printf("%q %q %q\n", e.ID, e.Type, e.Data)
+	}
+	// Output:
+	// "" "greeting" "hello\nworld"
+	// "42" "" "second event"
+}
+
+func ExampleStream() {
+	req, err := http.NewRequest("GET", "https://example.com/events", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s := sse.NewStream(nil, req)
+	defer s.Close()
+	for {
+		e, err := s.Next()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(e.Type, e.Data)
+	}
+}
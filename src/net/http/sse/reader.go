@@ -0,0 +1,161 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxEventSize is the default maximum size of an event read by
+// a [Reader] or a [Stream].
+const DefaultMaxEventSize = 1 << 20 // 1 MB
+
+// ErrEventTooLarge is returned by [Reader.Next] and [Stream.Next] when
+// an event is larger than the maximum event size.
+var ErrEventTooLarge = errors.New("sse: event too large")
+
+// A Reader reads server-sent events from an event stream.
+type Reader struct {
+	// MaxEventSize is the maximum size of an event, in bytes: the
+	// total length of the lines of the event, other than comments.
+	// No line, including comments, may be longer. If zero,
+	// DefaultMaxEventSize is used.
+	MaxEventSize int
+
+	br      *bufio.Reader
+	started bool // the byte order mark, if any, has been skipped
+	skipLF  bool // the last line ended with CR; skip a following LF
+	line    []byte
+
+	lastID string
+	retry  time.Duration
+}
+
+// NewReader returns a new [Reader] reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{br: bufio.NewReader(r)}
+}
+
+// Next returns the next event in the stream. At the end of the stream
+// it returns [io.EOF]. An event that is not terminated by a blank line
+// before the end of the stream is discarded, as required by the
+// specification.
+//
+// If an event is larger than MaxEventSize, Next returns
+// [ErrEventTooLarge], and the Reader can't be used any more.
+func (r *Reader) Next() (Event, error) {
+	maxSize := r.MaxEventSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxEventSize
+	}
+	var (
+		data    strings.Builder
+		hasData bool
+		typ     string
+		retry   time.Duration
+		size    int
+	)
+	for {
+		line, err := r.readLine(maxSize - size)
+		if err != nil {
+			return Event{}, err
+		}
+		if len(line) == 0 {
+			if !hasData {
+				// No event to dispatch.
+				typ, retry, size = "", 0, 0
+				continue
+			}
+			return Event{
+				ID:    r.lastID,
+				Type:  typ,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+				Retry: retry,
+			}, nil
+		}
+		if line[0] == ':' {
+			// A comment.
+			continue
+		}
+		size += len(line)
+		field, value, _ := strings.Cut(string(line), ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			typ = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				r.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				retry = time.Duration(min(ms, uint64(1<<63-1)/uint64(time.Millisecond))) * time.Millisecond
+				r.retry = retry
+			}
+		}
+	}
+}
+
+// LastEventID returns the most recent event ID read from the stream.
+func (r *Reader) LastEventID() string {
+	return r.lastID
+}
+
+// Retry returns the most recent reconnection time read from the stream,
+// or zero if the stream has not set one.
+func (r *Reader) Retry() time.Duration {
+	return r.retry
+}
+
+// readLine reads a line ending in CRLF, LF or CR, of at most maxLen
+// bytes. The returned slice is only valid until the next call.
+func (r *Reader) readLine(maxLen int) ([]byte, error) {
+	if !r.started {
+		r.started = true
+		// Skip a UTF-8 byte order mark. Peek at the first byte only,
+		// to avoid waiting for more data than a short first line.
+		if b, err := r.br.Peek(1); err == nil && b[0] == 0xef {
+			if b, err := r.br.Peek(3); err == nil && string(b) == "\xef\xbb\xbf" {
+				r.br.Discard(3)
+			}
+		}
+	}
+	line := r.line[:0]
+	for {
+		c, err := r.br.ReadByte()
+		if err != nil {
+			r.line = line
+			return nil, err
+		}
+		if r.skipLF {
+			r.skipLF = false
+			if c == '\n' {
+				continue
+			}
+		}
+		switch c {
+		case '\r':
+			r.skipLF = true
+			fallthrough
+		case '\n':
+			r.line = line
+			return line, nil
+		}
+		if len(line) >= maxLen {
+			r.line = line
+			return nil, ErrEventTooLarge
+		}
+		line = append(line, c)
+	}
+}
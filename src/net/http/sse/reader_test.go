@@ -0,0 +1,197 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func readAll(t *testing.T, r io.Reader) []Event {
+	t.Helper()
+	sr := NewReader(r)
+	var events []Event
+	for {
+		e, err := sr.Next()
+		if err == io.EOF {
+			return events
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+}
+
+var readerTests = []struct {
+	name string
+	in   string
+	want []Event
+}{
+	{
+		name: "simple",
+		in:   "data: hello\n\n",
+		want: []Event{{Data: "hello"}},
+	},
+	{
+		name: "multiline",
+		in:   "data: YHOO\ndata: +2\ndata: 10\n\n",
+		want: []Event{{Data: "YHOO\n+2\n10"}},
+	},
+	{
+		name: "fields",
+		in:   "id: 1\nevent: add\nretry: 1500\ndata: x\n\n",
+		want: []Event{{ID: "1", Type: "add", Data: "x", Retry: 1500 * time.Millisecond}},
+	},
+	{
+		name: "id persists",
+		in:   "id: 7\ndata: a\n\ndata: b\n\nid\ndata: c\n\n",
+		want: []Event{{ID: "7", Data: "a"}, {ID: "7", Data: "b"}, {Data: "c"}},
+	},
+	{
+		name: "comments and unknown fields",
+		in:   ": comment\nfoo: bar\ndata: x\n:another\n\n",
+		want: []Event{{Data: "x"}},
+	},
+	{
+		name: "no space after colon",
+		in:   "data:x\ndata:  y\ndata\n\n",
+		want: []Event{{Data: "x\n y\n"}},
+	},
+	{
+		name: "line endings",
+		in:   "data: a\r\ndata: b\rdata: c\n\r\ndata: d\r\r",
+		want: []Event{{Data: "a\nb\nc"}, {Data: "d"}},
+	},
+	{
+		name: "no data",
+		in:   "event: nothing\n\nid: 3\n\ndata: x\n\n",
+		want: []Event{{ID: "3", Data: "x"}},
+	},
+	{
+		name: "type reset",
+		in:   "event: a\ndata: 1\n\ndata: 2\n\n",
+		want: []Event{{Type: "a", Data: "1"}, {Data: "2"}},
+	},
+	{
+		name: "invalid retry",
+		in:   "retry: 1s\ndata: x\n\nretry: -5\ndata: y\n\n",
+		want: []Event{{Data: "x"}, {Data: "y"}},
+	},
+	{
+		name: "id with NUL ignored",
+		in:   "id: a\x00b\ndata: x\n\n",
+		want: []Event{{Data: "x"}},
+	},
+	{
+		name: "byte order mark",
+		in:   "\xef\xbb\xbfdata: x\n\n",
+		want: []Event{{Data: "x"}},
+	},
+	{
+		name: "incomplete event discarded",
+		in:   "data: x\n\ndata: y\n",
+		want: []Event{{Data: "x"}},
+	},
+	{
+		name: "empty data",
+		in:   "data\n\ndata:\n\n",
+		want: []Event{{Data: ""}, {Data: ""}},
+	},
+}
+
+func TestReader(t *testing.T) {
+	for _, test := range readerTests {
+		t.Run(test.name, func(t *testing.T) {
+			got := readAll(t, strings.NewReader(test.in))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got  %+v\nwant %+v", got, test.want)
+			}
+			// Reading one byte at a time must give the same result.
+			got = readAll(t, iotest.OneByteReader(strings.NewReader(test.in)))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("one byte at a time: got  %+v\nwant %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReaderDoesNotWaitAfterCR(t *testing.T) {
+	// An event ending with CR must be returned without
+	// waiting for the next byte, which may be a LF.
+	pr, pw := io.Pipe()
+	go pw.Write([]byte("data: x\r\r"))
+	r := NewReader(pr)
+	e, err := r.Next()
+	if err != nil || e.Data != "x" {
+		t.Fatalf("Next = %+v, %v", e, err)
+	}
+	go pw.Write([]byte("\ndata: y\r\n\r\n"))
+	e, err = r.Next()
+	if err != nil || e.Data != "y" {
+		t.Fatalf("Next = %+v, %v", e, err)
+	}
+	pw.Close()
+}
+
+func TestReaderState(t *testing.T) {
+	r := NewReader(strings.NewReader("id: 5\nretry: 200\ndata: x\n\n"))
+	if _, err := r.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.LastEventID(); got != "5" {
+		t.Errorf("LastEventID = %q, want 5", got)
+	}
+	if got := r.Retry(); got != 200*time.Millisecond {
+		t.Errorf("Retry = %v, want 200ms", got)
+	}
+}
+
+func TestReaderMaxEventSize(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		ok   bool
+	}{
+		{"fits", "data: 12345\n\n", true},
+		{"long line", "data: 123456\n\n", false},
+		{"long comment", ": 1234567890\n\n", false},
+		{"many lines", "data\ndata\ndata\n\n", false},
+		{"many events", "data\n\ndata\n\ndata\n\n", true},
+		{"many comments", ":\n:\n:\n:\ndata: 1234\n\n", true},
+	} {
+		r := NewReader(strings.NewReader(test.in))
+		r.MaxEventSize = len("data: 12345")
+		var err error
+		for err == nil {
+			_, err = r.Next()
+		}
+		if test.ok && err != io.EOF || !test.ok && err != ErrEventTooLarge {
+			t.Errorf("%s: Next = %v", test.name, err)
+		}
+	}
+}
+
+func FuzzReader(f *testing.F) {
+	for _, test := range readerTests {
+		f.Add(test.in)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		r := NewReader(strings.NewReader(in))
+		for {
+			e, err := r.Next()
+			if err != nil {
+				break
+			}
+			if strings.ContainsAny(e.ID, "\r\n\x00") || strings.ContainsAny(e.Type, "\r\n") {
+				t.Fatalf("invalid event %+v", e)
+			}
+		}
+	})
+}
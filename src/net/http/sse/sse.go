@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sse implements server-sent events, as specified in the
+// HTML Living Standard, section 9.2.
+//
+// A server-sent event stream is a long-lived HTTP response with the
+// content type text/event-stream, over which a server sends a series
+// of events to a client. A [Writer] sends events from an HTTP handler.
+// A [Reader] parses the events of a stream, and a [Stream] receives
+// events from a server, reconnecting when the connection is lost.
+//
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html.
+package sse
+
+import (
+	"errors"
+	"time"
+)
+
+// ContentType is the media type of an event stream.
+const ContentType = "text/event-stream"
+
+// An Event is a server-sent event.
+type Event struct {
+	// ID is the event's ID. A client that reconnects to a stream
+	// sends the ID of the last event it received, so that the server
+	// can resume the stream. When reading, ID is the most recent ID
+	// sent in the stream, which may have been set by an earlier event.
+	ID string
+
+	// Type is the type of the event.
+	// If empty, the type is "message".
+	Type string
+
+	// Data is the payload of the event. It may contain newlines.
+	Data string
+
+	// Retry is the time a client should wait before reconnecting
+	// after losing its connection to the stream. It is sent with
+	// millisecond precision. If zero, the event does not change the
+	// reconnection time.
+	Retry time.Duration
+}
+
+var errInvalidField = errors.New("sse: event ID or type contains a newline")
@@ -0,0 +1,211 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrStreamClosed is returned by [Stream.Next] after the stream
+// has been closed.
+var ErrStreamClosed = errors.New("sse: Stream closed")
+
+// defaultRetry is the reconnection time of a Stream
+// until the server sets one.
+const defaultRetry = 3 * time.Second
+
+// A Stream receives server-sent events from a server, as an
+// EventSource does in a web browser.
+//
+// When the connection to the server is lost, a Stream reconnects after
+// the reconnection time set by the server, or 3 seconds if the server
+// has not set one. It sends the ID of the last event it received in
+// the Last-Event-ID header, so that the server can resume the stream.
+type Stream struct {
+	// MaxEventSize is the maximum size of an event,
+	// as for [Reader.MaxEventSize].
+	MaxEventSize int
+
+	client *http.Client
+	req    *http.Request
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	resp   *http.Response
+	r      *Reader
+	lastID string
+	retry  time.Duration
+}
+
+// NewStream returns a [Stream] that receives events by sending req
+// with client. If client is nil, [http.DefaultClient] is used.
+// The request must not have a body. Its context controls the lifetime
+// of the stream, including any reconnections.
+//
+// The first connection is made by the first call to [Stream.Next].
+// If req has a Last-Event-ID header, it is sent with the first
+// connection.
+func NewStream(client *http.Client, req *http.Request) *Stream {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithCancelCause(req.Context())
+	return &Stream{
+		client: client,
+		req:    req,
+		ctx:    ctx,
+		cancel: cancel,
+		lastID: req.Header.Get("Last-Event-ID"),
+		retry:  defaultRetry,
+	}
+}
+
+// Next returns the next event from the server, connecting or
+// reconnecting to it if necessary.
+//
+// Next reconnects after network errors, such as a failure to connect,
+// other than TLS handshake failures. It does not reconnect, and returns
+// an error, if sending the request fails with a TLS handshake failure
+// or another error, if the server responds with a status other
+// than 200 (OK) or with a content type other than text/event-stream,
+// or if it sends an event larger than MaxEventSize, in which case the
+// error is [ErrEventTooLarge]. If the server responds with 204 (No
+// Content), to tell the client to stop reconnecting, Next returns
+// [io.EOF].
+// If the request's context is done, Next returns the context's error.
+// After the stream is closed, Next returns [ErrStreamClosed].
+func (s *Stream) Next() (Event, error) {
+	for {
+		if s.r == nil {
+			if err := s.connect(); err != nil {
+				return Event{}, err
+			}
+		}
+		e, err := s.r.Next()
+		s.lastID = s.r.LastEventID()
+		if d := s.r.Retry(); d > 0 {
+			s.retry = d
+		}
+		if err == nil {
+			return e, nil
+		}
+		s.resp.Body.Close()
+		s.resp, s.r = nil, nil
+		if err == ErrEventTooLarge {
+			return Event{}, err
+		}
+
+		// The connection was lost.
+		if err := s.wait(); err != nil {
+			return Event{}, err
+		}
+	}
+}
+
+// connect connects to the server, retrying after network errors.
+// Other errors, such as an invalid URL, are returned.
+func (s *Stream) connect() error {
+	for {
+		if s.ctx.Err() != nil {
+			return context.Cause(s.ctx)
+		}
+		req := s.req.Clone(s.ctx)
+		req.Header.Set("Accept", ContentType)
+		req.Header.Set("Cache-Control", "no-cache")
+		if s.lastID != "" {
+			req.Header.Set("Last-Event-ID", s.lastID)
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return context.Cause(s.ctx)
+			}
+			if !shouldReconnect(err) {
+				return err
+			}
+			if err := s.wait(); err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case resp.StatusCode == http.StatusNoContent:
+			resp.Body.Close()
+			return io.EOF
+		case resp.StatusCode != http.StatusOK:
+			resp.Body.Close()
+			return fmt.Errorf("sse: unexpected response status %q", resp.Status)
+		}
+		if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != ContentType {
+			resp.Body.Close()
+			return fmt.Errorf("sse: unexpected response content type %q", resp.Header.Get("Content-Type"))
+		}
+		s.resp = resp
+		s.r = NewReader(resp.Body)
+		s.r.MaxEventSize = s.MaxEventSize
+		s.r.lastID = s.lastID
+		return nil
+	}
+}
+
+// shouldReconnect reports whether the stream should reconnect after err,
+// returned by [http.Client.Do].
+//
+// Like an EventSource, a Stream reestablishes the connection after a
+// network error, unless the request was aborted or reconnecting is known
+// to be futile. Network errors are those of the connection to the server:
+// a [net.Error], or the connection closing before the response. Reconnecting
+// is futile if the server rejected the TLS handshake, or if its certificate
+// couldn't be verified, since the next attempt would fail in the same way.
+// Errors that happen before connecting, such as an unsupported URL scheme
+// or an error from the client's CheckRedirect function, are not network
+// errors.
+func shouldReconnect(err error) bool {
+	if ue, ok := err.(*url.Error); ok {
+		// A *url.Error is itself a net.Error.
+		err = ue.Err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// Aborted.
+		return false
+	}
+	var (
+		alertErr tls.AlertError
+		certErr  *tls.CertificateVerificationError
+	)
+	if errors.As(err, &alertErr) || errors.As(err, &certErr) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// wait waits for the reconnection time.
+func (s *Stream) wait() error {
+	t := time.NewTimer(s.retry)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-s.ctx.Done():
+		return context.Cause(s.ctx)
+	}
+}
+
+// Close closes the stream and its connection to the server.
+// It interrupts any call to [Stream.Next].
+func (s *Stream) Close() error {
+	s.cancel(ErrStreamClosed)
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamReconnect(t *testing.T) {
+	var conns atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		n := conns.Add(1)
+		if got := r.Header.Get("Accept"); got != ContentType {
+			t.Errorf("Accept = %q, want %q", got, ContentType)
+		}
+		start := 0
+		if id := r.Header.Get("Last-Event-ID"); id != "" {
+			if n == 1 {
+				t.Errorf("first connection has Last-Event-ID %q", id)
+			}
+			start, _ = strconv.Atoi(id)
+			start++
+		} else if n > 1 {
+			t.Errorf("connection %d has no Last-Event-ID", n)
+		}
+		if start >= 6 {
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w := NewWriter(rw, r)
+		// Send two events per connection.
+		for i := start; i < start+2; i++ {
+			w.Send(Event{ID: strconv.Itoa(i), Data: "event " + strconv.Itoa(i), Retry: time.Millisecond})
+		}
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	s := NewStream(ts.Client(), req)
+	defer s.Close()
+	for i := range 6 {
+		e, err := s.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "event " + strconv.Itoa(i); e.Data != want || e.ID != strconv.Itoa(i) {
+			t.Errorf("event %d = %+v, want ID %d, Data %q", i, e, i, want)
+		}
+	}
+	if _, err := s.Next(); err != io.EOF {
+		t.Errorf("Next after 204 = %v, want io.EOF", err)
+	}
+	if got := conns.Load(); got != 4 {
+		t.Errorf("server saw %d connections, want 4", got)
+	}
+}
+
+func TestStreamBadResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status":
+			http.Error(rw, "nope", http.StatusNotFound)
+		case "/type":
+			rw.Header().Set("Content-Type", "text/plain")
+			io.WriteString(rw, "data: x\n\n")
+		}
+	}))
+	defer ts.Close()
+	for _, path := range []string{"/status", "/type"} {
+		req, _ := http.NewRequest("GET", ts.URL+path, nil)
+		s := NewStream(ts.Client(), req)
+		if _, err := s.Next(); err == nil || !strings.HasPrefix(err.Error(), "sse: unexpected") {
+			t.Errorf("%v: Next = %v, want unexpected response error", path, err)
+		}
+		s.Close()
+	}
+}
+
+func TestStreamPermanentError(t *testing.T) {
+	// Errors other than network errors are not retried.
+	req, _ := http.NewRequest("GET", "nope://example.com/", nil)
+	s := NewStream(nil, req)
+	defer s.Close()
+	if _, err := s.Next(); err == nil || !strings.Contains(err.Error(), "unsupported protocol scheme") {
+		t.Errorf("Next = %v, want unsupported protocol scheme error", err)
+	}
+}
+
+func TestStreamCertificateError(t *testing.T) {
+	// A certificate that can't be verified is not retried.
+	ts := httptest.NewUnstartedServer(http.NotFoundHandler())
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0) // quiet for tests
+	ts.StartTLS()
+	defer ts.Close()
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	s := NewStream(&http.Client{}, req)
+	defer s.Close()
+	_, err := s.Next()
+	if _, ok := errors.AsType[*tls.CertificateVerificationError](err); !ok {
+		t.Errorf("Next = %v, want CertificateVerificationError", err)
+	}
+}
+
+func TestStreamEventTooLarge(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w := NewWriter(rw, r)
+		w.Send(Event{Data: "small"})
+		w.Send(Event{Data: strings.Repeat("x", 100)})
+	}))
+	defer ts.Close()
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	s := NewStream(ts.Client(), req)
+	s.MaxEventSize = 50
+	defer s.Close()
+	if e, err := s.Next(); err != nil || e.Data != "small" {
+		t.Fatalf("Next = %+v, %v", e, err)
+	}
+	if _, err := s.Next(); err != ErrEventTooLarge {
+		t.Errorf("Next = %v, want ErrEventTooLarge", err)
+	}
+}
+
+func TestStreamClose(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		NewWriter(rw, r).Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	s := NewStream(ts.Client(), req)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		s.Close()
+	}()
+	if _, err := s.Next(); err != ErrStreamClosed {
+		t.Errorf("Next = %v, want ErrStreamClosed", err)
+	}
+	if _, err := s.Next(); err != ErrStreamClosed {
+		t.Errorf("Next after Close = %v, want ErrStreamClosed", err)
+	}
+
+	// Cancelling the request's context also ends the stream.
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ = http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	s = NewStream(ts.Client(), req)
+	cancel()
+	if _, err := s.Next(); err != context.Canceled {
+		t.Errorf("Next = %v, want context.Canceled", err)
+	}
+}
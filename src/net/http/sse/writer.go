@@ -0,0 +1,229 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrWriterClosed is returned by the methods of a [Writer]
+// after it has been closed.
+var ErrWriterClosed = errors.New("sse: Writer closed")
+
+// A Writer writes server-sent events to an HTTP response.
+//
+// Each event is flushed to the client as soon as it is written.
+// The methods of a Writer may be called concurrently.
+type Writer struct {
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	ctx context.Context // request context
+
+	mu        sync.Mutex
+	buf       []byte
+	closed    bool
+	heartbeat time.Duration
+	timer     *time.Timer
+	stopTimer func() bool // stops the context.AfterFunc that stops timer
+	err       error
+}
+
+// NewWriter returns a [Writer] that writes events to w, the response
+// to the request r.
+//
+// NewWriter sets the Content-Type header of the response to
+// text/event-stream and the Cache-Control header to no-cache,
+// unless they are already set. The response header is written with
+// the first event, or by [Writer.Flush].
+func NewWriter(w http.ResponseWriter, r *http.Request) *Writer {
+	h := w.Header()
+	if _, ok := h["Content-Type"]; !ok {
+		h.Set("Content-Type", ContentType)
+	}
+	if _, ok := h["Cache-Control"]; !ok {
+		h.Set("Cache-Control", "no-cache")
+	}
+	return &Writer{w: w, rc: http.NewResponseController(w), ctx: r.Context()}
+}
+
+// Send writes e to the stream and flushes it to the client.
+// It returns an error if e's ID or Type contains a carriage return
+// or line feed, or if e's ID contains a NUL character.
+func (w *Writer) Send(e Event) error {
+	if strings.ContainsAny(e.ID, "\r\n\x00") || strings.ContainsAny(e.Type, "\r\n") {
+		return errInvalidField
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b := w.buf[:0]
+	if e.ID != "" {
+		b = appendField(b, "id", e.ID)
+	}
+	if e.Type != "" {
+		b = appendField(b, "event", e.Type)
+	}
+	if e.Retry > 0 {
+		b = appendField(b, "retry", strconv.FormatInt(e.Retry.Milliseconds(), 10))
+	}
+	data := e.Data
+	for {
+		line, rest, ok := cutLine(data)
+		b = appendField(b, "data", line)
+		if !ok {
+			break
+		}
+		data = rest
+	}
+	b = append(b, '\n')
+	w.buf = b
+	return w.writeLocked(b)
+}
+
+// Comment writes a comment to the stream and flushes it to the client.
+// Clients ignore comments. Each line of text is sent as a separate
+// comment line.
+func (w *Writer) Comment(text string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b := w.buf[:0]
+	for {
+		line, rest, ok := cutLine(text)
+		b = append(b, ':')
+		if line != "" {
+			b = append(b, ' ')
+			b = append(b, line...)
+		}
+		b = append(b, '\n')
+		if !ok {
+			break
+		}
+		text = rest
+	}
+	w.buf = b
+	return w.writeLocked(b)
+}
+
+// Flush writes the response header, if it has not been written yet,
+// and flushes any buffered data to the client.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writeLocked(nil)
+}
+
+// SetHeartbeat arranges for an empty comment to be sent to the client
+// whenever no event or comment has been written for the duration d.
+// Heartbeats keep idle connections from being closed by intermediaries,
+// and let the server detect clients that have gone away.
+// A zero or negative d disables heartbeats, which are disabled by default.
+//
+// Heartbeats are sent by another goroutine. They stop when w is closed,
+// or when the request's context is done, which happens when the client
+// goes away or the handler returns.
+func (w *Writer) SetHeartbeat(d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.heartbeat = d
+	w.resetTimerLocked()
+}
+
+// Close stops the heartbeats of w. Later calls to the methods of w
+// return [ErrWriterClosed]. Close does not end the response, which
+// ends when the handler returns.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	w.stopHeartbeatLocked()
+	return nil
+}
+
+func (w *Writer) resetTimerLocked() {
+	if w.closed {
+		return
+	}
+	switch {
+	case w.heartbeat <= 0:
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+	case w.timer == nil:
+		w.timer = time.AfterFunc(w.heartbeat, w.sendHeartbeat)
+		w.stopTimer = context.AfterFunc(w.ctx, func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			w.stopHeartbeatLocked()
+		})
+	default:
+		w.timer.Reset(w.heartbeat)
+	}
+}
+
+// stopHeartbeatLocked stops sending heartbeats, for good.
+func (w *Writer) stopHeartbeatLocked() {
+	w.heartbeat = 0
+	if w.timer != nil {
+		w.timer.Stop()
+		w.stopTimer()
+	}
+}
+
+func (w *Writer) sendHeartbeat() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// The handler may have returned, and the response be finished, before
+	// the context.AfterFunc stopping the timer runs.
+	if w.closed || w.err != nil || w.heartbeat <= 0 || w.ctx.Err() != nil {
+		return
+	}
+	w.writeLocked([]byte(":\n"))
+}
+
+// writeLocked writes b and flushes the response.
+func (w *Writer) writeLocked(b []byte) error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if len(b) > 0 {
+		if _, err := w.w.Write(b); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	if err := w.rc.Flush(); err != nil {
+		w.err = err
+		return err
+	}
+	w.resetTimerLocked()
+	return nil
+}
+
+func appendField(b []byte, name, value string) []byte {
+	b = append(b, name...)
+	b = append(b, ": "...)
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// cutLine slices s around the first line ending: CRLF, LF or CR.
+func cutLine(s string) (line, rest string, found bool) {
+	i := strings.IndexAny(s, "\r\n")
+	if i < 0 {
+		return s, "", false
+	}
+	if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+		return s[:i], s[i+2:], true
+	}
+	return s[:i], s[i+1:], true
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec, httptest.NewRequest("GET", "/", nil))
+	events := []Event{
+		{Data: "hello"},
+		{ID: "1", Type: "update", Data: "line 1\nline 2\r\nline 3\rline 4"},
+		{ID: "2", Retry: 2500 * time.Millisecond, Data: ""},
+	}
+	for _, e := range events {
+		if err := w.Send(e); err != nil {
+			t.Fatal(err)
+		}
+		if !rec.Flushed {
+			t.Errorf("Send did not flush")
+		}
+		rec.Flushed = false
+	}
+	if err := w.Comment("a comment\nacross lines"); err != nil {
+		t.Fatal(err)
+	}
+	const want = "data: hello\n\n" +
+		"id: 1\nevent: update\ndata: line 1\ndata: line 2\ndata: line 3\ndata: line 4\n\n" +
+		"id: 2\nretry: 2500\ndata: \n\n" +
+		": a comment\n: across lines\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("stream:\n%q\nwant:\n%q", got, want)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+
+	got := readAll(t, strings.NewReader(rec.Body.String()))
+	events[1].Data = "line 1\nline 2\nline 3\nline 4"
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("read back %+v\nwant %+v", got, events)
+	}
+}
+
+func TestWriterInvalid(t *testing.T) {
+	w := NewWriter(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	for _, e := range []Event{
+		{ID: "a\nb"},
+		{ID: "a\x00b"},
+		{Type: "a\rb"},
+	} {
+		if err := w.Send(e); err == nil {
+			t.Errorf("Send(%+v) succeeded", e)
+		}
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w := NewWriter(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	w.Close()
+	if err := w.Send(Event{Data: "x"}); err != ErrWriterClosed {
+		t.Errorf("Send after Close = %v, want ErrWriterClosed", err)
+	}
+}
+
+func TestWriterHeartbeat(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w := NewWriter(rw, r)
+		defer w.Close()
+		w.SetHeartbeat(time.Millisecond)
+		if err := w.Flush(); err != nil {
+			t.Error(err)
+		}
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	res, err := ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	br := bufio.NewReader(res.Body)
+	for range 3 {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line != ":\n" {
+			t.Fatalf("got %q, want heartbeat", line)
+		}
+	}
+}
+
+func TestWriterHeartbeatStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec, httptest.NewRequestWithContext(ctx, "GET", "/", nil))
+	w.SetHeartbeat(time.Millisecond)
+	cancel()
+
+	// Heartbeats stop without w being closed.
+	time.Sleep(10 * time.Millisecond)
+	w.mu.Lock()
+	n := rec.Body.Len()
+	w.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if rec.Body.Len() != n {
+		t.Errorf("heartbeats sent after the request's context was done")
+	}
+}
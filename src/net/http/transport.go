@@ -13,6 +13,7 @@ import (
 	"bufio"
 	"compress/flate"
 	"compress/gzip"
+	"compress/zstd"
 	"container/list"
 	"context"
 	"crypto/tls"
@@ -25,7 +26,7 @@ import (
 	"net"
 	"net/http/httptrace"
 	"net/http/internal"
-	"net/http/internal/ascii"
+	"net/http/internal/httpcommon"
 	"net/textproto"
 	"net/url"
 	"reflect"
@@ -198,13 +199,16 @@ type Transport struct {
 	DisableKeepAlives bool
 
 	// DisableCompression, if true, prevents the Transport from
-	// requesting compression with an "Accept-Encoding: gzip"
+	// requesting compression with an "Accept-Encoding: gzip, zstd"
 	// request header when the Request contains no existing
-	// Accept-Encoding value. If the Transport requests gzip on
-	// its own and gets a gzipped response, it's transparently
-	// decoded in the Response.Body. However, if the user
-	// explicitly requested gzip it is not automatically
+	// Accept-Encoding value. If the Transport requests compression on
+	// its own and gets a gzip or zstd compressed response, it's
+	// transparently decoded in the Response.Body. However, if the user
+	// explicitly requested compression it is not automatically
 	// uncompressed.
+	//
+	// Setting GODEBUG=httpzstd=0 restricts the compression requested
+	// and decoded by the Transport to gzip.
 	DisableCompression bool
 
 	// MaxIdleConns controls the maximum number of idle (keep-alive)
@@ -299,6 +303,9 @@ type Transport struct {
 	h3Transport        dialClientConner // non-nil if http3 wired up
 	tlsNextProtoWasNil bool             // whether TLSNextProto was nil when the Once fired
 
+	altSvcMu sync.Mutex
+	altSvc   map[string]altSvcEntry // HTTP/3 alternatives, keyed by origin "host:port"
+
 	// ForceAttemptHTTP2 controls whether HTTP/2 is enabled when a non-zero
 	// Dial, DialTLS, or DialContext func or TLSClientConfig is provided.
 	// By default, use of any those fields conservatively disables HTTP/2.
@@ -439,6 +446,14 @@ var http2client = godebug.New("http2client")
 // It must be called via t.nextProtoOnce.Do.
 func (t *Transport) onceSetNextProtoDefaults() {
 	t.tlsNextProtoWasNil = (t.TLSNextProto == nil)
+	if t.protocols().http3() && t.h3Transport == nil {
+		// Use the bundled HTTP/3 implementation.
+		// It requires a non-nil TLSClientConfig.
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		http3RegisterTransport(t, http3TransportOpts{})
+	}
 	if http2client.Value() == "0" {
 		http2client.IncNonDefault()
 		return
@@ -593,9 +608,11 @@ func (t *Transport) alternateRoundTripper(req *Request) RoundTripper {
 	return altProto[req.URL.Scheme]
 }
 
-func validateHeaders(hdrs Header) string {
+// validateHeaders reports the first invalid field in hdrs.
+// The :protocol pseudo-header is permitted if allowProtocol is set.
+func validateHeaders(hdrs Header, allowProtocol bool) string {
 	for k, vv := range hdrs {
-		if !httpguts.ValidHeaderFieldName(k) {
+		if !httpguts.ValidHeaderFieldName(k) && !(allowProtocol && k == ":protocol") {
 			return fmt.Sprintf("field name %q", k)
 		}
 		for _, v := range vv {
@@ -627,13 +644,13 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 	isHTTP := scheme == "http" || scheme == "https"
 	if isHTTP {
 		// Validate the outgoing headers.
-		if err := validateHeaders(req.Header); err != "" {
+		if err := validateHeaders(req.Header, isExtendedConnect(req)); err != "" {
 			req.closeBody()
 			return nil, fmt.Errorf("net/http: invalid header %s", err)
 		}
 
 		// Validate the outgoing trailers too.
-		if err := validateHeaders(req.Trailer); err != "" {
+		if err := validateHeaders(req.Trailer, false); err != "" {
 			req.closeBody()
 			return nil, fmt.Errorf("net/http: invalid trailer %s", err)
 		}
@@ -642,7 +659,7 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 	origReq := req
 	req = setupRewindBody(req)
 
-	if altRT := t.alternateRoundTripper(req); altRT != nil {
+	if altRT := t.alternateRoundTripper(req); altRT != nil && !t.hasAltSvcHTTP3(req.URL) {
 		if resp, err := altRT.RoundTrip(req); err != ErrSkipAltProtocol {
 			return resp, err
 		}
@@ -719,6 +736,13 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 			return nil, err
 		}
 
+		if pconn.alt == nil && isExtendedConnect(req) {
+			// Extended CONNECT (RFC 8441) has no HTTP/1 equivalent.
+			t.putOrCloseIdleConn(pconn)
+			req.closeBody()
+			return nil, internal.ErrExtendedConnectHTTP1
+		}
+
 		var resp *Response
 		if pconn.alt != nil {
 			// HTTP/2 path.
@@ -727,6 +751,9 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 			resp, err = pconn.roundTrip(treq)
 		}
 		if err == nil {
+			if resp.ProtoMajor < 3 {
+				t.noteAltSvc(cm, resp.Header)
+			}
 			if pconn.alt != nil {
 				// HTTP/2 requests are not cancelable with CancelRequest,
 				// so we have no further need for the request context.
@@ -771,6 +798,12 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 	}
 }
 
+// isExtendedConnect reports whether req is an extended CONNECT
+// request (RFC 8441), which sets the :protocol pseudo-header.
+func isExtendedConnect(req *Request) bool {
+	return req.Method == "CONNECT" && len(req.Header[":protocol"]) > 0
+}
+
 func http2isNoCachedConnError(err error) bool {
 	_, ok := err.(interface{ IsHTTP2NoCachedConnError() })
 	return ok
@@ -1068,6 +1101,9 @@ func (t *Transport) connectMethodForRequest(treq *transportRequest) (cm connectM
 		cm.proxyURL, err = t.Proxy(treq.Request)
 	}
 	cm.onlyH1 = treq.requiresHTTP1()
+	if err == nil && cm.proxyURL == nil && cm.targetScheme == "https" && !cm.onlyH1 {
+		cm.h3Addr = t.altSvcHTTP3(cm.targetAddr)
+	}
 	return cm, err
 }
 
@@ -1859,31 +1895,27 @@ type erringRoundTripper interface {
 var testHookProxyConnectTimeout = context.WithTimeout
 
 func (t *Transport) dialConn(ctx context.Context, cm connectMethod, isClientConn bool, internalStateHook func()) (pconn *persistConn, err error) {
-	// TODO: actually support HTTP/3. Among other things:
+	// TODO: improve HTTP/3 support. Among other things:
 	// - make HTTP/3 play well with proxy.
 	// - implement happy eyeball between HTTP/3 and HTTP/1 & HTTP/2.
 	// - clean up the connection pooling logic.
-	if p := t.protocols(); p.http3() {
-		if p.HTTP1() || p.HTTP2() || p.UnencryptedHTTP2() {
-			return nil, errors.New("http: when using HTTP3, Transport.Protocols must contain only HTTP3")
-		}
-		if t.h3Transport == nil {
-			return nil, errors.New("http: Transport.Protocols contains HTTP3, but Transport does not support HTTP/3")
-		}
-		tlsConfig, err := t.tlsConfigForDial(cm.addr())
-		if err != nil {
-			return nil, err
+	p := t.protocols()
+	onlyH3 := p.http3() && !p.HTTP1() && !p.HTTP2() && !p.UnencryptedHTTP2()
+	if onlyH3 {
+		return t.dialHTTP3(ctx, cm, cm.addr(), internalStateHook)
+	}
+	if cm.h3Addr != "" {
+		ctx3, cancel := context.WithTimeout(ctx, http3FallbackTimeout)
+		pconn, err := t.dialHTTP3(ctx3, cm, cm.h3Addr, internalStateHook)
+		cancel()
+		if err == nil {
+			return pconn, nil
 		}
-		tlsConfig.NextProtos = []string{"h3"}
-		rt, err := t.h3Transport.DialClientConn(ctx, cm.addr(), cm.proxyURL, tlsConfig, internalStateHook)
-		if err != nil {
+		if ctx.Err() != nil {
 			return nil, err
 		}
-		return &persistConn{
-			t:        t,
-			cacheKey: cm.key(),
-			alt:      rt,
-		}, nil
+		// The alternative doesn't work. Use HTTP/1 or HTTP/2 instead.
+		t.altSvcBroken(cm.targetAddr)
 	}
 
 	pconn = &persistConn{
@@ -2202,7 +2234,8 @@ type connectMethod struct {
 	// then targetAddr is not included in the connect method key, because the socket can
 	// be reused for different targetAddr values.
 	targetAddr string
-	onlyH1     bool // whether to disable HTTP/2 and force HTTP/1
+	onlyH1     bool   // whether to disable HTTP/2 and force HTTP/1
+	h3Addr     string // if non-empty, the address of an HTTP/3 alternative to try first
 }
 
 func (cm *connectMethod) key() connectMethodKey {
@@ -2219,6 +2252,7 @@ func (cm *connectMethod) key() connectMethodKey {
 		scheme: cm.targetScheme,
 		addr:   targetAddr,
 		onlyH1: cm.onlyH1,
+		h3:     cm.h3Addr != "",
 	}
 }
 
@@ -2244,6 +2278,7 @@ func (cm *connectMethod) addr() string {
 type connectMethodKey struct {
 	proxy, scheme, addr string
 	onlyH1              bool
+	h3                  bool // connections to an HTTP/3 alternative
 }
 
 func (k connectMethodKey) String() string {
@@ -2252,6 +2287,9 @@ func (k connectMethodKey) String() string {
 	if k.onlyH1 {
 		h1 = ",h1"
 	}
+	if k.h3 {
+		h1 += ",h3"
+	}
 	return fmt.Sprintf("%s|%s%s|%s", k.proxy, k.scheme, h1, k.addr)
 }
 
@@ -2602,8 +2640,8 @@ func (pc *persistConn) readLoop() {
 		}
 
 		resp.Body = body
-		if rc.addedGzip && ascii.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
-			resp.Body = &gzipReader{body: body}
+		if ok, zstd := httpcommon.IsResponseCompressed(resp.Header.Get("Content-Encoding")); rc.addedGzip && ok {
+			resp.Body = &gzipReader{body: body, zstd: zstd}
 			resp.Header.Del("Content-Encoding")
 			resp.Header.Del("Content-Length")
 			resp.ContentLength = -1
@@ -2913,8 +2951,8 @@ type requestAndChan struct {
 	ch   chan responseAndError // unbuffered; always send in select on callerGone
 
 	// whether the Transport (as opposed to the user client code)
-	// added the Accept-Encoding gzip header. If the Transport
-	// set it, only then do we transparently decode the gzip.
+	// added the Accept-Encoding header. If the Transport
+	// set it, only then do we transparently decode gzip or zstd.
 	addedGzip bool
 
 	// Optional blocking chan for Expect: 100-continue (for send).
@@ -3012,14 +3050,14 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 
 	// Ask for a compressed version if the caller didn't set their
 	// own value for Accept-Encoding. We only attempt to
-	// uncompress the gzip stream if we were the layer that
+	// uncompress the gzip or zstd stream if we were the layer that
 	// requested it.
 	requestedGzip := false
 	if !pc.t.DisableCompression &&
 		req.Header.Get("Accept-Encoding") == "" &&
 		req.Header.Get("Range") == "" &&
 		req.Method != "HEAD" {
-		// Request gzip only, not deflate. Deflate is ambiguous and
+		// Request gzip and zstd, not deflate. Deflate is ambiguous and
 		// not as universally supported anyway.
 		// See: https://zlib.net/zlib_faq.html#faq39
 		//
@@ -3028,11 +3066,11 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 		//   https://trac.nginx.org/nginx/ticket/358
 		//   https://golang.org/issue/5522
 		//
-		// We don't request gzip if the request is for a range, since
-		// auto-decoding a portion of a gzipped document will just fail
-		// anyway. See https://golang.org/issue/8923
+		// We don't request compression if the request is for a range,
+		// since auto-decoding a portion of a compressed document will
+		// just fail anyway. See https://golang.org/issue/8923
 		requestedGzip = true
-		req.extraHeaders().Set("Accept-Encoding", "gzip")
+		req.extraHeaders().Set("Accept-Encoding", httpcommon.AcceptEncoding())
 	}
 
 	var continueCh chan struct{}
@@ -3291,15 +3329,16 @@ func (es *bodyEOFSignal) condfn(err error) error {
 }
 
 // gzipReader wraps a response body so it can lazily
-// get gzip.Reader from the pool on the first call to Read.
-// After Close is called it puts gzip.Reader to the pool immediately
+// get a gzip.Reader or zstd.Reader from the pool on the first call to Read.
+// After Close is called it puts the reader to the pool immediately
 // if there is no Read in progress or later when Read completes.
 type gzipReader struct {
 	_    incomparable
-	body *bodyEOFSignal // underlying HTTP/1 response body framing
-	mu   sync.Mutex     // guards zr and zerr
-	zr   *gzip.Reader   // stores gzip reader from the pool between reads
-	zerr error          // sticky gzip reader init error or sentinel value to detect concurrent read and read after close
+	body io.ReadCloser // underlying HTTP/1 or HTTP/3 response body framing
+	zstd bool          // body is zstd rather than gzip compressed
+	mu   sync.Mutex    // guards zr and zerr
+	zr   io.Reader     // stores gzip or zstd reader from the pool between reads
+	zerr error         // sticky gzip reader init error or sentinel value to detect concurrent read and read after close
 }
 
 type eofReader struct{}
@@ -3328,9 +3367,34 @@ func gzipPoolPut(zr *gzip.Reader) {
 	gzipPool.Put(zr)
 }
 
-// acquire returns a gzip.Reader for reading response body.
+var zstdPool = sync.Pool{New: func() any { return new(zstd.Reader) }}
+
+// decompressPoolGet gets a gzip.Reader or zstd.Reader from the pool
+// and resets it to read from r.
+func (gz *gzipReader) decompressPoolGet(r io.Reader) (io.Reader, error) {
+	if gz.zstd {
+		zr := zstdPool.Get().(*zstd.Reader)
+		zr.Reset(r)
+		return zr, nil
+	}
+	return gzipPoolGet(r)
+}
+
+// decompressPoolPut puts a reader returned by decompressPoolGet
+// back into its pool.
+func decompressPoolPut(zr io.Reader) {
+	switch zr := zr.(type) {
+	case *gzip.Reader:
+		gzipPoolPut(zr)
+	case *zstd.Reader:
+		zr.Reset(nil)
+		zstdPool.Put(zr)
+	}
+}
+
+// acquire returns a reader for reading response body.
 // The reader must be released after use.
-func (gz *gzipReader) acquire() (*gzip.Reader, error) {
+func (gz *gzipReader) acquire() (io.Reader, error) {
 	gz.mu.Lock()
 	defer gz.mu.Unlock()
 	if gz.zerr != nil {
@@ -3343,12 +3407,12 @@ func (gz *gzipReader) acquire() (*gzip.Reader, error) {
 		// even when mu is temporarily dropped.
 		gz.zerr = errConcurrentReadOnResBody
 		gz.mu.Unlock()
-		zr, err := gzipPoolGet(gz.body)
+		zr, err := gz.decompressPoolGet(gz.body)
 		gz.mu.Lock()
 		// Guard against Close being called while gzipPoolGet is running.
 		if gz.zerr != errConcurrentReadOnResBody {
 			if zr != nil {
-				gzipPoolPut(zr)
+				decompressPoolPut(zr)
 			}
 			return nil, gz.zerr
 		}
@@ -3362,24 +3426,24 @@ func (gz *gzipReader) acquire() (*gzip.Reader, error) {
 	return ret, nil
 }
 
-// release returns the gzip.Reader to the pool if Close was called during Read.
-func (gz *gzipReader) release(zr *gzip.Reader) {
+// release returns the reader to the pool if Close was called during Read.
+func (gz *gzipReader) release(zr io.Reader) {
 	gz.mu.Lock()
 	defer gz.mu.Unlock()
 	if gz.zerr == errConcurrentReadOnResBody {
 		gz.zr, gz.zerr = zr, nil
 	} else { // errReadOnClosedResBody
-		gzipPoolPut(zr)
+		decompressPoolPut(zr)
 	}
 }
 
-// close returns the gzip.Reader to the pool immediately or
+// close returns the reader to the pool immediately or
 // signals release to do so after Read completes.
 func (gz *gzipReader) close() {
 	gz.mu.Lock()
 	defer gz.mu.Unlock()
 	if gz.zerr == nil && gz.zr != nil {
-		gzipPoolPut(gz.zr)
+		decompressPoolPut(gz.zr)
 		gz.zr = nil
 	}
 	gz.zerr = errReadOnClosedResBody
@@ -8,6 +8,7 @@ package http
 
 import (
 	"bytes"
+	"compress/zstd"
 	"context"
 	"crypto/tls"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"net"
 	"net/http/internal/http2"
 	"net/http/internal/testcert"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -268,3 +270,67 @@ func TestTransportBodyAltRewind(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// fakeClientConn is a genericClientConn whose RoundTrip calls roundTrip.
+type fakeClientConn struct {
+	genericClientConn
+	roundTrip roundTripFunc
+}
+
+func (cc fakeClientConn) RoundTrip(req *Request) (*Response, error) {
+	return cc.roundTrip(req)
+}
+
+func TestHTTP3ClientConnZstd(t *testing.T) {
+	const want = "The test string."
+	var buf bytes.Buffer
+	zw := zstd.NewWriter(&buf)
+	io.WriteString(zw, want)
+	zw.Close()
+	compressed := buf.String()
+
+	cc := http3ClientConn{
+		genericClientConn: fakeClientConn{roundTrip: func(req *Request) (*Response, error) {
+			return &Response{
+				StatusCode: 200,
+				Header: Header{
+					"Content-Encoding": {"zstd"},
+					"Content-Length":   {strconv.Itoa(len(compressed))},
+				},
+				ContentLength: int64(len(compressed)),
+				Body:          io.NopCloser(strings.NewReader(compressed)),
+			}, nil
+		}},
+		t: &Transport{},
+	}
+
+	// The response is decompressed if the transport asked for compression.
+	req, _ := NewRequest("GET", "https://example.org/", nil)
+	res, err := cc.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+	if !res.Uncompressed || res.ContentLength != -1 || res.Header.Get("Content-Encoding") != "" || res.Header.Get("Content-Length") != "" {
+		t.Errorf("Uncompressed = %v, ContentLength = %v, Header = %v; want decompressed response", res.Uncompressed, res.ContentLength, res.Header)
+	}
+
+	// It is left alone if the caller set Accept-Encoding itself.
+	req.Header.Set("Accept-Encoding", "zstd")
+	res, err = cc.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != compressed || res.Uncompressed {
+		t.Errorf("with Accept-Encoding set: body = %q, Uncompressed = %v; want compressed body", body, res.Uncompressed)
+	}
+}
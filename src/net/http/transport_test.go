@@ -13,6 +13,7 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"compress/zstd"
 	"context"
 	"crypto/rand"
 	"crypto/tls"
@@ -32,6 +33,7 @@ import (
 	"net/http/httptest"
 	"net/http/httptrace"
 	"net/http/httputil"
+	"net/http/internal"
 	"net/http/internal/testcert"
 	"net/textproto"
 	"net/url"
@@ -1130,7 +1132,7 @@ var roundTripTests = []struct {
 	compressed   bool
 }{
 	// Requests with no accept-encoding header use transparent compression
-	{"", "gzip", false},
+	{"", "gzip, zstd", false},
 	// Requests with other accept-encoding should pass through unmodified
 	{"foo", "foo", false},
 	// Requests with accept-encoding == gzip should be passed through
@@ -1147,7 +1149,7 @@ func testRoundTripGzip(t *testing.T, mode testMode) {
 			t.Errorf("in handler, test %v: Accept-Encoding = %q, want %q",
 				req.FormValue("testnum"), accept, expect)
 		}
-		if accept == "gzip" {
+		if accept == "gzip" || accept == "gzip, zstd" {
 			rw.Header().Set("Content-Encoding", "gzip")
 			gz := gzip.NewWriter(rw)
 			gz.Write([]byte(responseBody))
@@ -1161,7 +1163,7 @@ func testRoundTripGzip(t *testing.T, mode testMode) {
 
 	for i, test := range roundTripTests {
 		// Test basic request (no accept-encoding)
-		req, _ := NewRequest("GET", fmt.Sprintf("%s/?testnum=%d&expect_accept=%s", ts.URL, i, test.expectAccept), nil)
+		req, _ := NewRequest("GET", fmt.Sprintf("%s/?testnum=%d&expect_accept=%s", ts.URL, i, url.QueryEscape(test.expectAccept)), nil)
 		if test.accept != "" {
 			req.Header.Set("Accept-Encoding", test.accept)
 		}
@@ -1211,7 +1213,7 @@ func testTransportGzip(t *testing.T, mode testMode) {
 			}
 			return
 		}
-		if g, e := req.Header.Get("Accept-Encoding"), "gzip"; g != e {
+		if g, e := req.Header.Get("Accept-Encoding"), "gzip, zstd"; g != e {
 			t.Errorf("Accept-Encoding = %q, want %q", g, e)
 		}
 		rw.Header().Set("Content-Encoding", "gzip")
@@ -1293,6 +1295,98 @@ func testTransportGzip(t *testing.T, mode testMode) {
 	}
 }
 
+func TestTransportZstd(t *testing.T) { run(t, testTransportZstd) }
+func testTransportZstd(t *testing.T, mode testMode) {
+	want := strings.Repeat("The test string. ", 10000)
+	ts := newClientServerTest(t, mode, HandlerFunc(func(rw ResponseWriter, req *Request) {
+		if g, e := req.Header.Get("Accept-Encoding"), "gzip, zstd"; g != e {
+			t.Errorf("Accept-Encoding = %q, want %q", g, e)
+		}
+		var buf bytes.Buffer
+		zw := zstd.NewWriter(&buf)
+		io.WriteString(zw, want)
+		zw.Close()
+		rw.Header().Set("Content-Encoding", "zstd")
+		rw.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		rw.Write(buf.Bytes())
+	})).ts
+	c := ts.Client()
+
+	res, err := c.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != want {
+		t.Errorf("body mismatch: got %d bytes, want %d", len(body), len(want))
+	}
+	if !res.Uncompressed {
+		t.Errorf("Uncompressed = false, want true")
+	}
+	if g := res.Header.Get("Content-Encoding"); g != "" {
+		t.Errorf("Content-Encoding = %q, want none", g)
+	}
+	if res.ContentLength != -1 {
+		t.Errorf("ContentLength = %d, want -1", res.ContentLength)
+	}
+
+	// Close the body part way through; reads must then fail.
+	res, err = c.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 100)
+	if _, err := io.ReadFull(res.Body, buf); err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if n, err := res.Body.Read(buf); n != 0 || err == nil {
+		t.Errorf("Read after Close = %d, %v; want error", n, err)
+	}
+}
+
+func TestTransportZstdGODEBUG(t *testing.T) {
+	run(t, testTransportZstdGODEBUG, testNotParallel)
+}
+func testTransportZstdGODEBUG(t *testing.T, mode testMode) {
+	t.Setenv("GODEBUG", "httpzstd=0")
+	const want = "zstd compressed"
+	var buf bytes.Buffer
+	zw := zstd.NewWriter(&buf)
+	io.WriteString(zw, want)
+	zw.Close()
+	ts := newClientServerTest(t, mode, HandlerFunc(func(rw ResponseWriter, req *Request) {
+		if g, e := req.Header.Get("Accept-Encoding"), "gzip"; g != e {
+			t.Errorf("Accept-Encoding = %q, want %q", g, e)
+		}
+		rw.Header().Set("Content-Encoding", "zstd")
+		rw.Write(buf.Bytes())
+	})).ts
+
+	res, err := ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, buf.Bytes()) {
+		t.Errorf("body = %q, want the zstd compressed response", body)
+	}
+	if res.Uncompressed {
+		t.Errorf("Uncompressed = true, want false")
+	}
+	if g := res.Header.Get("Content-Encoding"); g != "zstd" {
+		t.Errorf("Content-Encoding = %q, want %q", g, "zstd")
+	}
+}
+
 // A transport100Continue test exercises Transport behaviors when sending a
 // request with an Expect: 100-continue header.
 type transport100ContinueTest struct {
@@ -4069,7 +4163,7 @@ func testRetryRequestsOnError(t *testing.T, mode testMode) {
 			req: func() *Request {
 				return newRequest("GET", "http://fake.golang", nil)
 			},
-			reqString: `GET / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nAccept-Encoding: gzip\r\n\r\n`,
+			reqString: `GET / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nAccept-Encoding: gzip, zstd\r\n\r\n`,
 		},
 		{
 			name: "IdempotentGetBodySomeWritten",
@@ -4081,7 +4175,7 @@ func testRetryRequestsOnError(t *testing.T, mode testMode) {
 			req: func() *Request {
 				return newRequest("GET", "http://fake.golang", strings.NewReader("foo\n"))
 			},
-			reqString: `GET / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nContent-Length: 4\r\nAccept-Encoding: gzip\r\n\r\nfoo\n`,
+			reqString: `GET / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nContent-Length: 4\r\nAccept-Encoding: gzip, zstd\r\n\r\nfoo\n`,
 		},
 		{
 			name: "NothingWrittenNoBody",
@@ -4092,7 +4186,7 @@ func testRetryRequestsOnError(t *testing.T, mode testMode) {
 			req: func() *Request {
 				return newRequest("DELETE", "http://fake.golang", nil)
 			},
-			reqString: `DELETE / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nAccept-Encoding: gzip\r\n\r\n`,
+			reqString: `DELETE / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nAccept-Encoding: gzip, zstd\r\n\r\n`,
 		},
 		{
 			name: "NothingWrittenGetBody",
@@ -4105,7 +4199,7 @@ func testRetryRequestsOnError(t *testing.T, mode testMode) {
 			req: func() *Request {
 				return newRequest("POST", "http://fake.golang", strings.NewReader("foo\n"))
 			},
-			reqString: `POST / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nContent-Length: 4\r\nAccept-Encoding: gzip\r\n\r\nfoo\n`,
+			reqString: `POST / HTTP/1.1\r\nHost: fake.golang\r\nUser-Agent: Go-http-client/1.1\r\nContent-Length: 4\r\nAccept-Encoding: gzip, zstd\r\n\r\nfoo\n`,
 		},
 	}
 
@@ -4856,7 +4950,7 @@ func TestTransportFlushesBodyChunks(t *testing.T) {
 	defer res.Body.Close()
 
 	want := []string{
-		"POST / HTTP/1.1\r\nHost: localhost:8080\r\nUser-Agent: x\r\nTransfer-Encoding: chunked\r\nAccept-Encoding: gzip\r\n\r\n",
+		"POST / HTTP/1.1\r\nHost: localhost:8080\r\nUser-Agent: x\r\nTransfer-Encoding: chunked\r\nAccept-Encoding: gzip, zstd\r\n\r\n",
 		"5\r\nnum0\n\r\n",
 		"5\r\nnum1\n\r\n",
 		"5\r\nnum2\n\r\n",
@@ -5423,7 +5517,7 @@ func testTransportEventTrace(t *testing.T, mode testMode, noHooks bool) {
 		wantOnce(fmt.Sprintf("WroteHeaderField: Host: [dns-is-faked.golang:%s]", port))
 		wantOnce(fmt.Sprintf("WroteHeaderField: Content-Length: [%d]", len(body)))
 		wantOnce("WroteHeaderField: X-Foo-Multiple-Vals: [bar baz]")
-		wantOnce("WroteHeaderField: Accept-Encoding: [gzip]")
+		wantOnce("WroteHeaderField: Accept-Encoding: [gzip, zstd]")
 	}
 	wantOnce("WroteHeaders")
 	wantOnce("Wait100Continue")
@@ -6873,6 +6967,43 @@ func testInvalidHeaderResponse(t *testing.T, mode testMode) {
 	}
 }
 
+func TestTransportExtendedConnect(t *testing.T) {
+	run(t, testTransportExtendedConnect, []testMode{http1Mode, http2Mode})
+}
+func testTransportExtendedConnect(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if r.Method != "CONNECT" || r.Header.Get(":protocol") != "echo" {
+			t.Errorf("got %v request with :protocol %q, want extended CONNECT", r.Method, r.Header.Get(":protocol"))
+		}
+		io.Copy(w, r.Body)
+	}), func(s *Server) {
+		s.HTTP2 = &HTTP2Config{EnableConnectProtocol: true}
+	})
+	req, _ := NewRequest("CONNECT", cst.ts.URL, strings.NewReader("hello"))
+	req.Header.Set(":protocol", "echo")
+	res, err := cst.c.Do(req)
+	if mode == http1Mode {
+		if !errors.Is(err, internal.ErrExtendedConnectHTTP1) {
+			t.Fatalf("HTTP/1 extended CONNECT: %v, want ErrExtendedConnectHTTP1", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if b, _ := io.ReadAll(res.Body); string(b) != "hello" {
+		t.Errorf("body = %q, want %q", b, "hello")
+	}
+
+	// :protocol is only valid in CONNECT requests.
+	req, _ = NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set(":protocol", "echo")
+	if _, err := cst.c.Do(req); err == nil {
+		t.Errorf("GET request with :protocol succeeded")
+	}
+}
+
 type bodyCloser bool
 
 func (bc *bodyCloser) Close() error {
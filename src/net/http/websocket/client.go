@@ -0,0 +1,230 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/internal"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// A Dialer opens WebSocket connections.
+// The zero value is a usable Dialer that uses [http.DefaultClient].
+type Dialer struct {
+	// Client is the HTTP client used for the opening handshake.
+	// If nil, http.DefaultClient is used.
+	//
+	// The client's Timeout, if any, limits the duration of the
+	// handshake but not the lifetime of the connection.
+	Client *http.Client
+
+	// Header specifies additional headers to send with the
+	// opening handshake, such as Origin or Authorization.
+	Header http.Header
+
+	// Subprotocols lists the application subprotocols to
+	// request, in order of preference.
+	Subprotocols []string
+
+	// EnableCompression requests the permessage-deflate extension,
+	// which compresses messages if the server supports it.
+	EnableCompression bool
+}
+
+// Dial opens a WebSocket connection to the URL with a zero [Dialer].
+func Dial(ctx context.Context, url string) (*Conn, *http.Response, error) {
+	var d Dialer
+	return d.Dial(ctx, url)
+}
+
+// Dial opens a WebSocket connection to the URL, which must have a
+// scheme of ws, wss, http or https.
+//
+// If the connection to the server uses HTTP/2, Dial opens the
+// WebSocket connection on a stream with the extended CONNECT method.
+// Otherwise, it upgrades the HTTP/1.1 connection.
+//
+// The context bounds the opening handshake. Once Dial returns, it
+// no longer affects the connection. Dial returns the server's
+// response to the handshake, if any, even if the handshake fails.
+// The caller must not use the response body.
+func (d *Dialer) Dial(ctx context.Context, rawURL string) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, nil, fmt.Errorf("websocket: unsupported URL scheme %q", u.Scheme)
+	}
+	u.Fragment, u.RawFragment = "", ""
+
+	client := http.DefaultClient
+	if d.Client != nil {
+		client = d.Client
+	}
+	if client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.Timeout)
+		defer cancel()
+		c := *client
+		c.Timeout = 0
+		client = &c
+	}
+
+	// The connection outlives ctx.
+	connCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	conn, resp, err := d.dialHTTP2(connCtx, client, u)
+	if errors.Is(err, internal.ErrExtendedConnectHTTP1) {
+		conn, resp, err = d.dialHTTP1(connCtx, client, u)
+	}
+	if err == nil && !stop() {
+		conn.CloseNow()
+		err = context.Cause(ctx)
+	}
+	if err != nil {
+		cancel()
+		if ctx.Err() != nil {
+			err = context.Cause(ctx)
+		}
+		return nil, resp, err
+	}
+	close := conn.close
+	conn.close = func() error {
+		err := close()
+		cancel()
+		return err
+	}
+	return conn, resp, nil
+}
+
+// newRequest returns a handshake request with the headers
+// common to both versions of HTTP.
+func (d *Dialer) newRequest(ctx context.Context, method string, u *url.URL, body io.Reader) *http.Request {
+	req, _ := http.NewRequestWithContext(ctx, method, u.String(), body)
+	for k, v := range d.Header {
+		req.Header[k] = slices.Clone(v)
+	}
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if len(d.Subprotocols) > 0 {
+		req.Header.Set("Sec-WebSocket-Protocol", strings.Join(d.Subprotocols, ", "))
+	}
+	if d.EnableCompression {
+		req.Header.Set("Sec-WebSocket-Extensions", deflateHeader)
+	}
+	return req
+}
+
+// dialHTTP2 opens a connection with an extended CONNECT request (RFC 8441).
+func (d *Dialer) dialHTTP2(ctx context.Context, client *http.Client, u *url.URL) (*Conn, *http.Response, error) {
+	pr, pw := io.Pipe()
+	req := d.newRequest(ctx, "CONNECT", u, pr)
+	req.Header.Set(":protocol", "websocket")
+	resp, err := client.Do(req)
+	if err != nil {
+		pw.Close()
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		pw.Close()
+		return nil, resp, fmt.Errorf("%w: unexpected response status %q", ErrBadHandshake, resp.Status)
+	}
+	subprotocol, compress, err := d.checkResponse(resp)
+	if err != nil {
+		resp.Body.Close()
+		pw.Close()
+		return nil, resp, err
+	}
+	close := func() error {
+		pw.Close()
+		return resp.Body.Close()
+	}
+	return newConn(bufio.NewReader(resp.Body), bufio.NewWriter(pw), nil, close, true, subprotocol, compress), resp, nil
+}
+
+// dialHTTP1 opens a connection by upgrading an HTTP/1.1 connection.
+func (d *Dialer) dialHTTP1(ctx context.Context, client *http.Client, u *url.URL) (*Conn, *http.Response, error) {
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	req := d.newRequest(ctx, "GET", u, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	var msg string
+	switch {
+	case resp.StatusCode != http.StatusSwitchingProtocols:
+		msg = fmt.Sprintf("unexpected response status %q", resp.Status)
+	case !httpguts.HeaderValuesContainsToken(resp.Header["Upgrade"], "websocket"):
+		msg = "missing Upgrade: websocket header"
+	case !httpguts.HeaderValuesContainsToken(resp.Header["Connection"], "upgrade"):
+		msg = "missing Connection: upgrade header"
+	case resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key):
+		msg = "invalid Sec-WebSocket-Accept"
+	}
+	if msg != "" {
+		resp.Body.Close()
+		return nil, resp, fmt.Errorf("%w: %s", ErrBadHandshake, msg)
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, resp, errors.New("websocket: response body is not writable")
+	}
+	subprotocol, compress, err := d.checkResponse(resp)
+	if err != nil {
+		rwc.Close()
+		return nil, resp, err
+	}
+	return newConn(bufio.NewReader(rwc), bufio.NewWriter(rwc), nil, rwc.Close, true, subprotocol, compress), resp, nil
+}
+
+// checkResponse checks the subprotocol and extensions
+// negotiated by the server.
+func (d *Dialer) checkResponse(resp *http.Response) (subprotocol string, compress bool, err error) {
+	switch protos := subprotocols(resp.Header); {
+	case len(protos) > 1:
+		return "", false, fmt.Errorf("%w: multiple subprotocols selected", ErrBadHandshake)
+	case len(protos) == 1:
+		subprotocol = protos[0]
+		if !slices.Contains(d.Subprotocols, subprotocol) {
+			return "", false, fmt.Errorf("%w: unexpected subprotocol %q", ErrBadHandshake, subprotocol)
+		}
+	}
+	switch exts := parseExtensions(resp.Header); {
+	case len(exts) > 1:
+		return "", false, fmt.Errorf("%w: too many extensions", ErrBadHandshake)
+	case len(exts) == 1:
+		if !d.EnableCompression || !checkDeflateResponse(exts[0]) {
+			return "", false, fmt.Errorf("%w: unexpected extension %q", ErrBadHandshake, exts[0].name)
+		}
+		compress = true
+	}
+	return subprotocol, compress, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The permessage-deflate extension is defined in RFC 7692.
+//
+// Both endpoints of a connection compress each message independently
+// (no context takeover). This avoids keeping a compressor and a 32 KiB
+// window per connection, and it lets a decompressor start from an empty
+// dictionary for every message.
+
+const deflateExtension = "permessage-deflate"
+
+// deflateHeader is both the extension offer sent by a client
+// and the response sent by a server that accepts an offer.
+const deflateHeader = deflateExtension + "; server_no_context_takeover; client_no_context_takeover"
+
+// deflateTail is the empty stored block that a sender removes from the
+// end of each compressed message, followed by a final empty block
+// that ends the stream for the decompressor.
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff}
+
+// An extension is an element of a Sec-WebSocket-Extensions header.
+type extension struct {
+	name   string
+	params map[string]string
+}
+
+// parseExtensions parses the Sec-WebSocket-Extensions header values in h.
+func parseExtensions(h http.Header) []extension {
+	var exts []extension
+	for _, v := range h["Sec-Websocket-Extensions"] {
+		for s := range strings.SplitSeq(v, ",") {
+			name, params, _ := strings.Cut(s, ";")
+			ext := extension{name: textproto.TrimString(name)}
+			if ext.name == "" {
+				continue
+			}
+			for p := range strings.SplitSeq(params, ";") {
+				k, v, _ := strings.Cut(p, "=")
+				k = textproto.TrimString(k)
+				if k == "" {
+					continue
+				}
+				v = textproto.TrimString(v)
+				if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+					v = v[1 : len(v)-1]
+				}
+				if ext.params == nil {
+					ext.params = make(map[string]string)
+				}
+				ext.params[k] = v
+			}
+			exts = append(exts, ext)
+		}
+	}
+	return exts
+}
+
+// acceptDeflate reports whether a server can accept a
+// permessage-deflate offer from a client.
+func acceptDeflate(offer extension) bool {
+	if offer.name != deflateExtension {
+		return false
+	}
+	for k, v := range offer.params {
+		switch k {
+		case "server_no_context_takeover", "client_no_context_takeover":
+			if v != "" {
+				return false
+			}
+		case "client_max_window_bits":
+			// The client may use any window size up to its limit.
+			// Since the response doesn't include this parameter,
+			// the client uses a 32 KiB window, which the decompressor
+			// always supports.
+		case "server_max_window_bits":
+			// The compressor always uses a 32 KiB window.
+			if v != "15" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkDeflateResponse reports whether a client can accept a
+// server's response to deflateOffer.
+func checkDeflateResponse(resp extension) bool {
+	if resp.name != deflateExtension {
+		return false
+	}
+	if _, ok := resp.params["server_no_context_takeover"]; !ok {
+		return false
+	}
+	for k, v := range resp.params {
+		switch k {
+		case "server_no_context_takeover", "client_no_context_takeover":
+			if v != "" {
+				return false
+			}
+		case "server_max_window_bits":
+			// A smaller window needs no change to the decompressor.
+			if n, err := strconv.Atoi(v); err != nil || n < 8 || n > 15 {
+				return false
+			}
+		default:
+			// Includes client_max_window_bits, which wasn't offered.
+			return false
+		}
+	}
+	return true
+}
+
+var flateWriterPool sync.Pool // *flate.Writer
+
+func getFlateWriter(w io.Writer) *flate.Writer {
+	if fw, ok := flateWriterPool.Get().(*flate.Writer); ok {
+		fw.Reset(w)
+		return fw
+	}
+	fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+	return fw
+}
+
+func putFlateWriter(fw *flate.Writer) {
+	fw.Reset(nil)
+	flateWriterPool.Put(fw)
+}
+
+var flateReaderPool sync.Pool // io.ReadCloser implementing flate.Resetter
+
+// newFlateReader returns a reader that decompresses a message
+// payload read from r.
+func newFlateReader(r io.Reader) io.ReadCloser {
+	r = io.MultiReader(r, bytes.NewReader(deflateTail))
+	if fr, ok := flateReaderPool.Get().(io.ReadCloser); ok {
+		fr.(flate.Resetter).Reset(r, nil)
+		return fr
+	}
+	return flate.NewReader(r)
+}
+
+func putFlateReader(fr io.ReadCloser) {
+	fr.Close()
+	flateReaderPool.Put(fr)
+}
+
+// A trimWriter writes to buf all but the last four bytes written to
+// it, which are the empty stored block ending a flushed deflate stream.
+type trimWriter struct {
+	buf  *[]byte
+	tail [4]byte
+	n    int // bytes in tail
+}
+
+func (w *trimWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if w.n+len(p) <= len(w.tail) {
+		w.n += copy(w.tail[w.n:], p)
+		return n, nil
+	}
+	// Emit what no longer fits in the tail.
+	keep := len(w.tail)
+	if len(p) >= keep {
+		*w.buf = append(*w.buf, w.tail[:w.n]...)
+		*w.buf = append(*w.buf, p[:len(p)-keep]...)
+		w.n = copy(w.tail[:], p[len(p)-keep:])
+		return n, nil
+	}
+	drop := w.n + len(p) - keep
+	*w.buf = append(*w.buf, w.tail[:drop]...)
+	copy(w.tail[:], w.tail[drop:w.n])
+	copy(w.tail[w.n-drop:], p)
+	w.n = keep
+	return n, nil
+}
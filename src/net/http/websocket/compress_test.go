@@ -0,0 +1,101 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseExtensions(t *testing.T) {
+	h := http.Header{"Sec-Websocket-Extensions": {
+		`permessage-deflate; client_max_window_bits, permessage-deflate; server_max_window_bits="10"`,
+		"x-webkit-deflate-frame",
+	}}
+	want := []extension{
+		{name: "permessage-deflate", params: map[string]string{"client_max_window_bits": ""}},
+		{name: "permessage-deflate", params: map[string]string{"server_max_window_bits": "10"}},
+		{name: "x-webkit-deflate-frame"},
+	}
+	if got := parseExtensions(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseExtensions = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeflateNegotiation(t *testing.T) {
+	for _, test := range []struct {
+		offer string
+		want  bool
+	}{
+		{"permessage-deflate", true},
+		{"permessage-deflate; client_max_window_bits", true},
+		{"permessage-deflate; client_max_window_bits=10", true},
+		{"permessage-deflate; server_no_context_takeover; client_no_context_takeover", true},
+		{"permessage-deflate; server_max_window_bits=15", true},
+		{"permessage-deflate; server_max_window_bits=10", false},
+		{"permessage-deflate; unknown", false},
+		{"x-webkit-deflate-frame", false},
+	} {
+		exts := parseExtensions(http.Header{"Sec-Websocket-Extensions": {test.offer}})
+		if got := acceptDeflate(exts[0]); got != test.want {
+			t.Errorf("acceptDeflate(%q) = %v, want %v", test.offer, got, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		resp string
+		want bool
+	}{
+		{deflateHeader, true},
+		{"permessage-deflate; server_no_context_takeover", true},
+		{"permessage-deflate; server_no_context_takeover; server_max_window_bits=9", true},
+		{"permessage-deflate", false},
+		{"permessage-deflate; server_no_context_takeover; server_max_window_bits=16", false},
+		{"permessage-deflate; server_no_context_takeover; client_max_window_bits=10", false},
+	} {
+		exts := parseExtensions(http.Header{"Sec-Websocket-Extensions": {test.resp}})
+		if got := checkDeflateResponse(exts[0]); got != test.want {
+			t.Errorf("checkDeflateResponse(%q) = %v, want %v", test.resp, got, test.want)
+		}
+	}
+}
+
+func TestDeflateMessage(t *testing.T) {
+	// Example from RFC 7692, Section 7.2.3.1.
+	fr := newFlateReader(bytes.NewReader([]byte{0xf2, 0x48, 0xcd, 0xc9, 0xc9, 0x07, 0x00}))
+	b, err := io.ReadAll(fr)
+	if err != nil || string(b) != "Hello" {
+		t.Errorf("decompressed %q, %v; want %q", b, err, "Hello")
+	}
+	putFlateReader(fr)
+
+	// An empty message is a single 0x00 byte.
+	var buf []byte
+	fw := getFlateWriter(&trimWriter{buf: &buf})
+	fw.Flush()
+	putFlateWriter(fw)
+	if !bytes.Equal(buf, []byte{0x00}) {
+		t.Errorf("compressed empty message = % x, want 00", buf)
+	}
+}
+
+func TestTrimWriter(t *testing.T) {
+	const in = "0123456789"
+	for _, sizes := range [][]int{{10}, {1, 9}, {3, 3, 3, 1}, {6, 1, 1, 2}, {2, 5, 3}} {
+		var buf []byte
+		w := &trimWriter{buf: &buf}
+		s := in
+		for _, n := range sizes {
+			w.Write([]byte(s[:n]))
+			s = s[n:]
+		}
+		if got, tail := string(buf), string(w.tail[:w.n]); got != "012345" || tail != "6789" {
+			t.Errorf("writes of %v: got %q, tail %q", sizes, got, tail)
+		}
+	}
+}
@@ -0,0 +1,825 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// defaultReadLimit is the default maximum size of a message.
+	defaultReadLimit = 32 << 20
+
+	// frameSize is the payload size of the frames written
+	// by a message writer before it is closed.
+	frameSize = 16 << 10
+
+	// closeTimeout is how long Close waits for the peer's close frame.
+	closeTimeout = 5 * time.Second
+)
+
+// A Conn is a WebSocket connection.
+//
+// A Conn supports one concurrent reader and one concurrent writer: at
+// most one goroutine may call [Conn.Read] or [Conn.Reader] and read the
+// returned message at a time, and at most one goroutine may call
+// [Conn.Write] or [Conn.Writer] and write the returned message at a time.
+// [Conn.Ping], [Conn.Close] and [Conn.CloseNow] may be called
+// concurrently with all other methods.
+//
+// The read methods respond to ping and close frames sent by the peer.
+// An application that doesn't otherwise read from a Conn should still
+// read from it in a loop, so that these control frames are handled.
+type Conn struct {
+	br          *bufio.Reader
+	bw          *bufio.Writer
+	flush       func() error // flushes the transport after bw; may be nil
+	close       func() error // closes the transport
+	client      bool         // masks outgoing frames and expects unmasked frames
+	compress    bool         // permessage-deflate was negotiated
+	subprotocol string
+
+	readLimit int64
+
+	// readMu is held while reading frames.
+	readMu chan struct{}
+
+	// Read state, guarded by readMu.
+	rmsg       *messageReader // message most recently returned by Reader
+	rremaining int64          // unread payload bytes in the current frame
+	rmore      bool           // frames remain in the current message
+	rkey       [4]byte        // masking key of the current frame
+	rmasked    bool
+	rpos       int
+	rerr       error // sticky error returned by all reads
+
+	// msgMu is held by the active message writer.
+	msgMu chan struct{}
+	wbuf  []byte // frame payload buffer of message writers
+
+	// writeMu guards writing frames.
+	writeMu   sync.Mutex
+	whdr      []byte
+	wmask     []byte
+	closeSent bool
+	werr      error
+
+	mu       sync.Mutex
+	pings    map[string]chan struct{}
+	pingID   uint64
+	closeErr error // why the transport was closed
+
+	closeOnce     sync.Once
+	closed        chan struct{} // closed with the transport
+	closeRecvOnce sync.Once
+	closeRecv     chan struct{} // closed when the peer's close frame arrives
+}
+
+func newConn(br *bufio.Reader, bw *bufio.Writer, flush, close func() error, client bool, subprotocol string, compress bool) *Conn {
+	return &Conn{
+		br:          br,
+		bw:          bw,
+		flush:       flush,
+		close:       close,
+		client:      client,
+		subprotocol: subprotocol,
+		compress:    compress,
+		readLimit:   defaultReadLimit,
+		readMu:      make(chan struct{}, 1),
+		msgMu:       make(chan struct{}, 1),
+		pings:       make(map[string]chan struct{}),
+		closed:      make(chan struct{}),
+		closeRecv:   make(chan struct{}),
+	}
+}
+
+// Subprotocol returns the application subprotocol negotiated
+// in the opening handshake, or "" if there is none.
+func (c *Conn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// SetReadLimit sets the maximum size in bytes of a message read from
+// the peer. A message exceeding the limit closes the connection with
+// [StatusMessageTooBig]. For compressed messages, the limit applies to
+// the decompressed size. The default limit is 32 MiB.
+// A limit of zero or less means no limit.
+//
+// SetReadLimit must not be called concurrently with the read methods.
+func (c *Conn) SetReadLimit(n int64) {
+	c.readLimit = n
+}
+
+// Read reads the next message from the connection.
+//
+// If the peer closes the connection, Read returns a *[CloseError].
+// If ctx is done before Read returns, the connection is closed
+// and Read returns the context's error.
+func (c *Conn) Read(ctx context.Context) (MessageType, []byte, error) {
+	typ, r, err := c.Reader(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	b, err := io.ReadAll(r)
+	return typ, b, err
+}
+
+// Reader returns the type of the next message from the connection and
+// a reader for its contents. The reader returns [io.EOF] at the end of
+// the message. Any unread part of the previous message is discarded.
+//
+// If the peer closes the connection, Reader returns a *[CloseError].
+// If ctx is done before the message has been read, the connection is
+// closed and reads return the context's error.
+func (c *Conn) Reader(ctx context.Context) (MessageType, io.Reader, error) {
+	select {
+	case c.readMu <- struct{}{}:
+	case <-ctx.Done():
+		return 0, nil, context.Cause(ctx)
+	}
+	defer func() { <-c.readMu }()
+
+	if c.rmsg != nil {
+		c.rmsg.stop()
+		c.rmsg = nil
+		if err := c.discardMessage(); err != nil {
+			return 0, nil, err
+		}
+	}
+	if c.rerr != nil {
+		return 0, nil, c.rerr
+	}
+
+	stop := c.watch(ctx)
+	h, err := c.readDataFrame()
+	if err != nil {
+		stop()
+		return 0, nil, err
+	}
+	mr := &messageReader{
+		c:    c,
+		text: h.op == opText,
+		stop: stop,
+	}
+	mr.r = payloadReader{c}
+	if h.rsv&rsv1Bit != 0 {
+		mr.fr = newFlateReader(mr.r)
+		mr.r = mr.fr
+	}
+	c.rmsg = mr
+	return MessageType(h.op), mr, nil
+}
+
+// watch closes the connection if ctx is done before stop is called.
+func (c *Conn) watch(ctx context.Context) (stop func() bool) {
+	return context.AfterFunc(ctx, func() {
+		c.closeWithError(context.Cause(ctx))
+	})
+}
+
+// readDataFrame reads the header of the next data frame,
+// handling any control frames that precede it.
+// It must be called with readMu held.
+func (c *Conn) readDataFrame() (frameHeader, error) {
+	for {
+		h, err := readFrameHeader(c.br)
+		if err != nil {
+			return h, c.readFailed(err)
+		}
+		if msg := c.checkFrame(h); msg != "" {
+			return h, c.fail(StatusProtocolError, msg)
+		}
+		if !h.op.isControl() {
+			c.rremaining = h.length
+			c.rmore = !h.fin
+			c.rkey = h.key
+			c.rmasked = h.masked
+			c.rpos = 0
+			return h, nil
+		}
+
+		var buf [maxControlPayload]byte
+		payload := buf[:h.length]
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			return h, c.readFailed(err)
+		}
+		if h.masked {
+			maskBytes(h.key, 0, payload)
+		}
+		switch h.op {
+		case opPing:
+			if err := c.writeControl(opPong, payload); err != nil && err != net.ErrClosed {
+				return h, c.readFailed(err)
+			}
+		case opPong:
+			c.mu.Lock()
+			if ch, ok := c.pings[string(payload)]; ok {
+				close(ch)
+				delete(c.pings, string(payload))
+			}
+			c.mu.Unlock()
+		case opClose:
+			return h, c.handleClose(payload)
+		}
+	}
+}
+
+// checkFrame returns a description of the protocol violation
+// in a frame header, or "" if there is none.
+func (c *Conn) checkFrame(h frameHeader) string {
+	switch {
+	case h.rsv&(rsv2Bit|rsv3Bit) != 0:
+		return "reserved bits set"
+	case h.rsv&rsv1Bit != 0 && (!c.compress || h.op.isControl() || h.op == opContinuation):
+		return "unexpected RSV1 bit"
+	case h.masked == c.client:
+		if c.client {
+			return "masked frame from server"
+		}
+		return "unmasked frame from client"
+	}
+	switch h.op {
+	case opContinuation:
+		if !c.rmore {
+			return "unexpected continuation frame"
+		}
+	case opText, opBinary:
+		if c.rmore {
+			return "expected continuation frame"
+		}
+	case opClose, opPing, opPong:
+		if !h.fin {
+			return "fragmented control frame"
+		}
+		if h.length > maxControlPayload {
+			return "control frame too long"
+		}
+	default:
+		return "unknown opcode"
+	}
+	return ""
+}
+
+// handleClose handles a close frame from the peer.
+func (c *Conn) handleClose(payload []byte) error {
+	ce := &CloseError{Code: StatusNoStatusReceived}
+	switch {
+	case len(payload) == 1:
+		return c.fail(StatusProtocolError, "invalid close frame")
+	case len(payload) >= 2:
+		ce.Code = StatusCode(binary.BigEndian.Uint16(payload))
+		ce.Reason = string(payload[2:])
+		if !ce.Code.valid() {
+			return c.fail(StatusProtocolError, "invalid close status")
+		}
+		if !utf8.ValidString(ce.Reason) {
+			return c.fail(StatusInvalidPayloadData, "invalid close reason")
+		}
+	}
+	c.closeRecvOnce.Do(func() { close(c.closeRecv) })
+
+	// Echo the status code, completing the closing handshake.
+	var echo []byte
+	if len(payload) >= 2 {
+		echo = payload[:2]
+	}
+	c.writeControl(opClose, echo)
+	c.closeWithError(ce)
+	c.rerr = ce
+	return ce
+}
+
+// fail fails the connection after a protocol violation by the peer.
+func (c *Conn) fail(code StatusCode, msg string) error {
+	err := errors.New("websocket: " + msg)
+	c.writeControl(opClose, closePayload(code, msg))
+	c.closeWithError(err)
+	c.rerr = c.closeError()
+	return c.rerr
+}
+
+// readFailed handles an error reading from the transport.
+func (c *Conn) readFailed(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	c.closeWithError(err)
+	c.rerr = c.closeError()
+	return c.rerr
+}
+
+// readPayload reads from the payload of the current message.
+// It must be called with readMu held.
+func (c *Conn) readPayload(p []byte) (int, error) {
+	for c.rremaining == 0 {
+		if !c.rmore {
+			return 0, io.EOF
+		}
+		if _, err := c.readDataFrame(); err != nil {
+			return 0, err
+		}
+	}
+	if int64(len(p)) > c.rremaining {
+		p = p[:c.rremaining]
+	}
+	n, err := c.br.Read(p)
+	c.rremaining -= int64(n)
+	if c.rmasked {
+		c.rpos = maskBytes(c.rkey, c.rpos, p[:n])
+	}
+	if err != nil {
+		return n, c.readFailed(err)
+	}
+	return n, nil
+}
+
+// discardMessage discards the rest of the current message.
+// It must be called with readMu held.
+func (c *Conn) discardMessage() error {
+	if c.rerr != nil {
+		return c.rerr
+	}
+	for c.rremaining > 0 || c.rmore {
+		if c.rremaining > 0 {
+			n, err := c.br.Discard(int(min(c.rremaining, 1<<30)))
+			c.rremaining -= int64(n)
+			if err != nil {
+				return c.readFailed(err)
+			}
+			continue
+		}
+		if _, err := c.readDataFrame(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A payloadReader reads the payload of the current message.
+type payloadReader struct{ c *Conn }
+
+func (r payloadReader) Read(p []byte) (int, error) { return r.c.readPayload(p) }
+
+// A messageReader is returned by Conn.Reader.
+type messageReader struct {
+	c    *Conn
+	r    io.Reader     // the payload, decompressed if fr != nil
+	fr   io.ReadCloser // flate reader of a compressed message
+	n    int64         // bytes returned
+	text bool
+	utf8 utf8Validator
+	stop func() bool
+	err  error
+}
+
+func (r *messageReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	c := r.c
+	c.readMu <- struct{}{}
+	defer func() { <-c.readMu }()
+	if c.rmsg != r {
+		// Close discarded the message.
+		r.err = c.rerr
+		if r.err == nil {
+			r.err = net.ErrClosed
+		}
+		return 0, r.err
+	}
+
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if c.readLimit > 0 && r.n > c.readLimit {
+		return 0, r.finish(c.fail(StatusMessageTooBig, "message too big"))
+	}
+	if r.text && !r.utf8.valid(p[:n]) {
+		return 0, r.finish(c.fail(StatusInvalidPayloadData, "invalid UTF-8 in text message"))
+	}
+	switch {
+	case err == io.EOF:
+		if r.fr != nil {
+			// The compressed data may end before the payload.
+			if err := c.discardMessage(); err != nil {
+				return n, r.finish(err)
+			}
+		}
+		if r.text && !r.utf8.complete() {
+			return 0, r.finish(c.fail(StatusInvalidPayloadData, "invalid UTF-8 in text message"))
+		}
+		return n, r.finish(io.EOF)
+	case err != nil && r.fr != nil && c.rerr == nil:
+		return n, r.finish(c.fail(StatusInvalidPayloadData, "invalid compressed data"))
+	case err != nil:
+		return n, r.finish(err)
+	}
+	return n, nil
+}
+
+// finish ends the message with err.
+func (r *messageReader) finish(err error) error {
+	r.err = err
+	r.stop()
+	if r.fr != nil {
+		putFlateReader(r.fr)
+		r.fr = nil
+	}
+	return err
+}
+
+// A utf8Validator validates text split across several buffers.
+type utf8Validator struct {
+	partial [utf8.UTFMax]byte // incomplete rune at the end of the last buffer
+	n       int
+}
+
+// valid reports whether p, following the buffers already validated,
+// may be part of a valid UTF-8 text.
+func (v *utf8Validator) valid(p []byte) bool {
+	if v.n > 0 {
+		for len(p) > 0 && !utf8.FullRune(v.partial[:v.n]) {
+			v.partial[v.n] = p[0]
+			v.n++
+			p = p[1:]
+		}
+		if !utf8.FullRune(v.partial[:v.n]) {
+			return true
+		}
+		if r, size := utf8.DecodeRune(v.partial[:v.n]); r == utf8.RuneError && size == 1 {
+			return false
+		}
+		v.n = 0
+	}
+	end := len(p)
+	for i := len(p) - 1; i >= 0 && i > len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+	if !utf8.Valid(p[:end]) {
+		return false
+	}
+	v.n = copy(v.partial[:], p[end:])
+	return true
+}
+
+// complete reports whether the text validated so far doesn't end
+// with an incomplete rune.
+func (v *utf8Validator) complete() bool {
+	return v.n == 0
+}
+
+// Write writes a message to the connection.
+//
+// If ctx is done before Write returns, the connection is closed
+// and Write returns the context's error.
+func (c *Conn) Write(ctx context.Context, typ MessageType, p []byte) error {
+	w, err := c.Writer(ctx, typ)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(p); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Writer returns a writer for the next message to send.
+// The message is sent in one or more frames as it is written,
+// and the writer must be closed to complete the message.
+// Writer blocks until the previous message writer has been closed.
+//
+// If ctx is done before the writer is closed, the connection is closed
+// and writes return the context's error.
+func (c *Conn) Writer(ctx context.Context, typ MessageType) (io.WriteCloser, error) {
+	if typ != TextMessage && typ != BinaryMessage {
+		return nil, errors.New("websocket: invalid message type " + typ.String())
+	}
+	select {
+	case c.msgMu <- struct{}{}:
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+	if c.wbuf == nil {
+		c.wbuf = make([]byte, 0, frameSize)
+	}
+	mw := &messageWriter{
+		c:    c,
+		op:   opcode(typ),
+		buf:  c.wbuf[:0],
+		stop: c.watch(ctx),
+	}
+	if c.compress {
+		mw.tw.buf = &mw.buf
+		mw.fw = getFlateWriter(&mw.tw)
+		mw.rsv = rsv1Bit
+	}
+	return mw, nil
+}
+
+// A messageWriter is returned by Conn.Writer.
+type messageWriter struct {
+	c    *Conn
+	op   opcode // opcode of the next frame
+	rsv  byte   // reserved bits of the next frame
+	buf  []byte // payload of the next frame
+	fw   *flate.Writer
+	tw   trimWriter // writes compressed data to buf
+	stop func() bool
+	err  error
+}
+
+func (w *messageWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.fw != nil {
+		if _, err := w.fw.Write(p); err != nil {
+			return 0, w.setErr(err)
+		}
+		if err := w.flushFrames(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	n := len(p)
+	for len(w.buf)+len(p) > frameSize {
+		if len(w.buf) == 0 {
+			// Write directly from p.
+			if err := w.writeFrame(false, p[:frameSize]); err != nil {
+				return 0, err
+			}
+			p = p[frameSize:]
+			continue
+		}
+		m := copy(w.buf[len(w.buf):frameSize], p)
+		w.buf = w.buf[:frameSize]
+		p = p[m:]
+		if err := w.writeFrame(false, w.buf); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[:0]
+	}
+	w.buf = append(w.buf, p...)
+	return n, nil
+}
+
+// flushFrames writes full frames of compressed data.
+func (w *messageWriter) flushFrames() error {
+	for len(w.buf) >= frameSize {
+		if err := w.writeFrame(false, w.buf[:frameSize]); err != nil {
+			return err
+		}
+		w.buf = w.buf[:copy(w.buf, w.buf[frameSize:])]
+	}
+	return nil
+}
+
+// Close writes the final frame of the message.
+func (w *messageWriter) Close() error {
+	if w.err != nil {
+		if w.err == errWriterClosed {
+			return nil
+		}
+		return w.err
+	}
+	if w.fw != nil {
+		if err := w.fw.Flush(); err != nil {
+			w.setErr(err)
+		} else if err := w.flushFrames(); err == nil && (w.tw.n != 4 || w.tw.tail != [4]byte{0, 0, 0xff, 0xff}) {
+			w.setErr(errors.New("websocket: unexpected end of deflate stream"))
+		}
+		putFlateWriter(w.fw)
+		w.fw = nil
+	}
+	if w.err == nil {
+		w.writeFrame(true, w.buf)
+	}
+	if cap(w.buf) <= 2*frameSize {
+		w.c.wbuf = w.buf[:0]
+	}
+	w.stop()
+	<-w.c.msgMu
+	err := w.err
+	w.err = errWriterClosed
+	return err
+}
+
+var errWriterClosed = errors.New("websocket: write to closed message writer")
+
+func (w *messageWriter) writeFrame(fin bool, payload []byte) error {
+	err := w.c.writeFrame(frameHeader{
+		fin: fin,
+		rsv: w.rsv,
+		op:  w.op,
+	}, payload)
+	w.op = opContinuation
+	w.rsv = 0
+	return w.setErr(err)
+}
+
+func (w *messageWriter) setErr(err error) error {
+	if w.err == nil {
+		w.err = err
+	}
+	return err
+}
+
+// writeControl writes a control frame.
+// It returns net.ErrClosed if a close frame has already been sent.
+func (c *Conn) writeControl(op opcode, payload []byte) error {
+	return c.writeFrame(frameHeader{fin: true, op: op}, payload)
+}
+
+// writeFrame writes a frame with the given header fields and payload.
+// It returns net.ErrClosed if a close frame has already been sent.
+func (c *Conn) writeFrame(h frameHeader, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.werr != nil {
+		return c.werr
+	}
+	select {
+	case <-c.closed:
+		return c.closeError()
+	default:
+	}
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	if h.op == opClose {
+		c.closeSent = true
+	}
+	h.length = int64(len(payload))
+	if c.client {
+		h.masked = true
+		rand.Read(h.key[:])
+	}
+	c.whdr = appendFrameHeader(c.whdr[:0], h)
+	c.bw.Write(c.whdr)
+	if !h.masked {
+		c.bw.Write(payload)
+	} else {
+		if c.wmask == nil {
+			c.wmask = make([]byte, 4096)
+		}
+		pos := 0
+		for len(payload) > 0 {
+			n := copy(c.wmask, payload)
+			pos = maskBytes(h.key, pos, c.wmask[:n])
+			c.bw.Write(c.wmask[:n])
+			payload = payload[n:]
+		}
+	}
+	err := c.bw.Flush()
+	if err == nil && c.flush != nil {
+		err = c.flush()
+	}
+	if err != nil {
+		c.closeWithError(err)
+		c.werr = c.closeError()
+		return c.werr
+	}
+	return nil
+}
+
+// closePayload returns the payload of a close frame.
+func closePayload(code StatusCode, reason string) []byte {
+	if len(reason) > maxControlPayload-2 {
+		reason = reason[:maxControlPayload-2]
+	}
+	b := binary.BigEndian.AppendUint16(nil, uint16(code))
+	return append(b, reason...)
+}
+
+// Ping sends a ping to the peer and waits for the corresponding pong.
+//
+// Pongs are received by the read methods, so another goroutine must be
+// reading from the connection for Ping to return successfully.
+func (c *Conn) Ping(ctx context.Context) error {
+	c.mu.Lock()
+	c.pingID++
+	p := binary.BigEndian.AppendUint64(nil, c.pingID)
+	ch := make(chan struct{})
+	c.pings[string(p)] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pings, string(p))
+		c.mu.Unlock()
+	}()
+
+	if err := c.writeControl(opPing, p); err != nil {
+		return err
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-c.closed:
+		return c.closeError()
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Close performs the closing handshake and closes the connection.
+// It sends a close frame with the given status code and reason,
+// waits for the peer's close frame, and closes the transport.
+//
+// The reason must be at most 123 bytes of UTF-8 text. Close waits at
+// most five seconds for the peer to respond. If another goroutine is
+// reading from the connection, that goroutine receives the peer's
+// close frame as a *[CloseError]; otherwise Close reads and discards
+// any messages that arrive before it.
+//
+// Close returns nil if the connection was already closed by the peer.
+func (c *Conn) Close(code StatusCode, reason string) error {
+	if !code.valid() {
+		return errors.New("websocket: invalid close status code")
+	}
+	if len(reason) > maxControlPayload-2 || !utf8.ValidString(reason) {
+		return errors.New("websocket: invalid close reason")
+	}
+	if err := c.writeControl(opClose, closePayload(code, reason)); err != nil {
+		c.CloseNow()
+		if err == net.ErrClosed {
+			// We replied to the peer's close frame.
+			return nil
+		}
+		var ce *CloseError
+		if errors.As(err, &ce) {
+			return nil
+		}
+		return err
+	}
+
+	t := time.AfterFunc(closeTimeout, func() {
+		c.closeWithError(errors.New("websocket: timed out waiting for close frame"))
+	})
+	defer t.Stop()
+	select {
+	case c.readMu <- struct{}{}:
+		// No read is in progress, so read until the close frame.
+		for c.rerr == nil {
+			c.discardMessage()
+			if c.rerr == nil {
+				c.readDataFrame()
+			}
+		}
+		c.rmsg = nil
+		<-c.readMu
+	case <-c.closeRecv:
+	case <-c.closed:
+	}
+
+	var err error
+	select {
+	case <-c.closeRecv:
+	default:
+		err = c.closeError()
+	}
+	c.CloseNow()
+	return err
+}
+
+// CloseNow closes the connection without a closing handshake.
+func (c *Conn) CloseNow() error {
+	c.closeWithError(net.ErrClosed)
+	return nil
+}
+
+// closeWithError closes the transport. Operations blocked on the
+// transport, and all later ones, return err.
+func (c *Conn) closeWithError(err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closeErr = err
+		c.mu.Unlock()
+		close(c.closed)
+		c.close()
+	})
+}
+
+// closeError returns the error the transport was closed with.
+func (c *Conn) closeError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeErr
+}
@@ -0,0 +1,420 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newConnPair returns the two ends of a connection over a net.Pipe.
+func newConnPair(t *testing.T, compress bool) (client, server *Conn) {
+	c1, c2 := net.Pipe()
+	client = newConn(bufio.NewReader(c1), bufio.NewWriter(c1), nil, c1.Close, true, "", compress)
+	server = newConn(bufio.NewReader(c2), bufio.NewWriter(c2), nil, c2.Close, false, "", compress)
+	t.Cleanup(func() {
+		client.CloseNow()
+		server.CloseNow()
+	})
+	return client, server
+}
+
+// newRawConn returns a server Conn and the client end of its transport,
+// for writing raw frames.
+func newRawConn(t *testing.T, compress bool) (*Conn, net.Conn) {
+	c1, c2 := net.Pipe()
+	server := newConn(bufio.NewReader(c2), bufio.NewWriter(c2), nil, c2.Close, false, "", compress)
+	t.Cleanup(func() {
+		c1.Close()
+		server.CloseNow()
+	})
+	return server, c1
+}
+
+// clientFrame returns a masked frame.
+func clientFrame(b0 byte, payload string) []byte {
+	h := frameHeader{
+		fin:    b0&finBit != 0,
+		rsv:    b0 & (rsv1Bit | rsv2Bit | rsv3Bit),
+		op:     opcode(b0 & 0xf),
+		masked: true,
+		key:    [4]byte{1, 2, 3, 4},
+		length: int64(len(payload)),
+	}
+	p := []byte(payload)
+	maskBytes(h.key, 0, p)
+	return append(appendFrameHeader(nil, h), p...)
+}
+
+// readServerFrame reads an unmasked frame.
+func readServerFrame(t *testing.T, r io.Reader) (frameHeader, []byte) {
+	t.Helper()
+	h, err := readFrameHeader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, h.length)
+	if _, err := io.ReadFull(r, p); err != nil {
+		t.Fatal(err)
+	}
+	return h, p
+}
+
+func TestConnMessages(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(map[bool]string{false: "plain", true: "compressed"}[compress], func(t *testing.T) {
+			client, server := newConnPair(t, compress)
+			msgs := []struct {
+				typ  MessageType
+				data []byte
+			}{
+				{TextMessage, []byte("hello")},
+				{BinaryMessage, []byte{0, 1, 2, 3}},
+				{TextMessage, nil},
+				{BinaryMessage, bytes.Repeat([]byte("0123456789"), 10000)},
+				{TextMessage, []byte(strings.Repeat("héllo, wörld ", 5000))},
+			}
+			go func() {
+				for _, m := range msgs {
+					if err := client.Write(context.Background(), m.typ, m.data); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+			for _, m := range msgs {
+				typ, data, err := server.Read(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if typ != m.typ || !bytes.Equal(data, m.data) {
+					t.Errorf("got %v message of %d bytes, want %v message of %d bytes", typ, len(data), m.typ, len(m.data))
+				}
+			}
+		})
+	}
+}
+
+func TestConnStreaming(t *testing.T) {
+	client, server := newConnPair(t, false)
+	want := bytes.Repeat([]byte("abcdefgh"), 3*frameSize/8+100)
+	go func() {
+		w, err := server.Writer(context.Background(), BinaryMessage)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for b := want; len(b) > 0; {
+			n := min(len(b), 1000)
+			w.Write(b[:n])
+			b = b[n:]
+		}
+		if err := w.Close(); err != nil {
+			t.Error(err)
+		}
+		server.Write(context.Background(), TextMessage, []byte("next"))
+	}()
+
+	typ, r, err := client.Reader(context.Background())
+	if err != nil || typ != BinaryMessage {
+		t.Fatalf("Reader = %v, %v", typ, err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("read %d bytes, %v; want %d bytes", len(got), err, len(want))
+	}
+	if _, data, err := client.Read(context.Background()); err != nil || string(data) != "next" {
+		t.Fatalf("Read = %q, %v", data, err)
+	}
+}
+
+func TestConnDiscardUnreadMessage(t *testing.T) {
+	client, server := newConnPair(t, true)
+	go func() {
+		client.Write(context.Background(), TextMessage, bytes.Repeat([]byte("x"), 100000))
+		client.Write(context.Background(), TextMessage, []byte("second"))
+	}()
+	_, r, err := server.Reader(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Read(make([]byte, 10))
+	_, data, err := server.Read(context.Background())
+	if err != nil || string(data) != "second" {
+		t.Fatalf("Read = %q, %v; want %q", data, err, "second")
+	}
+}
+
+func TestConnPing(t *testing.T) {
+	client, server := newConnPair(t, false)
+	go server.Read(context.Background())
+	go client.Read(context.Background())
+	for range 3 {
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := server.Ping(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestConnClose(t *testing.T) {
+	client, server := newConnPair(t, false)
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := server.Read(context.Background())
+		errc <- err
+	}()
+	if err := client.Close(StatusGoingAway, "bye"); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	err := <-errc
+	var ce *CloseError
+	if !errors.As(err, &ce) || ce.Code != StatusGoingAway || ce.Reason != "bye" {
+		t.Fatalf("server Read = %v, want CloseError 1001 bye", err)
+	}
+	if _, _, err := server.Read(context.Background()); err != ce {
+		t.Errorf("second Read = %v, want %v", err, ce)
+	}
+	if err := server.Write(context.Background(), TextMessage, []byte("x")); err == nil {
+		t.Errorf("Write after close succeeded")
+	}
+	if err := server.Close(StatusNormalClosure, ""); err != nil {
+		t.Errorf("Close after peer closed = %v", err)
+	}
+}
+
+func TestConnCloseConcurrentRead(t *testing.T) {
+	client, server := newConnPair(t, false)
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := client.Read(context.Background())
+		errc <- err
+	}()
+	go func() {
+		for {
+			if _, _, err := server.Read(context.Background()); err != nil {
+				return
+			}
+		}
+	}()
+	time.Sleep(10 * time.Millisecond) // let the client start reading
+	if err := client.Close(StatusNormalClosure, ""); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	var ce *CloseError
+	if err := <-errc; !errors.As(err, &ce) || ce.Code != StatusNormalClosure {
+		t.Fatalf("concurrent Read = %v, want echoed CloseError", err)
+	}
+}
+
+func TestConnContext(t *testing.T) {
+	client, _ := newConnPair(t, false)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.Read(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Read = %v, want context.DeadlineExceeded", err)
+	}
+	if err := client.Write(context.Background(), TextMessage, []byte("x")); err != context.DeadlineExceeded {
+		t.Fatalf("Write after timeout = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestConnReadLimit(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		client, server := newConnPair(t, compress)
+		server.SetReadLimit(1000)
+		go client.Write(context.Background(), BinaryMessage, make([]byte, 1001))
+		errc := make(chan error, 1)
+		go func() {
+			_, _, err := client.Read(context.Background())
+			errc <- err
+		}()
+		if _, _, err := server.Read(context.Background()); err == nil {
+			t.Errorf("compress=%v: Read of too big message succeeded", compress)
+		}
+		var ce *CloseError
+		if err := <-errc; !errors.As(err, &ce) || ce.Code != StatusMessageTooBig {
+			t.Errorf("compress=%v: client Read = %v, want CloseError 1009", compress, err)
+		}
+	}
+}
+
+func TestConnProtocolErrors(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		frames   [][]byte
+		compress bool
+		code     StatusCode
+	}{
+		{
+			name:   "unmasked",
+			frames: [][]byte{appendFrameHeader(nil, frameHeader{fin: true, op: opText})},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "reserved bits",
+			frames: [][]byte{clientFrame(finBit|rsv2Bit|byte(opText), "x")},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "RSV1 without compression",
+			frames: [][]byte{clientFrame(finBit|rsv1Bit|byte(opText), "x")},
+			code:   StatusProtocolError,
+		},
+		{
+			name:     "RSV1 on continuation",
+			frames:   [][]byte{clientFrame(rsv1Bit|byte(opText), "\x00"), clientFrame(finBit|rsv1Bit|byte(opContinuation), "x")},
+			compress: true,
+			code:     StatusProtocolError,
+		},
+		{
+			name:   "unknown opcode",
+			frames: [][]byte{clientFrame(finBit|0x3, "x")},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "fragmented ping",
+			frames: [][]byte{clientFrame(byte(opPing), "x")},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "long ping",
+			frames: [][]byte{clientFrame(finBit|byte(opPing), strings.Repeat("x", 126))},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "unexpected continuation",
+			frames: [][]byte{clientFrame(finBit|byte(opContinuation), "x")},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "interleaved messages",
+			frames: [][]byte{clientFrame(byte(opText), "x"), clientFrame(finBit|byte(opText), "y")},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "invalid UTF-8",
+			frames: [][]byte{clientFrame(finBit|byte(opText), "a\xffb")},
+			code:   StatusInvalidPayloadData,
+		},
+		{
+			name:   "truncated UTF-8",
+			frames: [][]byte{clientFrame(byte(opText), "a\xe2\x82"), clientFrame(finBit|byte(opContinuation), "")},
+			code:   StatusInvalidPayloadData,
+		},
+		{
+			name:     "corrupt compressed data",
+			frames:   [][]byte{clientFrame(finBit|rsv1Bit|byte(opBinary), "\xff\xff\xff")},
+			compress: true,
+			code:     StatusInvalidPayloadData,
+		},
+		{
+			name:   "invalid close code",
+			frames: [][]byte{clientFrame(finBit|byte(opClose), "\x03\xed")},
+			code:   StatusProtocolError,
+		},
+		{
+			name:   "one byte close payload",
+			frames: [][]byte{clientFrame(finBit|byte(opClose), "\x03")},
+			code:   StatusProtocolError,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server, raw := newRawConn(t, test.compress)
+			go func() {
+				for _, f := range test.frames {
+					raw.Write(f)
+				}
+			}()
+			errc := make(chan error, 1)
+			go func() {
+				_, _, err := server.Read(context.Background())
+				errc <- err
+			}()
+			h, p := readServerFrame(t, raw)
+			if h.op != opClose || len(p) < 2 {
+				t.Fatalf("got frame %+v, want close frame", h)
+			}
+			if code := StatusCode(p[0])<<8 | StatusCode(p[1]); code != test.code {
+				t.Errorf("close code = %v, want %v", code, test.code)
+			}
+			if err := <-errc; err == nil {
+				t.Errorf("Read succeeded")
+			}
+		})
+	}
+}
+
+func TestConnInterleavedControlFrames(t *testing.T) {
+	server, raw := newRawConn(t, false)
+	go func() {
+		raw.Write(clientFrame(byte(opText), "hel"))
+		raw.Write(clientFrame(finBit|byte(opPing), "ping"))
+		raw.Write(clientFrame(finBit|byte(opContinuation), "lo"))
+	}()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, data, err := server.Read(context.Background())
+		if err != nil || string(data) != "hello" {
+			t.Errorf("Read = %q, %v; want hello", data, err)
+		}
+	}()
+	h, p := readServerFrame(t, raw)
+	if h.op != opPong || string(p) != "ping" {
+		t.Errorf("got frame %+v %q, want pong", h, p)
+	}
+	<-done
+}
+
+func TestConnCloseFrameEcho(t *testing.T) {
+	server, raw := newRawConn(t, false)
+	go raw.Write(clientFrame(finBit|byte(opClose), "\x0b\xb8app reason"))
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := server.Read(context.Background())
+		errc <- err
+	}()
+	h, p := readServerFrame(t, raw)
+	if h.op != opClose || string(p) != "\x0b\xb8" {
+		t.Errorf("got frame %+v %q, want echoed close", h, p)
+	}
+	var ce *CloseError
+	if err := <-errc; !errors.As(err, &ce) || ce.Code != 3000 || ce.Reason != "app reason" {
+		t.Errorf("Read = %v, want CloseError 3000", err)
+	}
+}
+
+func TestUTF8Validator(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want bool
+	}{
+		{"hello", true},
+		{"héllo", true},
+		{"\xf0\x9f\x98\x80", true},
+		{"\xff", false},
+		{"\xed\xa0\x80", false}, // surrogate
+		{"\xe2\x82", false},     // incomplete
+		{"\xc0\x80", false},     // overlong
+	} {
+		for split := range len(test.in) + 1 {
+			var v utf8Validator
+			got := v.valid([]byte(test.in[:split])) && v.valid([]byte(test.in[split:])) && v.complete()
+			if got != test.want {
+				t.Errorf("%q split at %d: valid = %v, want %v", test.in, split, got, test.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/websocket"
+	"time"
+)
+
+func ExampleUpgrader() {
+	upgrader := &websocket.Upgrader{
+		Subprotocols:      []string{"chat.v1"},
+		EnableCompression: true,
+	}
+	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer c.CloseNow()
+		for {
+			typ, msg, err := c.Read(r.Context())
+			if err != nil {
+				return
+			}
+			if err := c.Write(r.Context(), typ, msg); err != nil {
+				return
+			}
+		}
+	})
+
+	// Accept WebSocket connections over HTTP/2 as well as HTTP/1.1.
+	srv := &http.Server{
+		Addr:  ":8443",
+		HTTP2: &http.HTTP2Config{EnableConnectProtocol: true},
+	}
+	log.Fatal(srv.ListenAndServeTLS("cert.pem", "key.pem"))
+}
+
+func ExampleDialer() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	d := &websocket.Dialer{Subprotocols: []string{"chat.v1"}}
+	c, _, err := d.Dial(ctx, "wss://example.com/ws")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.CloseNow()
+
+	if err := c.Write(ctx, websocket.TextMessage, []byte("hello")); err != nil {
+		log.Fatal(err)
+	}
+	_, msg, err := c.Read(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s\n", msg)
+	c.Close(websocket.StatusNormalClosure, "")
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// An opcode identifies the type of a frame. See RFC 6455, Section 5.2.
+type opcode byte
+
+const (
+	opContinuation opcode = 0x0
+	opText         opcode = 0x1
+	opBinary       opcode = 0x2
+	opClose        opcode = 0x8
+	opPing         opcode = 0x9
+	opPong         opcode = 0xa
+)
+
+func (op opcode) isControl() bool { return op&0x8 != 0 }
+
+const (
+	finBit  = 0x80
+	rsv1Bit = 0x40 // marks a compressed message (RFC 7692)
+	rsv2Bit = 0x20
+	rsv3Bit = 0x10
+	maskBit = 0x80
+
+	// maxControlPayload is the largest payload of a control frame.
+	maxControlPayload = 125
+
+	// maxFrameHeader is the size of the longest frame header.
+	maxFrameHeader = 2 + 8 + 4
+)
+
+// A frameHeader is the header of a WebSocket frame.
+type frameHeader struct {
+	fin    bool
+	rsv    byte // RSV1, RSV2 and RSV3 bits, in place
+	op     opcode
+	masked bool
+	key    [4]byte
+	length int64
+}
+
+var errFrameTooLong = errors.New("websocket: frame length overflows int64")
+
+// readFrameHeader reads a frame header from r.
+// It returns io.EOF only if r ends before the first byte of the header.
+func readFrameHeader(r io.Reader) (frameHeader, error) {
+	var buf [maxFrameHeader]byte
+	if _, err := io.ReadFull(r, buf[:2]); err != nil {
+		return frameHeader{}, err
+	}
+	h := frameHeader{
+		fin:    buf[0]&finBit != 0,
+		rsv:    buf[0] & (rsv1Bit | rsv2Bit | rsv3Bit),
+		op:     opcode(buf[0] & 0xf),
+		masked: buf[1]&maskBit != 0,
+		length: int64(buf[1] & 0x7f),
+	}
+	n := 0
+	switch h.length {
+	case 126:
+		n = 2
+	case 127:
+		n = 8
+	}
+	if h.masked {
+		n += 4
+	}
+	if _, err := io.ReadFull(r, buf[2:2+n]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return frameHeader{}, err
+	}
+	b := buf[2 : 2+n]
+	switch h.length {
+	case 126:
+		h.length = int64(binary.BigEndian.Uint16(b))
+		b = b[2:]
+	case 127:
+		l := binary.BigEndian.Uint64(b)
+		if l > math.MaxInt64 {
+			return frameHeader{}, errFrameTooLong
+		}
+		h.length = int64(l)
+		b = b[8:]
+	}
+	if h.masked {
+		copy(h.key[:], b)
+	}
+	return h, nil
+}
+
+// appendFrameHeader appends the encoding of h to b.
+func appendFrameHeader(b []byte, h frameHeader) []byte {
+	b0 := h.rsv | byte(h.op)
+	if h.fin {
+		b0 |= finBit
+	}
+	var b1 byte
+	if h.masked {
+		b1 = maskBit
+	}
+	switch {
+	case h.length <= 125:
+		b = append(b, b0, b1|byte(h.length))
+	case h.length <= math.MaxUint16:
+		b = append(b, b0, b1|126)
+		b = binary.BigEndian.AppendUint16(b, uint16(h.length))
+	default:
+		b = append(b, b0, b1|127)
+		b = binary.BigEndian.AppendUint64(b, uint64(h.length))
+	}
+	if h.masked {
+		b = append(b, h.key[:]...)
+	}
+	return b
+}
+
+// maskBytes applies the masking key to b, starting pos bytes into the
+// frame payload, and returns the payload position following b.
+// Masking and unmasking are the same operation.
+func maskBytes(key [4]byte, pos int, b []byte) int {
+	for i := range b {
+		b[i] ^= key[pos&3]
+		pos++
+	}
+	return pos & 3
+}
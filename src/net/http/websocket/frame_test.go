@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestFrameHeader(t *testing.T) {
+	for _, h := range []frameHeader{
+		{fin: true, op: opText, length: 0},
+		{fin: true, op: opBinary, length: 125},
+		{fin: false, op: opText, rsv: rsv1Bit, length: 126},
+		{fin: true, op: opContinuation, length: 65535},
+		{fin: true, op: opBinary, length: 65536},
+		{fin: true, op: opPing, masked: true, key: [4]byte{1, 2, 3, 4}, length: 5},
+		{fin: true, op: opBinary, masked: true, key: [4]byte{0xff, 0, 0xff, 0}, length: 1 << 40},
+	} {
+		b := appendFrameHeader(nil, h)
+		got, err := readFrameHeader(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("%+v: %v", h, err)
+			continue
+		}
+		if got != h {
+			t.Errorf("round trip of %+v = %+v", h, got)
+		}
+	}
+}
+
+func TestFrameHeaderEncoding(t *testing.T) {
+	// Examples from RFC 6455, Section 5.7.
+	for _, test := range []struct {
+		in   []byte
+		want frameHeader
+	}{
+		{
+			// A single-frame unmasked text message.
+			in:   []byte{0x81, 0x05},
+			want: frameHeader{fin: true, op: opText, length: 5},
+		},
+		{
+			// A single-frame masked text message.
+			in:   []byte{0x81, 0x85, 0x37, 0xfa, 0x21, 0x3d},
+			want: frameHeader{fin: true, op: opText, masked: true, key: [4]byte{0x37, 0xfa, 0x21, 0x3d}, length: 5},
+		},
+		{
+			// The first fragment of an unmasked text message.
+			in:   []byte{0x01, 0x03},
+			want: frameHeader{op: opText, length: 3},
+		},
+		{
+			// A 256-byte binary message in a single unmasked frame.
+			in:   []byte{0x82, 0x7e, 0x01, 0x00},
+			want: frameHeader{fin: true, op: opBinary, length: 256},
+		},
+		{
+			// A 64KiB binary message in a single unmasked frame.
+			in:   []byte{0x82, 0x7f, 0, 0, 0, 0, 0, 1, 0, 0},
+			want: frameHeader{fin: true, op: opBinary, length: 65536},
+		},
+	} {
+		got, err := readFrameHeader(bytes.NewReader(test.in))
+		if err != nil || got != test.want {
+			t.Errorf("readFrameHeader(% x) = %+v, %v; want %+v", test.in, got, err, test.want)
+		}
+		if b := appendFrameHeader(nil, test.want); !bytes.Equal(b, test.in) {
+			t.Errorf("appendFrameHeader(%+v) = % x, want % x", test.want, b, test.in)
+		}
+	}
+}
+
+func TestFrameHeaderErrors(t *testing.T) {
+	if _, err := readFrameHeader(bytes.NewReader(nil)); err != io.EOF {
+		t.Errorf("empty input: %v, want io.EOF", err)
+	}
+	if _, err := readFrameHeader(bytes.NewReader([]byte{0x82, 0xfe, 0x01})); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated header: %v, want io.ErrUnexpectedEOF", err)
+	}
+	b := []byte{0x82, 0x7f, 0x80, 0, 0, 0, 0, 0, 0, 0}
+	if _, err := readFrameHeader(bytes.NewReader(b)); err != errFrameTooLong {
+		t.Errorf("length with high bit set: %v, want errFrameTooLong", err)
+	}
+}
+
+func TestMaskBytes(t *testing.T) {
+	key := [4]byte{0x37, 0xfa, 0x21, 0x3d}
+	b := []byte("Hello")
+	maskBytes(key, 0, b)
+	if want := []byte{0x7f, 0x9f, 0x4d, 0x51, 0x58}; !bytes.Equal(b, want) {
+		t.Errorf("masked = % x, want % x", b, want)
+	}
+
+	// Masking in pieces is the same as masking at once.
+	pos := maskBytes(key, 0, b[:2])
+	pos = maskBytes(key, pos, b[2:3])
+	maskBytes(key, pos, b[3:])
+	if string(b) != "Hello" {
+		t.Errorf("unmasked = %q, want %q", b, "Hello")
+	}
+}
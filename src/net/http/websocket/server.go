@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// An Upgrader accepts WebSocket connections in an HTTP handler.
+// The zero value is a usable Upgrader with no subprotocols
+// and no compression.
+type Upgrader struct {
+	// Subprotocols lists the application subprotocols supported by
+	// the server, in order of preference. The first one that the
+	// client also requested is selected. If the client requests only
+	// subprotocols that aren't listed, none is selected, and the
+	// client typically fails the connection.
+	Subprotocols []string
+
+	// EnableCompression enables the permessage-deflate extension,
+	// which compresses messages when the client supports it.
+	EnableCompression bool
+
+	// CheckOrigin, if non-nil, reports whether to accept a request
+	// with an Origin header. Browsers send the Origin header with all
+	// WebSocket requests, and a server accepting requests from any
+	// origin is open to cross-site WebSocket hijacking.
+	//
+	// If CheckOrigin is nil, requests are accepted only if the host
+	// in the Origin header, if any, matches the request's Host.
+	CheckOrigin func(r *http.Request) bool
+}
+
+// Upgrade accepts r as the opening handshake of a WebSocket connection.
+//
+// Upgrade accepts both HTTP/1.1 requests to upgrade the connection
+// and HTTP/2 extended CONNECT requests. Response headers set in w
+// before calling Upgrade, such as cookies, are sent with the response
+// to the handshake. Upgrade may also set response headers to negotiate
+// the subprotocol and extensions.
+//
+// If the handshake fails, Upgrade replies to the client with an HTTP
+// error and returns an error. Otherwise, the handler must not use w
+// or r.Body afterwards. For an HTTP/1.1 connection, Upgrade takes over
+// the connection, and the returned [Conn] remains usable after the
+// handler returns. For HTTP/2, the [Conn] uses the request's stream,
+// which ends when the handler returns, so the handler must not return
+// until it is done with the Conn.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	h2 := r.Method == "CONNECT" && r.ProtoMajor >= 2
+	var key string
+	switch {
+	case h2:
+		if r.Header.Get(":protocol") != "websocket" {
+			return nil, u.reject(w, http.StatusBadRequest, "CONNECT request without :protocol websocket")
+		}
+	case r.Method != "GET":
+		w.Header().Set("Allow", "GET")
+		return nil, u.reject(w, http.StatusMethodNotAllowed, "method "+r.Method+" not allowed")
+	case !httpguts.HeaderValuesContainsToken(r.Header["Connection"], "upgrade"):
+		return nil, u.reject(w, http.StatusBadRequest, "missing Connection: upgrade header")
+	case !httpguts.HeaderValuesContainsToken(r.Header["Upgrade"], "websocket"):
+		return nil, u.reject(w, http.StatusBadRequest, "missing Upgrade: websocket header")
+	case !r.ProtoAtLeast(1, 1):
+		return nil, u.reject(w, http.StatusBadRequest, "HTTP/1.1 or later required")
+	default:
+		key = r.Header.Get("Sec-WebSocket-Key")
+		if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != 16 {
+			return nil, u.reject(w, http.StatusBadRequest, "invalid Sec-WebSocket-Key")
+		}
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, u.reject(w, http.StatusUpgradeRequired, "unsupported Sec-WebSocket-Version")
+	}
+	checkOrigin := u.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if _, ok := r.Header["Origin"]; ok && !checkOrigin(r) {
+		return nil, u.reject(w, http.StatusForbidden, "origin not allowed")
+	}
+
+	hdr := w.Header()
+	subprotocol := u.selectSubprotocol(r)
+	if subprotocol != "" {
+		hdr.Set("Sec-WebSocket-Protocol", subprotocol)
+	}
+	compress := false
+	if u.EnableCompression {
+		compress = slices.ContainsFunc(parseExtensions(r.Header), acceptDeflate)
+	}
+	if compress {
+		hdr.Set("Sec-WebSocket-Extensions", deflateHeader)
+	}
+
+	rc := http.NewResponseController(w)
+	if h2 {
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return nil, err
+		}
+		br := bufio.NewReader(r.Body)
+		bw := bufio.NewWriter(w)
+		return newConn(br, bw, rc.Flush, r.Body.Close, false, subprotocol, compress), nil
+	}
+
+	hdr.Set("Upgrade", "websocket")
+	hdr.Set("Connection", "Upgrade")
+	hdr.Set("Sec-WebSocket-Accept", acceptKey(key))
+	conn, brw, err := rc.Hijack()
+	if err != nil {
+		return nil, u.reject(w, http.StatusInternalServerError, "cannot take over connection: "+err.Error())
+	}
+	// Clear any deadlines set by the server's ReadTimeout and WriteTimeout.
+	conn.SetDeadline(time.Time{})
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	hdr.Write(brw)
+	brw.WriteString("\r\n")
+	if err := brw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return newConn(brw.Reader, brw.Writer, nil, conn.Close, false, subprotocol, compress), nil
+}
+
+// reject replies to a failed handshake.
+func (u *Upgrader) reject(w http.ResponseWriter, code int, msg string) error {
+	http.Error(w, http.StatusText(code), code)
+	return fmt.Errorf("%w: %s", ErrBadHandshake, msg)
+}
+
+// selectSubprotocol returns the subprotocol to use for r.
+func (u *Upgrader) selectSubprotocol(r *http.Request) string {
+	requested := subprotocols(r.Header)
+	for _, p := range u.Subprotocols {
+		if slices.Contains(requested, p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// subprotocols returns the subprotocols listed in the
+// Sec-WebSocket-Protocol headers in h.
+func subprotocols(h http.Header) []string {
+	var protos []string
+	for _, v := range h["Sec-Websocket-Protocol"] {
+		for p := range strings.SplitSeq(v, ",") {
+			if p = textproto.TrimString(p); p != "" {
+				protos = append(protos, p)
+			}
+		}
+	}
+	return protos
+}
+
+// sameOrigin reports whether the Origin header of r
+// names the host the request was sent to.
+func sameOrigin(r *http.Request) bool {
+	u, err := url.Parse(r.Header.Get("Origin"))
+	if err != nil {
+		return false
+	}
+	return ascii.EqualFold(u.Host, r.Host)
+}
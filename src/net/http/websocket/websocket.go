@@ -0,0 +1,110 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package websocket implements the WebSocket protocol defined in RFC 6455.
+//
+// A server accepts WebSocket connections with an [Upgrader] in an
+// HTTP handler, and a client opens them with a [Dialer]. Both sides
+// exchange messages over the resulting [Conn].
+//
+// WebSocket connections are carried either by an HTTP/1.1 connection
+// that switches protocols (RFC 6455, Section 4) or by a single HTTP/2
+// stream opened with the extended CONNECT method (RFC 8441).
+// A [Dialer] uses extended CONNECT when it reaches the server over
+// HTTP/2, and HTTP/1.1 otherwise. Servers accept extended CONNECT
+// requests only when [net/http.HTTP2Config.EnableConnectProtocol] is set.
+//
+// The permessage-deflate extension (RFC 7692) compresses messages when
+// both endpoints enable it. Neither endpoint keeps compression context
+// between messages.
+package websocket
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+// A MessageType is the type of a WebSocket data message.
+type MessageType int
+
+const (
+	// TextMessage denotes a message containing UTF-8 text.
+	TextMessage MessageType = 1
+
+	// BinaryMessage denotes a message containing binary data.
+	BinaryMessage MessageType = 2
+)
+
+func (t MessageType) String() string {
+	switch t {
+	case TextMessage:
+		return "text"
+	case BinaryMessage:
+		return "binary"
+	}
+	return "MessageType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// A StatusCode is the status code of a close frame,
+// indicating why a connection was closed.
+// See RFC 6455, Section 7.4.
+type StatusCode int
+
+const (
+	StatusNormalClosure      StatusCode = 1000
+	StatusGoingAway          StatusCode = 1001
+	StatusProtocolError      StatusCode = 1002
+	StatusUnsupportedData    StatusCode = 1003
+	StatusNoStatusReceived   StatusCode = 1005 // never sent; a close frame had no status
+	StatusAbnormalClosure    StatusCode = 1006 // never sent
+	StatusInvalidPayloadData StatusCode = 1007
+	StatusPolicyViolation    StatusCode = 1008
+	StatusMessageTooBig      StatusCode = 1009
+	StatusMandatoryExtension StatusCode = 1010
+	StatusInternalError      StatusCode = 1011
+	StatusServiceRestart     StatusCode = 1012
+	StatusTryAgainLater      StatusCode = 1013
+	StatusBadGateway         StatusCode = 1014
+	StatusTLSHandshake       StatusCode = 1015 // never sent
+)
+
+// valid reports whether c may appear in a close frame.
+func (c StatusCode) valid() bool {
+	switch {
+	case 1000 <= c && c <= 1003, 1007 <= c && c <= 1014:
+		return true
+	case 3000 <= c && c <= 4999:
+		// Registered with IANA (3000-3999) or private use (4000-4999).
+		return true
+	}
+	return false
+}
+
+// A CloseError is returned by the read methods of a [Conn]
+// after the peer has closed the connection.
+type CloseError struct {
+	Code   StatusCode
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	s := "websocket: connection closed with status " + strconv.Itoa(int(e.Code))
+	if e.Reason != "" {
+		s += ": " + e.Reason
+	}
+	return s
+}
+
+// ErrBadHandshake is returned, possibly wrapped, when the opening
+// handshake of a connection is invalid.
+var ErrBadHandshake = errors.New("websocket: bad handshake")
+
+// acceptKey returns the Sec-WebSocket-Accept value for a Sec-WebSocket-Key.
+func acceptKey(key string) string {
+	const guid = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	sum := sha1.Sum([]byte(key + guid))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer starts a server running handler on WebSocket
+// connections accepted with u, over HTTP/1.1 or HTTP/2.
+func newTestServer(t *testing.T, http2 bool, u *Upgrader, handler func(*Conn)) *httptest.Server {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.ProtoMajor == 2; got != http2 {
+			t.Errorf("request protocol is %v, want HTTP/2 %v", r.Proto, http2)
+		}
+		c, err := u.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer c.CloseNow()
+		handler(c)
+	})
+	ts := httptest.NewUnstartedServer(h)
+	if http2 {
+		ts.EnableHTTP2 = true
+		ts.Config.HTTP2 = &http.HTTP2Config{EnableConnectProtocol: true}
+		ts.StartTLS()
+	} else {
+		ts.Start()
+	}
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func echo(c *Conn) {
+	for {
+		typ, data, err := c.Read(context.Background())
+		if err != nil {
+			return
+		}
+		if err := c.Write(context.Background(), typ, data); err != nil {
+			return
+		}
+	}
+}
+
+func wsURL(ts *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+func TestDialEcho(t *testing.T) {
+	for _, mode := range []struct {
+		name     string
+		http2    bool
+		compress bool
+	}{
+		{"HTTP/1.1", false, false},
+		{"HTTP/1.1 compressed", false, true},
+		{"HTTP/2", true, false},
+		{"HTTP/2 compressed", true, true},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			u := &Upgrader{EnableCompression: true}
+			ts := newTestServer(t, mode.http2, u, echo)
+			d := &Dialer{Client: ts.Client(), EnableCompression: mode.compress}
+			c, resp, err := d.Dial(context.Background(), wsURL(ts))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.CloseNow()
+			if got := resp.ProtoMajor == 2; got != mode.http2 {
+				t.Errorf("response protocol is %v", resp.Proto)
+			}
+			if c.compress != mode.compress {
+				t.Errorf("compression negotiated = %v, want %v", c.compress, mode.compress)
+			}
+
+			msgs := []string{"hello", "", strings.Repeat("websocket ", 10000)}
+			for _, m := range msgs {
+				if err := c.Write(context.Background(), TextMessage, []byte(m)); err != nil {
+					t.Fatal(err)
+				}
+				typ, data, err := c.Read(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if typ != TextMessage || string(data) != m {
+					t.Errorf("echo of %d bytes = %v message of %d bytes", len(m), typ, len(data))
+				}
+			}
+
+			go io.Copy(io.Discard, readerOf(c))
+			if err := c.Ping(context.Background()); err != nil {
+				t.Errorf("Ping = %v", err)
+			}
+			if err := c.Close(StatusNormalClosure, ""); err != nil {
+				t.Errorf("Close = %v", err)
+			}
+		})
+	}
+}
+
+// readerOf returns a reader that reads messages from c until an error.
+func readerOf(c *Conn) io.Reader {
+	return readerFunc(func(p []byte) (int, error) {
+		_, _, err := c.Read(context.Background())
+		return 0, err
+	})
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
+
+func TestDialServerClose(t *testing.T) {
+	for _, http2 := range []bool{false, true} {
+		ts := newTestServer(t, http2, &Upgrader{}, func(c *Conn) {
+			c.Write(context.Background(), TextMessage, []byte("goodbye"))
+			c.Close(StatusGoingAway, "shutting down")
+		})
+		c, _, err := (&Dialer{Client: ts.Client()}).Dial(context.Background(), ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, data, err := c.Read(context.Background()); err != nil || string(data) != "goodbye" {
+			t.Fatalf("http2=%v: Read = %q, %v", http2, data, err)
+		}
+		_, _, err = c.Read(context.Background())
+		var ce *CloseError
+		if !errors.As(err, &ce) || ce.Code != StatusGoingAway || ce.Reason != "shutting down" {
+			t.Errorf("http2=%v: Read = %v, want CloseError 1001", http2, err)
+		}
+		c.CloseNow()
+	}
+}
+
+func TestSubprotocol(t *testing.T) {
+	u := &Upgrader{Subprotocols: []string{"v2", "v1"}}
+	ts := newTestServer(t, false, u, func(c *Conn) {
+		c.Write(context.Background(), TextMessage, []byte(c.Subprotocol()))
+	})
+	for _, test := range []struct {
+		requested []string
+		want      string
+	}{
+		{[]string{"v1", "v2"}, "v2"},
+		{[]string{"v1"}, "v1"},
+		{[]string{"v3"}, ""},
+		{nil, ""},
+	} {
+		d := &Dialer{Client: ts.Client(), Subprotocols: test.requested}
+		c, _, err := d.Dial(context.Background(), wsURL(ts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Subprotocol(); got != test.want {
+			t.Errorf("requested %q: client Subprotocol = %q, want %q", test.requested, got, test.want)
+		}
+		if _, data, _ := c.Read(context.Background()); string(data) != test.want {
+			t.Errorf("requested %q: server Subprotocol = %q, want %q", test.requested, data, test.want)
+		}
+		c.CloseNow()
+	}
+}
+
+func TestUpgradeErrors(t *testing.T) {
+	ts := newTestServer(t, false, &Upgrader{}, echo)
+	for _, test := range []struct {
+		name   string
+		method string
+		header http.Header
+		status int
+	}{
+		{
+			name:   "plain GET",
+			method: "GET",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "POST",
+			method: "POST",
+			header: http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}},
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "bad key",
+			method: "GET",
+			header: http.Header{
+				"Connection":            {"Upgrade"},
+				"Upgrade":               {"websocket"},
+				"Sec-Websocket-Version": {"13"},
+				"Sec-Websocket-Key":     {"short"},
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "bad version",
+			method: "GET",
+			header: http.Header{
+				"Connection":            {"keep-alive, Upgrade"},
+				"Upgrade":               {"websocket"},
+				"Sec-Websocket-Version": {"8"},
+				"Sec-Websocket-Key":     {"dGhlIHNhbXBsZSBub25jZQ=="},
+			},
+			status: http.StatusUpgradeRequired,
+		},
+		{
+			name:   "cross origin",
+			method: "GET",
+			header: http.Header{
+				"Connection":            {"Upgrade"},
+				"Upgrade":               {"websocket"},
+				"Sec-Websocket-Version": {"13"},
+				"Sec-Websocket-Key":     {"dGhlIHNhbXBsZSBub25jZQ=="},
+				"Origin":                {"https://evil.example"},
+			},
+			status: http.StatusForbidden,
+		},
+	} {
+		req, _ := http.NewRequest(test.method, ts.URL, nil)
+		for k, v := range test.header {
+			req.Header[k] = v
+		}
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("%v: status = %v, want %v", test.name, resp.StatusCode, test.status)
+		}
+	}
+}
+
+func TestUpgradeAcceptKey(t *testing.T) {
+	// Example from RFC 6455, Section 1.3.
+	if got, want := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("acceptKey = %q, want %q", got, want)
+	}
+}
+
+func TestUpgradeSameOrigin(t *testing.T) {
+	ts := newTestServer(t, false, &Upgrader{}, echo)
+	d := &Dialer{Client: ts.Client(), Header: http.Header{"Origin": {ts.URL}}}
+	c, _, err := d.Dial(context.Background(), wsURL(ts))
+	if err != nil {
+		t.Fatalf("Dial with same origin: %v", err)
+	}
+	c.CloseNow()
+}
+
+func TestDialBadHandshake(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "not a websocket server")
+	}))
+	defer ts.Close()
+	_, resp, err := (&Dialer{Client: ts.Client()}).Dial(context.Background(), wsURL(ts))
+	if !errors.Is(err, ErrBadHandshake) {
+		t.Errorf("Dial = %v, want ErrBadHandshake", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Dial response = %v, want 200 OK", resp)
+	}
+}
+
+func TestDialHTTP2WithoutExtendedConnect(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request")
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+	if _, _, err := (&Dialer{Client: ts.Client()}).Dial(context.Background(), ts.URL); err == nil {
+		t.Errorf("Dial succeeded without server support for extended CONNECT")
+	}
+}
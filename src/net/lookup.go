@@ -160,6 +160,29 @@ type Resolver struct {
 	// If nil, the default dialer is used.
 	Dial func(ctx context.Context, network, address string) (Conn, error)
 
+	// Exchange optionally specifies a function that sends a DNS query
+	// and returns the response, for use by Go's built-in DNS resolver
+	// instead of querying the name servers in the system configuration
+	// over UDP and TCP. Both query and the response are DNS messages in
+	// wire format, as defined in RFC 1035, Section 4. Responses whose ID
+	// or question section don't match the query are rejected. The
+	// response must not be modified after Exchange returns, since it
+	// may be kept in the Cache.
+	//
+	// Exchange can be used to send queries over encrypted transports.
+	// Package [net/securedns] implements DNS over TLS and DNS over HTTPS.
+	//
+	// If Exchange is set, Go's built-in DNS resolver is always used, and
+	// Dial is ignored. The number of attempts and the timeout of each
+	// attempt still come from the system configuration.
+	Exchange func(ctx context.Context, query []byte) ([]byte, error)
+
+	// Cache optionally specifies a cache of DNS responses for use by
+	// Go's built-in DNS resolver. If nil, every lookup that isn't
+	// answered by the hosts file queries the name servers.
+	// The cache isn't used by other resolvers; see PreferGo.
+	Cache *DNSCache
+
 	// lookupGroup merges LookupIPAddr calls together for lookups for the same
 	// host. The lookupGroup key is the LookupIPAddr.host argument.
 	// The return values are ([]IPAddr, error).
@@ -171,6 +194,14 @@ type Resolver struct {
 
 func (r *Resolver) preferGo() bool     { return r != nil && r.PreferGo }
 func (r *Resolver) strictErrors() bool { return r != nil && r.StrictErrors }
+func (r *Resolver) hasExchange() bool  { return r != nil && r.Exchange != nil }
+
+func (r *Resolver) getCache() *DNSCache {
+	if r == nil {
+		return nil
+	}
+	return r.Cache
+}
 
 func (r *Resolver) getLookupGroup() *singleflight.Group {
 	if r == nil {
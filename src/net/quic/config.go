@@ -8,9 +8,8 @@ import (
 	"crypto/tls"
 	"log/slog"
 	"math"
+	"net/quic/internal/quicwire"
 	"time"
-
-	"golang.org/x/net/internal/quic/quicwire"
 )
 
 // A Config structure configures a QUIC endpoint.
@@ -57,6 +56,9 @@ type Config struct {
 	// Enabling this setting reduces the amount of work packets with spoofed
 	// source address information can cause a server to perform,
 	// at the cost of increased handshake latency.
+	//
+	// Address validation is not supported in FIPS 140-only mode, where
+	// Listen and NewEndpoint return an error if it is required.
 	RequireAddressValidation bool
 
 	// StatelessResetKey is used to provide stateless reset of connections.
@@ -96,14 +98,35 @@ type Config struct {
 	// half the connection idle timeout.
 	KeepAlivePeriod time.Duration
 
+	// Enable0RTT permits sending and accepting 0-RTT data on resumed connections.
+	//
+	// A client which resumes a session permitting 0-RTT returns from
+	// [Endpoint.Dial] without waiting for the handshake to complete,
+	// and may send data on the connection immediately.
+	// A server issues session tickets permitting 0-RTT,
+	// and accepts 0-RTT data from clients which resume them.
+	//
+	// 0-RTT data is not protected against replay.
+	// An attacker may capture and resend it, causing the server to process it more than once.
+	// Applications should send data before the handshake completes
+	// only when doing so is safe. See RFC 9001, Section 9.2.
+	//
+	// Session resumption requires the client's TLSConfig to set a ClientSessionCache.
+	// 0-RTT additionally requires the endpoints to negotiate an application protocol
+	// using TLSConfig.NextProtos.
+	Enable0RTT bool
+
+	// EnableDatagrams permits sending and receiving unreliable datagrams
+	// using the QUIC DATAGRAM extension (RFC 9221).
+	// Datagrams may be used only when both endpoints enable them.
+	// See [Conn.SendDatagram] and [Conn.ReceiveDatagram].
+	EnableDatagrams bool
+
 	// QLogLogger receives qlog events.
 	//
 	// Events currently correspond to the definitions in draft-ietf-qlog-quic-events-03.
 	// This is not the latest version of the draft, but is the latest version supported
 	// by common event log viewers as of the time this paragraph was written.
-	//
-	// The qlog package contains a slog.Handler which serializes qlog events
-	// to a standard JSON representation.
 	QLogLogger *slog.Logger
 }
 
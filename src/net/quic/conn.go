@@ -13,6 +13,7 @@ import (
 	"log/slog"
 	"math/rand/v2"
 	"net/netip"
+	"sync"
 	"time"
 )
 
@@ -24,9 +25,13 @@ type Conn struct {
 	endpoint  *Endpoint
 	config    *Config
 	testHooks connTestHooks
+	prng      *rand.Rand
+
+	// The conn loop may read peerAddr and localAddr without holding addrMu,
+	// but must hold it to change them.
+	addrMu    sync.Mutex
 	peerAddr  netip.AddrPort
 	localAddr netip.AddrPort
-	prng      *rand.Rand
 
 	msgc  chan any
 	donec chan struct{} // closed when conn loop exits
@@ -38,12 +43,15 @@ type Conn struct {
 	connIDState connIDState
 	loss        lossState
 	streams     streamsState
+	datagrams   datagramsState
+	earlyData   earlyDataState
 	path        pathState
 	skip        skipState
 
 	// Packet protection keys, CRYPTO streams, and TLS state.
 	keysInitial   fixedKeyPair
 	keysHandshake fixedKeyPair
+	keysEarly     fixedKeyPair // 0-RTT keys; written by clients, read by servers
 	keysAppData   updatingKeyPair
 	crypto        [numberSpaceCount]cryptoStream
 	tls           *tls.QUICConn
@@ -141,10 +149,15 @@ func newConn(now time.Time, side connSide, cids newServerConnIDs, peerHostname s
 	c.keysAppData.init()
 	c.loss.init(c.side, smallestMaxDatagramSize, now)
 	c.streamsInit()
+	c.datagramsInit()
 	c.lifetimeInit()
 	c.restartIdleTimer(now)
 	c.skip.init(c)
 
+	var maxDatagramFrameSize int64
+	if config.EnableDatagrams {
+		maxDatagramFrameSize = maxUDPPayloadSize
+	}
 	if err := c.startTLS(now, initialConnID, peerHostname, transportParameters{
 		initialSrcConnID:               c.connIDState.srcConnID(),
 		originalDstConnID:              cids.originalDstConnID,
@@ -152,7 +165,6 @@ func newConn(now time.Time, side connSide, cids newServerConnIDs, peerHostname s
 		ackDelayExponent:               ackDelayExponent,
 		maxUDPPayloadSize:              maxUDPPayloadSize,
 		maxAckDelay:                    maxAckDelay,
-		disableActiveMigration:         true,
 		initialMaxData:                 config.maxConnReadBufferSize(),
 		initialMaxStreamDataBidiLocal:  config.maxStreamReadBufferSize(),
 		initialMaxStreamDataBidiRemote: config.maxStreamReadBufferSize(),
@@ -160,6 +172,7 @@ func newConn(now time.Time, side connSide, cids newServerConnIDs, peerHostname s
 		initialMaxStreamsBidi:          c.streams.remoteLimit[bidiStream].max,
 		initialMaxStreamsUni:           c.streams.remoteLimit[uniStream].max,
 		activeConnIDLimit:              activeConnIDLimit,
+		maxDatagramFrameSize:           maxDatagramFrameSize,
 	}); err != nil {
 		return nil, err
 	}
@@ -172,19 +185,33 @@ func newConn(now time.Time, side connSide, cids newServerConnIDs, peerHostname s
 }
 
 func (c *Conn) String() string {
-	return fmt.Sprintf("quic.Conn(%v,->%v)", c.side, c.peerAddr)
+	return fmt.Sprintf("quic.Conn(%v,->%v)", c.side, c.RemoteAddr())
 }
 
 // LocalAddr returns the local network address, if known.
+// The local address changes when a client migrates to a new endpoint.
 func (c *Conn) LocalAddr() netip.AddrPort {
+	c.addrMu.Lock()
+	defer c.addrMu.Unlock()
 	return c.localAddr
 }
 
 // RemoteAddr returns the remote network address, if known.
+// The remote address of a server connection changes when the client migrates.
 func (c *Conn) RemoteAddr() netip.AddrPort {
+	c.addrMu.Lock()
+	defer c.addrMu.Unlock()
 	return c.peerAddr
 }
 
+// setAddrs sets the conn's local and remote addresses.
+func (c *Conn) setAddrs(localAddr, peerAddr netip.AddrPort) {
+	c.addrMu.Lock()
+	defer c.addrMu.Unlock()
+	c.localAddr = localAddr
+	c.peerAddr = peerAddr
+}
+
 // ConnectionState returns basic TLS details about the connection.
 func (c *Conn) ConnectionState() tls.ConnectionState {
 	return c.tls.ConnectionState()
@@ -215,6 +242,13 @@ func (c *Conn) confirmHandshake(now time.Time) {
 	// "An endpoint MUST discard its Handshake keys when the TLS handshake is confirmed"
 	// https://www.rfc-editor.org/rfc/rfc9001#section-4.9.2-1
 	c.discardKeys(now, handshakeSpace)
+	// "Servers MAY temporarily retain 0-RTT keys to allow decrypting
+	// reordered packets without requiring their contents to be
+	// retransmitted with 1-RTT keys."
+	// https://www.rfc-editor.org/rfc/rfc9001#section-4.9.3-2
+	//
+	// We don't bother: Any reordered 0-RTT packets will be retransmitted.
+	c.keysEarly.discard()
 }
 
 // discardKeys discards unused packet protection keys.
@@ -238,6 +272,10 @@ func (c *Conn) receiveTransportParameters(p transportParameters) error {
 	if err := c.connIDState.validateTransportParameters(c, isRetry, p); err != nil {
 		return err
 	}
+	if err := c.checkRememberedParams(p); err != nil {
+		return err
+	}
+	c.earlyData.peerParams = p
 	c.streams.outflow.setMaxData(p.initialMaxData)
 	c.streams.localLimit[bidiStream].setMax(p.initialMaxStreamsBidi)
 	c.streams.localLimit[uniStream].setMax(p.initialMaxStreamsUni)
@@ -247,6 +285,7 @@ func (c *Conn) receiveTransportParameters(p transportParameters) error {
 	c.receivePeerMaxIdleTimeout(p.maxIdleTimeout)
 	c.peerAckDelayExponent = p.ackDelayExponent
 	c.loss.setMaxAckDelay(p.maxAckDelay)
+	c.setPeerMaxDatagramFrameSize(p.maxDatagramFrameSize)
 	if err := c.connIDState.setPeerActiveConnIDLimit(c, p.activeConnIDLimit); err != nil {
 		return err
 	}
@@ -261,9 +300,9 @@ func (c *Conn) receiveTransportParameters(p transportParameters) error {
 			return err
 		}
 	}
+	c.path.peerDisableActiveMigration = p.disableActiveMigration
 	// TODO: stateless_reset_token
 	// TODO: max_udp_payload_size
-	// TODO: disable_active_migration
 	// TODO: preferred_address
 	return nil
 }
@@ -304,6 +343,7 @@ func (c *Conn) loop(now time.Time) {
 		if c.isAlive() {
 			nextTimeout = firstTime(nextTimeout, c.loss.timer)
 			nextTimeout = firstTime(nextTimeout, c.acks[appDataSpace].nextAck)
+			nextTimeout = firstTime(nextTimeout, c.pathTimer())
 		} else {
 			nextTimeout = firstTime(nextTimeout, c.lifetime.drainEndTime)
 		}
@@ -342,6 +382,7 @@ func (c *Conn) loop(now time.Time) {
 				return
 			}
 			c.loss.advance(now, c.handleAckOrLoss)
+			c.pathAdvance(now)
 			if c.lifetimeAdvance(now) {
 				// The connection has completed the draining period,
 				// and may be shut down.
@@ -107,6 +107,7 @@ func (c *Conn) setState(now time.Time, state connState) {
 	if state != connStateAlive {
 		c.restartIdleTimer(now) // disable idle timer
 		c.streamsCleanup()
+		c.datagramsCleanup()
 	}
 }
 
@@ -0,0 +1,157 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quic
+
+import (
+	"context"
+	"errors"
+	"net/quic/internal/quicwire"
+	"sync"
+	"time"
+)
+
+// Maximum number of received or unsent datagrams we queue.
+// When a queue is full, additional datagrams are dropped.
+const maxQueuedDatagrams = 128
+
+// datagramsState is the state of the QUIC unreliable datagram extension.
+// https://www.rfc-editor.org/rfc/rfc9221
+type datagramsState struct {
+	recv queue[[]byte] // received datagrams
+
+	// peerMaxFrameSize is the peer's max_datagram_frame_size transport parameter.
+	// Zero if the peer does not support datagrams.
+	peerMaxFrameSize int64
+
+	sendMu sync.Mutex
+	send   [][]byte // datagrams waiting to be sent; guarded by sendMu
+	closed bool     // guarded by sendMu
+}
+
+var (
+	errDatagramsDisabled = errors.New("quic: datagrams are not enabled")
+	errDatagramTooLarge  = errors.New("quic: datagram too large")
+)
+
+func (c *Conn) datagramsInit() {
+	c.datagrams.recv = newQueue[[]byte]()
+}
+
+func (c *Conn) datagramsCleanup() {
+	c.datagrams.recv.close(errConnClosed)
+	c.datagrams.sendMu.Lock()
+	defer c.datagrams.sendMu.Unlock()
+	c.datagrams.closed = true
+	c.datagrams.send = nil
+}
+
+// setPeerMaxDatagramFrameSize sets the max_datagram_frame_size transport parameter
+// received from the peer.
+func (c *Conn) setPeerMaxDatagramFrameSize(size int64) {
+	c.datagrams.sendMu.Lock()
+	defer c.datagrams.sendMu.Unlock()
+	c.datagrams.peerMaxFrameSize = size
+}
+
+// maxDatagramPayloadSize is the largest datagram payload which fits in a
+// 1-RTT packet in a minimum-size datagram: The datagram size, less the
+// 1-RTT header (1 byte of type, a connection ID, and up to 4 bytes of packet number),
+// the DATAGRAM frame header (1 byte of type and a 2-byte length),
+// and AEAD overhead.
+const maxDatagramPayloadSize = smallestMaxDatagramSize - (1 + connIDLen + 4) - (1 + 2) - aeadOverhead
+
+// SendDatagram sends an unreliable datagram to the peer.
+//
+// Datagrams may be lost, and are not retransmitted.
+// SendDatagram does not wait for the datagram to be sent.
+// If many datagrams are waiting to be sent, the oldest are dropped.
+//
+// SendDatagram returns an error if datagrams are not enabled
+// by both endpoints (see [Config.EnableDatagrams]),
+// or if b is too large to fit in a single QUIC packet.
+func (c *Conn) SendDatagram(b []byte) error {
+	if !c.config.EnableDatagrams {
+		return errDatagramsDisabled
+	}
+	c.datagrams.sendMu.Lock()
+	defer c.datagrams.sendMu.Unlock()
+	if c.datagrams.closed {
+		return errConnClosed
+	}
+	if c.datagrams.peerMaxFrameSize == 0 {
+		return errors.New("quic: peer does not support datagrams")
+	}
+	if len(b) > maxDatagramPayloadSize || int64(1+quicwire.SizeVarint(uint64(len(b)))+len(b)) > c.datagrams.peerMaxFrameSize {
+		return errDatagramTooLarge
+	}
+	if len(c.datagrams.send) >= maxQueuedDatagrams {
+		c.datagrams.send[0] = nil
+		c.datagrams.send = c.datagrams.send[1:]
+	}
+	c.datagrams.send = append(c.datagrams.send, append([]byte(nil), b...))
+	c.wake()
+	return nil
+}
+
+// ReceiveDatagram waits for and returns the next datagram received from the peer.
+//
+// If the application does not read datagrams promptly,
+// newly received datagrams are dropped.
+func (c *Conn) ReceiveDatagram(ctx context.Context) ([]byte, error) {
+	if !c.config.EnableDatagrams {
+		return nil, errDatagramsDisabled
+	}
+	return c.datagrams.recv.get(ctx)
+}
+
+func (c *Conn) handleDatagramFrame(now time.Time, payload []byte) int {
+	data, n := consumeDatagramFrame(payload)
+	if n < 0 {
+		return -1
+	}
+	if !c.config.EnableDatagrams || int64(n) > maxUDPPayloadSize {
+		// "An endpoint that receives a DATAGRAM frame when it has not indicated
+		// support via the transport parameter MUST terminate the connection
+		// with an error of type PROTOCOL_VIOLATION. Similarly, an endpoint that
+		// receives a DATAGRAM frame that is larger than the value it sent in
+		// its max_datagram_frame_size transport parameter MUST terminate the
+		// connection with an error of type PROTOCOL_VIOLATION."
+		// https://www.rfc-editor.org/rfc/rfc9221#section-3-4
+		c.abort(now, localTransportError{
+			code:   errProtocolViolation,
+			reason: "unexpected DATAGRAM frame",
+		})
+		return -1
+	}
+	if c.datagrams.recv.len() < maxQueuedDatagrams {
+		c.datagrams.recv.put(cloneBytes(data))
+	}
+	return n
+}
+
+// appendDatagramFrames appends queued DATAGRAM frames to the current packet.
+//
+// It returns true if no more frames need appending,
+// false if not everything fit in the current packet.
+func (c *Conn) appendDatagramFrames() bool {
+	c.datagrams.sendMu.Lock()
+	defer c.datagrams.sendMu.Unlock()
+	for len(c.datagrams.send) > 0 {
+		b := c.datagrams.send[0]
+		if !c.w.appendDatagramFrame(b) {
+			if len(c.w.payload()) > 0 && !c.w.sent.ackEliciting {
+				// The packet contains only ACK frames, which would be
+				// abandoned if we add nothing else to it.
+				// Send them on their own, and put the datagram in the next packet.
+				// SendDatagram ensures the datagram fits in an empty packet.
+				c.w.appendPingFrame()
+			}
+			return false
+		}
+		c.datagrams.send[0] = nil
+		c.datagrams.send = c.datagrams.send[1:]
+	}
+	return true
+}
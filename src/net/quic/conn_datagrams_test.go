@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDatagramsLoopback(t *testing.T) {
+	serverConfig, clientConfig := newTestConfigs(t)
+	serverConfig.EnableDatagrams = true
+	clientConfig.EnableDatagrams = true
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, test := range []struct {
+		name     string
+		from, to *Conn
+	}{
+		{"client to server", cc, sc},
+		{"server to client", sc, cc},
+	} {
+		// Loopback shouldn't drop datagrams, but send a few in case it does.
+		const count = 10
+		for i := range count {
+			if err := test.from.SendDatagram(fmt.Appendf(nil, "datagram %v", i)); err != nil {
+				t.Fatalf("%v: SendDatagram: %v", test.name, err)
+			}
+		}
+		b, err := test.to.ReceiveDatagram(ctx)
+		if err != nil {
+			t.Fatalf("%v: ReceiveDatagram: %v", test.name, err)
+		}
+		var i int
+		if _, err := fmt.Sscanf(string(b), "datagram %d", &i); err != nil || i < 0 || i >= count {
+			t.Errorf("%v: received datagram %q, want one we sent", test.name, b)
+		}
+	}
+
+	big := make([]byte, maxDatagramPayloadSize+1)
+	if err := cc.SendDatagram(big); err == nil {
+		t.Errorf("SendDatagram(%v bytes) succeeded, want error", len(big))
+	}
+	if err := cc.SendDatagram(big[:maxDatagramPayloadSize]); err != nil {
+		t.Errorf("SendDatagram(%v bytes) = %v, want success", maxDatagramPayloadSize, err)
+	}
+	for {
+		b, err := sc.ReceiveDatagram(ctx)
+		if err != nil {
+			t.Fatalf("ReceiveDatagram: %v, want %v-byte datagram", err, maxDatagramPayloadSize)
+		}
+		if len(b) == maxDatagramPayloadSize {
+			break
+		}
+		// Skip any small datagrams left over from earlier in the test.
+	}
+
+	cc.Abort(nil)
+	for {
+		if _, err := sc.ReceiveDatagram(ctx); err != nil {
+			if ctx.Err() != nil {
+				t.Errorf("ReceiveDatagram did not return an error after the peer closed the connection")
+			}
+			break
+		}
+	}
+}
+
+func TestDatagramsNotEnabled(t *testing.T) {
+	serverConfig, clientConfig := newTestConfigs(t)
+	serverConfig.EnableDatagrams = true
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+	if err := cc.SendDatagram([]byte("x")); err == nil {
+		t.Errorf("client SendDatagram with datagrams disabled succeeded, want error")
+	}
+	if err := sc.SendDatagram([]byte("x")); err == nil {
+		t.Errorf("server SendDatagram to peer without datagram support succeeded, want error")
+	}
+}
@@ -404,6 +404,49 @@ func (s *connIDState) handleRetireConnID(c *Conn, seq int64) error {
 	return nil
 }
 
+// unusedRemoteConnID returns a remote connection ID other than the one
+// currently in use, for use on a new path.
+func (s *connIDState) unusedRemoteConnID() (connID, bool) {
+	for _, rcid := range s.remote[1:] {
+		if rcid.seq >= 0 {
+			return rcid.connID, true
+		}
+	}
+	return connID{}, false
+}
+
+// useRemoteConnID starts using the remote connection ID with the given sequence number
+// as the destination of packets we send, and retires the one previously in use.
+func (s *connIDState) useRemoteConnID(c *Conn, seq int64) {
+	i := slices.IndexFunc(s.remote, func(rcid remoteConnID) bool {
+		return rcid.seq == seq
+	})
+	if i <= 0 {
+		// This is the ID already in use, or the peer has retired it.
+		return
+	}
+	s.remote[0], s.remote[i] = s.remote[i], s.remote[0]
+	s.retireRemoteConnID(c, s.remote[i].seq)
+}
+
+// retireRemoteConnID retires the remote connection ID with the given sequence number.
+// It must not be the one currently in use.
+func (s *connIDState) retireRemoteConnID(c *Conn, seq int64) {
+	i := slices.IndexFunc(s.remote, func(rcid remoteConnID) bool {
+		return rcid.seq == seq
+	})
+	if i <= 0 {
+		return
+	}
+	token := s.remote[i].resetToken
+	c.endpoint.connsMap.updateConnIDs(func(conns *connsMap) {
+		conns.retireResetToken(c, token)
+	})
+	s.remote = slices.Delete(s.remote, i, i+1)
+	s.remoteRetiring.add(seq, seq+1)
+	s.needSend = true
+}
+
 func (s *connIDState) ackOrLossNewConnectionID(pnum packetNumber, seq int64, fate packetFate) {
 	for i := range s.local {
 		if s.local[i].seq != seq {
@@ -16,7 +16,7 @@ func (c *Conn) handleDatagram(now time.Time, dgram *datagram) (handled bool) {
 		// We don't have any way to tell in the general case what address we're
 		// sending packets from. Set our address from the destination address of
 		// the first packet received from the peer.
-		c.localAddr = dgram.localAddr
+		c.setAddrs(dgram.localAddr, c.peerAddr)
 	}
 	if dgram.peerAddr.IsValid() && dgram.peerAddr != c.peerAddr {
 		if c.side == clientSide {
@@ -25,9 +25,15 @@ func (c *Conn) handleDatagram(now time.Time, dgram *datagram) (handled bool) {
 			// https://www.rfc-editor.org/rfc/rfc9000#section-9-6
 			return false
 		}
-		// We currently don't support connection migration,
-		// so for now the server also drops packets from an unknown address.
-		return false
+		// "An endpoint MUST NOT initiate connection migration before
+		// the handshake is confirmed [...]. If the peer violates this
+		// requirement, the endpoint MUST either drop the incoming packets
+		// on that path without generating a Stateless Reset or proceed
+		// with path validation and allow the peer to migrate."
+		// https://www.rfc-editor.org/rfc/rfc9000#section-9-2
+		if !c.handshakeConfirmed.isSet() {
+			return false
+		}
 	}
 	buf := dgram.b
 	c.loss.datagramReceived(now, len(buf))
@@ -47,6 +53,9 @@ func (c *Conn) handleDatagram(now time.Time, dgram *datagram) (handled bool) {
 			n = c.handleLongHeader(now, dgram, ptype, initialSpace, c.keysInitial.r, buf)
 		case packetTypeHandshake:
 			n = c.handleLongHeader(now, dgram, ptype, handshakeSpace, c.keysHandshake.r, buf)
+		case packetType0RTT:
+			// Only servers have 0-RTT read keys.
+			n = c.handleLongHeader(now, dgram, ptype, appDataSpace, c.keysEarly.r, buf)
 		case packetType1RTT:
 			n = c.handle1RTT(now, dgram, buf)
 		case packetTypeRetry:
@@ -123,7 +132,7 @@ func (c *Conn) handleLongHeader(now time.Time, dgram *datagram, ptype packetType
 		c.logLongPacketReceived(p, buf[:n])
 	}
 	c.connIDState.handlePacket(c, p.ptype, p.srcConnID)
-	ackEliciting := c.handleFrames(now, dgram, ptype, space, p.payload)
+	ackEliciting, _ := c.handleFrames(now, dgram, ptype, space, p.payload)
 	c.acks[space].receive(now, space, p.num, ackEliciting, dgram.ecn)
 	if p.ptype == packetTypeHandshake && c.side == serverSide {
 		c.loss.validateClientAddress()
@@ -173,8 +182,14 @@ func (c *Conn) handle1RTT(now time.Time, dgram *datagram, buf []byte) int {
 	if c.logEnabled(QLogLevelPacket) {
 		c.log1RTTPacketReceived(p, buf)
 	}
-	ackEliciting := c.handleFrames(now, dgram, packetType1RTT, appDataSpace, p.payload)
+	ackEliciting, nonProbing := c.handleFrames(now, dgram, packetType1RTT, appDataSpace, p.payload)
 	c.acks[appDataSpace].receive(now, appDataSpace, p.num, ackEliciting, dgram.ecn)
+	if nonProbing && p.num > pnumMax && dgram.peerAddr.IsValid() && dgram.peerAddr != c.peerAddr && c.isAlive() {
+		// "An endpoint only changes the address to which it sends packets
+		// in response to the highest-numbered non-probing packet."
+		// https://www.rfc-editor.org/rfc/rfc9000#section-9.3-3
+		c.handlePeerMigration(now, dgram)
+	}
 	return len(buf)
 }
 
@@ -212,7 +227,7 @@ func (c *Conn) handleRetry(now time.Time, pkt []byte) {
 	// We need to resend any data we've already sent in Initial packets.
 	// We must not reuse already sent packet numbers.
 	c.loss.discardPackets(initialSpace, c.log, c.handleAckOrLoss)
-	// TODO: Discard 0-RTT packets as well, once we support 0-RTT.
+	c.loss.discardPackets(appDataSpace, c.log, c.handleAckOrLoss)
 	if c.testHooks != nil {
 		c.testHooks.init(false)
 	}
@@ -254,7 +269,11 @@ func (c *Conn) handleVersionNegotiation(now time.Time, pkt []byte) {
 	c.abortImmediately(now, errVersionNegotiation)
 }
 
-func (c *Conn) handleFrames(now time.Time, dgram *datagram, ptype packetType, space numberSpace, payload []byte) (ackEliciting bool) {
+// handleFrames handles the frames in a packet.
+// It reports whether the packet is ack-eliciting,
+// and whether it contains frames other than probing frames.
+// https://www.rfc-editor.org/rfc/rfc9000#section-9.1
+func (c *Conn) handleFrames(now time.Time, dgram *datagram, ptype packetType, space numberSpace, payload []byte) (ackEliciting, nonProbing bool) {
 	if len(payload) == 0 {
 		// "An endpoint MUST treat receipt of a packet containing no frames
 		// as a connection error of type PROTOCOL_VIOLATION."
@@ -263,7 +282,7 @@ func (c *Conn) handleFrames(now time.Time, dgram *datagram, ptype packetType, sp
 			code:   errProtocolViolation,
 			reason: "packet contains no frames",
 		})
-		return false
+		return false, false
 	}
 	// frameOK verifies that ptype is one of the packets in mask.
 	frameOK := func(c *Conn, ptype, mask packetType) (ok bool) {
@@ -295,6 +314,12 @@ func (c *Conn) handleFrames(now time.Time, dgram *datagram, ptype packetType, sp
 		default:
 			ackEliciting = true
 		}
+		switch payload[0] {
+		case frameTypePadding, frameTypeNewConnectionID,
+			frameTypePathChallenge, frameTypePathResponse:
+		default:
+			nonProbing = true
+		}
 		n := -1
 		switch payload[0] {
 		case frameTypePadding:
@@ -386,7 +411,7 @@ func (c *Conn) handleFrames(now time.Time, dgram *datagram, ptype packetType, sp
 			if !frameOK(c, ptype, ___1) {
 				return
 			}
-			n = c.handlePathResponseFrame(now, space, payload)
+			n = c.handlePathResponseFrame(now, dgram, space, payload)
 		case frameTypeConnectionCloseTransport:
 			// Transport CONNECTION_CLOSE is OK in all spaces.
 			n = c.handleConnectionCloseTransportFrame(now, payload)
@@ -400,13 +425,18 @@ func (c *Conn) handleFrames(now time.Time, dgram *datagram, ptype packetType, sp
 				return
 			}
 			n = c.handleHandshakeDoneFrame(now, space, payload)
+		case frameTypeDatagram, frameTypeDatagramWithSize:
+			if !frameOK(c, ptype, __01) {
+				return
+			}
+			n = c.handleDatagramFrame(now, payload)
 		}
 		if n < 0 {
 			c.abort(now, localTransportError{
 				code:   errFrameEncoding,
 				reason: "frame encoding error",
 			})
-			return false
+			return false, false
 		}
 		payload = payload[n:]
 	}
@@ -419,7 +449,7 @@ func (c *Conn) handleFrames(now time.Time, dgram *datagram, ptype packetType, sp
 			c.abort(now, err)
 		}
 	}
-	return ackEliciting
+	return ackEliciting, nonProbing
 }
 
 func (c *Conn) handleAckFrame(now time.Time, space numberSpace, payload []byte) int {
@@ -576,12 +606,12 @@ func (c *Conn) handlePathChallengeFrame(now time.Time, dgram *datagram, space nu
 	return n
 }
 
-func (c *Conn) handlePathResponseFrame(now time.Time, space numberSpace, payload []byte) int {
+func (c *Conn) handlePathResponseFrame(now time.Time, dgram *datagram, space numberSpace, payload []byte) int {
 	data, n := consumePathResponseFrame(payload)
 	if n < 0 {
 		return -1
 	}
-	c.handlePathResponse(now, data)
+	c.handlePathResponse(now, dgram, data)
 	return n
 }
 
@@ -25,6 +25,10 @@ func (c *Conn) maybeSend(now time.Time) (next time.Time) {
 		c.loss.cc.setUnderutilized(c.log, underutilized)
 	}()
 
+	// Path probes are sent in datagrams of their own,
+	// since they are sent on a different path from everything else.
+	c.maybeSendAltPath(now)
+
 	// Send one datagram on each iteration of this loop,
 	// until we hit a limit or run out of data to send.
 	//
@@ -91,6 +95,31 @@ func (c *Conn) maybeSend(now time.Time) (next time.Time) {
 			}
 		}
 
+		// 0-RTT packet.
+		// A client stops sending these once it has 1-RTT keys.
+		if c.keysEarly.canWrite() {
+			pnumMaxAcked := c.loss.spaces[appDataSpace].maxAcked
+			pnum := c.loss.nextNumber(appDataSpace)
+			p := longPacket{
+				ptype:     packetType0RTT,
+				version:   quicVersion1,
+				num:       pnum,
+				dstConnID: dstConnID,
+				srcConnID: c.connIDState.srcConnID(),
+			}
+			c.w.startProtectedLongHeaderPacket(pnumMaxAcked, p)
+			c.appendFrames(now, appDataSpace, pnum, limit)
+			if logPackets {
+				logSentPacket(c, packetType0RTT, pnum, p.srcConnID, p.dstConnID, c.w.payload())
+			}
+			if c.logEnabled(QLogLevelPacket) && len(c.w.payload()) > 0 {
+				c.logPacketSent(packetType0RTT, pnum, p.srcConnID, p.dstConnID, c.w.packetLen(), c.w.payload())
+			}
+			if sent := c.w.finishProtectedLongHeaderPacket(pnumMaxAcked, c.keysEarly.w, p); sent != nil {
+				c.packetSent(now, appDataSpace, sent)
+			}
+		}
+
 		// Handshake packet.
 		if c.keysHandshake.canWrite() {
 			pnumMaxAcked := c.loss.spaces[handshakeSpace].maxAcked
@@ -273,13 +302,18 @@ func (c *Conn) appendFrames(now time.Time, space numberSpace, pnum packetNumber,
 			return
 		}
 
-		// PATH_RESPONSE
-		if pad, ok := c.appendPathFrames(); !ok {
+		// PATH_CHALLENGE, PATH_RESPONSE
+		if pad, ok := c.appendPathFrames(now); !ok {
 			return
 		} else if pad {
 			defer c.w.appendPaddingTo(smallestMaxDatagramSize)
 		}
 
+		// DATAGRAM
+		if !c.appendDatagramFrames() {
+			return
+		}
+
 		// All stream-related frames. This should come last in the packet,
 		// so large amounts of STREAM data don't crowd out other frames
 		// we may need to send.
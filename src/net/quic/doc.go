@@ -2,11 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package quic implements the QUIC protocol.
-//
-// This package is a work in progress.
-// It is not ready for production usage.
-// Its API is subject to change without notice.
+// Package quic implements the QUIC protocol,
+// as defined in RFC 9000, RFC 9001, and RFC 9002.
 //
 // This package is low-level.
 // Most users will use it indirectly through an HTTP/3 implementation.
@@ -21,6 +18,23 @@
 //
 // A [Stream] is a QUIC stream, an ordered, reliable byte stream.
 //
+// # 0-RTT
+//
+// A client resuming a previous session may send data before the
+// handshake completes, and a server may accept it.
+// See [Config.Enable0RTT] and [Conn.Used0RTT].
+//
+// # Connection migration
+//
+// A client connection may move to a new network path
+// by migrating to another Endpoint with [Conn.Migrate].
+// Servers follow client migrations, validating the client's new address.
+//
+// # Datagrams
+//
+// A connection may exchange unreliable datagrams (RFC 9221).
+// See [Config.EnableDatagrams], [Conn.SendDatagram], and [Conn.ReceiveDatagram].
+//
 // # Cancellation
 //
 // All blocking operations may be canceled using a context.Context.
@@ -31,20 +45,12 @@
 //
 // # Limitations
 //
-// This package is a work in progress.
 // Known limitations include:
 //
 //   - Performance is untuned.
-//   - 0-RTT is not supported.
-//   - Address migration is not supported.
 //   - Server preferred addresses are not supported.
 //   - The latency spin bit is not supported.
 //   - Stream send/receive windows are configurable,
 //     but are fixed and do not adapt to available throughput.
 //   - Path MTU discovery is not implemented.
-//
-// # Security Policy
-//
-// This package is a work in progress,
-// and not yet covered by the Go security policy.
 package quic
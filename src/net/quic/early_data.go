@@ -0,0 +1,198 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quic
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"sync/atomic"
+	"time"
+)
+
+// earlyDataState is the state of 0-RTT.
+// https://www.rfc-editor.org/rfc/rfc9001#section-4.6
+type earlyDataState struct {
+	// localParams are the transport parameters we sent to the peer.
+	// A server stores the ones which must be remembered for 0-RTT in session tickets.
+	localParams transportParameters
+
+	// peerParams are the transport parameters sent by the peer.
+	// A client stores the ones which must be remembered for 0-RTT in session tickets.
+	peerParams transportParameters
+
+	// remembered are the server transport parameters from the session being resumed.
+	// Only set for a client attempting 0-RTT.
+	remembered transportParameters
+
+	// attempted is set when a client has 0-RTT keys.
+	// It is set before the conn loop starts and never changed afterwards.
+	attempted bool
+
+	// accepted is set when 0-RTT keys are installed, and cleared if the server rejects 0-RTT.
+	accepted bool
+
+	// used is set when the handshake completes, if 0-RTT was accepted.
+	used atomic.Bool
+}
+
+// sessionParamsPrefix prefixes the entry in a session ticket's
+// [tls.SessionState.Extra] holding remembered transport parameters.
+const sessionParamsPrefix = "quic transport parameters\x00"
+
+// rememberedParams returns the subset of p which must be remembered for 0-RTT.
+// https://www.rfc-editor.org/rfc/rfc9000#section-7.4.1-4
+// https://www.rfc-editor.org/rfc/rfc9221#section-3-6
+func rememberedParams(p transportParameters) transportParameters {
+	r := defaultTransportParameters()
+	r.activeConnIDLimit = p.activeConnIDLimit
+	r.initialMaxData = p.initialMaxData
+	r.initialMaxStreamDataBidiLocal = p.initialMaxStreamDataBidiLocal
+	r.initialMaxStreamDataBidiRemote = p.initialMaxStreamDataBidiRemote
+	r.initialMaxStreamDataUni = p.initialMaxStreamDataUni
+	r.initialMaxStreamsBidi = p.initialMaxStreamsBidi
+	r.initialMaxStreamsUni = p.initialMaxStreamsUni
+	r.maxDatagramFrameSize = p.maxDatagramFrameSize
+	return r
+}
+
+// rememberedParamsReduced reports whether p reduces any limit in the remembered parameters r.
+func rememberedParamsReduced(r, p transportParameters) bool {
+	return p.activeConnIDLimit < r.activeConnIDLimit ||
+		p.initialMaxData < r.initialMaxData ||
+		p.initialMaxStreamDataBidiLocal < r.initialMaxStreamDataBidiLocal ||
+		p.initialMaxStreamDataBidiRemote < r.initialMaxStreamDataBidiRemote ||
+		p.initialMaxStreamDataUni < r.initialMaxStreamDataUni ||
+		p.initialMaxStreamsBidi < r.initialMaxStreamsBidi ||
+		p.initialMaxStreamsUni < r.initialMaxStreamsUni ||
+		p.maxDatagramFrameSize < r.maxDatagramFrameSize
+}
+
+// sessionParams returns the remembered transport parameters stored in a session.
+func sessionParams(ss *tls.SessionState) (p transportParameters, ok bool) {
+	for _, b := range ss.Extra {
+		if v, found := bytes.CutPrefix(b, []byte(sessionParamsPrefix)); found {
+			p, err := unmarshalTransportParams(v)
+			return p, err == nil
+		}
+	}
+	return p, false
+}
+
+// appendSessionParams adds remembered transport parameters to a session ticket's extra data.
+func appendSessionParams(extra [][]byte, p transportParameters) [][]byte {
+	b := append([]byte(sessionParamsPrefix), marshalTransportParameters(rememberedParams(p))...)
+	return append(extra, b)
+}
+
+// handleResumeSession is called on both client and server
+// when the client attempts to resume a session.
+// It decides whether 0-RTT may be used with the session.
+func (c *Conn) handleResumeSession(ss *tls.SessionState) {
+	if !ss.EarlyData {
+		return
+	}
+	p, ok := sessionParams(ss)
+	switch {
+	case !c.config.Enable0RTT || !ok:
+		ss.EarlyData = false
+	case c.side == clientSide:
+		// Until we receive the server's transport parameters,
+		// 0-RTT data is limited by the ones it sent in the previous connection.
+		// https://www.rfc-editor.org/rfc/rfc9000#section-7.4.1-2
+		c.earlyData.remembered = p
+		c.streams.outflow.setMaxData(p.initialMaxData)
+		c.streams.localLimit[bidiStream].setMax(p.initialMaxStreamsBidi)
+		c.streams.localLimit[uniStream].setMax(p.initialMaxStreamsUni)
+		c.streams.peerInitialMaxStreamDataBidiLocal = p.initialMaxStreamDataBidiLocal
+		c.streams.peerInitialMaxStreamDataRemote[bidiStream] = p.initialMaxStreamDataBidiRemote
+		c.streams.peerInitialMaxStreamDataRemote[uniStream] = p.initialMaxStreamDataUni
+		c.setPeerMaxDatagramFrameSize(p.maxDatagramFrameSize)
+	case rememberedParamsReduced(p, c.earlyData.localParams):
+		// "[...] a server MUST either reject 0-RTT data or abort a handshake
+		// if the implied values for transport parameters cannot be supported."
+		// https://www.rfc-editor.org/rfc/rfc9000#section-7.4.1-6
+		ss.EarlyData = false
+	}
+}
+
+// handleStoreSession is called when a client receives a session ticket.
+func (c *Conn) handleStoreSession(ss *tls.SessionState) error {
+	ss.Extra = appendSessionParams(ss.Extra, c.earlyData.peerParams)
+	return c.tls.StoreSession(ss)
+}
+
+// sendSessionTicket sends a session ticket to the client.
+// The server transport parameters are stored in the ticket,
+// so they can be checked against the current ones when the client resumes.
+func (c *Conn) sendSessionTicket() error {
+	return c.tls.SendSessionTicket(tls.QUICSessionTicketOptions{
+		EarlyData: c.config.Enable0RTT,
+		Extra:     appendSessionParams(nil, c.earlyData.localParams),
+	})
+}
+
+// setEarlyDataKeys installs 0-RTT keys.
+func (c *Conn) setEarlyDataKeys(suite uint16, secret []byte, write bool) {
+	if write {
+		c.keysEarly.w.init(suite, secret)
+		c.earlyData.attempted = true
+	} else {
+		c.keysEarly.r.init(suite, secret)
+	}
+	c.earlyData.accepted = true
+}
+
+// rejectEarlyData is called when the server rejects a client's 0-RTT data.
+func (c *Conn) rejectEarlyData(now time.Time) {
+	c.earlyData.accepted = false
+	c.keysEarly.discard()
+	// "If the server rejects 0-RTT, [...] the client MAY attempt to send
+	// the data again in 1-RTT packets."
+	// https://www.rfc-editor.org/rfc/rfc9001#section-4.6.2-2
+	//
+	// Treat all 0-RTT packets as lost, so their contents are sent again.
+	c.loss.discardPackets(appDataSpace, c.log, c.handleAckOrLoss)
+}
+
+// checkRememberedParams is called when a client attempting 0-RTT receives
+// the server's transport parameters.
+func (c *Conn) checkRememberedParams(p transportParameters) error {
+	if !c.earlyData.attempted {
+		return nil
+	}
+	// "If 0-RTT data is accepted by the server, the server MUST NOT reduce
+	// any limits or alter any values that might be violated by the client
+	// with its 0-RTT data."
+	// https://www.rfc-editor.org/rfc/rfc9000#section-7.4.1-8
+	//
+	// A server which rejects 0-RTT may reduce limits,
+	// but we have already applied the remembered limits and
+	// may have opened streams or sent data permitted only by them.
+	// We treat this case as an error as well.
+	if rememberedParamsReduced(c.earlyData.remembered, p) {
+		return localTransportError{
+			code:   errProtocolViolation,
+			reason: "server reduced limits remembered for 0-RTT",
+		}
+	}
+	return nil
+}
+
+// Handshake waits for the connection handshake to complete.
+//
+// A connection returned by [Endpoint.Dial] or [Endpoint.Accept] has
+// usually completed its handshake already.
+// A client connection sending 0-RTT data does not wait for the handshake
+// to complete before being returned by Dial (see [Config.Enable0RTT]).
+func (c *Conn) Handshake(ctx context.Context) error {
+	return c.waitReady(ctx)
+}
+
+// Used0RTT reports whether 0-RTT data was accepted on this connection.
+// It returns false until the handshake completes.
+func (c *Conn) Used0RTT() bool {
+	return c.earlyData.used.Load()
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"testing"
+	"time"
+)
+
+// newResumableConfigs returns configs for a server and client with 0-RTT enabled,
+// and primes the client's session cache with a session for the server.
+func newResumableConfigs(t *testing.T) (serverConfig, clientConfig *Config) {
+	t.Helper()
+	serverConfig, clientConfig = newTestConfigs(t)
+	serverConfig.Enable0RTT = true
+	clientConfig.Enable0RTT = true
+	cache := tls.NewLRUClientSessionCache(1)
+	clientConfig.TLSConfig.ClientSessionCache = cache
+
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+	if cc.Used0RTT() || sc.Used0RTT() {
+		t.Errorf("first connection: Used0RTT() = true, want false")
+	}
+	// The server sends a session ticket after the handshake completes.
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, ok := cache.Get(clientConfig.TLSConfig.ServerName); ok {
+			break
+		}
+		if time.Since(start) > 10*time.Second {
+			t.Fatalf("client did not receive a session ticket")
+		}
+	}
+	cc.Close()
+	return serverConfig, clientConfig
+}
+
+func TestEarlyDataLoopback(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		serverAccept bool
+	}{
+		{"accepted", true},
+		{"rejected", false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			serverConfig, clientConfig := newResumableConfigs(t)
+			serverConfig = serverConfig.Clone()
+			serverConfig.Enable0RTT = test.serverAccept
+			server := newLocalEndpoint(t, serverConfig)
+			client := newLocalEndpoint(t, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			cc, err := client.Dial(ctx, "udp", server.LocalAddr().String(), clientConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cc.Abort(nil)
+
+			// Dial returns before the handshake completes,
+			// so this data is sent in 0-RTT packets.
+			want := "early data"
+			cs, err := cc.NewSendOnlyStream(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cs.SetWriteContext(ctx)
+			if _, err := cs.Write([]byte(want)); err != nil {
+				t.Fatal(err)
+			}
+			cs.Close()
+
+			sc, err := server.Accept(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ss, err := sc.AcceptStream(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ss.SetReadContext(ctx)
+			got, err := io.ReadAll(ss)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Errorf("server read %q, want %q", got, want)
+			}
+
+			if err := cc.Handshake(ctx); err != nil {
+				t.Fatalf("Handshake: %v", err)
+			}
+			if got, want := cc.Used0RTT(), test.serverAccept; got != want {
+				t.Errorf("client Used0RTT() = %v, want %v", got, want)
+			}
+			if got, want := sc.Used0RTT(), test.serverAccept; got != want {
+				t.Errorf("server Used0RTT() = %v, want %v", got, want)
+			}
+			if !cc.ConnectionState().DidResume {
+				t.Errorf("client did not resume session")
+			}
+		})
+	}
+}
+
+func TestEarlyDataNotEnabled(t *testing.T) {
+	serverConfig, clientConfig := newResumableConfigs(t)
+	clientConfig = clientConfig.Clone()
+	clientConfig.Enable0RTT = false
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+	if !cc.ConnectionState().DidResume {
+		t.Errorf("client did not resume session")
+	}
+	if cc.Used0RTT() || sc.Used0RTT() {
+		t.Errorf("client with 0-RTT disabled: Used0RTT() = true, want false")
+	}
+}
@@ -68,7 +68,12 @@ func Listen(network, address string, listenConfig *Config) (*Endpoint, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newEndpoint(pc, listenConfig, nil)
+	e, err := newEndpoint(pc, listenConfig, nil)
+	if err != nil {
+		pc.Close()
+		return nil, err
+	}
+	return e, nil
 }
 
 // NewEndpoint creates an endpoint using a net.PacketConn as the underlying transport.
@@ -106,6 +111,10 @@ func newEndpoint(pc packetConn, config *Config, hooks endpointTestHooks) (*Endpo
 	e.resetGen.init(statelessResetKey)
 	e.connsMap.init()
 	if config != nil && config.RequireAddressValidation {
+		// Without the Retry AEAD, every Initial packet would be dropped.
+		if _, err := retryAEAD(); err != nil {
+			return nil, fmt.Errorf("RequireAddressValidation is not supported: %w", err)
+		}
 		if err := e.retry.init(); err != nil {
 			return nil, err
 		}
@@ -164,6 +173,11 @@ func (e *Endpoint) Accept(ctx context.Context) (*Conn, error) {
 
 // Dial creates and returns a connection to a network address.
 // The config cannot be nil.
+//
+// Dial ordinarily waits for the connection handshake to complete.
+// When the connection resumes a session permitting 0-RTT data
+// and config.Enable0RTT is set, Dial returns immediately.
+// Use [Conn.Handshake] to wait for the handshake to complete.
 func (e *Endpoint) Dial(ctx context.Context, network, address string, config *Config) (*Conn, error) {
 	u, err := net.ResolveUDPAddr(network, address)
 	if err != nil {
@@ -175,6 +189,9 @@ func (e *Endpoint) Dial(ctx context.Context, network, address string, config *Co
 	if err != nil {
 		return nil, err
 	}
+	if c.earlyData.attempted {
+		return c, nil
+	}
 	if err := c.waitReady(ctx); err != nil {
 		c.Abort(nil)
 		return nil, err
@@ -205,6 +222,41 @@ func (e *Endpoint) serverConnEstablished(c *Conn) {
 // connDrained is called by a conn when it leaves the draining state,
 // either when the peer acknowledges connection closure or the drain timeout expires.
 func (e *Endpoint) connDrained(c *Conn) {
+	e.removeConn(c)
+}
+
+// addConn adds an existing conn to the endpoint,
+// when a client conn migrates to the endpoint from another one.
+// It must be called on the conn's loop.
+func (e *Endpoint) addConn(c *Conn) error {
+	e.connsMu.Lock()
+	defer e.connsMu.Unlock()
+	if e.closing {
+		return errors.New("endpoint closed")
+	}
+	e.conns[c] = struct{}{}
+	var cids [][]byte
+	for i := range c.connIDState.local {
+		cids = append(cids, c.connIDState.local[i].cid)
+	}
+	var tokens []statelessResetToken
+	for i := range c.connIDState.remote {
+		tokens = append(tokens, c.connIDState.remote[i].resetToken)
+	}
+	e.connsMap.updateConnIDs(func(conns *connsMap) {
+		for _, cid := range cids {
+			conns.addConnID(c, cid)
+		}
+		for _, token := range tokens {
+			conns.addResetToken(c, token)
+		}
+	})
+	return nil
+}
+
+// removeConn removes a conn from the endpoint.
+// It must be called on the conn's loop.
+func (e *Endpoint) removeConn(c *Conn) {
 	var cids [][]byte
 	for i := range c.connIDState.local {
 		cids = append(cids, c.connIDState.local[i].cid)
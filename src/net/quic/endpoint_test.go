@@ -0,0 +1,182 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quic
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/fips140"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"internal/testenv"
+	"io"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testCert = sync.OnceValues(func() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "quic test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"example.com"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+})
+
+// newTestConfigs returns server and client configs which trust each other.
+func newTestConfigs(t *testing.T) (serverConfig, clientConfig *Config) {
+	t.Helper()
+	cert, err := testCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(leaf)
+	serverConfig = &Config{
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS13,
+			NextProtos:   []string{"quic-test"},
+		},
+	}
+	clientConfig = &Config{
+		TLSConfig: &tls.Config{
+			RootCAs:    roots,
+			ServerName: "example.com",
+			MinVersion: tls.VersionTLS13,
+			NextProtos: []string{"quic-test"},
+		},
+	}
+	return serverConfig, clientConfig
+}
+
+func newLocalEndpoint(t *testing.T, config *Config) *Endpoint {
+	t.Helper()
+	e, err := Listen("udp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		e.Close(ctx)
+	})
+	return e
+}
+
+// newLocalConnPair connects a client endpoint to a server endpoint over loopback,
+// returning the client and server sides of the connection.
+func newLocalConnPair(t *testing.T, client, server *Endpoint, clientConfig *Config) (clientConn, serverConn *Conn) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	clientConn, err := client.Dial(ctx, "udp", server.LocalAddr().String(), clientConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConn, err = server.Accept(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clientConn, serverConn
+}
+
+// roundTrip writes data on a new stream from c1, echoes it back from c2,
+// and verifies c1 reads the same data.
+func roundTrip(t *testing.T, c1, c2 *Conn, data []byte) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s1, err := c1.NewStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1.SetReadContext(ctx)
+	s1.SetWriteContext(ctx)
+	if _, err := s1.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	s1.CloseWrite()
+	s2, err := c2.AcceptStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2.SetReadContext(ctx)
+	s2.SetWriteContext(ctx)
+	if _, err := io.Copy(s2, s2); err != nil {
+		t.Fatal(err)
+	}
+	s2.Close()
+	got, err := io.ReadAll(s1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("echoed %v bytes, want %v", len(got), len(data))
+	}
+}
+
+func TestStreamsLoopback(t *testing.T) {
+	serverConfig, clientConfig := newTestConfigs(t)
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+	if got, want := cc.RemoteAddr(), server.LocalAddr(); got != want {
+		t.Errorf("client RemoteAddr() = %v, want %v", got, want)
+	}
+	if got, want := sc.RemoteAddr(), client.LocalAddr(); got != want {
+		t.Errorf("server RemoteAddr() = %v, want %v", got, want)
+	}
+	roundTrip(t, cc, sc, []byte("hello"))
+	roundTrip(t, sc, cc, bytes.Repeat([]byte("0123456789"), 100000))
+}
+
+func TestRequireAddressValidation(t *testing.T) {
+	serverConfig, clientConfig := newTestConfigs(t)
+	serverConfig.RequireAddressValidation = true
+	if fips140.Enforced() {
+		// The Retry Integrity Tag uses AES-GCM with a fixed nonce.
+		if e, err := Listen("udp", "127.0.0.1:0", serverConfig); err == nil {
+			e.Close(context.Background())
+			t.Fatal("Listen succeeded with RequireAddressValidation in FIPS 140-only mode")
+		}
+		return
+	}
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+	roundTrip(t, cc, sc, []byte("hello"))
+
+	testenv.MustHaveExec(t)
+	cmd := testenv.Command(t, testenv.Executable(t), "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(cmd.Environ(), "GODEBUG=fips140=only")
+	out, err := cmd.CombinedOutput()
+	t.Logf("running with GODEBUG=fips140=only:\n%s", out)
+	if err != nil {
+		t.Errorf("fips140=only subprocess failed: %v", err)
+	}
+}
@@ -66,6 +66,8 @@ func parseDebugFrame(b []byte) (f debugFrame, n int) {
 		f, n = parseDebugFrameConnectionCloseApplication(b)
 	case frameTypeHandshakeDone:
 		f, n = parseDebugFrameHandshakeDone(b)
+	case frameTypeDatagram, frameTypeDatagramWithSize:
+		f, n = parseDebugFrameDatagram(b)
 	default:
 		return nil, -1
 	}
@@ -728,3 +730,28 @@ func (f debugFrameHandshakeDone) LogValue() slog.Value {
 		slog.String("frame_type", "handshake_done"),
 	)
 }
+
+// debugFrameDatagram is a DATAGRAM frame.
+type debugFrameDatagram struct {
+	data []byte
+}
+
+func parseDebugFrameDatagram(b []byte) (f debugFrameDatagram, n int) {
+	f.data, n = consumeDatagramFrame(b)
+	return f, n
+}
+
+func (f debugFrameDatagram) String() string {
+	return fmt.Sprintf("DATAGRAM Length=%v", len(f.data))
+}
+
+func (f debugFrameDatagram) write(w *packetWriter) bool {
+	return w.appendDatagramFrame(f.data)
+}
+
+func (f debugFrameDatagram) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("frame_type", "datagram"),
+		slog.Int("length", len(f.data)),
+	)
+}
@@ -112,6 +112,13 @@ func (c *lossState) validateClientAddress() {
 	c.antiAmplificationLimit = antiAmplificationUnlimited
 }
 
+// peerAddressChanged is called when a server's peer migrates to a new address,
+// in a datagram of the given size.
+// Until the address is validated, the anti-amplification limit applies to it.
+func (c *lossState) peerAddressChanged(size int) {
+	c.antiAmplificationLimit = 3 * size
+}
+
 // minDatagramSize is the minimum datagram size permitted by
 // anti-amplification protection.
 //
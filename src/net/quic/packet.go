@@ -7,8 +7,7 @@ package quic
 import (
 	"encoding/binary"
 	"fmt"
-
-	"golang.org/x/net/internal/quic/quicwire"
+	"net/quic/internal/quicwire"
 )
 
 // packetType is a QUIC packet type.
@@ -105,6 +104,13 @@ const (
 	frameTypeHandshakeDone              = 0x1e
 )
 
+// DATAGRAM frame types.
+// https://www.rfc-editor.org/rfc/rfc9221#section-4
+const (
+	frameTypeDatagram         = 0x30
+	frameTypeDatagramWithSize = 0x31
+)
+
 // The low three bits of STREAM frames.
 // https://www.rfc-editor.org/rfc/rfc9000.html#section-19.8
 const (
@@ -4,9 +4,7 @@
 
 package quic
 
-import (
-	"golang.org/x/net/internal/quic/quicwire"
-)
+import "net/quic/internal/quicwire"
 
 // parseLongHeaderPacket parses a QUIC long header packet.
 //
@@ -477,6 +475,20 @@ func consumePathResponseFrame(b []byte) (data pathChallengeData, n int) {
 	return consumePathChallengeFrame(b) // identical frame format
 }
 
+func consumeDatagramFrame(b []byte) (data []byte, n int) {
+	n = 1
+	if b[0] == frameTypeDatagram {
+		// A DATAGRAM frame without a Length field extends to the end of the packet.
+		return b[n:], len(b)
+	}
+	data, nn := quicwire.ConsumeVarintBytes(b[n:])
+	if nn < 0 {
+		return nil, -1
+	}
+	n += nn
+	return data, n
+}
+
 func consumeConnectionCloseTransportFrame(b []byte) (code transportError, frameType uint64, reason string, n int) {
 	n = 1
 	var nn int
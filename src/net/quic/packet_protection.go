@@ -8,6 +8,7 @@ import (
 	"crypto"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hkdf"
 	"crypto/sha256"
 	"crypto/tls"
 	"errors"
@@ -16,7 +17,6 @@ import (
 	"golang.org/x/crypto/chacha20"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/cryptobyte"
-	"golang.org/x/crypto/hkdf"
 )
 
 var errInvalidPacket = errors.New("quic: invalid packet")
@@ -478,7 +478,10 @@ var initialSalt = []byte{0x38, 0x76, 0x2c, 0xf7, 0xf5, 0x59, 0x34, 0xb3, 0x4d, 0
 //
 // https://www.rfc-editor.org/rfc/rfc9001#section-5.2
 func initialKeys(cid []byte, side connSide) fixedKeyPair {
-	initialSecret := hkdf.Extract(sha256.New, cid, initialSalt)
+	initialSecret, err := hkdf.Extract(sha256.New, cid, initialSalt)
+	if err != nil {
+		panic("quic: HKDF-Extract invocation failed unexpectedly")
+	}
 	var clientKeys fixedKeys
 	clientSecret := hkdfExpandLabel(sha256.New, initialSecret, "client in", nil, sha256.Size)
 	clientKeys.init(tls.TLS_AES_128_GCM_SHA256, clientSecret)
@@ -530,9 +533,8 @@ func hkdfExpandLabel(hash func() hash.Hash, secret []byte, label string, context
 	hkdfLabel.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
 		b.AddBytes(context)
 	})
-	out := make([]byte, length)
-	n, err := hkdf.Expand(hash, secret, hkdfLabel.BytesOrPanic()).Read(out)
-	if err != nil || n != length {
+	out, err := hkdf.Expand(hash, secret, string(hkdfLabel.BytesOrPanic()), length)
+	if err != nil {
 		panic("quic: HKDF-Expand-Label invocation failed unexpectedly")
 	}
 	return out
@@ -6,8 +6,7 @@ package quic
 
 import (
 	"encoding/binary"
-
-	"golang.org/x/net/internal/quic/quicwire"
+	"net/quic/internal/quicwire"
 )
 
 // A packetWriter constructs QUIC datagrams.
@@ -527,6 +526,19 @@ func (w *packetWriter) appendPathResponseFrame(data pathChallengeData) (added bo
 	return true
 }
 
+// appendDatagramFrame appends a DATAGRAM frame with a Length field.
+func (w *packetWriter) appendDatagramFrame(data []byte) (added bool) {
+	if w.avail() < 1+quicwire.SizeVarint(uint64(len(data)))+len(data) {
+		return false
+	}
+	w.b = append(w.b, frameTypeDatagramWithSize)
+	w.b = quicwire.AppendVarintBytes(w.b, data)
+	// DATAGRAM frames are not retransmitted,
+	// so there is no need to record the frame itself.
+	w.sent.markAckEliciting()
+	return true
+}
+
 // appendConnectionCloseTransportFrame appends a CONNECTION_CLOSE frame
 // carrying a transport error code.
 func (w *packetWriter) appendConnectionCloseTransportFrame(code transportError, frameType uint64, reason string) (added bool) {
@@ -0,0 +1,449 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quic
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/netip"
+	"slices"
+	"time"
+)
+
+type pathState struct {
+	// Response to a peer's PATH_CHALLENGE.
+	// This is not a sentVal, because we don't resend lost PATH_RESPONSE frames.
+	// We only track the most recent PATH_CHALLENGE.
+	// If the peer sends a second PATH_CHALLENGE before we respond to the first,
+	// we'll drop the first response.
+	sendPathResponse pathResponseType
+	data             pathChallengeData
+	responseAddr     netip.AddrPort // address the PATH_CHALLENGE was received from
+
+	// Validation of the current path.
+	// A server validates a client's new address after the client migrates,
+	// and reverts to the previous address if validation fails.
+	validate     pathValidation
+	prevPeerAddr netip.AddrPort
+
+	// alt is an alternate path, or nil.
+	alt *altPath
+
+	peerDisableActiveMigration bool // peer sent disable_active_migration
+}
+
+// pathChallengeData is data carried in a PATH_CHALLENGE or PATH_RESPONSE frame.
+type pathChallengeData [64 / 8]byte
+
+type pathResponseType uint8
+
+const (
+	pathResponseNotNeeded = pathResponseType(iota)
+	pathResponseSmall     // send PATH_RESPONSE, do not expand datagram
+	pathResponseExpanded  // send PATH_RESPONSE, expand datagram to 1200 bytes
+)
+
+// A pathValidation tracks validation of a path using PATH_CHALLENGE frames.
+// https://www.rfc-editor.org/rfc/rfc9000#section-8.2
+type pathValidation struct {
+	sent     []pathChallengeData // data in PATH_CHALLENGE frames sent
+	next     time.Time           // time to send the next PATH_CHALLENGE
+	interval time.Duration       // time between PATH_CHALLENGE frames
+	deadline time.Time           // time validation fails; zero if not validating
+}
+
+func (v *pathValidation) start(c *Conn, now time.Time) {
+	*v = pathValidation{
+		next:     now,
+		interval: c.loss.ptoBasePeriod(),
+		deadline: now.Add(c.pathValidationTimeout()),
+	}
+}
+
+func (v *pathValidation) stop() {
+	*v = pathValidation{}
+}
+
+func (v *pathValidation) active() bool {
+	return !v.deadline.IsZero()
+}
+
+func (v *pathValidation) shouldSend(now time.Time) bool {
+	return v.active() && !v.next.After(now)
+}
+
+// challengeSent records that we sent a PATH_CHALLENGE.
+func (v *pathValidation) challengeSent(now time.Time, data pathChallengeData) {
+	v.sent = append(v.sent, data)
+	// "An endpoint SHOULD NOT probe a new path with packets containing a
+	// PATH_CHALLENGE frame more frequently than it would an Initial packet."
+	// https://www.rfc-editor.org/rfc/rfc9000.html#section-8.2.1-2
+	//
+	// Back off exponentially, as for retransmission of Initial packets.
+	v.next = now.Add(v.interval)
+	v.interval *= 2
+}
+
+// timer returns the next time the validation needs attention.
+func (v *pathValidation) timer() time.Time {
+	if !v.active() {
+		return time.Time{}
+	}
+	return firstTime(v.next, v.deadline)
+}
+
+// An altPath is a network path other than the one the connection is using.
+//
+// A client probes an alternate path before migrating to it.
+// A server responds to probes from a client on an alternate path.
+//
+// An alternate path uses its own remote connection ID,
+// since the same connection ID must not be used on more than one path.
+// https://www.rfc-editor.org/rfc/rfc9000#section-9.5-3
+type altPath struct {
+	endpoint  *Endpoint
+	peerAddr  netip.AddrPort
+	dstConnID connID
+
+	validate pathValidation // client only
+	donec    chan error     // client only: receives the result of migration
+}
+
+var errPathValidationFailed = errors.New("quic: path validation failed")
+
+// pathValidationTimeout returns the time after which path validation is abandoned.
+func (c *Conn) pathValidationTimeout() time.Duration {
+	// "[...] a value of three times the larger of the current PTO or
+	// the PTO for the new path (using kInitialRtt, as defined in [QUIC-RECOVERY])
+	// is RECOMMENDED."
+	// https://www.rfc-editor.org/rfc/rfc9000#section-8.2.4-2
+	const initialRTT = 333 * time.Millisecond
+	const newPathPTO = initialRTT + 4*(initialRTT/2)
+	return 3 * max(c.loss.ptoPeriod(), newPathPTO)
+}
+
+func (c *Conn) newPathChallengeData() (data pathChallengeData) {
+	binary.BigEndian.PutUint64(data[:], c.prng.Uint64())
+	return data
+}
+
+func (c *Conn) handlePathChallenge(_ time.Time, dgram *datagram, data pathChallengeData) {
+	// A PATH_RESPONSE is sent in a datagram expanded to 1200 bytes,
+	// except when this would exceed the anti-amplification limit.
+	//
+	// Rather than maintaining anti-amplification state for each path
+	// we may be sending a PATH_RESPONSE on, follow the following heuristic:
+	//
+	// If we receive a PATH_CHALLENGE in an expanded datagram,
+	// respond with an expanded datagram.
+	//
+	// If we receive a PATH_CHALLENGE in a non-expanded datagram,
+	// then the peer is presumably blocked by its own anti-amplification limit.
+	// Respond with a non-expanded datagram. Receiving this PATH_RESPONSE
+	// will validate the path to the peer, remove its anti-amplification limit,
+	// and permit it to send a followup PATH_CHALLENGE in an expanded datagram.
+	// https://www.rfc-editor.org/rfc/rfc9000.html#section-8.2.1
+	if len(dgram.b) >= smallestMaxDatagramSize {
+		c.path.sendPathResponse = pathResponseExpanded
+	} else {
+		c.path.sendPathResponse = pathResponseSmall
+	}
+	c.path.data = data
+	c.path.responseAddr = dgram.peerAddr
+	if !dgram.peerAddr.IsValid() {
+		c.path.responseAddr = c.peerAddr
+	}
+	if c.path.responseAddr != c.peerAddr && c.side == serverSide {
+		// The client is probing a new path.
+		// We're required to send the PATH_RESPONSE on the path where the
+		// PATH_CHALLENGE was received (RFC 9000, Section 8.2.2).
+		if alt := c.path.alt; alt == nil || alt.peerAddr != c.path.responseAddr {
+			c.setAltPath(c.endpoint, c.path.responseAddr, nil)
+		}
+	}
+}
+
+func (c *Conn) handlePathResponse(now time.Time, dgram *datagram, data pathChallengeData) {
+	// "A PATH_RESPONSE frame received on any network path validates the path
+	// on which the PATH_CHALLENGE was sent."
+	// https://www.rfc-editor.org/rfc/rfc9000#section-8.2.3-3
+	switch {
+	case slices.Contains(c.path.validate.sent, data):
+		// The client's new address is valid.
+		c.path.validate.stop()
+		c.path.prevPeerAddr = netip.AddrPort{}
+		c.loss.validateClientAddress()
+	case c.path.alt != nil && slices.Contains(c.path.alt.validate.sent, data):
+		c.migrateToAltPath(now, dgram)
+	default:
+		// "If the content of a PATH_RESPONSE frame does not match the content of
+		// a PATH_CHALLENGE frame previously sent by the endpoint,
+		// the endpoint MAY generate a connection error of type PROTOCOL_VIOLATION."
+		// https://www.rfc-editor.org/rfc/rfc9000.html#section-19.18-4
+		//
+		// This may be a late response for a path we have finished or abandoned
+		// validating, so ignore it.
+	}
+}
+
+// appendPathFrames appends path validation related frames to the current packet.
+// If the return value pad is true, then the packet should be padded to 1200 bytes.
+func (c *Conn) appendPathFrames(now time.Time) (pad, ok bool) {
+	if c.path.sendPathResponse != pathResponseNotNeeded && c.path.responseAddr == c.peerAddr {
+		if !c.w.appendPathResponseFrame(c.path.data) {
+			return pad, false
+		}
+		if c.path.sendPathResponse == pathResponseExpanded {
+			pad = true
+		}
+		c.path.sendPathResponse = pathResponseNotNeeded
+	}
+	if c.path.validate.shouldSend(now) {
+		data := c.newPathChallengeData()
+		if !c.w.appendPathChallengeFrame(data) {
+			return pad, false
+		}
+		c.path.validate.challengeSent(now, data)
+		// "An endpoint MUST expand datagrams that contain a PATH_CHALLENGE frame
+		// to at least the smallest allowed maximum datagram size of 1200 bytes,
+		// unless the anti-amplification limit for the path does not permit
+		// sending a datagram of this size."
+		// https://www.rfc-editor.org/rfc/rfc9000#section-8.2.1-3
+		pad = true
+	}
+	return pad, true
+}
+
+// maybeSendAltPath sends a datagram containing PATH_CHALLENGE or PATH_RESPONSE
+// frames on the alternate path, if necessary.
+func (c *Conn) maybeSendAltPath(now time.Time) {
+	alt := c.path.alt
+	if alt == nil || !c.isAlive() || !c.keysAppData.canWrite() {
+		return
+	}
+	sendChallenge := alt.validate.shouldSend(now)
+	sendResponse := c.path.sendPathResponse != pathResponseNotNeeded &&
+		c.path.responseAddr == alt.peerAddr &&
+		c.path.responseAddr != c.peerAddr
+	if !sendChallenge && !sendResponse {
+		return
+	}
+	c.w.reset(smallestMaxDatagramSize)
+	pnumMaxAcked := c.loss.spaces[appDataSpace].maxAcked
+	pnum := c.loss.nextNumber(appDataSpace)
+	c.w.start1RTTPacket(pnum, pnumMaxAcked, alt.dstConnID.cid)
+	pad := false
+	if sendChallenge {
+		data := c.newPathChallengeData()
+		if c.w.appendPathChallengeFrame(data) {
+			alt.validate.challengeSent(now, data)
+			pad = true
+		}
+	}
+	if sendResponse && c.w.appendPathResponseFrame(c.path.data) {
+		if c.path.sendPathResponse == pathResponseExpanded {
+			pad = true
+		}
+		c.path.sendPathResponse = pathResponseNotNeeded
+	}
+	if pad {
+		c.w.appendPaddingTo(smallestMaxDatagramSize)
+	}
+	if logPackets {
+		logSentPacket(c, packetType1RTT, pnum, nil, alt.dstConnID.cid, c.w.payload())
+	}
+	if c.logEnabled(QLogLevelPacket) && len(c.w.payload()) > 0 {
+		c.logPacketSent(packetType1RTT, pnum, nil, alt.dstConnID.cid, c.w.packetLen(), c.w.payload())
+	}
+	if sent := c.w.finish1RTTPacket(pnum, pnumMaxAcked, alt.dstConnID.cid, &c.keysAppData); sent != nil {
+		c.packetSent(now, appDataSpace, sent)
+		alt.endpoint.sendDatagram(datagram{
+			b:        c.w.datagram(),
+			peerAddr: alt.peerAddr,
+		})
+	}
+}
+
+// setAltPath sets the alternate path, replacing any existing one.
+// It reports whether a remote connection ID is available for use on the path.
+func (c *Conn) setAltPath(e *Endpoint, peerAddr netip.AddrPort, donec chan error) bool {
+	if c.path.alt != nil {
+		c.abandonAltPath(errPathValidationFailed)
+	}
+	cid, ok := c.connIDState.unusedRemoteConnID()
+	if !ok {
+		return false
+	}
+	c.path.alt = &altPath{
+		endpoint:  e,
+		peerAddr:  peerAddr,
+		dstConnID: cid,
+		donec:     donec,
+	}
+	return true
+}
+
+// abandonAltPath stops using the alternate path.
+func (c *Conn) abandonAltPath(err error) {
+	alt := c.path.alt
+	c.path.alt = nil
+	if alt.endpoint != c.endpoint {
+		alt.endpoint.removeConn(c)
+	}
+	// We may have sent packets using the path's connection ID,
+	// so retire it rather than using it on another path.
+	c.connIDState.retireRemoteConnID(c, alt.dstConnID.seq)
+	if alt.donec != nil {
+		alt.donec <- err
+	}
+}
+
+// migrateToAltPath switches a client connection to the alternate path,
+// after the path has been validated.
+func (c *Conn) migrateToAltPath(now time.Time, dgram *datagram) {
+	alt := c.path.alt
+	// Make sure the new endpoint knows about all our connection IDs,
+	// including ones issued while we were validating the path.
+	if err := alt.endpoint.addConn(c); err != nil {
+		c.abandonAltPath(err)
+		return
+	}
+	c.path.alt = nil
+	prev := c.endpoint
+	c.endpoint = alt.endpoint
+	prev.removeConn(c)
+	c.connIDState.useRemoteConnID(c, alt.dstConnID.seq)
+	localAddr := dgram.localAddr
+	if !localAddr.IsValid() {
+		localAddr = c.endpoint.LocalAddr()
+	}
+	c.setAddrs(localAddr, c.peerAddr)
+	// TODO: Reset the congestion controller and RTT estimate
+	// when the new path has a different IP address (RFC 9000, Section 9.4).
+	alt.donec <- nil
+}
+
+// handlePeerMigration is called when a server receives a non-probing packet
+// from a client's new address.
+// https://www.rfc-editor.org/rfc/rfc9000#section-9.3
+func (c *Conn) handlePeerMigration(now time.Time, dgram *datagram) {
+	if !c.path.validate.active() {
+		c.path.prevPeerAddr = c.peerAddr
+	}
+	// "An endpoint MUST NOT reuse a connection ID when sending to
+	// more than one destination address."
+	// https://www.rfc-editor.org/rfc/rfc9000#section-9.5-3
+	if alt := c.path.alt; alt != nil && alt.peerAddr == dgram.peerAddr {
+		c.path.alt = nil
+		c.connIDState.useRemoteConnID(c, alt.dstConnID.seq)
+	} else {
+		if alt != nil {
+			c.abandonAltPath(errPathValidationFailed)
+		}
+		if cid, ok := c.connIDState.unusedRemoteConnID(); ok {
+			c.connIDState.useRemoteConnID(c, cid.seq)
+		}
+	}
+	c.setAddrs(c.localAddr, dgram.peerAddr)
+	// "Until a peer's address is deemed valid, an endpoint limits
+	// the amount of data it sends to that address."
+	// https://www.rfc-editor.org/rfc/rfc9000#section-9.3-4
+	c.loss.peerAddressChanged(len(dgram.b))
+	c.path.validate.start(c, now)
+	// TODO: Reset the congestion controller and RTT estimate
+	// when the new path has a different IP address (RFC 9000, Section 9.4).
+}
+
+// pathTimer returns the next time path validation needs attention.
+func (c *Conn) pathTimer() time.Time {
+	next := c.path.validate.timer()
+	if alt := c.path.alt; alt != nil {
+		next = firstTime(next, alt.validate.timer())
+	}
+	return next
+}
+
+// pathAdvance is called when time passes.
+func (c *Conn) pathAdvance(now time.Time) {
+	if v := &c.path.validate; v.active() && !v.deadline.After(now) {
+		// "If path validation fails, [...] the endpoint MUST
+		// revert to the last validated peer address."
+		// https://www.rfc-editor.org/rfc/rfc9000#section-9.3.2-2
+		v.stop()
+		c.setAddrs(c.localAddr, c.path.prevPeerAddr)
+		c.path.prevPeerAddr = netip.AddrPort{}
+		c.loss.validateClientAddress()
+	}
+	if alt := c.path.alt; alt != nil && alt.validate.active() && !alt.validate.deadline.After(now) {
+		c.abandonAltPath(errPathValidationFailed)
+	}
+}
+
+// Migrate moves a client connection to a new local endpoint,
+// such as one bound to a different network interface.
+//
+// Migrate validates the path from e to the server before using it,
+// and returns an error if validation fails or ctx expires first.
+// The connection continues to use its current endpoint if migration fails.
+// After a successful migration, the connection no longer uses its previous endpoint,
+// which may be closed.
+//
+// Migration is only possible after the handshake is confirmed,
+// when the server has not disabled active migration and
+// has provided the client with an unused connection ID.
+// The new endpoint should use the same [Config.StatelessResetKey] as the previous one.
+func (c *Conn) Migrate(ctx context.Context, e *Endpoint) error {
+	donec := make(chan error, 1)
+	if err := c.runOnLoop(ctx, func(now time.Time, c *Conn) {
+		if err := c.startMigration(now, e, donec); err != nil {
+			donec <- err
+		}
+	}); err != nil {
+		return err
+	}
+	select {
+	case err := <-donec:
+		return err
+	case <-ctx.Done():
+	}
+	c.runOnLoop(ctx, func(now time.Time, c *Conn) {
+		if alt := c.path.alt; alt != nil && alt.donec == donec {
+			c.abandonAltPath(ctx.Err())
+		}
+	})
+	select {
+	case err := <-donec:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+func (c *Conn) startMigration(now time.Time, e *Endpoint, donec chan error) error {
+	switch {
+	case c.side != clientSide:
+		return errors.New("quic: only clients may migrate connections")
+	case !c.isAlive():
+		return errConnClosed
+	case !c.handshakeConfirmed.isSet():
+		return errors.New("quic: cannot migrate before the handshake is confirmed")
+	case c.path.peerDisableActiveMigration:
+		return errors.New("quic: peer does not permit migration")
+	case e == c.endpoint:
+		return errors.New("quic: connection is already using endpoint")
+	case c.path.alt != nil:
+		return errors.New("quic: migration already in progress")
+	}
+	if err := e.addConn(c); err != nil {
+		return err
+	}
+	if !c.setAltPath(e, c.peerAddr, donec) {
+		e.removeConn(c)
+		return errors.New("quic: no connection ID available for migration")
+	}
+	c.path.alt.validate.start(c, now)
+	return nil
+}
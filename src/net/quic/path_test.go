@@ -0,0 +1,83 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quic
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMigrateLoopback(t *testing.T) {
+	serverConfig, clientConfig := newTestConfigs(t)
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+	// Exchange some data, to confirm the handshake and
+	// give the client a spare connection ID to migrate with.
+	roundTrip(t, cc, sc, []byte("before"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := sc.Migrate(ctx, newLocalEndpoint(t, nil)); err == nil {
+		t.Errorf("server conn Migrate succeeded, want error")
+	}
+	if err := cc.Migrate(ctx, client); err == nil {
+		t.Errorf("Migrate to current endpoint succeeded, want error")
+	}
+
+	newClient := newLocalEndpoint(t, nil)
+	if err := cc.Migrate(ctx, newClient); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if got, want := cc.LocalAddr().Port(), newClient.LocalAddr().Port(); got != want {
+		t.Errorf("after migration, client local port = %v, want %v", got, want)
+	}
+	// The previous endpoint is no longer needed.
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("closing previous client endpoint: %v", err)
+	}
+
+	roundTrip(t, cc, sc, []byte("after"))
+	// Sending more than three times the data received from the client's new address
+	// requires the server to validate the address.
+	roundTrip(t, sc, cc, bytes.Repeat([]byte("0123456789"), 100000))
+	if got, want := sc.RemoteAddr(), newClient.LocalAddr(); got != want {
+		t.Errorf("after migration, server RemoteAddr() = %v, want %v", got, want)
+	}
+
+	// Migrate a second time, to a third endpoint.
+	thirdClient := newLocalEndpoint(t, nil)
+	if err := cc.Migrate(ctx, thirdClient); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	roundTrip(t, cc, sc, []byte("after second migration"))
+	if got, want := sc.RemoteAddr(), thirdClient.LocalAddr(); got != want {
+		t.Errorf("after second migration, server RemoteAddr() = %v, want %v", got, want)
+	}
+}
+
+func TestMigrateClosedEndpoint(t *testing.T) {
+	serverConfig, clientConfig := newTestConfigs(t)
+	server := newLocalEndpoint(t, serverConfig)
+	client := newLocalEndpoint(t, nil)
+	cc, sc := newLocalConnPair(t, client, server, clientConfig)
+	roundTrip(t, cc, sc, []byte("before"))
+
+	// Migrating to a closed endpoint fails,
+	// and the connection continues to use its original endpoint.
+	newClient := newLocalEndpoint(t, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	newClient.Close(ctx)
+	if err := cc.Migrate(ctx, newClient); err == nil {
+		t.Fatalf("Migrate to closed endpoint succeeded, want error")
+	}
+	roundTrip(t, cc, sc, []byte("after"))
+	if got, want := sc.RemoteAddr(), client.LocalAddr(); got != want {
+		t.Errorf("server RemoteAddr() = %v, want %v", got, want)
+	}
+}
@@ -11,28 +11,30 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"net/netip"
+	"net/quic/internal/quicwire"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/chacha20poly1305"
-	"golang.org/x/net/internal/quic/quicwire"
 )
 
 // AEAD and nonce used to compute the Retry Integrity Tag.
 // https://www.rfc-editor.org/rfc/rfc9001#section-5.8
+//
+// The AEAD is created on first use rather than at init, since GCM with a fixed
+// nonce is not allowed in FIPS 140-only mode, and every program importing
+// net/http links this package. In that mode, Retry packets are not accepted,
+// and endpoints can't require address validation.
 var (
 	retrySecret = []byte{0xbe, 0x0c, 0x69, 0x0b, 0x9f, 0x66, 0x57, 0x5a, 0x1d, 0x76, 0x6b, 0x54, 0xe3, 0x68, 0xc8, 0x4e}
 	retryNonce  = []byte{0x46, 0x15, 0x99, 0xd3, 0x5d, 0x63, 0x2b, 0xf2, 0x23, 0x98, 0x25, 0xbb}
-	retryAEAD   = func() cipher.AEAD {
+	retryAEAD   = sync.OnceValues(func() (cipher.AEAD, error) {
 		c, err := aes.NewCipher(retrySecret)
 		if err != nil {
-			panic(err)
-		}
-		aead, err := cipher.NewGCM(c)
-		if err != nil {
-			panic(err)
+			return nil, err
 		}
-		return aead
-	}()
+		return cipher.NewGCM(c)
+	})
 )
 
 // retryTokenValidityPeriod is how long we accept a Retry packet token after sending it.
@@ -172,11 +174,14 @@ func (e *Endpoint) sendRetry(now time.Time, p genericLongPacket, peerAddr netip.
 	if err != nil {
 		return
 	}
-	b := encodeRetryPacket(p.dstConnID, retryPacket{
+	b, err := encodeRetryPacket(p.dstConnID, retryPacket{
 		dstConnID: p.srcConnID,
 		srcConnID: srcConnID,
 		token:     token,
 	})
+	if err != nil {
+		return
+	}
 	e.sendDatagram(datagram{
 		b:        b,
 		peerAddr: peerAddr,
@@ -189,7 +194,7 @@ type retryPacket struct {
 	token     []byte
 }
 
-func encodeRetryPacket(originalDstConnID []byte, p retryPacket) []byte {
+func encodeRetryPacket(originalDstConnID []byte, p retryPacket) ([]byte, error) {
 	// Retry packets include an integrity tag, computed by AEAD_AES_128_GCM over
 	// the original destination connection ID followed by the Retry packet
 	// (less the integrity tag itself).
@@ -197,6 +202,10 @@ func encodeRetryPacket(originalDstConnID []byte, p retryPacket) []byte {
 	//
 	// Create the pseudo-packet (including the original DCID), append the tag,
 	// and return the Retry packet.
+	aead, err := retryAEAD()
+	if err != nil {
+		return nil, err
+	}
 	var b []byte
 	b = quicwire.AppendUint8Bytes(b, originalDstConnID) // Original Destination Connection ID
 	start := len(b)                                     // start of the Retry packet
@@ -205,13 +214,17 @@ func encodeRetryPacket(originalDstConnID []byte, p retryPacket) []byte {
 	b = quicwire.AppendUint8Bytes(b, p.dstConnID)      // Destination Connection ID
 	b = quicwire.AppendUint8Bytes(b, p.srcConnID)      // Source Connection ID
 	b = append(b, p.token...)                          // Token
-	b = retryAEAD.Seal(b, retryNonce, nil, b)          // Retry Integrity Tag
-	return b[start:]
+	b = aead.Seal(b, retryNonce, nil, b)               // Retry Integrity Tag
+	return b[start:], nil
 }
 
 func parseRetryPacket(b, origDstConnID []byte) (p retryPacket, ok bool) {
 	const retryIntegrityTagLength = 128 / 8
 
+	aead, err := retryAEAD()
+	if err != nil {
+		return retryPacket{}, false
+	}
 	lp, ok := parseGenericLongHeaderPacket(b)
 	if !ok {
 		return retryPacket{}, false
@@ -226,7 +239,7 @@ func parseRetryPacket(b, origDstConnID []byte) (p retryPacket, ok bool) {
 	// Use this to validate the packet integrity tag.
 	pseudo := quicwire.AppendUint8Bytes(nil, origDstConnID)
 	pseudo = append(pseudo, b[:len(b)-retryIntegrityTagLength]...)
-	wantTag := retryAEAD.Seal(nil, retryNonce, nil, pseudo)
+	wantTag := aead.Seal(nil, retryNonce, nil, pseudo)
 	if !bytes.Equal(gotTag, wantTag) {
 		return retryPacket{}, false
 	}
@@ -5,10 +5,9 @@
 package quic
 
 import (
+	"net/quic/internal/quicwire"
 	"sync"
 	"time"
-
-	"golang.org/x/net/internal/quic/quicwire"
 )
 
 // A sentPacket tracks state related to an in-flight packet we sent,
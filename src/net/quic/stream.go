@@ -10,9 +10,8 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/quic/internal/quicwire"
 	"sync"
-
-	"golang.org/x/net/internal/quic/quicwire"
 )
 
 // A Stream is an ordered byte stream.
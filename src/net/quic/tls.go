@@ -26,7 +26,11 @@ func (c *Conn) startTLS(now time.Time, initialConnID []byte, peerHostname string
 
 	c.keysInitial = initialKeys(initialConnID, c.side)
 
-	qconfig := &tls.QUICConfig{TLSConfig: tlsConfig}
+	c.earlyData.localParams = params
+	qconfig := &tls.QUICConfig{
+		TLSConfig:           tlsConfig,
+		EnableSessionEvents: true,
+	}
 	if c.side == clientSide {
 		c.tls = tls.QUICClient(qconfig)
 	} else {
@@ -56,6 +60,8 @@ func (c *Conn) handleTLSEvents(now time.Time) error {
 				return err
 			}
 			switch e.Level {
+			case tls.QUICEncryptionLevelEarly:
+				c.setEarlyDataKeys(e.Suite, e.Data, false)
 			case tls.QUICEncryptionLevelHandshake:
 				c.keysHandshake.r.init(e.Suite, e.Data)
 			case tls.QUICEncryptionLevelApplication:
@@ -66,10 +72,16 @@ func (c *Conn) handleTLSEvents(now time.Time) error {
 				return err
 			}
 			switch e.Level {
+			case tls.QUICEncryptionLevelEarly:
+				c.setEarlyDataKeys(e.Suite, e.Data, true)
 			case tls.QUICEncryptionLevelHandshake:
 				c.keysHandshake.w.init(e.Suite, e.Data)
 			case tls.QUICEncryptionLevelApplication:
 				c.keysAppData.w.init(e.Suite, e.Data)
+				// "A client SHOULD stop sending 0-RTT data
+				// when it receives a 1-RTT key."
+				// https://www.rfc-editor.org/rfc/rfc9001#section-4.9.3-1
+				c.keysEarly.w = fixedKeys{}
 			}
 		case tls.QUICWriteData:
 			var space numberSpace
@@ -90,8 +102,20 @@ func (c *Conn) handleTLSEvents(now time.Time) error {
 				// at the server when the handshake completes."
 				// https://www.rfc-editor.org/rfc/rfc9001#section-4.1.2-1
 				c.confirmHandshake(now)
+				if err := c.sendSessionTicket(); err != nil {
+					return err
+				}
 			}
+			c.earlyData.used.Store(c.earlyData.accepted)
 			c.handshakeDone()
+		case tls.QUICResumeSession:
+			c.handleResumeSession(e.SessionState)
+		case tls.QUICStoreSession:
+			if err := c.handleStoreSession(e.SessionState); err != nil {
+				return err
+			}
+		case tls.QUICRejectedEarlyData:
+			c.rejectEarlyData(now)
 		case tls.QUICTransportParameters:
 			params, err := unmarshalTransportParams(e.Data)
 			if err != nil {
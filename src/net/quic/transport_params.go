@@ -7,9 +7,8 @@ package quic
 import (
 	"encoding/binary"
 	"net/netip"
+	"net/quic/internal/quicwire"
 	"time"
-
-	"golang.org/x/net/internal/quic/quicwire"
 )
 
 // transportParameters transferred in the quic_transport_parameters TLS extension.
@@ -35,6 +34,7 @@ type transportParameters struct {
 	activeConnIDLimit              int64
 	initialSrcConnID               []byte
 	retrySrcConnID                 []byte
+	maxDatagramFrameSize           int64 // RFC 9221; zero if datagrams are not supported
 }
 
 const (
@@ -72,6 +72,7 @@ const (
 	paramActiveConnectionIDLimit         = 0x0e
 	paramInitialSourceConnectionID       = 0x0f
 	paramRetrySourceConnectionID         = 0x10
+	paramMaxDatagramFrameSize            = 0x20 // https://www.rfc-editor.org/rfc/rfc9221#section-3
 )
 
 func marshalTransportParameters(p transportParameters) []byte {
@@ -161,6 +162,11 @@ func marshalTransportParameters(p transportParameters) []byte {
 		b = quicwire.AppendVarint(b, paramRetrySourceConnectionID)
 		b = quicwire.AppendVarintBytes(b, v)
 	}
+	if v := p.maxDatagramFrameSize; v != 0 {
+		b = quicwire.AppendVarint(b, paramMaxDatagramFrameSize)
+		b = quicwire.AppendVarint(b, uint64(quicwire.SizeVarint(uint64(v))))
+		b = quicwire.AppendVarint(b, uint64(v))
+	}
 	return b
 }
 
@@ -272,6 +278,8 @@ func unmarshalTransportParams(params []byte) (transportParameters, error) {
 		case paramRetrySourceConnectionID:
 			p.retrySrcConnID = val
 			n = len(val)
+		case paramMaxDatagramFrameSize:
+			p.maxDatagramFrameSize, n = quicwire.ConsumeVarintInt64(val)
 		default:
 			n = len(val)
 		}
@@ -0,0 +1,142 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package securedns
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const dnsMessageType = "application/dns-message"
+
+// HTTPSTransport sends DNS queries over HTTPS (DoH), as specified in
+// RFC 8484.
+//
+// Queries are sent with POST requests, over HTTP/2 if the server supports it,
+// and connections are kept open and reused for later queries. An
+// HTTPSTransport is safe for concurrent use by multiple goroutines, and should
+// be reused rather than created for each query.
+type HTTPSTransport struct {
+	// URL is the URL of the server, for example
+	// "https://dns.example/dns-query". It must use the https scheme.
+	URL string
+
+	// TLSConfig is the TLS configuration. If nil, the zero configuration is
+	// used.
+	TLSConfig *tls.Config
+
+	// Pins, if not empty, restricts the accepted server certificates to those
+	// matching one of the pins, as returned by [Pin]. The server certificate
+	// chain must also be valid, unless TLSConfig.InsecureSkipVerify is set, in
+	// which case the pins are the only authentication and the leaf
+	// certificate must match one.
+	Pins [][]byte
+
+	// Dial optionally specifies the function used to make TCP connections to
+	// the server. If nil, a zero [net.Dialer] is used.
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// IdleTimeout is how long an idle connection is kept for reuse. If zero,
+	// a default of 30 seconds is used.
+	IdleTimeout time.Duration
+
+	initOnce  sync.Once
+	initErr   error
+	transport *http.Transport
+	client    *http.Client
+}
+
+func (t *HTTPSTransport) init() error {
+	t.initOnce.Do(func() {
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			t.initErr = err
+			return
+		}
+		if u.Scheme != "https" {
+			t.initErr = errors.New("securedns: URL scheme must be https")
+			return
+		}
+		idleTimeout := t.IdleTimeout
+		if idleTimeout == 0 {
+			idleTimeout = defaultIdleTimeout
+		}
+		t.transport = &http.Transport{
+			DialContext:         dialContext(t.Dial),
+			TLSClientConfig:     tlsConfig(t.TLSConfig, t.Pins),
+			ForceAttemptHTTP2:   true,
+			MaxIdleConnsPerHost: maxIdleConnsPerServer,
+			IdleConnTimeout:     idleTimeout,
+		}
+		t.client = &http.Client{
+			Transport: t.transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return errors.New("securedns: unexpected redirect")
+			},
+		}
+	})
+	return t.initErr
+}
+
+// Exchange sends the DNS query and returns the response. Both are DNS
+// messages in wire format. It can be used as the Exchange field of a
+// [net.Resolver].
+func (t *HTTPSTransport) Exchange(ctx context.Context, query []byte) ([]byte, error) {
+	if len(query) < headerLen || len(query) > maxMessageLen {
+		return nil, errMalformedMessage
+	}
+	if err := t.init(); err != nil {
+		return nil, err
+	}
+
+	// The message ID should be zero, to make responses cacheable. See
+	// RFC 8484, Section 4.1.
+	msg := bytes.Clone(query)
+	msg[0], msg[1] = 0, 0
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dnsMessageType)
+	req.Header.Set("Accept", dnsMessageType)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("securedns: server returned HTTP status " + resp.Status)
+	}
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != dnsMessageType {
+		return nil, errors.New("securedns: unexpected response content type")
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageLen+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < headerLen || len(b) > maxMessageLen || b[0] != 0 || b[1] != 0 {
+		return nil, errors.New("securedns: unexpected response from server")
+	}
+	b[0], b[1] = query[0], query[1]
+	return b, nil
+}
+
+// CloseIdleConnections closes the connections kept for reuse. It does not
+// interrupt queries in progress.
+func (t *HTTPSTransport) CloseIdleConnections() {
+	if t.init() == nil {
+		t.transport.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package securedns implements encrypted transports for DNS queries: DNS over
+// TLS (DoT, RFC 7858) and DNS over HTTPS (DoH, RFC 8484).
+//
+// The transports are used by setting the Exchange field of a [net.Resolver]
+// to their Exchange method:
+//
+//	t := &securedns.HTTPSTransport{URL: "https://dns.example/dns-query"}
+//	r := &net.Resolver{Exchange: t.Exchange}
+//
+// The host name of the server is resolved with [net.DefaultResolver]. To
+// avoid sending that query in plaintext, use an IP address and set the
+// ServerName of the TLS configuration, or provide a Dial function.
+package securedns
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+)
+
+const (
+	// headerLen is the length of the DNS message header, which starts with
+	// the 16-bit message ID.
+	headerLen = 12
+
+	// maxMessageLen is the maximum length of a DNS message over TCP or TLS,
+	// which is prefixed by its 16-bit length.
+	maxMessageLen = 1<<16 - 1
+)
+
+var errMalformedMessage = errors.New("securedns: malformed DNS message")
+
+// Pin returns the pin of a certificate, for use in the Pins field of
+// [TLSTransport] and [HTTPSTransport]. The pin is the SHA-256 hash of the
+// DER-encoded SubjectPublicKeyInfo of the certificate, like the SPKI pins of
+// RFC 7858, Section 4.2.
+func Pin(cert *x509.Certificate) []byte {
+	h := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return h[:]
+}
+
+// tlsConfig returns a copy of config, or of the zero Config if config is nil,
+// which checks the server certificate against pins, if any.
+func tlsConfig(config *tls.Config, pins [][]byte) *tls.Config {
+	if config == nil {
+		config = &tls.Config{}
+	}
+	config = config.Clone()
+	if len(pins) == 0 {
+		return config
+	}
+	pins = append([][]byte(nil), pins...)
+	verifyConnection := config.VerifyConnection
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		if verifyConnection != nil {
+			if err := verifyConnection(cs); err != nil {
+				return err
+			}
+		}
+		return checkPins(cs, pins)
+	}
+	return config
+}
+
+// checkPins reports an error if no certificate in a verified chain matches one
+// of the pins. If the chain was not verified, because InsecureSkipVerify is
+// set, only the leaf certificate is considered, since the server didn't prove
+// possession of the other keys.
+func checkPins(cs tls.ConnectionState, pins [][]byte) error {
+	chains := cs.VerifiedChains
+	if len(chains) == 0 && len(cs.PeerCertificates) > 0 {
+		chains = [][]*x509.Certificate{cs.PeerCertificates[:1]}
+	}
+	for _, chain := range chains {
+		for _, cert := range chain {
+			pin := Pin(cert)
+			for _, p := range pins {
+				if bytes.Equal(pin, p) {
+					return nil
+				}
+			}
+		}
+	}
+	return errors.New("securedns: server certificate doesn't match any pin")
+}
+
+// dialFunc is the type of the Dial fields of the transports.
+type dialFunc = func(ctx context.Context, network, address string) (net.Conn, error)
+
+func dialContext(dial dialFunc) dialFunc {
+	if dial != nil {
+		return dial
+	}
+	var d net.Dialer
+	return d.DialContext
+}
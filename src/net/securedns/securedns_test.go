@@ -0,0 +1,354 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package securedns_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/securedns"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+var testAddr = netip.MustParseAddr("192.0.2.1")
+
+// testCertificate returns a self-signed certificate for 127.0.0.1 and
+// dns.test, and a pool containing it.
+func testCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"dns.test"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+// answer returns the response to a DNS query, with an A record for any name.
+func answer(query []byte) ([]byte, error) {
+	var q dnsmessage.Message
+	if err := q.Unpack(query); err != nil {
+		return nil, err
+	}
+	r := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 q.Header.ID,
+			Response:           true,
+			RecursionAvailable: true,
+		},
+		Questions: q.Questions,
+	}
+	if len(q.Questions) == 1 && q.Questions[0].Type == dnsmessage.TypeA {
+		r.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{
+				Name:  q.Questions[0].Name,
+				Type:  dnsmessage.TypeA,
+				Class: dnsmessage.ClassINET,
+			},
+			Body: &dnsmessage.AResource{A: testAddr.As4()},
+		}}
+	}
+	return r.Pack()
+}
+
+func lookup(t *testing.T, exchange func(context.Context, []byte) ([]byte, error)) error {
+	t.Helper()
+	r := &net.Resolver{Exchange: exchange}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	addrs, err := r.LookupNetIP(ctx, "ip4", "www.example.")
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(addrs, []netip.Addr{testAddr}) {
+		t.Errorf("LookupNetIP = %v, want [%v]", addrs, testAddr)
+	}
+	return nil
+}
+
+// dotServer is a DNS over TLS server which closes connections after
+// maxQueries queries, if not zero.
+type dotServer struct {
+	addr       string
+	conns      atomic.Int32
+	maxQueries atomic.Int32
+}
+
+func newDoTServer(t *testing.T, cert tls.Certificate) *dotServer {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := &dotServer{addr: l.Addr().String()}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.conns.Add(1)
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *dotServer) serve(c net.Conn) {
+	defer c.Close()
+	for i := int32(0); ; i++ {
+		if max := s.maxQueries.Load(); max != 0 && i >= max {
+			return
+		}
+		var l [2]byte
+		if _, err := io.ReadFull(c, l[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(l[:]))
+		if _, err := io.ReadFull(c, query); err != nil {
+			return
+		}
+		resp, err := answer(query)
+		if err != nil {
+			return
+		}
+		c.Write(binary.BigEndian.AppendUint16(nil, uint16(len(resp))))
+		c.Write(resp)
+	}
+}
+
+func TestTLSTransport(t *testing.T) {
+	cert, pool := testCertificate(t)
+	s := newDoTServer(t, cert)
+
+	tr := &securedns.TLSTransport{Addr: s.addr, TLSConfig: &tls.Config{RootCAs: pool}}
+	defer tr.CloseIdleConnections()
+	for range 3 {
+		if err := lookup(t, tr.Exchange); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := s.conns.Load(); n != 1 {
+		t.Errorf("server accepted %d connections, want 1", n)
+	}
+
+	// Connections closed by the server are replaced.
+	s.maxQueries.Store(1)
+	tr.CloseIdleConnections()
+	for range 3 {
+		if err := lookup(t, tr.Exchange); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The server certificate is verified.
+	tr = &securedns.TLSTransport{Addr: s.addr}
+	if err := lookup(t, tr.Exchange); err == nil {
+		t.Errorf("lookup succeeded with an untrusted certificate")
+	}
+}
+
+func TestTLSTransportPins(t *testing.T) {
+	cert, pool := testCertificate(t)
+	other, _ := testCertificate(t)
+	s := newDoTServer(t, cert)
+	for _, tt := range []struct {
+		name   string
+		config *tls.Config
+		pin    []byte
+		ok     bool
+	}{
+		{"verified", &tls.Config{RootCAs: pool}, securedns.Pin(cert.Leaf), true},
+		{"verified-mismatch", &tls.Config{RootCAs: pool}, securedns.Pin(other.Leaf), false},
+		{"pinned-only", &tls.Config{InsecureSkipVerify: true}, securedns.Pin(cert.Leaf), true},
+		{"pinned-only-mismatch", &tls.Config{InsecureSkipVerify: true}, securedns.Pin(other.Leaf), false},
+		{"untrusted", nil, securedns.Pin(cert.Leaf), false},
+	} {
+		tr := &securedns.TLSTransport{Addr: s.addr, TLSConfig: tt.config, Pins: [][]byte{tt.pin}}
+		if err := lookup(t, tr.Exchange); (err == nil) != tt.ok {
+			t.Errorf("%s: lookup error = %v, want success %v", tt.name, err, tt.ok)
+		}
+		tr.CloseIdleConnections()
+	}
+}
+
+func TestTLSTransportServerName(t *testing.T) {
+	cert, pool := testCertificate(t)
+	s := newDoTServer(t, cert)
+	_, port, _ := net.SplitHostPort(s.addr)
+
+	// Dial is used to reach the server, and the host in Addr is verified.
+	tr := &securedns.TLSTransport{
+		Addr:      net.JoinHostPort("dns.test", port),
+		TLSConfig: &tls.Config{RootCAs: pool},
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if address != net.JoinHostPort("dns.test", port) {
+				t.Errorf("unexpected Dial address %q", address)
+			}
+			var d net.Dialer
+			return d.DialContext(ctx, network, s.addr)
+		},
+	}
+	defer tr.CloseIdleConnections()
+	if err := lookup(t, tr.Exchange); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newDoHServer(t *testing.T, cert tls.Certificate, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	ts := httptest.NewUnstartedServer(handler)
+	ts.EnableHTTP2 = true
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	// Failed handshakes are expected in tests with mismatched pins.
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	conns := new(atomic.Int32)
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts, conns
+}
+
+func dohHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		query, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		if len(query) < 2 || query[0] != 0 || query[1] != 0 {
+			t.Errorf("query ID is not zero")
+		}
+		resp, err := answer(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(resp)
+	}
+}
+
+func TestHTTPSTransport(t *testing.T) {
+	cert, pool := testCertificate(t)
+	ts, conns := newDoHServer(t, cert, dohHandler(t))
+
+	tr := &securedns.HTTPSTransport{
+		URL:       ts.URL + "/dns-query",
+		TLSConfig: &tls.Config{RootCAs: pool},
+		Pins:      [][]byte{securedns.Pin(cert.Leaf)},
+	}
+	defer tr.CloseIdleConnections()
+	for range 3 {
+		if err := lookup(t, tr.Exchange); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("server accepted %d connections, want 1", n)
+	}
+
+	other, _ := testCertificate(t)
+	tr = &securedns.HTTPSTransport{
+		URL:       ts.URL + "/dns-query",
+		TLSConfig: &tls.Config{RootCAs: pool},
+		Pins:      [][]byte{securedns.Pin(other.Leaf)},
+	}
+	if err := lookup(t, tr.Exchange); err == nil {
+		t.Errorf("lookup succeeded with a mismatched pin")
+	}
+}
+
+func TestHTTPSTransportErrors(t *testing.T) {
+	cert, pool := testCertificate(t)
+	ts, _ := newDoHServer(t, cert, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status":
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case "/type":
+			w.Header().Set("Content-Type", "text/plain")
+			dohHandler(t)(discardHeader{w}, r)
+		case "/id":
+			query, _ := io.ReadAll(r.Body)
+			resp, _ := answer(query)
+			resp[1] = 1
+			w.Header().Set("Content-Type", "application/dns-message")
+			w.Write(resp)
+		case "/redirect":
+			http.Redirect(w, r, "/dns-query", http.StatusFound)
+		}
+	})
+	for _, path := range []string{"/status", "/type", "/id", "/redirect"} {
+		tr := &securedns.HTTPSTransport{URL: ts.URL + path, TLSConfig: &tls.Config{RootCAs: pool}}
+		if err := lookup(t, tr.Exchange); err == nil {
+			t.Errorf("%s: lookup succeeded", path)
+		}
+		tr.CloseIdleConnections()
+	}
+
+	tr := &securedns.HTTPSTransport{URL: "http://127.0.0.1/dns-query"}
+	if _, err := tr.Exchange(context.Background(), make([]byte, 12)); err == nil {
+		t.Errorf("Exchange succeeded with an http URL")
+	}
+	if _, err := tr.Exchange(context.Background(), []byte{1, 2}); err == nil {
+		t.Errorf("Exchange succeeded with a short query")
+	}
+}
+
+// discardHeader is a ResponseWriter that ignores changes to the header, to
+// let tests override the Content-Type set by a handler.
+type discardHeader struct {
+	http.ResponseWriter
+}
+
+func (w discardHeader) Header() http.Header { return http.Header{} }
+
+func TestPin(t *testing.T) {
+	cert, _ := testCertificate(t)
+	pin := securedns.Pin(cert.Leaf)
+	if len(pin) != 32 || bytes.Equal(pin, make([]byte, 32)) {
+		t.Errorf("unexpected pin %x", pin)
+	}
+}
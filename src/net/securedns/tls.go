@@ -0,0 +1,198 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package securedns
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTLSPort        = "853"
+	defaultIdleTimeout    = 30 * time.Second
+	maxIdleConnsPerServer = 4
+)
+
+// TLSTransport sends DNS queries over TLS (DoT), as specified in RFC 7858.
+//
+// Connections are kept open and reused for later queries. A TLSTransport is
+// safe for concurrent use by multiple goroutines, and should be reused rather
+// than created for each query.
+type TLSTransport struct {
+	// Addr is the address of the server, in host:port form. If the port is
+	// omitted, the default port 853 is used.
+	Addr string
+
+	// TLSConfig is the TLS configuration. If nil, the zero configuration is
+	// used. If its ServerName is empty, the host in Addr is used.
+	TLSConfig *tls.Config
+
+	// Pins, if not empty, restricts the accepted server certificates to those
+	// matching one of the pins, as returned by [Pin]. The server certificate
+	// chain must also be valid, unless TLSConfig.InsecureSkipVerify is set, in
+	// which case the pins are the only authentication and the leaf
+	// certificate must match one.
+	Pins [][]byte
+
+	// Dial optionally specifies the function used to make TCP connections to
+	// the server. If nil, a zero [net.Dialer] is used.
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// IdleTimeout is how long an idle connection is kept for reuse. If zero,
+	// a default of 30 seconds is used.
+	IdleTimeout time.Duration
+
+	initOnce sync.Once
+	addr     string
+	config   *tls.Config
+
+	mu   sync.Mutex
+	idle []*idleConn
+}
+
+type idleConn struct {
+	c     *tls.Conn
+	since time.Time
+}
+
+func (t *TLSTransport) init() {
+	t.initOnce.Do(func() {
+		t.addr = t.Addr
+		if _, _, err := net.SplitHostPort(t.addr); err != nil {
+			t.addr = net.JoinHostPort(t.addr, defaultTLSPort)
+		}
+		t.config = tlsConfig(t.TLSConfig, t.Pins)
+		if t.config.ServerName == "" {
+			host, _, _ := net.SplitHostPort(t.addr)
+			t.config.ServerName = host
+		}
+	})
+}
+
+// Exchange sends the DNS query and returns the response. Both are DNS
+// messages in wire format. It can be used as the Exchange field of a
+// [net.Resolver].
+func (t *TLSTransport) Exchange(ctx context.Context, query []byte) ([]byte, error) {
+	if len(query) < headerLen || len(query) > maxMessageLen {
+		return nil, errMalformedMessage
+	}
+	t.init()
+	for {
+		c, reused, err := t.getConn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := roundTrip(ctx, c, query)
+		if err == nil {
+			t.putConn(c)
+			return resp, nil
+		}
+		c.Close()
+		// The server might have closed an idle connection, in which case
+		// the query is retried on another one.
+		if !reused || ctx.Err() != nil {
+			return nil, err
+		}
+	}
+}
+
+// getConn returns an idle connection, or dials a new one.
+func (t *TLSTransport) getConn(ctx context.Context) (c *tls.Conn, reused bool, err error) {
+	timeout := t.IdleTimeout
+	if timeout == 0 {
+		timeout = defaultIdleTimeout
+	}
+	t.mu.Lock()
+	for len(t.idle) > 0 {
+		ic := t.idle[len(t.idle)-1]
+		t.idle = t.idle[:len(t.idle)-1]
+		if time.Since(ic.since) < timeout {
+			t.mu.Unlock()
+			return ic.c, true, nil
+		}
+		ic.c.Close()
+	}
+	t.mu.Unlock()
+
+	raw, err := dialContext(t.Dial)(ctx, "tcp", t.addr)
+	if err != nil {
+		return nil, false, err
+	}
+	c = tls.Client(raw, t.config)
+	if err := c.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		return nil, false, err
+	}
+	return c, false, nil
+}
+
+// putConn returns a connection to the idle pool.
+func (t *TLSTransport) putConn(c *tls.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.idle) >= maxIdleConnsPerServer {
+		c.Close()
+		return
+	}
+	t.idle = append(t.idle, &idleConn{c: c, since: time.Now()})
+}
+
+// CloseIdleConnections closes the connections kept for reuse. It does not
+// interrupt queries in progress.
+func (t *TLSTransport) CloseIdleConnections() {
+	t.mu.Lock()
+	idle := t.idle
+	t.idle = nil
+	t.mu.Unlock()
+	for _, ic := range idle {
+		ic.c.Close()
+	}
+}
+
+// roundTrip sends query on c, with the two-byte length prefix of RFC 1035,
+// Section 4.2.2, and reads the response.
+func roundTrip(ctx context.Context, c net.Conn, query []byte) ([]byte, error) {
+	deadline, _ := ctx.Deadline()
+	c.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		c.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	msg := make([]byte, 2+len(query))
+	msg[0], msg[1] = byte(len(query)>>8), byte(len(query))
+	copy(msg[2:], query)
+	if _, err := c.Write(msg); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(c, l[:]); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	resp := make([]byte, int(l[0])<<8|int(l[1]))
+	if _, err := io.ReadFull(c, resp); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	// Queries are not pipelined, so the response must match the query.
+	if len(resp) < headerLen || resp[0] != query[0] || resp[1] != query[1] {
+		return nil, errors.New("securedns: unexpected response from server")
+	}
+	return resp, nil
+}
+
+// ctxErr returns the context error if ctx is done, since in that case err is
+// likely caused by the deadline set by roundTrip.
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}